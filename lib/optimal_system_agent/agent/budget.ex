@@ -10,10 +10,18 @@ defmodule OptimalSystemAgent.Agent.Budget do
   - OSA_DAILY_BUDGET_USD (default 50.0)
   - OSA_MONTHLY_BUDGET_USD (default 500.0)
   - OSA_PER_CALL_LIMIT_USD (default 5.0)
+  - OSA_SESSION_BUDGET_USD (default none) — spend allowed per session
+
+  `set_limits/1` changes them at runtime; the new limits last until the
+  process restarts.
 
   Events emitted on :system_event:
-  - :budget_warning — when spend exceeds 80% of daily or monthly limit
+  - :budget_warning — when spend exceeds 80% of the session, daily or monthly limit
   - :budget_exceeded — when a limit is hit
+
+  Both carry `period` (:session, :daily or :monthly), `spent` and `limit`.
+  `type` holds the same value as `period` but collides with the bus event
+  type on the way to SSE clients.
   """
   use GenServer
   require Logger
@@ -36,15 +44,19 @@ defmodule OptimalSystemAgent.Agent.Budget do
   defstruct daily_limit: 50.0,
             monthly_limit: 500.0,
             per_call_limit: 5.0,
+            session_limit: nil,
             daily_spent: 0.0,
             monthly_spent: 0.0,
             daily_reset_at: nil,
             monthly_reset_at: nil,
+            session_spent: %{},
             ledger: []
 
   @daily_reset_ms 24 * 60 * 60 * 1000
   @monthly_reset_ms 30 * 24 * 60 * 60 * 1000
 
+  @limit_keys [:daily_limit, :monthly_limit, :per_call_limit, :session_limit]
+
   # ── Public API ──────────────────────────────────────────────────────
 
   def start_link(opts \\ []) do
@@ -62,15 +74,29 @@ defmodule OptimalSystemAgent.Agent.Budget do
   @doc """
   Check current budget status.
   Returns `{:ok, %{daily_remaining: x, monthly_remaining: y}}` or
-  `{:over_limit, :daily | :monthly}`.
+  `{:over_limit, :daily | :monthly}`. Given a session, its spend is checked
+  against the session limit too, returning `{:over_limit, :session}`.
+  """
+  def check_budget(session_id \\ nil) do
+    GenServer.call(__MODULE__, {:check_budget, session_id})
+  end
+
+  @doc """
+  Get full spend summary including limits, spent, and ledger size. Given a
+  session, it also has that session's `session_spent` and `session_remaining`.
   """
-  def check_budget do
-    GenServer.call(__MODULE__, :check_budget)
+  def get_status(session_id \\ nil) do
+    GenServer.call(__MODULE__, {:get_status, session_id})
   end
 
-  @doc "Get full spend summary including limits, spent, and ledger size."
-  def get_status do
-    GenServer.call(__MODULE__, :get_status)
+  @doc """
+  Change limits at runtime. Takes a map of `:daily_limit`, `:monthly_limit`,
+  `:per_call_limit` and `:session_limit` to positive USD amounts; a nil
+  `:session_limit` removes it. Returns `{:ok, status}` or
+  `{:error, {:invalid_limit, key}}` without changing any limit.
+  """
+  def set_limits(limits) when is_map(limits) do
+    GenServer.call(__MODULE__, {:set_limits, limits})
   end
 
   @doc "Manually reset daily spend counter."
@@ -125,12 +151,23 @@ defmodule OptimalSystemAgent.Agent.Budget do
         )
       )
 
+    session_limit =
+      parse_float_env(
+        "OSA_SESSION_BUDGET_USD",
+        Keyword.get(
+          opts,
+          :session_limit,
+          Application.get_env(:optimal_system_agent, :session_budget_usd)
+        )
+      )
+
     now = DateTime.utc_now()
 
     state = %__MODULE__{
       daily_limit: daily_limit,
       monthly_limit: monthly_limit,
       per_call_limit: per_call_limit,
+      session_limit: session_limit,
       daily_reset_at: DateTime.add(now, @daily_reset_ms, :millisecond),
       monthly_reset_at: DateTime.add(now, @monthly_reset_ms, :millisecond)
     }
@@ -163,18 +200,29 @@ defmodule OptimalSystemAgent.Agent.Budget do
     new_daily = state.daily_spent + cost
     new_monthly = state.monthly_spent + cost
 
+    session_spent =
+      if is_binary(session_id),
+        do: Map.update(state.session_spent, session_id, cost, &(&1 + cost)),
+        else: state.session_spent
+
     state = %{
       state
       | daily_spent: new_daily,
         monthly_spent: new_monthly,
+        session_spent: session_spent,
         ledger: Enum.take([entry | state.ledger], 10_000)
     }
 
+    if is_binary(session_id) and state.session_limit do
+      emit_session_events(Map.fetch!(session_spent, session_id), cost, state.session_limit, session_id)
+    end
+
     # Emit warnings at 80% thresholds
     if new_daily > state.daily_limit * 0.8 and new_daily - cost <= state.daily_limit * 0.8 do
       Bus.emit(:system_event, %{
         event: :budget_warning,
         type: :daily,
+        period: :daily,
         spent: new_daily,
         limit: state.daily_limit,
         utilization: new_daily / state.daily_limit,
@@ -187,6 +235,7 @@ defmodule OptimalSystemAgent.Agent.Budget do
       Bus.emit(:system_event, %{
         event: :budget_warning,
         type: :monthly,
+        period: :monthly,
         spent: new_monthly,
         limit: state.monthly_limit,
         utilization: new_monthly / state.monthly_limit,
@@ -200,6 +249,7 @@ defmodule OptimalSystemAgent.Agent.Budget do
       Bus.emit(:system_event, %{
         event: :budget_exceeded,
         type: :daily,
+        period: :daily,
         spent: new_daily,
         limit: state.daily_limit,
         message: "Daily budget exceeded: $#{Float.round(new_daily, 2)} / $#{state.daily_limit}",
@@ -215,6 +265,7 @@ defmodule OptimalSystemAgent.Agent.Budget do
       Bus.emit(:system_event, %{
         event: :budget_exceeded,
         type: :monthly,
+        period: :monthly,
         spent: new_monthly,
         limit: state.monthly_limit,
         message: "Monthly budget exceeded: $#{Float.round(new_monthly, 2)} / $#{state.monthly_limit}",
@@ -273,9 +324,18 @@ defmodule OptimalSystemAgent.Agent.Budget do
   end
 
   @impl true
-  def handle_call(:check_budget, _from, state) do
+  def handle_call(:check_budget, from, state), do: handle_call({:check_budget, nil}, from, state)
+
+  @impl true
+  def handle_call(:get_status, from, state), do: handle_call({:get_status, nil}, from, state)
+
+  @impl true
+  def handle_call({:check_budget, session_id}, _from, state) do
     result =
       cond do
+        state.session_limit && Map.get(state.session_spent, session_id, 0.0) >= state.session_limit ->
+          {:over_limit, :session}
+
         state.daily_spent >= state.daily_limit ->
           {:over_limit, :daily}
 
@@ -294,21 +354,29 @@ defmodule OptimalSystemAgent.Agent.Budget do
   end
 
   @impl true
-  def handle_call(:get_status, _from, state) do
-    status = %{
-      daily_limit: state.daily_limit,
-      monthly_limit: state.monthly_limit,
-      per_call_limit: state.per_call_limit,
-      daily_spent: Float.round(state.daily_spent, 4),
-      monthly_spent: Float.round(state.monthly_spent, 4),
-      daily_remaining: Float.round(state.daily_limit - state.daily_spent, 2),
-      monthly_remaining: Float.round(state.monthly_limit - state.monthly_spent, 2),
-      daily_reset_at: state.daily_reset_at,
-      monthly_reset_at: state.monthly_reset_at,
-      ledger_entries: length(state.ledger)
-    }
+  def handle_call({:get_status, session_id}, _from, state) do
+    {:reply, {:ok, status(state, session_id)}, state}
+  end
+
+  @impl true
+  def handle_call({:set_limits, limits}, _from, state) do
+    case Enum.find(limits, fn {key, value} -> not valid_limit?(key, value) end) do
+      nil ->
+        state =
+          Enum.reduce(limits, state, fn {key, value}, acc ->
+            Map.put(acc, key, value && value / 1)
+          end)
+
+        Logger.info(
+          "[Agent.Budget] Limits set — daily: $#{state.daily_limit}, monthly: $#{state.monthly_limit}, " <>
+            "per-call: $#{state.per_call_limit}, session: #{if state.session_limit, do: "$#{state.session_limit}", else: "none"}"
+        )
 
-    {:reply, {:ok, status}, state}
+        {:reply, {:ok, status(state, nil)}, state}
+
+      {key, _value} ->
+        {:reply, {:error, {:invalid_limit, key}}, state}
+    end
   end
 
   @impl true
@@ -345,6 +413,66 @@ defmodule OptimalSystemAgent.Agent.Budget do
 
   # ── Private ─────────────────────────────────────────────────────────
 
+  defp status(state, session_id) do
+    status = %{
+      daily_limit: state.daily_limit,
+      monthly_limit: state.monthly_limit,
+      per_call_limit: state.per_call_limit,
+      session_limit: state.session_limit,
+      daily_spent: Float.round(state.daily_spent, 4),
+      monthly_spent: Float.round(state.monthly_spent, 4),
+      daily_remaining: Float.round(state.daily_limit - state.daily_spent, 2),
+      monthly_remaining: Float.round(state.monthly_limit - state.monthly_spent, 2),
+      daily_reset_at: state.daily_reset_at,
+      monthly_reset_at: state.monthly_reset_at,
+      ledger_entries: length(state.ledger)
+    }
+
+    if is_binary(session_id) do
+      spent = Map.get(state.session_spent, session_id, 0.0)
+
+      Map.merge(status, %{
+        session_spent: Float.round(spent, 4),
+        session_remaining: state.session_limit && Float.round(state.session_limit - spent, 2)
+      })
+    else
+      status
+    end
+  end
+
+  defp valid_limit?(:session_limit, nil), do: true
+  defp valid_limit?(key, value) when key in @limit_keys, do: is_number(value) and value > 0
+  defp valid_limit?(_key, _value), do: false
+
+  # Same 80% warning and exceeded events as the daily and monthly limits,
+  # for the spend of one session.
+  defp emit_session_events(spent, cost, limit, session_id) do
+    if spent > limit * 0.8 and spent - cost <= limit * 0.8 do
+      Bus.emit(:system_event, %{
+        event: :budget_warning,
+        type: :session,
+        period: :session,
+        spent: spent,
+        limit: limit,
+        utilization: spent / limit,
+        message: "Session spend at #{round(spent / limit * 100)}% ($#{Float.round(spent, 2)} / $#{limit})",
+        session_id: session_id
+      })
+    end
+
+    if spent > limit do
+      Bus.emit(:system_event, %{
+        event: :budget_exceeded,
+        type: :session,
+        period: :session,
+        spent: spent,
+        limit: limit,
+        message: "Session budget exceeded: $#{Float.round(spent, 2)} / $#{limit}",
+        session_id: session_id
+      })
+    end
+  end
+
   defp schedule_daily_reset do
     Process.send_after(self(), :reset_daily, @daily_reset_ms)
   end
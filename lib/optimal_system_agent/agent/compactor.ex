@@ -46,6 +46,7 @@ defmodule OptimalSystemAgent.Agent.Compactor do
   ## Public API
 
       maybe_compact/1       — inspect and possibly compact a message list
+      compact/2             — compact a message list to a token target
       stats/0               — compaction metrics from the GenServer
       start_link/1          — GenServer lifecycle
       utilization/1         — context-window utilization percentage
@@ -118,6 +119,28 @@ defmodule OptimalSystemAgent.Agent.Compactor do
     end
   end
 
+  @doc """
+  Compacts the message list until it fits in `target_tokens`, whatever the
+  usage, e.g. to make room for a large prompt. Returns the messages as they
+  are when they already fit; like `maybe_compact/1` it never raises.
+  """
+  @spec compact([map()], non_neg_integer()) :: [map()]
+  def compact(messages, target_tokens) do
+    try do
+      tokens_before = estimate_tokens(messages)
+
+      if tokens_before <= target_tokens do
+        messages
+      else
+        run_pipeline(messages, tokens_before, :requested, max_tokens(), target_tokens)
+      end
+    rescue
+      e ->
+        Logger.error("Compactor.compact/2 crashed: #{Exception.message(e)}")
+        messages
+    end
+  end
+
   @doc """
   Returns context window utilization as a percentage (0.0 — 100.0).
   """
@@ -257,13 +280,15 @@ defmodule OptimalSystemAgent.Agent.Compactor do
   # ---------------------------------------------------------------------------
 
   @doc false
-  defp run_pipeline(messages, tokens_before, severity, max_tok) do
-    # Determine target: bring usage to 70% for background, 60% for aggressive/emergency
+  defp run_pipeline(messages, tokens_before, severity, max_tok, target \\ nil) do
+    # Determine target: bring usage to 70% for background, 60% for aggressive,
+    # 50% for emergency, or to the target of a requested compaction
     target_tokens =
       case severity do
         :background -> round(max_tok * 0.70)
         :aggressive -> round(max_tok * 0.60)
         :emergency -> round(max_tok * 0.50)
+        :requested -> target
       end
 
     {system_msgs, non_system} = split_system(messages)
@@ -212,6 +212,9 @@ defmodule OptimalSystemAgent.Agent.Context do
       {runtime_block(state), 1, "runtime"},
       {environment_block(state), 1, "environment"},
       {plan_mode_block(state), 1, "plan_mode"},
+      {session_instructions_block(state), 1, "session_instructions"},
+      {directed_agent_block(state), 1, "directed_agent"},
+      {reply_preferences_block(state), 1, "reply_preferences"},
 
       # Priority 2 — budget-fitted
       {memory_block_relevant(state), 2, "memory"},
@@ -688,6 +691,63 @@ defmodule OptimalSystemAgent.Agent.Context do
 
   defp plan_mode_block(_), do: nil
 
+  # How the user wants replies written, from the client's reply preferences.
+  defp reply_preferences_block(state) do
+    case Map.get(state, :preferences) do
+      prefs when is_map(prefs) and map_size(prefs) > 0 ->
+        lines =
+          [
+            {"reply_language", "Write replies in"},
+            {"verbosity", "Reply length"},
+            {"comment_style", "Comments in code you write"}
+          ]
+          |> Enum.filter(fn {key, _} -> Map.has_key?(prefs, key) end)
+          |> Enum.map_join("\n", fn {key, label} -> "- #{label}: #{prefs[key]}" end)
+
+        """
+        ## Reply Preferences
+        #{lines}
+        """
+
+      _ ->
+        nil
+    end
+  end
+
+  # The roster agent a message was addressed to with `@name`.
+  defp directed_agent_block(state) do
+    case Map.get(state, :agent) do
+      %{name: name, prompt: prompt} ->
+        """
+        ## Directed Agent: #{name}
+        The user addressed this message to the #{name} agent. Answer as that agent:
+
+        #{prompt}
+        """
+
+      _ ->
+        nil
+    end
+  end
+
+  # Per-session instructions the client sends with each message (e.g. the
+  # TUI's /override system prompt).
+  defp session_instructions_block(state) do
+    case Map.get(state, :system_prompt) do
+      prompt when is_binary(prompt) and prompt != "" ->
+        """
+        ## Session Instructions
+        The user set these instructions for this session. Follow them unless they
+        conflict with your safety rules.
+
+        #{prompt}
+        """
+
+      _ ->
+        nil
+    end
+  end
+
   defp environment_block(_state) do
     cwd = File.cwd!()
     git_info = cached_git_info()
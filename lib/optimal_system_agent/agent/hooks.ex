@@ -18,6 +18,11 @@ defmodule OptimalSystemAgent.Agent.Hooks do
 
   Hooks run in order. If any hook blocks, the chain stops.
 
+  A hook can be disabled at runtime with `set_enabled/2` and is then skipped
+  until enabled again. The guardrails (security_check, spend_guard) are
+  protected and always run. The last 20 blocks are kept for
+  `recent_blocks/0`.
+
   Based on: OSA Agent v3.3 hook system (13 events, 17 scripts)
   """
 
@@ -42,7 +47,11 @@ defmodule OptimalSystemAgent.Agent.Hooks do
           priority: integer()
         }
 
-  defstruct hooks: %{}, metrics: %{}
+  defstruct hooks: %{}, metrics: %{}, disabled: MapSet.new(), recent_blocks: []
+
+  # Guardrails that stay on whatever the runtime toggles say.
+  @protected_hooks ~w(security_check spend_guard)
+  @max_recent_blocks 20
 
   # ── Client API ────────────────────────────────────────────────────
 
@@ -77,12 +86,34 @@ defmodule OptimalSystemAgent.Agent.Hooks do
     GenServer.cast(__MODULE__, {:run_async, event, payload})
   end
 
-  @doc "List registered hooks."
-  @spec list_hooks() :: %{hook_event() => [%{name: String.t(), priority: integer()}]}
+  @doc """
+  List registered hooks. Each entry also says whether the hook is enabled and
+  whether it is a protected guardrail that cannot be disabled.
+  """
+  @spec list_hooks() :: %{
+          hook_event() => [
+            %{name: String.t(), priority: integer(), enabled: boolean(), protected: boolean()}
+          ]
+        }
   def list_hooks do
     GenServer.call(__MODULE__, :list_hooks)
   end
 
+  @doc """
+  Enable or disable a hook by name. A disabled hook is skipped for every
+  event it is registered for.
+  """
+  @spec set_enabled(String.t(), boolean()) :: :ok | {:error, :not_found | :protected}
+  def set_enabled(name, enabled) when is_boolean(enabled) do
+    GenServer.call(__MODULE__, {:set_enabled, name, enabled})
+  end
+
+  @doc "Recent blocks, newest first: hook_name, hook_event, reason, session_id, at."
+  @spec recent_blocks() :: [map()]
+  def recent_blocks do
+    GenServer.call(__MODULE__, :recent_blocks)
+  end
+
   @doc "Get hook execution metrics."
   @spec metrics() :: map()
   def metrics do
@@ -119,7 +150,7 @@ defmodule OptimalSystemAgent.Agent.Hooks do
 
   @impl true
   def handle_cast({:run_async, event, payload}, state) do
-    hooks = Map.get(state.hooks, event, [])
+    hooks = active_hooks(state, event)
     started_at = System.monotonic_time(:microsecond)
     {result, state} = run_chain(hooks, payload, event, state)
     elapsed_us = System.monotonic_time(:microsecond) - started_at
@@ -129,7 +160,7 @@ defmodule OptimalSystemAgent.Agent.Hooks do
 
   @impl true
   def handle_call({:run, event, payload}, _from, state) do
-    hooks = Map.get(state.hooks, event, [])
+    hooks = active_hooks(state, event)
     started_at = System.monotonic_time(:microsecond)
 
     {result, state} = run_chain(hooks, payload, event, state)
@@ -145,7 +176,15 @@ defmodule OptimalSystemAgent.Agent.Hooks do
     listing =
       state.hooks
       |> Enum.map(fn {event, hooks} ->
-        {event, Enum.map(hooks, fn h -> %{name: h.name, priority: h.priority} end)}
+        {event,
+         Enum.map(hooks, fn h ->
+           %{
+             name: h.name,
+             priority: h.priority,
+             enabled: not MapSet.member?(state.disabled, h.name),
+             protected: h.name in @protected_hooks
+           }
+         end)}
       end)
       |> Map.new()
 
@@ -157,6 +196,39 @@ defmodule OptimalSystemAgent.Agent.Hooks do
     {:reply, state.metrics, state}
   end
 
+  @impl true
+  def handle_call({:set_enabled, name, enabled}, _from, state) do
+    registered? =
+      Enum.any?(state.hooks, fn {_event, hooks} -> Enum.any?(hooks, &(&1.name == name)) end)
+
+    cond do
+      not registered? ->
+        {:reply, {:error, :not_found}, state}
+
+      name in @protected_hooks ->
+        {:reply, {:error, :protected}, state}
+
+      enabled ->
+        {:reply, :ok, %{state | disabled: MapSet.delete(state.disabled, name)}}
+
+      true ->
+        Logger.info("[Hooks] #{name} disabled")
+        {:reply, :ok, %{state | disabled: MapSet.put(state.disabled, name)}}
+    end
+  end
+
+  @impl true
+  def handle_call(:recent_blocks, _from, state) do
+    {:reply, state.recent_blocks, state}
+  end
+
+  # Hooks for event that are not disabled, in priority order.
+  defp active_hooks(state, event) do
+    state.hooks
+    |> Map.get(event, [])
+    |> Enum.reject(&MapSet.member?(state.disabled, &1.name))
+  end
+
   # ── Hook Chain Execution ──────────────────────────────────────────
 
   defp run_chain([], payload, _event, state), do: {{:ok, payload}, state}
@@ -170,15 +242,19 @@ defmodule OptimalSystemAgent.Agent.Hooks do
         {:block, reason} ->
           Logger.warning("[Hooks] #{hook.name} blocked #{event}: #{reason}")
 
-          Bus.emit(:system_event, %{
-            event: :hook_blocked,
+          block = %{
             hook_name: hook.name,
             hook_event: event,
             reason: reason,
             session_id: Map.get(payload, :session_id, "unknown")
-          })
+          }
+
+          Bus.emit(:system_event, Map.put(block, :event, :hook_blocked))
+
+          block = Map.put(block, :at, DateTime.utc_now() |> DateTime.to_iso8601())
+          recent = Enum.take([block | state.recent_blocks], @max_recent_blocks)
 
-          {{:blocked, reason}, state}
+          {{:blocked, reason}, %{state | recent_blocks: recent}}
 
         :skip ->
           run_chain(rest, payload, event, state)
@@ -366,7 +442,7 @@ defmodule OptimalSystemAgent.Agent.Hooks do
   # Spend guard — check budget limits before tool execution
   defp spend_guard(payload) do
     try do
-      case OptimalSystemAgent.Agent.Budget.check_budget() do
+      case OptimalSystemAgent.Agent.Budget.check_budget(Map.get(payload, :session_id)) do
         {:ok, _remaining} ->
           {:ok, payload}
 
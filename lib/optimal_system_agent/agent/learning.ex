@@ -3,7 +3,8 @@ defmodule OptimalSystemAgent.Agent.Learning do
   Self-learning engine based on SICA, VIGIL, and Mem0 patterns.
 
   Continuous improvement loop:
-    OBSERVE  → Capture every tool interaction, error, user correction
+    OBSERVE  → Capture every tool interaction, error, user correction and
+               response rating
     REFLECT  → Identify patterns across recent interactions
     PROPOSE  → Generate new patterns/skills when repetition detected
     TEST     → Validate proposed patterns against past data
@@ -52,7 +53,9 @@ defmodule OptimalSystemAgent.Agent.Learning do
               patterns_captured: 0,
               skills_generated: 0,
               errors_recovered: 0,
-              consolidations: 0
+              consolidations: 0,
+              feedback_positive: 0,
+              feedback_negative: 0
             }
 
   # ── Client API ────────────────────────────────────────────────────
@@ -73,6 +76,16 @@ defmodule OptimalSystemAgent.Agent.Learning do
     GenServer.cast(__MODULE__, {:correction, what_was_wrong, what_is_right})
   end
 
+  @doc """
+  Record a user's rating of an agent response: a map with `:rating`
+  (`:up` or `:down`), `:session_id`, `:message_id`, and optionally
+  `:comment` and `:excerpt` of the rated response.
+  """
+  @spec feedback(map()) :: :ok
+  def feedback(rating) do
+    GenServer.cast(__MODULE__, {:feedback, rating})
+  end
+
   @doc "Record an error for VIGIL recovery."
   @spec error(String.t(), String.t(), String.t()) :: :ok
   def error(tool_name, error_message, context) do
@@ -208,6 +221,42 @@ defmodule OptimalSystemAgent.Agent.Learning do
     {:noreply, state}
   end
 
+  # ── OBSERVE: Response Ratings ─────────────────────────────────────
+
+  @impl true
+  def handle_cast({:feedback, feedback}, state) do
+    episode = %{
+      timestamp: DateTime.utc_now(),
+      type: :feedback,
+      rating: feedback[:rating],
+      session_id: feedback[:session_id],
+      message_id: feedback[:message_id],
+      comment: feedback[:comment],
+      excerpt: feedback[:excerpt]
+    }
+
+    episodes = [episode | state.episodes] |> Enum.take(@max_episodes)
+
+    metrics =
+      case feedback[:rating] do
+        :up -> %{state.metrics | feedback_positive: state.metrics.feedback_positive + 1}
+        _ -> %{state.metrics | feedback_negative: state.metrics.feedback_negative + 1}
+      end
+
+    # Ratings are rare and worth keeping past the episode cap
+    persist_feedback(episode)
+
+    Bus.emit(:system_event, %{
+      event: :response_feedback,
+      session_id: feedback[:session_id],
+      message_id: feedback[:message_id],
+      rating: feedback[:rating],
+      comment: feedback[:comment]
+    })
+
+    {:noreply, %{state | episodes: episodes, metrics: metrics}}
+  end
+
   # ── OBSERVE: Errors (VIGIL Recovery) ──────────────────────────────
 
   @impl true
@@ -529,6 +578,14 @@ defmodule OptimalSystemAgent.Agent.Learning do
     e -> Logger.warning("[Learning] Failed to persist solutions: #{Exception.message(e)}")
   end
 
+  defp persist_feedback(episode) do
+    path = Path.join(learning_dir(), "feedback.jsonl")
+    line = Jason.encode!(%{episode | timestamp: DateTime.to_iso8601(episode.timestamp)})
+    File.write!(path, line <> "\n", [:append])
+  rescue
+    e -> Logger.warning("[Learning] Failed to persist feedback: #{Exception.message(e)}")
+  end
+
   defp load_persisted(state) do
     patterns = load_json("patterns.json", [])
     solutions = load_json("solutions.json", [])
@@ -14,6 +14,21 @@ defmodule OptimalSystemAgent.Agent.Loop do
     6. If tool_calls: execute each, append results, re-prompt
     7. When no tool_calls: return final response
     8. Write to memory, notify channel
+
+  A message in flight can be cancelled with `cancel/2`. The loop stops at
+  the next streamed token or iteration boundary, emits a `:request_cancelled`
+  system event and replies with a short cancellation notice.
+
+  When the provider rejects a call with a rate limit, the loop emits
+  `:provider_rate_limited` (request_id, provider, retry_after in seconds or
+  nil, message) so clients can show the state until a later call succeeds.
+
+  Extended thinking streams as `:thinking_delta` events. When the model
+  stops thinking, at its first answer token or at the end of the call, the
+  loop emits `:thinking_finished` with the thinking time in `duration_ms`.
+
+  A shell command streams its output as `:tool_output` events while it runs,
+  each chunk with its byte `offset` in the output.
   """
   use GenServer
   require Logger
@@ -23,12 +38,17 @@ defmodule OptimalSystemAgent.Agent.Loop do
   alias OptimalSystemAgent.Signal.Classifier
   alias OptimalSystemAgent.Signal.NoiseFilter
   alias OptimalSystemAgent.Agent.Hooks
+  alias OptimalSystemAgent.Agent.Tier
   alias OptimalSystemAgent.Providers.Registry, as: Providers
   alias OptimalSystemAgent.Tools.Registry, as: Tools
   alias OptimalSystemAgent.Events.Bus
 
   defp max_iterations, do: Application.get_env(:optimal_system_agent, :max_iterations, 30)
 
+  # In-flight requests per session: {session_id, request_id, cancel_requested?}.
+  # Public so cancel/2 can flag a request while the loop is busy in handle_call.
+  @requests_table :osa_loop_requests
+
   defstruct [
     :session_id,
     :user_id,
@@ -42,6 +62,12 @@ defmodule OptimalSystemAgent.Agent.Loop do
     last_tool_signature: nil,
     status: :idle,
     tools: [],
+    allowed_tools: nil,
+    system_prompt: nil,
+    env: nil,
+    agent: nil,
+    preferences: nil,
+    reply_to: nil,
     plan_mode: false,
     plan_mode_enabled: false,
     last_meta: %{iteration_count: 0, tools_used: []}
@@ -59,13 +85,61 @@ defmodule OptimalSystemAgent.Agent.Loop do
     GenServer.call(via(session_id), {:process, message, opts}, :infinity)
   end
 
-  @doc "Get metadata from the last process_message call (iteration_count, tools_used)."
+  @doc """
+  Request cancellation of the message the session's loop is processing.
+
+  When `request_id` is given it must match the in-flight request. Returns
+  `{:ok, request_id}` once the request is flagged, or `{:error, :not_running}`
+  when nothing matching is in flight.
+  """
+  @spec cancel(String.t(), String.t() | nil) :: {:ok, String.t()} | {:error, :not_running}
+  def cancel(session_id, request_id \\ nil) do
+    init_requests_table()
+
+    case :ets.lookup(@requests_table, session_id) do
+      [{^session_id, current, _}] when is_nil(request_id) or request_id == current ->
+        :ets.insert(@requests_table, {session_id, current, true})
+        {:ok, current}
+
+      _ ->
+        {:error, :not_running}
+    end
+  end
+
+  @doc "Get metadata from the last process_message call (iteration_count, tools_used, cancelled, provider, model)."
   def get_metadata(session_id) do
     GenServer.call(via(session_id), :get_metadata)
   rescue
     _ -> %{iteration_count: 0, tools_used: []}
   end
 
+  @doc """
+  Compact the session's conversation to at most `target_tokens`, e.g. to make
+  room for a prompt that would not fit otherwise. Returns
+  `{:ok, %{tokens_before: n, tokens_after: n}}`, `{:error, :busy}` while a
+  message is being processed, or `{:error, :not_found}`.
+  """
+  @spec compact(String.t(), non_neg_integer()) :: {:ok, map()} | {:error, :busy | :not_found}
+  def compact(session_id, target_tokens) do
+    init_requests_table()
+
+    case :ets.lookup(@requests_table, session_id) do
+      [_] -> {:error, :busy}
+      [] -> GenServer.call(via(session_id), {:compact, target_tokens}, 60_000)
+    end
+  catch
+    :exit, {:timeout, _} -> {:error, :busy}
+    :exit, _ -> {:error, :not_found}
+  end
+
+  @doc "Estimated tokens of the session's conversation, nil when it is busy or not running."
+  @spec context_tokens(String.t()) :: non_neg_integer() | nil
+  def context_tokens(session_id) do
+    GenServer.call(via(session_id), :context_tokens, 2_000)
+  catch
+    :exit, _ -> nil
+  end
+
   # --- Server Callbacks ---
 
   @impl true
@@ -93,9 +167,64 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
   @impl true
   def handle_call({:process, message, opts}, _from, state) do
+    request_id = Keyword.get(opts, :request_id) || generate_request_id()
+    init_requests_table()
+    :ets.insert(@requests_table, {state.session_id, request_id, false})
+
+    try do
+      # Overrides from opts apply to this message only; the session keeps its own.
+      {:reply, reply, new_state} = process(message, opts, state)
+
+      restored = %{
+        provider: state.provider,
+        model: state.model,
+        tools: state.tools,
+        allowed_tools: nil,
+        system_prompt: state.system_prompt,
+        env: state.env,
+        agent: nil,
+        preferences: state.preferences,
+        reply_to: nil
+      }
+
+      {:reply, reply, Map.merge(new_state, restored)}
+    after
+      :ets.delete(@requests_table, state.session_id)
+    end
+  end
+
+  @impl true
+  def handle_call(:get_metadata, _from, state) do
+    {:reply, state.last_meta, state}
+  end
+
+  @impl true
+  def handle_call({:compact, target_tokens}, _from, state) do
+    tokens_before = OptimalSystemAgent.Agent.Compactor.estimate_tokens(state.messages)
+    messages = OptimalSystemAgent.Agent.Compactor.compact(state.messages, target_tokens)
+    state = %{state | messages: messages}
+    emit_context_pressure(state)
+
+    tokens_after = OptimalSystemAgent.Agent.Compactor.estimate_tokens(messages)
+    {:reply, {:ok, %{tokens_before: tokens_before, tokens_after: tokens_after}}, state}
+  end
+
+  @impl true
+  def handle_call(:context_tokens, _from, state) do
+    {:reply, OptimalSystemAgent.Agent.Compactor.estimate_tokens(state.messages), state}
+  end
+
+  @impl true
+  def handle_call(:toggle_plan_mode, _from, state) do
+    new_val = not state.plan_mode_enabled
+    {:reply, {:ok, new_val}, %{state | plan_mode_enabled: new_val}}
+  end
+
+  defp process(message, opts, state) do
     skip_plan = Keyword.get(opts, :skip_plan, false)
 
-    # Apply per-call provider/model overrides (SDK passthrough)
+    # Apply per-call overrides (provider/model, tools, instructions, env, agent,
+    # preferences, reply_to)
     state = apply_overrides(state, opts)
 
     # 0. Clear per-message caches (git info runs once per message, not per iteration)
@@ -112,7 +241,7 @@ defmodule OptimalSystemAgent.Agent.Loop do
         Bus.emit(:system_event, %{event: :signal_low_weight, signal: Map.from_struct(signal), reason: reason})
 
         # Persist to session but don't invoke LLM
-        Memory.append(state.session_id, %{role: "user", content: message, channel: state.channel})
+        Memory.append(state.session_id, user_entry(state, message))
         ack = noise_acknowledgment(reason)
         Memory.append(state.session_id, %{role: "assistant", content: ack, channel: state.channel})
         state = %{state | status: :idle}
@@ -120,7 +249,7 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
       {:signal, _weight} ->
         # 3. Persist user message to JSONL session storage
-        Memory.append(state.session_id, %{role: "user", content: message, channel: state.channel})
+        Memory.append(state.session_id, user_entry(state, message))
 
         # 4. Compact message history if needed, then process through agent loop
         compacted = OptimalSystemAgent.Agent.Compactor.maybe_compact(state.messages)
@@ -166,6 +295,7 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
               Bus.emit(:agent_response, %{
                 session_id: state.session_id,
+                request_id: current_request_id(state.session_id),
                 response: plan_text,
                 response_type: "plan",
                 signal: Map.from_struct(signal)
@@ -192,11 +322,18 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
               emit_context_pressure(state)
 
-              meta = %{iteration_count: state.iteration, tools_used: extract_tools_used(state.messages)}
+              meta = %{
+                iteration_count: state.iteration,
+                tools_used: extract_tools_used(state.messages),
+                cancelled: cancel_requested?(state.session_id),
+                provider: state.provider,
+                model: state.model
+              }
               state = %{state | last_meta: meta}
 
               Bus.emit(:agent_response, %{
                 session_id: state.session_id,
+                request_id: current_request_id(state.session_id),
                 response: response,
                 signal: Map.from_struct(signal)
               })
@@ -207,7 +344,13 @@ defmodule OptimalSystemAgent.Agent.Loop do
           # Normal execution path
           {response, state} = run_loop(state)
 
-          meta = %{iteration_count: state.iteration, tools_used: extract_tools_used(state.messages)}
+          meta = %{
+            iteration_count: state.iteration,
+            tools_used: extract_tools_used(state.messages),
+            cancelled: cancel_requested?(state.session_id),
+            provider: state.provider,
+            model: state.model
+          }
 
           state = %{
             state
@@ -223,6 +366,7 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
           Bus.emit(:agent_response, %{
             session_id: state.session_id,
+            request_id: current_request_id(state.session_id),
             response: response,
             signal: Map.from_struct(signal)
           })
@@ -232,15 +376,13 @@ defmodule OptimalSystemAgent.Agent.Loop do
     end
   end
 
-  @impl true
-  def handle_call(:get_metadata, _from, state) do
-    {:reply, state.last_meta, state}
-  end
-
-  @impl true
-  def handle_call(:toggle_plan_mode, _from, state) do
-    new_val = not state.plan_mode_enabled
-    {:reply, {:ok, new_val}, %{state | plan_mode_enabled: new_val}}
+  # The user's message as persisted, with the message it replies to if any.
+  # The owner goes with each user message so a persisted session can be
+  # matched to its user after the loop is gone.
+  defp user_entry(state, message) do
+    entry = %{role: "user", content: message, channel: state.channel}
+    entry = if state.user_id, do: Map.put(entry, :user_id, state.user_id), else: entry
+    if state.reply_to, do: Map.put(entry, :metadata, %{reply_to: state.reply_to}), else: entry
   end
 
   # --- Agent Loop ---
@@ -257,11 +399,23 @@ defmodule OptimalSystemAgent.Agent.Loop do
   end
 
   defp do_run_loop(state) do
+    if cancel_requested?(state.session_id) do
+      cancelled_response(state)
+    else
+      do_run_iteration(state)
+    end
+  end
+
+  defp do_run_iteration(state) do
     # Build context (passes current signal for signal-aware system prompt)
     context = Context.build(state, state.current_signal)
 
     # Emit timing event before LLM call
-    Bus.emit(:llm_request, %{session_id: state.session_id, iteration: state.iteration})
+    Bus.emit(:llm_request, %{
+      session_id: state.session_id,
+      iteration: state.iteration,
+      max_iterations: max_iterations()
+    })
     start_time = System.monotonic_time(:millisecond)
 
     # Call LLM with streaming — emits per-token SSE events for live TUI display.
@@ -271,6 +425,9 @@ defmodule OptimalSystemAgent.Agent.Loop do
     llm_opts = if thinking_opts, do: Keyword.put(llm_opts, :thinking, thinking_opts), else: llm_opts
     result = llm_chat_stream(state, context.messages, llm_opts)
 
+    # A non-streaming fallback cannot be interrupted; drop its tool calls.
+    result = if cancel_requested?(state.session_id), do: {:error, :cancelled}, else: result
+
     # Emit timing + usage event after LLM call
     duration_ms = System.monotonic_time(:millisecond) - start_time
 
@@ -371,6 +528,9 @@ defmodule OptimalSystemAgent.Agent.Loop do
           run_loop(state)
         end
 
+      {:error, :cancelled} ->
+        cancelled_response(state)
+
       {:error, reason} ->
         reason_str = if is_binary(reason), do: reason, else: inspect(reason)
 
@@ -385,12 +545,39 @@ defmodule OptimalSystemAgent.Agent.Loop do
             {"I've exceeded the context window. Try breaking your request into smaller parts.", state}
           else
             Logger.error("LLM call failed: #{reason_str}")
-            {"I encountered an error processing your request. Please try again.", state}
+
+            if rate_limited?(reason_str) do
+              Bus.emit(:system_event, %{
+                event: :provider_rate_limited,
+                session_id: state.session_id,
+                request_id: current_request_id(state.session_id),
+                provider: to_string(state.provider || default_provider()),
+                retry_after: retry_after_seconds(reason_str),
+                message: reason_str
+              })
+
+              {"The provider is rate limiting requests. Wait a moment and try again.", state}
+            else
+              {"I encountered an error processing your request. Please try again.", state}
+            end
           end
         end
     end
   end
 
+  defp cancelled_response(state) do
+    Logger.info("Request cancelled for session #{state.session_id} at iteration #{state.iteration}")
+
+    Bus.emit(:system_event, %{
+      event: :request_cancelled,
+      session_id: state.session_id,
+      request_id: current_request_id(state.session_id),
+      iteration: state.iteration
+    })
+
+    {"Request cancelled.", state}
+  end
+
   defp context_overflow?(reason) do
     String.contains?(reason, "context_length") or
       String.contains?(reason, "max_tokens") or
@@ -398,15 +585,54 @@ defmodule OptimalSystemAgent.Agent.Loop do
       String.contains?(reason, "token limit")
   end
 
-  defp tool_call_hint(%{"command" => cmd}), do: String.slice(cmd, 0, 60)
-  defp tool_call_hint(%{"path" => p}), do: p
-  defp tool_call_hint(%{"query" => q}), do: String.slice(q, 0, 60)
+  defp rate_limited?(reason) do
+    down = String.downcase(reason)
+
+    String.contains?(reason, " 429") or
+      String.contains?(down, "rate limit") or
+      String.contains?(down, "rate_limit") or
+      String.contains?(down, "too many requests")
+  end
+
+  # Providers that report a wait time say "retry after N" or "try again in
+  # Ns"; nil when the error names none.
+  defp retry_after_seconds(reason) do
+    case Regex.run(~r/(?:retry[- _]after|try again in)\D{0,3}(\d+(?:\.\d+)?)/i, reason) do
+      [_, secs] -> secs |> Float.parse() |> elem(0) |> Float.ceil() |> trunc()
+      nil -> nil
+    end
+  end
+
+  defp default_provider, do: Application.get_env(:optimal_system_agent, :default_provider, :ollama)
 
-  defp tool_call_hint(args) when is_map(args) and map_size(args) > 0 do
+  @doc "A short summary of a tool call's arguments for progress and audit events."
+  @spec tool_call_hint(term()) :: String.t()
+  def tool_call_hint(%{"command" => cmd}), do: String.slice(cmd, 0, 60)
+  def tool_call_hint(%{"path" => p}), do: p
+  def tool_call_hint(%{"query" => q}), do: String.slice(q, 0, 60)
+
+  def tool_call_hint(args) when is_map(args) and map_size(args) > 0 do
     args |> Map.keys() |> Enum.take(2) |> Enum.join(", ")
   end
 
-  defp tool_call_hint(_), do: ""
+  def tool_call_hint(_), do: ""
+
+  # Streams a running tool's output to the session as :tool_output system
+  # events. Bus dispatch is concurrent, so each chunk carries its byte offset
+  # for the client to put them back in order. A chunk can end inside a
+  # multi-byte character, which JSON cannot carry, so invalid bytes are
+  # replaced.
+  defp tool_output_fun(tool_call, state) do
+    fn chunk, offset ->
+      Bus.emit(:system_event, %{
+        event: :tool_output,
+        name: tool_call.name,
+        output: String.replace_invalid(chunk),
+        offset: offset,
+        session_id: state.session_id
+      })
+    end
+  end
 
   # --- Parallel Tool Execution ---
 
@@ -424,22 +650,31 @@ defmodule OptimalSystemAgent.Agent.Loop do
       session_id: state.session_id
     }
 
-    tool_result =
-      case run_hooks(:pre_tool_use, pre_payload) do
-        {:blocked, reason} ->
-          "Blocked: #{reason}"
+    {tool_result, tool_ok} =
+      if tool_allowed?(state, tool_call.name) do
+        case run_hooks(:pre_tool_use, pre_payload) do
+          {:blocked, reason} ->
+            {"Blocked: #{reason}", false}
 
-        _ ->
-          case Tools.execute(tool_call.name, tool_call.arguments) do
-            {:ok, {:image, %{media_type: mt, data: b64, path: p}}} ->
-              {:image, mt, b64, p}
+          _ ->
+            on_output = tool_output_fun(tool_call, state)
 
-            {:ok, content} ->
-              content
+            tool_opts = if state.env, do: [on_output: on_output, env: state.env], else: [on_output: on_output]
 
-            {:error, reason} ->
-              "Error: #{reason}"
-          end
+            case Tools.execute(tool_call.name, tool_call.arguments, tool_opts) do
+              {:ok, {:image, %{media_type: mt, data: b64, path: p}}} ->
+                {{:image, mt, b64, p}, true}
+
+              {:ok, content} ->
+                {content, true}
+
+              {:error, reason} ->
+                {"Error: #{reason}", false}
+            end
+        end
+      else
+        # The model asked for a tool the request did not enable.
+        {"Blocked: #{tool_call.name} is disabled for this request", false}
       end
 
     tool_duration_ms = System.monotonic_time(:millisecond) - start_time_tool
@@ -467,13 +702,14 @@ defmodule OptimalSystemAgent.Agent.Loop do
       phase: :end,
       duration_ms: tool_duration_ms,
       args: arg_hint,
+      success: tool_ok,
       session_id: state.session_id
     })
 
     Bus.emit(:tool_result, %{
       name: tool_call.name,
       result: String.slice(result_str, 0, 500),
-      success: !match?({:error, _}, tool_result),
+      success: tool_ok,
       session_id: state.session_id
     })
 
@@ -512,24 +748,25 @@ defmodule OptimalSystemAgent.Agent.Loop do
   defp llm_chat_stream(%{session_id: session_id, provider: provider, model: model}, messages, opts) do
     # Stash result from {:done, _} callback into process dictionary
     Process.put(:llm_stream_result, nil)
+    Process.delete(:llm_thinking_started)
 
+    # Streaming callbacks run in this process, so a throw aborts the stream.
     callback = fn
-      {:text_delta, text} ->
-        Bus.emit(:system_event, %{
-          event: :streaming_token,
-          session_id: session_id,
-          text: text
-        })
+      {:text_delta, _text} = delta ->
+        if cancel_requested?(session_id), do: throw(:osa_cancelled)
+        finish_thinking(session_id)
+        emit_delta(session_id, delta)
 
       {:done, result} ->
+        finish_thinking(session_id)
         Process.put(:llm_stream_result, result)
 
-      {:thinking_delta, text} ->
-        Bus.emit(:system_event, %{
-          event: :thinking_delta,
-          session_id: session_id,
-          text: text
-        })
+      {:thinking_start, _} ->
+        Process.put(:llm_thinking_started, System.monotonic_time(:millisecond))
+
+      {:thinking_delta, _text} = delta ->
+        if cancel_requested?(session_id), do: throw(:osa_cancelled)
+        emit_delta(session_id, delta)
 
       # Ignore tool_use deltas — these are handled after the full result
       _other ->
@@ -539,15 +776,51 @@ defmodule OptimalSystemAgent.Agent.Loop do
     opts = if provider, do: Keyword.put(opts, :provider, provider), else: opts
     opts = if model, do: Keyword.put(opts, :model, model), else: opts
 
-    case Providers.chat_stream(messages, callback, opts) do
-      :ok ->
-        case Process.get(:llm_stream_result) do
-          nil -> {:error, "Stream completed but no result received"}
-          result -> {:ok, result}
-        end
+    try do
+      case Providers.chat_stream(messages, callback, opts) do
+        :ok ->
+          case Process.get(:llm_stream_result) do
+            nil -> {:error, "Stream completed but no result received"}
+            result -> {:ok, result}
+          end
+
+        {:error, _} = err ->
+          err
+      end
+    catch
+      :throw, :osa_cancelled -> {:error, :cancelled}
+    end
+  end
 
-      {:error, _} = err ->
-        err
+  defp emit_delta(session_id, {:text_delta, text}) do
+    Bus.emit(:system_event, %{
+      event: :streaming_token,
+      session_id: session_id,
+      text: text
+    })
+  end
+
+  defp emit_delta(session_id, {:thinking_delta, text}) do
+    Bus.emit(:system_event, %{
+      event: :thinking_delta,
+      session_id: session_id,
+      text: text
+    })
+  end
+
+  # Emits :thinking_finished once per thinking phase, stashed in the process
+  # dictionary by the :thinking_start callback.
+  defp finish_thinking(session_id) do
+    case Process.delete(:llm_thinking_started) do
+      nil ->
+        :ok
+
+      started ->
+        Bus.emit(:system_event, %{
+          event: :thinking_finished,
+          session_id: session_id,
+          duration_ms: System.monotonic_time(:millisecond) - started
+        })
     end
   end
 
@@ -556,11 +829,39 @@ defmodule OptimalSystemAgent.Agent.Loop do
     state
     |> maybe_override(:provider, Keyword.get(opts, :provider))
     |> maybe_override(:model, Keyword.get(opts, :model))
+    |> restrict_tools(Keyword.get(opts, :allowed_tools))
+    |> maybe_override(:system_prompt, Keyword.get(opts, :system_prompt))
+    |> maybe_override(:env, Keyword.get(opts, :env))
+    |> maybe_override(:preferences, Keyword.get(opts, :preferences))
+    |> maybe_override(:reply_to, Keyword.get(opts, :reply_to))
+    |> direct_to_agent(Keyword.get(opts, :agent), Keyword.get(opts, :model) != nil)
   end
 
   defp maybe_override(state, _key, nil), do: state
   defp maybe_override(state, key, value), do: Map.put(state, key, value)
 
+  # nil keeps every tool; a list (possibly empty) keeps only the named ones.
+  defp restrict_tools(state, nil), do: state
+
+  defp restrict_tools(state, names) when is_list(names) do
+    %{state | tools: Enum.filter(state.tools, &(&1.name in names)), allowed_tools: names}
+  end
+
+  # A message addressed to a roster agent (`@name ...`) runs with that agent's
+  # prompt and, unless the caller picked a model, the model of its tier.
+  defp direct_to_agent(state, nil, _model_given?), do: state
+
+  defp direct_to_agent(state, agent, model_given?) do
+    state = %{state | agent: agent}
+
+    if model_given?,
+      do: state,
+      else: %{state | model: Tier.model_for(agent.tier, state.provider || default_provider())}
+  end
+
+  defp tool_allowed?(%{allowed_tools: nil}, _name), do: true
+  defp tool_allowed?(%{allowed_tools: names}, name), do: name in names
+
   # --- Plan Mode ---
 
   defp should_plan?(signal, state) do
@@ -579,6 +880,37 @@ defmodule OptimalSystemAgent.Agent.Loop do
 
   defp via(session_id), do: {:via, Registry, {OptimalSystemAgent.SessionRegistry, session_id}}
 
+  @doc false
+  # Created at application startup; lazily on first use otherwise.
+  def init_requests_table do
+    if :ets.whereis(@requests_table) == :undefined do
+      try do
+        :ets.new(@requests_table, [:set, :public, :named_table])
+      rescue
+        ArgumentError -> :already_exists
+      end
+    end
+  end
+
+  defp cancel_requested?(session_id) do
+    match?([{_, _, true}], :ets.lookup(@requests_table, session_id))
+  rescue
+    ArgumentError -> false
+  end
+
+  defp current_request_id(session_id) do
+    case :ets.lookup(@requests_table, session_id) do
+      [{_, request_id, _}] -> request_id
+      _ -> nil
+    end
+  rescue
+    ArgumentError -> nil
+  end
+
+  defp generate_request_id do
+    "req_" <> Base.url_encode64(:crypto.strong_rand_bytes(8), padding: false)
+  end
+
   @doc """
   Returns the owner (user_id) stored in the SessionRegistry for the given session,
   or `nil` if the session does not exist.
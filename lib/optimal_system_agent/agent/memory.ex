@@ -7,6 +7,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
   1. **Session Memory** (JSONL per session)
      `~/.osa/sessions/{session_id}.jsonl`
      Append-only conversation history. Load by session ID for continuity.
+     `archive_session/1` moves a file to `~/.osa/sessions/archive/`, out of
+     the listing; `delete_session/1` removes it and its SQLite messages.
 
   2. **Long-term Memory** (MEMORY.md)
      `~/.osa/MEMORY.md`
@@ -26,6 +28,9 @@ defmodule OptimalSystemAgent.Agent.Memory do
     only the top entries that fit within a token budget.
   - `search/2` provides keyword and category search with sorting options.
   - `archive/1` moves old low-importance entries to dated archive files.
+  - `update_entry/2` and `delete_entry/1` let users correct or drop an entry.
+    A pinned entry (`## [category] timestamp (pinned)`) is always recalled
+    and never archived.
   """
   use GenServer
   require Logger
@@ -100,6 +105,27 @@ defmodule OptimalSystemAgent.Agent.Memory do
     GenServer.cast(__MODULE__, {:remember, content, category})
   end
 
+  @doc """
+  Change an entry in MEMORY.md. `attrs` may hold `:content`, `:category` and
+  `:pinned`; the timestamp is kept. A new content or category gives the entry
+  a new ID.
+
+  Content with a line starting `## [`, or a category holding `]` or a line
+  break, would read back as a different entry and is refused.
+
+  Returns `{:ok, entry}`, `{:error, :not_found}` or `{:error, :invalid_entry}`.
+  """
+  @spec update_entry(String.t(), map()) :: {:ok, map()} | {:error, :not_found | :invalid_entry}
+  def update_entry(entry_id, attrs) when is_map(attrs) do
+    GenServer.call(__MODULE__, {:update_entry, entry_id, attrs})
+  end
+
+  @doc "Remove an entry from MEMORY.md. Returns `:ok` or `{:error, :not_found}`."
+  @spec delete_entry(String.t()) :: :ok | {:error, :not_found}
+  def delete_entry(entry_id) do
+    GenServer.call(__MODULE__, {:delete_entry, entry_id})
+  end
+
   @doc "Read current MEMORY.md contents (full dump, backward-compatible)."
   def recall do
     GenServer.call(__MODULE__, :recall)
@@ -159,13 +185,31 @@ defmodule OptimalSystemAgent.Agent.Memory do
   end
 
   @doc """
-  List all session IDs with metadata (last active, message count, topic hint).
+  List all session IDs with metadata (last active, message count, topic hint,
+  and the owner's user_id when the first message recorded one).
   """
   @spec list_sessions() :: [map()]
   def list_sessions do
     GenServer.call(__MODULE__, :list_sessions)
   end
 
+  @doc """
+  Archive a session: its JSONL file moves to `~/.osa/sessions/archive/`, so it
+  is no longer listed. Its messages stay in SQLite.
+  """
+  @spec archive_session(String.t()) :: :ok | {:error, :not_found | term()}
+  def archive_session(session_id) do
+    GenServer.call(__MODULE__, {:archive_session, session_id})
+  end
+
+  @doc """
+  Delete a session: its JSONL file, archived or not, and its messages in SQLite.
+  """
+  @spec delete_session(String.t()) :: :ok | {:error, :not_found | term()}
+  def delete_session(session_id) do
+    GenServer.call(__MODULE__, {:delete_session, session_id})
+  end
+
   @doc """
   Resume a previous session — returns its history for re-injection into a loop.
   """
@@ -268,6 +312,52 @@ defmodule OptimalSystemAgent.Agent.Memory do
     {:reply, result, state}
   end
 
+  @impl true
+  def handle_call({:update_entry, entry_id, attrs}, _from, state) do
+    result =
+      if round_trips?(attrs) do
+        rewrite_entries(fn entries ->
+          case Enum.find_index(entries, fn {id, _} -> id == entry_id end) do
+            nil ->
+              {:error, :not_found}
+
+            i ->
+              {_id, entry} = Enum.at(entries, i)
+
+              entry =
+                entry
+                |> Map.merge(Map.take(attrs, [:content, :category, :pinned]))
+                |> refresh_entry()
+
+              {:ok, List.replace_at(entries, i, {entry.id, entry}), entry}
+          end
+        end)
+      else
+        {:error, :invalid_entry}
+      end
+
+    {:reply, result, state}
+  end
+
+  @impl true
+  def handle_call({:delete_entry, entry_id}, _from, state) do
+    result =
+      rewrite_entries(fn entries ->
+        case List.keytake(entries, entry_id, 0) do
+          nil -> {:error, :not_found}
+          {{_id, entry}, rest} -> {:ok, rest, entry}
+        end
+      end)
+
+    reply =
+      case result do
+        {:ok, _entry} -> :ok
+        error -> error
+      end
+
+    {:reply, reply, state}
+  end
+
   @impl true
   def handle_call({:archive, max_age_days}, _from, state) do
     result = do_archive(max_age_days)
@@ -286,6 +376,42 @@ defmodule OptimalSystemAgent.Agent.Memory do
     {:reply, sessions, state}
   end
 
+  @impl true
+  def handle_call({:archive_session, session_id}, _from, state) do
+    result =
+      with :ok <- valid_session_id(session_id) do
+        path = session_path(state.sessions_dir, session_id)
+        archive_dir = Path.join(state.sessions_dir, "archive")
+
+        if File.exists?(path) do
+          File.mkdir_p!(archive_dir)
+          File.rename(path, session_path(archive_dir, session_id))
+        else
+          {:error, :not_found}
+        end
+      end
+
+    {:reply, result, state}
+  end
+
+  @impl true
+  def handle_call({:delete_session, session_id}, _from, state) do
+    result =
+      with :ok <- valid_session_id(session_id) do
+        paths = [
+          session_path(state.sessions_dir, session_id),
+          session_path(Path.join(state.sessions_dir, "archive"), session_id)
+        ]
+
+        removed = Enum.count(paths, &(File.rm(&1) == :ok))
+        deleted = delete_from_sqlite(session_id)
+
+        if removed + deleted > 0, do: :ok, else: {:error, :not_found}
+      end
+
+    {:reply, result, state}
+  end
+
   @impl true
   def handle_call({:resume_session, session_id}, _from, state) do
     path = session_path(state.sessions_dir, session_id)
@@ -328,8 +454,12 @@ defmodule OptimalSystemAgent.Agent.Memory do
   defp do_recall_relevant(message, max_tokens) do
     # Try semantic search first (Python sidecar), fall back to keyword search
     case try_semantic_search(message, max_tokens) do
-      {:ok, results} when results != "" -> results
-      _ -> do_recall_relevant_keyword(message, max_tokens)
+      {:ok, results} when results != "" ->
+        pinned = select_within_budget(pinned_entries(), max_tokens * @chars_per_token)
+        Enum.join(Enum.reject([pinned, results], &(&1 == "")), "\n")
+
+      _ ->
+        do_recall_relevant_keyword(message, max_tokens)
     end
   end
 
@@ -410,9 +540,9 @@ defmodule OptimalSystemAgent.Agent.Memory do
           |> Enum.reject(&is_nil/1)
           |> Enum.sort_by(fn {score, _entry} -> score end, :desc)
 
-        # Select entries within token budget
+        # Select entries within token budget, pinned entries first
         max_chars = max_tokens * @chars_per_token
-        select_within_budget(scored, max_chars)
+        select_within_budget(with_pinned(scored), max_chars)
       end
     end
   end
@@ -484,7 +614,24 @@ defmodule OptimalSystemAgent.Agent.Memory do
       end
 
     scored = Enum.map(entries, fn entry -> {1.0, entry} end)
-    select_within_budget(scored, max_chars)
+    select_within_budget(with_pinned(scored), max_chars)
+  end
+
+  # Pinned entries, as {score, entry} pairs ranked above any recall score.
+  defp pinned_entries do
+    try do
+      :ets.tab2list(@entry_table)
+      |> Enum.map(fn {_id, entry} -> entry end)
+      |> Enum.filter(& &1[:pinned])
+      |> Enum.sort_by(fn entry -> entry[:timestamp] || "" end, :desc)
+      |> Enum.map(fn entry -> {2.0, entry} end)
+    rescue
+      _ -> []
+    end
+  end
+
+  defp with_pinned(scored) do
+    pinned_entries() ++ Enum.reject(scored, fn {_score, entry} -> entry[:pinned] end)
   end
 
   # ────────────────────────────────────────────────────────────────────
@@ -578,7 +725,7 @@ defmodule OptimalSystemAgent.Agent.Memory do
       Enum.split_with(all_entries, fn {_id, entry} ->
         importance = Map.get(@category_importance, entry[:category] || "general", 0.5)
         old_enough = entry_before_cutoff?(entry, cutoff)
-        old_enough and importance < 0.7
+        old_enough and importance < 0.7 and not entry[:pinned]
       end)
 
     if to_archive == [] do
@@ -593,9 +740,7 @@ defmodule OptimalSystemAgent.Agent.Memory do
 
         archive_content =
           to_archive
-          |> Enum.map(fn {_id, entry} ->
-            "## [#{entry[:category] || "general"}] #{entry[:timestamp] || "unknown"}\n#{entry[:content] || ""}\n"
-          end)
+          |> Enum.map(fn {_id, entry} -> format_entry(entry) end)
           |> Enum.join("\n")
 
         File.write!(archive_path, archive_content, [:append, :utf8])
@@ -604,9 +749,7 @@ defmodule OptimalSystemAgent.Agent.Memory do
         kept_content =
           to_keep
           |> Enum.sort_by(fn {_id, entry} -> entry[:timestamp] || "" end)
-          |> Enum.map(fn {_id, entry} ->
-            "## [#{entry[:category] || "general"}] #{entry[:timestamp] || "unknown"}\n#{entry[:content] || ""}\n"
-          end)
+          |> Enum.map(fn {_id, entry} -> format_entry(entry) end)
           |> Enum.join("\n")
 
         File.write!(memory_file_path(), kept_content, [:utf8])
@@ -735,8 +878,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
       lines = String.split(content, "\n", trim: true)
       message_count = length(lines)
 
-      # Parse first line for start time and topic hint
-      {first_timestamp, topic_hint} =
+      # Parse first line for start time, topic hint and owner
+      {first_timestamp, topic_hint, owner} =
         case lines do
           [first | _] ->
             case Jason.decode(first) do
@@ -752,14 +895,14 @@ defmodule OptimalSystemAgent.Agent.Memory do
                       nil
                   end
 
-                {ts, topic}
+                {ts, topic, Map.get(msg, "user_id")}
 
               _ ->
-                {nil, nil}
+                {nil, nil, nil}
             end
 
           [] ->
-            {nil, nil}
+            {nil, nil, nil}
         end
 
       # Parse last line for end time
@@ -780,7 +923,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
         message_count: message_count,
         first_active: first_timestamp,
         last_active: last_timestamp || first_timestamp,
-        topic_hint: topic_hint
+        topic_hint: topic_hint,
+        owner: owner
       }
     rescue
       _ ->
@@ -789,7 +933,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
           message_count: 0,
           first_active: nil,
           last_active: nil,
-          topic_hint: nil
+          topic_hint: nil,
+          owner: nil
         }
     end
   end
@@ -878,6 +1023,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
     # Parse entries in the format:
     # ## [category] 2026-02-27T10:30:00Z
     # Content spanning multiple lines...
+    #
+    # A header ending in "(pinned)" marks a pinned entry.
 
     entry_regex = ~r/^## \[([^\]]+)\]\s+(.+)$/m
 
@@ -891,7 +1038,13 @@ defmodule OptimalSystemAgent.Agent.Memory do
 
   defp parse_entry_parts([potential_header | rest], acc) do
     case Regex.run(~r/^## \[([^\]]+)\]\s+(.+)$/, String.trim(potential_header)) do
-      [_full, category, timestamp_str] ->
+      [_full, category, header_rest] ->
+        {timestamp_str, pinned} =
+          case String.replace_suffix(header_rest, "(pinned)", "") do
+            ^header_rest -> {header_rest, false}
+            ts -> {String.trim(ts), true}
+          end
+
         # The next element (if any) is the content until next header
         {content, remaining} =
           case rest do
@@ -917,7 +1070,8 @@ defmodule OptimalSystemAgent.Agent.Memory do
           category: category,
           timestamp: timestamp_str,
           content: content,
-          importance: importance
+          importance: importance,
+          pinned: pinned
         }
 
         parse_entry_parts(remaining, [{entry_id, entry} | acc])
@@ -928,6 +1082,54 @@ defmodule OptimalSystemAgent.Agent.Memory do
     end
   end
 
+  # Recompute the derived fields of an edited entry.
+  defp refresh_entry(entry) do
+    %{
+      entry
+      | id: generate_entry_id(entry.category, entry.timestamp, entry.content),
+        importance: compute_importance(entry.category, entry.content)
+    }
+  end
+
+  # An edit must parse back as the same single entry: no header line in the
+  # content and nothing in the category that ends the header early.
+  defp round_trips?(attrs) do
+    not Regex.match?(~r/^## \[/m, Map.get(attrs, :content, "")) and
+      not String.contains?(Map.get(attrs, :category, ""), ["]", "\n", "\r"])
+  end
+
+  defp format_entry(entry) do
+    pin = if entry[:pinned], do: " (pinned)", else: ""
+
+    "## [#{entry[:category] || "general"}] #{entry[:timestamp] || "unknown"}#{pin}\n#{entry[:content] || ""}\n"
+  end
+
+  # Apply fun to the parsed entries of MEMORY.md and write the file back,
+  # keeping any text before the first entry. fun returns
+  # {:ok, entries, result} to write, or an error to leave the file alone.
+  defp rewrite_entries(fun) do
+    content =
+      if File.exists?(memory_file_path()), do: File.read!(memory_file_path()), else: ""
+
+    preamble =
+      case Regex.run(~r/^## \[/m, content, return: :index) do
+        [{0, _}] -> ""
+        [{i, _}] -> binary_part(content, 0, i)
+        nil -> content
+      end
+
+    case fun.(parse_memory_entries(content)) do
+      {:ok, entries, result} ->
+        body = Enum.map_join(entries, "\n", fn {_id, entry} -> format_entry(entry) end)
+        File.write!(memory_file_path(), preamble <> body, [:utf8])
+        build_index()
+        {:ok, result}
+
+      error ->
+        error
+    end
+  end
+
   defp generate_entry_id(category, timestamp, content) do
     data = "#{category}:#{timestamp}:#{content}"
     :crypto.hash(:sha256, data) |> Base.encode16(case: :lower) |> String.slice(0, 16)
@@ -979,6 +1181,18 @@ defmodule OptimalSystemAgent.Agent.Memory do
     Path.join(dir, "#{session_id}.jsonl")
   end
 
+  # Session IDs name files, so one that could reach outside the sessions
+  # directory is refused.
+  defp valid_session_id(session_id) when is_binary(session_id) do
+    if session_id =~ ~r/^[\w.-]+$/ and session_id not in [".", ".."] do
+      :ok
+    else
+      {:error, :not_found}
+    end
+  end
+
+  defp valid_session_id(_), do: {:error, :not_found}
+
   defp memory_file_path do
     Path.join(osa_dir(), "MEMORY.md")
   end
@@ -1013,6 +1227,17 @@ defmodule OptimalSystemAgent.Agent.Memory do
       Logger.warning("SQLite write error: #{Exception.message(e)}")
   end
 
+  defp delete_from_sqlite(session_id) do
+    import Ecto.Query
+
+    {count, _} = from(m in Message, where: m.session_id == ^session_id) |> Repo.delete_all()
+    count
+  rescue
+    e ->
+      Logger.warning("SQLite delete error: #{Exception.message(e)}")
+      0
+  end
+
   defp load_from_sqlite(session_id) do
     import Ecto.Query
 
@@ -21,7 +21,7 @@ defmodule OptimalSystemAgent.Agent.Orchestrator do
   use GenServer
   require Logger
 
-  alias OptimalSystemAgent.Agent.{Appraiser, Roster, Tier, TaskQueue}
+  alias OptimalSystemAgent.Agent.{Appraiser, Loop, Roster, Tier, TaskQueue}
   alias OptimalSystemAgent.Events.Bus
   alias OptimalSystemAgent.Providers.Registry, as: Providers
   alias OptimalSystemAgent.Tools.Registry, as: Tools
@@ -1026,11 +1026,14 @@ defmodule OptimalSystemAgent.Agent.Orchestrator do
          task_id,
          system_prompt,
          sub_task,
-         _session_id,
+         session_id,
          orchestrator_pid,
          cached_tools,
          tier_opts
        ) do
+    # Lets the tool calls below be reported to the session for its audit log.
+    Process.put(:osa_sub_agent, {session_id, sub_task.name})
+
     # Build the conversation for this sub-agent
     user_message =
       if sub_task.context do
@@ -1198,12 +1201,17 @@ defmodule OptimalSystemAgent.Agent.Orchestrator do
 
               # Use execute_direct to bypass GenServer — Tools.Registry is blocked
               # by the parent execute("orchestrate") call that spawned us.
-              result_str =
+              started = System.monotonic_time(:millisecond)
+
+              {result_str, success} =
                 case Tools.execute_direct(tool_call.name, tool_call.arguments) do
-                  {:ok, output} -> output
-                  {:error, reason} -> "Error: #{reason}"
+                  {:ok, output} -> {output, true}
+                  {:error, reason} -> {"Error: #{reason}", false}
                 end
 
+              duration_ms = System.monotonic_time(:millisecond) - started
+              emit_agent_tool_call(tool_call, duration_ms, success)
+
               tool_msg = %{role: "tool", tool_call_id: tool_call.id, content: result_str}
               {msgs ++ [tool_msg], tu, et + estimate_tokens(result_str)}
             end)
@@ -1237,6 +1245,26 @@ defmodule OptimalSystemAgent.Agent.Orchestrator do
     end
   end
 
+  # Reports a sub-agent's tool call to the session that started it. Unlike
+  # the main loop's :tool_call events, these do not drive progress displays.
+  defp emit_agent_tool_call(tool_call, duration_ms, success) do
+    case Process.get(:osa_sub_agent) do
+      {session_id, agent_name} ->
+        Bus.emit(:system_event, %{
+          event: :agent_tool_call,
+          session_id: session_id,
+          agent_name: agent_name,
+          name: tool_call.name,
+          args: Loop.tool_call_hint(tool_call.arguments),
+          duration_ms: duration_ms,
+          success: success
+        })
+
+      nil ->
+        :ok
+    end
+  end
+
   # ── Agent Role Prompts ──────────────────────────────────────────────
 
   defp build_agent_prompt(sub_task) do
@@ -29,7 +29,10 @@ defmodule OptimalSystemAgent.Agent.Scheduler do
     - "command" — execute a shell command (same security checks as shell_execute)
     - "webhook" — make an outbound HTTP request; on_failure can trigger an agent job
 
-  Jobs fire on a 1-minute tick. Cron expressions support:
+  A job with a "notify_session" field reports each run's outcome to that
+  session as a `schedule_result` system event.
+
+  Jobs fire on a 1-minute tick (UTC). Cron expressions support:
     - `*`       any value
     - `*/n`     every n-th value
     - `n`       exact value
@@ -569,7 +572,10 @@ defmodule OptimalSystemAgent.Agent.Scheduler do
     end
 
     Enum.reduce(firing, state, fn job, acc ->
-      case execute_cron_job(job) do
+      result = execute_cron_job(job)
+      notify_result(job, result)
+
+      case result do
         {:ok, _} ->
           Logger.info("Cron '#{job["id"]}' (#{job["name"]}): completed")
           %{acc | failures: Map.delete(acc.failures, job["id"])}
@@ -592,6 +598,28 @@ defmodule OptimalSystemAgent.Agent.Scheduler do
     end)
   end
 
+  # Jobs created from a chat carry "notify_session"; the outcome of each run is
+  # delivered to that session as a system event.
+  defp notify_result(%{"notify_session" => session_id} = job, result)
+       when is_binary(session_id) and session_id != "" do
+    {status, output} =
+      case result do
+        {:ok, output} -> {"ok", to_string(output)}
+        {:error, reason} -> {"error", to_string(reason)}
+      end
+
+    Bus.emit(:system_event, %{
+      event: :schedule_result,
+      session_id: session_id,
+      job_id: job["id"],
+      name: job["name"],
+      status: status,
+      output: output
+    })
+  end
+
+  defp notify_result(_job, _result), do: :ok
+
   defp execute_cron_job(%{"type" => "agent", "job" => task} = job) do
     Logger.debug("Cron '#{job["id"]}': running agent task")
     execute_task(task, "cron_#{job["id"]}")
@@ -114,6 +114,10 @@ defmodule OptimalSystemAgent.Application do
     OptimalSystemAgent.Soul.load()
     OptimalSystemAgent.PromptLoader.load()
 
+    # In-flight request table for Loop.cancel/2, owned by the application
+    # process so it survives individual session loops.
+    OptimalSystemAgent.Agent.Loop.init_requests_table()
+
     case Supervisor.start_link(children, opts) do
       {:ok, pid} ->
         # Auto-detect best Ollama model + tier assignments SYNCHRONOUSLY at boot
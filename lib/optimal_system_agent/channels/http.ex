@@ -223,6 +223,40 @@ defmodule OptimalSystemAgent.Channels.HTTP do
     end
   end
 
+  post "/onboarding/verify" do
+    {:ok, raw, conn} = Plug.Conn.read_body(conn)
+
+    case Jason.decode(raw) do
+      {:ok, %{"provider" => provider} = params} when is_binary(provider) ->
+        body =
+          case OptimalSystemAgent.Onboarding.verify_provider(
+                 provider,
+                 Map.get(params, "model"),
+                 Map.get(params, "api_key")
+               ) do
+            :ok ->
+              Jason.encode!(%{status: "ok", message: "Connected to #{provider}"})
+
+            {:error, message, hint} ->
+              Jason.encode!(%{status: "error", message: message, hint: hint})
+          end
+
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, body)
+
+      {:ok, _} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(400, Jason.encode!(%{error: "invalid_request", details: "provider is required"}))
+
+      {:error, _} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(400, Jason.encode!(%{error: "invalid_json"}))
+    end
+  end
+
   # ── All /api routes require JWT ─────────────────────────────────────
 
   forward("/api/v1", to: OptimalSystemAgent.Channels.HTTP.API)
@@ -7,6 +7,7 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
 
   Agent endpoints:
     POST   /orchestrate                    — Process message through agent loop
+    POST   /orchestrate/cancel             — Cancel the in-flight message of a session
     GET    /stream/:session_id             — SSE event stream for a session
     POST   /classify                       — Signal classification only
 
@@ -20,6 +21,9 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     GET    /commands                       — List available slash commands
     POST   /commands/execute               — Execute a slash command
 
+  Roster endpoints:
+    GET    /agents                         — List agent roles with model, tools and description
+
   Orchestration endpoints:
     POST   /orchestrate/complex            — Launch multi-agent orchestrated task
     GET    /orchestrate/:task_id/progress   — Real-time progress for orchestrated task
@@ -34,9 +38,15 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   Memory endpoints:
     POST   /memory                         — Save to memory
     GET    /memory/recall                  — Recall memory
+    GET    /memory/entries                 — List or search memory entries
+    PUT    /memory/entries/:id             — Edit, recategorise or pin an entry
+    DELETE /memory/entries/:id             — Delete an entry
 
   Scheduler endpoints:
     GET    /scheduler/jobs                 — List scheduled jobs
+    POST   /scheduler/jobs                 — Add a scheduled job
+    POST   /scheduler/jobs/:id/toggle      — Enable or disable a job
+    DELETE /scheduler/jobs/:id             — Remove a job
     POST   /scheduler/reload               — Reload scheduler from config
 
   Fleet endpoints:
@@ -52,7 +62,9 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     GET    /events/stream                  — SSE stream for all events
 
   Channel webhooks:
-    GET    /channels                        — List active channel adapters
+    GET    /channels                        — List channel adapters with their settings
+    PUT    /channels/:name                  — Save a channel's settings or enable/disable it
+    POST   /channels/:name/test             — Test a channel's connection
     POST   /channels/telegram/webhook       — Telegram bot webhook
     POST   /channels/discord/webhook        — Discord bot webhook
     POST   /channels/slack/events           — Slack event subscription
@@ -67,15 +79,34 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
 
   Session endpoints:
     GET    /sessions                        — List all sessions (persisted + live)
-    POST   /sessions                        — Create a new session
+    POST   /sessions                        — Create a new session, or restore one by ID with messages
     GET    /sessions/:id                    — Get session details + messages
     GET    /sessions/:id/messages           — Get messages for a session
+    POST   /sessions/cleanup                — Archive or delete old or short sessions (dry_run previews)
 
   Analytics:
     GET    /analytics                       — Usage analytics (budget, learning, hooks, sessions)
 
+  Hook endpoints:
+    GET    /hooks                           — Registered hooks and recent blocks
+    PUT    /hooks/:name                     — Enable or disable a hook
+
+  MCP endpoints:
+    GET    /mcp/servers                     — Configured MCP servers with status and tools
+    POST   /mcp/servers                     — Add an MCP server to mcp.json
+    DELETE /mcp/servers/:name               — Remove an MCP server
+    POST   /mcp/servers/:name/restart       — Restart an MCP server
+
+  Provider key endpoints:
+    GET    /providers/keys                  — Key state of each provider, masked
+    POST   /providers/keys/:provider        — Store or rotate a provider's API key
+    DELETE /providers/keys/:provider        — Remove a stored API key
+
   Other endpoints:
     GET    /machines                        — List active machines
+    GET    /git/status                      — Branch + changed files via the git sidecar
+    GET    /git/diff                        — Unified diff against HEAD via the git sidecar
+    GET    /sidecars                        — Registered sidecars with health and capabilities
     POST   /webhooks/:trigger_id            — Trigger a webhook
     POST   /oscp                            — OSCP protocol endpoint
     GET    /tasks/history                   — Task execution history
@@ -117,6 +148,10 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   alias OptimalSystemAgent.Swarm.Orchestrator, as: Swarm
   alias OptimalSystemAgent.Agent.Orchestrator, as: TaskOrchestrator
   alias OptimalSystemAgent.Agent.Progress
+  alias OptimalSystemAgent.Agent.Roster
+  alias OptimalSystemAgent.Agent.Hooks
+  alias OptimalSystemAgent.MCP.Client, as: MCP
+  alias OptimalSystemAgent.Agent.Tier
   alias OptimalSystemAgent.Channels.Telegram
   alias OptimalSystemAgent.Channels.Discord
   alias OptimalSystemAgent.Channels.Slack
@@ -165,9 +200,11 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   # ── POST /orchestrate ───────────────────────────────────────────────
 
   post "/orchestrate" do
-    with %{"input" => input} <- conn.body_params do
+    with %{"input" => input} <- conn.body_params,
+         {:ok, loop_opts} <- orchestrate_opts(conn.body_params) do
       user_id = conn.body_params["user_id"] || conn.assigns[:user_id]
       session_id = conn.body_params["session_id"] || generate_session_id()
+      request_id = conn.body_params["request_id"]
       _workspace_id = conn.body_params["workspace_id"]
 
       start_time = System.monotonic_time(:millisecond)
@@ -179,7 +216,7 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
 
         _ ->
           # Process through the agent loop (same pipeline as CLI)
-          case Loop.process_message(session_id, input) do
+          case Loop.process_message(session_id, input, loop_opts) do
             {:ok, response} ->
               execution_ms = System.monotonic_time(:millisecond) - start_time
               signal = Classifier.classify(input, :http)
@@ -193,6 +230,8 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
                   tools_used: Map.get(meta, :tools_used, []),
                   iteration_count: Map.get(meta, :iteration_count, 0),
                   execution_ms: execution_ms,
+                  request_id: request_id,
+                  cancelled: Map.get(meta, :cancelled, false),
                   metadata: %{}
                 })
 
@@ -218,10 +257,40 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
           end
       end
     else
+      {:error, details} -> json_error(conn, 400, "invalid_request", details)
       _ -> json_error(conn, 400, "invalid_request", "Missing required field: input")
     end
   end
 
+  # ── POST /orchestrate/cancel ────────────────────────────────────────
+  #
+  # Cancel the message a session is processing. The loop stops at the next
+  # streamed token or iteration, emits a request_cancelled event on the
+  # session stream, and the pending /orchestrate call returns cancelled: true.
+  #
+  # Body: { "session_id": "...", "request_id": "..." }  (request_id optional)
+  # Response 202: { "status": "cancelling", "session_id": "...", "request_id": "..." }
+
+  post "/orchestrate/cancel" do
+    with %{"session_id" => session_id} when is_binary(session_id) <- conn.body_params,
+         :ok <- validate_session_owner(session_id, conn.assigns[:user_id]) do
+      case Loop.cancel(session_id, conn.body_params["request_id"]) do
+        {:ok, request_id} ->
+          body = Jason.encode!(%{status: "cancelling", session_id: session_id, request_id: request_id})
+
+          conn
+          |> put_resp_content_type("application/json")
+          |> send_resp(202, body)
+
+        {:error, :not_running} ->
+          json_error(conn, 409, "not_running", "No matching request in flight for session #{session_id}")
+      end
+    else
+      {:error, :not_found} -> json_error(conn, 404, "not_found", "Session not found")
+      _ -> json_error(conn, 400, "invalid_request", "Missing required field: session_id")
+    end
+  end
+
   # ── GET /stream/:session_id ─────────────────────────────────────────
 
   get "/stream/:session_id" do
@@ -271,6 +340,46 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     end
   end
 
+  # ── POST /tokens/count ──────────────────────────────────────────────
+  #
+  # Counts the tokens of a draft with the tokenizer sidecar; exact is false
+  # when it is down and the count is the heuristic estimate. The response
+  # also carries the context window, and with a session_id the tokens the
+  # session's conversation already uses (null when it is not running), so
+  # the client can tell whether the draft fits.
+
+  post "/tokens/count" do
+    with %{"text" => text} when is_binary(text) <- conn.body_params do
+      exact = OptimalSystemAgent.Go.Tokenizer.available?()
+
+      count =
+        case OptimalSystemAgent.Go.Tokenizer.count_tokens(text) do
+          {:ok, count} -> count
+          {:error, _} -> OptimalSystemAgent.Go.Tokenizer.count_tokens_heuristic(text)
+        end
+
+      context_used =
+        case conn.body_params["session_id"] do
+          id when is_binary(id) and id != "" -> Loop.context_tokens(id)
+          _ -> nil
+        end
+
+      body =
+        Jason.encode!(%{
+          tokens: count,
+          exact: exact,
+          context_window: Application.get_env(:optimal_system_agent, :max_context_tokens, 128_000),
+          context_used: context_used
+        })
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(200, body)
+    else
+      _ -> json_error(conn, 400, "invalid_request", "Missing required field: text")
+    end
+  end
+
   # ── GET /tools ──────────────────────────────────────────────────────
 
   get "/tools" do
@@ -333,6 +442,7 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
       Commands.list_commands()
       |> Enum.map(fn {name, description, category} ->
         %{name: name, description: description, category: category}
+        |> Map.merge(Commands.command_docs(name))
       end)
 
     body = Jason.encode!(%{commands: commands, count: length(commands)})
@@ -342,6 +452,39 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # ── GET /agents ───────────────────────────────────────────────────────
+  #
+  # The agent roster, elite tier first. `model` is the model the agent's tier
+  # maps to for the default provider; `tools` are the skills it may use.
+
+  get "/agents" do
+    provider = Application.get_env(:optimal_system_agent, :default_provider, :ollama)
+    models = Map.new([:elite, :specialist, :utility], &{&1, Tier.model_for(&1, provider)})
+
+    agents =
+      Roster.all()
+      |> Map.values()
+      |> Enum.sort_by(&{tier_rank(&1[:tier]), &1[:name]})
+      |> Enum.map(fn a ->
+        %{
+          name: a[:name],
+          tier: a[:tier],
+          role: a[:role],
+          model: Map.get(models, a[:tier]),
+          description: a[:description] || "",
+          tools: a[:skills] || [],
+          triggers: a[:triggers] || [],
+          escalate_to: a[:escalate_to]
+        }
+      end)
+
+    body = Jason.encode!(%{agents: agents, count: length(agents)})
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, body)
+  end
+
   # ── POST /commands/execute ────────────────────────────────────────────
 
   post "/commands/execute" do
@@ -351,8 +494,14 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
 
       input = if arg == "", do: command, else: "#{command} #{arg}"
 
+      fields =
+        case conn.body_params["fields"] do
+          %{} = fields -> fields
+          _ -> %{}
+        end
+
       {kind, output, action} =
-        case Commands.execute(input, session_id) do
+        case Commands.execute(input, session_id, fields) do
           {:command, text} -> {"text", text, ""}
           {:prompt, text} -> {"prompt", text, ""}
           {:action, act, text} -> {"action", text, inspect(act)}
@@ -398,6 +547,83 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # ── GET /memory/entries ─────────────────────────────────────────────
+  #
+  # List memory entries, newest first, or search them with ?q=.
+  # Optional: ?category=, ?limit= (default 200).
+  #
+  # Response:
+  #   { "entries": [{ "id", "category", "timestamp", "content",
+  #                   "importance", "pinned" }], "count": 1 }
+
+  get "/memory/entries" do
+    query = conn.params["q"] || ""
+
+    opts =
+      [limit: parse_int(conn.params["limit"]) || 200]
+      |> maybe_put(:category, conn.params["category"])
+      |> Keyword.put(:sort, if(String.trim(query) == "", do: :recency, else: :relevance))
+
+    entries = Memory.search(query, opts)
+    body = Jason.encode!(%{entries: entries, count: length(entries)})
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, body)
+  end
+
+  # ── PUT /memory/entries/:id ─────────────────────────────────────────
+  #
+  # Change an entry. Any of "content", "category" and "pinned" may be
+  # given. Editing the content or category changes the entry's ID; the
+  # response carries the new one.
+
+  put "/memory/entries/:id" do
+    attrs =
+      %{}
+      |> maybe_put_attr(:content, conn.body_params["content"], &(is_binary(&1) and &1 != ""))
+      |> maybe_put_attr(:category, conn.body_params["category"], &(is_binary(&1) and &1 != ""))
+      |> maybe_put_attr(:pinned, conn.body_params["pinned"], &is_boolean/1)
+
+    if attrs == %{} do
+      json_error(conn, 400, "invalid_request", "Give at least one of: content, category, pinned")
+    else
+      case Memory.update_entry(conn.params["id"], attrs) do
+        {:ok, entry} ->
+          conn
+          |> put_resp_content_type("application/json")
+          |> send_resp(200, Jason.encode!(%{entry: entry}))
+
+        {:error, :not_found} ->
+          json_error(conn, 404, "not_found", "Memory entry not found")
+
+        {:error, :invalid_entry} ->
+          json_error(
+            conn,
+            400,
+            "invalid_request",
+            "Content may not have a line starting with \"## [\" and the category may not contain \"]\""
+          )
+      end
+    end
+  end
+
+  # ── DELETE /memory/entries/:id ──────────────────────────────────────
+
+  delete "/memory/entries/:id" do
+    entry_id = conn.params["id"]
+
+    case Memory.delete_entry(entry_id) do
+      :ok ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(%{id: entry_id, status: "deleted"}))
+
+      {:error, :not_found} ->
+        json_error(conn, 404, "not_found", "Memory entry not found")
+    end
+  end
+
   # ── GET /models ─────────────────────────────────────────────────────
 
   get "/models" do
@@ -494,6 +720,52 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     end
   end
 
+  # ── Provider keys ───────────────────────────────────────────────────
+  #
+  # Keys are stored under "api_keys" in config.json and applied at once.
+  # They are never returned, only masked. An unknown provider is a 400, so
+  # clients can tell it from a backend without these routes.
+
+  get "/providers/keys" do
+    body = Jason.encode!(%{providers: OptimalSystemAgent.Onboarding.provider_keys()})
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, body)
+  end
+
+  post "/providers/keys/:provider" do
+    key = conn.body_params["api_key"]
+
+    case is_binary(key) && String.trim(key) do
+      key when is_binary(key) and key != "" ->
+        case OptimalSystemAgent.Onboarding.set_provider_key(conn.params["provider"], key) do
+          {:ok, entry} ->
+            conn
+            |> put_resp_content_type("application/json")
+            |> send_resp(200, Jason.encode!(entry))
+
+          {:error, :unknown_provider} ->
+            json_error(conn, 400, "invalid_request", "Unknown provider: #{conn.params["provider"]}")
+        end
+
+      _ ->
+        json_error(conn, 400, "invalid_request", "api_key is required")
+    end
+  end
+
+  delete "/providers/keys/:provider" do
+    case OptimalSystemAgent.Onboarding.delete_provider_key(conn.params["provider"]) do
+      {:ok, entry} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(entry))
+
+      {:error, :unknown_provider} ->
+        json_error(conn, 400, "invalid_request", "Unknown provider: #{conn.params["provider"]}")
+    end
+  end
+
   # ── GET /machines ───────────────────────────────────────────────────
 
   get "/machines" do
@@ -510,6 +782,63 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # ── GET /git/status ─────────────────────────────────────────────────
+  #
+  # Query params: path (repository directory, default ".")
+
+  get "/git/status" do
+    path = conn.params["path"] || "."
+
+    case OptimalSystemAgent.Go.Git.git_status(path) do
+      {:ok, result} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(result))
+
+      {:error, reason} ->
+        json_error(conn, 503, "git_unavailable", to_string(reason))
+    end
+  end
+
+  # ── GET /git/diff ───────────────────────────────────────────────────
+  #
+  # Query params: path (repository directory, default ".")
+
+  get "/git/diff" do
+    path = conn.params["path"] || "."
+
+    case OptimalSystemAgent.Go.Git.git_diff(path) do
+      {:ok, result} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(result))
+
+      {:error, reason} ->
+        json_error(conn, 503, "git_unavailable", to_string(reason))
+    end
+  end
+
+  # ── GET /sidecars ───────────────────────────────────────────────────
+  #
+  # Health as last polled by Sidecar.Manager: ready, degraded, unavailable.
+
+  get "/sidecars" do
+    sidecars =
+      OptimalSystemAgent.Sidecar.Manager.status()
+      |> Enum.map(fn s ->
+        %{
+          name: s.name |> Module.split() |> Enum.drop(1) |> Enum.join("."),
+          health: to_string(s.health),
+          capabilities: Enum.map(s.capabilities, &to_string/1)
+        }
+      end)
+      |> Enum.sort_by(& &1.name)
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, Jason.encode!(%{sidecars: sidecars, count: length(sidecars)}))
+  end
+
   # ── POST /webhooks/:trigger_id ───────────────────────────────────────
   #
   # Inbound webhook receiver. Accepts any JSON payload and forwards it to
@@ -561,6 +890,57 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # ── POST /scheduler/jobs ─────────────────────────────────────────────
+  #
+  # Add a cron job. The body is a CRONS.json job object; "notify_session"
+  # names a session that receives a schedule_result event after each run.
+
+  post "/scheduler/jobs" do
+    case Scheduler.add_job(conn.body_params) do
+      {:ok, job} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(201, Jason.encode!(%{job: job}))
+
+      {:error, reason} ->
+        json_error(conn, 422, "invalid_job", to_string(reason))
+    end
+  end
+
+  # ── POST /scheduler/jobs/:id/toggle ──────────────────────────────────
+
+  post "/scheduler/jobs/:id/toggle" do
+    job_id = conn.params["id"]
+
+    with enabled when is_boolean(enabled) <- conn.body_params["enabled"],
+         :ok <- Scheduler.toggle_job(job_id, enabled) do
+      body = Jason.encode!(%{id: job_id, enabled: enabled})
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(200, body)
+    else
+      {:error, reason} -> json_error(conn, 404, "job_not_found", to_string(reason))
+      _ -> json_error(conn, 400, "invalid_request", "Missing boolean field: enabled")
+    end
+  end
+
+  # ── DELETE /scheduler/jobs/:id ───────────────────────────────────────
+
+  delete "/scheduler/jobs/:id" do
+    job_id = conn.params["id"]
+
+    case Scheduler.remove_job(job_id) do
+      :ok ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(%{id: job_id, status: "removed"}))
+
+      {:error, reason} ->
+        json_error(conn, 404, "job_not_found", to_string(reason))
+    end
+  end
+
   # ── POST /scheduler/reload ───────────────────────────────────────────
   #
   # Reload CRONS.json and TRIGGERS.json without restarting the scheduler.
@@ -871,6 +1251,38 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     end
   end
 
+  # ── Budget ────────────────────────────────────────────────────────
+
+  get "/budget" do
+    alias OptimalSystemAgent.Agent.Budget
+
+    {:ok, status} = Budget.get_status(conn.params["session_id"])
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, Jason.encode!(status))
+  end
+
+  put "/budget" do
+    alias OptimalSystemAgent.Agent.Budget
+
+    limits =
+      for key <- ~w(daily_limit monthly_limit per_call_limit session_limit),
+          Map.has_key?(conn.body_params, key),
+          into: %{},
+          do: {String.to_existing_atom(key), conn.body_params[key]}
+
+    case Budget.set_limits(limits) do
+      {:ok, status} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(status))
+
+      {:error, {:invalid_limit, key}} ->
+        json_error(conn, 400, "invalid_request", "#{key} must be a positive number")
+    end
+  end
+
   # ── Analytics ─────────────────────────────────────────────────────
 
   get "/analytics" do
@@ -903,6 +1315,175 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # Records a user's rating of an agent response, so the learning engine can
+  # tell which responses users found wrong.
+  post "/analytics/feedback" do
+    params = conn.body_params
+
+    with rating when rating in ["up", "down"] <- params["rating"],
+         session_id when is_binary(session_id) and session_id != "" <- params["session_id"] do
+      OptimalSystemAgent.Agent.Learning.feedback(%{
+        rating: String.to_existing_atom(rating),
+        session_id: session_id,
+        message_id: params["message_id"],
+        comment: feedback_text(params["comment"], 2_000),
+        excerpt: feedback_text(params["excerpt"], 500)
+      })
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(201, Jason.encode!(%{status: "recorded"}))
+    else
+      _ -> json_error(conn, 400, "invalid_request", "rating must be \"up\" or \"down\" and session_id is required")
+    end
+  end
+
+  # ── GET /hooks ──────────────────────────────────────────────────────
+  #
+  # The hook pipeline: every registered hook with its event, priority and
+  # whether it is enabled, plus the most recent blocks, newest first.
+  # Protected hooks (the guardrails) cannot be disabled.
+
+  get "/hooks" do
+    hooks =
+      Hooks.list_hooks()
+      |> Enum.flat_map(fn {event, entries} -> Enum.map(entries, &Map.put(&1, :event, event)) end)
+      |> Enum.sort_by(&{to_string(&1.event), &1.priority})
+
+    body =
+      Jason.encode!(%{
+        hooks: hooks,
+        recent_blocks: Hooks.recent_blocks()
+      })
+
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, body)
+  end
+
+  # ── PUT /hooks/:name ────────────────────────────────────────────────
+  #
+  # Body: { "enabled": false }
+
+  put "/hooks/:name" do
+    name = conn.params["name"]
+
+    case conn.body_params["enabled"] do
+      enabled when is_boolean(enabled) ->
+        case Hooks.set_enabled(name, enabled) do
+          :ok ->
+            conn
+            |> put_resp_content_type("application/json")
+            |> send_resp(200, Jason.encode!(%{name: name, enabled: enabled}))
+
+          {:error, :not_found} ->
+            json_error(conn, 404, "not_found", "Hook not found: #{name}")
+
+          {:error, :protected} ->
+            json_error(conn, 409, "conflict", "#{name} is a guardrail and cannot be disabled")
+        end
+
+      _ ->
+        json_error(conn, 400, "invalid_request", "Missing required field: enabled (boolean)")
+    end
+  end
+
+  # ── GET /mcp/servers ────────────────────────────────────────────────
+  #
+  # The servers configured in ~/.osa/mcp.json, by name, with their status
+  # and the tools each registered.
+
+  get "/mcp/servers" do
+    conn
+    |> put_resp_content_type("application/json")
+    |> send_resp(200, Jason.encode!(%{servers: MCP.list_status()}))
+  end
+
+  # ── POST /mcp/servers ───────────────────────────────────────────────
+  #
+  # Body: { "name": "github", "command": "npx", "args": [...], "env": {...} }
+  #   or: { "name": "search", "url": "https://..." }
+
+  post "/mcp/servers" do
+    params = conn.body_params
+    name = params["name"]
+
+    config =
+      case params do
+        %{"url" => url} when is_binary(url) and url != "" ->
+          {:ok, %{"url" => url}}
+
+        %{"command" => command} when is_binary(command) and command != "" ->
+          args = params["args"] || []
+          env = params["env"] || %{}
+
+          cond do
+            not (is_list(args) and Enum.all?(args, &is_binary/1) and is_map(env)) -> :error
+            env == %{} -> {:ok, %{"command" => command, "args" => args}}
+            true -> {:ok, %{"command" => command, "args" => args, "env" => env}}
+          end
+
+        _ ->
+          :error
+      end
+
+    cond do
+      not (is_binary(name) and Regex.match?(~r/^[A-Za-z0-9_.-]+$/, name)) ->
+        json_error(conn, 400, "invalid_request", "name must be letters, digits, '.', '_' or '-'")
+
+      config == :error ->
+        json_error(conn, 400, "invalid_request", "Provide command (with optional args and env) or url")
+
+      true ->
+        {:ok, config} = config
+
+        case MCP.add_server(name, config) do
+          :ok ->
+            conn
+            |> put_resp_content_type("application/json")
+            |> send_resp(201, Jason.encode!(MCP.server_status(name, config)))
+
+          {:error, reason} ->
+            json_error(conn, 500, "internal_error", reason)
+        end
+    end
+  end
+
+  # ── DELETE /mcp/servers/:name ───────────────────────────────────────
+
+  delete "/mcp/servers/:name" do
+    name = conn.params["name"]
+
+    case MCP.remove_server(name) do
+      :ok ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(%{name: name, status: "removed"}))
+
+      {:error, :not_found} ->
+        json_error(conn, 404, "not_found", "MCP server not found: #{name}")
+
+      {:error, reason} ->
+        json_error(conn, 500, "internal_error", reason)
+    end
+  end
+
+  # ── POST /mcp/servers/:name/restart ─────────────────────────────────
+
+  post "/mcp/servers/:name/restart" do
+    name = conn.params["name"]
+
+    case MCP.restart_server(name) do
+      {:ok, status} ->
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(status))
+
+      {:error, :not_found} ->
+        json_error(conn, 404, "not_found", "MCP server not found: #{name}")
+    end
+  end
+
   # ── CloudEvents ─────────────────────────────────────────────────────
 
   post "/events" do
@@ -1085,6 +1666,8 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   #   POST /channels/dingtalk/webhook     — DingTalk robot
   #   POST /channels/feishu/events        — Feishu event subscription
   #   GET  /channels                      — List all channel adapters
+  #   PUT  /channels/:name                — Save settings, enable or disable
+  #   POST /channels/:name/test           — Test the connection
 
   # ── GET /channels ────────────────────────────────────────────────────
 
@@ -1097,7 +1680,14 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
       Jason.encode!(%{
         channels:
           Enum.map(channels, fn ch ->
-            %{name: ch.name, connected: ch.connected, module: inspect(ch.module)}
+            # A channel whose settings cannot be read is listed as unset.
+            settings =
+              case Manager.channel_settings(ch.name) do
+                {:ok, settings} -> settings
+                {:error, _reason} -> %{configured: false, enabled: true, fields: []}
+              end
+
+            Map.merge(settings, %{name: ch.name, connected: ch.connected, module: inspect(ch.module)})
           end),
         count: length(channels),
         active_count: Enum.count(channels, & &1.connected)
@@ -1108,6 +1698,70 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # ── PUT /channels/:name ──────────────────────────────────────────────
+  #
+  # Body: { "fields": { "token": "..." }, "enabled": true } — either key may
+  # be left out. Secrets come back masked.
+
+  put "/channels/:name" do
+    alias OptimalSystemAgent.Channels.Manager
+
+    fields = conn.body_params["fields"] || %{}
+    enabled = conn.body_params["enabled"]
+
+    valid? =
+      is_map(fields) and Enum.all?(Map.values(fields), &is_binary/1) and
+        (is_nil(enabled) or is_boolean(enabled))
+
+    with {:ok, channel} <- resolve_channel(conn.params["name"]),
+         :ok <- if(valid?, do: :ok, else: :invalid),
+         :ok <- if(fields == %{}, do: :ok, else: Manager.configure_channel(channel, fields)),
+         :ok <- if(is_nil(enabled), do: :ok, else: Manager.set_channel_enabled(channel, enabled)) do
+      {:ok, settings} = Manager.channel_settings(channel)
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(200, Jason.encode!(settings))
+    else
+      :invalid ->
+        json_error(conn, 400, "invalid_request", "fields must map names to strings; enabled must be a boolean")
+
+      {:error, :unknown_channel} ->
+        json_error(conn, 404, "not_found", "Unknown channel: #{conn.params["name"]}")
+
+      {:error, {:unknown_fields, keys}} ->
+        json_error(conn, 400, "invalid_request", "Unknown fields: #{Enum.join(keys, ", ")}")
+
+      {:error, :not_configured} ->
+        json_error(conn, 422, "not_configured", "Saved, but the channel is missing required settings")
+
+      {:error, reason} ->
+        json_error(conn, 500, "internal_error", "Failed to start the channel: #{inspect(reason)}")
+    end
+  end
+
+  # ── POST /channels/:name/test ────────────────────────────────────────
+
+  post "/channels/:name/test" do
+    alias OptimalSystemAgent.Channels.Manager
+
+    case resolve_channel(conn.params["name"]) do
+      {:ok, channel} ->
+        body =
+          case Manager.test_channel(channel) do
+            {:ok, :connected} -> %{name: channel, ok: true, status: "connected"}
+            {:error, reason} -> %{name: channel, ok: false, status: to_string(reason)}
+          end
+
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(body))
+
+      {:error, :unknown_channel} ->
+        json_error(conn, 404, "not_found", "Unknown channel: #{conn.params["name"]}")
+    end
+  end
+
   # ── Telegram ──────────────────────────────────────────────────────────
 
   post "/channels/telegram/webhook" do
@@ -1474,16 +2128,37 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # A client whose session the backend lost, e.g. in a restart, recreates it
+  # under the same "session_id" with its latest "messages" as the agent's
+  # context. A session that is still running is left as it is.
   post "/sessions" do
     user_id = conn.assigns[:user_id] || "anonymous"
+    session_id = conn.body_params["session_id"]
+    messages = restore_messages(conn.body_params["messages"])
 
-    case OptimalSystemAgent.SDK.Session.create(user_id: user_id, channel: :http) do
-      {:ok, session_id} ->
-        body = Jason.encode!(%{id: session_id, status: "created"})
+    exists =
+      is_binary(session_id) and
+        Registry.lookup(OptimalSystemAgent.SessionRegistry, session_id) != []
 
-        conn
-        |> put_resp_content_type("application/json")
-        |> send_resp(201, body)
+    opts =
+      [user_id: user_id, channel: :http, messages: messages] ++
+        if(is_binary(session_id) and session_id != "", do: [session_id: session_id], else: [])
+
+    with :ok <- validate_restore_owner(session_id, user_id, exists),
+         {:ok, session_id} <- OptimalSystemAgent.SDK.Session.create(opts) do
+      body =
+        Jason.encode!(%{
+          id: session_id,
+          status: if(exists, do: "exists", else: "created"),
+          restored: if(exists, do: 0, else: length(messages))
+        })
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(201, body)
+    else
+      {:error, :forbidden} ->
+        json_error(conn, 403, "forbidden", "Session #{session_id} belongs to another user")
 
       {:error, reason} ->
         json_error(conn, 500, "session_create_failed", inspect(reason))
@@ -1543,6 +2218,7 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
           content: m["content"],
           timestamp: m["timestamp"]
         }
+        |> maybe_put_attr(:reply_to, get_in(m, ["metadata", "reply_to"]), &is_map/1)
       end)
 
     body = Jason.encode!(%{messages: formatted, count: length(formatted)})
@@ -1552,6 +2228,105 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     |> send_resp(200, body)
   end
 
+  # Compacts a running session's conversation now, down to target_tokens
+  # when given, to make room for a prompt that would not fit otherwise.
+  post "/sessions/:id/compact" do
+    session_id = conn.params["id"]
+    max_tokens = Application.get_env(:optimal_system_agent, :max_context_tokens, 128_000)
+
+    target =
+      case conn.body_params["target_tokens"] do
+        n when is_integer(n) and n >= 0 -> n
+        _ -> round(max_tokens * 0.5)
+      end
+
+    with :ok <- validate_session_owner(session_id, conn.assigns[:user_id]),
+         {:ok, result} <- Loop.compact(session_id, target) do
+      body =
+        Jason.encode!(%{
+          session_id: session_id,
+          tokens_before: result.tokens_before,
+          tokens_after: result.tokens_after,
+          max_tokens: max_tokens
+        })
+
+      conn
+      |> put_resp_content_type("application/json")
+      |> send_resp(200, body)
+    else
+      {:error, :busy} ->
+        json_error(conn, 409, "session_busy", "Session #{session_id} is processing a message")
+
+      {:error, _} ->
+        json_error(conn, 404, "session_not_found", "Session #{session_id} not running")
+    end
+  end
+
+  # Archives, or with "action": "delete" deletes, the sessions that are not
+  # running and match every criterion given: last active more than
+  # "older_than_days" ago, fewer than "fewer_than_messages" messages. "ids"
+  # limits it to sessions previewed before; "dry_run" only lists them.
+  post "/sessions/cleanup" do
+    params = conn.body_params
+    action = Map.get(params, "action", "archive")
+    older_than = positive_int(params["older_than_days"])
+    fewer_than = positive_int(params["fewer_than_messages"])
+    dry_run = params["dry_run"] == true
+
+    cond do
+      action not in ["archive", "delete"] ->
+        json_error(conn, 400, "invalid_request", "action must be archive or delete")
+
+      is_nil(older_than) and is_nil(fewer_than) ->
+        json_error(
+          conn,
+          400,
+          "invalid_request",
+          "Give at least one of: older_than_days, fewer_than_messages"
+        )
+
+      true ->
+        user_id = conn.assigns[:user_id] || "anonymous"
+        matched = cleanup_candidates(older_than, fewer_than, params["ids"], user_id)
+
+        done =
+          if dry_run do
+            matched
+          else
+            Enum.filter(matched, fn s ->
+              result =
+                if action == "delete",
+                  do: Memory.delete_session(s.session_id),
+                  else: Memory.archive_session(s.session_id)
+
+              result == :ok
+            end)
+          end
+
+        sessions =
+          Enum.map(done, fn s ->
+            %{
+              id: s.session_id,
+              title: s.topic_hint,
+              message_count: s.message_count,
+              last_active: s.last_active
+            }
+          end)
+
+        body =
+          Jason.encode!(%{
+            action: action,
+            dry_run: dry_run,
+            sessions: sessions,
+            count: length(sessions)
+          })
+
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, body)
+    end
+  end
+
   # ── Catch-all ───────────────────────────────────────────────────────
 
   match _ do
@@ -1628,6 +2403,85 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     end
   end
 
+  # ── Session Cleanup ─────────────────────────────────────────────────
+
+  # The persisted sessions /sessions/cleanup acts on: none that is running,
+  # and of the others those user_id owns (any, for an anonymous dev-mode
+  # request, as in validate_session_owner/2) matching every criterion given,
+  # among ids when it is a list.
+  defp cleanup_candidates(older_than, fewer_than, ids, user_id) do
+    live_ids =
+      Registry.select(OptimalSystemAgent.SessionRegistry, [{{:"$1", :_, :_}, [], [:"$1"]}])
+
+    cutoff = older_than && DateTime.add(DateTime.utc_now(), -older_than * 86_400, :second)
+
+    Memory.list_sessions()
+    |> Enum.reject(&(&1.session_id in live_ids))
+    |> Enum.filter(&(user_id == "anonymous" or &1.owner == user_id))
+    |> Enum.filter(&(not is_list(ids) or &1.session_id in ids))
+    |> Enum.filter(fn s ->
+      (is_nil(cutoff) or active_before?(s.last_active, cutoff)) and
+        (is_nil(fewer_than) or s.message_count < fewer_than)
+    end)
+  end
+
+  defp active_before?(timestamp, cutoff) when is_binary(timestamp) do
+    case DateTime.from_iso8601(timestamp) do
+      {:ok, dt, _offset} -> DateTime.compare(dt, cutoff) == :lt
+      _ -> false
+    end
+  end
+
+  defp active_before?(_, _), do: false
+
+  defp positive_int(n) when is_integer(n) and n > 0, do: n
+  defp positive_int(_), do: nil
+
+  # ── Session Restore ─────────────────────────────────────────────────
+
+  # Only the owner may restore a session: the one registered while it runs
+  # (checked by validate_session_owner/2), or once it is gone the one its
+  # persisted messages record. A session with no recorded owner, or a new
+  # id, is free to take.
+  defp validate_restore_owner(session_id, user_id, true = _running?) do
+    case validate_session_owner(session_id, user_id) do
+      :ok -> :ok
+      {:error, :not_found} -> {:error, :forbidden}
+    end
+  end
+
+  defp validate_restore_owner(session_id, user_id, false = _running?) do
+    owner = Enum.find_value(Memory.list_sessions(), &(&1.session_id == session_id and &1.owner))
+
+    if user_id == "anonymous" or owner in [nil, user_id] do
+      :ok
+    else
+      Logger.warning(
+        "[API] Session restore ownership mismatch: session=#{session_id} owner=#{inspect(owner)} requester=#{inspect(user_id)}"
+      )
+
+      {:error, :forbidden}
+    end
+  end
+
+  # The messages a restored session is seeded with: the latest user and
+  # assistant messages of the list sent.
+  @max_restore_messages 50
+
+  defp restore_messages(messages) when is_list(messages) do
+    messages
+    |> Enum.flat_map(fn
+      %{"role" => role, "content" => content} when role in ["user", "assistant"] and is_binary(content) ->
+        [%{role: role, content: content}]
+
+      _ ->
+        []
+    end)
+    |> Enum.take(-@max_restore_messages)
+  end
+
+  defp restore_messages(_), do: []
+
   # ── SSE Loop ────────────────────────────────────────────────────────
 
   defp sse_loop(conn, session_id) do
@@ -1706,6 +2560,15 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
     Map.get(@known_channels, String.downcase(name), :http)
   end
 
+  defp resolve_channel(name) do
+    alias OptimalSystemAgent.Channels.Manager
+
+    case Enum.find(Manager.known_channels(), &(to_string(&1) == name)) do
+      nil -> {:error, :unknown_channel}
+      channel -> {:ok, channel}
+    end
+  end
+
   defp generate_session_id do
     "http_" <> (:crypto.strong_rand_bytes(12) |> Base.url_encode64(padding: false))
   end
@@ -1723,6 +2586,21 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   defp unwrap_ok(data) when is_map(data), do: data
   defp unwrap_ok(_), do: %{}
 
+  # Trimmed feedback text cut to max characters, nil when blank.
+  defp feedback_text(text, max) when is_binary(text) do
+    case String.trim(text) do
+      "" -> nil
+      text -> String.slice(text, 0, max)
+    end
+  end
+
+  defp feedback_text(_, _), do: nil
+
+  # Roster agents sort elite first; SDK agents may carry any tier.
+  defp tier_rank(:elite), do: 0
+  defp tier_rank(:specialist), do: 1
+  defp tier_rank(_), do: 2
+
   # ── Swarm Helpers ────────────────────────────────────────────────────
 
   defp swarm_to_map(swarm) do
@@ -1783,9 +2661,108 @@ defmodule OptimalSystemAgent.Channels.HTTP.API do
   end
 
   # Only include in opts list when value is non-nil
+  # Per-call options for Loop.process_message from an /orchestrate body.
+  # `provider`/`model` override the session's own for this message only;
+  # `allowed_tools` (absent = all, [] = none) limits the tools it may call.
+  # `system_prompt` is added to the system prompt and `env` to the
+  # environment of the commands its tools run. `agent` names the roster agent
+  # an `@name` prompt is addressed to. The reply preferences in `metadata`
+  # (reply_language, verbosity, comment_style) go into the system prompt.
+  # `reply_to` is stored with the user message it came with.
+  defp orchestrate_opts(params) do
+    with {:ok, provider} <- parse_provider(params["provider"]),
+         {:ok, allowed_tools} <- parse_allowed_tools(params["allowed_tools"]),
+         {:ok, env} <- parse_env(params["env"]),
+         {:ok, agent} <- parse_agent(params["agent"]),
+         {:ok, reply_to} <- parse_reply_to(params["reply_to"]) do
+      {:ok,
+       [request_id: params["request_id"]]
+       |> maybe_put(:provider, provider)
+       |> maybe_put(:model, non_empty_string(params["model"]))
+       |> maybe_put(:allowed_tools, allowed_tools)
+       |> maybe_put(:system_prompt, non_empty_string(params["system_prompt"]))
+       |> maybe_put(:env, env)
+       |> maybe_put(:agent, agent)
+       |> maybe_put(:preferences, reply_preferences(params["metadata"]))
+       |> maybe_put(:reply_to, reply_to)}
+    end
+  end
+
+  defp parse_provider(name) when name in [nil, ""], do: {:ok, nil}
+
+  defp parse_provider(name) when is_binary(name) do
+    case Enum.find(Providers.Registry.list_providers(), &(Atom.to_string(&1) == name)) do
+      nil -> {:error, "Unknown provider: #{name}"}
+      provider -> {:ok, provider}
+    end
+  end
+
+  defp parse_provider(_), do: {:error, "provider must be a string"}
+
+  defp parse_allowed_tools(nil), do: {:ok, nil}
+
+  defp parse_allowed_tools(names) when is_list(names) do
+    if Enum.all?(names, &is_binary/1),
+      do: {:ok, names},
+      else: {:error, "allowed_tools must be a list of tool names"}
+  end
+
+  defp parse_allowed_tools(_), do: {:error, "allowed_tools must be a list of tool names"}
+
+  defp parse_agent(name) when name in [nil, ""], do: {:ok, nil}
+
+  defp parse_agent(name) when is_binary(name) do
+    case Roster.get(name) do
+      nil -> {:error, "Unknown agent: #{name}"}
+      agent -> {:ok, agent}
+    end
+  end
+
+  defp parse_agent(_), do: {:error, "agent must be a string"}
+
+  defp parse_reply_to(nil), do: {:ok, nil}
+
+  defp parse_reply_to(%{"message_id" => id} = ref) when is_binary(id) do
+    {:ok, Map.take(ref, ~w(message_id role timestamp excerpt))}
+  end
+
+  defp parse_reply_to(_), do: {:error, "reply_to must be an object with a message_id"}
+
+  @reply_preference_keys ~w(reply_language verbosity comment_style)
+
+  defp reply_preferences(metadata) when is_map(metadata) do
+    prefs =
+      metadata
+      |> Map.take(@reply_preference_keys)
+      |> Map.filter(fn {_k, v} -> is_binary(v) and v != "" end)
+
+    if prefs == %{}, do: nil, else: prefs
+  end
+
+  defp reply_preferences(_), do: nil
+
+  defp parse_env(nil), do: {:ok, nil}
+  defp parse_env(env) when env == %{}, do: {:ok, nil}
+
+  defp parse_env(env) when is_map(env) do
+    if Enum.all?(env, fn {k, v} -> k =~ ~r/^[A-Za-z_][A-Za-z0-9_]*$/ and is_binary(v) end),
+      do: {:ok, env},
+      else: {:error, "env must map variable names to strings"}
+  end
+
+  defp parse_env(_), do: {:error, "env must map variable names to strings"}
+
+  defp non_empty_string(s) when is_binary(s) and s != "", do: s
+  defp non_empty_string(_), do: nil
+
   defp maybe_put(opts, _key, nil), do: opts
   defp maybe_put(opts, key, value), do: Keyword.put(opts, key, value)
 
+  # Puts value under key in a map when valid?.(value) holds.
+  defp maybe_put_attr(map, key, value, valid?) do
+    if valid?.(value), do: Map.put(map, key, value), else: map
+  end
+
   # ── Webhook Signature Verification Helpers ───────────────────────────
   #
   # Each helper returns:
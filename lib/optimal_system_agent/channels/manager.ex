@@ -21,13 +21,17 @@ defmodule OptimalSystemAgent.Channels.Manager do
 
   ## Channel registration
   All adapters are registered in `@channel_modules` below. Add new adapters there.
+
+  ## Settings
+  Each channel's credentials live under `"channels"` in `~/.osa/config.json`,
+  with the fields listed in `@channel_fields`, and `"enabled": false` keeps a
+  configured channel from starting. Saved values are applied on boot unless
+  the environment already sets them.
   """
   require Logger
 
   alias OptimalSystemAgent.Events.Bus
 
-  @osa_dir Path.expand("~/.osa")
-
   @channel_modules [
     OptimalSystemAgent.Channels.Telegram,
     OptimalSystemAgent.Channels.Discord,
@@ -41,41 +45,111 @@ defmodule OptimalSystemAgent.Channels.Manager do
     OptimalSystemAgent.Channels.Feishu
   ]
 
+  # Settings fields per channel: {key in config.json, application env key,
+  # whether the value is a secret}.
+  @channel_fields %{
+    telegram: [{"token", :telegram_bot_token, true}],
+    discord: [
+      {"token", :discord_bot_token, true},
+      {"application_id", :discord_application_id, false},
+      {"public_key", :discord_public_key, false}
+    ],
+    slack: [
+      {"token", :slack_bot_token, true},
+      {"signing_secret", :slack_signing_secret, true}
+    ],
+    whatsapp: [
+      {"token", :whatsapp_token, true},
+      {"phone_number_id", :whatsapp_phone_number_id, false},
+      {"verify_token", :whatsapp_verify_token, true}
+    ],
+    signal: [
+      {"api_url", :signal_api_url, false},
+      {"phone_number", :signal_phone_number, false}
+    ],
+    matrix: [
+      {"homeserver", :matrix_homeserver, false},
+      {"access_token", :matrix_access_token, true},
+      {"user_id", :matrix_user_id, false}
+    ],
+    email: [
+      {"from", :email_from, false},
+      {"api_key", :email_api_key, true}
+    ],
+    qq: [
+      {"app_id", :qq_app_id, false},
+      {"app_secret", :qq_app_secret, true},
+      {"token", :qq_token, true}
+    ],
+    dingtalk: [
+      {"access_token", :dingtalk_access_token, true},
+      {"secret", :dingtalk_secret, true}
+    ],
+    feishu: [
+      {"app_id", :feishu_app_id, false},
+      {"app_secret", :feishu_app_secret, true},
+      {"encrypt_key", :feishu_encrypt_key, true}
+    ]
+  }
+
+  # The fields an adapter cannot start without: its init/1 returns :ignore
+  # while they are unset.
+  @required_fields %{
+    telegram: ["token"],
+    discord: ["token"],
+    slack: ["token"],
+    whatsapp: ["token"],
+    signal: ["api_url"],
+    matrix: ["access_token"],
+    email: ["from"],
+    qq: ["app_id"],
+    dingtalk: ["access_token"],
+    feishu: ["app_id"]
+  }
+
   @doc """
   Start all channel adapters that have their required configuration present.
 
   Each adapter's `init/1` returns `:ignore` when its token/config is absent,
   so it's safe to attempt starting all of them — only configured ones will run.
+  Channels saved with `"enabled": false` are skipped.
 
   Returns a list of `{module, result}` tuples.
   """
   def start_configured_channels do
     Logger.info("Channels.Manager: Starting configured channel adapters...")
+    saved = apply_saved_config()
 
     results =
       Enum.map(@channel_modules, fn module ->
+        enabled = get_in(saved, [to_string(safe_channel_name(module)), "enabled"]) != false
+
         result =
-          case DynamicSupervisor.start_child(
-                 OptimalSystemAgent.Channels.Supervisor,
-                 {module, []}
-               ) do
-            {:ok, pid} ->
-              Logger.info("Channels.Manager: Started #{inspect(module)} (pid=#{inspect(pid)})")
-              {:ok, pid}
-
-            {:error, {:already_started, pid}} ->
-              {:ok, pid}
-
-            :ignore ->
-              # Adapter returned :ignore — not configured, skip silently
-              :ignore
-
-            {:error, reason} ->
-              Logger.warning(
-                "Channels.Manager: Failed to start #{inspect(module)}: #{inspect(reason)}"
-              )
-
-              {:error, reason}
+          if not enabled do
+            :ignore
+          else
+            case DynamicSupervisor.start_child(
+                   OptimalSystemAgent.Channels.Supervisor,
+                   {module, []}
+                 ) do
+              {:ok, pid} ->
+                Logger.info("Channels.Manager: Started #{inspect(module)} (pid=#{inspect(pid)})")
+                {:ok, pid}
+
+              {:error, {:already_started, pid}} ->
+                {:ok, pid}
+
+              :ignore ->
+                # Adapter returned :ignore — not configured, skip silently
+                :ignore
+
+              {:error, reason} ->
+                Logger.warning(
+                  "Channels.Manager: Failed to start #{inspect(module)}: #{inspect(reason)}"
+                )
+
+                {:error, reason}
+            end
           end
 
         {module, result}
@@ -287,8 +361,185 @@ defmodule OptimalSystemAgent.Channels.Manager do
     end
   end
 
+  @doc """
+  Settings of a channel for display: whether it is connected, configured
+  and enabled, and each field with whether it is set. Secret values are
+  masked down to their last four characters.
+
+  Returns `{:error, :invalid_config}` when the channel's entry in
+  config.json is not an object.
+  """
+  def channel_settings(channel) when is_atom(channel) do
+    case {find_module(channel), saved_channel_config(channel)} do
+      {nil, _saved} ->
+        {:error, :unknown_channel}
+
+      {_module, saved} when not is_map(saved) ->
+        {:error, :invalid_config}
+
+      {module, saved} ->
+        fields =
+          Enum.map(Map.get(@channel_fields, channel, []), fn {key, env_key, secret} ->
+            value = field_value(saved, key, env_key)
+
+            %{
+              key: key,
+              secret: secret,
+              set: value != nil,
+              value: if(secret, do: mask(value), else: value)
+            }
+          end)
+
+        {:ok,
+         %{
+           name: channel,
+           connected: pid_connected?(module, Process.whereis(module)),
+           configured: channel_configured?(channel),
+           enabled: Map.get(saved, "enabled") != false,
+           fields: fields
+         }}
+    end
+  end
+
+  @doc """
+  Save settings for a channel. `values` maps field keys to new values; an
+  empty string clears a field and fields not given are kept. A channel that
+  is running is restarted so the new values take effect.
+  Returns `:ok` or `{:error, reason}`.
+  """
+  def configure_channel(channel, values) when is_atom(channel) and is_map(values) do
+    known = Map.get(@channel_fields, channel, [])
+    unknown = Map.keys(values) -- Enum.map(known, &elem(&1, 0))
+
+    cond do
+      find_module(channel) == nil ->
+        {:error, :unknown_channel}
+
+      unknown != [] ->
+        {:error, {:unknown_fields, unknown}}
+
+      true ->
+        update_channel_config(channel, fn saved ->
+          Enum.reduce(values, saved, fn
+            {key, ""}, acc -> Map.delete(acc, key)
+            {key, value}, acc -> Map.put(acc, key, value)
+          end)
+        end)
+
+        Enum.each(known, fn {key, env_key, _secret} ->
+          case Map.fetch(values, key) do
+            {:ok, ""} -> Application.delete_env(:optimal_system_agent, env_key)
+            {:ok, value} -> Application.put_env(:optimal_system_agent, env_key, value)
+            :error -> :ok
+          end
+        end)
+
+        if channel_active?(channel) do
+          stop_channel(channel)
+          start_channel(channel)
+        end
+
+        :ok
+    end
+  end
+
+  @doc """
+  Enable or disable a channel. Enabling starts it; disabling stops it and
+  keeps it from starting on boot. Returns `:ok` or `{:error, reason}`, where
+  the error is from starting the adapter.
+  """
+  def set_channel_enabled(channel, enabled) when is_atom(channel) and is_boolean(enabled) do
+    if find_module(channel) == nil do
+      {:error, :unknown_channel}
+    else
+      update_channel_config(channel, &Map.put(&1, "enabled", enabled))
+
+      cond do
+        enabled ->
+          case start_channel(channel) do
+            {:ok, _pid} -> :ok
+            error -> error
+          end
+
+        Process.whereis(find_module(channel)) != nil ->
+          stop_channel(channel)
+
+        true ->
+          :ok
+      end
+    end
+  end
+
   # ── Private Helpers ──────────────────────────────────────────────────
 
+  # Puts saved channel settings into the application env where it has no
+  # value yet, and returns the saved "channels" map.
+  defp apply_saved_config do
+    saved = Map.get(read_config(), "channels", %{})
+
+    Enum.each(@channel_fields, fn {channel, fields} ->
+      config = Map.get(saved, to_string(channel), %{})
+
+      Enum.each(fields, fn {key, env_key, _secret} ->
+        value = Map.get(config, key)
+
+        if is_binary(value) and value != "" and
+             Application.get_env(:optimal_system_agent, env_key) == nil do
+          Application.put_env(:optimal_system_agent, env_key, value)
+        end
+      end)
+    end)
+
+    saved
+  end
+
+  defp field_value(saved, key, env_key) do
+    case Map.get(saved, key) do
+      value when is_binary(value) and value != "" ->
+        value
+
+      _ ->
+        case Application.get_env(:optimal_system_agent, env_key) do
+          value when is_binary(value) and value != "" -> value
+          _ -> nil
+        end
+    end
+  end
+
+  defp mask(nil), do: nil
+  defp mask(value) when byte_size(value) <= 8, do: "••••"
+  defp mask(value), do: "••••" <> String.slice(value, -4, 4)
+
+  defp read_config do
+    with {:ok, contents} <- File.read(Path.join(config_dir(), "config.json")),
+         {:ok, %{} = config} <- Jason.decode(contents) do
+      config
+    else
+      _ -> %{}
+    end
+  end
+
+  # The channel's entry in config.json as saved, %{} when it has none.
+  defp saved_channel_config(channel) do
+    case read_config() do
+      %{"channels" => %{} = channels} -> Map.get(channels, to_string(channel)) || %{}
+      _ -> %{}
+    end
+  end
+
+  defp update_channel_config(channel, fun) do
+    config = read_config()
+    channels = Map.get(config, "channels", %{})
+    updated = Map.update(channels, to_string(channel), fun.(%{}), fun)
+
+    File.mkdir_p!(config_dir())
+
+    File.write!(
+      Path.join(config_dir(), "config.json"),
+      Jason.encode!(Map.put(config, "channels", updated), pretty: true)
+    )
+  end
+
   defp find_module(channel) when is_atom(channel) do
     Enum.find(@channel_modules, fn mod ->
       safe_channel_name(mod) == channel or mod == channel
@@ -315,32 +566,18 @@ defmodule OptimalSystemAgent.Channels.Manager do
 
   defp pid_connected?(_module, nil), do: false
 
+  # Whether the required fields of channel are set, in config.json or the
+  # application env, so that starting it will not return :not_configured.
   defp channel_configured?(channel) do
-    config_path = Path.join(@osa_dir, "config.json")
-
-    if File.exists?(config_path) do
-      with {:ok, contents} <- File.read(config_path),
-           {:ok, config} <- Jason.decode(contents) do
-        channels = Map.get(config, "channels", %{})
-        Map.has_key?(channels, to_string(channel))
-      else
-        _ -> false
-      end
-    else
-      # Fall back to checking Application env for known config keys
-      case channel do
-        :telegram -> Application.get_env(:optimal_system_agent, :telegram_token) != nil
-        :discord -> Application.get_env(:optimal_system_agent, :discord_token) != nil
-        :slack -> Application.get_env(:optimal_system_agent, :slack_bot_token) != nil
-        :whatsapp -> Application.get_env(:optimal_system_agent, :whatsapp_token) != nil
-        :signal -> Application.get_env(:optimal_system_agent, :signal_phone) != nil
-        :matrix -> Application.get_env(:optimal_system_agent, :matrix_homeserver) != nil
-        :email -> Application.get_env(:optimal_system_agent, :email_smtp_host) != nil
-        :qq -> Application.get_env(:optimal_system_agent, :qq_appid) != nil
-        :dingtalk -> Application.get_env(:optimal_system_agent, :dingtalk_app_key) != nil
-        :feishu -> Application.get_env(:optimal_system_agent, :feishu_app_id) != nil
-        _ -> false
-      end
-    end
+    saved = saved_channel_config(channel)
+    required = Map.get(@required_fields, channel, [])
+
+    is_map(saved) and required != [] and
+      @channel_fields
+      |> Map.get(channel, [])
+      |> Enum.filter(fn {key, _env_key, _secret} -> key in required end)
+      |> Enum.all?(fn {key, env_key, _secret} -> field_value(saved, key, env_key) != nil end)
   end
+
+  defp config_dir, do: Application.get_env(:optimal_system_agent, :config_dir, "~/.osa") |> Path.expand()
 end
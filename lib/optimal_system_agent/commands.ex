@@ -70,19 +70,23 @@ defmodule OptimalSystemAgent.Commands do
     - `{:prompt, expanded_text}` — send expanded text to agent loop
     - `{:action, action, output}` — CLI takes action + displays output
     - `:unknown` — command not found
+
+  `fields` are the values of a form the command declares (see
+  `command_docs/1`), by field name, for clients that asked for them.
   """
-  @spec execute(String.t(), String.t()) ::
+  @spec execute(String.t(), String.t(), map()) ::
           {:command, String.t()}
           | {:prompt, String.t()}
           | {:action, atom() | tuple(), String.t()}
           | :unknown
-  def execute(input, session_id) do
+  def execute(input, session_id, fields \\ %{}) do
     [cmd | args] = String.split(input, ~r/\s+/, parts: 2)
     cmd = String.downcase(cmd)
     arg = List.first(args) || ""
 
     # Store command name so handlers can identify which command was invoked
     Process.put(:osa_current_cmd, cmd)
+    Process.put(:osa_command_fields, fields)
 
     case lookup(cmd) do
       {:builtin, handler} ->
@@ -119,6 +123,146 @@ defmodule OptimalSystemAgent.Commands do
     builtins ++ customs
   end
 
+  @doc """
+  Usage metadata of a command, for clients that document commands as they
+  are typed: `:usage` (the arguments it takes), `:example` (an invocation)
+  and `:details` (a longer description). Keys without metadata are absent.
+
+  `:forms` lists the forms a client can ask the command's values in instead,
+  each for the argument it is run with (`:arg`), with its `:fields`: a
+  `:name` the value is passed to `execute/3` under, a `:label`, a `:hint`,
+  whether it is `:required` or `:secret`, and a `:pattern` it must match.
+  """
+  @spec command_docs(String.t()) :: map()
+  def command_docs(name) do
+    docs =
+      case Map.get(command_metadata(), name) do
+        {usage, example, details} ->
+          %{usage: usage, example: example, details: details}
+          |> Map.reject(fn {_k, v} -> is_nil(v) end)
+
+        nil ->
+          %{}
+      end
+
+    case Map.get(command_forms(), name) do
+      nil -> docs
+      forms -> Map.put(docs, :forms, forms)
+    end
+  end
+
+  # Forms per built-in command taking several values.
+  defp command_forms do
+    %{
+      "create-command" => [
+        %{
+          arg: "",
+          title: "Create a command",
+          fields: [
+            %{
+              name: "name",
+              label: "Name",
+              required: true,
+              pattern: "^[a-z][a-z0-9_-]*$",
+              hint: "lowercase letters, digits, - and _, e.g. standup"
+            },
+            %{name: "description", label: "Description", required: true},
+            %{
+              name: "template",
+              label: "Template",
+              required: true,
+              hint: "the prompt sent when the command runs"
+            }
+          ]
+        }
+      ],
+      "cron" => [
+        %{
+          arg: "add",
+          title: "Add a cron job",
+          fields: [
+            %{name: "name", label: "Name", required: true},
+            %{
+              name: "schedule",
+              label: "Schedule",
+              required: true,
+              hint: "cron expression, e.g. 0 9 * * 1-5"
+            },
+            %{name: "job", label: "Task", required: true, hint: "what the agent does on each run"}
+          ]
+        }
+      ]
+    }
+  end
+
+  # The value of a form field passed to execute/3, or nil when it is blank.
+  defp command_field(name) do
+    case Process.get(:osa_command_fields, %{}) do
+      %{^name => value} when is_binary(value) ->
+        if String.trim(value) == "", do: nil, else: String.trim(value)
+
+      _ ->
+        nil
+    end
+  end
+
+  # {usage, example, details} per built-in command taking arguments.
+  defp command_metadata do
+    %{
+      "model" =>
+        {"/model [list | <provider> [<model>] | ollama-url <url>]", "/model ollama qwen3:32b",
+         "Without arguments shows the active provider and model. `list` shows every provider with its status; a provider name switches to it, optionally with a model."},
+      "resume" =>
+        {"/resume <id>", "/resume 7f3a2c", "Loads a stored session into this one. /sessions lists the IDs."},
+      "history" =>
+        {"/history [<id> | search <query>]", "/history search websocket",
+         "Without arguments lists recent sessions; an ID shows a session's messages and `search` looks through all of them."},
+      "channels" =>
+        {"/channels [connect | disconnect | status | test] <name>", "/channels connect slack",
+         "Without arguments lists the channel adapters and whether they are running."},
+      "whatsapp" =>
+        {"/whatsapp [connect | disconnect | test]", "/whatsapp connect",
+         "`connect` pairs WhatsApp Web by QR code; `disconnect` logs out and stops the adapter."},
+      "think" =>
+        {"/think fast|normal|deep", "/think deep",
+         "Sets the reasoning depth of this session. Without an argument shows the current one."},
+      "agents" =>
+        {"/agents [<name>]", "/agents backend-go", "Without arguments lists the roster; a name shows that agent's role, tier and skills."},
+      "tier" =>
+        {"/tier <elite|specialist|utility> <model> | clear <tier>", "/tier utility qwen3:8b",
+         "Overrides the model used for a tier until cleared."},
+      "export" =>
+        {"/export [<file>]", "/export review-notes.md", "Writes the session as markdown, to osa_session_<timestamp>.md unless a file is given."},
+      "cron" =>
+        {"/cron [add | run <id> | enable <id> | disable <id> | remove <id>]", "/cron run nightly-report",
+         "Without arguments lists the cron jobs."},
+      "triggers" =>
+        {"/triggers [add | remove <id>]", "/triggers remove on-push", "Without arguments lists the event triggers."},
+      "heartbeat" =>
+        {"/heartbeat [add <task>]", "/heartbeat add Check the CI dashboard", "Without arguments shows the heartbeat tasks and their next run."},
+      "create-command" =>
+        {"/create-command <name> | <description> | <template>", "/create-command standup | Daily standup | Summarize my recent activity",
+         "Registers a custom command whose template is sent as the prompt when it runs."},
+      "tasks" => {"/tasks [add \"<title>\"]", "/tasks add \"Write migration\"", "Without arguments shows the tasks tracked in this session."},
+      "logs" => {"/logs [<lines>]", "/logs 50", "Shows the last lines of the backend log, 20 by default."},
+      "completion" => {"/completion bash|zsh|fish", "/completion zsh", "Prints a shell completion script for the CLI."},
+      "docs" => {"/docs [<topic>]", "/docs swarms", "Without a topic lists the documentation topics."},
+      "mem-search" => {"/mem-search <query>", "/mem-search auth refactor", nil},
+      "mem-save" => {"/mem-save <text>", "/mem-save Deploys go through the staging cluster first", nil},
+      "mem-recall" => {"/mem-recall <topic>", "/mem-recall database", nil},
+      "mem-delete" => {"/mem-delete <entry>", nil, nil},
+      "explain" => {"/explain <code or concept>", "/explain lib/optimal_system_agent/commands.ex", nil},
+      "fix" => {"/fix [<review notes>]", nil, nil},
+      "search" => {"/search <query>", "/search rate limiter", nil},
+      "pr-review" => {"/pr-review <pr>", "/pr-review 42", nil},
+      "debug" => {"/debug <symptom>", "/debug login returns 500 after token refresh", nil},
+      "refactor" => {"/refactor <target>", nil, nil},
+      "security-scan" => {"/security-scan [<target>]", "/security-scan lib/", nil},
+      "secret-scan" => {"/secret-scan [<target>]", nil, nil},
+      "harden" => {"/harden [<target>]", nil, nil}
+    }
+  end
+
   @doc false
   defp category_for(name) do
     case name do
@@ -1769,9 +1913,14 @@ defmodule OptimalSystemAgent.Commands do
 
   # ── Budget Command ──────────────────────────────────────────────
 
-  defp cmd_budget(_arg, _session_id) do
+  defp cmd_budget(_arg, session_id) do
     try do
-      {:ok, status} = OptimalSystemAgent.Agent.Budget.get_status()
+      {:ok, status} = OptimalSystemAgent.Agent.Budget.get_status(session_id)
+
+      session =
+        if status.session_limit,
+          do: "\n\nSession:\n  Spent:     $#{Float.round(Map.get(status, :session_spent, 0.0), 4)}\n  Limit:     $#{Float.round(status.session_limit, 2)}",
+          else: ""
 
       daily_pct =
         if status.daily_limit > 0,
@@ -1795,7 +1944,7 @@ defmodule OptimalSystemAgent.Commands do
         Monthly:
           Spent:     $#{Float.round(status.monthly_spent, 4)}
           Limit:     $#{Float.round(status.monthly_limit, 2)}
-          Remaining: $#{Float.round(status.monthly_remaining, 4)} (#{monthly_pct}% used)
+          Remaining: $#{Float.round(status.monthly_remaining, 4)} (#{monthly_pct}% used)#{session}
 
         Per-call limit: $#{Float.round(status.per_call_limit, 2)}
         Ledger entries: #{status.ledger_entries}
@@ -2141,6 +2290,22 @@ defmodule OptimalSystemAgent.Commands do
           {:command, header <> lines <> footer}
         end
 
+      trimmed == "add" and command_field("schedule") != nil ->
+        job = %{
+          "name" => command_field("name"),
+          "schedule" => command_field("schedule"),
+          "type" => "agent",
+          "job" => command_field("job")
+        }
+
+        case Scheduler.add_job(job) do
+          {:ok, job} ->
+            {:command, "Added cron job #{job["id"]}: #{job["name"]} (#{job["schedule"]})"}
+
+          {:error, reason} ->
+            {:command, "Failed: #{reason}"}
+        end
+
       trimmed == "add" ->
         {:prompt,
          "Create a new cron job. Provide: name, schedule (cron expression), type (agent/command/webhook), and the task/command/url."}
@@ -2648,8 +2813,18 @@ defmodule OptimalSystemAgent.Commands do
   end
 
   defp cmd_create(arg, _session_id) do
+    args =
+      case command_field("name") do
+        nil ->
+          parse_create_args(arg)
+
+        name ->
+          {:ok, name, command_field("description") || "Custom command",
+           command_field("template") || ""}
+      end
+
     result =
-      case parse_create_args(arg) do
+      case args do
         {:ok, name, description, template} ->
           case register(name, description, template) do
             :ok -> "Created command /#{name} — try it out!"
@@ -2,11 +2,31 @@ defmodule OptimalSystemAgent.Go.Git do
   @moduledoc """
   GenServer wrapping the Go osa-git sidecar for repository introspection.
 
-  Exposes git_status, git_diff, git_log, and git_blame over the shared
-  JSON-RPC stdio protocol. Returns `{:error, :sidecar_unavailable}` when
+  Exposes git_status, git_diff, git_log, git_blame, file history
+  (git_file_log, git_show), commit search (git_search), branch management
+  (git_branch_list, git_branch_create, git_checkout, git_branch_delete),
+  tags (git_tag_list, git_tag_create, git_describe), remote operations (git_fetch, git_pull, git_push),
+  submodules (git_submodules, git_submodule_update), conflict resolution
+  (git_conflicts, git_resolve), ignore rules and tracked files
+  (git_check_ignore, git_ls_files), patches (git_apply) and a summary of
+  the repository (git_info) over the shared JSON-RPC stdio protocol. Returns `{:error, :sidecar_unavailable}` when
   the binary is missing — there is no meaningful in-process fallback for
   git operations.
 
+  Remote operations that fail for a known reason, such as rejected
+  credentials, return `{:error, {:remote, data}}` where `data` has the
+  `"kind"` (e.g. `"auth_failed"`, `"permission_denied"`,
+  `"non_fast_forward"`), `"remote"`, `"url"` and `"message"`.
+
+  Results too large for one line, such as big diffs and logs, arrive in
+  chunks (see `OptimalSystemAgent.Sidecar.Protocol`) and are joined before
+  they are returned.
+
+  The sidecar handles several requests at once, so a slow blame does not
+  hold up a status. A request that times out, or whose caller exits before
+  it is answered, is cancelled in the sidecar too, which stops log walks,
+  searches and remote operations early.
+
   Binary search order:
     1. priv/go/git/osa-git  (in-tree, dev)
     2. ~/.osa/bin/osa-git   (installed)
@@ -21,6 +41,12 @@ defmodule OptimalSystemAgent.Go.Git do
   # Git operations can involve large diffs, so give the sidecar more time.
   @request_timeout 5_000
 
+  # The sidecar gives up on a remote after two minutes.
+  @remote_timeout 125_000
+
+  # A pickaxe search reads every file each commit changed.
+  @search_timeout 30_000
+
   # -- Client API --
 
   def start_link(opts \\ []) do
@@ -31,7 +57,7 @@ defmodule OptimalSystemAgent.Go.Git do
 
   @impl OptimalSystemAgent.Sidecar.Behaviour
   def call(method, params, timeout \\ @request_timeout) do
-    GenServer.call(__MODULE__, {:request, method, params}, timeout + 500)
+    GenServer.call(__MODULE__, {:request, method, params, timeout}, timeout + 500)
   catch
     :exit, _ -> {:error, :timeout}
   end
@@ -52,10 +78,38 @@ defmodule OptimalSystemAgent.Go.Git do
   end
 
   @impl OptimalSystemAgent.Sidecar.Behaviour
-  def capabilities, do: [:git_status, :git_diff, :git_log, :git_blame]
+  def capabilities,
+    do: [
+      :git_status,
+      :git_diff,
+      :git_log,
+      :git_blame,
+      :git_file_log,
+      :git_show,
+      :git_search,
+      :git_branch_list,
+      :git_branch_create,
+      :git_checkout,
+      :git_branch_delete,
+      :git_tag_list,
+      :git_tag_create,
+      :git_describe,
+      :git_fetch,
+      :git_pull,
+      :git_push,
+      :git_submodules,
+      :git_submodule_update,
+      :git_conflicts,
+      :git_resolve,
+      :git_check_ignore,
+      :git_ls_files,
+      :git_apply,
+      :git_info
+    ]
 
   @doc """
   Return status of all changed files, current branch, and cleanliness.
+  Files that are submodules have `"submodule" => true`.
 
       {:ok, %{"files" => [...], "branch" => "main", "clean" => false}}
   """
@@ -94,6 +148,418 @@ defmodule OptimalSystemAgent.Go.Git do
     call("git_blame", %{"path" => path, "file" => file})
   end
 
+  @doc """
+  Return the commits that changed `file`, newest first, with how each
+  changed it.
+
+      {:ok, %{"file" => "lib/a.ex", "commits" => [%{"hash" => "abc", "author" => "Name",
+        "message" => "...", "date" => "...", "file" => "lib/a.ex", "change" => "renamed",
+        "old_file" => "lib/old.ex"}]}}
+
+  Options:
+    - `:follow` — continue through renames, listing older commits under the
+      file's old name
+    - `:limit` — default 10
+    - `:rev` — the revision to walk back from, default HEAD
+  """
+  @spec git_file_log(String.t(), String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_file_log(path \\ ".", file, opts \\ []) do
+    call("git_file_log", %{
+      "path" => path,
+      "file" => file,
+      "follow" => Keyword.get(opts, :follow, false),
+      "limit" => Keyword.get(opts, :limit, 10),
+      "rev" => Keyword.get(opts, :rev, "")
+    })
+  end
+
+  @doc """
+  Return a commit, given as a hash or any revision such as `"HEAD~2"`, with
+  its patch against the first parent and the files it changed. A patch over
+  `max_bytes` (default 256 KiB) is cut at a line with `"truncated"` set.
+
+      {:ok, %{"hash" => "abc", "parents" => ["def"], "author" => "Name",
+        "author_email" => "...", "author_date" => "...", "committer" => "Name",
+        "committer_email" => "...", "commit_date" => "...", "message" => "...",
+        "files" => [%{"file" => "lib/a.ex", "change" => "modified", "additions" => 3,
+        "deletions" => 1}], "patch" => "diff --git ...", "truncated" => false}}
+  """
+  @spec git_show(String.t(), String.t(), pos_integer() | nil) :: {:ok, map()} | {:error, atom()}
+  def git_show(path \\ ".", rev, max_bytes \\ nil) do
+    call("git_show", %{"path" => path, "rev" => rev, "max_bytes" => max_bytes || 0})
+  end
+
+  @doc """
+  Search the history for commits, newest first, by message, author or the
+  code they changed. What is given must all match; `"more"` is set when
+  there are matches past those returned.
+
+      {:ok, %{"commits" => [%{"hash" => "abc", "author" => "Name", "author_email" => "...",
+        "message" => "...", "date" => "...", "files" => ["lib/a.ex"]}], "more" => false}}
+
+  Options:
+    - `:query` — text in the commit message, ignoring case
+    - `:author` — text in the author's name or email, ignoring case
+    - `:pickaxe` — a string the commit added or removed, like `git log -S`;
+      `"files"` lists where
+    - `:rev` — the revision to search back from, default HEAD
+    - `:limit` — default 20
+    - `:offset` — matching commits to skip, for the next page
+  """
+  @spec git_search(String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_search(path \\ ".", opts) do
+    call(
+      "git_search",
+      %{
+        "path" => path,
+        "query" => Keyword.get(opts, :query, ""),
+        "author" => Keyword.get(opts, :author, ""),
+        "pickaxe" => Keyword.get(opts, :pickaxe, ""),
+        "rev" => Keyword.get(opts, :rev, ""),
+        "limit" => Keyword.get(opts, :limit, 20),
+        "offset" => Keyword.get(opts, :offset, 0)
+      },
+      @search_timeout
+    )
+  end
+
+  @doc """
+  Return the local branches, marking the checked out one, with the branch
+  each tracks and how many commits each side has that the other lacks.
+
+      {:ok, %{"current" => "main", "branches" => [%{"name" => "main", "hash" => "abc",
+        "current" => true, "upstream" => "origin/main", "ahead" => 1, "behind" => 0}]}}
+  """
+  @spec git_branch_list(String.t()) :: {:ok, map()} | {:error, atom()}
+  def git_branch_list(path \\ ".") do
+    call("git_branch_list", %{"path" => path})
+  end
+
+  @doc """
+  Create branch `name` at `start` (HEAD when nil) without checking it out.
+  A branch started from a remote branch such as `"origin/main"` tracks it.
+  Returns the branch as listed by `git_branch_list/1`.
+  """
+  @spec git_branch_create(String.t(), String.t(), String.t() | nil) :: {:ok, map()} | {:error, atom()}
+  def git_branch_create(path \\ ".", name, start \\ nil) do
+    call("git_branch_create", %{"path" => path, "name" => name, "start" => start || ""})
+  end
+
+  @doc """
+  Check out branch `name`. A branch that only exists on one remote is
+  created to track it.
+
+  Options:
+    - `:create` — create a missing branch, at `:start` (default HEAD)
+    - `:force` — discard local changes instead of refusing to switch
+  """
+  @spec git_checkout(String.t(), String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_checkout(path \\ ".", name, opts \\ []) do
+    call("git_checkout", %{
+      "path" => path,
+      "name" => name,
+      "create" => Keyword.get(opts, :create, false),
+      "start" => Keyword.get(opts, :start, ""),
+      "force" => Keyword.get(opts, :force, false)
+    })
+  end
+
+  @doc """
+  Delete branch `name`. The checked out branch cannot be deleted, and one
+  not merged into HEAD only with `force: true`. Returns the hash it pointed at.
+
+      {:ok, %{"name" => "topic", "hash" => "abc"}}
+  """
+  @spec git_branch_delete(String.t(), String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_branch_delete(path \\ ".", name, opts \\ []) do
+    call("git_branch_delete", %{"path" => path, "name" => name, "force" => Keyword.get(opts, :force, false)})
+  end
+
+  @doc """
+  Return the tags, newest first, with the commit each points at. Annotated
+  tags also have their tagger, date and message; a lightweight tag's date
+  is its commit's.
+
+      {:ok, %{"tags" => [%{"name" => "v1.2.0", "hash" => "abc", "object" => "def",
+        "annotated" => true, "tagger" => "Ann", "tagger_email" => "ann@example.com",
+        "date" => "2024-05-01T12:00:00Z", "message" => "Release 1.2.0\\n"}]}}
+
+  Options:
+    - `:pattern` — only tags matching a glob such as `"v1.*"`
+  """
+  @spec git_tag_list(String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_tag_list(path \\ ".", opts \\ []) do
+    call("git_tag_list", %{"path" => path, "pattern" => Keyword.get(opts, :pattern, "")})
+  end
+
+  @doc """
+  Create tag `name`, annotated when given a `:message` and lightweight
+  otherwise. Returns the tag as listed by `git_tag_list/2`.
+
+  Options:
+    - `:rev` — what to tag, default HEAD
+    - `:message` — the message of an annotated tag
+    - `:tagger_name`, `:tagger_email` — default user.name and user.email
+    - `:force` — replace an existing tag of the name
+  """
+  @spec git_tag_create(String.t(), String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_tag_create(path \\ ".", name, opts \\ []) do
+    call("git_tag_create", %{
+      "path" => path,
+      "name" => name,
+      "rev" => Keyword.get(opts, :rev, ""),
+      "message" => Keyword.get(opts, :message, ""),
+      "tagger_name" => Keyword.get(opts, :tagger_name, ""),
+      "tagger_email" => Keyword.get(opts, :tagger_email, ""),
+      "force" => Keyword.get(opts, :force, false)
+    })
+  end
+
+  @doc """
+  Describe a commit from its nearest annotated tag, as `git describe` does.
+
+      {:ok, %{"describe" => "v1.2.0-3-gabc1234", "tag" => "v1.2.0", "distance" => 3,
+        "hash" => "abc1234...", "dirty" => false}}
+
+  Options:
+    - `:rev` — default HEAD
+    - `:tags` — also use lightweight tags
+    - `:long` — give the distance and hash even on a tagged commit
+    - `:abbrev` — hash length, default 7
+    - `:dirty` — append `"-dirty"` when the worktree has changes
+  """
+  @spec git_describe(String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_describe(path \\ ".", opts \\ []) do
+    call("git_describe", %{
+      "path" => path,
+      "rev" => Keyword.get(opts, :rev, ""),
+      "tags" => Keyword.get(opts, :tags, false),
+      "long" => Keyword.get(opts, :long, false),
+      "abbrev" => Keyword.get(opts, :abbrev, 7),
+      "dirty" => Keyword.get(opts, :dirty, false)
+    })
+  end
+
+  @doc """
+  Fetch from a remote. Returns the remote-tracking branches and tags that
+  changed, with `"old"` missing for new refs and `"new"` for pruned ones.
+
+      {:ok, %{"remote" => "origin", "up_to_date" => false,
+        "updated" => [%{"name" => "origin/main", "old" => "abc", "new" => "def"}]}}
+
+  Options:
+    - `:remote` — default the current branch's upstream remote, else `"origin"`
+    - `:prune` — drop remote-tracking branches deleted on the remote
+    - `:auth` — `:ssh_agent`, `{:ssh_agent, user}`, `{:token, token}` or
+      `{:token, user, token}` (HTTPS); without it SSH remotes use the agent
+  """
+  @spec git_fetch(String.t(), keyword()) :: {:ok, map()} | {:error, atom() | {:remote, map()}}
+  def git_fetch(path \\ ".", opts \\ []) do
+    call("git_fetch", remote_params(path, opts), @remote_timeout)
+  end
+
+  @doc """
+  Fetch and fast-forward the current branch to its upstream, or to
+  `:branch` on the remote. Refuses a worktree with uncommitted changes.
+
+      {:ok, %{"remote" => "origin", "branch" => "main", "old" => "abc", "new" => "def",
+        "up_to_date" => false, "updated" => [...]}}
+
+  Takes `:remote` and `:auth` as for `git_fetch/2`.
+  """
+  @spec git_pull(String.t(), keyword()) :: {:ok, map()} | {:error, atom() | {:remote, map()}}
+  def git_pull(path \\ ".", opts \\ []) do
+    call("git_pull", remote_params(path, opts), @remote_timeout)
+  end
+
+  @doc """
+  Push the current branch, or `:branch`, to its upstream, or to the branch
+  of the same name on the remote. Returns the remote-tracking branches that
+  moved, like `git_fetch/2`.
+
+  Options, besides `:remote` and `:auth` as for `git_fetch/2`:
+    - `:force` — overwrite the remote branch when it has diverged
+    - `:set_upstream` — make the branch track the one pushed to
+  """
+  @spec git_push(String.t(), keyword()) :: {:ok, map()} | {:error, atom() | {:remote, map()}}
+  def git_push(path \\ ".", opts \\ []) do
+    call("git_push", remote_params(path, opts), @remote_timeout)
+  end
+
+  @doc """
+  Return the submodules with the commit each is pinned to, the commit checked
+  out in it, and whether it is initialized, in sync with the pin, and dirty.
+
+      {:ok, %{"submodules" => [%{"name" => "libs/sub", "path" => "libs/sub",
+        "url" => "https://...", "expected" => "abc", "current" => "def",
+        "initialized" => true, "in_sync" => false, "dirty" => false}]}}
+  """
+  @spec git_submodules(String.t()) :: {:ok, map()} | {:error, atom()}
+  def git_submodules(path \\ ".") do
+    call("git_submodules", %{"path" => path})
+  end
+
+  @doc """
+  Check out the pinned commit of the submodules, fetching it when missing.
+  Returns the submodules updated, as `git_submodules/1` does. Submodules
+  with uncommitted changes are refused.
+
+  Options:
+    - `:paths` — only these submodules, default all
+    - `:init` — initialize submodules that are not yet
+    - `:recursive` — update the submodules of submodules too
+    - `:no_fetch` — do not fetch, only check out
+    - `:auth` — as for `git_fetch/2`
+  """
+  @spec git_submodule_update(String.t(), keyword()) :: {:ok, map()} | {:error, atom() | {:remote, map()}}
+  def git_submodule_update(path \\ ".", opts \\ []) do
+    params =
+      %{
+        "path" => path,
+        "paths" => Keyword.get(opts, :paths, []),
+        "init" => Keyword.get(opts, :init, false),
+        "recursive" => Keyword.get(opts, :recursive, false),
+        "no_fetch" => Keyword.get(opts, :no_fetch, false)
+      }
+      |> put_auth(Keyword.get(opts, :auth))
+
+    call("git_submodule_update", params, @remote_timeout)
+  end
+
+  @doc """
+  Return the conflicted files of a merge, rebase, cherry-pick or revert that
+  stopped on conflicts, with the base, ours and theirs version of each and
+  the lines of the conflict markers left in the worktree file. A side a file
+  is missing from, as when one side deleted it, is left out.
+
+      {:ok, %{"operation" => "merge", "conflicts" => [%{"file" => "lib/a.ex",
+        "kind" => "both_modified", "base" => %{"hash" => "abc", "content" => "..."},
+        "ours" => %{...}, "theirs" => %{...},
+        "markers" => [%{"start" => 12, "separator" => 15, "end" => 18}]}]}}
+
+  Options:
+    - `:files` — only these paths
+    - `:max_bytes` — content returned of each side, default 64 KiB; longer
+      content is cut at a line with `"truncated"` set
+  """
+  @spec git_conflicts(String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_conflicts(path \\ ".", opts \\ []) do
+    call("git_conflicts", %{
+      "path" => path,
+      "files" => Keyword.get(opts, :files, []),
+      "max_bytes" => Keyword.get(opts, :max_bytes, 0)
+    })
+  end
+
+  @doc """
+  Stage resolved conflicted files, each given as `{file, content}` to write,
+  `{file, :delete}` to remove, or `file` to take the worktree file as it is.
+  A resolution that still has conflict markers is refused, and nothing is
+  changed, unless `force: true`. Returns the files still conflicted; once
+  none are, the operation can be committed.
+
+      {:ok, %{"resolved" => ["lib/a.ex"], "remaining" => []}}
+  """
+  @spec git_resolve(String.t(), [String.t() | {String.t(), String.t() | :delete}], keyword()) ::
+          {:ok, map()} | {:error, atom()}
+  def git_resolve(path \\ ".", resolutions, opts \\ []) do
+    force = Keyword.get(opts, :force, false)
+
+    files =
+      Enum.map(resolutions, fn
+        {file, :delete} -> %{"file" => file, "delete" => true}
+        {file, content} -> %{"file" => file, "content" => content, "force" => force}
+        file -> %{"file" => file, "force" => force}
+      end)
+
+    call("git_resolve", %{"path" => path, "files" => files})
+  end
+
+  @doc """
+  Check whether paths are ignored, before creating files there, and by
+  which rule of which .gitignore, `.git/info/exclude` or the user's excludes
+  file. The paths are relative to the worktree root and need not exist. A
+  tracked file is never ignored; a file kept by a `!` rule reports that
+  rule.
+
+      {:ok, %{"files" => [%{"file" => "build/out.js", "ignored" => true,
+        "tracked" => false,
+        "rule" => %{"source" => ".gitignore", "line" => 3, "pattern" => "build/"}}]}}
+  """
+  @spec git_check_ignore(String.t(), [String.t()]) :: {:ok, map()} | {:error, atom()}
+  def git_check_ignore(path \\ ".", files) do
+    call("git_check_ignore", %{"path" => path, "files" => files})
+  end
+
+  @doc """
+  Return the tracked files, sorted, with how many there are; `"more"` is set
+  when the limit cut the list short.
+
+      {:ok, %{"files" => ["lib/a.ex", "lib/b.ex"], "total" => 2, "more" => false}}
+
+  Options:
+    - `:pattern` — only files matching a glob: `"*.ex"` matches the names in
+      any directory, `"lib/**/*_test.exs"` whole paths, `**` standing for
+      any number of directories
+    - `:limit` — most files returned, default 1000
+  """
+  @spec git_ls_files(String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_ls_files(path \\ ".", opts \\ []) do
+    call("git_ls_files", %{
+      "path" => path,
+      "pattern" => Keyword.get(opts, :pattern, ""),
+      "limit" => Keyword.get(opts, :limit, 0)
+    })
+  end
+
+  @doc """
+  Apply a unified diff to the worktree, all of it or nothing: the files are
+  only changed when every hunk applies. A hunk applies where its context
+  matches nearest to the line its header gives. Returns how each file and
+  hunk applied, with the line of a hunk's first change in the patched file.
+
+      {:ok, %{"applied" => false, "files" => [%{"file" => "lib/a.ex",
+        "status" => "modified", "applied" => false, "hunks" => [
+          %{"header" => "@@ -12,4 +12,5 @@", "applied" => true, "line" => 14, "offset" => 2},
+          %{"header" => "@@ -40,3 +41,3 @@", "applied" => false,
+            "error" => "does not match the file"}]}]}}
+
+  Options:
+    - `:check` — only report whether the patch applies
+    - `:three_way` — merge the hunks of a file whose context has changed
+      since the patch was made, from the version named by its `index` line
+      or else the file as staged or at HEAD
+  """
+  @spec git_apply(String.t(), String.t(), keyword()) :: {:ok, map()} | {:error, atom()}
+  def git_apply(path \\ ".", patch, opts \\ []) do
+    call("git_apply", %{
+      "path" => path,
+      "patch" => patch,
+      "check" => Keyword.get(opts, :check, false),
+      "three_way" => Keyword.get(opts, :three_way, false)
+    })
+  end
+
+  @doc """
+  Describe the repository in one call: its root, whether it is bare, the
+  remotes with their URLs (passwords masked), the checked out branch with
+  its upstream and how far the two have diverged, the last commit with the
+  first line of its message, and the operation in progress (`"merge"`,
+  `"rebase"`, `"cherry_pick"`, `"revert"` or `""`). `"branch"` is `""` and
+  `"detached"` true when HEAD is detached; `"head"` is `""` before the
+  first commit.
+
+      {:ok, %{"root" => "/src/app", "git_dir" => "/src/app/.git", "bare" => false,
+        "remotes" => [%{"name" => "origin", "urls" => ["git@github.com:o/app.git"]}],
+        "branch" => "main", "detached" => false, "head" => "abc", "upstream" => "origin/main",
+        "ahead" => 1, "behind" => 0, "last_commit" => %{"hash" => "abc", "author" => "Name",
+        "message" => "Fix login", "date" => "..."}, "operation" => ""}}
+  """
+  @spec git_info(String.t()) :: {:ok, map()} | {:error, atom()}
+  def git_info(path \\ ".") do
+    call("git_info", %{"path" => path})
+  end
+
   # -- GenServer callbacks --
 
   @impl true
@@ -104,8 +570,10 @@ defmodule OptimalSystemAgent.Go.Git do
       port: nil,
       mode: :fallback,
       binary_path: binary_path,
-      # id => {from, timer_ref}
-      pending: %{}
+      # id => {from, timer_ref, monitor_ref}
+      pending: %{},
+      # id => {count, reversed chunks} of a result arriving in chunks
+      chunks: %{}
     }
 
     state = maybe_start_port(state)
@@ -132,16 +600,17 @@ defmodule OptimalSystemAgent.Go.Git do
   end
 
   # Generic request dispatch (satisfies Sidecar.Behaviour call/3).
-  def handle_call({:request, _method, _params}, _from, %{mode: :fallback} = state) do
+  def handle_call({:request, _method, _params, _timeout}, _from, %{mode: :fallback} = state) do
     {:reply, {:error, :sidecar_unavailable}, state}
   end
 
-  def handle_call({:request, method, params}, from, %{mode: :ready, port: port} = state) do
+  def handle_call({:request, method, params, timeout}, from, %{mode: :ready, port: port} = state) do
     {id, encoded} = Protocol.encode_request(method, params)
     Port.command(port, encoded)
 
-    timer_ref = Process.send_after(self(), {:request_timeout, id}, @request_timeout)
-    pending = Map.put(state.pending, id, {from, timer_ref})
+    timer_ref = Process.send_after(self(), {:request_timeout, id}, timeout)
+    {pid, _tag} = from
+    pending = Map.put(state.pending, id, {from, timer_ref, Process.monitor(pid)})
 
     {:noreply, %{state | pending: pending}}
   end
@@ -152,9 +621,28 @@ defmodule OptimalSystemAgent.Go.Git do
       {:ok, id, result} ->
         resolve_pending(state, id, {:ok, result})
 
+      {:error, id, %{"data" => %{} = data} = error} when is_binary(id) ->
+        resolve_pending(state, id, {:error, {:remote, Map.put(data, "message", error["message"])}})
+
       {:error, id, _error} when is_binary(id) ->
         resolve_pending(state, id, {:error, :sidecar_error})
 
+      {:chunk, id, _seq, chunk} ->
+        {:noreply, add_chunk(state, id, chunk)}
+
+      {:end, id, count} ->
+        {{received, reversed}, chunks} = Map.pop(state.chunks, id, {0, []})
+        state = %{state | chunks: chunks}
+
+        with true <- received == count,
+             {:ok, result} <- Protocol.decode_chunks(Enum.reverse(reversed)) do
+          resolve_pending(state, id, {:ok, result})
+        else
+          _ ->
+            Logger.warning("[Go.Git] Invalid chunked response: got #{received} of #{count} chunks")
+            resolve_pending(state, id, {:error, :sidecar_error})
+        end
+
       {:error, :invalid, reason} ->
         Logger.warning("[Go.Git] Invalid response: #{reason}")
         {:noreply, state}
@@ -172,15 +660,32 @@ defmodule OptimalSystemAgent.Go.Git do
 
   def handle_info({:request_timeout, id}, state) do
     case Map.pop(state.pending, id) do
-      {{from, _timer_ref}, pending} ->
+      {{from, _timer_ref, monitor_ref}, pending} ->
+        Process.demonitor(monitor_ref, [:flush])
         GenServer.reply(from, {:error, :timeout})
-        {:noreply, %{state | pending: pending}}
+        cancel_request(state, id)
+        {:noreply, %{state | pending: pending, chunks: Map.delete(state.chunks, id)}}
 
       {nil, _} ->
         {:noreply, state}
     end
   end
 
+  # The caller is gone, so nothing waits for the result.
+  def handle_info({:DOWN, monitor_ref, :process, _pid, _reason}, state) do
+    case Enum.find(state.pending, fn {_id, {_from, _timer_ref, ref}} -> ref == monitor_ref end) do
+      {id, {_from, timer_ref, _ref}} ->
+        Process.cancel_timer(timer_ref)
+        cancel_request(state, id)
+
+        {:noreply,
+         %{state | pending: Map.delete(state.pending, id), chunks: Map.delete(state.chunks, id)}}
+
+      nil ->
+        {:noreply, state}
+    end
+  end
+
   def handle_info(:restart_port, state) do
     state = maybe_start_port(%{state | port: nil})
 
@@ -246,10 +751,43 @@ defmodule OptimalSystemAgent.Go.Git do
     end
   end
 
+  defp remote_params(path, opts) do
+    %{
+      "path" => path,
+      "remote" => Keyword.get(opts, :remote, ""),
+      "branch" => Keyword.get(opts, :branch, ""),
+      "force" => Keyword.get(opts, :force, false),
+      "prune" => Keyword.get(opts, :prune, false),
+      "set_upstream" => Keyword.get(opts, :set_upstream, false)
+    }
+    |> put_auth(Keyword.get(opts, :auth))
+  end
+
+  defp put_auth(params, nil), do: params
+  defp put_auth(params, :ssh_agent), do: Map.put(params, "auth", %{"method" => "ssh_agent"})
+  defp put_auth(params, {:ssh_agent, user}), do: Map.put(params, "auth", %{"method" => "ssh_agent", "user" => user})
+  defp put_auth(params, {:token, token}), do: Map.put(params, "auth", %{"method" => "token", "token" => token})
+
+  defp put_auth(params, {:token, user, token}),
+    do: Map.put(params, "auth", %{"method" => "token", "user" => user, "token" => token})
+
+  # Tells the sidecar to stop working on a request; its reply, the
+  # cancellation or a result that was already on its way, is dropped as the
+  # request is no longer pending.
+  defp cancel_request(%{port: port}, id) when not is_nil(port) do
+    {_cancel_id, encoded} = Protocol.encode_request("cancel", %{"id" => id})
+    Port.command(port, encoded)
+  catch
+    _, _ -> :ok
+  end
+
+  defp cancel_request(_state, _id), do: :ok
+
   defp resolve_pending(state, id, result) do
     case Map.pop(state.pending, id) do
-      {{from, timer_ref}, pending} ->
+      {{from, timer_ref, monitor_ref}, pending} ->
         Process.cancel_timer(timer_ref)
+        Process.demonitor(monitor_ref, [:flush])
         GenServer.reply(from, result)
         {:noreply, %{state | pending: pending}}
 
@@ -259,11 +797,22 @@ defmodule OptimalSystemAgent.Go.Git do
   end
 
   defp fail_all_pending(state, reason) do
-    Enum.each(state.pending, fn {_id, {from, timer_ref}} ->
+    Enum.each(state.pending, fn {_id, {from, timer_ref, monitor_ref}} ->
       Process.cancel_timer(timer_ref)
+      Process.demonitor(monitor_ref, [:flush])
       GenServer.reply(from, {:error, reason})
     end)
 
-    %{state | pending: %{}}
+    %{state | pending: %{}, chunks: %{}}
+  end
+
+  # Chunks are kept only for requests still waiting for their result.
+  defp add_chunk(state, id, chunk) do
+    if Map.has_key?(state.pending, id) do
+      chunks = Map.update(state.chunks, id, {1, [chunk]}, fn {n, acc} -> {n + 1, [chunk | acc]} end)
+      %{state | chunks: chunks}
+    else
+      state
+    end
   end
 end
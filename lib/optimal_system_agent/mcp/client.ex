@@ -47,4 +47,133 @@ defmodule OptimalSystemAgent.MCP.Client do
       %{}
     end
   end
+
+  @doc """
+  Status of every configured server, by name. See `server_status/2`.
+  """
+  @spec list_status() :: [map()]
+  def list_status do
+    load_servers()
+    |> Enum.map(fn {name, config} -> server_status(name, config) end)
+    |> Enum.sort_by(& &1.name)
+  end
+
+  @doc """
+  Status of one server: its transport and command or URL, the tools it
+  registered (named `mcp_<server>_<tool>`), and a state of "connected" once
+  it has tools, "error" when it cannot be started, or "connecting".
+  """
+  @spec server_status(String.t(), map()) :: map()
+  def server_status(name, config) do
+    tools = server_tools(name)
+
+    {transport, error} =
+      case config do
+        %{"url" => url} when is_binary(url) ->
+          case URI.parse(url) do
+            %URI{scheme: scheme, host: host} when scheme in ["http", "https"] and is_binary(host) ->
+              {"http", nil}
+
+            _ ->
+              {"http", "invalid url: #{url}"}
+          end
+
+        %{"command" => command} when is_binary(command) ->
+          if System.find_executable(command),
+            do: {"stdio", nil},
+            else: {"stdio", "command not found: #{command}"}
+
+        _ ->
+          {"unknown", "neither command nor url is set"}
+      end
+
+    status =
+      cond do
+        error != nil -> "error"
+        tools != [] -> "connected"
+        true -> "connecting"
+      end
+
+    %{
+      name: name,
+      transport: transport,
+      command: config["command"],
+      args: config["args"] || [],
+      url: config["url"],
+      status: status,
+      error: error,
+      tools: tools
+    }
+  end
+
+  @doc """
+  Add a server to mcp.json, or replace the one with the same name. Other
+  keys of the file are kept.
+  """
+  @spec add_server(String.t(), map()) :: :ok | {:error, String.t()}
+  def add_server(name, config) do
+    update_config(fn servers -> {:ok, Map.put(servers, name, config)} end)
+  end
+
+  @doc "Remove a server from mcp.json."
+  @spec remove_server(String.t()) :: :ok | {:error, :not_found | String.t()}
+  def remove_server(name) do
+    update_config(fn servers ->
+      if Map.has_key?(servers, name),
+        do: {:ok, Map.delete(servers, name)},
+        else: {:error, :not_found}
+    end)
+  end
+
+  @doc """
+  Restart a server: its entry is read from mcp.json again and checked anew.
+  Returns the new status.
+  """
+  @spec restart_server(String.t()) :: {:ok, map()} | {:error, :not_found}
+  def restart_server(name) do
+    case Map.fetch(load_servers(), name) do
+      {:ok, config} ->
+        Logger.info("[MCP] Restarting #{name}")
+        {:ok, server_status(name, config)}
+
+      :error ->
+        {:error, :not_found}
+    end
+  end
+
+  defp server_tools(name) do
+    prefix = "mcp_#{name}_"
+
+    try do
+      OptimalSystemAgent.Tools.Registry.list_tools_direct()
+      |> Enum.map(& &1.name)
+      |> Enum.filter(&String.starts_with?(&1, prefix))
+      |> Enum.sort()
+    rescue
+      _ -> []
+    end
+  end
+
+  # Applies fun to the "mcpServers" map of mcp.json and writes the result
+  # back, creating the file when needed.
+  defp update_config(fun) do
+    path = mcp_config_path()
+
+    doc =
+      with true <- File.exists?(path),
+           {:ok, %{} = doc} <- Jason.decode(File.read!(path)) do
+        doc
+      else
+        _ -> %{}
+      end
+
+    with {:ok, servers} <- fun.(Map.get(doc, "mcpServers", %{})),
+         :ok <- File.mkdir_p(Path.dirname(path)),
+         :ok <- File.write(path, Jason.encode!(Map.put(doc, "mcpServers", servers), pretty: true)) do
+      :ok
+    else
+      {:error, :not_found} -> {:error, :not_found}
+      {:error, reason} -> {:error, "could not write #{path}: #{inspect(reason)}"}
+    end
+  end
 end
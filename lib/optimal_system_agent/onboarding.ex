@@ -416,6 +416,92 @@ defmodule OptimalSystemAgent.Onboarding do
     end)
   end
 
+  @doc """
+  Test provider credentials for the HTTP onboarding wizard before setup is
+  written. Sends a one-token chat to `model`, or the provider's default,
+  with `api_key`, or the key already configured when it is blank. For
+  Ollama, checks that the daemon is reachable. Returns `:ok` or
+  `{:error, message, hint}`; hint is nil when there is no likely fix.
+  """
+  @spec verify_provider(String.t(), String.t() | nil, String.t() | nil) ::
+          :ok | {:error, String.t(), String.t() | nil}
+  def verify_provider(provider, model, api_key) do
+    case Enum.find(@providers, fn {_num, key, _name, _model, _env} -> key == provider end) do
+      nil ->
+        {:error, "Unknown provider: #{provider}", nil}
+
+      {_num, "ollama", _name, _model, _env} ->
+        case test_provider_connectivity("ollama", model, nil) do
+          :ok -> :ok
+          {:error, reason} -> {:error, reason, "Start Ollama with `ollama serve`, or pick another provider."}
+        end
+
+      {_num, _key, _name, default_model, env_var} ->
+        model = if is_binary(model) and model != "", do: model, else: default_model
+
+        api_key =
+          if is_binary(api_key) and api_key != "",
+            do: api_key,
+            else: Application.get_env(:optimal_system_agent, env_var_to_app_key(env_var))
+
+        case test_provider_connectivity(provider, model, api_key) do
+          :ok -> chat_probe(provider, model, api_key, env_var)
+          {:error, reason} -> {:error, reason, "Enter a key, or set #{env_var} before starting OSA."}
+        end
+    end
+  end
+
+  @doc """
+  Returns the key state of every provider that takes an API key, for the
+  HTTP API. `source` is `"config"` for a key stored under `api_keys` in
+  config.json and `"env"` for one only the environment sets. The key itself
+  is never returned, only its last four characters in `masked`.
+  """
+  @spec provider_keys() :: [map()]
+  def provider_keys do
+    stored = stored_api_keys()
+
+    for {_num, key, name, _model, env_var} <- @providers, env_var do
+      provider_key_entry(key, name, env_var, stored)
+    end
+  end
+
+  @doc """
+  Store `api_key` for `provider` under `api_keys` in config.json and apply it
+  to the running system. Returns `{:ok, entry}` as listed by
+  `provider_keys/0`, or `{:error, :unknown_provider}`.
+  """
+  @spec set_provider_key(String.t(), String.t()) :: {:ok, map()} | {:error, :unknown_provider}
+  def set_provider_key(provider, api_key) do
+    with {:ok, name, env_var} <- key_provider(provider) do
+      stored = update_api_keys(&Map.put(&1, env_var, api_key))
+      System.put_env(env_var, api_key)
+      Application.put_env(:optimal_system_agent, env_var_to_app_key(env_var), api_key)
+      {:ok, provider_key_entry(provider, name, env_var, stored)}
+    end
+  end
+
+  @doc """
+  Remove the key of `provider` from config.json and from the running system.
+  A key that only the environment sets is left alone. Returns `{:ok, entry}`
+  or `{:error, :unknown_provider}`.
+  """
+  @spec delete_provider_key(String.t()) :: {:ok, map()} | {:error, :unknown_provider}
+  def delete_provider_key(provider) do
+    with {:ok, name, env_var} <- key_provider(provider) do
+      removed = Map.get(stored_api_keys(), env_var)
+      stored = update_api_keys(&Map.delete(&1, env_var))
+
+      # apply_config copied the stored key into the environment.
+      if removed && System.get_env(env_var) == removed do
+        System.delete_env(env_var)
+        Application.delete_env(:optimal_system_agent, env_var_to_app_key(env_var))
+      end
+
+      {:ok, provider_key_entry(provider, name, env_var, stored)}
+    end
+  end
+
   @doc "Scan for OS templates and return as maps for the HTTP API."
   @spec templates_list() :: [map()]
   def templates_list do
@@ -862,6 +948,70 @@ defmodule OptimalSystemAgent.Onboarding do
   defp test_provider_connectivity(_provider, _model, ""), do: {:error, "No API key provided"}
   defp test_provider_connectivity(_provider, _model, _key), do: :ok
 
+  @verify_timeout_ms 20_000
+
+  # Sends a one-token chat with api_key in place of the configured key,
+  # which is put back afterwards. Calls the provider module directly so the
+  # fallback chain cannot answer for it.
+  defp chat_probe(provider, model, api_key, env_var) do
+    {:ok, %{module: module}} =
+      OptimalSystemAgent.Providers.Registry.provider_info(String.to_existing_atom(provider))
+
+    app_key = env_var_to_app_key(env_var)
+    previous = Application.get_env(:optimal_system_agent, app_key)
+    Application.put_env(:optimal_system_agent, app_key, api_key)
+
+    task =
+      Task.async(fn ->
+        try do
+          module.chat([%{role: "user", content: "ping"}], model: model, max_tokens: 1, temperature: 0.0)
+        rescue
+          e -> {:error, Exception.message(e)}
+        end
+      end)
+
+    try do
+      case Task.yield(task, @verify_timeout_ms) || Task.shutdown(task, :brutal_kill) do
+        {:ok, {:ok, _}} ->
+          :ok
+
+        {:ok, {:error, reason}} ->
+          reason = if is_binary(reason), do: reason, else: inspect(reason)
+          {:error, reason, verify_hint(reason, model, env_var)}
+
+        {:exit, reason} ->
+          {:error, "#{provider} check crashed: #{inspect(reason)}", nil}
+
+        nil ->
+          {:error, "#{provider} did not answer within #{div(@verify_timeout_ms, 1000)}s",
+           "Check the network connection to the provider."}
+      end
+    after
+      if previous,
+        do: Application.put_env(:optimal_system_agent, app_key, previous),
+        else: Application.delete_env(:optimal_system_agent, app_key)
+    end
+  end
+
+  defp verify_hint(reason, model, env_var) do
+    cond do
+      reason =~ ~r/\b(401|403)\b|unauthori[sz]ed|invalid.*key|authentication|permission/i ->
+        "The key was rejected; check it for typos, or create a new one. #{env_var} holds it."
+
+      reason =~ ~r/\b404\b|model/i ->
+        "Check that #{model} is available to this account, or pick another model."
+
+      reason =~ ~r/\b429\b|rate limit|quota|billing|credit/i ->
+        "The key works but the account is out of quota or rate limited."
+
+      reason =~ ~r/connection failed|timeout|nxdomain|econnrefused/i ->
+        "Check the network connection to the provider."
+
+      true ->
+        nil
+    end
+  end
+
   defp ensure_httpc_started do
     :inets.start()
     :ssl.start()
@@ -989,6 +1139,66 @@ defmodule OptimalSystemAgent.Onboarding do
     if key_atom, do: Application.get_env(:optimal_system_agent, key_atom), else: nil
   end
 
+  # ── Provider Keys ───────────────────────────────────────────────
+
+  defp key_provider(provider) do
+    case Enum.find(@providers, fn {_num, key, _name, _model, env} -> key == provider and env end) do
+      {_num, _key, name, _model, env_var} -> {:ok, name, env_var}
+      nil -> {:error, :unknown_provider}
+    end
+  end
+
+  defp provider_key_entry(provider, name, env_var, stored) do
+    value =
+      Map.get(stored, env_var) ||
+        Application.get_env(:optimal_system_agent, env_var_to_app_key(env_var))
+
+    configured = is_binary(value) and value != ""
+
+    %{
+      provider: provider,
+      name: name,
+      env_var: env_var,
+      configured: configured,
+      masked: if(configured, do: mask_key(value)),
+      source: cond do
+        Map.has_key?(stored, env_var) -> "config"
+        configured -> "env"
+        true -> nil
+      end
+    }
+  end
+
+  defp mask_key(key) when byte_size(key) <= 8, do: "••••"
+  defp mask_key(key), do: String.slice(key, 0, 3) <> "…" <> String.slice(key, -4, 4)
+
+  defp stored_api_keys do
+    case read_config_json() do
+      %{"api_keys" => %{} = keys} -> keys
+      _ -> %{}
+    end
+  end
+
+  # Applies fun to the api_keys of config.json, keeping the rest of the
+  # file, and returns the new api_keys.
+  defp update_api_keys(fun) do
+    config = read_config_json()
+    keys = fun.(Map.get(config, "api_keys") || %{})
+    dir = config_dir()
+    File.mkdir_p!(dir)
+    File.write!(Path.join(dir, "config.json"), Jason.encode!(Map.put(config, "api_keys", keys), pretty: true))
+    keys
+  end
+
+  defp read_config_json do
+    with {:ok, contents} <- File.read(Path.join(config_dir(), "config.json")),
+         {:ok, %{} = config} <- Jason.decode(contents) do
+      config
+    else
+      _ -> %{}
+    end
+  end
+
   # ── Formatting Helpers ──────────────────────────────────────────
 
   defp format_channels([]), do: "#{@dim}(none)#{@reset}"
@@ -33,6 +33,31 @@ defmodule OptimalSystemAgent.Sandbox.Executor do
 
   alias OptimalSystemAgent.Sandbox.{Config, Docker, Wasm}
 
+  defmodule Output do
+    @moduledoc false
+    # Collects a command's output into a binary, passing each chunk and its
+    # byte offset to on_output as it arrives.
+    defstruct [:on_output]
+
+    defimpl Collectable do
+      def into(%{on_output: on_output}) do
+        collector = fn
+          {acc, size}, {:cont, chunk} ->
+            on_output.(chunk, size)
+            {[acc | chunk], size + byte_size(chunk)}
+
+          {acc, _size}, :done ->
+            IO.iodata_to_binary(acc)
+
+          _acc, :halt ->
+            :ok
+        end
+
+        {{[], 0}, collector}
+      end
+    end
+  end
+
   @type exec_result ::
           {:ok, output :: String.t()}
           | {:ok, output :: String.t(), exit_code :: non_neg_integer()}
@@ -49,8 +74,10 @@ defmodule OptimalSystemAgent.Sandbox.Executor do
   - `:cwd`        — working directory (BEAM mode only; Docker always uses /workspace)
   - `:network`    — override network flag for this call (Docker mode)
   - `:image`      — override container image for this call (Docker mode)
-  - `:extra_env`  — `[{"KEY", "val"}]` env vars to inject (Docker mode)
+  - `:extra_env`  — `[{"KEY", "val"}]` env vars to inject (Docker and BEAM mode)
   - `:workspace`  — override host workspace path (Docker mode)
+  - `:on_output`  — `fun(chunk, offset)` called with each chunk of output and
+    its byte offset while the command runs (BEAM mode only)
 
   ## Return values
 
@@ -108,6 +135,8 @@ defmodule OptimalSystemAgent.Sandbox.Executor do
   defp beam_execute(command, opts) do
     timeout = Keyword.get(opts, :timeout, 30_000)
     cwd = Keyword.get(opts, :cwd, nil)
+    on_output = Keyword.get(opts, :on_output, nil)
+    extra_env = Keyword.get(opts, :extra_env, [])
 
     task =
       Task.async(fn ->
@@ -115,6 +144,10 @@ defmodule OptimalSystemAgent.Sandbox.Executor do
           cmd_opts =
             [stderr_to_stdout: true]
             |> then(fn o -> if cwd, do: Keyword.put(o, :cd, cwd), else: o end)
+            |> then(fn o -> if extra_env != [], do: Keyword.put(o, :env, extra_env), else: o end)
+            |> then(fn o ->
+              if on_output, do: Keyword.put(o, :into, %Output{on_output: on_output}), else: o
+            end)
 
           System.cmd("sh", ["-c", command], cmd_opts)
         rescue
@@ -26,6 +26,9 @@ defmodule OptimalSystemAgent.SDK.Session do
   - `:extra_tools` — additional tool definitions for this session
   - `:provider` — LLM provider override for this session
   - `:model` — model name override for this session
+  - `:messages` — conversation to seed the agent's context with, as
+    `%{role: "user" | "assistant", content: String.t()}` maps, e.g. to
+    restore a session lost in a restart (ignored when the session exists)
   """
   @spec create(keyword()) :: {:ok, String.t()} | {:error, term()}
   def create(opts \\ []) do
@@ -37,7 +40,8 @@ defmodule OptimalSystemAgent.SDK.Session do
       channel: Keyword.get(opts, :channel, :sdk),
       extra_tools: Keyword.get(opts, :extra_tools, []),
       provider: Keyword.get(opts, :provider),
-      model: Keyword.get(opts, :model)
+      model: Keyword.get(opts, :model),
+      messages: Keyword.get(opts, :messages, [])
     ]
 
     case DynamicSupervisor.start_child(@supervisor, {Loop, loop_opts}) do
@@ -10,6 +10,22 @@ defmodule OptimalSystemAgent.Sidecar.Protocol do
 
   Each message is a single JSON line terminated by `\\n`.
   The `id` field correlates requests with responses.
+
+  A sidecar may send a result too large for one line, such as a big git
+  diff, as successive pieces of its JSON followed by an end frame:
+
+      Chunk: {"id":"abc","seq":0,"result_chunk":"{\\"diff\\":\\"..."}\n
+      End:   {"id":"abc","result_end":{"chunks":3}}\n
+
+  Joined in order, the chunks are the JSON of the result.
+
+  Responses need not come in the order of the requests. A sidecar that
+  handles requests concurrently, such as git, accepts a `cancel` request
+  naming another by its `id`; that one is then answered with error code
+  `-32800`:
+
+      Cancel:   {"id":"abd","method":"cancel","params":{"id":"abc"}}\n
+      Response: {"id":"abd","result":{"cancelled":true}}\n
   """
 
   @doc """
@@ -36,14 +52,26 @@ defmodule OptimalSystemAgent.Sidecar.Protocol do
   Returns one of:
     - `{:ok, id, result}` — successful response
     - `{:error, id, error_map}` — error response with code + message
+    - `{:chunk, id, seq, chunk}` — a piece of a chunked result
+    - `{:end, id, chunks}` — the end of a chunked result, with its piece count
     - `{:error, :invalid, reason}` — malformed line
   """
   @spec decode_response(binary()) ::
-          {:ok, String.t(), map()} | {:error, String.t(), map()} | {:error, :invalid, String.t()}
+          {:ok, String.t(), map()}
+          | {:error, String.t(), map()}
+          | {:chunk, String.t(), non_neg_integer(), binary()}
+          | {:end, String.t(), non_neg_integer()}
+          | {:error, :invalid, String.t()}
   def decode_response(line) do
     line = String.trim(line)
 
     case Jason.decode(line) do
+      {:ok, %{"id" => id, "seq" => seq, "result_chunk" => chunk}} ->
+        {:chunk, id, seq, chunk}
+
+      {:ok, %{"id" => id, "result_end" => %{"chunks" => chunks}}} ->
+        {:end, id, chunks}
+
       {:ok, %{"id" => id, "result" => result}} ->
         {:ok, id, result}
 
@@ -58,6 +86,17 @@ defmodule OptimalSystemAgent.Sidecar.Protocol do
     end
   end
 
+  @doc """
+  Decode the result of a chunked response from its chunks, in order.
+  """
+  @spec decode_chunks([binary()]) :: {:ok, term()} | {:error, :invalid, String.t()}
+  def decode_chunks(chunks) do
+    case chunks |> IO.iodata_to_binary() |> Jason.decode() do
+      {:ok, result} -> {:ok, result}
+      {:error, reason} -> {:error, :invalid, "JSON decode failed: #{inspect(reason)}"}
+    end
+  end
+
   @doc """
   Generate a unique request ID (8-char hex).
   """
@@ -309,11 +309,31 @@ defmodule OptimalSystemAgent.Swarm.Orchestrator do
         Planner.decompose(task, max_agents: max_agents)
       end
 
-    # 2. Start workers under DynamicSupervisor
+    # 2. Start workers under DynamicSupervisor. Roles that appear more than
+    # once are numbered, so progress events name each worker apart.
+    role_counts = Enum.frequencies_by(plan.agents, & &1.role)
+
     workers =
-      Enum.map(plan.agents, fn agent_spec ->
+      plan.agents
+      |> Enum.with_index()
+      |> Enum.map(fn {agent_spec, i} ->
         worker_id = generate_id()
-        init_opts = %{id: worker_id, swarm_id: swarm_id, role: agent_spec.role}
+
+        name =
+          if role_counts[agent_spec.role] > 1 do
+            n = Enum.count(Enum.take(plan.agents, i + 1), &(&1.role == agent_spec.role))
+            "#{agent_spec.role} #{n}"
+          else
+            to_string(agent_spec.role)
+          end
+
+        init_opts = %{
+          id: worker_id,
+          swarm_id: swarm_id,
+          role: agent_spec.role,
+          name: name,
+          session_id: session_id
+        }
 
         case DynamicSupervisor.start_child(
                OptimalSystemAgent.Swarm.AgentPool,
@@ -338,7 +358,17 @@ defmodule OptimalSystemAgent.Swarm.Orchestrator do
       # 4. Schedule timeout
       Process.send_after(self(), {:timeout, swarm_id}, timeout_ms)
 
-      # 5. Execute pattern asynchronously
+      # 5. Announce the swarm before any worker reports progress
+      Bus.emit(:system_event, %{
+        event: :swarm_started,
+        swarm_id: swarm_id,
+        pattern: plan.pattern,
+        agent_count: length(workers),
+        task_preview: String.slice(task, 0, 200),
+        session_id: session_id
+      })
+
+      # 6. Execute pattern asynchronously
       orchestrator = self()
 
       Task.start(fn ->
@@ -389,15 +419,6 @@ defmodule OptimalSystemAgent.Swarm.Orchestrator do
           active_count: state.active_count + 1
       }
 
-      Bus.emit(:system_event, %{
-        event: :swarm_started,
-        swarm_id: swarm_id,
-        pattern: plan.pattern,
-        agent_count: length(workers),
-        task_preview: String.slice(task, 0, 200),
-        session_id: session_id
-      })
-
       Logger.info("Swarm #{swarm_id} launched: pattern=#{plan.pattern} agents=#{length(workers)}")
 
       {:reply, {:ok, swarm_id}, new_state}
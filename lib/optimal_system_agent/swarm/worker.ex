@@ -16,6 +16,10 @@ defmodule OptimalSystemAgent.Swarm.Worker do
     5. Replies to the caller with {:ok, result} | {:error, reason}
     6. Exits normally (restart: :temporary)
 
+  Each assignment emits `:swarm_agent_started` and `:swarm_agent_completed`
+  on :system_event, scoped to the swarm's session, so clients can show the
+  progress of every agent.
+
   Workers are `:temporary` — they are expected to exit after completing
   their assigned task. If they crash, the DynamicSupervisor does NOT restart
   them; instead the Orchestrator handles failure via the return value from
@@ -25,6 +29,7 @@ defmodule OptimalSystemAgent.Swarm.Worker do
   require Logger
 
   alias OptimalSystemAgent.Agent.{Roster, Tier}
+  alias OptimalSystemAgent.Events.Bus
   alias OptimalSystemAgent.Providers.Registry, as: Providers
   alias OptimalSystemAgent.Swarm.Mailbox
 
@@ -32,6 +37,9 @@ defmodule OptimalSystemAgent.Swarm.Worker do
     :id,
     :swarm_id,
     :role,
+    # display name, e.g. "researcher 2"; defaults to the role
+    :name,
+    :session_id,
     # :idle | :working | :done | :failed
     status: :idle,
     messages: [],
@@ -63,11 +71,13 @@ defmodule OptimalSystemAgent.Swarm.Worker do
   # ── GenServer Callbacks ──────────────────────────────────────────────
 
   @impl true
-  def init(%{id: id, swarm_id: swarm_id, role: role} = _opts) do
+  def init(%{id: id, swarm_id: swarm_id, role: role} = opts) do
     state = %__MODULE__{
       id: id,
       swarm_id: swarm_id,
       role: role,
+      name: Map.get(opts, :name, to_string(role)),
+      session_id: Map.get(opts, :session_id),
       started_at: DateTime.utc_now()
     }
 
@@ -97,8 +107,15 @@ defmodule OptimalSystemAgent.Swarm.Worker do
       "Worker #{state.id} (#{state.role}) calling LLM [#{tier}/#{model}] for task: #{String.slice(task_description, 0, 80)}..."
     )
 
+    emit_agent_event(state, :swarm_agent_started, %{
+      model: model,
+      current_action: String.slice(task_description, 0, 120)
+    })
+
+    response = Providers.chat(messages, temperature: temperature, model: model)
+
     result =
-      case Providers.chat(messages, temperature: temperature, model: model) do
+      case response do
         {:ok, %{content: content}} when is_binary(content) and content != "" ->
           # Post result to swarm mailbox so peers can read it
           Mailbox.post(state.swarm_id, state.id, content)
@@ -115,14 +132,20 @@ defmodule OptimalSystemAgent.Swarm.Worker do
           {:error, reason}
       end
 
-    {status, result_value} =
+    {status, result_value, error} =
       case result do
-        {:ok, text} -> {:done, text}
-        {:error, _} -> {:failed, nil}
+        {:ok, text} -> {:done, text, nil}
+        {:error, reason} -> {:failed, nil, inspect(reason)}
       end
 
     state = %{state | status: status, result: result_value}
 
+    emit_agent_event(state, :swarm_agent_completed, %{
+      status: if(status == :done, do: "completed", else: "failed"),
+      error: error,
+      tokens_used: tokens_used(response)
+    })
+
     {:reply, result, state}
   end
 
@@ -154,6 +177,28 @@ defmodule OptimalSystemAgent.Swarm.Worker do
   defp role_to_tier(:tester), do: :specialist
   defp role_to_tier(_), do: :specialist
 
+  defp emit_agent_event(state, event, fields) do
+    Bus.emit(
+      :system_event,
+      Map.merge(
+        %{
+          event: event,
+          swarm_id: state.swarm_id,
+          agent_name: state.name,
+          role: to_string(state.role),
+          session_id: state.session_id
+        },
+        fields
+      )
+    )
+  end
+
+  defp tokens_used({:ok, %{usage: %{} = usage}}) do
+    Map.get(usage, :input_tokens, 0) + Map.get(usage, :output_tokens, 0)
+  end
+
+  defp tokens_used(_), do: 0
+
   defp build_system_prompt(role, swarm_id) do
     role_prompt = Roster.role_prompt(role)
 
@@ -27,7 +27,14 @@ defmodule OptimalSystemAgent.Tools.Builtins.ShellExecute do
   end
 
   @impl true
-  def execute(%{"command" => command}) do
+  def execute(args), do: execute(args, [])
+
+  @doc """
+  Execute the command, passing `:on_output` on to `Sandbox.Executor` so the
+  caller sees the output while the command runs. `:env` is a map of extra
+  environment variables for the command.
+  """
+  def execute(%{"command" => command}, opts) do
     # Strip trailing & (background operator) to force foreground execution
     command = Regex.replace(~r/\s*&\s*$/, command, "")
 
@@ -46,7 +53,11 @@ defmodule OptimalSystemAgent.Tools.Builtins.ShellExecute do
 
           Logger.debug("[ShellExecute] Dispatching command via Sandbox.Executor")
 
-          case Executor.execute(trimmed, workspace: workspace, cwd: workspace) do
+          exec_opts =
+            [workspace: workspace, cwd: workspace, extra_env: extra_env(opts)] ++
+              Keyword.take(opts, [:on_output])
+
+          case Executor.execute(trimmed, exec_opts) do
             {:ok, output, 0} -> {:ok, maybe_truncate(output)}
             {:ok, output, code} -> {:error, "Exit #{code}:\n#{maybe_truncate(output)}"}
             {:error, reason} -> {:error, reason}
@@ -58,6 +69,12 @@ defmodule OptimalSystemAgent.Tools.Builtins.ShellExecute do
     end
   end
 
+  defp extra_env(opts) do
+    opts
+    |> Keyword.get(:env, %{})
+    |> Enum.map(fn {k, v} -> {to_string(k), to_string(v)} end)
+  end
+
   defp maybe_truncate(output) do
     if byte_size(output) > @max_output_bytes do
       String.slice(output, 0, @max_output_bytes) <> "\n[output truncated at 100KB]"
@@ -39,7 +39,7 @@ defmodule OptimalSystemAgent.Tools.Builtins.WebFetch do
       case :httpc.request(:get, {url_charlist, headers}, http_opts, opts) do
         {:ok, {{_, status, _}, _headers, body}} when status in 200..299 ->
           text = body |> strip_html() |> String.slice(0, @max_body_bytes)
-          {:ok, "#{prompt} from #{url}:\n\n#{text}"}
+          {:ok, "#{prompt} from #{url}:\n#{title_line(body)}\n#{text}"}
         {:ok, {{_, status, _}, _headers, _body}} ->
           {:error, "HTTP #{status} from #{url}"}
         {:error, reason} ->
@@ -80,6 +80,18 @@ defmodule OptimalSystemAgent.Tools.Builtins.WebFetch do
     [verify: :verify_peer, cacerts: :public_key.cacerts_get(), depth: 3]
   end
 
+  # The page's <title> as a "Title: ..." line, or "" when it has none.
+  defp title_line(body) when is_binary(body) do
+    with [_, title] <- Regex.run(~r/<title[^>]*>([\s\S]*?)<\/title>/i, body),
+         title when title != "" <- strip_html(title) do
+      "Title: #{title}\n"
+    else
+      _ -> ""
+    end
+  end
+
+  defp title_line(_), do: ""
+
   defp strip_html(body) when is_binary(body) do
     body
     |> String.replace(~r/<script[^>]*>[\s\S]*?<\/script>/i, "")
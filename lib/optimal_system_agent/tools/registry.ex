@@ -88,9 +88,14 @@ defmodule OptimalSystemAgent.Tools.Registry do
     GenServer.call(__MODULE__, {:search, query})
   end
 
-  @doc "Execute a tool by name with given arguments."
-  def execute(tool_name, arguments) do
-    GenServer.call(__MODULE__, {:execute, tool_name, arguments}, 60_000)
+  @doc """
+  Execute a tool by name with given arguments.
+
+  `opts` reach tools that export `execute/2`, such as `:on_output` for
+  streaming a shell command's output; other tools ignore them.
+  """
+  def execute(tool_name, arguments, opts \\ []) do
+    GenServer.call(__MODULE__, {:execute, tool_name, arguments, opts}, 60_000)
   end
 
   @doc """
@@ -354,11 +359,14 @@ defmodule OptimalSystemAgent.Tools.Registry do
     {:reply, results, state}
   end
 
-  def handle_call({:execute, tool_name, arguments}, _from, state) do
+  def handle_call({:execute, tool_name, arguments, opts}, _from, state) do
     result =
       case Map.get(state.builtin_tools, tool_name) do
         nil -> {:error, "Unknown tool: #{tool_name}"}
-        mod -> mod.execute(arguments)
+        mod ->
+          if function_exported?(mod, :execute, 2),
+            do: mod.execute(arguments, opts),
+            else: mod.execute(arguments)
       end
 
     {:reply, result, state}
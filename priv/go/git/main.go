@@ -2,15 +2,41 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	linediff "github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
 // Request is a JSON-RPC request read from stdin.
@@ -27,10 +53,31 @@ type Response struct {
 	Error  *RPCError   `json:"error,omitempty"`
 }
 
-// RPCError represents a JSON-RPC error object.
+// ChunkFrame carries a piece of the JSON of a result too large for one
+// line. Seq counts the frames of a response from 0.
+type ChunkFrame struct {
+	ID    string `json:"id"`
+	Seq   int    `json:"seq"`
+	Chunk string `json:"result_chunk"`
+}
+
+// EndFrame follows the last ChunkFrame of a response, with their number.
+type EndFrame struct {
+	ID  string     `json:"id"`
+	End ChunkCount `json:"result_end"`
+}
+
+// ChunkCount is the number of ChunkFrames an EndFrame closes.
+type ChunkCount struct {
+	Chunks int `json:"chunks"`
+}
+
+// RPCError represents a JSON-RPC error object. Data is set for errors
+// callers can act on, such as RemoteErrorData.
 type RPCError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 // PathParams holds a path parameter (used by most methods).
@@ -50,10 +97,13 @@ type LogParams struct {
 	Limit int    `json:"limit"`
 }
 
-// FileStatus represents a single changed file in git_status.
+// FileStatus represents a single changed file in git_status. Submodule
+// marks a submodule whose checked out commit is not the pinned one, which
+// git_submodules tells more about.
 type FileStatus struct {
-	Path   string `json:"path"`
-	Status string `json:"status"`
+	Path      string `json:"path"`
+	Status    string `json:"status"`
+	Submodule bool   `json:"submodule,omitempty"`
 }
 
 // StatusResult is returned by git_status.
@@ -94,16 +144,594 @@ type BlameResult struct {
 	Lines []BlameLine `json:"lines"`
 }
 
+// FileLogParams holds path + file for git_file_log. Rev is the revision to
+// walk back from (default HEAD); Follow continues the history of a file
+// across the commits that renamed it.
+type FileLogParams struct {
+	Path   string `json:"path"`
+	File   string `json:"file"`
+	Rev    string `json:"rev"`
+	Limit  int    `json:"limit"`
+	Follow bool   `json:"follow"`
+}
+
+// FileLogEntry represents a commit in git_file_log. File is the path the
+// commit changed, which differs from the requested one before a rename;
+// Change is "added", "modified", "deleted" or "renamed", with OldFile
+// set for renames.
+type FileLogEntry struct {
+	CommitEntry
+	File    string `json:"file"`
+	OldFile string `json:"old_file,omitempty"`
+	Change  string `json:"change"`
+}
+
+// FileLogResult is returned by git_file_log, newest commit first.
+type FileLogResult struct {
+	File    string         `json:"file"`
+	Commits []FileLogEntry `json:"commits"`
+}
+
+// ShowParams holds path + revision for git_show. MaxBytes caps the patch
+// (default defaultShowBytes).
+type ShowParams struct {
+	Path     string `json:"path"`
+	Rev      string `json:"rev"`
+	MaxBytes int    `json:"max_bytes"`
+}
+
+// ShowFile is a file a commit changed, with its added and deleted lines.
+// OldFile is set for renames; Binary files have no line counts.
+type ShowFile struct {
+	File      string `json:"file"`
+	OldFile   string `json:"old_file,omitempty"`
+	Change    string `json:"change"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+}
+
+// ShowResult is returned by git_show. The patch is against the first
+// parent, or empty for a root commit, and is cut at a line boundary with
+// Truncated set when it exceeds the limit; Files always lists every file.
+type ShowResult struct {
+	Hash           string     `json:"hash"`
+	Parents        []string   `json:"parents"`
+	Author         string     `json:"author"`
+	AuthorEmail    string     `json:"author_email"`
+	AuthorDate     string     `json:"author_date"`
+	Committer      string     `json:"committer"`
+	CommitterEmail string     `json:"committer_email"`
+	CommitDate     string     `json:"commit_date"`
+	Message        string     `json:"message"`
+	Files          []ShowFile `json:"files"`
+	Patch          string     `json:"patch"`
+	Truncated      bool       `json:"truncated"`
+}
+
+// defaultShowBytes is the patch size git_show returns by default, well
+// below the line length the caller reads a response in.
+const defaultShowBytes = 256 * 1024
+
+// SearchParams holds path + what to look for for git_search, from Rev
+// (default HEAD) back. Query matches the commit message and Author the
+// author's name or email, both ignoring case; Pickaxe finds the commits
+// that change how often a string occurs in a file, as git log -S does.
+// What is given must all match. Offset skips that many matching commits
+// and Limit (default 20) caps those returned.
+type SearchParams struct {
+	Path    string `json:"path"`
+	Rev     string `json:"rev"`
+	Query   string `json:"query"`
+	Author  string `json:"author"`
+	Pickaxe string `json:"pickaxe"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+}
+
+// SearchEntry is a commit git_search found. Files are those a Pickaxe
+// search found the string added to or removed from.
+type SearchEntry struct {
+	CommitEntry
+	AuthorEmail string   `json:"author_email"`
+	Files       []string `json:"files,omitempty"`
+}
+
+// SearchResult is returned by git_search, newest commit first. More is set
+// when there are matching commits past the ones returned.
+type SearchResult struct {
+	Commits []SearchEntry `json:"commits"`
+	More    bool          `json:"more"`
+}
+
+// maxPickaxeBytes is the largest file a git_search pickaxe looks into.
+const maxPickaxeBytes = 1 << 20
+
+// BranchParams holds path + branch name for git_branch_create, git_checkout
+// and git_branch_delete. Start is the revision a new branch points at
+// (default HEAD); Create lets git_checkout create a missing branch; Force
+// discards local changes on checkout, or deletes an unmerged branch.
+type BranchParams struct {
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+	Start  string `json:"start"`
+	Create bool   `json:"create"`
+	Force  bool   `json:"force"`
+}
+
+// BranchEntry represents a single local branch. Upstream is the branch it
+// tracks, e.g. "origin/main"; Ahead and Behind count the commits on each
+// side that the other lacks. UpstreamGone is set when the tracked branch
+// no longer exists.
+type BranchEntry struct {
+	Name         string `json:"name"`
+	Hash         string `json:"hash"`
+	Current      bool   `json:"current"`
+	Upstream     string `json:"upstream,omitempty"`
+	UpstreamGone bool   `json:"upstream_gone,omitempty"`
+	Ahead        int    `json:"ahead"`
+	Behind       int    `json:"behind"`
+}
+
+// BranchListResult is returned by git_branch_list. Current is empty when
+// HEAD is detached.
+type BranchListResult struct {
+	Branches []BranchEntry `json:"branches"`
+	Current  string        `json:"current"`
+}
+
+// BranchDeleteResult is returned by git_branch_delete, with the hash the
+// branch pointed at so it can be recreated.
+type BranchDeleteResult struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+// TagListParams holds path + optional glob pattern, such as "v1.*", for
+// git_tag_list.
+type TagListParams struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+}
+
+// TagEntry is a tag. Hash is the commit it points at and Object the tag
+// object of an annotated tag. Date is when an annotated tag was made, or
+// the commit date for a lightweight one.
+type TagEntry struct {
+	Name        string `json:"name"`
+	Hash        string `json:"hash"`
+	Object      string `json:"object,omitempty"`
+	Annotated   bool   `json:"annotated"`
+	Tagger      string `json:"tagger,omitempty"`
+	TaggerEmail string `json:"tagger_email,omitempty"`
+	Date        string `json:"date"`
+	Message     string `json:"message,omitempty"`
+}
+
+// TagListResult is returned by git_tag_list, newest tag first.
+type TagListResult struct {
+	Tags []TagEntry `json:"tags"`
+}
+
+// TagCreateParams holds path + tag name for git_tag_create. Rev is what the
+// tag points at (default HEAD). A Message makes an annotated tag, tagged by
+// TaggerName and TaggerEmail (default user.name and user.email from the git
+// config); without one the tag is lightweight. Force replaces an existing
+// tag of the name.
+type TagCreateParams struct {
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Rev         string `json:"rev"`
+	Message     string `json:"message"`
+	TaggerName  string `json:"tagger_name"`
+	TaggerEmail string `json:"tagger_email"`
+	Force       bool   `json:"force"`
+}
+
+// DescribeParams holds path + revision for git_describe (default HEAD).
+// Tags lets lightweight tags describe it, not only annotated ones; Long
+// always gives the distance and hash, even on a tagged commit; Abbrev is
+// the hash length (default 7); Dirty marks a worktree with changes.
+type DescribeParams struct {
+	Path   string `json:"path"`
+	Rev    string `json:"rev"`
+	Tags   bool   `json:"tags"`
+	Long   bool   `json:"long"`
+	Abbrev int    `json:"abbrev"`
+	Dirty  bool   `json:"dirty"`
+}
+
+// DescribeResult is returned by git_describe: Describe is as git describe
+// prints it, e.g. "v1.2.0-3-gabc1234", from the nearest Tag and the commits
+// since it.
+type DescribeResult struct {
+	Describe string `json:"describe"`
+	Tag      string `json:"tag"`
+	Distance int    `json:"distance"`
+	Hash     string `json:"hash"`
+	Dirty    bool   `json:"dirty"`
+}
+
+// describeCandidates is how many of the nearest tagged commits git_describe
+// compares, as git does.
+const describeCandidates = 10
+
+// SubmoduleEntry is a submodule of the repository. Expected is the commit
+// the repository pins it at and Current the one checked out, empty until it
+// is initialized; InSync is set when they agree. Dirty marks uncommitted
+// changes inside the submodule.
+type SubmoduleEntry struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	Branch      string `json:"branch,omitempty"`
+	Expected    string `json:"expected"`
+	Current     string `json:"current,omitempty"`
+	Initialized bool   `json:"initialized"`
+	InSync      bool   `json:"in_sync"`
+	Dirty       bool   `json:"dirty"`
+}
+
+// SubmodulesResult is returned by git_submodules, ordered by path.
+type SubmodulesResult struct {
+	Submodules []SubmoduleEntry `json:"submodules"`
+}
+
+// SubmoduleUpdateParams holds path for git_submodule_update, which checks
+// out the pinned commit of the submodules at Paths (default all), fetching
+// it unless NoFetch is set. Init initializes submodules that are not yet;
+// Recursive updates the submodules of submodules too. Auth is as for
+// git_fetch. Submodules with uncommitted changes are refused.
+type SubmoduleUpdateParams struct {
+	Path      string      `json:"path"`
+	Paths     []string    `json:"paths"`
+	Init      bool        `json:"init"`
+	Recursive bool        `json:"recursive"`
+	NoFetch   bool        `json:"no_fetch"`
+	Auth      *AuthParams `json:"auth"`
+}
+
+// submoduleDepth caps how deep a recursive git_submodule_update goes.
+const submoduleDepth = 10
+
+// AuthParams are the credentials of a remote operation. Method "ssh_agent"
+// signs with the keys of the agent at SSH_AUTH_SOCK as User (default "git");
+// "token" sends Token over HTTPS as the password of User (default "git",
+// which GitHub and Gitea accept; GitLab wants "oauth2"). Without auth, SSH
+// remotes still try the agent and HTTPS remotes are accessed anonymously.
+type AuthParams struct {
+	Method string `json:"method"`
+	User   string `json:"user"`
+	Token  string `json:"token"`
+}
+
+// RemoteParams holds path + remote for git_fetch, git_pull and git_push.
+// Remote defaults to the current branch's upstream remote, else "origin".
+// Branch is the remote branch git_pull merges (default the upstream) or the
+// local branch git_push pushes (default the current one). Force lets a fetch
+// or pull move remote-tracking branches backwards and a push overwrite the
+// remote branch; Prune drops remote-tracking branches gone from the remote;
+// SetUpstream makes the pushed branch track the remote one.
+type RemoteParams struct {
+	Path        string      `json:"path"`
+	Remote      string      `json:"remote"`
+	Branch      string      `json:"branch"`
+	Force       bool        `json:"force"`
+	Prune       bool        `json:"prune"`
+	SetUpstream bool        `json:"set_upstream"`
+	Auth        *AuthParams `json:"auth"`
+}
+
+// RefUpdate is a remote-tracking branch or tag a remote operation changed.
+// Old is empty for a new ref and New for a deleted one.
+type RefUpdate struct {
+	Name string `json:"name"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// RemoteResult is returned by git_fetch and git_push. UpToDate is set when
+// no ref changed.
+type RemoteResult struct {
+	Remote   string      `json:"remote"`
+	Updated  []RefUpdate `json:"updated"`
+	UpToDate bool        `json:"up_to_date"`
+}
+
+// PullResult is returned by git_pull: the refs the fetch changed, and the
+// commits the pulled branch moved from and to.
+type PullResult struct {
+	RemoteResult
+	Branch string `json:"branch"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// RemoteErrorData is the data of a codeRemote error. Kind is one of
+// "auth_required" (the remote wants credentials), "auth_failed" (it
+// rejected them), "permission_denied" (they lack access), "auth_unavailable"
+// (no SSH agent is running), "invalid_auth" (the method does not suit the
+// remote's URL), "host_key" (the SSH host key is unknown or changed),
+// "not_found", "non_fast_forward", "rejected" (the remote refused the push)
+// or "timeout".
+type RemoteErrorData struct {
+	Kind   string `json:"kind"`
+	Remote string `json:"remote"`
+	URL    string `json:"url,omitempty"`
+}
+
+// codeRemote is the error code of a remote operation that failed for a
+// reason in RemoteErrorData.
+const codeRemote = -2
+
+// remoteTimeout bounds a fetch, pull or push, so an unreachable remote does
+// not hold up the requests queued behind it.
+const remoteTimeout = 2 * time.Minute
+
+// ConflictsParams holds path + optional files for git_conflicts, which
+// lists every conflicted file when Files is empty. MaxBytes caps the content
+// of each side (default defaultConflictBytes).
+type ConflictsParams struct {
+	Path     string   `json:"path"`
+	Files    []string `json:"files"`
+	MaxBytes int      `json:"max_bytes"`
+}
+
+// ConflictSide is one version of a conflicted file as staged by the merge.
+// Binary files have no content; Truncated is set when the content was cut
+// at a line boundary to fit the limit.
+type ConflictSide struct {
+	Hash      string `json:"hash"`
+	Content   string `json:"content,omitempty"`
+	Binary    bool   `json:"binary,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// ConflictMarker is a conflict hunk in the worktree file, by the 1-based
+// lines of its "<<<<<<<", "|||||||" (diff3 style only), "=======" and
+// ">>>>>>>" markers.
+type ConflictMarker struct {
+	Start     int `json:"start"`
+	Base      int `json:"base,omitempty"`
+	Separator int `json:"separator"`
+	End       int `json:"end"`
+}
+
+// ConflictFile is a conflicted path. Kind is "both_modified", "both_added",
+// "deleted_by_us", "deleted_by_them", "added_by_us", "added_by_them" or
+// "both_deleted", as git status names them. Base is the common ancestor,
+// Ours the checked out side and Theirs the side merged in; a side is missing
+// when it has no such file. Markers is empty when the worktree file has none
+// left, or is missing.
+type ConflictFile struct {
+	File    string           `json:"file"`
+	Kind    string           `json:"kind"`
+	Base    *ConflictSide    `json:"base,omitempty"`
+	Ours    *ConflictSide    `json:"ours,omitempty"`
+	Theirs  *ConflictSide    `json:"theirs,omitempty"`
+	Markers []ConflictMarker `json:"markers"`
+}
+
+// ConflictsResult is returned by git_conflicts. Operation is the operation
+// in progress: "merge", "rebase", "cherry_pick", "revert", or "" for none.
+type ConflictsResult struct {
+	Operation string         `json:"operation"`
+	Conflicts []ConflictFile `json:"conflicts"`
+}
+
+// defaultConflictBytes is the content git_conflicts returns of each side
+// by default.
+const defaultConflictBytes = 64 * 1024
+
+// Resolution is the resolved version of a conflicted file for git_resolve:
+// Content to write and stage, or with Content nil the worktree file as it
+// is, or with Delete the file removed. Content still holding conflict
+// markers is refused unless Force is set.
+type Resolution struct {
+	File    string  `json:"file"`
+	Content *string `json:"content"`
+	Delete  bool    `json:"delete"`
+	Force   bool    `json:"force"`
+}
+
+// ResolveParams holds path + resolutions for git_resolve.
+type ResolveParams struct {
+	Path  string       `json:"path"`
+	Files []Resolution `json:"files"`
+}
+
+// ResolveResult is returned by git_resolve, with the files still conflicted.
+type ResolveResult struct {
+	Resolved  []string `json:"resolved"`
+	Remaining []string `json:"remaining"`
+}
+
+// CheckIgnoreParams holds path + files for git_check_ignore. Files are
+// relative to the worktree root and need not exist yet.
+type CheckIgnoreParams struct {
+	Path  string   `json:"path"`
+	Files []string `json:"files"`
+}
+
+// IgnoreRule is an ignore pattern as written, with the file it is in and
+// its 1-based line. Source is a .gitignore by its path in the worktree,
+// ".git/info/exclude", or the path of the excludes file of the user.
+type IgnoreRule struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line"`
+	Pattern string `json:"pattern"`
+}
+
+// IgnoreStatus is whether a file is ignored. Rule is the rule that decides
+// it, on the file or a directory it is in; a negated "!" rule is reported
+// for a file that is not ignored because of it. A tracked file is never
+// ignored, whatever the rules say, as git keeps tracking it.
+type IgnoreStatus struct {
+	File    string      `json:"file"`
+	Ignored bool        `json:"ignored"`
+	Tracked bool        `json:"tracked"`
+	Rule    *IgnoreRule `json:"rule,omitempty"`
+}
+
+// CheckIgnoreResult is returned by git_check_ignore, a status per file in
+// the order asked.
+type CheckIgnoreResult struct {
+	Files []IgnoreStatus `json:"files"`
+}
+
+// LsFilesParams holds path + optional glob pattern for git_ls_files. A
+// pattern without a "/", such as "*.ex", matches file names in any
+// directory; one with a "/" matches the whole path, with "**" standing for
+// any number of directories, as in "lib/**/*_test.exs". Limit (default
+// 1000) caps the files returned.
+type LsFilesParams struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+	Limit   int    `json:"limit"`
+}
+
+// LsFilesResult is returned by git_ls_files: the tracked files matching,
+// sorted, and how many there are. More is set when the limit cut the list
+// short.
+type LsFilesResult struct {
+	Files []string `json:"files"`
+	Total int      `json:"total"`
+	More  bool     `json:"more"`
+}
+
+// ApplyParams holds path + a unified diff for git_apply, as git diff or
+// diff -u writes it. With Check nothing is changed, and the result tells
+// whether the patch applies. With ThreeWay, a file whose hunks no longer
+// match is merged instead: the hunks are applied to the version the patch
+// was made from, named by its "index" line or else the file as staged or
+// at HEAD, and the changes made to the file since are merged in.
+type ApplyParams struct {
+	Path     string `json:"path"`
+	Patch    string `json:"patch"`
+	Check    bool   `json:"check"`
+	ThreeWay bool   `json:"three_way"`
+}
+
+// ApplyHunk is how a hunk applied, by its "@@" header. Line is the 1-based
+// line of its first change in the patched file and Offset how many lines
+// the hunk was from where its header put it; Merged is set when it applied
+// by three-way merge. Error says why it did not apply.
+type ApplyHunk struct {
+	Header  string `json:"header"`
+	Applied bool   `json:"applied"`
+	Line    int    `json:"line,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+	Merged  bool   `json:"merged,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ApplyFile is how the patch of a file applied. Status is "modified",
+// "added", "deleted" or "renamed", with OldFile the name it had. Error is
+// set when the file itself is the problem, as for an added file that
+// already exists.
+type ApplyFile struct {
+	File    string      `json:"file"`
+	OldFile string      `json:"old_file,omitempty"`
+	Status  string      `json:"status"`
+	Applied bool        `json:"applied"`
+	Error   string      `json:"error,omitempty"`
+	Hunks   []ApplyHunk `json:"hunks"`
+}
+
+// ApplyResult is returned by git_apply. A patch applies whole or not at
+// all: Applied is set when every hunk of every file applies, and only then
+// is the worktree changed, unless Check was asked for.
+type ApplyResult struct {
+	Applied bool        `json:"applied"`
+	Files   []ApplyFile `json:"files"`
+}
+
+// RemoteInfo is a configured remote with its URLs, passwords masked; the
+// first is fetched from and all are pushed to.
+type RemoteInfo struct {
+	Name string   `json:"name"`
+	URLs []string `json:"urls"`
+}
+
+// InfoResult is returned by git_info. Root is the worktree, or the
+// repository directory of a bare repository. Branch is the checked out
+// branch, set even before its first commit, and "" when HEAD is detached;
+// Head is "" before the first commit. Upstream, Ahead and Behind are as in
+// BranchEntry. LastCommit is HEAD, its Message cut to the first line.
+// Operation is as in ConflictsResult.
+type InfoResult struct {
+	Root         string       `json:"root"`
+	GitDir       string       `json:"git_dir"`
+	Bare         bool         `json:"bare"`
+	Remotes      []RemoteInfo `json:"remotes"`
+	Branch       string       `json:"branch"`
+	Detached     bool         `json:"detached"`
+	Head         string       `json:"head"`
+	Upstream     string       `json:"upstream,omitempty"`
+	UpstreamGone bool         `json:"upstream_gone,omitempty"`
+	Ahead        int          `json:"ahead"`
+	Behind       int          `json:"behind"`
+	LastCommit   *CommitEntry `json:"last_commit,omitempty"`
+	Operation    string       `json:"operation"`
+}
+
+// responseChunkBytes is the most of a result's JSON written as one line. A
+// larger result, such as a big diff or log, is written as result_chunk
+// frames holding successive pieces of its JSON, then a result_end frame, so
+// no line outgrows the reader's line buffer.
+const responseChunkBytes = 64 << 10
+
 var stdout = bufio.NewWriter(os.Stdout)
 
+// stdoutMu keeps the lines of responses written at once from interleaving.
+var stdoutMu sync.Mutex
+
 func writeResponse(resp Response) {
-	data, err := json.Marshal(resp)
+	if resp.Error == nil && resp.Result != nil {
+		result, err := json.Marshal(resp.Result)
+		if err != nil {
+			log.Printf("failed to marshal response: %v", err)
+			return
+		}
+		if len(result) > responseChunkBytes {
+			writeChunks(resp.ID, result)
+			return
+		}
+		resp.Result = json.RawMessage(result)
+	}
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	writeLine(resp)
+	stdout.Flush()
+}
+
+// writeChunks writes result as ChunkFrames, cut between UTF-8 sequences,
+// and the EndFrame.
+func writeChunks(id string, result []byte) {
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	n := 0
+	for len(result) > 0 {
+		end := min(len(result), responseChunkBytes)
+		for end < len(result) && !utf8.RuneStart(result[end]) {
+			end--
+		}
+		writeLine(ChunkFrame{ID: id, Seq: n, Chunk: string(result[:end])})
+		result = result[end:]
+		n++
+	}
+	writeLine(EndFrame{ID: id, End: ChunkCount{Chunks: n}})
+	stdout.Flush()
+}
+
+// writeLine writes v as one line of JSON.
+func writeLine(v interface{}) {
+	data, err := json.Marshal(v)
 	if err != nil {
 		log.Printf("failed to marshal response: %v", err)
 		return
 	}
 	fmt.Fprintf(stdout, "%s\n", data)
-	stdout.Flush()
 }
 
 func errorResponse(id string, code int, message string) Response {
@@ -137,12 +765,19 @@ func statusCodeString(code git.StatusCode) string {
 	}
 }
 
-// openRepo opens a git repository rooted at path. Falls back to ".".
+// openRepo opens a git repository rooted at path. Falls back to ".". A path
+// with no .git in it or above it may be a bare repository.
 func openRepo(path string) (*git.Repository, error) {
 	if path == "" {
 		path = "."
 	}
-	return git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if bare, bareErr := git.PlainOpen(path); bareErr == nil {
+			return bare, nil
+		}
+	}
+	return repo, err
 }
 
 func handleGitStatus(id string, params json.RawMessage) Response {
@@ -179,6 +814,13 @@ func handleGitStatus(id string, params json.RawMessage) Response {
 		}
 	}
 
+	submodules := make(map[string]bool)
+	if subs, err := wt.Submodules(); err == nil {
+		for _, sm := range subs {
+			submodules[sm.Config().Path] = true
+		}
+	}
+
 	files := make([]FileStatus, 0, len(status))
 	for filePath, fs := range status {
 		// Include any file that has a staging or worktree change.
@@ -192,7 +834,7 @@ func handleGitStatus(id string, params json.RawMessage) Response {
 		} else {
 			continue
 		}
-		files = append(files, FileStatus{Path: filePath, Status: label})
+		files = append(files, FileStatus{Path: filePath, Status: label, Submodule: submodules[filePath]})
 	}
 
 	return Response{
@@ -314,7 +956,7 @@ func splitLines(s string) []string {
 	return lines
 }
 
-func handleGitLog(id string, params json.RawMessage) Response {
+func handleGitLog(ctx context.Context, id string, params json.RawMessage) Response {
 	var p LogParams
 	if params != nil {
 		if err := json.Unmarshal(params, &p); err != nil {
@@ -347,12 +989,10 @@ func handleGitLog(id string, params json.RawMessage) Response {
 		if len(commits) >= p.Limit {
 			return fmt.Errorf("stop") // sentinel to break iteration
 		}
-		commits = append(commits, CommitEntry{
-			Hash:    c.Hash.String(),
-			Author:  c.Author.Name,
-			Message: c.Message,
-			Date:    c.Author.When.UTC().Format(time.RFC3339),
-		})
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		commits = append(commits, commitEntry(c))
 		return nil
 	})
 
@@ -411,53 +1051,3168 @@ func handleGitBlame(id string, params json.RawMessage) Response {
 	return Response{ID: id, Result: BlameResult{Lines: lines}}
 }
 
-func handleRequest(req Request) Response {
-	switch req.Method {
-	case "ping":
-		return Response{ID: req.ID, Result: "pong"}
-	case "git_status":
-		return handleGitStatus(req.ID, req.Params)
-	case "git_diff":
-		return handleGitDiff(req.ID, req.Params)
-	case "git_log":
-		return handleGitLog(req.ID, req.Params)
-	case "git_blame":
-		return handleGitBlame(req.ID, req.Params)
-	default:
-		return errorResponse(req.ID, -32601, fmt.Sprintf("unknown method: %s", req.Method))
+func handleGitFileLog(ctx context.Context, id string, params json.RawMessage) Response {
+	var p FileLogParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	p.File = strings.Trim(p.File, "/")
+	if p.File == "" {
+		return errorResponse(id, -32602, "missing required param: file")
+	}
+	if p.Limit <= 0 {
+		p.Limit = 10
 	}
-}
 
-func main() {
-	// Direct all library logging to stderr — stdout is protocol-only.
-	log.SetOutput(os.Stderr)
-	log.SetFlags(log.Ltime | log.Lshortfile)
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
 
-	log.Println("osa-git sidecar ready")
+	result := FileLogResult{File: p.File, Commits: []FileLogEntry{}}
+	start, err := resolveRev(repo, p.Rev)
+	if err == plumbing.ErrReferenceNotFound && p.Rev == "" {
+		// No commits yet.
+		return Response{ID: id, Result: result}
+	}
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	// 10MB buffer to handle large diffs in a single line.
-	scanner.Buffer(make([]byte, 0), 10*1024*1024)
+	iter, err := repo.Log(&git.LogOptions{From: start, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get log: %v", err))
+	}
+	defer iter.Close()
 
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
+	// Walk back from start, keeping the commits where the file differs from
+	// every parent. With Follow, a commit that adds the file under the name
+	// being tracked and removes another it resembles renamed it, and the
+	// older commits are searched for the old name.
+	file := p.File
+	errStop := errors.New("stop")
+	err = iter.ForEach(func(c *object.Commit) error {
+		if len(result.Commits) >= p.Limit {
+			return errStop
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+		cur, inCommit := entryHash(tree, file)
 
-		var req Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			log.Printf("failed to parse request: %v", err)
-			writeResponse(errorResponse("", -32700, fmt.Sprintf("parse error: %v", err)))
-			continue
+		var first *object.Tree
+		inFirst := false
+		same := false
+		for i, ph := range c.ParentHashes {
+			parent, err := repo.CommitObject(ph)
+			if err != nil {
+				return err
+			}
+			pt, err := parent.Tree()
+			if err != nil {
+				return err
+			}
+			h, ok := entryHash(pt, file)
+			if i == 0 {
+				first, inFirst = pt, ok
+			}
+			if ok == inCommit && h == cur {
+				same = true
+				break
+			}
+		}
+		if same || (!inCommit && !inFirst) {
+			return nil
 		}
 
-		resp := handleRequest(req)
-		writeResponse(resp)
+		entry := FileLogEntry{CommitEntry: commitEntry(c), File: file}
+		switch {
+		case !inCommit:
+			entry.Change = "deleted"
+		case !inFirst:
+			entry.Change = "added"
+			if p.Follow {
+				old, err := renamedFrom(ctx, first, tree, file)
+				if err != nil {
+					return err
+				}
+				if old != "" {
+					entry.Change, entry.OldFile = "renamed", old
+					file = old
+				}
+			}
+		default:
+			entry.Change = "modified"
+		}
+		result.Commits = append(result.Commits, entry)
+		return nil
+	})
+	if err != nil && err != errStop {
+		return errorResponse(id, -1, fmt.Sprintf("failed to walk history: %v", err))
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatalf("stdin scanner error: %v", err)
+	return Response{ID: id, Result: result}
+}
+
+func handleGitShow(ctx context.Context, id string, params json.RawMessage) Response {
+	var p ShowParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.MaxBytes <= 0 {
+		p.MaxBytes = defaultShowBytes
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	hash, err := resolveRev(repo, p.Rev)
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+	c, err := repo.CommitObject(hash)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get commit: %v", err))
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get commit tree: %v", err))
+	}
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get parent commit: %v", err))
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get parent tree: %v", err))
+		}
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("diff failed: %v", err))
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("diff failed: %v", err))
+	}
+
+	result := ShowResult{
+		Hash:           c.Hash.String(),
+		Parents:        make([]string, 0, c.NumParents()),
+		Author:         c.Author.Name,
+		AuthorEmail:    c.Author.Email,
+		AuthorDate:     c.Author.When.UTC().Format(time.RFC3339),
+		Committer:      c.Committer.Name,
+		CommitterEmail: c.Committer.Email,
+		CommitDate:     c.Committer.When.UTC().Format(time.RFC3339),
+		Message:        c.Message,
+		Files:          showFiles(patch),
+		Patch:          patch.String(),
+	}
+	for _, ph := range c.ParentHashes {
+		result.Parents = append(result.Parents, ph.String())
+	}
+	if len(result.Patch) > p.MaxBytes {
+		cut := strings.LastIndexByte(result.Patch[:p.MaxBytes], '\n')
+		result.Patch = result.Patch[:cut+1]
+		result.Truncated = true
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+func handleGitSearch(ctx context.Context, id string, params json.RawMessage) Response {
+	var p SearchParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.Query == "" && p.Author == "" && p.Pickaxe == "" {
+		return errorResponse(id, -32602, "one of query, author or pickaxe is required")
+	}
+	if p.Limit <= 0 {
+		p.Limit = 20
+	}
+	p.Offset = max(p.Offset, 0)
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	result := SearchResult{Commits: []SearchEntry{}}
+	start, err := resolveRev(repo, p.Rev)
+	if err == plumbing.ErrReferenceNotFound && p.Rev == "" {
+		// No commits yet.
+		return Response{ID: id, Result: result}
+	}
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: start, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get log: %v", err))
+	}
+	defer iter.Close()
+
+	query, author := strings.ToLower(p.Query), strings.ToLower(p.Author)
+	skipped := 0
+	errStop := errors.New("stop")
+	err = iter.ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if query != "" && !strings.Contains(strings.ToLower(c.Message), query) {
+			return nil
+		}
+		if author != "" && !strings.Contains(strings.ToLower(c.Author.Name), author) &&
+			!strings.Contains(strings.ToLower(c.Author.Email), author) {
+			return nil
+		}
+		var files []string
+		if p.Pickaxe != "" {
+			var err error
+			if files, err = pickaxeFiles(ctx, c, p.Pickaxe); err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return nil
+			}
+		}
+
+		switch {
+		case skipped < p.Offset:
+			skipped++
+		case len(result.Commits) == p.Limit:
+			result.More = true
+			return errStop
+		default:
+			result.Commits = append(result.Commits, SearchEntry{
+				CommitEntry: commitEntry(c),
+				AuthorEmail: c.Author.Email,
+				Files:       files,
+			})
+		}
+		return nil
+	})
+	if err != nil && err != errStop {
+		return errorResponse(id, -1, fmt.Sprintf("search failed: %v", err))
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+// pickaxeFiles returns the files in which c changes how often s occurs,
+// against its parent. Merge commits are left out, as git log -S does, and
+// so are binary files and those over maxPickaxeBytes.
+func pickaxeFiles(ctx context.Context, c *object.Commit, s string) ([]string, error) {
+	if c.NumParents() > 1 {
+		return nil, nil
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parentTree *object.Tree
+	if c.NumParents() == 1 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, parentTree, tree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, ch := range changes {
+		from, to, err := ch.Files()
+		if err != nil {
+			return nil, err
+		}
+		before, err := occurrences(from, s)
+		if err != nil {
+			return nil, err
+		}
+		after, err := occurrences(to, s)
+		if err != nil {
+			return nil, err
+		}
+		if before != after {
+			name := ch.To.Name
+			if name == "" {
+				name = ch.From.Name
+			}
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// occurrences counts s in f, which is 0 for a missing, binary or too large
+// file.
+func occurrences(f *object.File, s string) (int, error) {
+	if f == nil || f.Size > maxPickaxeBytes {
+		return 0, nil
+	}
+	if bin, err := f.IsBinary(); err != nil || bin {
+		return 0, err
+	}
+	content, err := f.Contents()
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(content, s), nil
+}
+
+// resolveRev returns the commit a revision such as a hash, branch, tag or
+// "HEAD~2" names; "" is HEAD.
+func resolveRev(repo *git.Repository, rev string) (plumbing.Hash, error) {
+	if rev == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return head.Hash(), nil
+	}
+	h, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unknown revision %q: %v", rev, err)
+	}
+	return *h, nil
+}
+
+// commitEntry describes c as listed by git_log.
+func commitEntry(c *object.Commit) CommitEntry {
+	return CommitEntry{
+		Hash:    c.Hash.String(),
+		Author:  c.Author.Name,
+		Message: c.Message,
+		Date:    c.Author.When.UTC().Format(time.RFC3339),
+	}
+}
+
+// entryHash returns the hash of the file or directory at path in tree.
+func entryHash(tree *object.Tree, path string) (plumbing.Hash, bool) {
+	if tree == nil {
+		return plumbing.ZeroHash, false
+	}
+	e, err := tree.FindEntry(path)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	return e.Hash, true
+}
+
+// renamedFrom returns the path file had in from when the change to to
+// renamed it, or "" when it was added.
+func renamedFrom(ctx context.Context, from, to *object.Tree, file string) (string, error) {
+	if from == nil {
+		return "", nil
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, from, to, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", err
+	}
+	for _, ch := range changes {
+		if ch.To.Name == file && ch.From.Name != "" && ch.From.Name != file {
+			return ch.From.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// showFiles lists the files of patch with their line counts.
+func showFiles(patch *object.Patch) []ShowFile {
+	fps := patch.FilePatches()
+	files := make([]ShowFile, 0, len(fps))
+	for _, fp := range fps {
+		from, to := fp.Files()
+		var f ShowFile
+		switch {
+		case from == nil:
+			f.File, f.Change = to.Path(), "added"
+		case to == nil:
+			f.File, f.Change = from.Path(), "deleted"
+		case from.Path() != to.Path():
+			f.File, f.OldFile, f.Change = to.Path(), from.Path(), "renamed"
+		default:
+			f.File, f.Change = to.Path(), "modified"
+		}
+		f.Binary = fp.IsBinary()
+		for _, chunk := range fp.Chunks() {
+			n := strings.Count(chunk.Content(), "\n")
+			if s := chunk.Content(); s != "" && s[len(s)-1] != '\n' {
+				n++
+			}
+			switch chunk.Type() {
+			case diff.Add:
+				f.Additions += n
+			case diff.Delete:
+				f.Deletions += n
+			}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+func handleGitBranchList(id string, params json.RawMessage) Response {
+	var p PathParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+
+	iter, err := repo.Branches()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to list branches: %v", err))
+	}
+	defer iter.Close()
+
+	current := currentBranch(repo)
+	branches := []BranchEntry{}
+	_ = iter.ForEach(func(ref *plumbing.Reference) error {
+		branches = append(branches, branchEntry(repo, cfg, ref, current))
+		return nil
+	})
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+
+	return Response{ID: id, Result: BranchListResult{Branches: branches, Current: current}}
+}
+
+func handleGitBranchCreate(id string, params json.RawMessage) Response {
+	p, errResp := branchParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	ref, err := createBranch(repo, p.Name, p.Start)
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+	return branchResponse(id, repo, ref)
+}
+
+func handleGitCheckout(id string, params json.RawMessage) Response {
+	p, errResp := branchParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+
+	// go-git moves HEAD before it notices local changes, so refuse up front
+	// rather than leave the worktree half switched.
+	if !p.Force {
+		status, err := wt.Status()
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get status: %v", err))
+		}
+		if hasLocalChanges(status) {
+			return errorResponse(id, -1, "worktree has uncommitted changes; commit them first or pass force to discard them")
+		}
+	}
+
+	refName := plumbing.NewBranchReferenceName(p.Name)
+	ref, err := repo.Reference(refName, true)
+	switch {
+	case err == nil:
+	case !errors.Is(err, plumbing.ErrReferenceNotFound):
+		return errorResponse(id, -1, fmt.Sprintf("failed to resolve branch: %v", err))
+	default:
+		// Like git, a branch that only exists on one remote is created
+		// locally to track it; any other missing branch needs create.
+		start := p.Start
+		if !p.Create {
+			if start = remoteBranch(repo, p.Name); start == "" {
+				return errorResponse(id, -1, fmt.Sprintf("branch not found: %s", p.Name))
+			}
+		}
+		if ref, err = createBranch(repo, p.Name, start); err != nil {
+			return errorResponse(id, -1, err.Error())
+		}
+	}
+
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: ref.Name(), Force: p.Force}); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("checkout failed: %v", err))
+	}
+	return branchResponse(id, repo, ref)
+}
+
+func handleGitBranchDelete(id string, params json.RawMessage) Response {
+	p, errResp := branchParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(p.Name), true)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("branch not found: %s", p.Name))
+	}
+	if currentBranch(repo) == p.Name {
+		return errorResponse(id, -1, fmt.Sprintf("cannot delete the checked out branch: %s", p.Name))
+	}
+
+	if !p.Force {
+		merged, err := mergedIntoHead(repo, ref.Hash())
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to check merge state: %v", err))
+		}
+		if !merged {
+			return errorResponse(id, -1, fmt.Sprintf("branch %s is not merged into HEAD; pass force to delete it anyway", p.Name))
+		}
+	}
+
+	if err := repo.Storer.RemoveReference(ref.Name()); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to delete branch: %v", err))
+	}
+	// Drop its tracking config too; a branch without any has none to drop.
+	if err := repo.DeleteBranch(p.Name); err != nil && !errors.Is(err, git.ErrBranchNotFound) {
+		log.Printf("failed to remove config of branch %s: %v", p.Name, err)
+	}
+
+	return Response{ID: id, Result: BranchDeleteResult{Name: p.Name, Hash: ref.Hash().String()}}
+}
+
+// branchParams decodes and validates the params of the branch methods. It
+// returns a response to send instead when they are invalid.
+func branchParams(id string, params json.RawMessage) (BranchParams, *Response) {
+	var p BranchParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			resp := errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+			return p, &resp
+		}
+	}
+	if p.Name == "" {
+		resp := errorResponse(id, -32602, "missing required param: name")
+		return p, &resp
+	}
+	if err := plumbing.NewBranchReferenceName(p.Name).Validate(); err != nil {
+		resp := errorResponse(id, -32602, fmt.Sprintf("invalid branch name %q: %v", p.Name, err))
+		return p, &resp
+	}
+	return p, nil
+}
+
+// branchResponse returns the branch list entry of ref.
+func branchResponse(id string, repo *git.Repository, ref *plumbing.Reference) Response {
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+	return Response{ID: id, Result: branchEntry(repo, cfg, ref, currentBranch(repo))}
+}
+
+// currentBranch returns the checked out branch, or "" when HEAD is detached
+// or the repository has no commits.
+func currentBranch(repo *git.Repository) string {
+	head, err := repo.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return ""
+	}
+	return head.Name().Short()
+}
+
+// branchEntry describes the branch ref with its upstream and how far the two
+// have diverged.
+func branchEntry(repo *git.Repository, cfg *config.Config, ref *plumbing.Reference, current string) BranchEntry {
+	name := ref.Name().Short()
+	entry := BranchEntry{Name: name, Hash: ref.Hash().String(), Current: name == current}
+
+	b, ok := cfg.Branches[name]
+	if !ok || b.Merge == "" {
+		return entry
+	}
+	upstreamRef := b.Merge
+	entry.Upstream = b.Merge.Short()
+	if b.Remote != "" && b.Remote != "." {
+		upstreamRef = plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short())
+		entry.Upstream = b.Remote + "/" + b.Merge.Short()
+	}
+
+	upstream, err := repo.Reference(upstreamRef, true)
+	if err != nil {
+		entry.UpstreamGone = true
+		return entry
+	}
+	ahead, behind, err := aheadBehind(repo, ref.Hash(), upstream.Hash())
+	if err != nil {
+		log.Printf("failed to compare %s with %s: %v", name, entry.Upstream, err)
+		return entry
+	}
+	entry.Ahead, entry.Behind = ahead, behind
+	return entry
+}
+
+// aheadBehind counts the commits reachable from local but not upstream, and
+// the reverse. Both histories are walked newest first, marking each commit
+// with the sides it is reachable from, until only commits reachable from
+// both are left to walk.
+func aheadBehind(repo *git.Repository, local, upstream plumbing.Hash) (ahead, behind int, err error) {
+	const (
+		fromLocal uint8 = 1 << iota
+		fromUpstream
+		fromBoth = fromLocal | fromUpstream
+	)
+	flags := make(map[plumbing.Hash]uint8)
+	var queue []*object.Commit
+	mark := func(h plumbing.Hash, f uint8) error {
+		if flags[h]&f == f {
+			return nil
+		}
+		flags[h] |= f
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, c)
+		return nil
+	}
+
+	if err := mark(local, fromLocal); err != nil {
+		return 0, 0, err
+	}
+	if err := mark(upstream, fromUpstream); err != nil {
+		return 0, 0, err
+	}
+	for len(queue) > 0 {
+		newest, shared := 0, true
+		for i, c := range queue {
+			if flags[c.Hash] != fromBoth {
+				shared = false
+			}
+			if c.Committer.When.After(queue[newest].Committer.When) {
+				newest = i
+			}
+		}
+		if shared {
+			break
+		}
+		c := queue[newest]
+		queue = append(queue[:newest], queue[newest+1:]...)
+		for _, parent := range c.ParentHashes {
+			if err := mark(parent, flags[c.Hash]); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	for _, f := range flags {
+		switch f {
+		case fromLocal:
+			ahead++
+		case fromUpstream:
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
+
+// createBranch creates the branch name at the revision start (HEAD when
+// empty). A branch started from a remote-tracking branch tracks it.
+func createBranch(repo *git.Repository, name, start string) (*plumbing.Reference, error) {
+	refName := plumbing.NewBranchReferenceName(name)
+	if _, err := repo.Reference(refName, false); err == nil {
+		return nil, fmt.Errorf("a branch named %s already exists", name)
+	}
+	if start == "" {
+		start = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(start))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %v", start, err)
+	}
+
+	ref := plumbing.NewHashReference(refName, *hash)
+	if err := repo.Storer.SetReference(ref); err != nil {
+		return nil, fmt.Errorf("failed to create branch: %v", err)
+	}
+
+	if remote, branch, ok := strings.Cut(start, "/"); ok {
+		if _, err := repo.Reference(plumbing.NewRemoteReferenceName(remote, branch), false); err == nil {
+			err := repo.CreateBranch(&config.Branch{Name: name, Remote: remote, Merge: plumbing.NewBranchReferenceName(branch)})
+			if err != nil {
+				log.Printf("failed to set upstream of %s: %v", name, err)
+			}
+		}
+	}
+	return ref, nil
+}
+
+// remoteBranch returns "<remote>/<name>" when exactly one remote has a
+// branch called name.
+func remoteBranch(repo *git.Repository, name string) string {
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return ""
+	}
+	found := ""
+	for _, r := range remotes {
+		remote := r.Config().Name
+		if _, err := repo.Reference(plumbing.NewRemoteReferenceName(remote, name), false); err == nil {
+			if found != "" {
+				return ""
+			}
+			found = remote + "/" + name
+		}
+	}
+	return found
+}
+
+// hasLocalChanges reports whether a checkout could overwrite changes: any
+// staged change, or a tracked file changed in the worktree.
+func hasLocalChanges(status git.Status) bool {
+	for _, fs := range status {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked {
+			return true
+		}
+		if fs.Worktree != git.Unmodified && fs.Worktree != git.Untracked {
+			return true
+		}
+	}
+	return false
+}
+
+// mergedIntoHead reports whether the commit hash is reachable from HEAD.
+func mergedIntoHead(repo *git.Repository, hash plumbing.Hash) (bool, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return false, err
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, err
+	}
+	c, err := repo.CommitObject(hash)
+	if err != nil {
+		return false, err
+	}
+	return c.IsAncestor(headCommit)
+}
+
+func handleGitTagList(id string, params json.RawMessage) Response {
+	var p TagListParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if _, err := path.Match(p.Pattern, ""); err != nil {
+		return errorResponse(id, -32602, fmt.Sprintf("invalid pattern: %v", err))
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	tags, err := listTags(repo)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to list tags: %v", err))
+	}
+	result := TagListResult{Tags: make([]TagEntry, 0, len(tags))}
+	for _, t := range tags {
+		if ok, _ := path.Match(p.Pattern, t.Name); p.Pattern == "" || ok {
+			result.Tags = append(result.Tags, t)
+		}
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+func handleGitTagCreate(id string, params json.RawMessage) Response {
+	var p TagCreateParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.Name == "" {
+		return errorResponse(id, -32602, "name is required")
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	hash, err := resolveRev(repo, p.Rev)
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+
+	var opts *git.CreateTagOptions
+	if p.Message != "" {
+		tagger, err := taggerSignature(repo, p.TaggerName, p.TaggerEmail)
+		if err != nil {
+			return errorResponse(id, -1, err.Error())
+		}
+		opts = &git.CreateTagOptions{Tagger: tagger, Message: p.Message}
+	}
+
+	refName := plumbing.NewTagReferenceName(p.Name)
+	if _, err := repo.Reference(refName, false); err == nil {
+		if !p.Force {
+			return errorResponse(id, -1, fmt.Sprintf("a tag named %s already exists", p.Name))
+		}
+		if err := repo.Storer.RemoveReference(refName); err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to replace tag: %v", err))
+		}
+	}
+	ref, err := repo.CreateTag(p.Name, hash, opts)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to create tag: %v", err))
+	}
+
+	entry, err := tagEntry(repo, ref)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read tag: %v", err))
+	}
+	return Response{ID: id, Result: entry}
+}
+
+func handleGitDescribe(id string, params json.RawMessage) Response {
+	var p DescribeParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.Abbrev <= 0 {
+		p.Abbrev = 7
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	from, err := resolveRev(repo, p.Rev)
+	if err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+	tags, err := listTags(repo)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to list tags: %v", err))
+	}
+
+	// The tag naming each commit: annotated before lightweight, then the
+	// newest, as listTags orders them.
+	byCommit := make(map[plumbing.Hash]string)
+	for _, t := range tags {
+		if !t.Annotated && !p.Tags {
+			continue
+		}
+		h := plumbing.NewHash(t.Hash)
+		if prev, ok := byCommit[h]; !ok || (t.Annotated && !tagAnnotated(tags, prev)) {
+			byCommit[h] = t.Name
+		}
+	}
+
+	tag, distance, err := nearestTag(repo, from, byCommit)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to walk history: %v", err))
+	}
+	if tag == "" {
+		msg := fmt.Sprintf("no annotated tags can describe %s", from)
+		if p.Tags {
+			msg = fmt.Sprintf("no tags can describe %s", from)
+		}
+		return errorResponse(id, -1, msg)
+	}
+
+	result := DescribeResult{Tag: tag, Distance: distance, Hash: from.String(), Describe: tag}
+	if distance > 0 || p.Long {
+		abbrev := from.String()
+		if p.Abbrev < len(abbrev) {
+			abbrev = abbrev[:p.Abbrev]
+		}
+		result.Describe = fmt.Sprintf("%s-%d-g%s", tag, distance, abbrev)
+	}
+	if p.Dirty {
+		wt, err := repo.Worktree()
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+		}
+		status, err := wt.Status()
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get status: %v", err))
+		}
+		if hasLocalChanges(status) {
+			result.Dirty = true
+			result.Describe += "-dirty"
+		}
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+// listTags returns the repository's tags, newest first.
+func listTags(repo *git.Repository) ([]TagEntry, error) {
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	tags := []TagEntry{}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		t, err := tagEntry(repo, ref)
+		if err != nil {
+			log.Printf("skipping tag %s: %v", ref.Name().Short(), err)
+			return nil
+		}
+		tags = append(tags, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Date != tags[j].Date {
+			return tags[i].Date > tags[j].Date
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+// tagEntry describes the tag ref, peeling an annotated tag, or a tag of a
+// tag, to the commit it names.
+func tagEntry(repo *git.Repository, ref *plumbing.Reference) (TagEntry, error) {
+	e := TagEntry{Name: ref.Name().Short(), Hash: ref.Hash().String()}
+	target := ref.Hash()
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		e.Object = tag.Hash.String()
+		e.Annotated = true
+		e.Tagger = tag.Tagger.Name
+		e.TaggerEmail = tag.Tagger.Email
+		e.Date = tag.Tagger.When.UTC().Format(time.RFC3339)
+		e.Message = tag.Message
+		for tag.TargetType == plumbing.TagObject {
+			if tag, err = repo.TagObject(tag.Target); err != nil {
+				return e, err
+			}
+		}
+		target = tag.Target
+		e.Hash = target.String()
+	} else if !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return e, err
+	}
+	if c, err := repo.CommitObject(target); err == nil && !e.Annotated {
+		e.Date = c.Committer.When.UTC().Format(time.RFC3339)
+	}
+	return e, nil
+}
+
+// tagAnnotated reports whether the tag called name is annotated.
+func tagAnnotated(tags []TagEntry, name string) bool {
+	for _, t := range tags {
+		if t.Name == name {
+			return t.Annotated
+		}
+	}
+	return false
+}
+
+// taggerSignature returns the tagger of an annotated tag, taking what is
+// not given from the user settings of the git config.
+func taggerSignature(repo *git.Repository, name, email string) (*object.Signature, error) {
+	if name == "" || email == "" {
+		cfg, err := repo.ConfigScoped(config.GlobalScope)
+		if err == nil {
+			if name == "" {
+				name = cfg.User.Name
+			}
+			if email == "" {
+				email = cfg.User.Email
+			}
+		}
+	}
+	if name == "" || email == "" {
+		return nil, errors.New("an annotated tag needs a tagger: pass tagger_name and tagger_email or set user.name and user.email")
+	}
+	return &object.Signature{Name: name, Email: email, When: time.Now()}, nil
+}
+
+// nearestTag returns the tag of byCommit that describes from, with the
+// commits from has that the tagged commit lacks. Of the first tagged
+// commits found going back from from, the one with the fewest such commits
+// wins. tag is "" when no tagged commit is reachable.
+func nearestTag(repo *git.Repository, from plumbing.Hash, byCommit map[plumbing.Hash]string) (tag string, distance int, err error) {
+	if name, ok := byCommit[from]; ok {
+		return name, 0, nil
+	}
+	var candidates []plumbing.Hash
+	seen := map[plumbing.Hash]bool{from: true}
+	queue := []plumbing.Hash{from}
+	for len(queue) > 0 && len(candidates) < describeCandidates {
+		c, err := repo.CommitObject(queue[0])
+		if err != nil {
+			return "", 0, err
+		}
+		queue = queue[1:]
+		for _, ph := range c.ParentHashes {
+			if seen[ph] {
+				continue
+			}
+			seen[ph] = true
+			if _, ok := byCommit[ph]; ok {
+				candidates = append(candidates, ph)
+			}
+			queue = append(queue, ph)
+		}
+	}
+
+	for _, cand := range candidates {
+		base, err := ancestors(repo, cand, nil)
+		if err != nil {
+			return "", 0, err
+		}
+		ahead, err := ancestors(repo, from, base)
+		if err != nil {
+			return "", 0, err
+		}
+		if tag == "" || len(ahead) < distance {
+			tag, distance = byCommit[cand], len(ahead)
+		}
+	}
+	return tag, distance, nil
+}
+
+// ancestors returns from and the commits reachable from it, leaving out
+// those in stop and what lies behind them.
+func ancestors(repo *git.Repository, from plumbing.Hash, stop map[plumbing.Hash]bool) (map[plumbing.Hash]bool, error) {
+	out := make(map[plumbing.Hash]bool)
+	stack := []plumbing.Hash{from}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if out[h] || stop[h] {
+			continue
+		}
+		out[h] = true
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, c.ParentHashes...)
+	}
+	return out, nil
+}
+
+func handleGitFetch(ctx context.Context, id string, params json.RawMessage) Response {
+	p, errResp := remoteParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+	remote, _ := remoteTarget(cfg, currentBranch(repo), p.Remote)
+	if _, err := repo.Remote(remote); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("remote not found: %s", remote))
+	}
+	auth, err := authMethod(p.Auth)
+	if err != nil {
+		return remoteErrorResponse(id, repo, "fetch", remote, p.Auth != nil, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+
+	before := refSnapshot(repo, remote)
+	err = repo.FetchContext(ctx, &git.FetchOptions{RemoteName: remote, Auth: auth, Force: p.Force, Prune: p.Prune})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return remoteErrorResponse(id, repo, "fetch", remote, p.Auth != nil, err)
+	}
+
+	updated := refUpdates(before, refSnapshot(repo, remote))
+	return Response{ID: id, Result: RemoteResult{Remote: remote, Updated: updated, UpToDate: len(updated) == 0}}
+}
+
+func handleGitPull(ctx context.Context, id string, params json.RawMessage) Response {
+	p, errResp := remoteParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get status: %v", err))
+	}
+	if hasLocalChanges(status) {
+		return errorResponse(id, -1, "worktree has uncommitted changes; commit them first")
+	}
+
+	local := currentBranch(repo)
+	if local == "" {
+		return errorResponse(id, -1, "cannot pull with a detached HEAD or no commits")
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+	remote, branch := remoteTarget(cfg, local, p.Remote)
+	if p.Branch != "" {
+		branch = p.Branch
+	}
+	auth, err := authMethod(p.Auth)
+	if err != nil {
+		return remoteErrorResponse(id, repo, "pull", remote, p.Auth != nil, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+
+	head, err := repo.Head()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to resolve HEAD: %v", err))
+	}
+	before := refSnapshot(repo, remote)
+	err = wt.PullContext(ctx, &git.PullOptions{
+		RemoteName:    remote,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+		Auth:          auth,
+		Force:         p.Force,
+	})
+	switch {
+	case err == nil, errors.Is(err, git.NoErrAlreadyUpToDate):
+	case errors.Is(err, plumbing.ErrReferenceNotFound):
+		return errorResponse(id, -1, fmt.Sprintf("branch %s not found on %s", branch, remote))
+	default:
+		return remoteErrorResponse(id, repo, "pull", remote, p.Auth != nil, err)
+	}
+
+	newHead, err := repo.Head()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to resolve HEAD: %v", err))
+	}
+	updated := refUpdates(before, refSnapshot(repo, remote))
+	return Response{ID: id, Result: PullResult{
+		RemoteResult: RemoteResult{Remote: remote, Updated: updated, UpToDate: newHead.Hash() == head.Hash()},
+		Branch:       local,
+		Old:          head.Hash().String(),
+		New:          newHead.Hash().String(),
+	}}
+}
+
+func handleGitPush(ctx context.Context, id string, params json.RawMessage) Response {
+	p, errResp := remoteParams(id, params)
+	if errResp != nil {
+		return *errResp
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+
+	local := p.Branch
+	if local == "" {
+		if local = currentBranch(repo); local == "" {
+			return errorResponse(id, -1, "cannot push a detached HEAD; pass branch")
+		}
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(local), false); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("branch not found: %s", local))
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+	remote, branch := remoteTarget(cfg, local, p.Remote)
+	if _, err := repo.Remote(remote); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("remote not found: %s", remote))
+	}
+	auth, err := authMethod(p.Auth)
+	if err != nil {
+		return remoteErrorResponse(id, repo, "push", remote, p.Auth != nil, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+
+	spec := config.RefSpec(fmt.Sprintf("%s:%s", plumbing.NewBranchReferenceName(local), plumbing.NewBranchReferenceName(branch)))
+	if p.Force {
+		spec = "+" + spec
+	}
+	before := refSnapshot(repo, remote)
+	err = repo.PushContext(ctx, &git.PushOptions{RemoteName: remote, RefSpecs: []config.RefSpec{spec}, Auth: auth})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return remoteErrorResponse(id, repo, "push", remote, p.Auth != nil, err)
+	}
+
+	if p.SetUpstream {
+		if err := setUpstream(repo, local, remote, branch); err != nil {
+			log.Printf("failed to set upstream of %s: %v", local, err)
+		}
+	}
+
+	updated := refUpdates(before, refSnapshot(repo, remote))
+	return Response{ID: id, Result: RemoteResult{Remote: remote, Updated: updated, UpToDate: len(updated) == 0}}
+}
+
+// remoteParams decodes and validates the params of the remote methods. It
+// returns a response to send instead when they are invalid.
+func handleGitSubmodules(id string, params json.RawMessage) Response {
+	var p PathParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read submodules: %v", err))
+	}
+
+	result := SubmodulesResult{Submodules: make([]SubmoduleEntry, 0, len(subs))}
+	for _, sm := range subs {
+		entry, err := submoduleEntry(sm)
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get status of submodule %s: %v", sm.Config().Path, err))
+		}
+		result.Submodules = append(result.Submodules, entry)
+	}
+	sort.Slice(result.Submodules, func(i, j int) bool { return result.Submodules[i].Path < result.Submodules[j].Path })
+
+	return Response{ID: id, Result: result}
+}
+
+func handleGitSubmoduleUpdate(ctx context.Context, id string, params json.RawMessage) Response {
+	var p SubmoduleUpdateParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read submodules: %v", err))
+	}
+
+	selected := subs
+	if len(p.Paths) > 0 {
+		byPath := make(map[string]*git.Submodule, len(subs))
+		for _, sm := range subs {
+			byPath[sm.Config().Path] = sm
+		}
+		selected = nil
+		for _, path := range p.Paths {
+			sm, ok := byPath[strings.Trim(path, "/")]
+			if !ok {
+				return errorResponse(id, -32602, fmt.Sprintf("not a submodule: %s", path))
+			}
+			selected = append(selected, sm)
+		}
+	}
+	auth, err := authMethod(p.Auth)
+	if err != nil {
+		return remoteErrorResponse(id, repo, "submodule update", "origin", p.Auth != nil, err)
+	}
+	opts := &git.SubmoduleUpdateOptions{Init: p.Init, NoFetch: p.NoFetch, Auth: auth}
+	if p.Recursive {
+		opts.RecurseSubmodules = submoduleDepth
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+
+	// A checkout that fails on local changes has moved the submodule's HEAD
+	// already, so those with changes are refused before any is updated.
+	for _, sm := range selected {
+		entry, err := submoduleEntry(sm)
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get status of submodule %s: %v", entry.Path, err))
+		}
+		if entry.Dirty {
+			return errorResponse(id, -1, fmt.Sprintf("submodule %s has uncommitted changes; commit them first", entry.Path))
+		}
+	}
+
+	result := SubmodulesResult{Submodules: make([]SubmoduleEntry, 0, len(selected))}
+	for _, sm := range selected {
+		path := sm.Config().Path
+		err := sm.UpdateContext(ctx, opts)
+		switch {
+		case errors.Is(err, git.ErrSubmoduleNotInitialized):
+			return errorResponse(id, -1, fmt.Sprintf("submodule %s is not initialized; pass init to initialize it", path))
+		case err != nil:
+			if sub, serr := sm.Repository(); serr == nil {
+				return remoteErrorResponse(id, sub, "update of submodule "+path, "origin", p.Auth != nil, err)
+			}
+			return errorResponse(id, -1, fmt.Sprintf("update of submodule %s failed: %v", path, err))
+		}
+		entry, err := submoduleEntry(sm)
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to get status of submodule %s: %v", path, err))
+		}
+		result.Submodules = append(result.Submodules, entry)
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+// submoduleEntry describes sm, with the state of its checkout when it is
+// initialized.
+func submoduleEntry(sm *git.Submodule) (SubmoduleEntry, error) {
+	cfg := sm.Config()
+	entry := SubmoduleEntry{Name: cfg.Name, Path: cfg.Path, URL: cfg.URL, Branch: cfg.Branch}
+	status, err := sm.Status()
+	if err != nil {
+		return entry, err
+	}
+	entry.Expected = status.Expected.String()
+	sub, err := sm.Repository()
+	if errors.Is(err, git.ErrSubmoduleNotInitialized) {
+		return entry, nil
+	}
+	if err != nil {
+		return entry, err
+	}
+	entry.Initialized = true
+	if !status.Current.IsZero() {
+		entry.Current = status.Current.String()
+	}
+	entry.InSync = status.IsClean()
+
+	swt, err := sub.Worktree()
+	if err != nil {
+		return entry, err
+	}
+	sstatus, err := swt.Status()
+	if err != nil {
+		return entry, err
+	}
+	entry.Dirty = hasLocalChanges(sstatus)
+	return entry, nil
+}
+
+func remoteParams(id string, params json.RawMessage) (RemoteParams, *Response) {
+	var p RemoteParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			resp := errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+			return p, &resp
+		}
+	}
+	if p.Branch != "" {
+		if err := plumbing.NewBranchReferenceName(p.Branch).Validate(); err != nil {
+			resp := errorResponse(id, -32602, fmt.Sprintf("invalid branch name %q: %v", p.Branch, err))
+			return p, &resp
+		}
+	}
+	if a := p.Auth; a != nil {
+		switch {
+		case a.Method != "ssh_agent" && a.Method != "token":
+			resp := errorResponse(id, -32602, fmt.Sprintf("invalid auth method %q: use ssh_agent or token", a.Method))
+			return p, &resp
+		case a.Method == "token" && a.Token == "":
+			resp := errorResponse(id, -32602, "missing required param: auth.token")
+			return p, &resp
+		}
+	}
+	return p, nil
+}
+
+// authMethod returns the go-git credentials for a, nil for the transport's
+// default.
+func authMethod(a *AuthParams) (transport.AuthMethod, error) {
+	if a == nil {
+		return nil, nil
+	}
+	user := a.User
+	if user == "" {
+		user = "git"
+	}
+	if a.Method == "ssh_agent" {
+		return gitssh.NewSSHAgentAuth(user)
+	}
+	return &githttp.BasicAuth{Username: user, Password: a.Token}, nil
+}
+
+// remoteTarget returns the remote and the branch on it that local pairs
+// with: its upstream when it tracks a branch of remote (or of any remote
+// when remote is empty), otherwise remote, or "origin", and local's name.
+func remoteTarget(cfg *config.Config, local, remote string) (string, string) {
+	b, ok := cfg.Branches[local]
+	tracked := ok && b.Merge != "" && b.Remote != "" && b.Remote != "."
+	if tracked && (remote == "" || remote == b.Remote) {
+		return b.Remote, b.Merge.Short()
+	}
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+	return remote, local
+}
+
+// setUpstream makes local track branch on remote.
+func setUpstream(repo *git.Repository, local, remote, branch string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	b, ok := cfg.Branches[local]
+	if !ok {
+		b = &config.Branch{Name: local}
+		cfg.Branches[local] = b
+	}
+	b.Remote = remote
+	b.Merge = plumbing.NewBranchReferenceName(branch)
+	return repo.SetConfig(cfg)
+}
+
+// refSnapshot maps the remote-tracking branches of remote, and the tags, to
+// the hashes they point at.
+func refSnapshot(repo *git.Repository, remote string) map[plumbing.ReferenceName]plumbing.Hash {
+	refs := make(map[plumbing.ReferenceName]plumbing.Hash)
+	iter, err := repo.References()
+	if err != nil {
+		log.Printf("failed to list references: %v", err)
+		return refs
+	}
+	defer iter.Close()
+
+	prefix := "refs/remotes/" + remote + "/"
+	_ = iter.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if ref.Type() == plumbing.HashReference && (strings.HasPrefix(name.String(), prefix) || name.IsTag()) {
+			refs[name] = ref.Hash()
+		}
+		return nil
+	})
+	return refs
+}
+
+// refUpdates lists the refs that differ between two snapshots, by name.
+func refUpdates(before, after map[plumbing.ReferenceName]plumbing.Hash) []RefUpdate {
+	updates := []RefUpdate{}
+	for name, hash := range after {
+		if old, ok := before[name]; !ok {
+			updates = append(updates, RefUpdate{Name: name.Short(), New: hash.String()})
+		} else if old != hash {
+			updates = append(updates, RefUpdate{Name: name.Short(), Old: old.String(), New: hash.String()})
+		}
+	}
+	for name, hash := range before {
+		if _, ok := after[name]; !ok {
+			updates = append(updates, RefUpdate{Name: name.Short(), Old: hash.String()})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Name < updates[j].Name })
+	return updates
+}
+
+// remoteErrorResponse reports a failed fetch, pull or push, as a codeRemote
+// error when its cause is one RemoteErrorData names.
+func remoteErrorResponse(id string, repo *git.Repository, op, remote string, hasAuth bool, err error) Response {
+	message := fmt.Sprintf("%s failed: %v", op, err)
+	kind := remoteErrorKind(err)
+	if kind == "" {
+		return errorResponse(id, -1, message)
+	}
+	// Servers answer wrong credentials like missing ones.
+	if kind == "auth_required" && hasAuth {
+		kind = "auth_failed"
+	}
+	return Response{ID: id, Error: &RPCError{
+		Code:    codeRemote,
+		Message: message,
+		Data:    RemoteErrorData{Kind: kind, Remote: remote, URL: remoteURL(repo, remote)},
+	}}
+}
+
+// remoteErrorKind classifies err for RemoteErrorData, "" when it is none of
+// the kinds listed there. SSH failures only surface as text.
+func remoteErrorKind(err error) string {
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired):
+		return "auth_required"
+	case errors.Is(err, transport.ErrAuthorizationFailed):
+		return "permission_denied"
+	case errors.Is(err, transport.ErrInvalidAuthMethod):
+		return "invalid_auth"
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return "not_found"
+	case errors.Is(err, git.ErrNonFastForwardUpdate), errors.Is(err, git.ErrForceNeeded):
+		return "non_fast_forward"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "unable to authenticate"):
+		return "auth_failed"
+	case strings.Contains(msg, "permission") && strings.Contains(msg, "denied"):
+		return "permission_denied"
+	case strings.Contains(msg, "ssh_auth_sock"), strings.Contains(msg, "ssh agent"):
+		return "auth_unavailable"
+	case strings.Contains(msg, "knownhosts"):
+		return "host_key"
+	case strings.HasPrefix(msg, "non-fast-forward update"):
+		return "non_fast_forward"
+	case strings.Contains(msg, "command error on"):
+		return "rejected"
+	}
+	return ""
+}
+
+// remoteURL returns the first URL of remote with any password masked.
+func remoteURL(repo *git.Repository, remote string) string {
+	r, err := repo.Remote(remote)
+	if err != nil || len(r.Config().URLs) == 0 {
+		return ""
+	}
+	return redactURL(r.Config().URLs[0])
+}
+
+// redactURL masks the password of a remote URL.
+func redactURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return raw
+}
+
+func handleGitConflicts(id string, params json.RawMessage) Response {
+	var p ConflictsParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.MaxBytes <= 0 {
+		p.MaxBytes = defaultConflictBytes
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+
+	wanted := make(map[string]bool, len(p.Files))
+	for _, f := range p.Files {
+		wanted[cleanFile(f)] = true
+	}
+	stages := conflictStages(idx)
+	names := make([]string, 0, len(stages))
+	for name := range stages {
+		if len(wanted) == 0 || wanted[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := ConflictsResult{Operation: operationInProgress(repo), Conflicts: make([]ConflictFile, 0, len(names))}
+	for _, name := range names {
+		s := stages[name]
+		f := ConflictFile{File: name, Kind: conflictKind(s), Markers: []ConflictMarker{}}
+		for stage, side := range map[index.Stage]**ConflictSide{index.AncestorMode: &f.Base, index.OurMode: &f.Ours, index.TheirMode: &f.Theirs} {
+			e, ok := s[stage]
+			if !ok {
+				continue
+			}
+			if *side, err = conflictSide(repo, e.Hash, p.MaxBytes); err != nil {
+				return errorResponse(id, -1, fmt.Sprintf("failed to read %s: %v", name, err))
+			}
+		}
+		if data, err := util.ReadFile(wt.Filesystem, name); err == nil {
+			f.Markers = conflictMarkers(string(data))
+		}
+		result.Conflicts = append(result.Conflicts, f)
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+func handleGitResolve(id string, params json.RawMessage) Response {
+	var p ResolveParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if len(p.Files) == 0 {
+		return errorResponse(id, -32602, "files is required")
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+
+	// Check every resolution before changing anything, so a bad one leaves
+	// the worktree and the index as they were.
+	stages := conflictStages(idx)
+	for i := range p.Files {
+		r := &p.Files[i]
+		r.File = cleanFile(r.File)
+		if _, ok := stages[r.File]; !ok {
+			return errorResponse(id, -32602, fmt.Sprintf("%s is not conflicted", r.File))
+		}
+		if r.Delete || r.Force {
+			continue
+		}
+		content := ""
+		if r.Content != nil {
+			content = *r.Content
+		} else {
+			data, err := util.ReadFile(wt.Filesystem, r.File)
+			if err != nil {
+				return errorResponse(id, -1, fmt.Sprintf("failed to read %s: %v", r.File, err))
+			}
+			content = string(data)
+		}
+		if m := conflictMarkers(content); len(m) > 0 {
+			return errorResponse(id, -1, fmt.Sprintf("%s still has conflict markers at line %d", r.File, m[0].Start))
+		}
+	}
+
+	result := ResolveResult{Resolved: make([]string, 0, len(p.Files)), Remaining: []string{}}
+	for _, r := range p.Files {
+		switch {
+		case r.Delete:
+			if err := wt.Filesystem.Remove(r.File); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return errorResponse(id, -1, fmt.Sprintf("failed to remove %s: %v", r.File, err))
+			}
+		case r.Content != nil:
+			perm := os.FileMode(0o644)
+			if info, err := wt.Filesystem.Lstat(r.File); err == nil {
+				perm = info.Mode().Perm()
+			}
+			if err := util.WriteFile(wt.Filesystem, r.File, []byte(*r.Content), perm); err != nil {
+				return errorResponse(id, -1, fmt.Sprintf("failed to write %s: %v", r.File, err))
+			}
+		}
+		dropEntries(idx, r.File)
+		result.Resolved = append(result.Resolved, r.File)
+	}
+	// Staging a file updates its entry, so the conflict stages go first.
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to write index: %v", err))
+	}
+	for _, r := range p.Files {
+		if r.Delete {
+			continue
+		}
+		if _, err := wt.Add(r.File); err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to stage %s: %v", r.File, err))
+		}
+	}
+
+	if idx, err = repo.Storer.Index(); err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+	for name := range conflictStages(idx) {
+		result.Remaining = append(result.Remaining, name)
+	}
+	sort.Strings(result.Remaining)
+
+	return Response{ID: id, Result: result}
+}
+
+// cleanFile returns file as a slash-separated path relative to the
+// worktree root, as the index names it.
+func cleanFile(file string) string {
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(file)), "./")
+}
+
+// conflictStages returns the conflict stages of the index by file.
+func conflictStages(idx *index.Index) map[string]map[index.Stage]*index.Entry {
+	stages := make(map[string]map[index.Stage]*index.Entry)
+	for _, e := range idx.Entries {
+		if e.Stage == 0 {
+			continue
+		}
+		if stages[e.Name] == nil {
+			stages[e.Name] = make(map[index.Stage]*index.Entry)
+		}
+		stages[e.Name][e.Stage] = e
+	}
+	return stages
+}
+
+// dropEntries removes every entry of file from the index, resolved or not.
+func dropEntries(idx *index.Index, file string) {
+	kept := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if e.Name != file {
+			kept = append(kept, e)
+		}
+	}
+	idx.Entries = kept
+}
+
+// conflictKind names a conflict after the stages it has, like git status.
+func conflictKind(s map[index.Stage]*index.Entry) string {
+	_, base := s[index.AncestorMode]
+	_, ours := s[index.OurMode]
+	_, theirs := s[index.TheirMode]
+	switch {
+	case ours && theirs && base:
+		return "both_modified"
+	case ours && theirs:
+		return "both_added"
+	case base && theirs:
+		return "deleted_by_us"
+	case base && ours:
+		return "deleted_by_them"
+	case ours:
+		return "added_by_us"
+	case theirs:
+		return "added_by_them"
+	default:
+		return "both_deleted"
+	}
+}
+
+// conflictSide reads a staged version of a conflicted file, cutting its
+// content at a line boundary past maxBytes.
+func conflictSide(repo *git.Repository, hash plumbing.Hash, maxBytes int) (*ConflictSide, error) {
+	blob, err := repo.BlobObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	side := &ConflictSide{Hash: hash.String()}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		side.Binary = true
+		return side, nil
+	}
+	if len(data) > maxBytes {
+		data = data[:bytes.LastIndexByte(data[:maxBytes], '\n')+1]
+		side.Truncated = true
+	}
+	side.Content = string(data)
+	return side, nil
+}
+
+// conflictMarkers returns the conflict hunks of content. A hunk whose
+// markers are incomplete is left out.
+func conflictMarkers(content string) []ConflictMarker {
+	out := []ConflictMarker{}
+	var cur *ConflictMarker
+	for i, line := range strings.Split(content, "\n") {
+		n := i + 1
+		switch {
+		case marker(line, "<<<<<<<"):
+			cur = &ConflictMarker{Start: n}
+		case cur == nil:
+		case marker(line, "|||||||") && cur.Separator == 0:
+			cur.Base = n
+		case strings.TrimRight(line, "\r") == "=======" && cur.Separator == 0:
+			cur.Separator = n
+		case marker(line, ">>>>>>>") && cur.Separator != 0:
+			cur.End = n
+			out = append(out, *cur)
+			cur = nil
+		}
+	}
+	return out
+}
+
+// marker reports whether line is the conflict marker prefix, alone or
+// followed by a space and a label.
+func marker(line, prefix string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), prefix)
+	return ok && (rest == "" || rest[0] == ' ')
+}
+
+// operationInProgress names the operation that stopped on conflicts, from
+// the state files git leaves in the repository directory.
+func operationInProgress(repo *git.Repository) string {
+	s, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ""
+	}
+	fs := s.Filesystem()
+	for _, op := range []struct{ file, name string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry_pick"},
+		{"REVERT_HEAD", "revert"},
+	} {
+		if _, err := fs.Stat(op.file); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+func handleGitCheckIgnore(id string, params json.RawMessage) Response {
+	var p CheckIgnoreParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if len(p.Files) == 0 {
+		return errorResponse(id, -32602, "files is required")
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+	rules, err := baseIgnoreRules(repo)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read ignore rules: %v", err))
+	}
+
+	dirRules := make(map[string][]ignoreRule)
+	result := CheckIgnoreResult{Files: make([]IgnoreStatus, 0, len(p.Files))}
+	for _, f := range p.Files {
+		name := cleanFile(f)
+		if name == "." || name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return errorResponse(id, -32602, fmt.Sprintf("%s is outside the worktree", f))
+		}
+		st := IgnoreStatus{File: name, Tracked: tracked(idx, name)}
+
+		// Rules of a .gitignore apply below its directory, and later rules
+		// win, so each directory on the way down adds its own.
+		parts := strings.Split(name, "/")
+		applicable := rules
+		for i := range parts {
+			dir := strings.Join(parts[:i], "/")
+			local, ok := dirRules[dir]
+			if !ok {
+				if local, err = gitignoreRules(wt.Filesystem, dir); err != nil {
+					return errorResponse(id, -1, fmt.Sprintf("failed to read ignore rules: %v", err))
+				}
+				dirRules[dir] = local
+			}
+			applicable = append(applicable[:len(applicable):len(applicable)], local...)
+
+			isDir := i < len(parts)-1
+			if !isDir {
+				if info, err := wt.Filesystem.Lstat(name); err == nil {
+					isDir = info.IsDir()
+				}
+			}
+			r, m := matchIgnore(applicable, parts[:i+1], isDir)
+			if m == gitignore.NoMatch {
+				continue
+			}
+			st.Rule = &IgnoreRule{Source: r.source, Line: r.line, Pattern: r.text}
+			// Nothing inside an ignored directory can be included again.
+			if m == gitignore.Exclude && i < len(parts)-1 {
+				st.Ignored = true
+				break
+			}
+			st.Ignored = m == gitignore.Exclude
+		}
+		if st.Tracked {
+			st.Ignored = false
+		}
+		result.Files = append(result.Files, st)
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+func handleGitLsFiles(id string, params json.RawMessage) Response {
+	var p LsFilesParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if _, err := path.Match(p.Pattern, ""); err != nil {
+		return errorResponse(id, -32602, fmt.Sprintf("invalid pattern: %v", err))
+	}
+	if p.Limit <= 0 {
+		p.Limit = 1000
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+
+	// A conflicted file has an entry per stage; it is listed once.
+	seen := make(map[string]bool, len(idx.Entries))
+	var files []string
+	for _, e := range idx.Entries {
+		if seen[e.Name] {
+			continue
+		}
+		seen[e.Name] = true
+		if p.Pattern == "" || matchGlob(p.Pattern, e.Name) {
+			files = append(files, e.Name)
+		}
+	}
+	sort.Strings(files)
+
+	result := LsFilesResult{Files: files, Total: len(files)}
+	if len(files) > p.Limit {
+		result.Files = files[:p.Limit]
+		result.More = true
+	}
+	if result.Files == nil {
+		result.Files = []string{}
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+// ignoreRule is a parsed ignore pattern with where it was read from.
+type ignoreRule struct {
+	pattern gitignore.Pattern
+	source  string
+	line    int
+	text    string
+}
+
+// baseIgnoreRules returns the rules that apply across the worktree, in the
+// order git reads them: the excludes file of the user, then
+// .git/info/exclude.
+func baseIgnoreRules(repo *git.Repository) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	if file := excludesFile(repo); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		rules = append(rules, parseIgnoreRules(data, file, nil)...)
+	}
+	if s, ok := repo.Storer.(*filesystem.Storage); ok {
+		data, err := util.ReadFile(s.Filesystem(), "info/exclude")
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		rules = append(rules, parseIgnoreRules(data, ".git/info/exclude", nil)...)
+	}
+	return rules, nil
+}
+
+// excludesFile returns the path of the excludes file of the user: the
+// core.excludesFile setting, or git's default of
+// $XDG_CONFIG_HOME/git/ignore.
+func excludesFile(repo *git.Repository) string {
+	home, _ := os.UserHomeDir()
+	if cfg, err := repo.ConfigScoped(config.GlobalScope); err == nil {
+		if file := cfg.Raw.Section("core").Option("excludesfile"); file != "" {
+			if rest, ok := strings.CutPrefix(file, "~/"); ok && home != "" {
+				file = filepath.Join(home, rest)
+			}
+			return file
+		}
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "git", "ignore")
+	}
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".config", "git", "ignore")
+}
+
+// gitignoreRules returns the rules of the .gitignore in dir of the
+// worktree, none when it has no such file.
+func gitignoreRules(fs billy.Filesystem, dir string) ([]ignoreRule, error) {
+	file := path.Join(dir, ".gitignore")
+	data, err := util.ReadFile(fs, file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var domain []string
+	if dir != "" {
+		domain = strings.Split(dir, "/")
+	}
+	return parseIgnoreRules(data, file, domain), nil
+}
+
+// parseIgnoreRules parses the lines of an ignore file, skipping blanks and
+// comments as git does. domain is the directory the rules apply below.
+func parseIgnoreRules(data []byte, source string, domain []string) []ignoreRule {
+	var rules []ignoreRule
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		rules = append(rules, ignoreRule{
+			pattern: gitignore.ParsePattern(line, domain),
+			source:  source,
+			line:    n + 1,
+			text:    line,
+		})
+	}
+	return rules
+}
+
+// matchIgnore returns the last of rules matching the path split into parts,
+// which decides whether it is ignored, and how it matched.
+func matchIgnore(rules []ignoreRule, parts []string, isDir bool) (ignoreRule, gitignore.MatchResult) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if m := rules[i].pattern.Match(parts, isDir); m != gitignore.NoMatch {
+			return rules[i], m
+		}
+	}
+	return ignoreRule{}, gitignore.NoMatch
+}
+
+// tracked reports whether the index has file, or files under it when it is
+// a directory.
+func tracked(idx *index.Index, file string) bool {
+	for _, e := range idx.Entries {
+		if e.Name == file || strings.HasPrefix(e.Name, file+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether name matches pattern as git_ls_files matches
+// it: by its base name when the pattern has no "/", or else segment by
+// segment, "**" matching any number of them.
+func matchGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func handleGitApply(id string, params json.RawMessage) Response {
+	var p ApplyParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if strings.TrimSpace(p.Patch) == "" {
+		return errorResponse(id, -32602, "patch is required")
+	}
+	patches, err := parsePatch(p.Patch)
+	if err != nil {
+		return errorResponse(id, -32602, fmt.Sprintf("invalid patch: %v", err))
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to get worktree: %v", err))
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read index: %v", err))
+	}
+
+	files := &patchedFiles{fs: wt.Filesystem, content: make(map[string]*string), perm: make(map[string]os.FileMode)}
+	result := ApplyResult{Applied: true, Files: make([]ApplyFile, 0, len(patches))}
+	for _, fp := range patches {
+		f, err := applyFilePatch(repo, idx, files, fp, p.ThreeWay)
+		if err != nil {
+			return errorResponse(id, -1, fmt.Sprintf("failed to read %s: %v", f.File, err))
+		}
+		result.Applied = result.Applied && f.Applied
+		result.Files = append(result.Files, f)
+	}
+	if !result.Applied || p.Check {
+		return Response{ID: id, Result: result}
+	}
+	if err := files.write(); err != nil {
+		return errorResponse(id, -1, err.Error())
+	}
+
+	return Response{ID: id, Result: result}
+}
+
+// filePatch is the patch of one file. Names are relative to the worktree
+// root, "" for /dev/null. Preimage is the blob hash, often abbreviated, of
+// the version it was made from, and mode the mode of an added file.
+type filePatch struct {
+	oldName, newName string
+	named            bool
+	preimage         string
+	mode             os.FileMode
+	binary           bool
+	hunks            []hunk
+}
+
+// hunk is a hunk of a filePatch. Each line keeps its ' ', '-' or '+' and
+// its end of line, which a last line without one lacks.
+type hunk struct {
+	header   string
+	oldStart int
+	lines    []string
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parsePatch parses a unified diff, with or without git's extended
+// headers. Text before the first file, such as a commit message, is
+// skipped.
+func parsePatch(patch string) ([]filePatch, error) {
+	lines := strings.SplitAfter(patch, "\n")
+	var files []filePatch
+	var cur *filePatch
+	start := func() {
+		files = append(files, filePatch{})
+		cur = &files[len(files)-1]
+	}
+	for i := 0; i < len(lines); i++ {
+		text := strings.TrimRight(lines[i], "\r\n")
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			start()
+			if rest := strings.TrimPrefix(text, "diff --git "); strings.HasPrefix(rest, "a/") {
+				if a, b, ok := strings.Cut(rest[2:], " b/"); ok {
+					cur.oldName, cur.newName = a, b
+				}
+			}
+		case fileHeader(lines, i):
+			if cur == nil || cur.named || len(cur.hunks) > 0 {
+				start()
+			}
+			cur.oldName, cur.newName = patchNames(text[4:], strings.TrimRight(lines[i+1], "\r\n")[4:])
+			cur.named = true
+			i++
+		case strings.HasPrefix(text, "@@"):
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: hunk before any file header", i+1)
+			}
+			h, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			cur.hunks = append(cur.hunks, h)
+			i = next - 1
+		case cur == nil:
+			// Text before the first file, such as a commit message.
+		case strings.HasPrefix(text, "new file mode "):
+			cur.oldName = ""
+			cur.mode = parseMode(strings.TrimPrefix(text, "new file mode "))
+		case strings.HasPrefix(text, "deleted file mode "):
+			cur.newName = ""
+		case strings.HasPrefix(text, "rename from "):
+			cur.oldName = strings.TrimPrefix(text, "rename from ")
+		case strings.HasPrefix(text, "rename to "):
+			cur.newName = strings.TrimPrefix(text, "rename to ")
+		case strings.HasPrefix(text, "index "):
+			hashes, _, _ := strings.Cut(strings.TrimPrefix(text, "index "), " ")
+			cur.preimage, _, _ = strings.Cut(hashes, "..")
+		case strings.HasPrefix(text, "Binary files ") || text == "GIT binary patch":
+			cur.binary = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no file headers")
+	}
+	for _, f := range files {
+		if f.oldName == "" && f.newName == "" {
+			return nil, errors.New("file header names no file")
+		}
+	}
+	return files, nil
+}
+
+// fileHeader reports whether lines[i] and the line after are the "---" and
+// "+++" lines that start the patch of a file.
+func fileHeader(lines []string, i int) bool {
+	return strings.HasPrefix(lines[i], "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+}
+
+// patchNames returns the names of the "---" and "+++" lines, without their
+// timestamps and the "a/" and "b/" prefixes git adds.
+func patchNames(old, new string) (string, string) {
+	name := func(s string) string {
+		s, _, _ = strings.Cut(s, "\t")
+		if u, err := strconv.Unquote(s); err == nil {
+			s = u
+		}
+		if s == "/dev/null" {
+			return ""
+		}
+		return s
+	}
+	old, new = name(old), name(new)
+	if (old == "" || strings.HasPrefix(old, "a/")) && (new == "" || strings.HasPrefix(new, "b/")) {
+		old, new = strings.TrimPrefix(old, "a/"), strings.TrimPrefix(new, "b/")
+	}
+	return old, new
+}
+
+// parseMode returns the permissions of a git file mode such as "100755".
+func parseMode(s string) os.FileMode {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0
+	}
+	return os.FileMode(mode).Perm()
+}
+
+// parseHunk parses the hunk whose header is lines[i], returning it and the
+// index of the line after it. The line counts of the header are trusted
+// over what the lines look like, so a removed line starting "--" is not
+// taken for a file header; lines marked as hunk lines past the counts are
+// still taken, as hand-written patches often miscount.
+func parseHunk(lines []string, i int) (hunk, int, error) {
+	text := strings.TrimRight(lines[i], "\r\n")
+	m := hunkHeader.FindStringSubmatch(text)
+	if m == nil {
+		return hunk{}, 0, fmt.Errorf("line %d: malformed hunk header %q", i+1, text)
+	}
+	h := hunk{header: m[0]}
+	h.oldStart, _ = strconv.Atoi(m[1])
+	oldCount, newCount := 1, 1
+	if m[2] != "" {
+		oldCount, _ = strconv.Atoi(m[2])
+	}
+	if m[4] != "" {
+		newCount, _ = strconv.Atoi(m[4])
+	}
+
+	j := i + 1
+	for ; j < len(lines) && lines[j] != ""; j++ {
+		line := lines[j]
+		within := oldCount > 0 || newCount > 0
+		switch {
+		case strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file" is about the line before.
+			if n := len(h.lines); n > 0 {
+				h.lines[n-1] = strings.TrimSuffix(h.lines[n-1], "\n")
+			}
+			continue
+		case strings.HasPrefix(line, "@@") || strings.HasPrefix(line, "diff ") || fileHeader(lines, j):
+			return h, j, nil
+		case !within && strings.TrimRight(line, "\r\n") == "--":
+			return h, j, nil // the signature of git format-patch
+		case line == "\n" || line == "\r\n":
+			if !within {
+				return h, j, nil
+			}
+			// A blank line stripped of its marker: added when no old lines
+			// remain, else context.
+			if oldCount <= 0 {
+				line = "+" + line
+			} else {
+				line = " " + line
+			}
+		}
+		switch line[0] {
+		case ' ':
+			oldCount--
+			newCount--
+		case '-':
+			oldCount--
+		case '+':
+			newCount--
+		default:
+			return h, j, nil
+		}
+		h.lines = append(h.lines, line)
+	}
+	return h, j, nil
+}
+
+// side returns the lines of h before the patch, for '-', or after it, for
+// '+'.
+func (h hunk) side(marker byte) []string {
+	var out []string
+	for _, l := range h.lines {
+		if l[0] == ' ' || l[0] == marker {
+			out = append(out, l[1:])
+		}
+	}
+	return out
+}
+
+// leading returns the number of context lines h starts with.
+func (h hunk) leading() int {
+	n := 0
+	for n < len(h.lines) && h.lines[n][0] == ' ' {
+		n++
+	}
+	return n
+}
+
+// want returns the 0-based line of the file where the header puts h.
+func (h hunk) want() int {
+	if len(h.side('-')) == 0 {
+		return h.oldStart // a pure insertion goes after line oldStart
+	}
+	return max(h.oldStart-1, 0)
+}
+
+// patchedFiles holds the files a patch changes until all of it applies:
+// their content by name, nil for a file removed, so the patch of a file
+// that an earlier one changed applies to the changed version.
+type patchedFiles struct {
+	fs      billy.Filesystem
+	content map[string]*string
+	perm    map[string]os.FileMode
+	order   []string
+}
+
+// read returns the content of file and whether it exists.
+func (p *patchedFiles) read(file string) (string, bool, error) {
+	if c, ok := p.content[file]; ok {
+		if c == nil {
+			return "", false, nil
+		}
+		return *c, true, nil
+	}
+	data, err := util.ReadFile(p.fs, file)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// set records the new content of file, nil to remove it.
+func (p *patchedFiles) set(file string, content *string, perm os.FileMode) {
+	if _, ok := p.content[file]; !ok {
+		p.order = append(p.order, file)
+	}
+	p.content[file] = content
+	p.perm[file] = perm
+}
+
+// write writes the files to the worktree, in the order they were set.
+func (p *patchedFiles) write() error {
+	for _, file := range p.order {
+		c := p.content[file]
+		if c == nil {
+			if err := p.fs.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %v", file, err)
+			}
+			continue
+		}
+		if err := util.WriteFile(p.fs, file, []byte(*c), p.perm[file]); err != nil {
+			return fmt.Errorf("failed to write %s: %v", file, err)
+		}
+	}
+	return nil
+}
+
+// applyFilePatch applies fp to files, reporting how it and each of its
+// hunks applied. An error is returned only when a file cannot be read.
+func applyFilePatch(repo *git.Repository, idx *index.Index, files *patchedFiles, fp filePatch, threeWay bool) (ApplyFile, error) {
+	f := ApplyFile{File: cleanFile(fp.newName), Status: "modified", Hunks: []ApplyHunk{}}
+	src := cleanFile(fp.oldName)
+	switch {
+	case fp.oldName == "":
+		f.Status, src = "added", f.File
+	case fp.newName == "":
+		f.File, f.Status = src, "deleted"
+	case src != f.File:
+		f.Status, f.OldFile = "renamed", src
+	}
+	for _, name := range []string{src, f.File} {
+		if name == "." || name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			f.Error = fmt.Sprintf("%s is outside the worktree", name)
+			return f, nil
+		}
+		if inGitDir(name) {
+			f.Error = fmt.Sprintf("%s is inside .git", name)
+			return f, nil
+		}
+		link, err := symlinkOn(files.fs, name)
+		if err != nil {
+			return f, err
+		}
+		switch {
+		case link == name:
+			f.Error = fmt.Sprintf("%s is a symbolic link", name)
+			return f, nil
+		case link != "":
+			f.Error = fmt.Sprintf("%s is beyond the symbolic link %s", name, link)
+			return f, nil
+		}
+	}
+	if fp.binary {
+		f.Error = "binary patches are not supported"
+		return f, nil
+	}
+
+	content, exists, err := files.read(src)
+	if err != nil {
+		return f, err
+	}
+	if f.Status == "modified" && creates(fp) && (!exists || content != "") {
+		f.Status = "added" // a new file without "--- /dev/null"
+	}
+	switch {
+	case f.Status == "added" && exists:
+		f.Error = "already exists"
+		return f, nil
+	case f.Status != "added" && !exists:
+		f.Error = "no such file"
+		return f, nil
+	}
+
+	ours := fileLines(content)
+	out, hunks, ok := applyHunks(ours, fp.hunks)
+	if !ok && threeWay && f.Status != "added" {
+		base, found, err := preimage(repo, idx, src, fp.preimage)
+		if err != nil {
+			return f, err
+		}
+		if found {
+			out, hunks, ok = mergeHunks(fileLines(base), ours, fp.hunks)
+		}
+	}
+	f.Hunks, f.Applied = hunks, ok
+	if !ok {
+		return f, nil
+	}
+
+	if f.Status == "deleted" {
+		if len(out) > 0 {
+			f.Applied, f.Error = false, "the patch leaves lines of the deleted file"
+			return f, nil
+		}
+		files.set(src, nil, 0)
+		return f, nil
+	}
+	perm := fp.mode
+	if perm == 0 {
+		perm = 0o644
+		if info, err := files.fs.Lstat(src); err == nil {
+			perm = info.Mode().Perm()
+		}
+	}
+	patched := strings.Join(out, "")
+	files.set(f.File, &patched, perm)
+	if f.Status == "renamed" {
+		files.set(src, nil, 0)
+	}
+	return f, nil
+}
+
+// inGitDir reports whether name has a .git component, compared without
+// case as git apply does, so a patch cannot write hooks or config.
+func inGitDir(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.EqualFold(part, ".git") {
+			return true
+		}
+	}
+	return false
+}
+
+// symlinkOn returns the first component of name, from the worktree root,
+// that is a symbolic link, or "" when none is, so a patch cannot write
+// through a link to outside the worktree or into .git.
+func symlinkOn(fs billy.Filesystem, name string) (string, error) {
+	parts := strings.Split(name, "/")
+	for i := range parts {
+		p := strings.Join(parts[:i+1], "/")
+		info, err := fs.Lstat(p)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return p, nil
+		}
+	}
+	return "", nil
+}
+
+// creates reports whether fp only adds lines to an empty file.
+func creates(fp filePatch) bool {
+	for _, h := range fp.hunks {
+		if h.oldStart != 0 || len(h.side('-')) > 0 {
+			return false
+		}
+	}
+	return len(fp.hunks) > 0
+}
+
+// fileLines splits content into lines, each with its end of line.
+func fileLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// applyHunks applies hunks in order to lines, each where its context
+// matches nearest to where its header puts it, after the hunk before. It
+// returns the patched lines, how each hunk applied, and whether all did.
+func applyHunks(lines []string, hunks []hunk) ([]string, []ApplyHunk, bool) {
+	out := make([]string, 0, len(lines))
+	results := make([]ApplyHunk, len(hunks))
+	ok := true
+	pos, offset := 0, 0
+	for i, h := range hunks {
+		results[i] = ApplyHunk{Header: h.header}
+		old := h.side('-')
+		at := findLines(lines, old, h.want()+offset, pos)
+		if at < 0 {
+			results[i].Error = "does not match the file"
+			ok = false
+			continue
+		}
+		out = append(out, lines[pos:at]...)
+		offset = at - h.want()
+		results[i].Applied, results[i].Line, results[i].Offset = true, len(out)+1+h.leading(), offset
+		out = append(out, h.side('+')...)
+		pos = at + len(old)
+	}
+	return append(out, lines[pos:]...), results, ok
+}
+
+// findLines returns where old occurs in lines, at from or after, nearest
+// to want; -1 when it does not.
+func findLines(lines, old []string, want, from int) int {
+	last := len(lines) - len(old)
+	if last < from {
+		return -1
+	}
+	want = min(max(want, from), last)
+	for d := 0; want-d >= from || want+d <= last; d++ {
+		if at := want - d; at >= from && slices.Equal(lines[at:at+len(old)], old) {
+			return at
+		}
+		if at := want + d; d > 0 && at <= last && slices.Equal(lines[at:at+len(old)], old) {
+			return at
+		}
+	}
+	return -1
+}
+
+// preimage returns the version of file a patch was made from: the blob
+// hash names, when the file as staged or at HEAD has it, or the file so
+// when hash is "". A full hash is also looked up in the object database.
+func preimage(repo *git.Repository, idx *index.Index, file, hash string) (string, bool, error) {
+	var candidates []plumbing.Hash
+	if plumbing.IsHash(hash) {
+		candidates = append(candidates, plumbing.NewHash(hash))
+	}
+	for _, e := range idx.Entries {
+		if e.Name == file && e.Stage == 0 {
+			candidates = append(candidates, e.Hash)
+		}
+	}
+	if head, err := repo.Head(); err == nil {
+		if c, err := repo.CommitObject(head.Hash()); err == nil {
+			if tree, err := c.Tree(); err == nil {
+				if h, ok := entryHash(tree, file); ok {
+					candidates = append(candidates, h)
+				}
+			}
+		}
+	}
+	for _, h := range candidates {
+		if !strings.HasPrefix(h.String(), hash) {
+			continue
+		}
+		blob, err := repo.BlobObject(h)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+		r, err := blob.Reader()
+		if err != nil {
+			return "", false, err
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	}
+	return "", false, nil
+}
+
+// change replaces lines start to end of a base version of a file with
+// lines; hunk is the index of the hunk it comes from, for a change the
+// patch makes.
+type change struct {
+	start, end int
+	lines      []string
+	hunk       int
+}
+
+// mergeHunks applies hunks by three-way merge: to base, the version of the
+// file the patch was made from, and then the changes made to it in ours,
+// the file as it is, are merged in. A hunk fails when base does not match
+// it or when it overlaps a change in ours that differs from its own.
+func mergeHunks(base, ours []string, hunks []hunk) ([]string, []ApplyHunk, bool) {
+	results := make([]ApplyHunk, len(hunks))
+	ok := true
+	var theirs []change
+	pos := 0
+	for i, h := range hunks {
+		results[i] = ApplyHunk{Header: h.header}
+		at := findLines(base, h.side('-'), h.want(), pos)
+		if at < 0 {
+			results[i].Error = "does not match the file or the version it was made from"
+			ok = false
+			continue
+		}
+		results[i].Applied, results[i].Merged = true, true
+		results[i].Offset = at - h.want()
+		theirs = append(theirs, hunkChanges(h, at, i)...)
+		pos = at + len(h.side('-'))
+	}
+	mine := lineChanges(base, ours)
+
+	var out []string
+	pos = 0
+	emit := func(c change) {
+		out = append(out, base[pos:c.start]...)
+		if c.hunk >= 0 && results[c.hunk].Line == 0 {
+			results[c.hunk].Line = len(out) + 1
+		}
+		out = append(out, c.lines...)
+		pos = c.end
+	}
+	for i, j := 0, 0; i < len(mine) || j < len(theirs); {
+		switch {
+		case i < len(mine) && j < len(theirs) && overlaps(mine[i], theirs[j]):
+			if mine[i].start == theirs[j].start && mine[i].end == theirs[j].end && slices.Equal(mine[i].lines, theirs[j].lines) {
+				emit(theirs[j])
+				i++
+			} else {
+				results[theirs[j].hunk].Applied, results[theirs[j].hunk].Merged = false, false
+				results[theirs[j].hunk].Error = "conflicts with changes made to the file"
+				ok = false
+			}
+			j++
+		case j == len(theirs) || i < len(mine) && mine[i].start < theirs[j].start:
+			emit(mine[i])
+			i++
+		default:
+			emit(theirs[j])
+			j++
+		}
+	}
+	return append(out, base[pos:]...), results, ok
+}
+
+// overlaps reports whether changes a and b touch the same lines, or insert
+// at the same place.
+func overlaps(a, b change) bool {
+	return a.start == b.start || a.start < b.end && b.start < a.end
+}
+
+// hunkChanges returns the changes hunk h, number i, makes to a file it
+// matches at line at.
+func hunkChanges(h hunk, at, i int) []change {
+	var out []change
+	var cur *change
+	for _, l := range h.lines {
+		if l[0] == ' ' {
+			if cur != nil {
+				out = append(out, *cur)
+				cur = nil
+			}
+			at++
+			continue
+		}
+		if cur == nil {
+			cur = &change{start: at, end: at, hunk: i}
+		}
+		if l[0] == '-' {
+			cur.end++
+			at++
+		} else {
+			cur.lines = append(cur.lines, l[1:])
+		}
+	}
+	if cur != nil {
+		out = append(out, *cur)
+	}
+	return out
+}
+
+// lineChanges returns the changes that turn base into ours.
+func lineChanges(base, ours []string) []change {
+	var out []change
+	var cur *change
+	at := 0
+	for _, d := range linediff.Do(strings.Join(base, ""), strings.Join(ours, "")) {
+		lines := fileLines(d.Text)
+		if d.Type == diffmatchpatch.DiffEqual {
+			if cur != nil {
+				out = append(out, *cur)
+				cur = nil
+			}
+			at += len(lines)
+			continue
+		}
+		if cur == nil {
+			cur = &change{start: at, end: at, hunk: -1}
+		}
+		if d.Type == diffmatchpatch.DiffDelete {
+			cur.end += len(lines)
+			at += len(lines)
+		} else {
+			cur.lines = append(cur.lines, lines...)
+		}
+	}
+	if cur != nil {
+		out = append(out, *cur)
+	}
+	return out
+}
+
+func handleGitInfo(id string, params json.RawMessage) Response {
+	var p PathParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+
+	repo, err := openRepo(p.Path)
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to open repo: %v", err))
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return errorResponse(id, -1, fmt.Sprintf("failed to read config: %v", err))
+	}
+
+	info := InfoResult{Remotes: []RemoteInfo{}, Operation: operationInProgress(repo)}
+	if s, ok := repo.Storer.(*filesystem.Storage); ok {
+		info.GitDir = s.Filesystem().Root()
+	}
+	switch wt, err := repo.Worktree(); {
+	case errors.Is(err, git.ErrIsBareRepository):
+		info.Bare, info.Root = true, info.GitDir
+	case err != nil:
+		return errorResponse(id, -1, fmt.Sprintf("failed to open worktree: %v", err))
+	default:
+		info.Root = wt.Filesystem.Root()
+	}
+
+	for name, r := range cfg.Remotes {
+		urls := make([]string, len(r.URLs))
+		for i, u := range r.URLs {
+			urls[i] = redactURL(u)
+		}
+		info.Remotes = append(info.Remotes, RemoteInfo{Name: name, URLs: urls})
+	}
+	sort.Slice(info.Remotes, func(i, j int) bool { return info.Remotes[i].Name < info.Remotes[j].Name })
+
+	if sym, err := repo.Reference(plumbing.HEAD, false); err == nil {
+		if sym.Type() == plumbing.SymbolicReference {
+			info.Branch = sym.Target().Short()
+		} else {
+			info.Detached = true
+		}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		// No commits yet: there is nothing to compare or summarize.
+		return Response{ID: id, Result: info}
+	}
+	info.Head = head.Hash().String()
+	if head.Name().IsBranch() {
+		b := branchEntry(repo, cfg, head, info.Branch)
+		info.Upstream, info.UpstreamGone, info.Ahead, info.Behind = b.Upstream, b.UpstreamGone, b.Ahead, b.Behind
+	}
+	if c, err := repo.CommitObject(head.Hash()); err == nil {
+		e := commitEntry(c)
+		e.Message, _, _ = strings.Cut(strings.TrimSpace(e.Message), "\n")
+		info.LastCommit = &e
+	}
+	return Response{ID: id, Result: info}
+}
+
+// maxWorkers is the most requests handled at once; the others wait for a
+// worker. A slow blame or search so holds up no more than its own worker,
+// and a status asked for meanwhile is answered as soon as one is free.
+const maxWorkers = 4
+
+// codeCancelled is the error code of a request cancelled before it
+// finished.
+const codeCancelled = -32800
+
+// CancelParams names the request a cancel is for.
+type CancelParams struct {
+	ID string `json:"id"`
+}
+
+// CancelResult reports whether the request was still waiting or running.
+// A request cancelled so gets a codeCancelled error instead of its result.
+type CancelResult struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+// mutatingMethods change the repository or its worktree. They run one at a
+// time and never alongside the reads, which do run side by side.
+var mutatingMethods = map[string]bool{
+	"git_branch_create":    true,
+	"git_checkout":         true,
+	"git_branch_delete":    true,
+	"git_tag_create":       true,
+	"git_fetch":            true,
+	"git_pull":             true,
+	"git_push":             true,
+	"git_submodule_update": true,
+	"git_resolve":          true,
+	"git_apply":            true,
+}
+
+var (
+	workers  = make(chan struct{}, maxWorkers)
+	repoLock sync.RWMutex
+
+	inflightMu sync.Mutex
+	inflight   = make(map[string]context.CancelFunc)
+)
+
+// serve handles req in a goroutine of its own, once a worker is free, and
+// writes its response. A request is cancelled by handleCancel while it
+// waits or runs: a handler that watches ctx, such as a log walk or a fetch,
+// stops early, and one that cannot, such as a blame, keeps its worker until
+// it returns, but the response is the cancellation either way. A change to
+// the repository that was under way when it was cancelled may still have
+// been made.
+func serve(req Request, wg *sync.WaitGroup) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inflightMu.Lock()
+	if _, dup := inflight[req.ID]; dup || req.ID == "" {
+		inflightMu.Unlock()
+		cancel()
+		writeResponse(errorResponse(req.ID, -32600, fmt.Sprintf("duplicate or missing request id: %q", req.ID)))
+		return
+	}
+	inflight[req.ID] = cancel
+	inflightMu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp := run(ctx, req)
+		// The request is done once it leaves inflight; a cancel that took
+		// it out first has been told it was cancelled, so it is.
+		inflightMu.Lock()
+		_, running := inflight[req.ID]
+		delete(inflight, req.ID)
+		inflightMu.Unlock()
+		cancel()
+		if !running {
+			resp = cancelledResponse(req.ID)
+		}
+		writeResponse(resp)
+	}()
+}
+
+// run waits for a worker and the repository lock, then handles req, giving
+// up as soon as ctx is done.
+func run(ctx context.Context, req Request) Response {
+	select {
+	case workers <- struct{}{}:
+	case <-ctx.Done():
+		return cancelledResponse(req.ID)
+	}
+
+	lock, unlock := repoLock.RLock, repoLock.RUnlock
+	if mutatingMethods[req.Method] {
+		lock, unlock = repoLock.Lock, repoLock.Unlock
+	}
+	done := make(chan Response, 1)
+	go func() {
+		// The handler keeps its worker until it returns, even once it is
+		// cancelled, so that no more than maxWorkers ever run.
+		defer func() { <-workers }()
+		lock()
+		defer unlock()
+		if ctx.Err() != nil {
+			done <- cancelledResponse(req.ID)
+			return
+		}
+		done <- handleRequest(ctx, req)
+	}()
+
+	select {
+	case resp := <-done:
+		return resp
+	case <-ctx.Done():
+		return cancelledResponse(req.ID)
+	}
+}
+
+// handleCancel cancels the request params names, if it is still waiting or
+// running.
+func handleCancel(id string, params json.RawMessage) Response {
+	var p CancelParams
+	if params != nil {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return errorResponse(id, -32602, fmt.Sprintf("invalid params: %v", err))
+		}
+	}
+	if p.ID == "" {
+		return errorResponse(id, -32602, "missing required param: id")
+	}
+
+	inflightMu.Lock()
+	cancel, ok := inflight[p.ID]
+	delete(inflight, p.ID)
+	inflightMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return Response{ID: id, Result: CancelResult{Cancelled: ok}}
+}
+
+func cancelledResponse(id string) Response {
+	return errorResponse(id, codeCancelled, "request cancelled")
+}
+
+func handleRequest(ctx context.Context, req Request) Response {
+	switch req.Method {
+	case "ping":
+		return Response{ID: req.ID, Result: "pong"}
+	case "git_status":
+		return handleGitStatus(req.ID, req.Params)
+	case "git_diff":
+		return handleGitDiff(req.ID, req.Params)
+	case "git_log":
+		return handleGitLog(ctx, req.ID, req.Params)
+	case "git_blame":
+		return handleGitBlame(req.ID, req.Params)
+	case "git_file_log":
+		return handleGitFileLog(ctx, req.ID, req.Params)
+	case "git_show":
+		return handleGitShow(ctx, req.ID, req.Params)
+	case "git_search":
+		return handleGitSearch(ctx, req.ID, req.Params)
+	case "git_branch_list":
+		return handleGitBranchList(req.ID, req.Params)
+	case "git_branch_create":
+		return handleGitBranchCreate(req.ID, req.Params)
+	case "git_checkout":
+		return handleGitCheckout(req.ID, req.Params)
+	case "git_branch_delete":
+		return handleGitBranchDelete(req.ID, req.Params)
+	case "git_tag_list":
+		return handleGitTagList(req.ID, req.Params)
+	case "git_tag_create":
+		return handleGitTagCreate(req.ID, req.Params)
+	case "git_describe":
+		return handleGitDescribe(req.ID, req.Params)
+	case "git_fetch":
+		return handleGitFetch(ctx, req.ID, req.Params)
+	case "git_pull":
+		return handleGitPull(ctx, req.ID, req.Params)
+	case "git_push":
+		return handleGitPush(ctx, req.ID, req.Params)
+	case "git_submodules":
+		return handleGitSubmodules(req.ID, req.Params)
+	case "git_submodule_update":
+		return handleGitSubmoduleUpdate(ctx, req.ID, req.Params)
+	case "git_conflicts":
+		return handleGitConflicts(req.ID, req.Params)
+	case "git_resolve":
+		return handleGitResolve(req.ID, req.Params)
+	case "git_check_ignore":
+		return handleGitCheckIgnore(req.ID, req.Params)
+	case "git_ls_files":
+		return handleGitLsFiles(req.ID, req.Params)
+	case "git_apply":
+		return handleGitApply(req.ID, req.Params)
+	case "git_info":
+		return handleGitInfo(req.ID, req.Params)
+	default:
+		return errorResponse(req.ID, -32601, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func main() {
+	// Direct all library logging to stderr — stdout is protocol-only.
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ltime | log.Lshortfile)
+
+	log.Println("osa-git sidecar ready")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	// 10MB buffer to handle large diffs in a single line.
+	scanner.Buffer(make([]byte, 0), 10*1024*1024)
+
+	var wg sync.WaitGroup
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			log.Printf("failed to parse request: %v", err)
+			writeResponse(errorResponse("", -32700, fmt.Sprintf("parse error: %v", err)))
+			continue
+		}
+
+		if req.Method == "cancel" {
+			writeResponse(handleCancel(req.ID, req.Params))
+			continue
+		}
+		serve(req, &wg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("stdin scanner error: %v", err)
+	}
+	wg.Wait()
+	// Let handlers still running after a cancel finish, so that none stops
+	// halfway through changing the repository.
+	for i := 0; i < maxWorkers; i++ {
+		workers <- struct{}{}
 	}
 
 	log.Println("stdin closed, exiting")
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+)
+
+// Aliases in tui.json give slash commands, with or without their arguments,
+// a shorter name, such as /r for /review or /m4 for "/model
+// anthropic/claude-sonnet-4". A typed alias is replaced by the command it
+// stands for before the command is routed, with the arguments typed after
+// it appended. An alias may stand for another alias, and each is expanded
+// once, so one named after a command can add to its arguments, such as
+// /review for "/review --strict". /help and the palette list them.
+
+// expandAlias replaces the alias text starts with by the command it stands
+// for, keeping the arguments after it. Text that does not start with an
+// alias is returned as is.
+func expandAlias(aliases map[string]string, text string) string {
+	seen := make(map[string]bool)
+	for {
+		name, args, _ := strings.Cut(text, " ")
+		cmd, ok := aliases[name]
+		if !ok || seen[name] || !validAlias(name, cmd) {
+			return text
+		}
+		seen[name] = true
+		text = strings.TrimSpace(strings.TrimSpace(cmd) + " " + args)
+	}
+}
+
+// validAlias reports whether name is a single slash word standing for a
+// slash command. Others are ignored, and flagged by osa doctor.
+func validAlias(name, cmd string) bool {
+	return len(name) > 1 && strings.HasPrefix(name, "/") && !strings.ContainsAny(name, " \t\n") &&
+		strings.HasPrefix(strings.TrimSpace(cmd), "/")
+}
+
+// aliasNames returns the valid aliases, sorted.
+func aliasNames(aliases map[string]string) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		if validAlias(name, aliases[name]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// aliasHelp lists the aliases for /help, or returns "" when there are none.
+func aliasHelp(aliases map[string]string) string {
+	names := aliasNames(aliases)
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n" + i18n.T("Aliases:") + "\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", name, strings.TrimSpace(aliases[name])))
+	}
+	return b.String()
+}
+
+// aliasPaletteItems lists the aliases in the command palette.
+func aliasPaletteItems(aliases map[string]string) []dialog.PaletteItem {
+	var items []dialog.PaletteItem
+	for _, name := range aliasNames(aliases) {
+		items = append(items, dialog.PaletteItem{
+			Name:        name,
+			Description: i18n.T("Alias for %s", strings.TrimSpace(aliases[name])),
+			Category:    "alias",
+		})
+	}
+	return items
+}
@@ -1,44 +1,133 @@
 package app
 
 import (
+	"context"
 	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/miosa/osa-tui/client"
 	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/doctor"
+	"github.com/miosa/osa-tui/drafts"
+	"github.com/miosa/osa-tui/guard"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/msg"
+	"github.com/miosa/osa-tui/panestatus"
+	"github.com/miosa/osa-tui/prompts"
+	"github.com/miosa/osa-tui/redact"
+	"github.com/miosa/osa-tui/schedule"
+	"github.com/miosa/osa-tui/speech"
 	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/transcript"
 	"github.com/miosa/osa-tui/ui/activity"
 	"github.com/miosa/osa-tui/ui/chat"
 	"github.com/miosa/osa-tui/ui/clipboard"
+	"github.com/miosa/osa-tui/ui/common"
 	"github.com/miosa/osa-tui/ui/completions"
 	"github.com/miosa/osa-tui/ui/dialog"
 	"github.com/miosa/osa-tui/ui/header"
 	"github.com/miosa/osa-tui/ui/input"
 	"github.com/miosa/osa-tui/ui/logo"
+	"github.com/miosa/osa-tui/ui/pinned"
 	"github.com/miosa/osa-tui/ui/selection"
 	"github.com/miosa/osa-tui/ui/sidebar"
 	"github.com/miosa/osa-tui/ui/status"
 	"github.com/miosa/osa-tui/ui/toast"
+	"github.com/miosa/osa-tui/ui/tools"
 )
 
 // ProfileDir is set by main to the user's profile directory path.
 var ProfileDir string
 
+// ProfileName is set by main from --profile; empty means the default profile.
+var ProfileName string
+
 func profileDirPath() string { return ProfileDir }
 
+// ColorModeFlag is set by main from --colors and overrides the configured
+// color mode when non-empty.
+var ColorModeFlag string
+
+// StartSession, ResumeLatest and StartPrompt are set by main from --session,
+// --resume and the prompt given on the command line. The session is opened
+// once the backend answers and the prompt is submitted when the TUI is idle.
+var (
+	StartSession string
+	ResumeLatest bool
+	StartPrompt  string
+)
+
+// TranscriptLog is set by main from --log-transcript; prompts, replies and
+// tool calls are appended to it when non-nil.
+var TranscriptLog *transcript.Log
+
+// ScreenReaderFlag, ReducedMotionFlag and MinimalFlag are set by main from
+// --accessible, --reduced-motion and --minimal. They enable the mode
+// regardless of the config.
+var (
+	ScreenReaderFlag  bool
+	ReducedMotionFlag bool
+	MinimalFlag       bool
+)
+
+// LocalesDir is set by main to the directory holding user translation catalogs.
+var LocalesDir string
+
+// ThemesDir is set by main to the directory holding custom theme files.
+var ThemesDir string
+
+// themeWatchInterval is how often ThemesDir is polled for changed files.
+const themeWatchInterval = 2 * time.Second
+
+// clockInterval is how often relative message timestamps are re-rendered.
+const clockInterval = time.Minute
+
+// gitPollInterval is how often the sidebar git status is refreshed.
+const gitPollInterval = 10 * time.Second
+
+// The startup banner stays up for bannerDuration, or bannerNewsDuration
+// when it shows what is new after an upgrade, unless a key is pressed.
+const (
+	bannerDuration     = 2 * time.Second
+	bannerNewsDuration = 5 * time.Second
+)
+
+// resizeDebounce is how long the terminal size must hold before the layout
+// follows it, so dragging a window edge re-wraps the chat once, not per step.
+const resizeDebounce = 40 * time.Millisecond
+
+// gitLocalTimeout bounds the local "git status" run when the backend has no
+// git endpoint.
+const gitLocalTimeout = 2 * time.Second
+
 const maxMessageSize = 100_000
 
+// maxPinnedFileSize caps files opened in the pinned pane.
+const maxPinnedFileSize = 1 << 20
+
+// replyExcerptLines caps how many lines of a message a reply quotes.
+const replyExcerptLines = 8
+
 func truncateResponse(s string) string {
 	if len(s) > maxMessageSize {
 		return s[:maxMessageSize] + "\n\n... (response truncated at 100KB)"
@@ -67,9 +156,96 @@ type ProgramReady struct{ Program *tea.Program }
 
 type bannerTimeout struct{}
 type commandsLoaded []client.CommandEntry
-type toolCountLoaded int
+
 type retryHealth struct{}
 
+// doctorDone carries the results of /doctor.
+type doctorDone []doctor.Result
+
+// imagePasted carries the file a pasted clipboard image was saved to.
+type imagePasted struct {
+	path string
+	err  error
+}
+
+// resizeSettled is sent resizeDebounce after a resize; seq matches the
+// latest one while the size has held.
+type resizeSettled struct{ seq int }
+
+// themeWatchTick carries the latest signature of ThemesDir.
+type themeWatchTick struct{ sig string }
+
+// clockTick is sent every clockInterval to age relative timestamps.
+type clockTick struct{}
+
+// gitStatusLoaded carries the workspace git status for the sidebar and
+// header. poll is set on results of the periodic refresh, which schedules the
+// next one; local once the backend turns out to have no git endpoint, so later
+// refreshes go straight to the local check.
+type gitStatusLoaded struct {
+	root   string
+	branch string
+	files  []sidebar.GitFile
+	err    error
+	poll   bool
+	local  bool
+}
+
+type gitPollTick struct{}
+
+// chordExpired ends the chord pending since seq when no key followed in time.
+type chordExpired struct{ seq int }
+
+// draftCountTick fires draftPause after the draft changed; seq tells whether
+// it has changed again since.
+type draftCountTick struct{ seq int }
+
+// draftCounted carries the token count of a draft.
+type draftCounted struct {
+	text   string
+	tokens int
+	exact  bool
+	window int  // the context window, 0 when the backend did not say
+	used   *int // the tokens the conversation uses, nil when unknown
+	err    error
+}
+
+// gitDiffLoaded carries the workspace diff requested by /pin diff.
+type gitDiffLoaded struct {
+	diff string
+	err  error
+}
+
+// schedulerJobsLoaded carries the job list for the /schedule manager.
+type schedulerJobsLoaded struct {
+	jobs []client.SchedulerJob
+	err  error
+}
+
+// scheduleCreated reports the outcome of /schedule "<when>" <prompt>.
+type scheduleCreated struct {
+	job *client.SchedulerJob
+	err error
+}
+
+// scheduleActionDone reports the outcome of a schedule manager action.
+type scheduleActionDone struct {
+	action dialog.ScheduleAction
+	err    error
+}
+
+// cancelAcked reports the backend's answer to a cancel request.
+type cancelAcked struct {
+	requestID string
+	err       error
+}
+
+// editorClosed is sent when an $EDITOR process started from the TUI exits.
+type editorClosed struct {
+	path string
+	err  error
+}
+
 // refreshTokenResult carries the outcome of an automatic token refresh.
 type refreshTokenResult struct {
 	token        string
@@ -91,10 +267,12 @@ type Model struct {
 	status   status.Model
 	agents   activity.AgentsModel
 	picker   dialog.PickerModel
+	themes   dialog.ThemePickerModel
 	toasts   toast.ToastsModel
 	palette  dialog.PaletteModel
 	plan     dialog.PlanModel
 	sidebar  sidebar.Model
+	pinned   pinned.Model
 
 	// New dialog models (Wave 4)
 	permissions dialog.PermissionsModel
@@ -102,17 +280,34 @@ type Model struct {
 	quit        dialog.QuitModel
 	models      dialog.ModelsModel
 	onboarding  dialog.OnboardingModel
+	keyManager  dialog.KeysModel
+	form        dialog.FormModel
+	scheduler   dialog.ScheduleModel
+	timeline    dialog.TimelineModel
+	roster      dialog.RosterModel
+	swarmWizard dialog.SwarmWizardModel
+	memory      dialog.MemoryModel
+	hooks       dialog.HooksModel
+	toolCatalog dialog.ToolsModel
+	audit       dialog.AuditModel
+	mcp         dialog.MCPModel
+	channels    dialog.ChannelsModel
+	budget      dialog.BudgetModel
 
 	// Text selection + clipboard (Wave 6)
 	selection selection.Model
 
-	state      State
+	state      State   // surface receiving keys: the top of modals, else base
+	base       State   // connecting, banner, idle or processing
+	modals     []State // open modal surfaces, topmost last
 	layout     Layout
 	layoutMode LayoutMode
 
-	client  *client.Client
-	sse     *client.SSEClient
-	program *tea.Program
+	client     *client.Client
+	sse        *client.SSEClient
+	ollamaPull *client.OllamaClient // in-flight onboarding model pull
+	pullModel  string               // model pulled by /model pull, shown as a toast
+	program    *tea.Program
 
 	sessionID      string
 	width          int
@@ -120,18 +315,91 @@ type Model struct {
 	keys           KeyMap
 	bgTasks        []string
 	commandEntries []client.CommandEntry
+	agentRoles     []client.AgentRole             // roster, for /agents and @name prompts
+	toolEntries    []client.ToolEntry             // catalog, for /tools and allowed_tools
+	toolUsage      map[string]dialog.ToolUsage    // calls seen per tool, for /tools
+	auditLog       map[string][]dialog.AuditEntry // tool calls per session, for /audit
+	swarmID        string                         // swarm launched by /swarm new, followed by the agents panel
+	swarmLaunching bool                           // its launch request is in flight
+	swarmAbandon   bool                           // cancelled while launching; cancel it once its ID is known
 	confirmQuit    bool
 
-	processingStart  time.Time
-	streamBuf        strings.Builder
-	thinkingBuf      strings.Builder // accumulates ThinkingDelta text for the chat ThinkingBox
-	sseReconnecting  bool            // true while a ReconnectListenCmd goroutine is in-flight
-	responseReceived bool            // true once SSE agent_response rendered for current req
-	cancelled        bool            // true when user cancelled the current request
+	processingStart time.Time
+	streamBuf       strings.Builder
+	thinkingBuf     strings.Builder    // accumulates ThinkingDelta text for the chat ThinkingBox
+	sseReconnecting bool               // true while a ReconnectListenCmd goroutine is in-flight
+	backendDown     bool               // the stream dropped; polling until the backend is back
+	recovering      bool               // the session of a backend that is back is being checked
+	responses       *responseRegistry  // REST and SSE deliveries of recent answers
+	frames          *frameStats        // Update and View timing, for /stats
+	startSession    string             // --session ID, until opened
+	startResume     bool               // --resume, until the latest session is opened
+	startSwitching  bool               // the startup session switch is in flight
+	startPrompt     string             // command-line prompt, until submitted
+	requestID       string             // ID of the latest orchestrate request; "" once it is cancelled
+	cancelPending   string             // request ID awaiting the backend's cancel confirmation
+	queue           []string           // prompts submitted while processing, sent in order
+	queuePaused     bool               // true after a failed turn until /queue send
+	altOf           string             // answer ID the in-flight retry is an alternative to
+	altModel        string             // provider/model override for the in-flight retry
+	retryPending    *chat.RetryTarget  // set by "/retry pick" until a model is chosen
+	errorDetails    string             // full text of the error offering actions, for "copy"
+	rateLimitedReq  string             // request ID of the latest provider rate limit
+	transcript      *transcript.Log    // nil unless --log-transcript
+	guard           *guard.Guard       // nil when destructive_guard is "off"
+	guardErr        error              // from compiling destructive_patterns
+	speaker         *speech.Speaker    // nil unless /speak is on
+	speakerErr      error              // why speech could not start
+	speechSplit     speech.Splitter    // streamed reply text not yet spoken
+	speechStreamed  bool               // the current reply streamed, so only its rest is left to speak
+	speechMuted     bool               // "/speak stop" or a cancel cut off the current reply
+	panes           *panestatus.Writer // nil unless pane status is published
+	unread          int                // replies since the last key press or focus, for pane status
+	guardPending    bool               // output paused on a destructive tool call
+	guardHeld       []tea.Msg          // output received while paused, in order
+	pasteConfirm    string             // how to send a large paste awaiting confirmation: "send", "queue" or "now"
+	sizeConfirm     string             // how to send a prompt too large for the context window, likewise
+	ctxLevel        int                // context thresholds crossed and notified: 0 none, 1 warn, 2 alert
+	ctxLevelSession string             // session ctxLevel is for
+	draftText       string             // the draft last scheduled for saving and a token count
+	draftCount      draftCounted       // the latest tokenizer count of a draft
+	draftSeq        int                // bumped on every draft change, to debounce counting
+	noTokenCount    bool               // the backend cannot count tokens; estimate locally
 
 	pendingProviderFilter string // set by "/model <provider>" to filter picker
+	pendingModelsDialog   bool   // set by "/models" to open the full models dialog
 	config                config.Config
 	refreshToken          string
+
+	gitPolling bool // periodic git status refresh is running
+	gitLocal   bool // the backend has no git endpoint; ask git directly
+
+	chordKeys []string // keys of a pending chord, such as ["space", "s"]
+	chordSeq  int      // bumped per chord key, to match its expiry
+
+	pendingSize tea.WindowSizeMsg // latest terminal size, applied once it settles
+	resizeSeq   int               // bumped per resize, to match its settle tick
+
+	formTemplate   prompts.Template              // template whose placeholders the form fills
+	feedbackFor    chat.Quote                    // reply whose thumbs down the form asks a comment for
+	quickFixes     []quickFix                    // suggested file changes awaiting confirmation
+	sessionCleanup *client.SessionCleanupRequest // previewed session cleanup awaiting confirmation
+	cmdForm        commandFormRun                // backend command whose form is open
+
+	replyTo     *client.ReplyRef // message quoted by the pending prompt
+	replyHeader string           // first quote line; the reply is dropped if it is edited out
+
+	lastPlan       string // most recent plan, for /pin plan
+	lastToolName   string // most recent tool result, for /pin tool
+	lastToolResult string
+
+	drag       dragTarget // pane divider being dragged with the mouse
+	dragAnchor int        // bottom row of the panel being resized
+
+	themeSig  string  // last seen signature of ThemesDir
+	themeErrs []error // errors from the last custom theme load
+	themeOrig string  // theme in use when the theme picker opened
+	localeErr error   // set when the configured locale has no catalog
 }
 
 // New constructs the root Model.  It applies the persisted theme and
@@ -140,87 +408,250 @@ func New(c *client.Client) Model {
 	workspace, _ := os.Getwd()
 	hdr := header.NewHeader()
 	hdr.SetWorkspace(workspace)
+	sb := sidebar.New()
+	sb.SetSessionInfo("", workspace)
 
 	cfg := config.Load(profileDirPath())
-	if cfg.Theme != "" {
+	localeErr := i18n.Init(cfg.Locale, LocalesDir)
+	if cfg.Locale == "" || cfg.Locale == "auto" {
+		localeErr = nil // no catalog for the environment locale: English
+	}
+	style.SetAccessibility(cfg.ScreenReader || ScreenReaderFlag, cfg.ReducedMotion || ReducedMotionFlag, MinimalFlag)
+	_, themeErrs := style.LoadUserThemes(ThemesDir)
+	if cfg.Theme != "" && cfg.Theme != "auto" {
 		style.SetTheme(cfg.Theme)
 	}
 
+	g, guardErr := loadGuard(cfg)
+	sp, speakerErr := loadSpeaker(cfg)
+
+	ch := chat.New(80, 20)
+	ch.SetTimestamps(parseTimestamps(cfg.Timestamps))
+	ch.SetNoticesHidden(cfg.HideNotices)
+
 	layoutMode := LayoutCompact
 	if cfg.SidebarOpen {
 		layoutMode = LayoutSidebar
 	}
 
+	in := input.New()
+	in.SetSnippets(cfg.Snippets)
+	st := status.New()
+	st.SetSegments(cfg.StatusSegments)
+	warnAt, alertAt := contextThresholds(cfg)
+	st.SetContextThresholds(warnAt, alertAt)
+	sb.SetContextThresholds(warnAt, alertAt)
+
 	return Model{
-		header:      hdr,
-		chat:        chat.New(80, 20),
-		input:       input.New(),
-		activity:    activity.New(),
-		tasks:       activity.NewTasks(),
-		status:      status.New(),
-		plan:        dialog.NewPlan(),
-		agents:      activity.NewAgents(),
-		picker:      dialog.NewPicker(),
-		toasts:      toast.NewToasts(),
-		palette:     dialog.NewPalette(),
-		sidebar:     sidebar.New(),
-		permissions: dialog.NewPermissions(),
-		sessions:    dialog.NewSessions(),
-		quit:        dialog.NewQuit(),
-		models:      dialog.NewModels(),
-		onboarding:  dialog.NewOnboarding(),
-		selection:   selection.New(),
-		state:       StateConnecting,
-		layoutMode:  layoutMode,
-		client:      c,
-		keys:        DefaultKeyMap(),
-		width:       80,
-		height:      24,
-		config:      cfg,
+		header:       hdr,
+		chat:         ch,
+		input:        in,
+		activity:     activity.New(),
+		tasks:        activity.NewTasks(),
+		status:       st,
+		plan:         dialog.NewPlan(),
+		agents:       activity.NewAgents(),
+		picker:       dialog.NewPicker(),
+		themes:       dialog.NewThemePicker(),
+		toasts:       toast.NewToasts(),
+		palette:      dialog.NewPalette(),
+		sidebar:      sb,
+		pinned:       pinned.New(),
+		permissions:  dialog.NewPermissions(),
+		sessions:     dialog.NewSessions(),
+		quit:         dialog.NewQuit(),
+		models:       dialog.NewModels(),
+		onboarding:   dialog.NewOnboarding(),
+		keyManager:   dialog.NewKeys(),
+		scheduler:    dialog.NewSchedule(),
+		timeline:     dialog.NewTimeline(),
+		roster:       dialog.NewRoster(),
+		memory:       dialog.NewMemory(),
+		hooks:        dialog.NewHooks(),
+		toolCatalog:  dialog.NewTools(),
+		audit:        dialog.NewAudit(),
+		mcp:          dialog.NewMCP(),
+		channels:     dialog.NewChannels(),
+		budget:       dialog.NewBudget(),
+		selection:    selection.New(),
+		state:        StateConnecting,
+		base:         StateConnecting,
+		layoutMode:   layoutMode,
+		client:       c,
+		keys:         DefaultKeyMap(),
+		width:        80,
+		height:       24,
+		config:       cfg,
+		themeSig:     style.UserThemesSignature(ThemesDir),
+		themeErrs:    themeErrs,
+		localeErr:    localeErr,
+		transcript:   TranscriptLog,
+		guard:        g,
+		guardErr:     guardErr,
+		speaker:      sp,
+		speakerErr:   speakerErr,
+		panes:        openPaneStatus(cfg),
+		responses:    newResponseRegistry(),
+		frames:       newFrameStats(cfg.FrameBudgetMS),
+		startSession: StartSession,
+		startResume:  ResumeLatest,
+		startPrompt:  StartPrompt,
 	}
 }
 
 // SetRefreshToken stores the refresh token for automatic re-authentication.
 func (m *Model) SetRefreshToken(t string) { m.refreshToken = t }
 
+// Close stops what the model leaves running after the program exits, such
+// as a reply being read aloud, and removes its pane status.
+func (m Model) Close() {
+	if m.speaker != nil {
+		m.speaker.Close()
+	}
+	if m.panes != nil {
+		m.panes.Remove()
+	}
+}
+
 // -- Init ---------------------------------------------------------------------
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.checkHealth(), m.input.Focus(), func() tea.Msg { return tea.RequestWindowSize() })
+	return tea.Batch(m.checkHealth(), m.focusInput(), func() tea.Msg { return tea.RequestWindowSize() }, watchThemes(), watchClock(), tea.Raw(ansi.SetModeLightDark))
 }
 
 // -- Update -------------------------------------------------------------------
 
 func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
+	start := time.Now()
+	mm, cmd := m.update(rawMsg)
+	if next, ok := mm.(Model); ok {
+		if next.layout.TermWidth > 0 && next.layout != next.computeLayout() {
+			next.recomputeLayout() // a panel, the input or the status bar changed height
+			mm = next
+		}
+		next.publishPaneStatus(false)
+	}
+	m.frames.recordUpdate(rawMsg, time.Since(start))
+	return mm, cmd
+}
+
+// update handles a message; Update wraps it to time each call. Messages
+// handled on behalf of another go through update, so they are not timed twice.
+func (m Model) update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.guardPending {
+		if mm, cmd, ok := m.holdForGuard(rawMsg); ok {
+			return mm, cmd
+		}
+	}
+
 	switch v := rawMsg.(type) {
 
 	case tea.WindowSizeMsg:
-		m.width = v.Width
-		m.height = v.Height
-		m.layout = ComputeLayout(
-			v.Width, v.Height, m.layoutMode,
-			countLines(m.status.View()),
-			countLines(m.tasks.View()),
-			countLines(m.agents.View()),
-		)
-		m.chat.SetSize(m.layout.ChatWidth, m.layout.ChatHeight)
-		m.sidebar.SetSize(m.layout.SidebarWidth, m.layout.SidebarHeight)
-		m.plan.SetWidth(v.Width - 4)
-		m.picker.SetWidth(v.Width - 4)
-		m.input.SetWidth(v.Width)
-		m.header.SetWidth(v.Width)
-		m.permissions.SetSize(v.Width, v.Height)
-		m.sessions.SetSize(v.Width, v.Height)
-		m.quit.SetSize(v.Width, v.Height)
-		m.models.SetSize(v.Width, v.Height)
-		m.onboarding.SetSize(v.Width, v.Height)
+		if m.layout.TermWidth == 0 {
+			m.applySize(v) // first size: lay out at once
+			return m, nil
+		}
+		m.pendingSize = v
+		m.resizeSeq++
+		seq := m.resizeSeq
+		return m, tea.Tick(resizeDebounce, func(time.Time) tea.Msg { return resizeSettled{seq: seq} })
+
+	case resizeSettled:
+		if v.seq == m.resizeSeq {
+			m.applySize(m.pendingSize)
+		}
 		return m, nil
 
+	case tea.PasteMsg:
+		if m.state == StateForm {
+			var cmd tea.Cmd
+			m.form, cmd = m.form.Update(v)
+			return m, cmd
+		}
+		if m.state == StateSwarmWizard {
+			var cmd tea.Cmd
+			m.swarmWizard, cmd = m.swarmWizard.Update(v)
+			return m, cmd
+		}
+		if m.state == StateMemory && m.memory.Editing() {
+			var cmd tea.Cmd
+			m.memory, cmd = m.memory.Update(v)
+			return m, cmd
+		}
+		if m.state == StateChannels && m.channels.Editing() {
+			var cmd tea.Cmd
+			m.channels, cmd = m.channels.Update(v)
+			return m, cmd
+		}
+		if m.state == StateBudget && m.budget.Editing() {
+			var cmd tea.Cmd
+			m.budget, cmd = m.budget.Update(v)
+			return m, cmd
+		}
+		if m.state == StateIdle || m.state == StateProcessing {
+			if mm, cmd, ok := m.handleImagePaste(v.Content); ok {
+				return mm, cmd
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(v)
+			return m.watchDraft(cmd)
+		}
+
+	// The completions popup answers through the input it belongs to.
+	case completions.SelectedMsg, completions.DismissMsg:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(v)
+		return m.watchDraft(cmd)
+
+	case imagePasted:
+		if v.err != nil {
+			m.toasts.AddWithActions(i18n.T("Image paste failed: %v", v.err), toast.ToastError,
+				toast.Action{ID: "paste-image", Label: i18n.T("Retry")})
+			return m, m.tickCmd()
+		}
+		return m.attachImage(v.path)
+
+	// -- Terminal appearance --
+
+	case tea.FocusMsg, tea.ResumeMsg:
+		m.unread = 0
+		var cmds []tea.Cmd
+		if m.autoTheme() {
+			cmds = append(cmds, tea.RequestBackgroundColor)
+		}
+		if m.gitPolling {
+			cmds = append(cmds, m.fetchGitStatus(false))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.BackgroundColorMsg:
+		return m.applyAutoTheme(v.IsDark())
+
+	case uv.DarkColorSchemeEvent:
+		return m.applyAutoTheme(true)
+
+	case uv.LightColorSchemeEvent:
+		return m.applyAutoTheme(false)
+
+	case tea.ColorProfileMsg:
+		return m.handleColorProfile(v)
+
 	case tea.MouseClickMsg:
 		switch m.state {
 		case StateIdle, StateProcessing, StatePlanReview:
+			if t := m.dividerAt(v.Mouse()); t != dragNone {
+				m.startDrag(t)
+				return m, nil
+			}
+			if mouse := v.Mouse(); mouse.X < m.layout.SidebarWidth {
+				y := mouse.Y - m.mainTop()
+				if path, ok := m.sidebar.ItemAt(y); ok {
+					return m.openInEditor(path)
+				}
+				return m, nil
+			}
 			var cmd tea.Cmd
 			m.chat, cmd = m.chat.Update(v)
 			return m, cmd
@@ -231,9 +662,30 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tea.MouseMotionMsg:
+		if m.drag != dragNone {
+			m.dragTo(v.Mouse())
+		}
+		return m, nil
+
+	case tea.MouseReleaseMsg:
+		if m.drag != dragNone {
+			m.drag = dragNone
+			m.savePaneSizes()
+		}
+		return m, nil
+
 	case tea.MouseWheelMsg:
 		switch m.state {
 		case StateIdle, StateProcessing, StatePlanReview:
+			if mouse := v.Mouse(); m.layout.PinnedWidth > 0 && mouse.X >= m.width-m.layout.PinnedWidth {
+				if mouse.Button == tea.MouseWheelUp {
+					m.pinned.ScrollUp(3)
+				} else if mouse.Button == tea.MouseWheelDown {
+					m.pinned.ScrollDown(3)
+				}
+				return m, nil
+			}
 			var cmd tea.Cmd
 			m.chat, cmd = m.chat.Update(v)
 			return m, cmd
@@ -241,6 +693,7 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyPressMsg:
+		m.unread = 0
 		return m.handleKey(v)
 
 	// -- Program lifecycle --
@@ -260,6 +713,34 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case retryHealth:
 		return m, m.checkHealth()
 
+	case backendPoll:
+		return m, m.checkBackend()
+
+	case backendHealth:
+		return m.handleBackendHealth(v)
+
+	case sessionRecovered:
+		return m.handleSessionRecovered(v)
+
+	case sessionCompacted:
+		return m.handleSessionCompacted(v)
+
+	case feedbackSent:
+		return m.handleFeedbackSent(v)
+
+	case sessionsCleanedUp:
+		return m.handleSessionsCleanedUp(v)
+	case quickFixesApplied:
+		return m.handleQuickFixesApplied(v)
+
+	case themeWatchTick:
+		return m.handleThemeWatch(v)
+
+	case clockTick:
+		m.chat.RefreshTimestamps()
+		m.publishPaneStatus(true)
+		return m, watchClock()
+
 	case bannerTimeout:
 		if m.state == StateBanner {
 			return m, m.checkOnboarding()
@@ -269,24 +750,65 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case msg.OnboardingStatusResult:
 		if v.Err != nil {
 			// Fail-open: backend unreachable, skip onboarding with notice
-			m.state = StateIdle
+			m.setBase(StateIdle)
 			m.recomputeLayout()
-			m.chat.AddSystemMessage("Could not check setup status — run /doctor to verify configuration")
-			return m, m.input.Focus()
+			m.chat.AddSystemMessage(i18n.T("Could not check setup status — run /doctor to verify configuration"))
+			m, cmd := m.submitStartPrompt()
+			return m, tea.Batch(m.focusInput(), cmd)
 		}
 		if !v.NeedsOnboarding {
-			m.state = StateIdle
+			m.setBase(StateIdle)
 			m.recomputeLayout()
-			return m, m.input.Focus()
+			m, cmd := m.submitStartPrompt()
+			return m, tea.Batch(m.focusInput(), cmd)
 		}
 		m.onboarding.SetProviders(v.Providers, v.SystemInfo)
 		m.onboarding.SetTemplates(v.Templates)
 		m.onboarding.SetMachines(v.Machines)
 		m.onboarding.SetChannels(v.Channels)
 		m.onboarding.SetSize(m.width, m.height)
-		m.state = StateOnboarding
+		m.setBase(StateIdle)
+		m.pushModal(StateOnboarding)
+		return m, nil
+
+	case dialog.OnboardingVerifyRequest:
+		return m, m.verifyProvider(v)
+
+	case msg.OnboardingVerifyResult:
+		m.onboarding.SetVerifyResult(v)
+		return m, nil
+
+	case dialog.OnboardingPullRequest:
+		if m.program == nil {
+			return m, func() tea.Msg {
+				return client.OllamaPullDoneEvent{Model: v.Model, Err: fmt.Errorf("program not ready")}
+			}
+		}
+		m.ollamaPull = client.NewOllama()
+		return m, m.ollamaPull.PullCmd(m.program, v.Model)
+
+	case dialog.OnboardingPullCancel:
+		if m.ollamaPull != nil {
+			m.ollamaPull.Close()
+		}
+		return m, nil
+
+	case client.OllamaPullProgressEvent:
+		if v.Model == m.pullModel {
+			m.toasts.SetProgress("pull:"+v.Model, i18n.T("Pulling %s · %s", v.Model, v.Status), v.Completed, v.Total,
+				toast.Action{ID: "pull-cancel", Label: i18n.T("Cancel")})
+			return m, nil
+		}
+		m.onboarding.SetPullProgress(v.Status, v.Completed, v.Total)
 		return m, nil
 
+	case client.OllamaPullDoneEvent:
+		m.ollamaPull = nil
+		if v.Model == m.pullModel {
+			return m.finishPull(v)
+		}
+		return m, m.onboarding.SetPullDone(v.Err, v.Cancelled)
+
 	case dialog.OnboardingDone:
 		// Fire POST to complete setup
 		return m, m.completeOnboarding(v)
@@ -297,13 +819,14 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case msg.OnboardingComplete:
-		m.state = StateIdle
+		m.closeModal(StateOnboarding)
 		m.recomputeLayout()
 		m.header.SetHealth(msg.HealthResult{Provider: v.Provider, Model: v.Model})
 		m.status.SetProviderInfo(v.Provider, v.Model)
 		m.sidebar.SetModelInfo(v.Provider, v.Model)
-		m.chat.AddSystemMessage(fmt.Sprintf("Setup complete — using %s/%s", v.Provider, v.Model))
-		return m, m.input.Focus()
+		m.chat.AddSystemMessage(i18n.T("Setup complete — using %s/%s", v.Provider, v.Model))
+		m, cmd := m.submitStartPrompt()
+		return m, tea.Batch(m.focusInput(), cmd)
 
 	// -- Orchestration --
 
@@ -324,38 +847,142 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 				Description: cmd.Description,
 				Category:    cmd.Category,
 				Icon:        "/",
+				Usage:       cmd.Usage,
+				Example:     cmd.Example,
+				Details:     cmd.Details,
 			}
 		}
 		m.input.SetCommands(names)
 		m.input.SetCompletions(items)
 		return m, nil
 
-	case toolCountLoaded:
-		m.header.SetToolCount(int(v))
-		m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
+	case gitStatusLoaded:
+		return m.handleGitStatus(v)
+
+	case gitDiffLoaded:
+		switch {
+		case errors.Is(v.err, client.ErrNotSupported):
+			m.chat.AddSystemWarning(i18n.T("Diffs are not supported by this backend."))
+		case v.err != nil:
+			m.chat.AddSystemError(i18n.T("Git diff failed: %v", v.err))
+		case strings.TrimSpace(v.diff) == "":
+			m.chat.AddSystemMessage(i18n.T("No uncommitted changes."))
+		default:
+			return m.pin(pinned.KindDiff, "git diff", v.diff)
+		}
+		return m, nil
+
+	case draftCountTick:
+		if v.seq != m.draftSeq || m.draftText == "" {
+			return m, nil
+		}
+		m.keepDraft()
+		if m.noTokenCount {
+			return m, nil
+		}
+		return m, m.countDraft(m.draftText)
+
+	case draftCounted:
+		if v.text != m.draftText {
+			return m, nil
+		}
+		if errors.Is(v.err, client.ErrNotSupported) {
+			m.noTokenCount = true
+		}
+		if v.err != nil {
+			m.input.SetDraftTokens(estimateTokens(v.text), false)
+			return m, nil
+		}
+		m.draftCount = v
+		m.input.SetDraftTokens(v.tokens, v.exact)
+		if v.window > 0 {
+			used, _ := m.input.ContextBudget()
+			if v.used != nil {
+				used = *v.used
+			}
+			m.input.SetContextBudget(used, v.window)
+		}
+		return m, nil
+
+	case chordExpired:
+		if v.seq != m.chordSeq || len(m.chordKeys) == 0 {
+			return m, nil
+		}
+		return m.abandonChord(), nil
+
+	case gitPollTick:
+		if m.layoutMode == LayoutSidebar {
+			return m, m.fetchGitStatus(true)
+		}
+		return m, tea.Tick(gitPollInterval, func(time.Time) tea.Msg { return gitPollTick{} })
+
+	case editorClosed:
+		if v.err != nil {
+			m.chat.AddSystemError(i18n.T("Editor failed for %s: %v", v.path, v.err))
+		}
+		return m, tea.Batch(m.focusInput(), m.fetchGitStatus(false))
+
+	case budgetLoaded:
+		return m.handleBudgetLoaded(v)
+
+	case dialog.BudgetLimit:
+		return m, m.setBudgetLimit(v)
+
+	case budgetSaved:
+		return m.handleBudgetSaved(v)
+
+	case doctorDone:
+		report := i18n.T("Doctor") + "\n\n" + doctor.Format(v)
+		if doctor.Failed(v) > 0 {
+			m.chat.AddSystemWarning(report)
+		} else {
+			m.chat.AddSystemMessage(report)
+		}
 		return m, nil
 
+	case toolsLoaded:
+		return m.handleToolsLoaded(v)
+
 	// -- SSE lifecycle --
 
 	case client.SSEConnectedEvent:
 		m.sessionID = v.SessionID
+		m.syncSessionModel()
 		m.sseReconnecting = false
-		return m, nil
+		m.status.SetConnection(status.Connected)
+		cmds := []tea.Cmd{m.fetchBudget(false)} // the session's spend
+		if m.backendDown && !m.recovering {
+			var cmd tea.Cmd
+			m, cmd = m.startRecovery()
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
 
 	case client.SSEDisconnectedEvent:
+		if v.Closed {
+			// Replaced by a new stream, or closed for good.
+			if m.sse == nil {
+				m.status.SetConnection(status.Offline)
+			}
+			return m, nil
+		}
 		if m.sseReconnecting {
 			return m, nil
 		}
 		if m.sessionID != "" && m.sse != nil && !m.sse.IsClosed() && m.program != nil {
 			m.sseReconnecting = true
-			return m, m.sse.ReconnectListenCmd(m.program)
+			m.status.SetConnection(status.Reconnecting)
+			poll := m.backendLost()
+			return m, tea.Batch(m.sse.ReconnectListenCmd(m.program), poll)
 		}
+		m.status.SetConnection(status.Offline)
 		return m, nil
 
 	case client.SSEReconnectingEvent:
-		m.chat.AddSystemWarning(fmt.Sprintf(
-			"Connection lost. Reconnecting (attempt %d/%d)...", v.Attempt, client.MaxReconnects,
-		))
+		m.status.SetConnection(status.Reconnecting)
+		if v.Attempt == 1 {
+			m.chat.AddSystemWarning(i18n.T("Connection lost. Reconnecting..."))
+		}
 		return m, nil
 
 	case client.SSEAuthFailedEvent:
@@ -363,9 +990,9 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.refreshToken != "" {
 			return m, m.doRefreshToken(m.refreshToken)
 		}
-		m.chat.AddSystemWarning("Authentication expired. Use /login to re-authenticate.")
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.chat.AddSystemWarning(i18n.T("Authentication expired. Use /login to re-authenticate."))
+		m.setBase(StateIdle)
+		return m, m.focusInput()
 
 	case refreshTokenResult:
 		return m.handleRefreshTokenResult(v)
@@ -374,10 +1001,10 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case msg.LoginResult:
 		if v.Err != nil {
-			m.chat.AddSystemError(fmt.Sprintf("Login failed: %v", v.Err))
+			m.chat.AddSystemError(i18n.T("Login failed: %v", v.Err))
 		} else {
 			m.refreshToken = v.RefreshToken
-			m.chat.AddSystemMessage(fmt.Sprintf("Authenticated (token expires in %ds)", v.ExpiresIn))
+			m.chat.AddSystemMessage(i18n.T("Authenticated (token expires in %ds)", v.ExpiresIn))
 			if m.sse != nil {
 				m.closeSSE()
 			}
@@ -389,9 +1016,9 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case msg.LogoutResult:
 		if v.Err != nil {
-			m.chat.AddSystemError(fmt.Sprintf("Logout error: %v", v.Err))
+			m.chat.AddSystemError(i18n.T("Logout error: %v", v.Err))
 		} else {
-			m.chat.AddSystemMessage("Logged out")
+			m.chat.AddSystemMessage(i18n.T("Logged out"))
 			m.closeSSE()
 		}
 		return m, nil
@@ -406,9 +1033,14 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// -- Streaming / SSE agent events --
 
+	case client.SSEBatch:
+		return m.handleSSEBatch(v)
+
 	case client.StreamingTokenEvent:
+		m.activity, _ = m.activity.Update(msg.StreamingDelta{Text: v.Text})
 		m.streamBuf.WriteString(v.Text)
 		m.chat.SetStreamingContent(m.streamBuf.String())
+		m.speakDelta(v.Text)
 		return m, nil
 
 	case client.ThinkingDeltaEvent:
@@ -417,22 +1049,43 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		m.chat.SetThinkingContent(m.thinkingBuf.String())
 		return m, nil
 
+	case client.ThinkingFinishedEvent:
+		m.chat.FinishThinking(v.DurationMs)
+		return m, nil
+
 	case client.AgentResponseEvent:
 		m.streamBuf.Reset()
 		return m.handleClientAgentResponse(v)
 
 	case client.LLMRequestEvent:
-		m.activity, _ = m.activity.Update(msg.LLMRequest{Iteration: v.Iteration})
+		m.activity, _ = m.activity.Update(msg.LLMRequest{Iteration: v.Iteration, MaxIterations: v.MaxIterations})
 		return m, nil
 
 	case client.ToolCallStartEvent:
 		m.activity, _ = m.activity.Update(msg.ToolCallStart{Name: v.Name, Args: v.Args})
 		m.chat.TrackToolStart(v.Name, v.Args)
+		m.record(transcript.Record{Kind: transcript.KindToolCall, Tool: v.Name, Args: v.Args})
+		m.sidebar.TouchFile(tools.TouchedFile(v.Name, v.Args))
+		if rule, ok := m.guard.Match(v.Args); ok {
+			m = m.guardToolCall(v, rule)
+		}
+		return m, nil
+
+	case client.ToolOutputEvent:
+		m.activity, _ = m.activity.Update(msg.ToolOutput{Name: v.Name, Output: v.Output, Offset: v.Offset})
+		if m.base == StateProcessing {
+			m.chat.SetProcessingView(m.activity.View())
+		}
 		return m, nil
 
 	case client.ToolCallEndEvent:
+		m.recordToolUse(v)
+		m.recordAudit("", v.Name, v.Args, v.DurationMs, v.Success)
 		m.activity, _ = m.activity.Update(msg.ToolCallEnd{Name: v.Name, DurationMs: v.DurationMs, Success: v.Success})
 		m.chat.TrackToolEnd(v.Name, v.DurationMs, v.Success)
+		if m.gitPolling && touchesFiles(v.Name) {
+			return m, m.fetchGitStatus(false)
+		}
 		return m, nil
 
 	case client.LLMResponseEvent:
@@ -447,7 +1100,8 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case client.ContextPressureEvent:
 		m.status.SetContext(v.Utilization, v.MaxTokens, v.EstimatedTokens)
 		m.sidebar.SetContext(v.Utilization, v.MaxTokens, v.EstimatedTokens)
-		return m, nil
+		m.input.SetContextBudget(v.EstimatedTokens, v.MaxTokens)
+		return m, m.notifyContextPressure(v.Utilization)
 
 	// -- Tasks --
 
@@ -518,83 +1172,130 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	// -- Swarm events --
 
 	case client.SwarmStartedEvent:
-		m.chat.AddSystemMessage(fmt.Sprintf(
+		if m.swarmLaunching && m.swarmID == "" {
+			m.swarmID = v.SwarmID // may arrive before the launch reply
+		}
+		m.chat.AddSystemMessage(i18n.T(
 			"Swarm launched: %s pattern with %d agents", v.Pattern, v.AgentCount,
 		))
 		return m, nil
 
 	case client.SwarmCompletedEvent:
+		m.swarmEnded(v.SwarmID)
 		m.activity.Stop()
 		m.chat.ClearProcessingView()
 		m.status.SetActive(false)
-		m.state = StateIdle
+		m.setBase(StateIdle)
 		if v.ResultPreview != "" {
 			m.chat.AddAgentMessage(v.ResultPreview, nil, 0, fmt.Sprintf("swarm/%s", v.Pattern))
 		} else {
-			m.chat.AddSystemMessage(fmt.Sprintf("Swarm %s (%s) completed.", v.SwarmID, v.Pattern))
+			m.chat.AddSystemMessage(i18n.T("Swarm %s (%s) completed.", v.SwarmID, v.Pattern))
 		}
-		return m, m.input.Focus()
+		return m, m.focusInput()
 
 	case client.SwarmFailedEvent:
+		m.swarmEnded(v.SwarmID)
 		m.activity.Stop()
 		m.chat.ClearProcessingView()
 		m.status.SetActive(false)
-		m.state = StateIdle
-		m.chat.AddSystemError(fmt.Sprintf("Swarm %s failed: %s", v.SwarmID, v.Reason))
-		return m, m.input.Focus()
+		m.setBase(StateIdle)
+		m.chat.AddSystemError(i18n.T("Swarm %s failed: %s", v.SwarmID, v.Reason))
+		return m, m.focusInput()
 
 	case client.SwarmCancelledEvent:
+		m.swarmEnded(v.SwarmID)
 		m.activity.Stop()
 		m.chat.ClearProcessingView()
 		m.status.SetActive(false)
-		m.state = StateIdle
-		m.chat.AddSystemWarning(fmt.Sprintf("Swarm %s was cancelled.", v.SwarmID))
-		return m, m.input.Focus()
+		m.setBase(StateIdle)
+		m.chat.AddSystemWarning(i18n.T("Swarm %s was cancelled.", v.SwarmID))
+		return m, m.focusInput()
 
 	case client.SwarmTimeoutEvent:
+		m.swarmEnded(v.SwarmID)
 		m.activity.Stop()
 		m.chat.ClearProcessingView()
 		m.status.SetActive(false)
-		m.state = StateIdle
-		m.chat.AddSystemError(fmt.Sprintf("Swarm %s timed out.", v.SwarmID))
-		return m, m.input.Focus()
+		m.setBase(StateIdle)
+		m.chat.AddSystemError(i18n.T("Swarm %s timed out.", v.SwarmID))
+		return m, m.focusInput()
+
+	case client.SwarmAgentStartedEvent:
+		return m.handleSwarmAgentStarted(v)
+
+	case client.SwarmAgentCompletedEvent:
+		return m.handleSwarmAgentCompleted(v)
 
 	case client.SwarmIntelligenceStartedEvent:
-		m.chat.AddSystemMessage(fmt.Sprintf(
+		m.chat.AddSystemMessage(i18n.T(
 			"Swarm intelligence (%s) started: %s", v.Type, v.Task,
 		))
 		return m, nil
 
 	case client.SwarmIntelligenceRoundEvent:
-		m.chat.AddSystemMessage(fmt.Sprintf("Swarm intelligence round %d", v.Round))
+		m.chat.AddSystemMessage(i18n.T("Swarm intelligence round %d", v.Round))
 		return m, nil
 
 	case client.SwarmIntelligenceConvergedEvent:
-		m.chat.AddSystemMessage(fmt.Sprintf("Swarm intelligence converged at round %d", v.Round))
+		m.chat.AddSystemMessage(i18n.T("Swarm intelligence converged at round %d", v.Round))
 		return m, nil
 
 	case client.SwarmIntelligenceCompletedEvent:
-		statusStr := "completed"
+		summary := i18n.T("Swarm intelligence completed after %d rounds", v.Rounds)
 		if v.Converged {
-			statusStr = "converged"
+			summary = i18n.T("Swarm intelligence converged after %d rounds", v.Rounds)
 		}
-		m.chat.AddSystemMessage(fmt.Sprintf(
-			"Swarm intelligence %s after %d rounds", statusStr, v.Rounds,
-		))
+		m.chat.AddSystemMessage(summary)
 		return m, nil
 
 	// -- Hook / budget events --
 
 	case client.HookBlockedEvent:
-		m.chat.AddSystemError(fmt.Sprintf("Blocked by %s: %s", v.HookName, v.Reason))
+		m.chat.AddSystemError(i18n.T("Blocked by %s: %s", v.HookName, v.Reason))
+		m.noteHookBlock(v)
+		return m, nil
+
+	case cancelAcked:
+		return m.handleCancelAcked(v), nil
+
+	case swarmCancelled:
+		if v.err != nil {
+			m.chat.AddSystemError(i18n.T("Failed to cancel swarm %s: %v", v.id, v.err))
+		}
+		return m, nil
+
+	case client.RequestCancelledEvent:
+		return m.confirmCancelled(v.RequestID, v.Iteration), nil
+
+	case client.ScheduleResultEvent:
+		if v.Status == "error" {
+			m.chat.AddSystemError(i18n.T("[%s] Scheduled run failed: %s", v.Name, v.Output))
+		} else {
+			m.chat.AddSystemMessage(fmt.Sprintf("[%s]\n%s", v.Name, v.Output))
+		}
 		return m, nil
 
 	case client.BudgetWarningEvent:
-		m.chat.AddSystemWarning(fmt.Sprintf("Budget at %.0f%%: %s", v.Utilization*100, v.Message))
+		m.chat.AddSystemWarning(i18n.T("Budget at %.0f%%: %s", v.Utilization*100, v.Message))
+		m.status.SetBudget(v.Period, v.Spent, v.Limit)
+		m.sidebar.SetBudgetSpend(v.Period, v.Spent, v.Limit)
+		m.recomputeLayout()
 		return m, nil
 
 	case client.BudgetExceededEvent:
-		m.chat.AddSystemError(fmt.Sprintf("Budget exceeded: %s", v.Message))
+		m.chat.AddSystemError(i18n.T("Budget exceeded: %s", v.Message))
+		m.status.SetBudget(v.Period, v.Spent, v.Limit)
+		m.sidebar.SetBudgetSpend(v.Period, v.Spent, v.Limit)
+		m.recomputeLayout()
+		return m, nil
+
+	case client.ProviderRateLimitedEvent:
+		if v.RequestID != "" && v.RequestID != m.requestID {
+			return m, nil
+		}
+		m.rateLimitedReq = v.RequestID
+		m.status.SetRateLimit(v.Provider, time.Duration(v.RetryAfter)*time.Second, time.Now())
+		m.recomputeLayout()
 		return m, nil
 
 	// -- Tool results --
@@ -602,6 +1303,8 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case client.ToolResultEvent:
 		m.activity, _ = m.activity.Update(msg.ToolResult{Name: v.Name, Result: v.Result, Success: v.Success})
 		m.chat.TrackToolResult(v.Name, v.Result, v.Success)
+		m.lastToolName, m.lastToolResult = v.Name, v.Result
+		m.record(transcript.Record{Kind: transcript.KindToolResult, Tool: v.Name, Text: v.Result, Success: &v.Success})
 		return m, nil
 
 	// -- Signal classification --
@@ -614,11 +1317,13 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	// -- Parse warnings (toasts) --
 
 	case client.SSEParseWarning:
-		m.toasts.Add(v.Message, toast.ToastWarning)
+		log.Printf("sse: %s", v.Message)
+		m.toasts.AddWithActions(v.Message, toast.ToastWarning, toast.Action{ID: "open-log", Label: i18n.T("Open log")})
 		return m, m.tickCmd()
 
 	case msg.SSEParseWarning:
-		m.toasts.Add(v.Message, toast.ToastWarning)
+		log.Printf("sse: %s", v.Message)
+		m.toasts.AddWithActions(v.Message, toast.ToastWarning, toast.Action{ID: "open-log", Label: i18n.T("Open log")})
 		return m, m.tickCmd()
 
 	// -- UI toggle events --
@@ -639,6 +1344,9 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		_ = config.Save(profileDirPath(), m.config)
 		m.recomputeLayout()
+		if m.layoutMode == LayoutSidebar && m.gitPolling {
+			return m, m.fetchGitStatus(false)
+		}
 		return m, nil
 
 	// -- Tick --
@@ -646,7 +1354,7 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case msg.TickMsg:
 		m.toasts.Tick()
 		needTick := false
-		if m.state == StateProcessing {
+		if m.base == StateProcessing {
 			m.status.SetStats(
 				time.Since(m.processingStart),
 				m.activity.ToolCount(),
@@ -682,16 +1390,30 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case dialog.PickerCancel:
 		m.picker.Clear()
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.closeModal(StateModelPicker)
+		return m, m.focusInput()
+
+	case dialog.ThemePreview:
+		m.previewTheme(v.Name)
+		return m, nil
+
+	case dialog.ThemeChoice:
+		m.closeThemePicker()
+		m, cmd := m.setTheme(v.Name)
+		return m, tea.Batch(m.focusInput(), cmd)
+
+	case dialog.ThemeCancel:
+		m.previewTheme(m.themeOrig)
+		m.closeThemePicker()
+		return m, m.focusInput()
 
 	case dialog.PaletteExecuteMsg:
-		m.state = StateIdle
+		m.closeModal(StatePalette)
 		return m.submitInput(v.Command)
 
 	case dialog.PaletteDismissMsg:
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.closeModal(StatePalette)
+		return m, m.focusInput()
 
 	// -- New dialog messages (Wave 4) --
 
@@ -704,27 +1426,142 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 	case dialog.ModelChoice:
 		return m.handleModelsChoice(v)
 
+	case dialog.ModelFavoriteToggle:
+		return m.handleModelFavorite(v)
+
 	case dialog.ModelCancel:
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.closeModal(StateModels)
+		m.retryPending = nil
+		return m, m.focusInput()
+
+	case msg.ProviderKeysResult:
+		return m.handleProviderKeys(v)
+
+	case dialog.KeyAction:
+		return m, m.applyKeyAction(v)
+
+	case dialog.FormSubmit:
+		return m.handleFormSubmit(v)
+
+	case dialog.ScheduleAction:
+		return m, m.applyScheduleAction(v)
+
+	case dialog.RosterChoice:
+		return m.handleRosterChoice(v)
+
+	case dialog.MemoryAction:
+		return m, m.applyMemoryAction(v)
+
+	case memoriesLoaded:
+		return m.handleMemoriesLoaded(v)
+
+	case memoryActionDone:
+		return m.handleMemoryActionDone(v)
+
+	case dialog.HookToggle:
+		return m, m.toggleHook(v)
+
+	case hooksLoaded:
+		return m.handleHooksLoaded(v)
+
+	case hookToggled:
+		return m.handleHookToggled(v)
+
+	case dialog.ToolToggle:
+		return m.handleToolToggle(v)
+
+	case dialog.AuditExport:
+		return m.exportAudit(v.Entries, "")
+
+	case client.AgentToolCallEvent:
+		return m.handleAgentToolCall(v)
+
+	case dialog.MCPAction:
+		return m.handleMCPAction(v)
+
+	case mcpServersLoaded:
+		return m.handleMCPServersLoaded(v)
+
+	case mcpActionDone:
+		return m.handleMCPActionDone(v)
+
+	case dialog.ChannelAction:
+		return m, m.applyChannelAction(v)
+
+	case channelsLoaded:
+		return m.handleChannelsLoaded(v)
+
+	case channelActionDone:
+		return m.handleChannelActionDone(v)
+
+	case dialog.SwarmLaunch:
+		return m.handleSwarmLaunch(v)
+
+	case swarmLaunched:
+		return m.handleSwarmLaunched(v)
+
+	case agentsLoaded:
+		return m.handleAgentsLoaded(v)
+
+	case dialog.TimelineJump:
+		m.closeModal(StateTimeline)
+		m.chat.JumpTo(v.Index)
+		return m, m.focusInput()
+
+	case schedulerJobsLoaded:
+		return m.handleSchedulerJobs(v)
+
+	case scheduleCreated:
+		return m.handleScheduleCreated(v)
+
+	case scheduleActionDone:
+		if v.err != nil {
+			m.scheduler.SetStatus(fmt.Sprintf("%s: %v", v.action.Name, v.err), true)
+			return m, nil
+		}
+		switch {
+		case v.action.Action == "delete":
+			m.scheduler.SetStatus(i18n.T("Removed %s", v.action.Name), false)
+		case v.action.Enabled:
+			m.scheduler.SetStatus(i18n.T("Enabled %s", v.action.Name), false)
+		default:
+			m.scheduler.SetStatus(i18n.T("Paused %s", v.action.Name), false)
+		}
+		return m, m.fetchSchedulerJobs()
+
+	case dialog.FormCancel:
+		m.closeModal(StateForm)
+		return m, m.focusInput()
+
+	case msg.ProviderKeyUpdated:
+		m.keyManager.SetResult(v.Provider, v.Removed, v.Err)
+		if v.Err != nil {
+			return m, nil
+		}
+		return m, m.listProviderKeys()
 
 	case dialog.QuitConfirmed:
 		m.closeSSE()
 		return m, tea.Quit
 
 	case dialog.QuitCancelled:
-		m.state = StateIdle
+		m.closeModal(StateQuit)
 		m.confirmQuit = false
-		return m, m.input.Focus()
+		return m, m.focusInput()
 	}
 
 	// Forward to activity during processing (handles spinner ticks, etc.)
-	if m.state == StateProcessing {
+	if m.base == StateProcessing {
 		var cmd tea.Cmd
 		m.activity, cmd = m.activity.Update(rawMsg)
 		cmds = append(cmds, cmd)
 	}
 
+	// Forward spinner ticks to the onboarding connection test.
+	if m.hasModal(StateOnboarding) {
+		cmds = append(cmds, m.onboarding.UpdateSpinner(rawMsg))
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -733,7 +1570,9 @@ func (m Model) Update(rawMsg tea.Msg) (tea.Model, tea.Cmd) {
 // View returns the tea.View for the current frame.
 // AltScreen, MouseMode, and ReportFocus are set on every frame.
 func (m Model) View() tea.View {
+	start := time.Now()
 	content := m.renderView()
+	m.frames.recordView(time.Since(start))
 	v := tea.NewView(content)
 	v.AltScreen = true
 	v.MouseMode = tea.MouseModeCellMotion
@@ -756,17 +1595,56 @@ func (m Model) renderView() string {
 	if m.state == StateSessions {
 		return m.sessions.View()
 	}
-	if m.state == StateModels {
-		return m.models.View()
+	if m.state == StateKeys {
+		return m.keyManager.View()
 	}
-	if m.state == StateOnboarding {
-		return m.onboarding.View()
+	if m.state == StateForm {
+		return m.form.View()
 	}
-
-	var sections []string
-
-	switch m.state {
-	case StateConnecting:
+	if m.state == StateSchedule {
+		return m.scheduler.View()
+	}
+	if m.state == StateTimeline {
+		return m.timeline.View()
+	}
+	if m.state == StateRoster {
+		return m.roster.View()
+	}
+	if m.state == StateSwarmWizard {
+		return m.swarmWizard.View()
+	}
+	if m.state == StateMemory {
+		return m.memory.View()
+	}
+	if m.state == StateHooks {
+		return m.hooks.View()
+	}
+	if m.state == StateTools {
+		return m.toolCatalog.View()
+	}
+	if m.state == StateAudit {
+		return m.audit.View()
+	}
+	if m.state == StateMCP {
+		return m.mcp.View()
+	}
+	if m.state == StateChannels {
+		return m.channels.View()
+	}
+	if m.state == StateBudget {
+		return m.budget.View()
+	}
+	if m.state == StateModels {
+		return m.models.View()
+	}
+	if m.state == StateOnboarding {
+		return m.onboarding.View()
+	}
+
+	var sections []string
+
+	switch m.base {
+	case StateConnecting:
 		sections = append(sections, m.renderConnecting())
 
 	case StateBanner:
@@ -784,12 +1662,12 @@ func (m Model) renderView() string {
 
 		// Task checklist
 		if m.tasks.HasTasks() {
-			sections = append(sections, m.tasks.View())
+			sections = append(sections, m.tasksView())
 		}
 
 		// Multi-agent panel (processing state only)
-		if m.state == StateProcessing && m.agents.IsActive() {
-			sections = append(sections, m.agents.View())
+		if m.base == StateProcessing && m.agents.IsActive() {
+			sections = append(sections, m.agentsView())
 		}
 
 		// Plan overlay (replaces input when reviewing)
@@ -802,15 +1680,25 @@ func (m Model) renderView() string {
 			sections = append(sections, m.picker.View())
 		}
 
+		// Theme picker, below the chat it previews on
+		if m.state == StateThemePicker {
+			sections = append(sections, m.themes.View())
+		}
+
 		// Status bar
 		sections = append(sections, m.status.View())
 
-		// Input (hidden during plan review and model picker)
-		if m.state != StatePlanReview && m.state != StateModelPicker {
+		// Input (hidden during plan review and the pickers)
+		if m.state != StatePlanReview && m.state != StateModelPicker && m.state != StateThemePicker {
 			sections = append(sections, m.input.View())
 		}
 	}
 
+	// Frame timing, from /stats overlay
+	if line := m.frames.overlayView(m.width); line != "" {
+		sections = append(sections, line)
+	}
+
 	// Toasts overlay
 	if m.toasts.HasToasts() {
 		sections = append(sections, m.toasts.View(m.width))
@@ -825,6 +1713,9 @@ func (m Model) renderView() string {
 // The chat model's View() already renders the welcome screen when there are no messages.
 func (m Model) renderMain() string {
 	chatView := m.chat.View()
+	if m.layout.PinnedWidth > 0 {
+		chatView = lipgloss.JoinHorizontal(lipgloss.Top, chatView, m.pinned.View())
+	}
 
 	if m.layout.Mode == LayoutSidebar && m.layout.SidebarWidth > 0 {
 		return lipgloss.JoinHorizontal(lipgloss.Top, m.sidebar.View(), chatView)
@@ -837,13 +1728,23 @@ func (m Model) renderMain() string {
 func (m Model) handleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	switch m.state {
 	case StateIdle:
-		return m.handleIdleKey(k)
+		mm, cmd := m.handleIdleKey(k)
+		return mm.(Model).watchDraft(cmd)
 	case StateProcessing:
-		return m.handleProcessingKey(k)
+		mm, cmd := m.handleProcessingKey(k)
+		return mm.(Model).watchDraft(cmd)
 	case StatePlanReview:
 		return m.handlePlanKey(k)
 	case StateModelPicker:
 		return m.handlePickerKey(k)
+	case StateThemePicker:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Cancel) {
+			m.themes.Clear()
+			return m, func() tea.Msg { return dialog.ThemeCancel{} }
+		}
+		var cmd tea.Cmd
+		m.themes, cmd = m.themes.Update(k)
+		return m, cmd
 	case StatePalette:
 		return m.handlePaletteKey(k)
 	case StatePermissions:
@@ -854,28 +1755,183 @@ func (m Model) handleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleSessionsKey(k)
 	case StateModels:
 		return m.handleModelsKey(k)
+	case StateKeys:
+		return m.handleKeysKey(k)
+	case StateForm:
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(k)
+		return m, cmd
+	case StateSchedule:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.scheduler.Busy() {
+			m.closeModal(StateSchedule)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.scheduler, cmd = m.scheduler.Update(k)
+		return m, cmd
+	case StateTimeline:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
+			m.closeModal(StateTimeline)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.timeline, cmd = m.timeline.Update(k)
+		return m, cmd
+	case StateRoster:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
+			m.closeModal(StateRoster)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.roster, cmd = m.roster.Update(k)
+		return m, cmd
+	case StateSwarmWizard:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.swarmWizard.Busy() {
+			m.closeModal(StateSwarmWizard)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.swarmWizard, cmd = m.swarmWizard.Update(k)
+		return m, cmd
+	case StateMemory:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.memory.Busy() {
+			m.closeModal(StateMemory)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.memory, cmd = m.memory.Update(k)
+		return m, cmd
+	case StateHooks:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
+			m.closeModal(StateHooks)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.hooks, cmd = m.hooks.Update(k)
+		return m, cmd
+	case StateTools:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
+			m.closeModal(StateTools)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.toolCatalog, cmd = m.toolCatalog.Update(k)
+		return m, cmd
+	case StateAudit:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
+			m.closeModal(StateAudit)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.audit, cmd = m.audit.Update(k)
+		return m, cmd
+	case StateMCP:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.mcp.Busy() {
+			m.closeModal(StateMCP)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.mcp, cmd = m.mcp.Update(k)
+		return m, cmd
+	case StateChannels:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.channels.Busy() {
+			m.closeModal(StateChannels)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.channels, cmd = m.channels.Update(k)
+		return m, cmd
+	case StateBudget:
+		if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.budget.Busy() {
+			m.closeModal(StateBudget)
+			return m, m.focusInput()
+		}
+		var cmd tea.Cmd
+		m.budget, cmd = m.budget.Update(k)
+		return m, cmd
 	case StateOnboarding:
 		cmd := m.onboarding.Update(k)
 		return m, cmd
 	case StateBanner:
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.setBase(StateIdle)
+		return m, m.focusInput()
 	}
 	return m, nil
 }
 
 func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if mm, cmd, ok := m.handlePaneKey(k); ok {
+		return mm, cmd
+	}
+	if mm, cmd, ok := m.handleToastKey(k); ok {
+		return mm, cmd
+	}
+	if mm, cmd, ok := m.handleErrorActionKey(k); ok {
+		return mm, cmd
+	}
+	if mm, cmd, ok := m.handleChordKey(k); ok {
+		return mm, cmd
+	}
 	switch {
 	case key.Matches[tea.KeyPressMsg](k, m.keys.Escape):
+		if m.chat.HasSelection() {
+			m.chat.ClearSelection()
+			return m, nil
+		}
 		m.input.Reset()
 		return m, nil
 
+	case key.Matches[tea.KeyPressMsg](k, m.keys.SelectPrev) && !m.input.CompletionsVisible():
+		m.chat.SelectPrev()
+		return m, nil
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.SelectNext) && !m.input.CompletionsVisible():
+		m.chat.SelectNext()
+		return m, nil
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Reply):
+		return m.replyToMessage()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Retry):
+		return m.handleRetryCommand("")
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Reveal):
+		return m.toggleReveal()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Timeline):
+		return m.openTimeline()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.RateUp) && m.chat.HasSelection():
+		return m.rateReply("up", "", false)
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.RateDown) && m.chat.HasSelection():
+		return m.rateReply("down", "", true)
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Apply) && m.chat.HasSelection() && m.input.Value() == "":
+		return m.handleApplyCommand("")
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleExpand):
+		if !m.input.TogglePastePreview() {
+			m.chat.ToggleToolCalls()
+		}
+		return m, nil
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleThinking):
+		m.chat.ToggleThinking()
+		return m, nil
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleRaw):
+		m.chat.ToggleRaw()
+		return m, nil
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Notices):
+		return m.setNoticesHidden(!m.config.HideNotices)
+
 	case key.Matches[tea.KeyPressMsg](k, m.keys.Cancel):
-		if m.input.Value() == "" {
+		if m.input.Value() == "" && !m.input.HasPastes() {
 			m.quit = dialog.NewQuit()
 			m.quit.SetWidth(m.width)
-			m.state = StateQuit
-			m.input.Blur()
+			m.pushModal(StateQuit)
 			return m, nil
 		}
 		m.input.Reset()
@@ -887,11 +1943,19 @@ func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-	case key.Matches[tea.KeyPressMsg](k, m.keys.Submit):
-		text := strings.TrimSpace(m.input.Value())
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Submit) && !m.input.SnippetsVisible():
+		text := strings.TrimSpace(m.input.Content())
 		if text == "" {
+			// Enter on an empty input expands or collapses a long answer.
+			m.chat.ToggleCollapsed()
 			return m, nil
 		}
+		if mm, ok := m.guardPromptSize(text, "send"); ok {
+			return mm, nil
+		}
+		if mm, ok := m.confirmLargePaste(text, "send"); ok {
+			return mm, nil
+		}
 		m.input.Submit(text)
 		return m.submitInput(text)
 
@@ -903,7 +1967,7 @@ func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, m.createSession()
 
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleSidebar):
-		return m.Update(msg.ToggleSidebar{})
+		return m.update(msg.ToggleSidebar{})
 
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ScrollTop):
 		m.chat.ScrollToTop()
@@ -921,6 +1985,12 @@ func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		updated, cmd := m.openPalette()
 		return updated, cmd
 
+	case key.Matches[tea.KeyPressMsg](k, m.keys.RepeatLast):
+		return m.repeatLastCommand()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.CycleModel):
+		return m.cycleFavoriteModel()
+
 	case key.Matches[tea.KeyPressMsg](k, m.keys.PageUp),
 		key.Matches[tea.KeyPressMsg](k, m.keys.PageDown):
 		var cmd tea.Cmd
@@ -944,11 +2014,18 @@ func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case key.Matches[tea.KeyPressMsg](k, m.keys.RetryFail):
+		if m.input.Value() == "" {
+			if t, ok := m.chat.FailedPrompt(); ok {
+				return m.retry(t, "", "")
+			}
+		}
+
 	case key.Matches[tea.KeyPressMsg](k, m.keys.CopyMessage):
 		if m.input.Value() == "" {
 			if text := m.chat.CopyLastMessage(); text != "" {
 				_ = clipboard.Copy(text)
-				m.toasts.Add("Copied to clipboard", toast.ToastInfo)
+				m.toasts.Add(i18n.T("Copied to clipboard"), toast.ToastInfo)
 				return m, m.tickCmd()
 			}
 		}
@@ -961,20 +2038,58 @@ func (m Model) handleIdleKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleProcessingKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if mm, cmd, ok := m.handlePaneKey(k); ok {
+		return mm, cmd
+	}
+	if mm, cmd, ok := m.handleToastKey(k); ok {
+		return mm, cmd
+	}
+	if mm, cmd, ok := m.handleErrorActionKey(k); ok {
+		return mm, cmd
+	}
 	switch {
 	case key.Matches[tea.KeyPressMsg](k, m.keys.Cancel),
 		key.Matches[tea.KeyPressMsg](k, m.keys.Escape):
-		m.cancelled = true
-		m.state = StateIdle
-		m.activity.Stop()
-		m.chat.ClearProcessingView()
-		m.chat.ClearPendingToolCalls()
-		m.status.SetActive(false)
-		m.chat.AddSystemMessage("Request cancelled.")
-		return m, m.input.Focus()
+		return m.cancelCurrent()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.Submit) && !m.input.CompletionsVisible():
+		text := strings.TrimSpace(m.input.Content())
+		if text == "" {
+			return m, nil
+		}
+		if mm, ok := m.guardPromptSize(text, "queue"); ok {
+			return mm, nil
+		}
+		if mm, ok := m.confirmLargePaste(text, "queue"); ok {
+			return mm, nil
+		}
+		m.input.Submit(text)
+		if text == "/queue" || strings.HasPrefix(text, "/queue ") {
+			return m.handleQueueCommand(strings.TrimSpace(strings.TrimPrefix(text, "/queue")))
+		}
+		m = m.enqueue(text, false)
+		m.toasts.Add(i18n.T("Prompt queued"), toast.ToastInfo)
+		return m, m.tickCmd()
+
+	case key.Matches[tea.KeyPressMsg](k, m.keys.SendNow):
+		text := strings.TrimSpace(m.input.Content())
+		if text == "" {
+			return m, nil
+		}
+		if mm, ok := m.guardPromptSize(text, "now"); ok {
+			return mm, nil
+		}
+		if mm, ok := m.confirmLargePaste(text, "now"); ok {
+			return mm, nil
+		}
+		m.input.Submit(text)
+		m = m.enqueue(text, true)
+		return m.cancelCurrent()
 
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleExpand):
-		m.activity.SetExpanded(!m.activity.IsExpanded())
+		if !m.input.TogglePastePreview() {
+			m.activity.SetExpanded(!m.activity.IsExpanded())
+		}
 		return m, nil
 
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleThinking):
@@ -984,13 +2099,13 @@ func (m Model) handleProcessingKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleBackground):
 		m.bgTasks = append(m.bgTasks, m.activity.Summary())
 		m.status.SetBackgroundCount(len(m.bgTasks))
-		m.state = StateIdle
+		m.setBase(StateIdle)
 		m.chat.ClearProcessingView()
-		m.toasts.Add("Task moved to background", toast.ToastInfo)
-		return m, m.input.Focus()
+		m.toasts.Add(i18n.T("Task moved to background"), toast.ToastInfo)
+		return m, m.focusInput()
 
 	case key.Matches[tea.KeyPressMsg](k, m.keys.ToggleSidebar):
-		return m.Update(msg.ToggleSidebar{})
+		return m.update(msg.ToggleSidebar{})
 	}
 
 	if key.Matches[tea.KeyPressMsg](k, m.keys.PageUp) ||
@@ -1000,7 +2115,39 @@ func (m Model) handleProcessingKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	return m, nil
+	// Typing continues while the agent works; Enter queues the prompt.
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(k)
+	return m, cmd
+}
+
+// cancelCurrent cancels the running request locally and on the backend,
+// then sends the next queued prompt, if any.
+func (m Model) cancelCurrent() (Model, tea.Cmd) {
+	m = m.dropGuard()
+	m.cutSpeech()
+	m.setBase(StateIdle)
+	m.activity.Stop()
+	m.chat.ClearProcessingView()
+	m.chat.ClearPendingToolCalls()
+	m.status.SetActive(false)
+	cmds := []tea.Cmd{m.focusInput()}
+	if m.swarmID != "" {
+		m.chat.AddSystemMessage(i18n.T("Cancelling swarm..."))
+		cmds = append(cmds, m.cancelSwarm(m.swarmID))
+	} else if m.requestID == "" || m.sessionID == "" {
+		m.swarmAbandon = m.swarmLaunching
+		m.chat.AddSystemMessage(i18n.T("Request cancelled."))
+	} else {
+		m.cancelPending = m.requestID
+		m.chat.AddSystemMessage(i18n.T("Cancelling request..."))
+		cmds = append(cmds, m.cancelOrchestrate(m.requestID))
+	}
+	m.requestID = "" // whatever still arrives for it is dropped
+	m.swarmID, m.swarmLaunching = "", false
+	m.queuePaused = false
+	m, cmd := m.sendQueued()
+	return m, tea.Batch(append(cmds, cmd)...)
 }
 
 func (m Model) handlePlanKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
@@ -1030,16 +2177,61 @@ func (m Model) openPalette() (Model, tea.Cmd) {
 
 	// Local-only commands first.
 	localCmds := []dialog.PaletteItem{
-		{Name: "/help", Description: "Show available commands", Category: "system"},
-		{Name: "/clear", Description: "Clear chat history", Category: "system"},
-		{Name: "/theme", Description: "List or switch themes", Category: "system"},
-		{Name: "/models", Description: "Browse & switch models", Category: "config"},
-		{Name: "/sessions", Description: "List all sessions", Category: "session"},
-		{Name: "/session new", Description: "Create new session", Category: "session"},
-		{Name: "/bg", Description: "List background tasks", Category: "system"},
-		{Name: "/exit", Description: "Exit OSA", Category: "system"},
+		{Name: "/help", Description: i18n.T("Show available commands"), Category: "system"},
+		{Name: "/clear", Description: i18n.T("Clear chat history"), Category: "system"},
+		{Name: "/theme", Description: i18n.T("Preview and switch themes"), Category: "system"},
+		{Name: "/lang", Description: i18n.T("List or switch interface language"), Category: "system"},
+		{Name: "/profile", Description: i18n.T("List profiles"), Category: "config"},
+		{Name: "/models", Description: i18n.T("Browse & switch models"), Category: "config"},
+		{Name: "/keys", Description: i18n.T("Manage provider API keys"), Category: "config"},
+		{Name: "/prefs", Description: i18n.T("Set reply language, verbosity and code comment style"), Category: "config"},
+		{Name: "/sessions", Description: i18n.T("List all sessions"), Category: "session"},
+		{Name: "/sessions cleanup", Description: i18n.T("Archive or delete old and short sessions"), Category: "session"},
+		{Name: "/session new", Description: i18n.T("Create new session"), Category: "session"},
+		{Name: "/agents", Description: i18n.T("Browse agent roles and address one"), Category: "session"},
+		{Name: "/swarm new", Description: i18n.T("Launch a swarm of agents step by step"), Category: "session"},
+		{Name: "/system", Description: i18n.T("Add to the system prompt for this session"), Category: "session"},
+		{Name: "/env", Description: i18n.T("Set environment variables for this session"), Category: "session"},
+		{Name: "/memory", Description: i18n.T("Browse, edit and pin saved memories"), Category: "memory"},
+		{Name: "/tools", Description: i18n.T("Browse tools and disable them for this session"), Category: "session"},
+		{Name: "/audit", Description: i18n.T("List the tool calls of this session and export them"), Category: "session"},
+		{Name: "/hooks", Description: i18n.T("Show hooks and recent blocks; toggle hooks"), Category: "security"},
+		{Name: "/mcp", Description: i18n.T("Manage MCP servers and see their tools"), Category: "system"},
+		{Name: "/channels", Description: i18n.T("Set up, enable and test messaging channels"), Category: "system"},
+		{Name: "/budget", Description: i18n.T("See spend against limits and change them"), Category: "system"},
+		{Name: "/retry", Description: i18n.T("Retry the latest prompt"), Category: "session"},
+		{Name: "/retry pick", Description: i18n.T("Retry the latest prompt with another model"), Category: "session"},
+		{Name: "/compact", Description: i18n.T("Compact the conversation to free context"), Category: "session"},
+		{Name: "/feedback", Description: i18n.T("Rate the latest agent reply up or down"), Category: "session"},
+		{Name: "/apply", Description: i18n.T("Apply the files suggested in the latest agent reply"), Category: "session"},
+		{Name: "/reveal", Description: i18n.T("Reveal or mask secrets in the chat"), Category: "system"},
+		{Name: "/paste-image", Description: i18n.T("Attach the image on the clipboard"), Category: "session"},
+		{Name: "/timeline", Description: i18n.T("Jump through the session by time"), Category: "session"},
+		{Name: "/notices", Description: i18n.T("Show or hide system notices in the chat"), Category: "system"},
+		{Name: "/export", Description: i18n.T("Export the conversation as Markdown"), Category: "session"},
+		{Name: "/timestamps", Description: i18n.T("Show message times as relative, absolute or not at all"), Category: "system"},
+		{Name: "/speak", Description: i18n.T("Read agent replies aloud"), Category: "system"},
+		{Name: "/doctor", Description: i18n.T("Check the backend, terminal and config"), Category: "system"},
+		{Name: "/stats", Description: i18n.T("Show update and render timing"), Category: "system"},
+		{Name: "/bg", Description: i18n.T("List background tasks"), Category: "system"},
+		{Name: "/notifications", Description: i18n.T("List dismissed notifications"), Category: "system"},
+		{Name: "/prompts", Description: i18n.T("List prompt templates"), Category: "prompts"},
+		{Name: "/schedule", Description: i18n.T("Manage scheduled prompts"), Category: "prompts"},
+		{Name: "/exit", Description: i18n.T("Exit OSA"), Category: "system"},
 	}
 	items = append(items, localCmds...)
+	items = append(items, aliasPaletteItems(m.config.Aliases)...)
+
+	// Saved prompt templates.
+	if tmpls, err := prompts.List(prompts.Dir(profileDirPath())); err == nil {
+		for _, t := range tmpls {
+			items = append(items, dialog.PaletteItem{
+				Name:        "/prompts use " + t.Name,
+				Description: templateSummary(t),
+				Category:    "prompts",
+			})
+		}
+	}
 
 	// Backend commands (skip duplicates).
 	seen := make(map[string]bool)
@@ -1057,17 +2249,75 @@ func (m Model) openPalette() (Model, tea.Cmd) {
 		}
 	}
 
-	m.state = StatePalette
-	m.input.Blur()
+	// Recently run commands first, described like the command they run.
+	var recent []dialog.PaletteItem
+	for _, text := range m.config.RecentCommands[:min(len(m.config.RecentCommands), maxPaletteRecent)] {
+		item := dialog.PaletteItem{Name: text, Category: "recent", Recent: true}
+		base, _, _ := strings.Cut(text, " ")
+		for _, it := range items {
+			if it.Name == base {
+				item.Description, item.Category = it.Description, it.Category
+				break
+			}
+		}
+		recent = append(recent, item)
+	}
+	items = append(recent, items...)
+
+	m.pushModal(StatePalette)
 	openCmd := m.palette.Open(items, m.width, m.height)
 	return m, openCmd
 }
 
 // -- Input submission ---------------------------------------------------------
 
+// Caps on the recent commands kept in tui.json and listed in the palette.
+const (
+	maxRecentCommands = 20
+	maxPaletteRecent  = 5
+)
+
+// recordRecentCommand moves a slash command, with its arguments, to the
+// front of the recent list. Commands that quit, log in or carry a secret are
+// not kept.
+func (m *Model) recordRecentCommand(text string) {
+	base, _, _ := strings.Cut(text, " ")
+	if !strings.HasPrefix(text, "/") || strings.Contains(text, "\n") {
+		return
+	}
+	switch base {
+	case "/exit", "/quit", "/login", "/logout":
+		return
+	}
+	if _, n := redact.Scrub(text); n > 0 {
+		return
+	}
+	recent := []string{text}
+	for _, c := range m.config.RecentCommands {
+		if c != text && len(recent) < maxRecentCommands {
+			recent = append(recent, c)
+		}
+	}
+	m.config.RecentCommands = recent
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		log.Printf("save recent commands: %v", err)
+	}
+}
+
+// repeatLastCommand runs the most recent slash command again.
+func (m Model) repeatLastCommand() (Model, tea.Cmd) {
+	if len(m.config.RecentCommands) == 0 {
+		m.toasts.Add(i18n.T("No command to repeat yet"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	return m.submitInput(m.config.RecentCommands[0])
+}
+
 // submitInput routes typed text to the appropriate handler.
 func (m Model) submitInput(text string) (Model, tea.Cmd) {
+	text = expandAlias(m.config.Aliases, text)
 	m.chat.AddUserMessage(text)
+	m.recordRecentCommand(text)
 
 	switch {
 	case text == "/exit" || text == "/quit":
@@ -1084,95 +2334,283 @@ func (m Model) submitInput(text string) (Model, tea.Cmd) {
 		return m, nil
 
 	case strings.HasPrefix(text, "/login"):
-		m.toasts.Add("Authenticating...", toast.ToastInfo)
+		m.toasts.Add(i18n.T("Authenticating..."), toast.ToastInfo)
 		return m, tea.Batch(m.doLogin(strings.TrimSpace(strings.TrimPrefix(text, "/login"))), m.tickCmd())
 
 	case strings.HasPrefix(text, "/logout"):
-		m.toasts.Add("Logging out...", toast.ToastInfo)
+		m.toasts.Add(i18n.T("Logging out..."), toast.ToastInfo)
 		return m, tea.Batch(m.doLogout(), m.tickCmd())
 
 	case text == "/sessions":
-		m.toasts.Add("Loading sessions...", toast.ToastInfo)
+		m.toasts.Add(i18n.T("Loading sessions..."), toast.ToastInfo)
 		return m, tea.Batch(m.listSessions(), m.tickCmd())
 
+	case text == "/sessions cleanup" || strings.HasPrefix(text, "/sessions cleanup "):
+		return m.handleSessionCleanupCommand(strings.TrimPrefix(text, "/sessions cleanup"))
+
 	case text == "/session" || strings.HasPrefix(text, "/session "):
 		arg := strings.TrimSpace(strings.TrimPrefix(text, "/session"))
 		if arg == "" {
-			m.chat.AddSystemMessage("Current session: " + shortID(m.sessionID))
+			m.chat.AddSystemMessage(i18n.T("Current session: %s", shortID(m.sessionID)))
 			return m, nil
 		}
 		if arg == "new" {
-			m.toasts.Add("Creating session...", toast.ToastInfo)
+			m.toasts.Add(i18n.T("Creating session..."), toast.ToastInfo)
 			return m, tea.Batch(m.createSession(), m.tickCmd())
 		}
-		m.toasts.Add(fmt.Sprintf("Switching to session %s...", arg), toast.ToastInfo)
+		m.toasts.Add(i18n.T("Switching to session %s...", arg), toast.ToastInfo)
 		return m, tea.Batch(m.switchSession(arg), m.tickCmd())
 
+	case text == "/keys":
+		m.toasts.Add(i18n.T("Loading provider keys..."), toast.ToastInfo)
+		m.input.Blur()
+		return m, tea.Batch(m.listProviderKeys(), m.tickCmd())
+
 	case text == "/models":
-		m.toasts.Add("Loading models...", toast.ToastInfo)
+		m.pendingModelsDialog = true
+		m.toasts.Add(i18n.T("Loading models..."), toast.ToastInfo)
 		m.input.Blur()
 		return m, tea.Batch(m.fetchModels(), m.tickCmd())
 
 	case text == "/model":
 		m.pendingProviderFilter = strings.ToLower(m.header.Provider())
-		m.toasts.Add(fmt.Sprintf("Loading %s models...", m.header.Provider()), toast.ToastInfo)
+		m.toasts.Add(i18n.T("Loading %s models...", m.header.Provider()), toast.ToastInfo)
 		m.input.Blur()
 		return m, tea.Batch(m.fetchModels(), m.tickCmd())
 
+	case text == "/model pin" || strings.HasPrefix(text, "/model pin "):
+		arg := strings.TrimSpace(strings.TrimPrefix(text, "/model pin"))
+		provider, modelName := m.header.Provider(), m.header.ModelName()
+		if arg != "" {
+			var ok bool
+			provider, modelName, ok = strings.Cut(arg, "/")
+			if !ok {
+				provider, modelName = "ollama", arg
+			}
+		}
+		if modelName == "" {
+			m.chat.AddSystemError(i18n.T("No model to pin. Usage: /model pin <provider>/<model>"))
+			return m, nil
+		}
+		m.setSessionModel(provider, modelName)
+		m.chat.AddSystemMessage(i18n.T("Pinned %s / %s to session %s", provider, modelName, shortID(m.sessionID)))
+		return m, nil
+
+	case strings.HasPrefix(text, "/model pull"):
+		return m.pullModelCmd(strings.TrimSpace(strings.TrimPrefix(text, "/model pull")))
+
+	case text == "/model unpin":
+		if p, _ := m.sessionModel(); p == "" {
+			m.chat.AddSystemMessage(i18n.T("No model pinned to this session."))
+			return m, nil
+		}
+		m.setSessionModel("", "")
+		m.chat.AddSystemMessage(i18n.T("Session %s now uses the default model (%s / %s)",
+			shortID(m.sessionID), m.header.Provider(), m.header.ModelName()))
+		return m, nil
+
 	case strings.HasPrefix(text, "/model "):
 		arg := strings.TrimSpace(strings.TrimPrefix(text, "/model"))
 		parts := strings.SplitN(arg, "/", 2)
 		if len(parts) == 2 {
-			m.chat.AddSystemMessage(fmt.Sprintf("Switching to %s / %s...", parts[0], parts[1]))
+			m.chat.AddSystemMessage(i18n.T("Switching to %s / %s...", parts[0], parts[1]))
 			return m, m.switchModel(parts[0], parts[1])
 		}
 		if isKnownProvider(arg) {
 			m.pendingProviderFilter = strings.ToLower(arg)
-			m.toasts.Add(fmt.Sprintf("Loading %s models...", arg), toast.ToastInfo)
+			m.toasts.Add(i18n.T("Loading %s models...", arg), toast.ToastInfo)
 			m.input.Blur()
 			return m, tea.Batch(m.fetchModels(), m.tickCmd())
 		}
 		// Default to ollama for bare model names (e.g. "/model qwen3:8b")
-		m.chat.AddSystemMessage(fmt.Sprintf("Switching to ollama / %s...", arg))
+		m.chat.AddSystemMessage(i18n.T("Switching to ollama / %s...", arg))
 		return m, m.switchModel("ollama", arg)
 
 	case text == "/theme":
+		return m.openThemePicker(), nil
+
+	case strings.HasPrefix(text, "/theme "):
+		return m.setTheme(strings.TrimSpace(strings.TrimPrefix(text, "/theme")))
+
+	case text == "/lang":
 		var sb strings.Builder
-		sb.WriteString("Available themes:\n")
-		for _, name := range style.ThemeNames {
+		sb.WriteString(i18n.T("Interface language: %s", i18n.Locale()) + "\n")
+		for _, name := range i18n.Available(LocalesDir) {
 			marker := "  "
-			if name == style.CurrentThemeName {
+			if name == i18n.Locale() {
 				marker = "* "
 			}
 			sb.WriteString(fmt.Sprintf("  %s%s\n", marker, name))
 		}
-		sb.WriteString("\nUsage: /theme <name>")
-		m.chat.AddSystemMessage(strings.TrimRight(sb.String(), "\n"))
+		if m.localeErr != nil {
+			sb.WriteString(fmt.Sprintf("\n  ! %v\n", m.localeErr))
+		}
+		sb.WriteString("\n" + i18n.T("Usage: /lang <locale|auto>"))
+		m.chat.AddSystemMessage(sb.String())
 		return m, nil
 
-	case strings.HasPrefix(text, "/theme "):
-		name := strings.TrimSpace(strings.TrimPrefix(text, "/theme"))
-		if !style.SetTheme(name) {
-			m.chat.AddSystemError(fmt.Sprintf(
-				"Unknown theme: %s (available: %s)", name, strings.Join(style.ThemeNames, ", "),
-			))
+	case strings.HasPrefix(text, "/lang "):
+		name := strings.TrimSpace(strings.TrimPrefix(text, "/lang"))
+		if err := i18n.Init(name, LocalesDir); err != nil && name != "auto" {
+			m.chat.AddSystemError(i18n.T("Unknown language: %s (available: %s)", name, strings.Join(i18n.Available(LocalesDir), ", ")))
 			return m, nil
 		}
-		m.config.Theme = name
+		m.localeErr = nil
+		m.config.Locale = name
 		if err := config.Save(profileDirPath(), m.config); err != nil {
-			m.chat.AddSystemWarning(fmt.Sprintf("Theme applied but could not persist: %v", err))
+			m.chat.AddSystemWarning(i18n.T("Language applied but could not persist: %v", err))
 		}
-		m.recomputeLayout()
-		m.toasts.Add(fmt.Sprintf("Theme set to: %s", name), toast.ToastInfo)
-		return m, m.tickCmd()
+		m.chat.AddSystemMessage(i18n.T("Language set to %s. Some screens update after restart.", i18n.Locale()))
+		return m, nil
+
+	case text == "/profile":
+		var sb strings.Builder
+		sb.WriteString(i18n.T("Profiles:") + "\n")
+		for _, name := range config.ListProfiles() {
+			marker := "  "
+			if name == currentProfile() {
+				marker = "* "
+			}
+			sb.WriteString(fmt.Sprintf("  %s%s\n", marker, name))
+		}
+		sb.WriteString("\n" + i18n.T("Usage: /profile switch <name>  (create with: osa profile create <name>)"))
+		m.chat.AddSystemMessage(sb.String())
+		return m, nil
+
+	case strings.HasPrefix(text, "/profile switch"):
+		return m.switchProfile(strings.TrimSpace(strings.TrimPrefix(text, "/profile switch")))
+
+	case text == "/open" || strings.HasPrefix(text, "/open "):
+		arg := strings.TrimSpace(strings.TrimPrefix(text, "/open"))
+		items := m.sidebar.OpenableItems()
+		if arg == "" {
+			if len(items) == 0 {
+				m.chat.AddSystemMessage(i18n.T("No files to open yet. Usage: /open <n|path>"))
+				return m, nil
+			}
+			var sb strings.Builder
+			sb.WriteString(i18n.T("Files:") + "\n")
+			for i, p := range items {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, p))
+			}
+			sb.WriteString("\n" + i18n.T("Usage: /open <n|path>"))
+			m.chat.AddSystemMessage(sb.String())
+			return m, nil
+		}
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 1 || n > len(items) {
+				m.chat.AddSystemError(i18n.T("No file %d (sidebar lists %d)", n, len(items)))
+				return m, nil
+			}
+			arg = items[n-1]
+		}
+		return m.openInEditor(arg)
+
+	case text == "/pin" || strings.HasPrefix(text, "/pin "):
+		return m.handlePinCommand(strings.TrimSpace(strings.TrimPrefix(text, "/pin")))
+
+	case text == "/prompts" || strings.HasPrefix(text, "/prompts "):
+		return m.handlePromptsCommand(strings.TrimSpace(strings.TrimPrefix(text, "/prompts")))
+
+	case text == "/schedule":
+		m.scheduler.Reset()
+		return m, m.fetchSchedulerJobs()
+
+	case strings.HasPrefix(text, "/schedule "):
+		return m.createSchedule(strings.TrimSpace(strings.TrimPrefix(text, "/schedule")))
+
+	case text == "/reveal":
+		return m.toggleReveal()
+
+	case text == "/paste-image":
+		return m, pasteClipboardImage()
+
+	case text == "/notifications":
+		m.chat.AddSystemMessage(m.notificationsText())
+		return m, nil
+
+	case text == "/swarm new" || strings.HasPrefix(text, "/swarm new "):
+		return m.openSwarmWizard(strings.TrimSpace(strings.TrimPrefix(text, "/swarm new")))
+
+	case text == "/timeline":
+		return m.openTimeline()
+
+	case text == "/agents" || strings.HasPrefix(text, "/agents "):
+		m.toasts.Add(i18n.T("Loading agents..."), toast.ToastInfo)
+		return m, tea.Batch(m.fetchAgents(true, strings.TrimSpace(strings.TrimPrefix(text, "/agents"))), m.tickCmd())
+
+	case text == "/hooks":
+		return m.openHooks()
+
+	case text == "/mcp":
+		return m.openMCP()
+
+	case text == "/channels":
+		return m.openChannels()
+
+	case text == "/budget":
+		return m.openBudget()
+
+	case text == "/audit" || strings.HasPrefix(text, "/audit "):
+		return m.handleAuditCommand(strings.TrimSpace(strings.TrimPrefix(text, "/audit")))
+
+	case text == "/tools" || strings.HasPrefix(text, "/tools "):
+		m.toasts.Add(i18n.T("Loading tools..."), toast.ToastInfo)
+		return m, tea.Batch(m.fetchTools(true, strings.TrimSpace(strings.TrimPrefix(text, "/tools"))), m.tickCmd())
+
+	case text == "/memory" || strings.HasPrefix(text, "/memory "):
+		return m.openMemory(strings.TrimSpace(strings.TrimPrefix(text, "/memory")))
+
+	case text == "/system" || strings.HasPrefix(text, "/system "):
+		return m.handleSystemCommand(strings.TrimSpace(strings.TrimPrefix(text, "/system")))
+
+	case text == "/env" || strings.HasPrefix(text, "/env "):
+		return m.handleEnvCommand(strings.TrimSpace(strings.TrimPrefix(text, "/env")))
+
+	case text == "/prefs" || strings.HasPrefix(text, "/prefs "):
+		return m.handlePrefsCommand(strings.TrimSpace(strings.TrimPrefix(text, "/prefs")))
+
+	case text == "/stats" || strings.HasPrefix(text, "/stats "):
+		return m.handleStatsCommand(strings.TrimSpace(strings.TrimPrefix(text, "/stats")))
+
+	case text == "/timestamps" || strings.HasPrefix(text, "/timestamps "):
+		return m.handleTimestampsCommand(strings.TrimSpace(strings.TrimPrefix(text, "/timestamps")))
+
+	case text == "/speak" || strings.HasPrefix(text, "/speak "):
+		return m.handleSpeakCommand(strings.TrimSpace(strings.TrimPrefix(text, "/speak")))
+
+	case text == "/notices" || strings.HasPrefix(text, "/notices "):
+		return m.handleNoticesCommand(strings.TrimSpace(strings.TrimPrefix(text, "/notices")))
+
+	case text == "/export" || strings.HasPrefix(text, "/export "):
+		return m.handleExportCommand(strings.TrimSpace(strings.TrimPrefix(text, "/export")))
+
+	case text == "/compact" || strings.HasPrefix(text, "/compact "):
+		return m.handleCompactCommand(strings.TrimSpace(strings.TrimPrefix(text, "/compact")))
+
+	case text == "/feedback" || strings.HasPrefix(text, "/feedback "):
+		return m.handleFeedbackCommand(strings.TrimSpace(strings.TrimPrefix(text, "/feedback")))
+
+	case text == "/apply" || strings.HasPrefix(text, "/apply "):
+		return m.handleApplyCommand(strings.TrimSpace(strings.TrimPrefix(text, "/apply")))
+
+	case text == "/doctor":
+		m.toasts.Add(i18n.T("Running checks…"), toast.ToastInfo)
+		return m, tea.Batch(m.runDoctor(), m.tickCmd())
+
+	case text == "/retry" || strings.HasPrefix(text, "/retry "):
+		return m.handleRetryCommand(strings.TrimSpace(strings.TrimPrefix(text, "/retry")))
+
+	case text == "/queue" || strings.HasPrefix(text, "/queue "):
+		return m.handleQueueCommand(strings.TrimSpace(strings.TrimPrefix(text, "/queue")))
 
 	case text == "/bg":
 		if len(m.bgTasks) == 0 {
-			m.chat.AddSystemMessage("No background tasks running.")
+			m.chat.AddSystemMessage(i18n.T("No background tasks running."))
 			return m, nil
 		}
 		var sb strings.Builder
-		sb.WriteString("Background tasks:\n")
+		sb.WriteString(i18n.T("Background tasks:") + "\n")
 		for i, t := range m.bgTasks {
 			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, t))
 		}
@@ -1185,14 +2623,17 @@ func (m Model) submitInput(text string) (Model, tea.Cmd) {
 		parts := strings.SplitN(text[1:], " ", 2)
 		cmd := parts[0]
 		if cmd == "" {
-			m.chat.AddSystemMessage("Type /help for available commands, or Ctrl+K for command palette.")
+			m.chat.AddSystemMessage(i18n.T("Type /help for available commands, or Ctrl+K for command palette."))
 			return m, nil
 		}
 		arg := ""
 		if len(parts) > 1 {
 			arg = parts[1]
 		}
-		m.toasts.Add(fmt.Sprintf("Running /%s...", cmd), toast.ToastInfo)
+		if form, ok := m.commandForm(cmd, arg); ok {
+			return m.openCommandForm(cmd, arg, form)
+		}
+		m.toasts.Add(i18n.T("Running /%s...", cmd), toast.ToastInfo)
 		return m, tea.Batch(m.executeCommand(cmd, arg), m.tickCmd())
 	}
 
@@ -1200,50 +2641,102 @@ func (m Model) submitInput(text string) (Model, tea.Cmd) {
 	return m.submitPrompt(text)
 }
 
-// submitPrompt sends raw text directly to the agent pipeline.
+// submitPrompt sends raw text directly to the agent pipeline, along with the
+// files attached in the input.
 func (m Model) submitPrompt(text string) (Model, tea.Cmd) {
+	m.altOf, m.altModel = "", ""
+	if paths := m.input.Attachments(); len(paths) > 0 {
+		text += attachmentNote(paths)
+		m.input.ClearAttachments()
+	}
+	return m.startTurn(text, "")
+}
+
+// startTurn sends text to the agent and switches to the processing view.
+// requestID is the ID of a failed request being retried, or "" for a new one.
+func (m Model) startTurn(text, requestID string) (Model, tea.Cmd) {
 	m.activity.Reset()
 	m.activity.Start()
 	m.agents.Reset()
 	m.tasks.Reset()
 	m.streamBuf.Reset()
 	m.thinkingBuf.Reset()
-	m.responseReceived = false
-	m.cancelled = false
-	m.state = StateProcessing
+	m.beginSpeechTurn()
+	m.chat.ClearThinking()
+	m.requestID = requestID
+	if m.requestID == "" {
+		m.requestID = newRequestID()
+	}
+	m.setBase(StateProcessing)
 	m.processingStart = time.Now()
 	m.status.SetActive(true)
 	m.chat.SetProcessingView(m.activity.View())
-	m.input.Blur()
-	return m, tea.Batch(m.orchestrate(text), m.tickCmd())
+	m.record(transcript.Record{Kind: transcript.KindPrompt, Text: text})
+	cmd := m.orchestrate(text)
+	m.replyTo, m.replyHeader = nil, ""
+	return m, tea.Batch(cmd, m.tickCmd())
 }
 
 // -- Health -------------------------------------------------------------------
 
 func (m Model) handleHealth(h msg.HealthResult) (Model, tea.Cmd) {
 	if h.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Backend unreachable: %v -- retrying in 5s", h.Err))
-		m.state = StateConnecting
+		m.chat.AddSystemError(i18n.T("Backend unreachable: %v -- retrying in 5s", h.Err))
+		m.setBase(StateConnecting)
 		return m, tea.Tick(5*time.Second, func(time.Time) tea.Msg { return retryHealth{} })
 	}
 
+	m.backendDown, m.recovering = false, false
 	m.header.SetHealth(h)
 	m.status.SetProviderInfo(h.Provider, h.Model)
 	m.sidebar.SetModelInfo(h.Provider, h.Model)
-	m.state = StateBanner
+	m.setBase(StateBanner)
 
 	b := make([]byte, 4)
 	if _, err := io.ReadFull(rand.Reader, b); err != nil {
 		b = []byte{0, 0, 0, 0}
 	}
 	m.sessionID = generateSessionID(b)
+	m.syncSessionModel()
 
 	m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
 	m.recomputeLayout()
+	bannerFor := bannerDuration
+	if m.setBannerNotes() {
+		bannerFor = bannerNewsDuration
+	}
 
 	var cmds []tea.Cmd
-	cmds = append(cmds, m.fetchCommands(), m.fetchToolCount())
-	cmds = append(cmds, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return bannerTimeout{} }))
+	if m.transcript != nil {
+		m.toasts.Add(i18n.T("Logging transcript to %s", m.transcript.Path(time.Now())), toast.ToastInfo)
+		cmds = append(cmds, m.tickCmd())
+	}
+	if m.guardErr != nil {
+		m.chat.AddSystemWarning(i18n.T("Ignoring destructive_patterns: %v", m.guardErr))
+	}
+	if m.speakerErr != nil {
+		m.chat.AddSystemWarning(i18n.T("Not reading replies aloud: %v", m.speakerErr))
+		m.speakerErr = nil
+	}
+	cmds = append(cmds, m.fetchCommands(), m.fetchTools(false, ""), m.fetchMCPServers(false), m.fetchBudget(false), m.fetchAgents(false, ""))
+	if cmd := m.autoArchive(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	switch {
+	case m.startSession != "":
+		cmds = append(cmds, m.switchSession(m.startSession))
+		m.startSession, m.startSwitching = "", true
+	case m.startResume:
+		cmds = append(cmds, m.resumeLatest())
+		m.startResume, m.startSwitching = false, true
+	case m.startPrompt == "":
+		m.recoverDraft()
+	}
+	if !m.gitPolling {
+		m.gitPolling = true
+		cmds = append(cmds, m.fetchGitStatus(true))
+	}
+	cmds = append(cmds, tea.Tick(bannerFor, func(time.Time) tea.Msg { return bannerTimeout{} }))
 	if m.program != nil {
 		if cmd := m.startSSE(); cmd != nil {
 			cmds = append(cmds, cmd)
@@ -1252,11 +2745,32 @@ func (m Model) handleHealth(h msg.HealthResult) (Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// setBannerNotes picks the banner tip for this run and, after an upgrade,
+// the "what's new" line, reporting whether there is one. The tip rotation
+// and the version are remembered for the next run.
+func (m *Model) setBannerNotes() bool {
+	version := m.header.Version()
+	news := header.WhatsNew(m.config.LastVersion, version)
+	if m.config.HideBannerTips {
+		m.header.SetBannerNotes("", "")
+	} else {
+		m.header.SetBannerNotes(header.Tip(m.config.TipIndex), news)
+		m.config.TipIndex = (m.config.TipIndex + 1) % len(header.Tips)
+	}
+	m.config.LastVersion = version
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		log.Printf("save banner state: %v", err)
+	}
+	return news != "" && !m.config.HideBannerTips
+}
+
 // -- Orchestration ------------------------------------------------------------
 
 func (m Model) handleOrchestrate(r msg.OrchestrateResult) (Model, tea.Cmd) {
-	// If user cancelled, silently drop the late-arriving response.
-	if m.cancelled {
+	// If SSE already delivered this answer, fold the REST metadata into it.
+	// This also covers answers a queued prompt has since moved on from.
+	if e, ok := m.responses.lookup(r.RequestID); ok {
+		m.reconcile(r.RequestID, e, restDelivery(r))
 		if r.Err == nil && r.SessionID != "" && m.sessionID != r.SessionID {
 			m.sessionID = r.SessionID
 		}
@@ -1268,9 +2782,13 @@ func (m Model) handleOrchestrate(r msg.OrchestrateResult) (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// If SSE already rendered this response, skip duplicate.
-	if m.responseReceived {
-		if r.SessionID != "" && m.sessionID != r.SessionID {
+	// Drop a late result for a cancelled request, or for an earlier request
+	// after a newer prompt was sent.
+	if !m.isCurrent(r.RequestID) {
+		if r.Cancelled {
+			m = m.confirmCancelled(r.RequestID, r.IterationCount)
+		}
+		if r.Err == nil && r.SessionID != "" && m.sessionID != r.SessionID {
 			m.sessionID = r.SessionID
 		}
 		if m.sse == nil && m.program != nil && m.sessionID != "" {
@@ -1287,7 +2805,10 @@ func (m Model) handleOrchestrate(r msg.OrchestrateResult) (Model, tea.Cmd) {
 		m.chat.ClearProcessingView()
 		m.status.SetActive(false)
 		m.plan.SetPlan(r.Output)
-		m.state = StatePlanReview
+		m.lastPlan = r.Output
+		m.setBase(StateIdle)
+		m.pushModal(StatePlanReview)
+		m.responses.add(r.RequestID, restDelivery(r), "")
 		if r.SessionID != "" && m.sessionID != r.SessionID {
 			m.sessionID = r.SessionID
 		}
@@ -1299,35 +2820,42 @@ func (m Model) handleOrchestrate(r msg.OrchestrateResult) (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	wasBackground := (m.state == StateIdle)
+	wasBackground := (m.base == StateIdle)
 	m.activity.Stop()
 	m.chat.ClearProcessingView()
 	m.status.SetActive(false)
-	m.state = StateIdle
+	m.setBase(StateIdle)
 	var cmds []tea.Cmd
-	cmds = append(cmds, m.input.Focus())
+	cmds = append(cmds, m.focusInput())
 
 	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Error: %v", r.Err))
+		m.record(transcript.Record{Kind: transcript.KindError, Text: r.Err.Error()})
+		m = m.markTurnFailed(r.Err)
+		m.unread++
+		if len(m.queue) > 0 {
+			m.queuePaused = true
+			m.chat.AddSystemWarning(i18n.T("%d queued prompt(s) paused. Use /queue send to continue or /queue clear to drop them.", len(m.queue)))
+		}
 		return m, tea.Batch(cmds...)
 	}
 
 	if wasBackground {
-		m.chat.AddSystemMessage("Background task completed")
+		m.chat.AddSystemMessage(i18n.T("Background task completed"))
 		if len(m.bgTasks) > 0 {
 			m.bgTasks = m.bgTasks[1:]
 		}
 		m.status.SetBackgroundCount(len(m.bgTasks))
 	}
 
-	m.responseReceived = true
 	output := truncateResponse(r.Output)
 	if output == "" {
 		output = "(no response)"
 	}
 
 	sig := msgSignalToChat(r.Signal)
-	m.chat.AddAgentMessage(output, sig, r.ExecutionMs, m.header.ModelName())
+	m.addAgentMessage(output, sig, r.ExecutionMs)
+	m.speakReply(r.Output)
+	m.responses.add(r.RequestID, restDelivery(r), m.chat.LastAgentID())
 	if sig != nil {
 		m.status.SetSignal(&status.Signal{
 			Mode:  sig.Mode,
@@ -1344,54 +2872,863 @@ func (m Model) handleOrchestrate(r msg.OrchestrateResult) (Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 	}
-	return m, tea.Batch(cmds...)
+	cmds = append(cmds, m.settleLimits())
+	m, cmd := m.sendQueued()
+	return m, tea.Batch(append(cmds, cmd)...)
 }
 
 func (m Model) handleClientAgentResponse(r client.AgentResponseEvent) (Model, tea.Cmd) {
-	// Drop if cancelled or REST already rendered.
-	if m.cancelled || m.responseReceived {
+	// Events from backends that don't echo the request ID belong to the
+	// current request.
+	id := r.RequestID
+	if id == "" {
+		id = m.requestID
+	}
+	d := delivery{
+		via:          viaSSE,
+		responseType: r.ResponseType,
+		text:         r.Response,
+		signal:       clientSignalToChat(r.Signal),
+		durationMs:   time.Since(m.processingStart).Milliseconds(),
+	}
+	// If REST already delivered this answer, fold the SSE metadata into it.
+	if e, ok := m.responses.lookup(id); ok {
+		m.reconcile(id, e, d)
+		return m, nil
+	}
+	// Drop if cancelled or left over from an earlier request.
+	if !m.isCurrent(id) {
 		return m, nil
 	}
 
 	if r.ResponseType == "plan" {
+		m.activity.Stop()
+		m.chat.ClearProcessingView()
+		m.status.SetActive(false)
 		m.plan.SetPlan(r.Response)
-		m.state = StatePlanReview
+		m.lastPlan = r.Response
+		m.setBase(StateIdle)
+		m.pushModal(StatePlanReview)
+		m.responses.add(id, d, "")
 		return m, nil
 	}
 
-	m.responseReceived = true
-	wasBackground := (m.state == StateIdle)
+	wasBackground := (m.base == StateIdle)
 	m.activity.Stop()
 	m.chat.ClearProcessingView()
 	m.status.SetActive(false)
-	m.state = StateIdle
-	focusCmd := m.input.Focus()
+	m.setBase(StateIdle)
+	focusCmd := m.focusInput()
 
 	if wasBackground {
-		m.chat.AddSystemMessage("Background task completed")
+		m.chat.AddSystemMessage(i18n.T("Background task completed"))
 		if len(m.bgTasks) > 0 {
 			m.bgTasks = m.bgTasks[1:]
 		}
 		m.status.SetBackgroundCount(len(m.bgTasks))
 	}
 
-	sig := clientSignalToChat(r.Signal)
-	m.chat.AddAgentMessage(
-		truncateResponse(r.Response), sig,
-		time.Since(m.processingStart).Milliseconds(),
-		m.header.ModelName(),
-	)
+	sig := d.signal
+	m.addAgentMessage(truncateResponse(r.Response), sig, d.durationMs)
+	m.speakReply(r.Response)
+	m.responses.add(id, d, m.chat.LastAgentID())
 	if sig != nil {
 		m.status.SetSignal(&status.Signal{Mode: sig.Mode, Genre: sig.Genre, Type: sig.Type})
 	}
-	return m, focusCmd
+	limitsCmd := m.settleLimits()
+	m, cmd := m.sendQueued()
+	return m, tea.Batch(focusCmd, limitsCmd, cmd)
+}
+
+// restDelivery describes the REST reply r for the response registry.
+func restDelivery(r msg.OrchestrateResult) delivery {
+	return delivery{
+		via:          viaREST,
+		responseType: r.ResponseType,
+		text:         r.Output,
+		signal:       msgSignalToChat(r.Signal),
+		durationMs:   r.ExecutionMs,
+		err:          r.Err,
+	}
+}
+
+// addAgentMessage appends the turn's answer, linked to the original answer
+// when the turn was a retry.
+func (m *Model) addAgentMessage(text string, sig *chat.Signal, durationMs int64) {
+	modelName := m.header.ModelName()
+	if m.altModel != "" {
+		_, modelName, _ = strings.Cut(m.altModel, "/")
+	}
+	if m.altOf != "" {
+		m.chat.AddAlternativeMessage(m.altOf, text, sig, durationMs, modelName)
+	} else {
+		m.chat.AddAgentMessage(text, sig, durationMs, modelName)
+	}
+	m.record(transcript.Record{Kind: transcript.KindReply, Text: text, Model: modelName, DurationMs: durationMs})
+	m.altOf, m.altModel = "", ""
+	m.unread++
+}
+
+// record appends r to the transcript, if one is being logged. A write error
+// stops logging rather than repeating the warning for every record.
+func (m *Model) record(r transcript.Record) {
+	if m.transcript == nil {
+		return
+	}
+	if r.Session == "" {
+		r.Session = m.sessionID
+	}
+	if r.RequestID == "" {
+		r.RequestID = m.requestID
+	}
+	if err := m.transcript.Write(r); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Transcript logging stopped: %v", err))
+		m.transcript = nil
+	}
+}
+
+// -- Retry --------------------------------------------------------------------
+
+// handleRetryCommand implements /retry [pick|<provider/model>]: resubmit the
+// prompt behind the selected message, or the latest one, and show the result
+// as an alternative answer.
+func (m Model) handleRetryCommand(arg string) (Model, tea.Cmd) {
+	t, ok := m.chat.RetryTarget()
+	if !ok {
+		m.chat.AddSystemWarning(i18n.T("Nothing to retry yet."))
+		return m, nil
+	}
+	switch {
+	case arg == "":
+		return m.retry(t, "", "")
+	case arg == "pick":
+		m.retryPending = &t
+		m.pendingModelsDialog = true
+		m.toasts.Add(i18n.T("Loading models..."), toast.ToastInfo)
+		m.input.Blur()
+		return m, tea.Batch(m.fetchModels(), m.tickCmd())
+	}
+	provider, modelName, found := strings.Cut(arg, "/")
+	if !found {
+		provider, modelName = m.header.Provider(), arg
+	}
+	return m.retry(t, provider, modelName)
+}
+
+// retry resubmits t.Prompt, with provider/modelName for this turn only when
+// modelName is set.
+func (m Model) retry(t chat.RetryTarget, provider, modelName string) (Model, tea.Cmd) {
+	m.chat.ClearSelection()
+	excerpt := ansi.Truncate(strings.Join(strings.Fields(t.Prompt), " "), 60, "…")
+	m.chat.ClearFailed(t.PromptID)
+	if modelName != "" {
+		m.chat.AddSystemMessage(i18n.T("Retrying with %s/%s: %s", provider, modelName, excerpt))
+	} else {
+		m.chat.AddSystemMessage(i18n.T("Retrying: %s", excerpt))
+	}
+	m.altOf, m.altModel = t.AnswerID, ""
+	if modelName != "" {
+		m.altModel = provider + "/" + modelName
+	}
+	// Resending a failed request under its own ID lets the backend answer
+	// it once if the first attempt got through. Another model is a new one.
+	rid := t.RequestID
+	if modelName != "" {
+		rid = ""
+	}
+	return m.startTurn(t.Prompt, rid)
+}
+
+// -- Error actions ------------------------------------------------------------
+
+// authErrorHints mark errors a provider key change may fix.
+var authErrorHints = []string{
+	"api 401", "api 403", "unauthorized", "forbidden", "api key", "api_key", "authenticat", "credential",
+}
+
+// markTurnFailed shows a failed request on its prompt, which r retries, and
+// points at the provider keys when err looks like an authentication failure.
+// Without a prompt to mark it falls back to an error offering remediations.
+func (m Model) markTurnFailed(err error) Model {
+	reason := err.Error()
+	lower := strings.ToLower(reason)
+	for _, h := range authErrorHints {
+		if strings.Contains(lower, h) {
+			reason += " (check the provider key with /keys)"
+			break
+		}
+	}
+	m.chat.DismissErrorActions()
+	if !m.chat.MarkPromptFailed(reason, m.requestID) {
+		return m.addErrorWithActions(i18n.T("Error: %v", err), err, true)
+	}
+	return m
+}
+
+// addErrorWithActions shows text as an error offering the remediations that
+// fit err. canRetry offers resubmitting the last prompt, for failed turns.
+func (m Model) addErrorWithActions(text string, err error, canRetry bool) Model {
+	var actions []chat.ErrorAction
+	if _, ok := m.chat.RetryTarget(); ok && canRetry {
+		actions = append(actions,
+			chat.ErrorAction{ID: "retry", Label: i18n.T("Retry")},
+			chat.ErrorAction{ID: "retry-model", Label: i18n.T("Retry with another model")},
+		)
+	} else {
+		actions = append(actions, chat.ErrorAction{ID: "models", Label: i18n.T("Switch model")})
+	}
+	lower := strings.ToLower(err.Error())
+	for _, h := range authErrorHints {
+		if strings.Contains(lower, h) {
+			actions = append(actions, chat.ErrorAction{ID: "keys", Label: i18n.T("Provider keys")})
+			break
+		}
+	}
+	actions = append(actions, chat.ErrorAction{ID: "copy", Label: i18n.T("Copy details")})
+	m.errorDetails = text
+	m.chat.AddSystemErrorWithActions(text, actions)
+	return m
+}
+
+// handleErrorActionKey handles keys for the actions of the latest error while
+// the input is empty, or while a large paste or an oversized prompt awaits
+// confirmation. ok is false for keys it leaves to the caller; such a key
+// drops the question.
+func (m Model) handleErrorActionKey(k tea.KeyPressMsg) (Model, tea.Cmd, bool) {
+	if !m.chat.HasErrorActions() || (m.input.Value() != "" && m.pasteConfirm == "" && m.sizeConfirm == "") || m.chat.HasSelection() {
+		return m, nil, false
+	}
+	switch k.String() {
+	case "tab":
+		m.chat.CycleErrorAction(1)
+		return m, nil, true
+	case "shift+tab":
+		m.chat.CycleErrorAction(-1)
+		return m, nil, true
+	case "esc":
+		m.chat.DismissErrorActions()
+		m.pasteConfirm, m.sizeConfirm = "", ""
+		m.quickFixes, m.sessionCleanup = nil, nil
+		if m.guardPending {
+			mm, cmd := m.runErrorAction("guard-stop")
+			return mm, cmd, true
+		}
+		return m, nil, true
+	case "enter":
+		a, ok := m.chat.SelectedErrorAction()
+		if !ok {
+			return m, nil, false
+		}
+		m.chat.DismissErrorActions()
+		mm, cmd := m.runErrorAction(a.ID)
+		return mm, cmd, true
+	}
+	if m.pasteConfirm != "" || m.sizeConfirm != "" {
+		m.chat.DismissErrorActions()
+		m.pasteConfirm, m.sizeConfirm = "", ""
+	}
+	return m, nil, false
+}
+
+// runErrorAction runs the remediation with the given ErrorAction ID.
+func (m Model) runErrorAction(id string) (Model, tea.Cmd) {
+	switch id {
+	case "retry":
+		return m.handleRetryCommand("")
+	case "retry-model":
+		return m.handleRetryCommand("pick")
+	case "models":
+		m.pendingModelsDialog = true
+		m.toasts.Add(i18n.T("Loading models..."), toast.ToastInfo)
+		m.input.Blur()
+		return m, tea.Batch(m.fetchModels(), m.tickCmd())
+	case "keys":
+		m.toasts.Add(i18n.T("Loading provider keys..."), toast.ToastInfo)
+		m.input.Blur()
+		return m, tea.Batch(m.listProviderKeys(), m.tickCmd())
+	case "guard-stop":
+		return m.cancelCurrent()
+	case "guard-continue":
+		m.chat.AddSystemMessage(i18n.T("Output resumed."))
+		return m.releaseGuard()
+	case "paste-edit":
+		m.pasteConfirm = ""
+		return m, nil
+	case "paste-send":
+		return m.sendLargePaste()
+	case "size-edit":
+		m.sizeConfirm = ""
+		return m, nil
+	case "size-truncate":
+		return m.truncatePrompt()
+	case "size-compact":
+		return m.compactForPrompt()
+	case "size-models":
+		m.sizeConfirm = ""
+		return m.runErrorAction("models")
+	case "apply-cancel":
+		m.quickFixes = nil
+		return m, nil
+	case "apply-write":
+		return m.applyQuickFixes()
+	case "cleanup-cancel":
+		m.sessionCleanup = nil
+		return m, nil
+	case "cleanup-run":
+		return m.runSessionCleanup()
+	case "copy":
+		if err := clipboard.Copy(redact.Display(m.errorDetails)); err != nil {
+			m.toasts.Add(i18n.T("Copy failed: %v", err), toast.ToastError)
+		} else {
+			m.toasts.Add(i18n.T("Error details copied"), toast.ToastInfo)
+		}
+		return m, m.tickCmd()
+	}
+	return m, nil
+}
+
+// -- Drafts -----------------------------------------------------------------------
+
+// draftPause is how long typing must pause before the draft is saved and its
+// tokens are counted.
+const draftPause = 400 * time.Millisecond
+
+// draftRecoverWindow is how recent the draft of another session must be to
+// be brought into a fresh session at startup.
+const draftRecoverWindow = 24 * time.Hour
+
+// watchDraft saves the draft and counts its tokens once typing pauses, after
+// a key that may have changed it. An emptied draft is forgotten at once.
+// cmd is the key's own command.
+func (m Model) watchDraft(cmd tea.Cmd) (Model, tea.Cmd) {
+	text := m.input.Content()
+	if text == m.draftText {
+		return m, cmd
+	}
+	m.draftText = text
+	m.draftSeq++
+	if strings.TrimSpace(text) == "" {
+		m.keepDraft()
+		return m, cmd
+	}
+	if m.noTokenCount {
+		m.input.SetDraftTokens(estimateTokens(text), false)
+	}
+	seq := m.draftSeq
+	return m, tea.Batch(cmd, tea.Tick(draftPause, func(time.Time) tea.Msg { return draftCountTick{seq} }))
+}
+
+// keepDraft saves the input as the current session's draft, or forgets the
+// draft when the input is empty.
+func (m Model) keepDraft() {
+	text, pastes := m.input.Draft()
+	if strings.TrimSpace(text) == "" {
+		text = ""
+	}
+	if err := drafts.Save(profileDirPath(), m.sessionID, drafts.Draft{Text: text, Pastes: pastes}); err != nil {
+		log.Printf("save draft: %v", err)
+	}
+}
+
+// restoreDraft replaces the input with the current session's draft, after
+// keepDraft saved the draft of the session being left.
+func (m *Model) restoreDraft() tea.Cmd {
+	d, _ := drafts.Get(profileDirPath(), m.sessionID)
+	m.input.RestoreDraft(d.Text, d.Pastes)
+	m.draftText = m.input.Content()
+	if d.Empty() {
+		return nil
+	}
+	m.toasts.Add(i18n.T("Draft restored"), toast.ToastInfo)
+	return m.tickCmd()
+}
+
+// recoverDraft brings the latest recent draft of another session into the
+// fresh startup session, so a prompt left unsent when the TUI quit is not
+// lost.
+func (m *Model) recoverDraft() {
+	id, d, ok := drafts.Latest(profileDirPath(), draftRecoverWindow)
+	if !ok || id == m.sessionID {
+		return
+	}
+	m.input.RestoreDraft(d.Text, d.Pastes)
+	m.draftText = m.input.Content()
+	m.keepDraft()
+	if err := drafts.Save(profileDirPath(), id, drafts.Draft{}); err != nil {
+		log.Printf("save draft: %v", err)
+	}
+	m.chat.AddSystemMessage(i18n.T("Restored the unsent draft of session %s from %s.", shortID(id), d.Saved.Local().Format("Jan 2 15:04")))
+}
+
+// countDraft asks the backend's tokenizer for the tokens of text, and for
+// the context the session's conversation uses.
+func (m Model) countDraft(text string) tea.Cmd {
+	c, sessionID := m.client, m.sessionID
+	return func() tea.Msg {
+		res, err := c.CountTokens(text, sessionID)
+		if err != nil {
+			return draftCounted{text: text, err: err}
+		}
+		return draftCounted{text: text, tokens: res.Tokens, exact: res.Exact, window: res.ContextWindow, used: res.ContextUsed}
+	}
+}
+
+// estimateTokens approximates the tokens of text at four bytes each, for
+// backends that cannot count them.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// -- Large pastes ---------------------------------------------------------------
+
+// Pasted blocks beyond either size are confirmed before they are sent, as
+// they take a large share of the context window.
+const (
+	largePasteLines = 500
+	largePasteBytes = 32 << 10
+)
+
+// confirmLargePaste asks before text carrying large pasted blocks is sent
+// the given way ("send", "queue" or "now"), reporting whether it asked.
+// Slash commands are never held.
+func (m Model) confirmLargePaste(text, how string) (Model, bool) {
+	lines, size := m.input.PasteSize()
+	if strings.HasPrefix(text, "/") || (lines <= largePasteLines && size <= largePasteBytes) {
+		return m, false
+	}
+	m.pasteConfirm = how
+	m.chat.AddSystemConfirm(
+		i18n.T("Send %d pasted lines (%s, about %d tokens)?", lines, common.HumanSize(int64(size)), size/4),
+		[]chat.ErrorAction{
+			{ID: "paste-edit", Label: i18n.T("Keep editing")},
+			{ID: "paste-send", Label: i18n.T("Send")},
+		})
+	return m, true
+}
+
+// sendLargePaste sends the input after its large paste was confirmed, the way
+// confirmLargePaste was asked to.
+func (m Model) sendLargePaste() (Model, tea.Cmd) {
+	how := m.pasteConfirm
+	m.pasteConfirm = ""
+	text := strings.TrimSpace(m.input.Content())
+	if text == "" {
+		return m, nil
+	}
+	return m.sendAs(how, text)
+}
+
+// sendAs sends text from the input the given way: "send", "queue" or "now".
+func (m Model) sendAs(how, text string) (Model, tea.Cmd) {
+	m.input.Submit(text)
+	switch how {
+	case "queue":
+		m = m.enqueue(text, false)
+		m.toasts.Add(i18n.T("Prompt queued"), toast.ToastInfo)
+		return m, m.tickCmd()
+	case "now":
+		m = m.enqueue(text, true)
+		return m.cancelCurrent()
+	}
+	return m.submitInput(text)
+}
+
+// -- Destructive tool calls ---------------------------------------------------
+
+// loadGuard returns the destructive-call guard configured by cfg, nil when it
+// is off. An invalid extra pattern is reported and the built-in rules used.
+func loadGuard(cfg config.Config) (*guard.Guard, error) {
+	if cfg.DestructiveGuard == "off" {
+		return nil, nil
+	}
+	g, err := guard.New(cfg.DestructivePatterns)
+	if err != nil {
+		g, _ = guard.New(nil)
+	}
+	return g, err
+}
+
+// guardToolCall flags a tool call matching the destructive rule, pausing
+// output until it is acknowledged unless destructive_guard is "warn".
+func (m Model) guardToolCall(v client.ToolCallStartEvent, rule string) Model {
+	call := strings.TrimSpace(v.Name + " " + v.Args)
+	if m.config.DestructiveGuard == "warn" {
+		m.chat.AddSystemWarning(i18n.T("Destructive tool call (%s): %s", rule, call))
+		return m
+	}
+	m.guardPending = true
+	m.chat.AddSystemConfirm(
+		i18n.T("Destructive tool call (%s): %s", rule, call)+"\n"+
+			i18n.T("Output is paused. The backend may already be running it; stopping cancels the request."),
+		[]chat.ErrorAction{
+			{ID: "guard-stop", Label: i18n.T("Stop")},
+			{ID: "guard-continue", Label: i18n.T("Continue")},
+		})
+	return m
+}
+
+// holdForGuard keeps agent output back while a destructive call awaits
+// acknowledgment. A block by a backend hook means the call was gated there,
+// so output resumes on its own. ok is false for messages handled as usual.
+func (m Model) holdForGuard(raw tea.Msg) (Model, tea.Cmd, bool) {
+	switch v := raw.(type) {
+	case client.HookBlockedEvent:
+		m.chat.DismissErrorActions()
+		m.chat.AddSystemError(i18n.T("Blocked by %s: %s", v.HookName, v.Reason))
+		m.noteHookBlock(v)
+		mm, cmd := m.releaseGuard()
+		return mm, cmd, true
+	case client.ToolResultEvent:
+		m.guardHeld = append(m.guardHeld, raw)
+		if strings.HasPrefix(v.Result, "Blocked") {
+			m.chat.DismissErrorActions()
+			mm, cmd := m.releaseGuard()
+			return mm, cmd, true
+		}
+		return m, nil, true
+	case client.StreamingTokenEvent, client.ThinkingDeltaEvent, client.ThinkingFinishedEvent, client.AgentResponseEvent,
+		client.LLMRequestEvent, client.LLMResponseEvent, client.ToolCallStartEvent,
+		client.ToolOutputEvent, client.ToolCallEndEvent, client.SignalClassifiedEvent, msg.OrchestrateResult:
+		m.guardHeld = append(m.guardHeld, raw)
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// releaseGuard resumes output, replaying what was held back in order. A
+// replayed destructive call pauses it again, holding the rest.
+func (m Model) releaseGuard() (Model, tea.Cmd) {
+	held := m.guardHeld
+	m.guardPending, m.guardHeld = false, nil
+	var cmds []tea.Cmd
+	for _, h := range held {
+		mm, cmd := m.update(h)
+		m = mm.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// dropGuard ends a pause, discarding the held output.
+func (m Model) dropGuard() Model {
+	if m.guardPending {
+		m.chat.DismissErrorActions()
+	}
+	m.guardPending, m.guardHeld = false, nil
+	return m
+}
+
+// -- Timeline -------------------------------------------------------------------
+
+// openTimeline shows the session timeline overlay.
+func (m Model) openTimeline() (Model, tea.Cmd) {
+	msgs := m.chat.Timeline()
+	if len(msgs) == 0 {
+		m.chat.AddSystemMessage(i18n.T("No messages to show on the timeline yet."))
+		return m, nil
+	}
+	entries := make([]dialog.TimelineEntry, len(msgs))
+	for i, e := range msgs {
+		role := "agent"
+		if e.Role == chat.RoleUser {
+			role = "user"
+		}
+		entries[i] = dialog.TimelineEntry{Index: e.Index, Role: role, Time: e.Time, Excerpt: e.Text}
+	}
+	m.timeline.SetSize(m.width, m.height)
+	m.timeline.SetEntries(entries)
+	m.pushModal(StateTimeline)
+	return m, nil
+}
+
+// -- Image paste ----------------------------------------------------------------
+
+// imageExts are the attachment extensions the backend's file_read tool
+// returns as image content for vision-capable models.
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".bmp": true, ".tiff": true,
+}
+
+// handleImagePaste attaches pasted image data, sent as raw bytes or a data
+// URI by terminals that allow it, or a pasted path to an image file, as
+// dropping a file on most terminals does. ok is false for other text.
+func (m Model) handleImagePaste(content string) (Model, tea.Cmd, bool) {
+	if data := pastedImageData(content); data != nil {
+		return m, saveImageCmd(data), true
+	}
+	path := strings.TrimSpace(content)
+	if len(path) > 1 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	}
+	path = strings.ReplaceAll(path, "\\ ", " ")
+	path = strings.TrimPrefix(path, "file://")
+	if !imageExts[strings.ToLower(filepath.Ext(path))] || strings.ContainsRune(path, '\n') {
+		return m, nil, false
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return m, nil, false
+	}
+	mm, cmd := m.attachImage(path)
+	return mm, cmd, true
+}
+
+// pastedImageData returns the image in a paste of raw image bytes or of a
+// base64 data URI, or nil when the paste is not an image.
+func pastedImageData(content string) []byte {
+	if rest, ok := strings.CutPrefix(content, "data:image/"); ok {
+		_, b64, found := strings.Cut(rest, ";base64,")
+		if !found {
+			return nil
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil {
+			return nil
+		}
+		content = string(data)
+	}
+	if !strings.HasPrefix(http.DetectContentType([]byte(content)), "image/") {
+		return nil
+	}
+	return []byte(content)
+}
+
+// pasteClipboardImage reads the system clipboard image and saves it.
+func pasteClipboardImage() tea.Cmd {
+	return func() tea.Msg {
+		data, err := clipboard.ReadImage()
+		if err != nil {
+			return imagePasted{err: err}
+		}
+		return saveImageCmd(data)()
+	}
+}
+
+// saveImageCmd writes pasted image data to the profile's pastes directory,
+// where the backend can read it.
+func saveImageCmd(data []byte) tea.Cmd {
+	return func() tea.Msg {
+		dir := filepath.Join(profileDirPath(), "pastes")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return imagePasted{err: err}
+		}
+		ext := ".png"
+		switch http.DetectContentType(data) {
+		case "image/jpeg":
+			ext = ".jpg"
+		case "image/gif":
+			ext = ".gif"
+		case "image/webp":
+			ext = ".webp"
+		case "image/bmp":
+			ext = ".bmp"
+		}
+		base := "img-" + time.Now().Format("0102-150405")
+		path := filepath.Join(dir, base+ext)
+		for n := 2; ; n++ {
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+			if errors.Is(err, os.ErrExist) {
+				path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, n, ext))
+				continue
+			}
+			if err != nil {
+				return imagePasted{err: err}
+			}
+			_, err = f.Write(data)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return imagePasted{err: err}
+			}
+			return imagePasted{path: path}
+		}
+	}
+}
+
+// attachImage adds an image file to the input's attachments.
+func (m Model) attachImage(path string) (Model, tea.Cmd) {
+	if err := m.input.AttachFile(path); err != nil {
+		m.toasts.Add(i18n.T("Attach failed: %v", err), toast.ToastError)
+	} else {
+		m.toasts.Add(i18n.T("Image attached · backspace on an empty input removes it"), toast.ToastInfo)
+	}
+	return m, m.tickCmd()
+}
+
+// attachmentNote lists attached files below a prompt. The backend has no
+// upload endpoint, so images reach vision-capable models through file_read.
+func attachmentNote(paths []string) string {
+	var sb strings.Builder
+	sb.WriteString("\n\nAttached files:")
+	for _, p := range paths {
+		sb.WriteString("\n- " + p)
+		if imageExts[strings.ToLower(filepath.Ext(p))] {
+			sb.WriteString(" (image, view it with file_read)")
+		}
+	}
+	return sb.String()
+}
+
+// -- Secrets -------------------------------------------------------------------
+
+// toggleReveal shows or masks secrets in the chat and in copied messages.
+func (m Model) toggleReveal() (Model, tea.Cmd) {
+	redact.SetRevealed(!redact.Revealed())
+	m.chat.InvalidateCache()
+	m.pinned.Refresh()
+	if redact.Revealed() {
+		m.toasts.Add(i18n.T("Secrets revealed · alt+v masks them again"), toast.ToastWarning)
+	} else {
+		m.toasts.Add(i18n.T("Secrets masked"), toast.ToastInfo)
+	}
+	return m, m.tickCmd()
+}
+
+// -- Timestamps ---------------------------------------------------------------
+
+// parseTimestamps maps the timestamps setting to a chat mode; anything
+// unknown hides them.
+func parseTimestamps(s string) chat.Timestamps {
+	switch s {
+	case "relative":
+		return chat.TimestampsRelative
+	case "absolute":
+		return chat.TimestampsAbsolute
+	}
+	return chat.TimestampsOff
+}
+
+// handleTimestampsCommand sets how message times show, or without an
+// argument cycles off → relative → absolute, and saves the choice.
+func (m Model) handleTimestampsCommand(arg string) (Model, tea.Cmd) {
+	if arg == "" {
+		switch m.config.Timestamps {
+		case "relative":
+			arg = "absolute"
+		case "absolute":
+			arg = "off"
+		default:
+			arg = "relative"
+		}
+	}
+	if arg != "relative" && arg != "absolute" && arg != "off" {
+		m.chat.AddSystemError(i18n.T("Usage: /timestamps [relative|absolute|off]"))
+		return m, nil
+	}
+	m.config.Timestamps = arg
+	if arg == "off" {
+		m.config.Timestamps = ""
+	}
+	m.chat.SetTimestamps(parseTimestamps(arg))
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Timestamps set but could not persist: %v", err))
+	}
+	m.toasts.Add(i18n.T("Timestamps: %s", arg), toast.ToastInfo)
+	return m, m.tickCmd()
+}
+
+// watchClock reports each clockInterval, aligned to the system clock.
+func watchClock() tea.Cmd {
+	return tea.Tick(clockInterval, func(time.Time) tea.Msg { return clockTick{} })
+}
+
+// -- Prompt queue -------------------------------------------------------------
+
+// queuePreviewWidth caps the next-prompt preview in the status bar.
+const queuePreviewWidth = 40
+
+// enqueue adds a prompt to the queue, at the front when first is set.
+func (m Model) enqueue(text string, first bool) Model {
+	if first {
+		m.queue = append([]string{text}, m.queue...)
+	} else {
+		m.queue = append(m.queue, text)
+	}
+	m.syncQueue()
+	return m
+}
+
+// sendQueued submits queued prompts once the agent is idle. Local commands
+// finish immediately, so it keeps going until a prompt starts processing or
+// a dialog opens.
+func (m Model) sendQueued() (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for m.base == StateIdle && !m.queuePaused && len(m.queue) > 0 {
+		text := m.queue[0]
+		m.queue = m.queue[1:]
+		var cmd tea.Cmd
+		m, cmd = m.submitInput(text)
+		cmds = append(cmds, cmd)
+	}
+	m.syncQueue()
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) syncQueue() {
+	next := ""
+	if len(m.queue) > 0 {
+		next = ansi.Truncate(strings.Join(strings.Fields(m.queue[0]), " "), queuePreviewWidth, "…")
+	}
+	m.status.SetQueue(len(m.queue), next)
+	m.recomputeLayout()
+}
+
+// handleQueueCommand implements /queue [clear|drop <n>|send].
+func (m Model) handleQueueCommand(arg string) (Model, tea.Cmd) {
+	sub, rest, _ := strings.Cut(arg, " ")
+	switch sub {
+	case "":
+		if len(m.queue) == 0 {
+			m.chat.AddSystemMessage(i18n.T("No queued prompts. Press Enter while the agent is working to queue one."))
+			return m, nil
+		}
+		var b strings.Builder
+		b.WriteString(i18n.T("Queued prompts (%d):", len(m.queue)) + "\n")
+		for i, q := range m.queue {
+			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, ansi.Truncate(strings.Join(strings.Fields(q), " "), 72, "…")))
+		}
+		if m.queuePaused {
+			b.WriteString("\n" + i18n.T("Paused after an error. /queue send continues."))
+		}
+		m.chat.AddSystemMessage(strings.TrimRight(b.String(), "\n"))
+		return m, nil
+
+	case "clear":
+		n := len(m.queue)
+		m.queue = nil
+		m.queuePaused = false
+		m.syncQueue()
+		m.chat.AddSystemMessage(i18n.T("Dropped %d queued prompt(s).", n))
+		return m, nil
+
+	case "drop":
+		n, err := strconv.Atoi(strings.TrimSpace(rest))
+		if err != nil || n < 1 || n > len(m.queue) {
+			m.chat.AddSystemError(i18n.T("Usage: /queue drop <1-%d>", max(len(m.queue), 1)))
+			return m, nil
+		}
+		m.queue = append(m.queue[:n-1:n-1], m.queue[n:]...)
+		m.syncQueue()
+		m.chat.AddSystemMessage(i18n.T("Dropped queued prompt %d.", n))
+		return m, nil
+
+	case "send":
+		m.queuePaused = false
+		if m.base != StateIdle {
+			m.chat.AddSystemMessage(i18n.T("The next queued prompt is sent when the current request finishes."))
+			return m, nil
+		}
+		return m.sendQueued()
+	}
+	m.chat.AddSystemError(i18n.T("Usage: /queue [clear|drop <n>|send]"))
+	return m, nil
 }
 
 // -- Command handler ----------------------------------------------------------
 
 func (m Model) handleCommand(r msg.CommandResult) (Model, tea.Cmd) {
 	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Command error: %v", r.Err))
+		m = m.addErrorWithActions(i18n.T("Command error: %v", r.Err), r.Err, false)
 		return m, nil
 	}
 	switch r.Kind {
@@ -1413,16 +3750,18 @@ func (m Model) handleCommandAction(action, output string) (Model, tea.Cmd) {
 		m.closeSSE()
 		b := make([]byte, 4)
 		io.ReadFull(rand.Reader, b) //nolint:errcheck
+		m.keepDraft()
 		m.sessionID = generateSessionID(b)
+		m.syncSessionModel()
 		m.chat = chat.New(m.layout.ChatWidth, m.layout.ChatHeight)
 		m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
 		if output != "" {
 			m.chat.AddSystemMessage(output)
 		} else {
-			m.chat.AddSystemMessage("New session started.")
+			m.chat.AddSystemMessage(i18n.T("New session started."))
 		}
 		var cmds []tea.Cmd
-		cmds = append(cmds, m.input.Focus())
+		cmds = append(cmds, m.focusInput(), m.restoreDraft())
 		if m.program != nil {
 			if cmd := m.startSSE(); cmd != nil {
 				cmds = append(cmds, cmd)
@@ -1446,14 +3785,16 @@ func (m Model) handleCommandAction(action, output string) (Model, tea.Cmd) {
 		sid := extractResumeSessionID(action)
 		if sid != "" {
 			m.closeSSE()
+			m.keepDraft()
 			m.sessionID = sid
+			m.syncSessionModel()
 			if output != "" {
 				m.chat.AddSystemMessage(output)
 			} else {
-				m.chat.AddSystemMessage(fmt.Sprintf("Resumed session: %s", sid))
+				m.chat.AddSystemMessage(i18n.T("Resumed session: %s", sid))
 			}
 			var cmds []tea.Cmd
-			cmds = append(cmds, m.input.Focus())
+			cmds = append(cmds, m.focusInput(), m.restoreDraft())
 			if m.program != nil {
 				if cmd := m.startSSE(); cmd != nil {
 					cmds = append(cmds, cmd)
@@ -1491,32 +3832,33 @@ func (m Model) handlePlanDecision(d dialog.PlanDecision) (Model, tea.Cmd) {
 	m.plan.Clear()
 	switch d.Decision {
 	case "approve":
-		m.chat.AddSystemMessage("Plan approved. Executing...")
+		m.chat.AddSystemMessage(i18n.T("Plan approved. Executing..."))
 		m.activity.Reset()
 		m.activity.Start()
 		m.streamBuf.Reset()
-		m.responseReceived = false
-		m.cancelled = false
-		m.state = StateProcessing
+		m.beginSpeechTurn()
+		m.requestID = newRequestID()
+		m.closeModal(StatePlanReview)
+		m.setBase(StateProcessing)
 		m.processingStart = time.Now()
 		m.status.SetActive(true)
 		return m, tea.Batch(m.orchestrateWithOpts("Approved. Execute the plan.", true), m.tickCmd())
 
 	case "reject":
-		m.chat.AddSystemMessage("Plan rejected.")
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.chat.AddSystemMessage(i18n.T("Plan rejected."))
+		m.closeModal(StatePlanReview)
+		return m, m.focusInput()
 
 	case "edit":
-		m.chat.AddSystemMessage("Edit the plan below:")
-		m.state = StateIdle
-		focusCmd := m.input.Focus()
+		m.chat.AddSystemMessage(i18n.T("Edit the plan below:"))
+		m.closeModal(StatePlanReview)
+		focusCmd := m.focusInput()
 		m.input.SetValue("Regarding the plan: ")
 		return m, focusCmd
 	}
 
-	m.state = StateIdle
-	return m, m.input.Focus()
+	m.closeModal(StatePlanReview)
+	return m, m.focusInput()
 }
 
 // -- Auth helpers -------------------------------------------------------------
@@ -1568,9 +3910,9 @@ func (m Model) doRefreshToken(refreshToken string) tea.Cmd {
 
 func (m Model) handleRefreshTokenResult(r refreshTokenResult) (Model, tea.Cmd) {
 	if r.err != nil {
-		m.chat.AddSystemWarning("Session expired. Use /login to re-authenticate.")
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.chat.AddSystemWarning(i18n.T("Session expired. Use /login to re-authenticate."))
+		m.setBase(StateIdle)
+		return m, m.focusInput()
 	}
 	m.client.SetToken(r.token)
 	m.refreshToken = r.refreshToken
@@ -1592,14 +3934,106 @@ func (m Model) doLogout() tea.Cmd {
 	}
 }
 
-// -- SSE management ----------------------------------------------------------
+// -- Profiles ----------------------------------------------------------------
 
-func (m *Model) startSSE() tea.Cmd {
-	if m.program == nil || m.sessionID == "" {
-		return nil
+// currentProfile returns the active profile name for display.
+func currentProfile() string {
+	if ProfileName == "" {
+		return config.DefaultProfile
 	}
-	m.sse = client.NewSSE(m.client.BaseURL, m.client.Token, m.sessionID)
-	return m.sse.ListenCmd(m.program)
+	return ProfileName
+}
+
+// switchProfile moves the running TUI to another profile: the SSE stream is
+// closed, settings and credentials are reloaded from the profile directory,
+// and the health check runs again to open a fresh session.
+func (m Model) switchProfile(name string) (Model, tea.Cmd) {
+	switch {
+	case name == "":
+		m.chat.AddSystemError(i18n.T("Usage: /profile switch <name>"))
+		return m, nil
+	case name == currentProfile():
+		m.chat.AddSystemMessage(i18n.T("Already using profile %s.", name))
+		return m, nil
+	case !config.ProfileExists(name):
+		m.chat.AddSystemError(i18n.T("Unknown profile: %s (available: %s). Create it with: osa profile create %s",
+			name, strings.Join(config.ListProfiles(), ", "), name))
+		return m, nil
+	case m.base == StateProcessing:
+		m.chat.AddSystemWarning(i18n.T("Wait for the current request to finish before switching profiles."))
+		return m, nil
+	}
+
+	m.closeSSE()
+	if name == config.DefaultProfile {
+		name = ""
+	}
+	ProfileName, ProfileDir = name, config.ProfilePath(name)
+
+	m.config = config.Load(profileDirPath())
+	m.input.SetSnippets(m.config.Snippets)
+	m.status.SetSegments(m.config.StatusSegments)
+	m.applyContextThresholds()
+	m.chat.SetNoticesHidden(m.config.HideNotices)
+	m.guard, m.guardErr = loadGuard(m.config)
+	if m.guardErr != nil {
+		m.chat.AddSystemWarning(i18n.T("Ignoring destructive_patterns: %v", m.guardErr))
+	}
+	m.closeSpeaker()
+	if m.speaker, m.speakerErr = loadSpeaker(m.config); m.speakerErr != nil {
+		m.chat.AddSystemWarning(i18n.T("Not reading replies aloud: %v", m.speakerErr))
+		m.speakerErr = nil
+	}
+	token, refresh := config.ReadCredentials(profileDirPath())
+	if env := os.Getenv("OSA_TOKEN"); env != "" {
+		token = env
+	}
+	m.client.SetToken(token)
+	m.refreshToken = refresh
+
+	m.localeErr = i18n.Init(m.config.Locale, LocalesDir)
+	if m.config.Locale == "" || m.config.Locale == "auto" {
+		m.localeErr = nil
+	}
+	style.SetAccessibility(m.config.ScreenReader || ScreenReaderFlag, m.config.ReducedMotion || ReducedMotionFlag, MinimalFlag)
+
+	var cmds []tea.Cmd
+	if m.autoTheme() {
+		cmds = append(cmds, tea.RequestBackgroundColor)
+	} else {
+		style.SetTheme(m.config.Theme)
+	}
+	if mode, forced := m.colorMode(); forced {
+		style.SetColorMode(mode)
+		cmds = append(cmds, func() tea.Msg { return tea.ColorProfileMsg{Profile: mode.Profile()} })
+	}
+
+	m.layoutMode = LayoutCompact
+	if m.config.SidebarOpen {
+		m.layoutMode = LayoutSidebar
+	}
+	m.chat = chat.New(m.layout.ChatWidth, m.layout.ChatHeight)
+	m.chat.AddSystemMessage(i18n.T("Switched to profile %s. Reconnecting...", currentProfile()))
+	m.recomputeLayout()
+	m.sessionID = ""
+	m.closeModals()
+	m.setBase(StateConnecting)
+
+	cmds = append(cmds, m.checkHealth())
+	return m, tea.Batch(cmds...)
+}
+
+// -- SSE management ----------------------------------------------------------
+
+func (m *Model) startSSE() tea.Cmd {
+	if m.program == nil || m.sessionID == "" {
+		return nil
+	}
+	m.sse = client.NewSSE(m.client.BaseURL, m.client.Token, m.sessionID)
+	if MinimalFlag {
+		m.sse.SetBatchInterval(minimalBatchInterval)
+	}
+	return m.sse.ListenCmd(m.program)
 }
 
 func (m *Model) closeSSE() {
@@ -1628,6 +4062,82 @@ func (m Model) checkHealth() tea.Cmd {
 	}
 }
 
+// -- Provider key commands ---------------------------------------------------
+
+func (m Model) listProviderKeys() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		entries, err := c.ListProviderKeys()
+		if err != nil {
+			return msg.ProviderKeysResult{Err: err}
+		}
+		keys := make([]msg.ProviderKey, len(entries))
+		for i, e := range entries {
+			keys[i] = msg.ProviderKey{
+				Provider:   e.Provider,
+				Name:       e.Name,
+				EnvVar:     e.EnvVar,
+				Configured: e.Configured,
+				Masked:     e.Masked,
+				Source:     e.Source,
+			}
+		}
+		return msg.ProviderKeysResult{Keys: keys}
+	}
+}
+
+func (m Model) handleProviderKeys(r msg.ProviderKeysResult) (Model, tea.Cmd) {
+	if r.Err != nil {
+		if m.hasModal(StateKeys) {
+			m.keyManager.SetResult("list", false, r.Err)
+			return m, nil
+		}
+		if errors.Is(r.Err, client.ErrNotSupported) {
+			m.chat.AddSystemWarning(i18n.T("Key management is not supported by this backend — set keys via env vars or ~/.osa/config.json"))
+		} else {
+			m.chat.AddSystemError(i18n.T("Failed to load provider keys: %v", r.Err))
+		}
+		return m, m.focusInput()
+	}
+	if !m.hasModal(StateKeys) {
+		m.keyManager.Reset()
+	}
+	m.keyManager.SetKeys(r.Keys)
+	m.keyManager.SetSize(m.width, m.height)
+	m.pushModal(StateKeys)
+	return m, nil
+}
+
+// applyKeyAction validates a new key against the provider (when the backend
+// supports it) before storing it, so a bad key is rejected up front.
+func (m Model) applyKeyAction(a dialog.KeyAction) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		switch a.Action {
+		case "remove":
+			err := c.DeleteProviderKey(a.Provider)
+			return msg.ProviderKeyUpdated{Provider: a.Provider, Removed: true, Err: err}
+		case "set":
+			res, err := c.VerifyProvider(client.OnboardingVerifyRequest{Provider: a.Provider, APIKey: a.APIKey})
+			switch {
+			case errors.Is(err, client.ErrNotSupported):
+				// Older backend: store without a live check.
+			case err != nil:
+				return msg.ProviderKeyUpdated{Provider: a.Provider, Err: fmt.Errorf("validate: %w", err)}
+			case res.Status != "ok":
+				reason := res.Message
+				if reason == "" {
+					reason = "provider rejected the key"
+				}
+				return msg.ProviderKeyUpdated{Provider: a.Provider, Err: errors.New(reason)}
+			}
+			err = c.SetProviderKey(a.Provider, a.APIKey)
+			return msg.ProviderKeyUpdated{Provider: a.Provider, Err: err}
+		}
+		return nil
+	}
+}
+
 // -- Onboarding commands -----------------------------------------------------
 
 func (m Model) checkOnboarding() tea.Cmd {
@@ -1681,6 +4191,39 @@ func (m Model) checkOnboarding() tea.Cmd {
 	}
 }
 
+func (m Model) verifyProvider(req dialog.OnboardingVerifyRequest) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		if req.Provider == "ollama" {
+			// Check the local daemon first so a missing model can be pulled
+			// here instead of failing later with a 404. If the daemon is not
+			// reachable locally, leave the verdict to the backend.
+			if ok, err := client.NewOllama().HasModel(req.Model); err == nil && !ok {
+				return msg.OnboardingVerifyResult{Seq: req.Seq, MissingModel: req.Model}
+			}
+		}
+		result, err := c.VerifyProvider(client.OnboardingVerifyRequest{
+			Provider: req.Provider,
+			Model:    req.Model,
+			APIKey:   req.APIKey,
+			EnvVar:   req.EnvVar,
+		})
+		if errors.Is(err, client.ErrNotSupported) {
+			// Fail-open: older backends cannot test credentials.
+			return msg.OnboardingVerifyResult{Seq: req.Seq, Skipped: true, Message: i18n.T("Backend does not support connection tests")}
+		}
+		if err != nil {
+			return msg.OnboardingVerifyResult{Seq: req.Seq, Message: err.Error(), Err: err}
+		}
+		return msg.OnboardingVerifyResult{
+			Seq:     req.Seq,
+			OK:      result.Status == "ok",
+			Message: result.Message,
+			Hint:    result.Hint,
+		}
+	}
+}
+
 func (m Model) completeOnboarding(done dialog.OnboardingDone) tea.Cmd {
 	return func() tea.Msg {
 		result, err := m.client.CompleteOnboarding(client.OnboardingSetupRequest{
@@ -1726,14 +4269,37 @@ func (m Model) orchestrate(inputText string) tea.Cmd {
 func (m Model) orchestrateWithOpts(inputText string, skipPlan bool) tea.Cmd {
 	c := m.client
 	sid := m.sessionID
+	rid := m.requestID
+	provider, modelName := m.sessionModel()
+	if m.altModel != "" {
+		provider, modelName, _ = strings.Cut(m.altModel, "/")
+	}
+	override := m.sessionOverride()
+	agent := m.mentionedAgent(inputText)
+	allowed := m.allowedTools()
+	metadata := m.replyPrefsMetadata()
+	var replyTo *client.ReplyRef
+	if m.replyTo != nil && strings.Contains(inputText, m.replyHeader) {
+		replyTo = m.replyTo
+	}
 	return func() tea.Msg {
 		resp, err := c.Orchestrate(client.OrchestrateRequest{
 			Input:     inputText,
 			SessionID: sid,
 			SkipPlan:  skipPlan,
+			Provider:  provider,
+			Model:     modelName,
+			ReplyTo:   replyTo,
+			RequestID: rid,
+
+			SystemPrompt: override.System,
+			Env:          override.Env,
+			Agent:        agent,
+			AllowedTools: allowed,
+			Metadata:     metadata,
 		})
 		if err != nil {
-			return msg.OrchestrateResult{Err: err}
+			return msg.OrchestrateResult{RequestID: rid, Err: err}
 		}
 		r := msg.OrchestrateResult{
 			SessionID:      resp.SessionID,
@@ -1742,6 +4308,8 @@ func (m Model) orchestrateWithOpts(inputText string, skipPlan bool) tea.Cmd {
 			ToolsUsed:      resp.ToolsUsed,
 			IterationCount: resp.IterationCount,
 			ExecutionMs:    resp.ExecutionMs,
+			RequestID:      rid,
+			Cancelled:      resp.Cancelled,
 		}
 		if resp.Signal != nil {
 			r.Signal = &msg.Signal{
@@ -1757,7 +4325,59 @@ func (m Model) orchestrateWithOpts(inputText string, skipPlan bool) tea.Cmd {
 	}
 }
 
+// cancelOrchestrate asks the backend to stop request rid.
+func (m Model) cancelOrchestrate(rid string) tea.Cmd {
+	c := m.client
+	sid := m.sessionID
+	return func() tea.Msg {
+		return cancelAcked{requestID: rid, err: c.CancelOrchestrate(sid, rid)}
+	}
+}
+
+// handleCancelAcked reports the backend's answer to a cancel. On success
+// the final confirmation arrives as a RequestCancelledEvent or as the
+// cancelled orchestrate response, whichever comes first.
+func (m Model) handleCancelAcked(a cancelAcked) Model {
+	if a.requestID != m.cancelPending {
+		return m // already confirmed
+	}
+	switch {
+	case a.err == nil:
+		m.toasts.Add(i18n.T("Backend is stopping the request"), toast.ToastInfo)
+		return m
+	case errors.Is(a.err, client.ErrNotRunning):
+		m.chat.AddSystemMessage(i18n.T("Request cancelled. It had already finished; the response was discarded."))
+	case errors.Is(a.err, client.ErrNotSupported):
+		m.chat.AddSystemWarning(i18n.T("This backend cannot cancel requests. It keeps running in the background and its response will be discarded."))
+	default:
+		m.chat.AddSystemError(i18n.T("Cancel failed: %v. The response will be discarded.", a.err))
+	}
+	m.cancelPending = ""
+	return m
+}
+
+// confirmCancelled records that the backend stopped request rid after the
+// given number of tool rounds.
+func (m Model) confirmCancelled(rid string, rounds int) Model {
+	if m.cancelPending == "" || (rid != "" && rid != m.cancelPending) {
+		return m
+	}
+	m.cancelPending = ""
+	if rounds > 0 {
+		m.chat.AddSystemMessage(i18n.T("Request cancelled. The agent stopped after %d tool round(s).", rounds))
+	} else {
+		m.chat.AddSystemMessage(i18n.T("Request cancelled. The agent stopped."))
+	}
+	return m
+}
+
 func (m Model) executeCommand(cmd, arg string) tea.Cmd {
+	return m.executeCommandFields(cmd, arg, nil)
+}
+
+// executeCommandFields runs backend command /cmd arg with the values of its
+// form.
+func (m Model) executeCommandFields(cmd, arg string, fields map[string]string) tea.Cmd {
 	c := m.client
 	sid := m.sessionID
 	return func() tea.Msg {
@@ -1765,6 +4385,7 @@ func (m Model) executeCommand(cmd, arg string) tea.Cmd {
 			Command:   cmd,
 			Arg:       arg,
 			SessionID: sid,
+			Fields:    fields,
 		})
 		if err != nil {
 			return msg.CommandResult{Err: err}
@@ -1784,264 +4405,1706 @@ func (m Model) fetchCommands() tea.Cmd {
 	}
 }
 
-func (m Model) fetchToolCount() tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		tools, err := c.ListTools()
-		if err != nil {
-			return toolCountLoaded(0)
-		}
-		return toolCountLoaded(len(tools))
+// runDoctor runs the doctor checks against the current backend and profile.
+func (m Model) runDoctor() tea.Cmd {
+	opts := doctor.Options{
+		Client:     m.client,
+		ProfileDir: profileDirPath(),
+		ThemesDir:  ThemesDir,
+		LocalesDir: LocalesDir,
+		ThemeErrs:  m.themeErrs,
+		Colors:     style.CurrentColorMode.Profile(),
+	}
+	return func() tea.Msg { return doctorDone(doctor.Run(opts)) }
+}
+
+// settleLimits runs after a turn completes: a turn that was not rate limited
+// clears the rate-limit segment, and a showing budget is refreshed so a
+// daily or monthly reset hides it. A configured cost segment and the
+// sidebar's budget bars are refreshed after every turn.
+func (m *Model) settleLimits() tea.Cmd {
+	if m.rateLimitedReq != m.requestID {
+		m.status.ClearRateLimit()
+		m.recomputeLayout()
+	}
+	if m.status.HasBudget() || m.status.ShowsSegment("cost") || m.sidebar.HasBudget() {
+		return m.fetchBudget(false)
+	}
+	return nil
+}
+
+func (m Model) tickCmd() tea.Cmd {
+	every := time.Second
+	if MinimalFlag {
+		every = minimalTickInterval
+	}
+	return tea.Tick(every, func(time.Time) tea.Msg { return msg.TickMsg{} })
+}
+
+// -- Model selection ---------------------------------------------------------
+
+func (m Model) handleModelList(r msg.ModelListResult) (Model, tea.Cmd) {
+	openDialog := m.pendingModelsDialog
+	m.pendingModelsDialog = false
+	if r.Err != nil || len(r.Models) == 0 {
+		m.retryPending = nil
+	}
+	if r.Err != nil {
+		m = m.addErrorWithActions(i18n.T("Failed to list models: %v", r.Err), r.Err, false)
+		return m, m.focusInput()
+	}
+	if len(r.Models) == 0 {
+		m.chat.AddSystemWarning(i18n.T(
+			"No models available. Current: %s. Is Ollama running?", m.header.ModelName(),
+		))
+		return m, m.focusInput()
+	}
+
+	if openDialog {
+		return m.openModelsDialog(r)
+	}
+
+	filter := m.pendingProviderFilter
+	m.pendingProviderFilter = ""
+
+	var items []dialog.PickerItem
+	for _, entry := range r.Models {
+		if filter != "" && strings.ToLower(entry.Provider) != filter {
+			continue
+		}
+		items = append(items, dialog.PickerItem{
+			Name:     entry.Name,
+			Provider: entry.Provider,
+			Size:     entry.Size,
+			Active:   entry.Active,
+		})
+	}
+
+	if len(items) == 0 {
+		m.chat.AddSystemError(i18n.T(
+			"No models available for provider: %s. Is the API key configured?", filter,
+		))
+		return m, m.focusInput()
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Provider != items[j].Provider {
+			return items[i].Provider < items[j].Provider
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	m.picker.SetWidth(m.width - 4)
+	m.picker.SetItems(items)
+	m.pushModal(StateModelPicker)
+	return m, nil
+}
+
+// openModelsDialog shows the enhanced models dialog, grouped by provider with
+// the user's favorites and recent models on top.
+func (m Model) openModelsDialog(r msg.ModelListResult) (Model, tea.Cmd) {
+	byProvider := make(map[string][]dialog.ModelEntry)
+	var providers []string
+	for _, e := range r.Models {
+		if _, ok := byProvider[e.Provider]; !ok {
+			providers = append(providers, e.Provider)
+		}
+		var size string
+		if e.Size > 0 {
+			size = fmt.Sprintf("%.1f GB", float64(e.Size)/1e9)
+		}
+		byProvider[e.Provider] = append(byProvider[e.Provider], dialog.ModelEntry{
+			Name:          e.Name,
+			Size:          size,
+			Active:        e.Active,
+			Reasoning:     e.Reasoning,
+			ContextWindow: e.ContextWindow,
+			Vision:        e.Vision,
+			Tools:         e.Tools,
+			InputPrice:    e.InputPrice,
+			OutputPrice:   e.OutputPrice,
+		})
+	}
+	sort.Strings(providers)
+	groups := make([]dialog.ModelGroup, 0, len(providers))
+	for _, p := range providers {
+		entries := byProvider[p]
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		groups = append(groups, dialog.ModelGroup{Provider: p, Models: entries})
+	}
+
+	m.models.SetSize(m.width, m.height)
+	m.models.SetPreferences(m.config.FavoriteModels, m.config.RecentModels)
+	m.models.SetModels(groups)
+	m.pushModal(StateModels)
+	return m, nil
+}
+
+// handleModelFavorite persists a star toggled in the models dialog.
+func (m Model) handleModelFavorite(f dialog.ModelFavoriteToggle) (Model, tea.Cmd) {
+	key := f.Provider + "/" + f.Model
+	favs := make([]string, 0, len(m.config.FavoriteModels)+1)
+	for _, k := range m.config.FavoriteModels {
+		if k != key {
+			favs = append(favs, k)
+		}
+	}
+	if f.Favorite {
+		favs = append(favs, key)
+	}
+	m.config.FavoriteModels = favs
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.toasts.Add(i18n.T("Could not save favorites: %v", err), toast.ToastWarning)
+		return m, m.tickCmd()
+	}
+	return m, nil
+}
+
+// sessionModel returns the model pinned to the current session, if any.
+func (m Model) sessionModel() (provider, modelName string) {
+	pin := m.config.SessionModels[m.sessionID]
+	provider, modelName, _ = strings.Cut(pin, "/")
+	return provider, modelName
+}
+
+// syncSessionModel refreshes the header and sidebar after the session, its
+// pin or its overrides changed.
+func (m *Model) syncSessionModel() {
+	m.header.SetSessionModel(m.sessionModel())
+	o := m.sessionOverride()
+	m.sidebar.SetOverrides(o.System, o.Env)
+}
+
+// setSessionModel pins provider/model to the current session and persists
+// it. Empty values remove the pin.
+func (m *Model) setSessionModel(provider, modelName string) {
+	if m.config.SessionModels == nil {
+		m.config.SessionModels = make(map[string]string)
+	}
+	if modelName == "" {
+		delete(m.config.SessionModels, m.sessionID)
+	} else {
+		m.config.SessionModels[m.sessionID] = provider + "/" + modelName
+	}
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Session model applied but could not persist: %v", err))
+	}
+	m.syncSessionModel()
+}
+
+// cycleFavoriteModel pins the next favorite model to the current session.
+func (m Model) cycleFavoriteModel() (Model, tea.Cmd) {
+	favs := m.config.FavoriteModels
+	if len(favs) == 0 {
+		m.toasts.Add(i18n.T("No favorite models — star some in /models with ctrl+s"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	provider, modelName := m.sessionModel()
+	if modelName == "" {
+		provider, modelName = m.header.Provider(), m.header.ModelName()
+	}
+	current := provider + "/" + modelName
+	next := favs[0]
+	for i, f := range favs {
+		if f == current {
+			next = favs[(i+1)%len(favs)]
+			break
+		}
+	}
+	provider, modelName, _ = strings.Cut(next, "/")
+	m.setSessionModel(provider, modelName)
+	m.toasts.Add(i18n.T("Session model: %s / %s", provider, modelName), toast.ToastInfo)
+	return m, m.tickCmd()
+}
+
+// recordRecentModel moves provider/model to the front of the recent list.
+func (m *Model) recordRecentModel(provider, modelName string) {
+	key := provider + "/" + modelName
+	recent := []string{key}
+	for _, k := range m.config.RecentModels {
+		if k != key && len(recent) < 5 {
+			recent = append(recent, k)
+		}
+	}
+	m.config.RecentModels = recent
+	_ = config.Save(profileDirPath(), m.config)
+}
+
+func (m Model) handleModelSwitch(r msg.ModelSwitchResult) (Model, tea.Cmd) {
+	if r.Err != nil {
+		m = m.addErrorWithActions(i18n.T("Switch failed: %v", r.Err), r.Err, false)
+		return m, nil
+	}
+	m.recordRecentModel(r.Provider, r.Model)
+	m.status.SetProviderInfo(r.Provider, r.Model)
+	m.header.SetModelOverride(r.Provider, r.Model)
+	m.sidebar.SetModelInfo(r.Provider, r.Model)
+	m.chat.AddSystemMessage(i18n.T("Switched to %s / %s", r.Provider, r.Model))
+	return m, m.checkHealth()
+}
+
+func (m Model) handlePickerChoice(c dialog.PickerChoice) (Model, tea.Cmd) {
+	m.picker.Clear()
+	m.closeModal(StateModelPicker)
+	m.chat.AddSystemMessage(i18n.T("Switching to %s / %s...", c.Provider, c.Name))
+	return m, tea.Batch(m.focusInput(), m.switchModel(c.Provider, c.Name))
+}
+
+func (m Model) fetchModels() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		resp, err := c.ListModels()
+		if err != nil {
+			return msg.ModelListResult{Err: err}
+		}
+		var models []msg.ModelEntry
+		for _, entry := range resp.Models {
+			models = append(models, msg.ModelEntry{
+				Name:          entry.Name,
+				Provider:      entry.Provider,
+				Size:          entry.Size,
+				Active:        entry.Active,
+				ContextWindow: entry.ContextWindow,
+				Vision:        entry.Vision,
+				Tools:         entry.Tools,
+				Reasoning:     entry.Reasoning,
+				InputPrice:    entry.InputPrice,
+				OutputPrice:   entry.OutputPrice,
+			})
+		}
+		return msg.ModelListResult{Models: models, Current: resp.Current, Provider: resp.Provider}
+	}
+}
+
+func (m Model) switchModel(provider, modelName string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		resp, err := c.SwitchModel(client.ModelSwitchRequest{Provider: provider, Model: modelName})
+		if err != nil {
+			return msg.ModelSwitchResult{Err: err}
+		}
+		return msg.ModelSwitchResult{Provider: resp.Provider, Model: resp.Model}
+	}
+}
+
+// -- Session management -------------------------------------------------------
+
+func (m Model) listSessions() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		sessions, err := c.ListSessions()
+		if err != nil {
+			return msg.SessionListResult{Err: err}
+		}
+		var result []msg.SessionInfo
+		for _, s := range sessions {
+			result = append(result, msg.SessionInfo{
+				ID:           s.ID,
+				CreatedAt:    s.CreatedAt,
+				Title:        s.Title,
+				MessageCount: s.MessageCount,
+			})
+		}
+		return msg.SessionListResult{Sessions: result}
+	}
+}
+
+func (m Model) createSession() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		resp, err := c.CreateSession()
+		if err != nil {
+			return msg.SessionSwitchResult{Err: err}
+		}
+		return msg.SessionSwitchResult{SessionID: resp.ID}
+	}
+}
+
+func (m Model) switchSession(id string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		info, err := c.GetSession(id)
+		if err != nil {
+			return msg.SessionSwitchResult{Err: err}
+		}
+		messages := info.Messages
+		if len(messages) == 0 {
+			fetched, err := c.GetSessionMessages(id)
+			if err == nil {
+				messages = fetched
+			}
+		}
+		var result []msg.SessionMessage
+		for _, sm := range messages {
+			result = append(result, msg.SessionMessage{
+				Role:      sm.Role,
+				Content:   sm.Content,
+				Timestamp: sm.Timestamp,
+			})
+		}
+		return msg.SessionSwitchResult{SessionID: info.ID, Messages: result}
+	}
+}
+
+func (m Model) handleSessionList(r msg.SessionListResult) (Model, tea.Cmd) {
+	if r.Err != nil {
+		m.chat.AddSystemError(i18n.T("Session list error: %v", r.Err))
+		return m, nil
+	}
+	if len(r.Sessions) == 0 {
+		m.chat.AddSystemMessage(i18n.T("No sessions found."))
+		return m, nil
+	}
+	var sb strings.Builder
+	sb.WriteString(i18n.T("Sessions:") + "\n")
+	for i, s := range r.Sessions {
+		title := s.Title
+		if title == "" {
+			title = i18n.T("(untitled)")
+		}
+		sb.WriteString(fmt.Sprintf("  %d. %s — %s (%s)\n",
+			i+1, shortID(s.ID), title, i18n.T("%d messages", s.MessageCount),
+		))
+	}
+	m.chat.AddSystemMessage(strings.TrimRight(sb.String(), "\n"))
+	return m, nil
+}
+
+func (m Model) handleSessionSwitch(r msg.SessionSwitchResult) (Model, tea.Cmd) {
+	atStartup := m.startSwitching
+	m.startSwitching = false
+	if r.Err != nil {
+		m.chat.AddSystemError(i18n.T("Session error: %v", r.Err))
+		if atStartup && m.startPrompt != "" {
+			// Leave the prompt for the user rather than sending it to a
+			// session they didn't ask for.
+			m.input.SetValue(m.startPrompt)
+			m.startPrompt = ""
+		}
+		return m, nil
+	}
+	m.closeSSE()
+	m.keepDraft()
+	m.sessionID = r.SessionID
+	m.syncSessionModel()
+	m.chat = chat.New(m.layout.ChatWidth, m.layout.ChatHeight)
+	m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
+
+	if len(r.Messages) > 0 {
+		for _, sm := range r.Messages {
+			switch sm.Role {
+			case "user":
+				m.chat.AddUserMessage(sm.Content)
+			case "assistant":
+				m.chat.AddAgentMessage(sm.Content, nil, 0, "")
+			default:
+				m.chat.AddSystemMessage(sm.Content)
+			}
+			if t, err := time.Parse(time.RFC3339, sm.Timestamp); err == nil {
+				m.chat.StampLast(t.Local())
+			}
+		}
+		m.chat.AddSystemMessage(i18n.T(
+			"--- Resumed session %s (%d messages) ---", shortID(r.SessionID), len(r.Messages),
+		))
+	} else {
+		m.chat.AddSystemMessage(i18n.T("Switched to session %s", shortID(r.SessionID)))
+	}
+
+	var cmds []tea.Cmd
+	cmds = append(cmds, m.focusInput(), m.restoreDraft())
+	if m.program != nil {
+		if cmd := m.startSSE(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if atStartup {
+		var cmd tea.Cmd
+		m, cmd = m.submitStartPrompt()
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// resumeLatest switches to the most recently active session, for --resume.
+func (m Model) resumeLatest() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		sessions, err := c.ListSessions()
+		if err != nil {
+			return msg.SessionSwitchResult{Err: err}
+		}
+		if len(sessions) == 0 {
+			return msg.SessionSwitchResult{Err: fmt.Errorf("no earlier session to resume")}
+		}
+		// The backend lists sessions by last activity, latest first.
+		return m.switchSession(sessions[0].ID)()
+	}
+}
+
+// submitStartPrompt submits the command-line prompt once the TUI is idle
+// and the startup session switch, if any, has landed.
+func (m Model) submitStartPrompt() (Model, tea.Cmd) {
+	if m.startPrompt == "" || m.startSwitching || m.state != StateIdle {
+		return m, nil
+	}
+	text := m.startPrompt
+	m.startPrompt = ""
+	return m.submitInput(text)
+}
+
+// -- Workspace files ----------------------------------------------------------
+
+// fetchGitStatus loads the workspace repository status for the sidebar and
+// header, from the backend's git sidecar or, when it has none, from a local
+// "git status".
+func (m Model) fetchGitStatus(poll bool) tea.Cmd {
+	c := m.client
+	root := gitRoot(m.header.Workspace())
+	local := m.gitLocal
+	return func() tea.Msg {
+		if root == "" {
+			return gitStatusLoaded{err: fmt.Errorf("not a git repository"), poll: poll}
+		}
+		err := client.ErrNotSupported // what the backend said last time, when local
+		if !local {
+			var st *client.GitStatusResponse
+			if st, err = c.GitStatus(root); err == nil {
+				return gitStatusLoaded{root: root, branch: st.Branch, files: sortedGitFiles(st.Files), poll: poll}
+			}
+		}
+		// The backend is unreachable or has no git sidecar: ask git directly.
+		branch, files, lerr := localGitStatus(root)
+		if lerr != nil {
+			return gitStatusLoaded{err: err, poll: poll}
+		}
+		return gitStatusLoaded{root: root, branch: branch, files: files, poll: poll,
+			local: errors.Is(err, client.ErrNotSupported)}
+	}
+}
+
+// sortedGitFiles converts the backend's file list for the sidebar, sorted by
+// path.
+func sortedGitFiles(in []client.GitFileStatus) []sidebar.GitFile {
+	files := make([]sidebar.GitFile, len(in))
+	for i, f := range in {
+		files[i] = sidebar.GitFile{Path: f.Path, Status: f.Status}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// localGitStatus runs "git status" in root for a backend without the git
+// sidecar. Without a git binary it falls back to reading the branch from
+// .git/HEAD, reporting no changed files.
+func localGitStatus(root string) (branch string, files []sidebar.GitFile, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitLocalTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", "-C", root, "status", "--porcelain", "--branch").Output()
+	if err != nil {
+		head, herr := os.ReadFile(filepath.Join(root, ".git", "HEAD"))
+		if herr != nil {
+			return "", nil, err
+		}
+		ref := strings.TrimSpace(string(head))
+		if b, ok := strings.CutPrefix(ref, "ref: refs/heads/"); ok {
+			return b, nil, nil
+		}
+		return "HEAD", nil, nil
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		code, path := line[:2], line[3:]
+		if code == "##" {
+			branch = porcelainBranch(path)
+			continue
+		}
+		if _, to, ok := strings.Cut(path, " -> "); ok {
+			path = to
+		}
+		files = append(files, sidebar.GitFile{Path: strings.Trim(path, `"`), Status: porcelainStatus(code)})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return branch, files, nil
+}
+
+// porcelainBranch extracts the branch from a porcelain "## main...origin/main
+// [ahead 1]" header.
+func porcelainBranch(s string) string {
+	if b, ok := strings.CutPrefix(s, "No commits yet on "); ok {
+		return b
+	}
+	if strings.HasPrefix(s, "HEAD (no branch)") {
+		return "HEAD"
+	}
+	s, _, _ = strings.Cut(s, "...")
+	s, _, _ = strings.Cut(s, " ")
+	return s
+}
+
+// porcelainStatus maps a porcelain XY code to the sidebar's status names.
+func porcelainStatus(code string) string {
+	switch {
+	case code == "??":
+		return "untracked"
+	case strings.Contains(code, "U") || code == "AA" || code == "DD":
+		return "conflict"
+	case strings.Contains(code, "R"):
+		return "renamed"
+	case strings.Contains(code, "C"):
+		return "copied"
+	case strings.Contains(code, "A"):
+		return "added"
+	case strings.Contains(code, "D"):
+		return "deleted"
+	}
+	return "modified"
+}
+
+// touchesFiles reports whether a tool may have changed workspace files, so
+// the git status is worth refreshing after it.
+func touchesFiles(tool string) bool {
+	switch tool {
+	case "file_write", "file_edit", "shell_execute":
+		return true
+	}
+	return false
+}
+
+// handleGitStatus updates the sidebar git section and the header branch.
+// Errors hide them; a backend without the git endpoint switches to the local
+// check, and polling stops only when that fails too.
+func (m Model) handleGitStatus(v gitStatusLoaded) (Model, tea.Cmd) {
+	if v.err != nil {
+		m.sidebar.ClearGitStatus()
+		m.status.SetGitBranch("")
+		m.header.SetGit("", false)
+	} else {
+		m.sidebar.SetGitStatus(v.root, v.branch, v.files)
+		m.status.SetGitBranch(v.branch)
+		m.header.SetGit(v.branch, len(v.files) > 0)
+	}
+	if v.local {
+		m.gitLocal = true
+	}
+	if !v.poll {
+		return m, nil
+	}
+	if errors.Is(v.err, client.ErrNotSupported) {
+		m.gitPolling = false
+		return m, nil
+	}
+	return m, tea.Tick(gitPollInterval, func(time.Time) tea.Msg { return gitPollTick{} })
+}
+
+// gitRoot returns the enclosing repository root of dir, or "".
+func gitRoot(dir string) string {
+	for dir != "" {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ""
+}
+
+// openInEditor suspends the TUI and opens path in $VISUAL or $EDITOR
+// (default vi). The editor value may include arguments, e.g. "code -w".
+func (m Model) openInEditor(path string) (Model, tea.Cmd) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	m.input.Blur()
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorClosed{path: path, err: err}
+	})
+}
+
+// -- Custom themes ------------------------------------------------------------
+
+// watchThemes polls ThemesDir and reports its signature after each interval.
+func watchThemes() tea.Cmd {
+	return tea.Tick(themeWatchInterval, func(time.Time) tea.Msg {
+		return themeWatchTick{sig: style.UserThemesSignature(ThemesDir)}
+	})
+}
+
+// handleThemeWatch reloads custom themes when a file in ThemesDir was added,
+// edited or removed, re-applying the active theme so edits show immediately.
+func (m Model) handleThemeWatch(v themeWatchTick) (Model, tea.Cmd) {
+	if v.sig == m.themeSig {
+		return m, watchThemes()
+	}
+	m.themeSig = v.sig
+	loaded, errs := style.LoadUserThemes(ThemesDir)
+	m.themeErrs = errs
+	for _, err := range errs {
+		m.chat.AddSystemWarning(i18n.T("Theme file skipped: %v", err))
+	}
+
+	current := style.CurrentThemeName
+	if !style.SetTheme(current) {
+		style.SetTheme("dark")
+		m.chat.AddSystemWarning(i18n.T("Theme %s was removed; switched to dark", current))
+	}
+	m.chat.InvalidateCache()
+	m.recomputeLayout()
+	m.toasts.Add(i18n.T("Reloaded custom themes (%d)", len(loaded)), toast.ToastInfo)
+	return m, tea.Batch(watchThemes(), m.tickCmd())
+}
+
+// -- Terminal appearance ------------------------------------------------------
+
+// autoTheme reports whether the theme follows the terminal background.
+func (m Model) autoTheme() bool {
+	return m.config.Theme == "" || m.config.Theme == "auto"
+}
+
+// openThemePicker lists the themes in place of the input. Moving the cursor
+// previews a theme on the chat; Enter applies it and Esc restores the one in
+// use now.
+func (m Model) openThemePicker() Model {
+	items := []dialog.ThemeItem{{
+		Name:   "auto",
+		Detail: i18n.T("follow terminal, now %s", style.CurrentThemeName),
+		Active: m.autoTheme(),
+	}}
+	for _, name := range style.ThemeNames {
+		item := dialog.ThemeItem{Name: name, Active: name == style.CurrentThemeName && !m.autoTheme()}
+		if style.IsUserTheme(name) {
+			item.Detail = i18n.T("custom")
+		}
+		items = append(items, item)
+	}
+	var notes []string
+	for _, err := range m.themeErrs {
+		notes = append(notes, fmt.Sprintf("! %v", err))
+	}
+	notes = append(notes, i18n.T("Colors: %s · Custom themes: %s", style.CurrentColorMode, ThemesDir))
+
+	m.themeOrig = style.CurrentThemeName
+	m.themes.SetWidth(m.width - 4)
+	m.themes.Open(items, notes)
+	m.pushModal(StateThemePicker)
+	m.recomputeLayout()
+	return m
+}
+
+// closeThemePicker returns to the input after the picker is done.
+func (m *Model) closeThemePicker() {
+	m.themes.Clear()
+	m.closeModal(StateThemePicker)
+	m.recomputeLayout()
+}
+
+// previewTheme shows a theme without saving it. "auto" previews the theme
+// that was in use when the picker opened, as the terminal background decides
+// the real one only once it is applied.
+func (m *Model) previewTheme(name string) {
+	if name == "auto" {
+		name = m.themeOrig
+	}
+	if name == style.CurrentThemeName || !style.SetTheme(name) {
+		return
+	}
+	m.chat.InvalidateCache()
+	m.recomputeLayout()
+}
+
+// setTheme applies and persists a theme, or "auto" to follow the terminal
+// background.
+func (m Model) setTheme(name string) (Model, tea.Cmd) {
+	if name == "auto" {
+		m.config.Theme = name
+		if err := config.Save(profileDirPath(), m.config); err != nil {
+			m.chat.AddSystemWarning(i18n.T("Theme applied but could not persist: %v", err))
+		}
+		m.toasts.Add(i18n.T("Theme follows terminal background"), toast.ToastInfo)
+		return m, tea.Batch(tea.RequestBackgroundColor, m.tickCmd())
+	}
+	if !style.SetTheme(name) {
+		m.chat.AddSystemError(i18n.T(
+			"Unknown theme: %s (available: %s)", name, strings.Join(style.ThemeNames, ", "),
+		))
+		return m, nil
+	}
+	m.config.Theme = name
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Theme applied but could not persist: %v", err))
+	}
+	m.chat.InvalidateCache()
+	m.recomputeLayout()
+	m.toasts.Add(i18n.T("Theme set to: %s", name), toast.ToastInfo)
+	return m, m.tickCmd()
+}
+
+// applyAutoTheme switches between the dark and light themes when the
+// terminal background or OS appearance changes and no theme is pinned.
+// While the theme picker is previewing, the change waits for its outcome.
+func (m Model) applyAutoTheme(dark bool) (Model, tea.Cmd) {
+	if !m.autoTheme() || m.hasModal(StateThemePicker) {
+		return m, nil
+	}
+	name := "light"
+	if dark {
+		name = "dark"
+	}
+	if name == style.CurrentThemeName {
+		return m, nil
+	}
+	style.SetTheme(name)
+	m.chat.InvalidateCache()
+	m.recomputeLayout()
+	return m, nil
+}
+
+// colorMode returns the forced color mode from --colors or the config.
+// ok is false when the mode should follow the detected terminal profile.
+func (m Model) colorMode() (style.ColorMode, bool) {
+	if ColorModeFlag != "" {
+		return style.ParseColorMode(ColorModeFlag)
+	}
+	return style.ParseColorMode(m.config.ColorMode)
+}
+
+// handleColorProfile adapts the palette to the terminal's color depth. A
+// forced mode is pushed back to the renderer so output is quantized the same
+// way the palette was, except when colors are disabled entirely (NO_COLOR).
+func (m Model) handleColorProfile(v tea.ColorProfileMsg) (Model, tea.Cmd) {
+	mode, forced := m.colorMode()
+	if forced && v.Profile > colorprofile.ASCII && v.Profile != mode.Profile() {
+		want := mode.Profile()
+		return m, func() tea.Msg { return tea.ColorProfileMsg{Profile: want} }
+	}
+	if !forced {
+		mode = style.ColorModeForProfile(v.Profile)
+	}
+	style.SetColorMode(mode)
+	m.recomputeLayout()
+	return m, nil
+}
+
+// -- Reply --------------------------------------------------------------------
+
+// replyToMessage quotes the selected message, or the latest agent reply, at
+// the top of the input and records it as the reply target of the next prompt.
+func (m Model) replyToMessage() (Model, tea.Cmd) {
+	q, ok := m.chat.SelectedQuote()
+	if !ok {
+		m.toasts.Add(i18n.T("Nothing to reply to"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	block, excerpt := quoteBlock(q)
+	role := "assistant"
+	if q.Role == chat.RoleUser {
+		role = "user"
+	}
+	m.replyTo = &client.ReplyRef{
+		MessageID: q.ID,
+		Role:      role,
+		Timestamp: q.Timestamp.Format(time.RFC3339),
+		Excerpt:   excerpt,
+	}
+	m.replyHeader, _, _ = strings.Cut(block, "\n")
+
+	m.input.SetValue(block + "\n\n" + m.input.Value())
+	m.chat.ClearSelection()
+	return m, m.focusInput()
+}
+
+// quoteBlock formats q as a markdown blockquote headed by a line that names
+// the quoted message, and returns the quoted excerpt.
+func quoteBlock(q chat.Quote) (block, excerpt string) {
+	lines := strings.Split(strings.TrimSpace(q.Content), "\n")
+	cut := len(lines) > replyExcerptLines
+	if cut {
+		lines = lines[:replyExcerptLines]
+	}
+	excerpt = strings.Join(lines, "\n")
+
+	whose := "your"
+	if q.Role == chat.RoleUser {
+		whose = "my"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "> In reply to %s message at %s (%s):", whose, q.Timestamp.Format("15:04"), q.ID)
+	for _, l := range lines {
+		sb.WriteString("\n> " + l)
+	}
+	if cut {
+		sb.WriteString("\n> …")
+	}
+	return sb.String(), excerpt
+}
+
+// -- Prompt templates ---------------------------------------------------------
+
+// promptsUsage is shown by /prompts and after a malformed subcommand.
+const promptsUsage = "Usage: /prompts [use <name|n> | save <name> [text] | delete <name> | import <path> | export <name> <path>]"
+
+// handlePromptsCommand implements /prompts. Templates live in the profile's
+// prompts directory, one file per template.
+func (m Model) handlePromptsCommand(arg string) (Model, tea.Cmd) {
+	dir := prompts.Dir(profileDirPath())
+	args := strings.Fields(arg)
+	if len(args) == 0 {
+		tmpls, err := prompts.List(dir)
+		if err != nil {
+			m.chat.AddSystemError(i18n.T("Cannot read prompt templates: %v", err))
+			return m, nil
+		}
+		var sb strings.Builder
+		if len(tmpls) == 0 {
+			sb.WriteString(i18n.T("No prompt templates yet. Save one with /prompts save <name>, or add .md files to %s", dir) + "\n")
+		} else {
+			sb.WriteString(i18n.T("Prompt templates (%s):", dir) + "\n")
+			for i, t := range tmpls {
+				fmt.Fprintf(&sb, "  %2d  %-24s %s\n", i+1, t.Name, templateSummary(t))
+			}
+		}
+		sb.WriteString(i18n.T(promptsUsage))
+		m.chat.AddSystemMessage(sb.String())
+		return m, nil
+	}
+
+	sub, rest := args[0], args[1:]
+	switch {
+	case sub == "use" && len(rest) == 1:
+		t, err := m.findTemplate(dir, rest[0])
+		if err != nil {
+			m.chat.AddSystemError(err.Error())
+			return m, nil
+		}
+		return m.useTemplate(t)
+
+	case sub == "save" && len(rest) >= 1:
+		// The text keeps its own spacing and newlines.
+		body := strings.TrimSpace(strings.TrimPrefix(arg, "save"))
+		body = strings.TrimSpace(strings.TrimPrefix(body, rest[0]))
+		if body == "" {
+			body = m.input.LastPrompt()
+		}
+		if body == "" {
+			m.chat.AddSystemError(i18n.T("Nothing to save: give the text, or send a prompt first."))
+			return m, nil
+		}
+		t, err := prompts.Save(dir, rest[0], body)
+		if err != nil {
+			m.chat.AddSystemError(i18n.T("Cannot save template: %v", err))
+			return m, nil
+		}
+		m.chat.AddSystemMessage(i18n.T("Saved template %s (%s)", t.Name, t.Path))
+		return m, nil
+
+	case sub == "delete" && len(rest) == 1:
+		if err := prompts.Delete(dir, rest[0]); err != nil {
+			m.chat.AddSystemError(i18n.T("Cannot delete template: %v", err))
+			return m, nil
+		}
+		m.chat.AddSystemMessage(i18n.T("Deleted template %s", rest[0]))
+		return m, nil
+
+	case sub == "import" && len(rest) == 1:
+		t, err := prompts.Import(dir, m.workspacePath(rest[0]))
+		if err != nil {
+			m.chat.AddSystemError(i18n.T("Cannot import template: %v", err))
+			return m, nil
+		}
+		m.chat.AddSystemMessage(i18n.T("Imported template %s", t.Name))
+		return m, nil
+
+	case sub == "export" && len(rest) == 2:
+		if err := prompts.Export(dir, rest[0], m.workspacePath(rest[1])); err != nil {
+			m.chat.AddSystemError(i18n.T("Cannot export template: %v", err))
+			return m, nil
+		}
+		m.chat.AddSystemMessage(i18n.T("Exported template %s to %s", rest[0], rest[1]))
+		return m, nil
+	}
+	m.chat.AddSystemError(i18n.T(promptsUsage))
+	return m, nil
+}
+
+// findTemplate resolves a template by name or by its number in /prompts.
+func (m Model) findTemplate(dir, ref string) (prompts.Template, error) {
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return prompts.Load(dir, ref)
+	}
+	tmpls, err := prompts.List(dir)
+	if err != nil {
+		return prompts.Template{}, err
+	}
+	if n < 1 || n > len(tmpls) {
+		return prompts.Template{}, fmt.Errorf("no template %d (%d saved)", n, len(tmpls))
+	}
+	return tmpls[n-1], nil
+}
+
+// useTemplate inserts a template into the input, first asking for its
+// placeholder values when it has any.
+func (m Model) useTemplate(t prompts.Template) (Model, tea.Cmd) {
+	vars := t.Vars()
+	if len(vars) == 0 {
+		return m.insertPrompt(t.Fill(nil))
+	}
+	fields := make([]dialog.FormField, len(vars))
+	for i, v := range vars {
+		fields[i] = dialog.FormField{Label: v.Name, Value: v.Default}
+	}
+	m.formTemplate = t
+	m.form = dialog.NewForm("prompt", t.Name, fields)
+	m.form.SetSize(m.width, m.height)
+	m.pushModal(StateForm)
+	return m, nil
+}
+
+func (m Model) handleFormSubmit(f dialog.FormSubmit) (Model, tea.Cmd) {
+	m.closeModal(StateForm)
+	if f.ID == "mcp" {
+		return m.submitMCPForm(f.Values)
+	}
+	if f.ID == "prefs" {
+		return m.submitPrefsForm(f.Values)
+	}
+	if f.ID == "feedback" {
+		return m.submitFeedbackForm(f.Values)
+	}
+	if strings.HasPrefix(f.ID, commandFormPrefix) {
+		return m.submitCommandForm(f.Values)
+	}
+	if f.ID != "prompt" {
+		return m, m.focusInput()
+	}
+	values := make(map[string]string)
+	for i, v := range m.formTemplate.Vars() {
+		if i < len(f.Values) {
+			values[v.Name] = f.Values[i]
+		}
+	}
+	return m.insertPrompt(m.formTemplate.Fill(values))
+}
+
+// insertPrompt appends text to the input for review before sending.
+func (m Model) insertPrompt(text string) (Model, tea.Cmd) {
+	if cur := m.input.Value(); cur != "" {
+		text = cur + "\n" + text
+	}
+	m.input.SetValue(text)
+	return m, m.focusInput()
+}
+
+// workspacePath resolves a user-typed path: "~/" is the home directory and
+// relative paths are relative to the workspace.
+func (m Model) workspacePath(p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(m.header.Workspace(), p)
+}
+
+// templateSummary returns the first non-blank line of a template, shortened.
+func templateSummary(t prompts.Template) string {
+	for _, l := range strings.Split(t.Body, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			return ansi.Truncate(l, 60, "…")
+		}
+	}
+	return ""
+}
+
+// -- Scheduled prompts --------------------------------------------------------
+
+// scheduleUsage is shown after a malformed /schedule command.
+const scheduleUsage = `Usage: /schedule "<when>" <prompt>, e.g. /schedule "every weekday 9am" Summarize open PRs`
+
+// upcomingRuns is how many next runs the schedule manager shows per job.
+const upcomingRuns = 3
+
+// createSchedule implements /schedule "<when>" <prompt>. The job runs the
+// prompt through the backend scheduler and reports to the current session.
+func (m Model) createSchedule(arg string) (Model, tea.Cmd) {
+	when, prompt, ok := cutQuoted(arg)
+	prompt = strings.TrimSpace(prompt)
+	if !ok || when == "" || prompt == "" {
+		m.chat.AddSystemError(i18n.T(scheduleUsage))
+		return m, nil
+	}
+	cron, err := schedule.Parse(when, time.Local, time.Now())
+	if err != nil {
+		m.chat.AddSystemError(i18n.T("Invalid schedule: %v", err))
+		return m, nil
+	}
+	req := client.SchedulerJobRequest{
+		Name:          scheduleName(prompt),
+		Schedule:      cron,
+		Type:          "agent",
+		Job:           prompt,
+		Spec:          when,
+		NotifySession: m.sessionID,
+	}
+	c := m.client
+	return m, func() tea.Msg {
+		job, err := c.CreateSchedulerJob(req)
+		return scheduleCreated{job: job, err: err}
+	}
+}
+
+func (m Model) handleScheduleCreated(r scheduleCreated) (Model, tea.Cmd) {
+	switch {
+	case errors.Is(r.err, client.ErrNotSupported):
+		m.chat.AddSystemWarning(i18n.T("Scheduling is not supported by this backend."))
+	case r.err != nil:
+		m.chat.AddSystemError(i18n.T("Failed to schedule: %v", r.err))
+	default:
+		next := i18n.T("no run within a year")
+		if runs, err := schedule.Next(r.job.Schedule, time.Now(), 1); err == nil && len(runs) > 0 {
+			next = i18n.T("next run %s", runs[0].Format("Mon Jan 2 15:04"))
+		}
+		m.chat.AddSystemMessage(i18n.T("Scheduled %s: %s (%s). Results arrive in this session; manage with /schedule.",
+			r.job.Name, scheduleWhen(*r.job), next))
+	}
+	return m, nil
+}
+
+func (m Model) fetchSchedulerJobs() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		jobs, err := c.ListSchedulerJobs()
+		return schedulerJobsLoaded{jobs: jobs, err: err}
+	}
+}
+
+// handleSchedulerJobs opens the schedule manager, or refreshes it after an
+// action.
+func (m Model) handleSchedulerJobs(r schedulerJobsLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if m.hasModal(StateSchedule) {
+			m.scheduler.SetStatus(i18n.T("Failed to load jobs: %v", r.err), true)
+			return m, nil
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load scheduled jobs: %v", r.err))
+		return m, nil
+	}
+	now := time.Now()
+	entries := make([]dialog.ScheduleEntry, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		e := dialog.ScheduleEntry{
+			ID:          j.ID,
+			Name:        j.Name,
+			When:        scheduleWhen(j),
+			Prompt:      j.Job,
+			Enabled:     j.Enabled,
+			CircuitOpen: j.CircuitOpen,
+		}
+		if j.Enabled && !j.CircuitOpen {
+			e.Upcoming, _ = schedule.Next(j.Schedule, now, upcomingRuns)
+		}
+		entries = append(entries, e)
+	}
+	m.scheduler.SetEntries(entries)
+	m.scheduler.SetSize(m.width, m.height)
+	if !m.hasModal(StateSchedule) {
+		m.pushModal(StateSchedule)
+	}
+	return m, nil
+}
+
+func (m Model) applyScheduleAction(a dialog.ScheduleAction) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		var err error
+		switch a.Action {
+		case "toggle":
+			err = c.SetSchedulerJobEnabled(a.ID, a.Enabled)
+		case "delete":
+			err = c.DeleteSchedulerJob(a.ID)
+		}
+		if errors.Is(err, client.ErrNotSupported) {
+			err = fmt.Errorf("not supported by this backend")
+		}
+		return scheduleActionDone{action: a, err: err}
+	}
+}
+
+// scheduleWhen describes a job's schedule: its phrase when it has one,
+// otherwise the cron expression.
+func scheduleWhen(j client.SchedulerJob) string {
+	if j.Spec != "" {
+		return j.Spec
+	}
+	return j.Schedule + " (UTC)"
+}
+
+// scheduleName derives a short job name from the first words of a prompt.
+func scheduleName(prompt string) string {
+	words := strings.Fields(prompt)
+	if len(words) > 5 {
+		words = words[:5]
+	}
+	return ansi.Truncate(strings.Join(words, " "), 40, "…")
+}
+
+// cutQuoted splits a leading "double" or 'single' quoted string from s.
+func cutQuoted(s string) (quoted, rest string, ok bool) {
+	if s == "" || (s[0] != '"' && s[0] != '\'') {
+		return "", s, false
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 0 {
+		return "", s, false
+	}
+	return s[1 : end+1], s[end+2:], true
+}
+
+// -- Pinned pane --------------------------------------------------------------
+
+// handlePinCommand implements /pin. With no argument it reports what is
+// pinned; otherwise it pins a file, the workspace diff, the latest plan or the
+// latest tool output.
+func (m Model) handlePinCommand(arg string) (Model, tea.Cmd) {
+	switch arg {
+	case "":
+		if m.pinned.IsActive() {
+			m.chat.AddSystemMessage(i18n.T("Pinned: %s\nUsage: /pin <n|path|diff|plan|tool|off>", m.pinned.Title()))
+		} else {
+			m.chat.AddSystemMessage(i18n.T("Nothing pinned. Usage: /pin <n|path|diff|plan|tool|off>"))
+		}
+		return m, nil
+	case "off":
+		m.pinned.Close()
+		m.recomputeLayout()
+		return m, nil
+	case "diff":
+		root := gitRoot(m.header.Workspace())
+		if root == "" {
+			m.chat.AddSystemError(i18n.T("Not in a git repository."))
+			return m, nil
+		}
+		c := m.client
+		return m, func() tea.Msg {
+			d, err := c.GitDiff(root)
+			return gitDiffLoaded{diff: d, err: err}
+		}
+	case "plan":
+		if m.lastPlan == "" {
+			m.chat.AddSystemError(i18n.T("No plan to pin yet."))
+			return m, nil
+		}
+		return m.pin(pinned.KindMarkdown, "Plan", m.lastPlan)
+	case "tool":
+		if m.lastToolName == "" {
+			m.chat.AddSystemError(i18n.T("No tool output to pin yet."))
+			return m, nil
+		}
+		return m.pin(pinned.KindText, m.lastToolName+" output", m.lastToolResult)
+	}
+
+	path := arg
+	if n, err := strconv.Atoi(arg); err == nil {
+		items := m.sidebar.OpenableItems()
+		if n < 1 || n > len(items) {
+			m.chat.AddSystemError(i18n.T("No file %d (sidebar lists %d)", n, len(items)))
+			return m, nil
+		}
+		path = items[n-1]
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.header.Workspace(), path)
+	}
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		m.chat.AddSystemError(i18n.T("Cannot pin %s: %v", arg, err))
+		return m, nil
+	case info.IsDir():
+		m.chat.AddSystemError(i18n.T("Cannot pin %s: is a directory", arg))
+		return m, nil
+	case info.Size() > maxPinnedFileSize:
+		m.chat.AddSystemError(i18n.T("Cannot pin %s: file is larger than 1 MB", arg))
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.chat.AddSystemError(i18n.T("Cannot pin %s: %v", arg, err))
+		return m, nil
+	}
+	title, err := filepath.Rel(m.header.Workspace(), path)
+	if err != nil || strings.HasPrefix(title, "..") {
+		title = path
+	}
+	return m.pin(pinned.KindFile, title, string(data))
+}
+
+// pin shows content in the pinned pane, or explains why it cannot be shown.
+func (m Model) pin(kind pinned.Kind, title, content string) (Model, tea.Cmd) {
+	m.pinned.Pin(kind, title, content)
+	m.recomputeLayout()
+	if m.layout.PinnedWidth == 0 {
+		m.chat.AddSystemWarning(i18n.T("Pinned %s, but the terminal is too narrow to show it beside the chat.", title))
 	}
+	return m, nil
 }
 
-func (m Model) tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg { return msg.TickMsg{} })
-}
+// -- Pane sizing --------------------------------------------------------------
 
-// -- Model selection ---------------------------------------------------------
+// dragTarget identifies a pane divider grabbed with the mouse.
+type dragTarget int
 
-func (m Model) handleModelList(r msg.ModelListResult) (Model, tea.Cmd) {
-	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Failed to list models: %v", r.Err))
-		return m, m.input.Focus()
+const (
+	dragNone    dragTarget = iota
+	dragSidebar            // sidebar's right border
+	dragTasks              // top line of the tasks panel
+	dragAgents             // top line of the agents panel
+)
+
+// tasksView renders the tasks panel, capped at the configured height.
+func (m Model) tasksView() string { return clampPanel(m.tasks.View(), m.config.TasksHeight) }
+
+// agentsView renders the agents panel, capped at the configured height.
+func (m Model) agentsView() string { return clampPanel(m.agents.View(), m.config.AgentsHeight) }
+
+// tasksHeight and agentsHeight are the heights of tasksView and agentsView.
+func (m Model) tasksHeight() int  { return panelHeight(m.tasks.Height(), m.config.TasksHeight) }
+func (m Model) agentsHeight() int { return panelHeight(m.agents.Height(), m.config.AgentsHeight) }
+
+// clampPanel cuts view to maxLines, replacing the overflow with a summary.
+// maxLines <= 0 leaves view unchanged.
+func clampPanel(view string, maxLines int) string {
+	if view == "" || maxLines <= 0 {
+		return view
 	}
-	if len(r.Models) == 0 {
-		m.chat.AddSystemWarning(fmt.Sprintf(
-			"No models available. Current: %s. Is Ollama running?", m.header.ModelName(),
-		))
-		return m, m.input.Focus()
+	lines := strings.Split(view, "\n")
+	if len(lines) <= maxLines {
+		return view
+	}
+	hidden := len(lines) - maxLines + 1
+	if maxLines == 1 {
+		return lines[0] + style.Hint.Render(fmt.Sprintf("  (+%d, ctrl+↑)", hidden-1))
 	}
+	more := style.Hint.Render(fmt.Sprintf("     … %d more (ctrl+↑ to expand)", hidden))
+	return strings.Join(lines[:maxLines-1], "\n") + "\n" + more
+}
 
-	filter := m.pendingProviderFilter
-	m.pendingProviderFilter = ""
+// -- Toasts -------------------------------------------------------------------
 
-	var items []dialog.PickerItem
-	for _, entry := range r.Models {
-		if filter != "" && strings.ToLower(entry.Provider) != filter {
-			continue
-		}
-		items = append(items, dialog.PickerItem{
-			Name:     entry.Name,
-			Provider: entry.Provider,
-			Size:     entry.Size,
-			Active:   entry.Active,
-		})
+// handleToastKey runs the action behind Alt+1..Alt+3 on the newest toast
+// offering one. ok is false for other keys, or when no toast has actions.
+func (m Model) handleToastKey(k tea.KeyPressMsg) (Model, tea.Cmd, bool) {
+	if !key.Matches[tea.KeyPressMsg](k, m.keys.ToastAction) || !m.toasts.HasActions() {
+		return m, nil, false
 	}
-
-	if len(items) == 0 {
-		m.chat.AddSystemError(fmt.Sprintf(
-			"No models available for provider: %s. Is the API key configured?", filter,
-		))
-		return m, m.input.Focus()
+	a, ok := m.toasts.Trigger(int(k.Code - '0'))
+	if !ok {
+		return m, nil, true
 	}
+	mm, cmd := m.runToastAction(a.ID)
+	return mm, cmd, true
+}
 
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Provider != items[j].Provider {
-			return items[i].Provider < items[j].Provider
+// handleChordKey collects a key sequence bound as a chord, such as "g g" or
+// the leader "space s n", while the input is empty. ok is false for keys that
+// do not start one. A key that does not continue a pending chord abandons it:
+// its keys are typed into the input and the key is handled as usual, so "go"
+// still types.
+func (m Model) handleChordKey(k tea.KeyPressMsg) (Model, tea.Cmd, bool) {
+	if len(m.chordKeys) == 0 && (m.input.Value() != "" || m.input.HasPastes() || m.input.CompletionsVisible()) {
+		return m, nil, false
+	}
+	seq := append(slices.Clone(m.chordKeys), k.String())
+	var next []key.Help
+	for _, c := range m.keys.Chords() {
+		if c.Matches(seq) {
+			m.chordKeys = nil
+			m.input.SetChordHint("")
+			mm, cmd := m.runChord(c)
+			return mm, cmd, true
 		}
-		return items[i].Name < items[j].Name
-	})
-
-	m.picker.SetWidth(m.width - 4)
-	m.picker.SetItems(items)
-	m.state = StateModelPicker
-	m.input.Blur()
-	return m, nil
+		if c.Continues(seq) {
+			rest := c.Keys()[len(seq):]
+			next = append(next, key.Help{Key: strings.Join(rest, " "), Desc: c.Help().Desc})
+		}
+	}
+	if len(next) == 0 {
+		if len(m.chordKeys) == 0 {
+			return m, nil, false
+		}
+		mm, cmd := m.abandonChord().handleIdleKey(k)
+		return mm.(Model), cmd, true
+	}
+	m.chordKeys = seq
+	m.chordSeq++
+	parts := make([]string, len(next))
+	for i, h := range next {
+		parts[i] = h.Key + " " + h.Desc
+	}
+	m.input.SetChordHint(strings.Join(seq, " ") + " … " + strings.Join(parts, " · "))
+	seqNo := m.chordSeq
+	return m, tea.Tick(chordTimeout, func(time.Time) tea.Msg { return chordExpired{seq: seqNo} }), true
 }
 
-func (m Model) handleModelSwitch(r msg.ModelSwitchResult) (Model, tea.Cmd) {
-	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Switch failed: %v", r.Err))
-		return m, nil
+// abandonChord drops a pending chord, typing its keys into the input as if
+// they had never been taken for one.
+func (m Model) abandonChord() Model {
+	keys := m.chordKeys
+	m.chordKeys = nil
+	m.input.SetChordHint("")
+	for _, s := range keys {
+		if s == leaderKey {
+			continue // a leading space is trimmed on submit anyway
+		}
+		m.input, _ = m.input.Update(tea.KeyPressMsg{Code: []rune(s)[0], Text: s})
 	}
-	m.status.SetProviderInfo(r.Provider, r.Model)
-	m.header.SetModelOverride(r.Provider, r.Model)
-	m.sidebar.SetModelInfo(r.Provider, r.Model)
-	m.chat.AddSystemMessage(fmt.Sprintf("Switched to %s / %s", r.Provider, r.Model))
-	return m, m.checkHealth()
+	return m
 }
 
-func (m Model) handlePickerChoice(c dialog.PickerChoice) (Model, tea.Cmd) {
-	m.picker.Clear()
-	m.state = StateIdle
-	m.chat.AddSystemMessage(fmt.Sprintf("Switching to %s / %s...", c.Provider, c.Name))
-	return m, tea.Batch(m.input.Focus(), m.switchModel(c.Provider, c.Name))
+// runChord carries out a completed chord. Leader chords run the slash
+// command they stand for.
+func (m Model) runChord(c Chord) (Model, tea.Cmd) {
+	switch {
+	case c.Matches(m.keys.GotoTop.Keys()):
+		m.chat.ScrollToTop()
+		return m, nil
+	case c.Matches(m.keys.GotoBottom.Keys()):
+		m.chat.ScrollToBottom()
+		return m, nil
+	case c.Matches(m.keys.LeaderNew.Keys()):
+		return m.submitInput("/session new")
+	case c.Matches(m.keys.LeaderResume.Keys()):
+		return m.submitInput("/sessions")
+	case c.Matches(m.keys.LeaderModels.Keys()):
+		return m.submitInput("/models")
+	case c.Matches(m.keys.LeaderTheme.Keys()):
+		return m.submitInput("/theme")
+	case c.Matches(m.keys.LeaderHistory.Keys()):
+		return m.submitInput("/notifications")
+	}
+	return m, nil
 }
 
-func (m Model) fetchModels() tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		resp, err := c.ListModels()
-		if err != nil {
-			return msg.ModelListResult{Err: err}
-		}
-		var models []msg.ModelEntry
-		for _, entry := range resp.Models {
-			models = append(models, msg.ModelEntry{
-				Name:     entry.Name,
-				Provider: entry.Provider,
-				Size:     entry.Size,
-				Active:   entry.Active,
-			})
+// runToastAction carries out a toast action by ID.
+func (m Model) runToastAction(id string) (Model, tea.Cmd) {
+	switch {
+	case id == "open-log":
+		return m.openInEditor(filepath.Join(profileDirPath(), "tui.log"))
+	case id == "paste-image":
+		return m, pasteClipboardImage()
+	case id == "compact":
+		return m.submitInput("/compact")
+	case id == "session-new":
+		return m.submitInput("/session new")
+	case id == "pull-cancel":
+		if m.ollamaPull != nil {
+			m.ollamaPull.Close()
 		}
-		return msg.ModelListResult{Models: models, Current: resp.Current, Provider: resp.Provider}
+		return m, nil
+	case strings.HasPrefix(id, "pull:"):
+		return m.pullModelCmd(strings.TrimPrefix(id, "pull:"))
+	case strings.HasPrefix(id, "use-model:"):
+		name := strings.TrimPrefix(id, "use-model:")
+		m.chat.AddSystemMessage(i18n.T("Switching to ollama / %s...", name))
+		return m, m.switchModel("ollama", name)
 	}
+	return m, nil
 }
 
-func (m Model) switchModel(provider, modelName string) tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		resp, err := c.SwitchModel(client.ModelSwitchRequest{Provider: provider, Model: modelName})
-		if err != nil {
-			return msg.ModelSwitchResult{Err: err}
+// notificationsText lists the dismissed toasts, newest first.
+func (m Model) notificationsText() string {
+	history := m.toasts.History()
+	if len(history) == 0 {
+		return i18n.T("No notifications yet.")
+	}
+	var sb strings.Builder
+	sb.WriteString(i18n.T("Notifications:") + "\n")
+	for _, e := range history {
+		icon := "✓"
+		switch e.Level {
+		case toast.ToastWarning:
+			icon = "⚠"
+		case toast.ToastError:
+			icon = "✘"
 		}
-		return msg.ModelSwitchResult{Provider: resp.Provider, Model: resp.Model}
+		sb.WriteString(fmt.Sprintf("  %s  %s %s\n", e.At.Format("15:04:05"), icon, e.Message))
 	}
+	return strings.TrimRight(sb.String(), "\n")
 }
 
-// -- Session management -------------------------------------------------------
-
-func (m Model) listSessions() tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		sessions, err := c.ListSessions()
-		if err != nil {
-			return msg.SessionListResult{Err: err}
-		}
-		var result []msg.SessionInfo
-		for _, s := range sessions {
-			result = append(result, msg.SessionInfo{
-				ID:           s.ID,
-				CreatedAt:    s.CreatedAt,
-				Title:        s.Title,
-				MessageCount: s.MessageCount,
-			})
-		}
-		return msg.SessionListResult{Sessions: result}
+// pullModelCmd pulls an Ollama model in the background, its progress shown
+// in a toast offering to cancel it.
+func (m Model) pullModelCmd(name string) (Model, tea.Cmd) {
+	switch {
+	case name == "":
+		m.chat.AddSystemError(i18n.T("No model to pull. Usage: /model pull <name>"))
+		return m, nil
+	case m.ollamaPull != nil:
+		m.chat.AddSystemWarning(i18n.T("A model pull is already running."))
+		return m, nil
+	case m.program == nil:
+		m.chat.AddSystemError(i18n.T("Cannot pull models before the TUI is ready."))
+		return m, nil
 	}
+	m.ollamaPull = client.NewOllama()
+	m.pullModel = name
+	m.toasts.SetProgress("pull:"+name, i18n.T("Pulling %s…", name), 0, 0,
+		toast.Action{ID: "pull-cancel", Label: i18n.T("Cancel")})
+	return m, tea.Batch(m.ollamaPull.PullCmd(m.program, name), m.tickCmd())
 }
 
-func (m Model) createSession() tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		resp, err := c.CreateSession()
-		if err != nil {
-			return msg.SessionSwitchResult{Err: err}
-		}
-		return msg.SessionSwitchResult{SessionID: resp.ID}
+// finishPull replaces a /model pull's progress toast with the outcome.
+func (m Model) finishPull(v client.OllamaPullDoneEvent) (Model, tea.Cmd) {
+	m.pullModel = ""
+	key := "pull:" + v.Model
+	switch {
+	case v.Cancelled:
+		m.toasts.FinishProgress(key, i18n.T("Pull of %s cancelled", v.Model), toast.ToastInfo)
+	case v.Err != nil:
+		log.Printf("ollama pull %s: %v", v.Model, v.Err)
+		m.toasts.FinishProgress(key, i18n.T("Pull of %s failed: %v", v.Model, v.Err), toast.ToastError,
+			toast.Action{ID: key, Label: i18n.T("Retry")},
+			toast.Action{ID: "open-log", Label: i18n.T("Open log")})
+	default:
+		m.toasts.FinishProgress(key, i18n.T("Pulled %s", v.Model), toast.ToastInfo,
+			toast.Action{ID: "use-model:" + v.Model, Label: i18n.T("Use")})
 	}
+	return m, m.tickCmd()
 }
 
-func (m Model) switchSession(id string) tea.Cmd {
-	c := m.client
-	return func() tea.Msg {
-		info, err := c.GetSession(id)
-		if err != nil {
-			return msg.SessionSwitchResult{Err: err}
+// handlePaneKey applies the pane sizing and pinned pane scrolling keys. ok
+// is false for other keys.
+func (m Model) handlePaneKey(k tea.KeyPressMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches[tea.KeyPressMsg](k, m.keys.SidebarShrink):
+		if m.layout.SidebarWidth == 0 {
+			return m, nil, true
 		}
-		messages := info.Messages
-		if len(messages) == 0 {
-			fetched, err := c.GetSessionMessages(id)
-			if err == nil {
-				messages = fetched
-			}
+		if m.layout.SidebarWidth <= sidebarMinWidth {
+			// Narrowing past the minimum collapses the sidebar.
+			mm, cmd := m.update(msg.ToggleSidebar{})
+			return mm.(Model), cmd, true
 		}
-		var result []msg.SessionMessage
-		for _, sm := range messages {
-			result = append(result, msg.SessionMessage{
-				Role:      sm.Role,
-				Content:   sm.Content,
-				Timestamp: sm.Timestamp,
-			})
+		m.config.SidebarWidth = m.layout.SidebarWidth - sidebarResizeStep
+	case key.Matches[tea.KeyPressMsg](k, m.keys.SidebarGrow):
+		if m.layout.SidebarWidth == 0 {
+			if m.layoutMode == LayoutSidebar {
+				return m, nil, true // terminal too narrow for the sidebar
+			}
+			mm, cmd := m.update(msg.ToggleSidebar{})
+			return mm.(Model), cmd, true
 		}
-		return msg.SessionSwitchResult{SessionID: info.ID, Messages: result}
+		m.config.SidebarWidth = m.layout.SidebarWidth + sidebarResizeStep
+	case key.Matches[tea.KeyPressMsg](k, m.keys.PanelGrow):
+		m.resizePanel(m.activePanel(), +1)
+	case key.Matches[tea.KeyPressMsg](k, m.keys.PanelShrink):
+		m.resizePanel(m.activePanel(), -1)
+	case key.Matches[tea.KeyPressMsg](k, m.keys.PinnedUp) && m.pinned.IsActive():
+		m.pinned.ScrollUp(m.pinned.HalfPage())
+		return m, nil, true
+	case key.Matches[tea.KeyPressMsg](k, m.keys.PinnedDown) && m.pinned.IsActive():
+		m.pinned.ScrollDown(m.pinned.HalfPage())
+		return m, nil, true
+	default:
+		return m, nil, false
 	}
+	m.recomputeLayout()
+	m.savePaneSizes()
+	return m, nil, true
 }
 
-func (m Model) handleSessionList(r msg.SessionListResult) (Model, tea.Cmd) {
-	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Session list error: %v", r.Err))
-		return m, nil
+// activePanel returns the resizable panel below the chat: the agents panel
+// while it is shown, otherwise the tasks panel.
+func (m Model) activePanel() dragTarget {
+	switch {
+	case m.base == StateProcessing && m.agents.IsActive():
+		return dragAgents
+	case m.tasks.HasTasks():
+		return dragTasks
 	}
-	if len(r.Sessions) == 0 {
-		m.chat.AddSystemMessage("No sessions found.")
-		return m, nil
+	return dragNone
+}
+
+// resizePanel changes the height cap of panel by delta lines. Growing to the
+// panel's full height switches back to automatic sizing.
+func (m *Model) resizePanel(panel dragTarget, delta int) {
+	var full int
+	var h *int
+	switch panel {
+	case dragTasks:
+		full, h = m.tasks.Height(), &m.config.TasksHeight
+	case dragAgents:
+		full, h = m.agents.Height(), &m.config.AgentsHeight
+	default:
+		return
 	}
-	var sb strings.Builder
-	sb.WriteString("Sessions:\n")
-	for i, s := range r.Sessions {
-		title := s.Title
-		if title == "" {
-			title = "(untitled)"
-		}
-		sb.WriteString(fmt.Sprintf("  %d. %s — %s (%d messages)\n",
-			i+1, shortID(s.ID), title, s.MessageCount,
-		))
+	cur := *h
+	if cur <= 0 || cur > full {
+		cur = full
 	}
-	m.chat.AddSystemMessage(strings.TrimRight(sb.String(), "\n"))
-	return m, nil
+	setPanelHeight(h, full, cur+delta)
 }
 
-func (m Model) handleSessionSwitch(r msg.SessionSwitchResult) (Model, tea.Cmd) {
-	if r.Err != nil {
-		m.chat.AddSystemError(fmt.Sprintf("Session error: %v", r.Err))
-		return m, nil
+// setPanelHeight stores height n for a panel of full lines, clamped to at
+// least 1; n >= full selects automatic sizing.
+func setPanelHeight(h *int, full, n int) {
+	switch {
+	case n >= full:
+		*h = 0
+	case n < 1:
+		*h = 1
+	default:
+		*h = n
 	}
-	m.closeSSE()
-	m.sessionID = r.SessionID
-	m.chat = chat.New(m.layout.ChatWidth, m.layout.ChatHeight)
-	m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
+}
 
-	if len(r.Messages) > 0 {
-		for _, sm := range r.Messages {
-			switch sm.Role {
-			case "user":
-				m.chat.AddUserMessage(sm.Content)
-			case "assistant":
-				m.chat.AddAgentMessage(sm.Content, nil, 0, "")
-			default:
-				m.chat.AddSystemMessage(sm.Content)
-			}
+// dividerAt returns the divider under the mouse, if any.
+func (m Model) dividerAt(mouse tea.Mouse) dragTarget {
+	if w := m.layout.SidebarWidth; w > 0 && (mouse.X == w-1 || mouse.X == w) &&
+		mouse.Y >= m.mainTop() && mouse.Y < m.mainTop()+m.layout.ChatHeight {
+		return dragSidebar
+	}
+	tasksTop := m.mainTop() + m.layout.ChatHeight
+	if m.tasks.HasTasks() {
+		if mouse.Y == tasksTop {
+			return dragTasks
 		}
-		m.chat.AddSystemMessage(fmt.Sprintf(
-			"--- Resumed session %s (%d messages) ---", shortID(r.SessionID), len(r.Messages),
-		))
-	} else {
-		m.chat.AddSystemMessage(fmt.Sprintf("Switched to session %s", shortID(r.SessionID)))
+		tasksTop += m.tasksHeight()
 	}
+	if m.base == StateProcessing && m.agents.IsActive() && mouse.Y == tasksTop {
+		return dragAgents
+	}
+	return dragNone
+}
 
-	var cmds []tea.Cmd
-	cmds = append(cmds, m.input.Focus())
-	if m.program != nil {
-		if cmd := m.startSSE(); cmd != nil {
-			cmds = append(cmds, cmd)
+// mainTop returns the first screen row below the header.
+func (m Model) mainTop() int { return m.layout.HeaderHeight }
+
+// startDrag grabs divider t and records the bottom row of a grabbed panel.
+func (m *Model) startDrag(t dragTarget) {
+	m.drag = t
+	switch t {
+	case dragTasks:
+		m.dragAnchor = m.mainTop() + m.layout.ChatHeight + m.tasksHeight()
+	case dragAgents:
+		m.dragAnchor = m.mainTop() + m.layout.ChatHeight + m.agentsHeight()
+		if m.tasks.HasTasks() {
+			m.dragAnchor += m.tasksHeight()
 		}
 	}
-	return m, tea.Batch(cmds...)
+}
+
+// dragTo moves the grabbed divider to the mouse position. A panel's bottom
+// edge stays put while its top line follows the mouse.
+func (m *Model) dragTo(mouse tea.Mouse) {
+	switch m.drag {
+	case dragSidebar:
+		m.config.SidebarWidth = clampSidebarWidth(mouse.X+1, m.width)
+	case dragTasks:
+		setPanelHeight(&m.config.TasksHeight, m.tasks.Height(), m.dragAnchor-mouse.Y)
+	case dragAgents:
+		setPanelHeight(&m.config.AgentsHeight, m.agents.Height(), m.dragAnchor-mouse.Y)
+	}
+	m.recomputeLayout()
+}
+
+// savePaneSizes persists the pane sizes.
+func (m *Model) savePaneSizes() {
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Pane size applied but could not persist: %v", err))
+	}
 }
 
 // -- Layout helpers -----------------------------------------------------------
 
+// applySize lays the screen out for a terminal of size v and sizes the
+// sub-models that fill it.
+func (m *Model) applySize(v tea.WindowSizeMsg) {
+	m.width = v.Width
+	m.height = v.Height
+	m.plan.SetWidth(v.Width - 4)
+	m.picker.SetWidth(v.Width - 4)
+	m.themes.SetWidth(v.Width - 4)
+	m.input.SetWidth(v.Width)
+	m.header.SetWidth(v.Width)
+	m.permissions.SetSize(v.Width, v.Height)
+	m.sessions.SetSize(v.Width, v.Height)
+	m.quit.SetSize(v.Width, v.Height)
+	m.models.SetSize(v.Width, v.Height)
+	m.onboarding.SetSize(v.Width, v.Height)
+	m.keyManager.SetSize(v.Width, v.Height)
+	m.form.SetSize(v.Width, v.Height)
+	m.scheduler.SetSize(v.Width, v.Height)
+	m.timeline.SetSize(v.Width, v.Height)
+	m.roster.SetSize(v.Width, v.Height)
+	m.swarmWizard.SetSize(v.Width, v.Height)
+	m.memory.SetSize(v.Width, v.Height)
+	m.hooks.SetSize(v.Width, v.Height)
+	m.toolCatalog.SetSize(v.Width, v.Height)
+	m.audit.SetSize(v.Width, v.Height)
+	m.mcp.SetSize(v.Width, v.Height)
+	m.channels.SetSize(v.Width, v.Height)
+	m.budget.SetSize(v.Width, v.Height)
+	m.recomputeLayout()
+}
+
 // recomputeLayout recalculates the Layout struct from current dimensions and
-// sub-model view heights, then propagates updated dimensions into sub-models.
+// sub-model heights, then propagates updated dimensions into sub-models.
 func (m *Model) recomputeLayout() {
-	m.layout = ComputeLayout(
-		m.width, m.height, m.layoutMode,
-		countLines(m.status.View()),
-		countLines(m.tasks.View()),
-		countLines(m.agents.View()),
-	)
+	m.status.SetWidth(m.width)
+	m.layout = m.computeLayout()
 	m.chat.SetSize(m.layout.ChatWidth, m.layout.ChatHeight)
 	m.sidebar.SetSize(m.layout.SidebarWidth, m.layout.SidebarHeight)
+	m.pinned.SetSize(m.layout.PinnedWidth, m.layout.ChatHeight)
+}
+
+// computeLayout returns the layout for the current state. The heights of the
+// sections around the chat come from each component's Height, matching what
+// renderView draws, so no section is rendered just to be measured.
+func (m Model) computeLayout() Layout {
+	input := m.input.Height()
+	switch m.state {
+	case StatePlanReview:
+		input = m.plan.Height()
+	case StateModelPicker:
+		input = m.picker.Height()
+	case StateThemePicker:
+		input = m.themes.Height()
+	}
+	panels := m.tasksHeight()
+	if m.base == StateProcessing && m.agents.IsActive() {
+		panels += m.agentsHeight()
+	}
+	if m.frames.overlay {
+		panels++
+	}
+	return ComputeLayout(
+		m.width, m.height, m.layoutMode, m.config.SidebarWidth, m.pinned.IsActive(),
+		m.status.Height(), input, panels,
+	)
 }
 
-// countLines returns the number of lines in a rendered string.
-func countLines(s string) int {
-	if s == "" {
-		return 0
+// panelHeight is the height of a panel of h lines once clampPanel caps it at
+// maxLines.
+func panelHeight(h, maxLines int) int {
+	if maxLines > 0 {
+		return min(h, maxLines)
 	}
-	return strings.Count(s, "\n") + 1
+	return h
 }
 
 // -- Rendering helpers --------------------------------------------------------
@@ -2067,7 +6130,7 @@ const osaLogo = ` ██████╗ ███████╗ █████
 // Falls back to staticHelpText when no commands have been fetched.
 func (m Model) dynamicHelpText() string {
 	if len(m.commandEntries) == 0 {
-		return staticHelpText()
+		return staticHelpText(m.config.Aliases)
 	}
 
 	categoryOrder := []string{
@@ -2096,14 +6159,14 @@ func (m Model) dynamicHelpText() string {
 	}
 
 	var b strings.Builder
-	b.WriteString("Available commands:\n")
+	b.WriteString(i18n.T("Available commands:") + "\n")
 
 	for _, cat := range categoryOrder {
 		cmds, ok := groups[cat]
 		if !ok || len(cmds) == 0 {
 			continue
 		}
-		label := categoryLabels[cat]
+		label := i18n.T(categoryLabels[cat])
 		if label == "" {
 			label = cat
 		}
@@ -2130,57 +6193,176 @@ func (m Model) dynamicHelpText() string {
 		}
 	}
 
+	b.WriteString(aliasHelp(m.config.Aliases))
 	b.WriteString(keybindingsHelp())
 	return b.String()
 }
 
-func staticHelpText() string {
-	return `Commands:
-  /help          Show this help
-  /status        System status
-  /models        Browse & switch models (↑↓ picker)
-  /model         Show current model
-  /model <name>  Switch to model (e.g. /model qwen3:8b)
-  /agents        List agent roster
-  /tools         List available tools
-  /sessions      List all sessions
-  /session       Show current session
-  /session new   Create new session
-  /session <id>  Switch to session
-  /bg            List background tasks
-  /theme         List or switch themes
-  /clear         Clear chat history
-  /exit          Exit OSA
-` + keybindingsHelp()
+// helpCommands lists the built-in slash commands shown by /help.
+var helpCommands = [][2]string{
+	{"/help", "Show this help"},
+	{"/status", "System status"},
+	{"/models", "Browse & switch models (↑↓ picker)"},
+	{"/keys", "Manage provider API keys"},
+	{"/model", "Show current model"},
+	{"/model <name>", "Switch to model (e.g. /model qwen3:8b)"},
+	{"/model pin", "Pin current (or given) model to this session"},
+	{"/model unpin", "Use the default model in this session again"},
+	{"/tools [name]", "Browse tools and their parameters; space disables one for this session"},
+	{"/audit [filter]", "List this session's tool calls; ctrl+e exports them as CSV"},
+	{"/audit export [path]", "Write this session's tool calls as CSV"},
+	{"/sessions", "List all sessions"},
+	{"/sessions cleanup", "Archive or delete old and short sessions, after a preview"},
+	{"/sessions cleanup auto", "Archive old or short sessions on connecting"},
+	{"/session", "Show current session"},
+	{"/session new", "Create new session"},
+	{"/session <id>", "Switch to session"},
+	{"/agents", "Browse agent roles; Enter starts a prompt with @name"},
+	{"/swarm new [task]", "Launch a swarm: pattern, agents, task and time budget"},
+	{"/memory [query]", "Browse memories: view, edit, delete or pin them"},
+	{"/hooks", "Show the hook pipeline and recent blocks; space toggles a hook"},
+	{"/mcp", "Manage MCP servers: add, remove, restart, see their tools"},
+	{"/channels", "Set channel tokens, enable or disable channels, test them"},
+	{"/budget", "Spend against the session, daily and monthly limits; set them"},
+	{"/system <text>", "Add <text> to the system prompt in this session"},
+	{"/system clear", "Drop this session's system prompt addition"},
+	{"/env KEY=VALUE", "Set a variable for this session's tools (KEY= unsets)"},
+	{"/env clear", "Drop this session's variables"},
+	{"/prefs", "Set reply language, verbosity and code comments for all sessions"},
+	{"/prefs clear", "Drop the reply preferences"},
+	{"/bg", "List background tasks"},
+	{"/notifications", "List dismissed notifications"},
+	{"/model pull <name>", "Pull an Ollama model, with progress in a toast"},
+	{"/theme", "Preview and switch themes"},
+	{"/lang", "List or switch interface language"},
+	{"/profile", "List profiles"},
+	{"/profile switch", "Switch to another profile and reconnect"},
+	{"/open <n|path>", "Open sidebar file n (or a path) in $EDITOR"},
+	{"/pin <n|path>", "Pin a file beside the chat"},
+	{"/pin diff", "Pin the workspace git diff"},
+	{"/pin plan", "Pin the latest plan"},
+	{"/pin tool", "Pin the latest tool output"},
+	{"/pin off", "Close the pinned pane"},
+	{"/prompts", "List prompt templates"},
+	{"/prompts use", "Insert template <name|n>, filling its {{placeholders}}"},
+	{"/prompts save", "Save <name> from the given text or your last prompt"},
+	{"/prompts delete", "Delete template <name>"},
+	{"/prompts import", "Add a template file <path> to the library"},
+	{"/prompts export", "Write template <name> to <path> for sharing"},
+	{"/schedule", "Manage scheduled prompts and upcoming runs"},
+	{"/retry", "Resubmit the selected or latest prompt"},
+	{"/retry pick", "Retry with a model chosen from the list"},
+	{"/retry <p/model>", "Retry once with another provider/model"},
+	{"/feedback up|down", "Rate the selected or latest reply; down asks what was wrong"},
+	{"/apply [n]", "Show the diff of the files the selected or latest reply suggests, and write them"},
+	{"/compact", "Compact the conversation now (stats: compaction statistics)"},
+	{"/export [path]", "Write the conversation as Markdown (--notices: with system notices)"},
+	{"/notices", "Show or hide system notices in the chat"},
+	{"/queue", "List prompts queued while the agent works"},
+	{"/queue clear", "Drop all queued prompts (or drop <n> for one)"},
+	{"/queue send", "Resume a queue paused by an error"},
+	{`/schedule "when"`, "Run <prompt> on a schedule, e.g. \"every weekday 9am\""},
+	{"/reveal", "Reveal or mask API keys and tokens in the chat"},
+	{"/paste-image", "Attach the clipboard image to the next prompt"},
+	{"/timeline", "Show message density over time and jump to a message"},
+	{"/timestamps", "Cycle message times: relative, absolute, off"},
+	{"/speak [on|off]", "Read agent replies aloud as they stream (toggles)"},
+	{"/speak stop", "Cut off the reply being read"},
+	{"/doctor", "Check backend, auth, sidecars, terminal and config"},
+	{"/stats", "Show update and render timing; /stats overlay keeps it on screen"},
+	{"/clear", "Clear chat history"},
+	{"/exit", "Exit OSA"},
+}
+
+// helpKeys lists the keybindings shown by /help and F1.
+var helpKeys = [][2]string{
+	{"Enter", "Submit message"},
+	{"Alt+Enter", "Insert newline (multi-line input)"},
+	{"Ctrl+C", "Cancel / quit"},
+	{"Ctrl+S", "While working: cancel and send the input now"},
+	{"Ctrl+L", "Toggle sidebar"},
+	{"Ctrl+←/→", "Narrow/widen sidebar"},
+	{"Ctrl+↑/↓", "Grow/shrink tasks or agents panel"},
+	{"Alt+↑/↓", "Scroll pinned pane"},
+	{"Shift+↑/↓", "Select a message (Esc clears)"},
+	{"Ctrl+R", "Reply to selected or latest message"},
+	{"Alt+R", "Retry the selected or latest prompt"},
+	{"+ / -", "Rate the selected reply helpful or wrong"},
+	{"a", "Apply the files the selected reply suggests (when input is empty)"},
+	{"r", "Retry a failed prompt (when input is empty)"},
+	{"Alt+V", "Reveal/mask secrets in the chat"},
+	{"Alt+T", "Session timeline"},
+	{"Alt+N", "Show or hide system notices"},
+	{"Ctrl+O", "Expand/collapse details; when idle, the tool calls of an answer; with a paste chip, its preview"},
+	{"Enter", "With an empty input: expand/collapse the selected or latest long answer"},
+	{"Ctrl+T", "Toggle thinking box or an answer's thinking"},
+	{"Alt+P", "Show the selected or latest answer as raw text or rendered"},
+	{"Ctrl+B", "Move task to background"},
+	{"Ctrl+K", "Command palette"},
+	{"Alt+.", "Repeat the last slash command"},
+	{"Alt+1..3", "Run an action of the latest notification"},
+	{"Ctrl+N", "New session"},
+	{"Alt+M", "Cycle favorite models (this session)"},
+	{"Ctrl+U", "Clear input"},
+	{"F1", "Show this help"},
+	{"Home", "Scroll to top"},
+	{"g g / g e", "Scroll to top/bottom (when input is empty)"},
+	{"Space …", "Leader keys (when input is empty): s n new session, s l sessions, m models, t theme, h notifications"},
+	{"End", "Scroll to bottom"},
+	{"PgUp/PgDn", "Scroll chat history"},
+	{"j/k", "Scroll (when input not focused)"},
+	{"u/d", "Half-page scroll (when input not focused)"},
+	{"Tab", "Autocomplete commands"},
+	{"Tab/Enter", "Choose/run an action under an error"},
+	{"Up/Down", "Navigate input history"},
+}
+
+// HelpEntry is a built-in slash command or keybinding with its untranslated
+// description, exported for shell tooling such as the man page generator.
+type HelpEntry struct{ Name, Description string }
+
+// SlashCommands returns the built-in slash commands in /help order.
+func SlashCommands() []HelpEntry { return helpEntries(helpCommands) }
+
+// KeyBindings returns the keybindings in /help order.
+func KeyBindings() []HelpEntry { return helpEntries(helpKeys) }
+
+func helpEntries(rows [][2]string) []HelpEntry {
+	out := make([]HelpEntry, len(rows))
+	for i, r := range rows {
+		out[i] = HelpEntry{Name: r[0], Description: r[1]}
+	}
+	return out
+}
+
+// helpTips lists the tips appended to the keybindings help.
+var helpTips = []string{
+	"Use Alt+Enter to compose multi-line messages",
+	"Ctrl+B moves a running task to background",
+	"Ctrl+L toggles the sidebar panel",
+	"/sessions lists sessions; /session <id> to switch",
+}
+
+func staticHelpText(aliases map[string]string) string {
+	var b strings.Builder
+	b.WriteString(i18n.T("Commands:") + "\n")
+	for _, c := range helpCommands {
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", c[0], i18n.T(c[1])))
+	}
+	return b.String() + aliasHelp(aliases) + keybindingsHelp()
 }
 
 func keybindingsHelp() string {
-	return `
-Keybindings:
-  Enter        Submit message
-  Alt+Enter    Insert newline (multi-line input)
-  Ctrl+C       Cancel / quit
-  Ctrl+L       Toggle sidebar
-  Ctrl+O       Expand/collapse details
-  Ctrl+T       Toggle thinking box
-  Ctrl+B       Move task to background
-  Ctrl+K       Command palette
-  Ctrl+N       New session
-  Ctrl+U       Clear input
-  F1           Show this help
-  Home         Scroll to top
-  End          Scroll to bottom
-  PgUp/PgDn    Scroll chat history
-  j/k          Scroll (when input not focused)
-  u/d          Half-page scroll (when input not focused)
-  Tab          Autocomplete commands
-  Up/Down      Navigate input history
-
-Tips:
-  · Use Alt+Enter to compose multi-line messages
-  · Ctrl+B moves a running task to background
-  · Ctrl+L toggles the sidebar panel
-  · /sessions lists sessions; /session <id> to switch`
+	var b strings.Builder
+	b.WriteString("\n" + i18n.T("Keybindings:") + "\n")
+	for _, k := range helpKeys {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", i18n.T(k[0]), i18n.T(k[1])))
+	}
+	b.WriteString("\n" + i18n.T("Tips:"))
+	for _, t := range helpTips {
+		b.WriteString("\n  · " + i18n.T(t))
+	}
+	return b.String()
 }
 
 // -- New dialog key handlers (Wave 4) -----------------------------------------
@@ -2200,18 +6382,28 @@ func (m Model) handleQuitKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleSessionsKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	// Esc with no action — dismiss and return to idle.
 	if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.closeModal(StateSessions)
+		return m, m.focusInput()
 	}
 	var cmd tea.Cmd
 	m.sessions, cmd = m.sessions.Update(k)
 	return m, cmd
 }
 
+func (m Model) handleKeysKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) && !m.keyManager.Busy() {
+		m.closeModal(StateKeys)
+		return m, m.focusInput()
+	}
+	var cmd tea.Cmd
+	m.keyManager, cmd = m.keyManager.Update(k)
+	return m, cmd
+}
+
 func (m Model) handleModelsKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	if key.Matches[tea.KeyPressMsg](k, m.keys.Escape) {
-		m.state = StateIdle
-		return m, m.input.Focus()
+		m.closeModal(StateModels)
+		return m, m.focusInput()
 	}
 	var cmd tea.Cmd
 	m.models, cmd = m.models.Update(k)
@@ -2221,37 +6413,42 @@ func (m Model) handleModelsKey(k tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 // -- New dialog decision handlers (Wave 4) ------------------------------------
 
 func (m Model) handlePermissionDecision(d dialog.PermissionDecision) (Model, tea.Cmd) {
-	m.state = StateIdle
+	m.closeModal(StatePermissions)
 	switch d.Decision {
 	case "allow", "allow_session":
-		m.chat.AddSystemMessage(fmt.Sprintf("Allowed: %s", d.ToolCallID))
+		m.chat.AddSystemMessage(i18n.T("Allowed: %s", d.ToolCallID))
 	case "deny":
-		m.chat.AddSystemWarning(fmt.Sprintf("Denied tool: %s", d.ToolCallID))
+		m.chat.AddSystemWarning(i18n.T("Denied tool: %s", d.ToolCallID))
 	}
-	return m, m.input.Focus()
+	return m, m.focusInput()
 }
 
 func (m Model) handleSessionAction(a dialog.SessionAction) (Model, tea.Cmd) {
-	m.state = StateIdle
+	m.closeModal(StateSessions)
 	switch a.Action {
 	case "switch":
-		return m, tea.Batch(m.input.Focus(), m.switchSession(a.SessionID))
+		return m, tea.Batch(m.focusInput(), m.switchSession(a.SessionID))
 	case "create":
-		return m, tea.Batch(m.input.Focus(), m.createSession())
+		return m, tea.Batch(m.focusInput(), m.createSession())
 	case "rename":
-		m.chat.AddSystemMessage(fmt.Sprintf("Renamed session %s → %s", shortID(a.SessionID), a.NewName))
-		return m, m.input.Focus()
+		m.chat.AddSystemMessage(i18n.T("Renamed session %s → %s", shortID(a.SessionID), a.NewName))
+		return m, m.focusInput()
 	case "delete":
-		m.chat.AddSystemMessage(fmt.Sprintf("Deleted session %s", shortID(a.SessionID)))
-		return m, m.input.Focus()
+		m.chat.AddSystemMessage(i18n.T("Deleted session %s", shortID(a.SessionID)))
+		return m, m.focusInput()
 	}
-	return m, m.input.Focus()
+	return m, m.focusInput()
 }
 
 func (m Model) handleModelsChoice(c dialog.ModelChoice) (Model, tea.Cmd) {
-	m.state = StateIdle
-	m.chat.AddSystemMessage(fmt.Sprintf("Switching to %s / %s...", c.Provider, c.Model))
-	return m, tea.Batch(m.input.Focus(), m.switchModel(c.Provider, c.Model))
+	m.closeModal(StateModels)
+	if t := m.retryPending; t != nil {
+		m.retryPending = nil
+		m, cmd := m.retry(*t, c.Provider, c.Model)
+		return m, tea.Batch(m.focusInput(), cmd)
+	}
+	m.chat.AddSystemMessage(i18n.T("Switching to %s / %s...", c.Provider, c.Model))
+	return m, tea.Batch(m.focusInput(), m.switchModel(c.Provider, c.Model))
 }
 
 // -- Signal conversion helpers ------------------------------------------------
@@ -2296,6 +6493,21 @@ func shortID(id string) string {
 	return id
 }
 
+// isCurrent reports whether id is the request awaiting an answer, so a
+// delivery for it should be shown. Cancelled and superseded requests are not.
+func (m Model) isCurrent(id string) bool {
+	return id != "" && id == m.requestID
+}
+
+// newRequestID returns a random ID for an orchestrate request.
+func newRequestID() string {
+	b := make([]byte, 6)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return fmt.Sprintf("req_%d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("req_%x", b)
+}
+
 // generateSessionID creates a time-based session ID with random suffix.
 func generateSessionID(randBytes []byte) string {
 	return fmt.Sprintf("tui_%d_%x", time.Now().UnixNano(), randBytes)
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// Every tool call that finishes in a session, by its agent or a sub-agent
+// of an orchestrated task, goes into the session's audit log, with when it
+// started, the summary of its arguments, how long it took and whether it
+// succeeded. /audit lists the log in a dialog that filters as one types;
+// ctrl+e there, or /audit export, writes it as CSV for documenting what the
+// agent did on the machine. The log holds the calls seen since the TUI
+// started, as the backend keeps no history of them.
+
+// maxAuditEntries is the most calls kept per session; the oldest go first.
+const maxAuditEntries = 10000
+
+// auditHeader is the header row of an exported audit log.
+var auditHeader = []string{"time", "agent", "tool", "arguments", "duration_ms", "success"}
+
+// recordAudit adds a finished tool call of the current session to its log.
+func (m *Model) recordAudit(agent, tool, args string, durationMs int64, success bool) {
+	if m.auditLog == nil {
+		m.auditLog = make(map[string][]dialog.AuditEntry)
+	}
+	e := dialog.AuditEntry{
+		Time:       time.Now().Add(-time.Duration(durationMs) * time.Millisecond),
+		Agent:      agent,
+		Tool:       tool,
+		Args:       args,
+		DurationMs: durationMs,
+		Success:    success,
+	}
+	// Calls finish out of order; keep the log in the order they started.
+	calls := m.auditLog[m.sessionID]
+	i := len(calls)
+	for i > 0 && calls[i-1].Time.After(e.Time) {
+		i--
+	}
+	calls = slices.Insert(calls, i, e)
+	if len(calls) > maxAuditEntries {
+		calls = calls[len(calls)-maxAuditEntries:]
+	}
+	m.auditLog[m.sessionID] = calls
+}
+
+// handleAgentToolCall logs a tool call of a sub-agent. It is not part of the
+// agent's own activity, so nothing else shows it.
+func (m Model) handleAgentToolCall(v client.AgentToolCallEvent) (Model, tea.Cmd) {
+	m.recordAudit(v.AgentName, v.Name, v.Args, v.DurationMs, v.Success)
+	return m, nil
+}
+
+// handleAuditCommand implements /audit [filter], which opens the log of the
+// session, and /audit export [path], which writes all of it as CSV.
+func (m Model) handleAuditCommand(arg string) (Model, tea.Cmd) {
+	entries := m.auditLog[m.sessionID]
+	if sub, path, _ := strings.Cut(arg, " "); sub == "export" {
+		return m.exportAudit(entries, strings.TrimSpace(path))
+	}
+	m.audit.SetEntries(slices.Clone(entries), arg)
+	m.audit.SetSize(m.width, m.height)
+	m.pushModal(StateAudit)
+	return m, nil
+}
+
+// exportAudit writes entries as CSV to path, by default a dated file in the
+// working directory.
+func (m Model) exportAudit(entries []dialog.AuditEntry, path string) (Model, tea.Cmd) {
+	if len(entries) == 0 {
+		m.chat.AddSystemMessage(i18n.T("No tool calls to export."))
+		return m, nil
+	}
+	if path == "" {
+		path = "osa-audit-" + time.Now().Format("2006-01-02-1504") + ".csv"
+	}
+	if err := writeAuditCSV(path, entries); err != nil {
+		m.chat.AddSystemError(i18n.T("Export failed: %v", err))
+		return m, nil
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	m.toasts.Add(i18n.T("Exported %d tool calls to %s", len(entries), path), toast.ToastInfo)
+	return m, m.tickCmd()
+}
+
+// writeAuditCSV writes entries to path, one row per call after the header.
+func writeAuditCSV(path string, entries []dialog.AuditEntry) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(auditHeader)
+	for _, e := range entries {
+		agent := e.Agent
+		if agent == "" {
+			agent = "main"
+		}
+		_ = w.Write([]string{
+			e.Time.Format(time.RFC3339),
+			agent,
+			e.Tool,
+			e.Args,
+			strconv.FormatInt(e.DurationMs, 10),
+			strconv.FormatBool(e.Success),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/sidebar"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// Spend and limits are fetched on connect and after turns for the status
+// bar's budget segment and the sidebar's budget bars. /budget shows them in
+// a dialog where the session, daily, monthly and per-call limits can be
+// changed; the backend keeps them until it restarts. A backend without
+// GET /budget reports its budget with its analytics, and /budget runs its
+// command instead.
+
+// budgetLoaded carries spend and limits; budget is nil when the backend
+// reported none. open is set when /budget asked for it, and err is only
+// kept then.
+type budgetLoaded struct {
+	budget     *client.Budget
+	spendKnown bool // the backend reported today's spend
+	open       bool
+	err        error
+}
+
+// budgetSaved reports the outcome of changing a limit.
+type budgetSaved struct {
+	limit dialog.BudgetLimit
+	err   error
+}
+
+func (m Model) fetchBudget(open bool) tea.Cmd {
+	c, sessionID := m.client, m.sessionID
+	return func() tea.Msg {
+		b, err := c.GetBudget(sessionID)
+		switch {
+		case err == nil:
+			return budgetLoaded{budget: b, spendKnown: true, open: open}
+		case open:
+			return budgetLoaded{err: err, open: open}
+		}
+		a, err := c.GetAnalytics()
+		if err != nil || a == nil {
+			return budgetLoaded{}
+		}
+		num := func(k string) float64 {
+			f, _ := a.Budget[k].(float64)
+			return f
+		}
+		return budgetLoaded{
+			budget: &client.Budget{
+				DailySpent:   num("daily_spent"),
+				DailyLimit:   num("daily_limit"),
+				MonthlySpent: num("monthly_spent"),
+				MonthlyLimit: num("monthly_limit"),
+			},
+			spendKnown: a.Budget["daily_spent"] != nil,
+		}
+	}
+}
+
+// openBudget implements /budget.
+func (m Model) openBudget() (Model, tea.Cmd) {
+	m.budget.Reset()
+	m.toasts.Add(i18n.T("Loading budget..."), toast.ToastInfo)
+	return m, tea.Batch(m.fetchBudget(true), m.tickCmd())
+}
+
+// budgetShowAt is the utilisation from which the budget segment shows, the
+// backend's budget_warning threshold.
+const budgetShowAt = 0.8
+
+// handleBudgetLoaded shows the tightest budget in the status bar once it
+// passes budgetShowAt, hiding the segment after the spend was reset, and
+// updates the sidebar and, for /budget, the dialog.
+func (m Model) handleBudgetLoaded(r budgetLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if errors.Is(r.err, client.ErrNotSupported) {
+			return m, m.executeCommand("budget", "")
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load budget: %v", r.err))
+		return m, nil
+	}
+	b := r.budget
+	if b == nil {
+		return m, nil
+	}
+	if r.spendKnown {
+		m.status.SetSpend(b.DailySpent)
+	}
+	if b.DailyLimit <= 0 && b.MonthlyLimit <= 0 {
+		return m, nil
+	}
+	bars := budgetBars(*b)
+	m.status.ClearBudget()
+	for _, s := range bars {
+		if s.Limit > 0 && s.Spent/s.Limit >= budgetShowAt {
+			m.status.SetBudget(s.Period, s.Spent, s.Limit)
+		}
+	}
+	m.sidebar.SetBudget(bars)
+	m.recomputeLayout()
+	if !r.open && !m.hasModal(StateBudget) {
+		return m, nil
+	}
+	m.budget.SetEntries(budgetEntries(*b))
+	m.budget.SetSize(m.width, m.height)
+	if !m.hasModal(StateBudget) {
+		m.pushModal(StateBudget)
+	}
+	return m, nil
+}
+
+func (m Model) setBudgetLimit(a dialog.BudgetLimit) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		var limit *float64
+		if a.Limit > 0 {
+			limit = &a.Limit
+		}
+		_, err := c.SetBudgetLimits(map[string]*float64{a.Key: limit})
+		return budgetSaved{limit: a, err: err}
+	}
+}
+
+// handleBudgetSaved reports the change in the dialog and reloads the budget,
+// with the session's spend, for the dialog, status bar and sidebar.
+func (m Model) handleBudgetSaved(r budgetSaved) (Model, tea.Cmd) {
+	name := strings.ReplaceAll(strings.TrimSuffix(r.limit.Key, "_limit"), "_", "-")
+	switch {
+	case r.err != nil:
+		m.budget.SetStatus(fmt.Sprintf("%s: %v", name, r.err), true)
+		return m, nil
+	case r.limit.Limit == 0:
+		m.budget.SetStatus(i18n.T("Removed the %s limit", name), false)
+	default:
+		m.budget.SetStatus(i18n.T("Set the %s limit to $%.2f", name, r.limit.Limit), false)
+	}
+	return m, m.fetchBudget(false)
+}
+
+// budgetBars lists spend against the session, daily and monthly limits.
+func budgetBars(b client.Budget) []sidebar.BudgetStatus {
+	bars := []sidebar.BudgetStatus{
+		{Period: "daily", Spent: b.DailySpent, Limit: b.DailyLimit},
+		{Period: "monthly", Spent: b.MonthlySpent, Limit: b.MonthlyLimit},
+	}
+	if b.SessionLimit != nil {
+		bars = append([]sidebar.BudgetStatus{{Period: "session", Spent: b.SessionSpent, Limit: *b.SessionLimit}}, bars...)
+	}
+	return bars
+}
+
+func budgetEntries(b client.Budget) []dialog.BudgetEntry {
+	var sessionLimit float64
+	if b.SessionLimit != nil {
+		sessionLimit = *b.SessionLimit
+	}
+	return []dialog.BudgetEntry{
+		{Key: "session_limit", Label: i18n.T("Session"), Spent: b.SessionSpent, HasSpend: true, Limit: sessionLimit, Optional: true},
+		{Key: "daily_limit", Label: i18n.T("Daily"), Spent: b.DailySpent, HasSpend: true, Limit: b.DailyLimit},
+		{Key: "monthly_limit", Label: i18n.T("Monthly"), Spent: b.MonthlySpent, HasSpend: true, Limit: b.MonthlyLimit},
+		{Key: "per_call_limit", Label: i18n.T("Per call"), Limit: b.PerCallLimit},
+	}
+}
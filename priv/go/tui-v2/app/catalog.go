@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// The tool catalog is fetched on connect, for the header's tool count, and
+// again by /tools, which lists it in a dialog with each tool's parameters
+// and how often it ran. Space disables a tool for the current session: the
+// session override keeps the disabled names, and orchestrate requests carry
+// the remaining tools as allowed_tools.
+
+// toolsLoaded carries the tool catalog; open is set when /tools asked for
+// it, with filter as the text given after the command.
+type toolsLoaded struct {
+	tools  []client.ToolEntry
+	err    error
+	open   bool
+	filter string
+}
+
+func (m Model) fetchTools(open bool, filter string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		tools, err := c.ListTools()
+		return toolsLoaded{tools: tools, err: err, open: open, filter: filter}
+	}
+}
+
+// handleToolsLoaded stores the catalog and, for /tools, opens the dialog.
+func (m Model) handleToolsLoaded(r toolsLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if r.open {
+			m.chat.AddSystemError(i18n.T("Failed to load tools: %v", r.err))
+		}
+		return m, nil
+	}
+	m.toolEntries = r.tools
+	m.header.SetToolCount(len(r.tools))
+	m.chat.SetWelcomeData(m.header.Version(), m.header.WelcomeLine(), m.header.Workspace())
+	if !r.open {
+		return m, nil
+	}
+	if len(r.tools) == 0 {
+		m.chat.AddSystemMessage(i18n.T("The backend reports no tools."))
+		return m, nil
+	}
+	disabled := m.sessionOverride().DisabledTools
+	entries := make([]dialog.ToolCatalogEntry, len(r.tools))
+	for i, t := range r.tools {
+		entries[i] = dialog.ToolCatalogEntry{
+			Name:        t.Name,
+			Description: t.Description,
+			Params:      toolParams(t.Parameters),
+			Usage:       m.toolUsage[t.Name],
+			Enabled:     !slices.Contains(disabled, t.Name),
+		}
+	}
+	m.toolCatalog.SetEntries(entries, r.filter)
+	m.toolCatalog.SetSize(m.width, m.height)
+	m.pushModal(StateTools)
+	return m, nil
+}
+
+// handleToolToggle enables or disables a tool for the current session.
+func (m Model) handleToolToggle(t dialog.ToolToggle) (Model, tea.Cmd) {
+	o := m.sessionOverride()
+	disabled := slices.DeleteFunc(slices.Clone(o.DisabledTools), func(n string) bool { return n == t.Name })
+	if !t.Enabled {
+		disabled = append(disabled, t.Name)
+		slices.Sort(disabled)
+	}
+	if len(disabled) == 0 {
+		disabled = nil
+	}
+	o.DisabledTools = disabled
+	m.setSessionOverride(o)
+	m.toolCatalog.SetEnabled(t.Name, t.Enabled)
+	if t.Enabled {
+		m.toasts.Add(i18n.T("%s enabled for this session", t.Name), toast.ToastInfo)
+	} else {
+		m.toasts.Add(i18n.T("%s disabled for this session", t.Name), toast.ToastInfo)
+	}
+	return m, m.tickCmd()
+}
+
+// allowedTools is the allowlist sent with orchestrate requests: the catalog
+// without the tools disabled in this session. It is nil when none are
+// disabled or the catalog is not known yet, and empty when all are.
+func (m Model) allowedTools() *[]string {
+	disabled := m.sessionOverride().DisabledTools
+	if len(disabled) == 0 || len(m.toolEntries) == 0 {
+		return nil
+	}
+	allowed := make([]string, 0, len(m.toolEntries))
+	for _, t := range m.toolEntries {
+		if !slices.Contains(disabled, t.Name) {
+			allowed = append(allowed, t.Name)
+		}
+	}
+	return &allowed
+}
+
+// recordToolUse counts a finished tool call for the catalog.
+func (m *Model) recordToolUse(v client.ToolCallEndEvent) {
+	if m.toolUsage == nil {
+		m.toolUsage = make(map[string]dialog.ToolUsage)
+	}
+	u := m.toolUsage[v.Name]
+	u.Calls++
+	if !v.Success {
+		u.Failures++
+	}
+	u.TotalMs += v.DurationMs
+	u.Last = time.Now()
+	m.toolUsage[v.Name] = u
+}
+
+// toolParams lists a tool's arguments, required ones first, each group by
+// name.
+func toolParams(p client.ToolParameters) []dialog.ToolParam {
+	params := make([]dialog.ToolParam, 0, len(p.Properties))
+	for name, prop := range p.Properties {
+		var typ string
+		switch t := prop.Type.(type) {
+		case string:
+			typ = t
+		case []any:
+			types := make([]string, len(t))
+			for i, v := range t {
+				types[i] = fmt.Sprint(v)
+			}
+			typ = strings.Join(types, "|")
+		}
+		params = append(params, dialog.ToolParam{
+			Name:        name,
+			Type:        typ,
+			Description: prop.Description,
+			Required:    slices.Contains(p.Required, name),
+		})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].Required != params[j].Required {
+			return params[i].Required
+		}
+		return params[i].Name < params[j].Name
+	})
+	return params
+}
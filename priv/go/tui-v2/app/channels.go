@@ -0,0 +1,139 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /channels manages the backend's messaging channels after onboarding:
+// each channel's tokens and other settings, typed masked when secret,
+// enabling and disabling it, and testing its connection. Settings are saved
+// in the backend's config. A backend that lists channels without their
+// settings gets its /channels command instead.
+
+// channelsLoaded carries the channels for the channels dialog; refresh is
+// set when it updates a dialog that may have been closed since.
+type channelsLoaded struct {
+	channels []client.Channel
+	err      error
+	refresh  bool
+}
+
+// channelActionDone reports the outcome of a channel action: the channel's
+// new state after a save, enable or disable, or the result of a test.
+type channelActionDone struct {
+	action  dialog.ChannelAction
+	channel *client.Channel
+	test    *client.ChannelTest
+	err     error
+}
+
+func (m Model) fetchChannels(refresh bool) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		channels, err := c.ListChannels()
+		return channelsLoaded{channels: channels, err: err, refresh: refresh}
+	}
+}
+
+// openChannels implements /channels.
+func (m Model) openChannels() (Model, tea.Cmd) {
+	m.channels.Reset()
+	m.toasts.Add(i18n.T("Loading channels..."), toast.ToastInfo)
+	return m, tea.Batch(m.fetchChannels(false), m.tickCmd())
+}
+
+// handleChannelsLoaded opens the channels dialog on the loaded channels.
+func (m Model) handleChannelsLoaded(r channelsLoaded) (Model, tea.Cmd) {
+	if r.refresh && (r.err != nil || !m.hasModal(StateChannels)) {
+		return m, nil
+	}
+	if r.err != nil {
+		if errors.Is(r.err, client.ErrNotSupported) {
+			return m, m.executeCommand("channels", "")
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load channels: %v", r.err))
+		return m, nil
+	}
+	entries := make([]dialog.ChannelEntry, 0, len(r.channels))
+	for _, ch := range r.channels {
+		if ch.Fields != nil {
+			entries = append(entries, channelEntry(ch))
+		}
+	}
+	if len(entries) == 0 && len(r.channels) > 0 {
+		return m, m.executeCommand("channels", "")
+	}
+	m.channels.SetEntries(entries)
+	m.channels.SetSize(m.width, m.height)
+	if !m.hasModal(StateChannels) {
+		m.pushModal(StateChannels)
+	}
+	return m, nil
+}
+
+func (m Model) applyChannelAction(a dialog.ChannelAction) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		r := channelActionDone{action: a}
+		switch a.Action {
+		case "save":
+			r.channel, r.err = c.UpdateChannel(a.Name, client.ChannelUpdate{Fields: map[string]string{a.Field: a.Value}})
+		case "enable", "disable":
+			enabled := a.Action == "enable"
+			r.channel, r.err = c.UpdateChannel(a.Name, client.ChannelUpdate{Enabled: &enabled})
+		case "test":
+			r.test, r.err = c.TestChannel(a.Name)
+		}
+		return r
+	}
+}
+
+// handleChannelActionDone shows the outcome in the channels dialog.
+func (m Model) handleChannelActionDone(r channelActionDone) (Model, tea.Cmd) {
+	a := r.action
+	switch {
+	case r.err != nil:
+		m.channels.SetStatus(fmt.Sprintf("%s: %v", a.Name, r.err), true)
+		// A failed start still saves the setting; show what was stored.
+		return m, m.fetchChannels(true)
+	case a.Action == "test" && r.test.OK:
+		m.channels.SetStatus(i18n.T("%s is connected", a.Name), false)
+	case a.Action == "test":
+		m.channels.SetStatus(fmt.Sprintf("%s: %s", a.Name, strings.ReplaceAll(r.test.Status, "_", " ")), true)
+	default:
+		m.channels.Replace(channelEntry(*r.channel))
+		switch {
+		case a.Action == "save" && a.Value == "":
+			m.channels.SetStatus(i18n.T("Cleared %s", a.Field), false)
+		case a.Action == "save":
+			m.channels.SetStatus(i18n.T("Saved %s", a.Field), false)
+		case a.Action == "enable":
+			m.channels.SetStatus(i18n.T("Enabled %s", a.Name), false)
+		default:
+			m.channels.SetStatus(i18n.T("Disabled %s; it stays off after a restart", a.Name), false)
+		}
+	}
+	return m, nil
+}
+
+func channelEntry(ch client.Channel) dialog.ChannelEntry {
+	fields := make([]dialog.ChannelField, len(ch.Fields))
+	for i, f := range ch.Fields {
+		fields[i] = dialog.ChannelField{Key: f.Key, Secret: f.Secret, Set: f.Set, Value: f.Value}
+	}
+	return dialog.ChannelEntry{
+		Name:       ch.Name,
+		Connected:  ch.Connected,
+		Configured: ch.Configured,
+		Enabled:    ch.Enabled,
+		Fields:     fields,
+	}
+}
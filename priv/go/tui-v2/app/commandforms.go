@@ -0,0 +1,113 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// Backend commands that take several values can declare forms in their
+// metadata (GET /api/v1/commands): running the command with the argument a
+// form is for, such as /cron add, opens the form instead, with a field per
+// value. Required fields and fields with a pattern are checked as the form
+// is submitted, and secret fields are masked. The values are sent by name
+// as the fields of POST /api/v1/commands/execute, so they need no quoting
+// or separators on one line.
+
+// commandFormPrefix starts the form ID of a backend command's form.
+const commandFormPrefix = "command:"
+
+// commandFormRun is the backend command a form was opened for.
+type commandFormRun struct {
+	cmd  string
+	arg  string
+	form client.CommandForm
+}
+
+// commandForm returns the form backend command cmd declares for arg.
+func (m Model) commandForm(cmd, arg string) (client.CommandForm, bool) {
+	arg = strings.TrimSpace(arg)
+	for _, e := range m.commandEntries {
+		if e.Name != cmd {
+			continue
+		}
+		for _, f := range e.Forms {
+			if len(f.Fields) > 0 && strings.EqualFold(f.Arg, arg) {
+				return f, true
+			}
+		}
+	}
+	return client.CommandForm{}, false
+}
+
+// openCommandForm opens form, which runs /cmd arg when submitted.
+func (m Model) openCommandForm(cmd, arg string, form client.CommandForm) (Model, tea.Cmd) {
+	fields := make([]dialog.FormField, len(form.Fields))
+	for i, f := range form.Fields {
+		label := f.Label
+		if label == "" {
+			label = f.Name
+		}
+		fields[i] = dialog.FormField{
+			Label:    label,
+			Value:    f.Default,
+			Hint:     f.Hint,
+			Required: f.Required,
+			Masked:   f.Secret,
+			Validate: fieldPattern(f),
+		}
+	}
+	title := form.Title
+	if title == "" {
+		title = strings.TrimSpace("/" + cmd + " " + arg)
+	}
+	m.cmdForm = commandFormRun{cmd: cmd, arg: strings.TrimSpace(arg), form: form}
+	m.form = dialog.NewForm(commandFormPrefix+cmd, title, fields)
+	m.form.SetSize(m.width, m.height)
+	m.pushModal(StateForm)
+	return m, nil
+}
+
+// fieldPattern returns the check that a value matches the pattern of f, or
+// nil when f has none. A pattern that does not compile is ignored.
+func fieldPattern(f client.CommandField) func(string) error {
+	if f.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(f.Pattern)
+	if err != nil {
+		log.Printf("command form field %s: %v", f.Name, err)
+		return nil
+	}
+	msg := f.Hint
+	if msg == "" {
+		msg = i18n.T("does not match %s", f.Pattern)
+	}
+	return func(v string) error {
+		if re.MatchString(v) {
+			return nil
+		}
+		return errors.New(msg)
+	}
+}
+
+// submitCommandForm runs the command of the submitted form with its values.
+func (m Model) submitCommandForm(values []string) (Model, tea.Cmd) {
+	run := m.cmdForm
+	m.cmdForm = commandFormRun{}
+	fields := make(map[string]string, len(run.form.Fields))
+	for i, f := range run.form.Fields {
+		if i < len(values) {
+			fields[f.Name] = values[i]
+		}
+	}
+	m.toasts.Add(i18n.T("Running /%s...", run.cmd), toast.ToastInfo)
+	return m, tea.Batch(m.focusInput(), m.executeCommandFields(run.cmd, run.arg, fields), m.tickCmd())
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// The context bar in the status bar and the sidebar turns amber once the
+// conversation takes context_warn_at percent of the context window and red
+// at context_alert_at, 80 and 95 by default. Crossing either shows a toast
+// once, offering to compact the conversation or to start a new session; it
+// shows again only after compaction, or anything else, brought the context
+// back below it, or in another session. /compact compacts the conversation
+// on demand.
+
+// Default shares of the context window at which the context bar warns.
+const (
+	defaultContextWarnAt  = 0.80
+	defaultContextAlertAt = 0.95
+)
+
+// contextThresholds returns the shares of the context window at which cfg
+// has the context bar turn amber and red.
+func contextThresholds(cfg config.Config) (warn, alert float64) {
+	warn, alert = defaultContextWarnAt, defaultContextAlertAt
+	if cfg.ContextWarnAt > 0 {
+		warn = float64(cfg.ContextWarnAt) / 100
+	}
+	if cfg.ContextAlertAt > 0 {
+		alert = float64(cfg.ContextAlertAt) / 100
+	}
+	return warn, max(warn, alert)
+}
+
+// applyContextThresholds colors the context bars by the thresholds of the
+// config.
+func (m *Model) applyContextThresholds() {
+	warn, alert := contextThresholds(m.config)
+	m.status.SetContextThresholds(warn, alert)
+	m.sidebar.SetContextThresholds(warn, alert)
+}
+
+// notifyContextPressure shows a toast when the utilization of the context
+// window crossed a threshold it had not crossed yet in this session.
+func (m *Model) notifyContextPressure(util float64) tea.Cmd {
+	warn, alert := contextThresholds(m.config)
+	level := 0
+	switch {
+	case util >= alert:
+		level = 2
+	case util >= warn:
+		level = 1
+	}
+	if m.ctxLevelSession != m.sessionID {
+		m.ctxLevelSession, m.ctxLevel = m.sessionID, 0
+	}
+	if level <= m.ctxLevel {
+		m.ctxLevel = level
+		return nil
+	}
+	m.ctxLevel = level
+
+	severity := toast.ToastWarning
+	if level == 2 {
+		severity = toast.ToastError
+	}
+	m.toasts.AddWithActions(i18n.T("Context %d%% full — /compact or /session new", int(util*100)), severity,
+		toast.Action{ID: "compact", Label: i18n.T("Compact")},
+		toast.Action{ID: "session-new", Label: i18n.T("New session")})
+	return m.tickCmd()
+}
+
+// handleCompactCommand implements /compact, which compacts the conversation
+// now, and /compact stats, the backend's compaction statistics.
+func (m Model) handleCompactCommand(arg string) (Model, tea.Cmd) {
+	switch {
+	case arg == "stats":
+		m.toasts.Add(i18n.T("Running /%s...", "compact"), toast.ToastInfo)
+		return m, tea.Batch(m.executeCommand("compact", ""), m.tickCmd())
+	case arg != "":
+		m.chat.AddSystemError(i18n.T("Usage: /compact [stats]"))
+		return m, nil
+	case m.sessionID == "":
+		m.chat.AddSystemMessage(i18n.T("Nothing to compact yet."))
+		return m, nil
+	case m.base == StateProcessing:
+		m.chat.AddSystemWarning(i18n.T("Wait for the current request to finish before compacting."))
+		return m, nil
+	}
+	m.toasts.Add(i18n.T("Compacting the conversation..."), toast.ToastInfo)
+	return m, tea.Batch(m.compactSession(0, ""), m.tickCmd())
+}
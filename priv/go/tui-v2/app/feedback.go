@@ -0,0 +1,108 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// Agent replies can be rated thumbs up or down, with + or - on a selected
+// reply or with /feedback on the latest one. Ratings go to the backend's
+// analytics, which feeds them to the learning engine so it learns which
+// responses users found wrong. A thumbs down asks what was wrong; the
+// comment is optional.
+
+// feedbackExcerpt is how much of the rated reply is sent with a rating.
+const feedbackExcerpt = 500
+
+// feedbackSent is the result of sendFeedback.
+type feedbackSent struct {
+	rating string
+	err    error
+}
+
+// handleFeedbackCommand implements /feedback up|down [comment] for the
+// selected or latest agent reply.
+func (m Model) handleFeedbackCommand(arg string) (Model, tea.Cmd) {
+	rating, comment, _ := strings.Cut(arg, " ")
+	if rating != "up" && rating != "down" {
+		m.chat.AddSystemError(i18n.T("Usage: /feedback up|down [comment]"))
+		return m, nil
+	}
+	return m.rateReply(rating, strings.TrimSpace(comment), comment == "")
+}
+
+// rateReply rates the selected or latest agent reply. A thumbs down without
+// a comment first asks for one when ask is set.
+func (m Model) rateReply(rating, comment string, ask bool) (Model, tea.Cmd) {
+	q, ok := m.chat.SelectedQuote()
+	if !ok || q.Role != chat.RoleAgent {
+		m.toasts.Add(i18n.T("Select an agent reply to rate"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	if rating == "down" && comment == "" && ask {
+		m.feedbackFor = q
+		m.form = dialog.NewForm("feedback", i18n.T("What was wrong with this reply?"), []dialog.FormField{
+			{Label: i18n.T("Comment (optional)")},
+		})
+		m.form.SetSize(m.width, m.height)
+		m.pushModal(StateForm)
+		return m, nil
+	}
+	m.chat.ClearSelection()
+	return m, m.sendFeedback(q, rating, comment)
+}
+
+// submitFeedbackForm sends the thumbs down the form asked a comment for.
+func (m Model) submitFeedbackForm(values []string) (Model, tea.Cmd) {
+	var comment string
+	if len(values) > 0 {
+		comment = strings.TrimSpace(values[0])
+	}
+	q := m.feedbackFor
+	m.feedbackFor = chat.Quote{}
+	m.chat.ClearSelection()
+	return m, tea.Batch(m.focusInput(), m.sendFeedback(q, "down", comment))
+}
+
+// sendFeedback posts a rating of reply q.
+func (m Model) sendFeedback(q chat.Quote, rating, comment string) tea.Cmd {
+	excerpt := strings.TrimSpace(q.Content)
+	if r := []rune(excerpt); len(r) > feedbackExcerpt {
+		excerpt = string(r[:feedbackExcerpt])
+	}
+	c := m.client
+	req := client.FeedbackRequest{
+		SessionID: m.sessionID,
+		MessageID: q.ID,
+		Rating:    rating,
+		Comment:   comment,
+		Excerpt:   excerpt,
+	}
+	return func() tea.Msg {
+		return feedbackSent{rating: rating, err: c.SendFeedback(req)}
+	}
+}
+
+// handleFeedbackSent reports whether the rating was recorded.
+func (m Model) handleFeedbackSent(v feedbackSent) (Model, tea.Cmd) {
+	switch {
+	case errors.Is(v.err, client.ErrNotSupported):
+		m.chat.AddSystemWarning(i18n.T("This backend does not take feedback."))
+		return m, nil
+	case v.err != nil:
+		m.chat.AddSystemError(i18n.T("Feedback not sent: %v", v.err))
+		return m, nil
+	case v.rating == "up":
+		m.toasts.Add(i18n.T("Thanks, marked as helpful"), toast.ToastInfo)
+	default:
+		m.toasts.Add(i18n.T("Thanks, marked as wrong"), toast.ToastInfo)
+	}
+	return m, m.tickCmd()
+}
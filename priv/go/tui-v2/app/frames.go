@@ -0,0 +1,200 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// Every Update and View call is timed by the frame tracker. Calls that take
+// longer than the frame budget are counted as slow, and the latest are kept
+// with the message that led to them: a slow View is blamed on the message
+// handled just before it. /stats prints the figures and "/stats overlay"
+// keeps a one-line summary on screen while reproducing a lag.
+
+const (
+	defaultFrameBudget = 16 * time.Millisecond // one frame at 60 Hz
+	frameWindow        = 512                   // recent calls kept per kind, for percentiles
+	maxSlowFrames      = 8
+	lagFactor          = 4 // calls this many budgets long are logged
+)
+
+// frameSeries times one kind of call: the recent durations plus running
+// totals since the last reset.
+type frameSeries struct {
+	recent     []time.Duration // ring of the last frameWindow durations
+	next       int
+	count      int
+	slow       int
+	total, max time.Duration
+}
+
+// add records a call of duration d.
+func (s *frameSeries) add(d time.Duration, slow bool) {
+	if len(s.recent) < frameWindow {
+		s.recent = append(s.recent, d)
+	} else {
+		s.recent[s.next] = d
+		s.next = (s.next + 1) % frameWindow
+	}
+	s.count++
+	s.total += d
+	s.max = max(s.max, d)
+	if slow {
+		s.slow++
+	}
+}
+
+// percentile returns the pth percentile (0-100) of the recent durations.
+func (s frameSeries) percentile(p int) time.Duration {
+	if len(s.recent) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(s.recent)
+	slices.Sort(sorted)
+	return sorted[min(len(sorted)*p/100, len(sorted)-1)]
+}
+
+// mean returns the mean duration since the last reset.
+func (s frameSeries) mean() time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+	return s.total / time.Duration(s.count)
+}
+
+// slowFrame is a call over the frame budget.
+type slowFrame struct {
+	kind string // "update" or "view"
+	msg  string // type of the message handled, e.g. "client.StreamingTokenEvent"
+	d    time.Duration
+	at   time.Time
+}
+
+// frameStats is shared by every copy of the Model, so View, which cannot
+// change the Model, still records its timing.
+type frameStats struct {
+	budget       time.Duration
+	update, view frameSeries
+	slowest      []slowFrame // newest last
+	lastMsg      string      // type of the latest message handled
+	overlay      bool        // show the timing line on screen
+	since        time.Time
+}
+
+func newFrameStats(budgetMS int) *frameStats {
+	budget := defaultFrameBudget
+	if budgetMS > 0 {
+		budget = time.Duration(budgetMS) * time.Millisecond
+	}
+	return &frameStats{budget: budget, since: time.Now()}
+}
+
+// recordUpdate times the handling of msg.
+func (f *frameStats) recordUpdate(msg any, d time.Duration) {
+	f.lastMsg = fmt.Sprintf("%T", msg)
+	f.record("update", &f.update, d)
+}
+
+// recordView times a frame render.
+func (f *frameStats) recordView(d time.Duration) {
+	f.record("view", &f.view, d)
+}
+
+func (f *frameStats) record(kind string, s *frameSeries, d time.Duration) {
+	slow := d > f.budget
+	s.add(d, slow)
+	if !slow {
+		return
+	}
+	f.slowest = append(f.slowest, slowFrame{kind: kind, msg: f.lastMsg, d: d, at: time.Now()})
+	if len(f.slowest) > maxSlowFrames {
+		f.slowest = f.slowest[len(f.slowest)-maxSlowFrames:]
+	}
+	if d >= lagFactor*f.budget {
+		log.Printf("lag: %s took %s after %s", kind, d.Round(time.Microsecond), f.lastMsg)
+	}
+}
+
+// reset clears the figures, keeping the budget and the overlay setting.
+func (f *frameStats) reset() {
+	*f = frameStats{budget: f.budget, overlay: f.overlay, since: time.Now()}
+}
+
+// report is the /stats text. items and lines describe the chat, whose size
+// drives the cost of rendering it.
+func (f *frameStats) report(items, lines int) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("Frame timing since %s (budget %s):", f.since.Format("15:04:05"), f.budget) + "\n")
+	row := func(label, unit string, s frameSeries) {
+		fmt.Fprintf(&sb, "  %-7s %6d %-7s mean %s · p50 %s · p95 %s · max %s · %d slow\n",
+			label, s.count, unit, ms(s.mean()), ms(s.percentile(50)), ms(s.percentile(95)), ms(s.max), s.slow)
+	}
+	row("update", "msgs", f.update)
+	row("view", "frames", f.view)
+	fmt.Fprintf(&sb, "  chat    %d messages, %d lines\n", items, lines)
+	if len(f.slowest) > 0 {
+		sb.WriteString(i18n.T("Slowest recent:") + "\n")
+		for i := len(f.slowest) - 1; i >= 0; i-- {
+			s := f.slowest[i]
+			fmt.Fprintf(&sb, "  %s  %-6s %8s  after %s\n", s.at.Format("15:04:05"), s.kind, ms(s.d), s.msg)
+		}
+	}
+	sb.WriteString(i18n.T("Use /stats overlay to show timing on screen, /stats reset to start over."))
+	return sb.String()
+}
+
+// overlayView is the one-line timing summary, right-aligned to width; "" when
+// the overlay is off.
+func (f *frameStats) overlayView(width int) string {
+	if !f.overlay {
+		return ""
+	}
+	line := fmt.Sprintf("update p95 %s · view p95 %s max %s · %d slow ",
+		ms(f.update.percentile(95)), ms(f.view.percentile(95)), ms(f.view.max), f.update.slow+f.view.slow)
+	line = ansi.Truncate(line, width, "…")
+	pad := max(width-ansi.StringWidth(line), 0)
+	st := style.Faint
+	if len(f.slowest) > 0 && time.Since(f.slowest[len(f.slowest)-1].at) < time.Second {
+		st = style.ErrorText // a frame just went over budget
+	}
+	return strings.Repeat(" ", pad) + st.Render(line)
+}
+
+// ms formats d in milliseconds with one decimal, e.g. "3.2ms".
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// handleStatsCommand prints the frame timing, or with "overlay" toggles the
+// on-screen summary and with "reset" clears the figures.
+func (m Model) handleStatsCommand(arg string) (Model, tea.Cmd) {
+	switch arg {
+	case "":
+		m.chat.AddSystemMessage(m.frames.report(m.chat.Len(), m.chat.Lines()))
+		return m, nil
+	case "overlay":
+		m.frames.overlay = !m.frames.overlay
+		m.recomputeLayout()
+		if m.frames.overlay {
+			m.toasts.Add(i18n.T("Frame timing overlay on"), toast.ToastInfo)
+		} else {
+			m.toasts.Add(i18n.T("Frame timing overlay off"), toast.ToastInfo)
+		}
+		return m, m.tickCmd()
+	case "reset":
+		m.frames.reset()
+		m.toasts.Add(i18n.T("Frame timing reset"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	m.chat.AddSystemError(i18n.T("Usage: /stats [overlay|reset]"))
+	return m, nil
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /hooks opens a view of the backend's hook pipeline: every hook with its
+// event and priority, with space to disable or enable it, and the actions
+// hooks blocked recently. Blocks reported while the view is open are added
+// to it. A backend without the hooks endpoint gets its /hooks command
+// instead.
+
+// hooksLoaded carries the hook pipeline for the hooks view.
+type hooksLoaded struct {
+	status *client.HooksStatus
+	err    error
+}
+
+// hookToggled reports the outcome of enabling or disabling a hook.
+type hookToggled struct {
+	toggle dialog.HookToggle
+	err    error
+}
+
+func (m Model) fetchHooks() tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		status, err := c.ListHooks()
+		return hooksLoaded{status: status, err: err}
+	}
+}
+
+// openHooks implements /hooks.
+func (m Model) openHooks() (Model, tea.Cmd) {
+	m.hooks.Reset()
+	m.toasts.Add(i18n.T("Loading hooks..."), toast.ToastInfo)
+	return m, tea.Batch(m.fetchHooks(), m.tickCmd())
+}
+
+// handleHooksLoaded opens the hooks view on the loaded pipeline.
+func (m Model) handleHooksLoaded(r hooksLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if errors.Is(r.err, client.ErrNotSupported) {
+			return m, m.executeCommand("hooks", "")
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load hooks: %v", r.err))
+		return m, nil
+	}
+	hooks := make([]dialog.HookEntry, len(r.status.Hooks))
+	for i, h := range r.status.Hooks {
+		hooks[i] = dialog.HookEntry{
+			Name:      h.Name,
+			Event:     h.Event,
+			Priority:  h.Priority,
+			Enabled:   h.Enabled,
+			Protected: h.Protected,
+		}
+	}
+	blocks := make([]dialog.HookBlockEntry, len(r.status.RecentBlocks))
+	for i, b := range r.status.RecentBlocks {
+		blocks[i] = dialog.HookBlockEntry{Hook: b.HookName, Event: b.HookEvent, Reason: b.Reason}
+		if at, err := time.Parse(time.RFC3339, b.At); err == nil {
+			blocks[i].At = at.Local()
+		}
+	}
+	m.hooks.SetEntries(hooks, blocks)
+	m.hooks.SetSize(m.width, m.height)
+	if !m.hasModal(StateHooks) {
+		m.pushModal(StateHooks)
+	}
+	return m, nil
+}
+
+func (m Model) toggleHook(t dialog.HookToggle) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		return hookToggled{toggle: t, err: c.SetHookEnabled(t.Name, t.Enabled)}
+	}
+}
+
+// handleHookToggled shows the hook's new state in the hooks view.
+func (m Model) handleHookToggled(r hookToggled) (Model, tea.Cmd) {
+	switch {
+	case r.err != nil:
+		m.hooks.SetStatus(fmt.Sprintf("%s: %v", r.toggle.Name, r.err), true)
+	case r.toggle.Enabled:
+		m.hooks.SetEnabled(r.toggle.Name, true)
+		m.hooks.SetStatus(i18n.T("Enabled %s", r.toggle.Name), false)
+	default:
+		m.hooks.SetEnabled(r.toggle.Name, false)
+		m.hooks.SetStatus(i18n.T("Disabled %s until enabled again or the backend restarts", r.toggle.Name), false)
+	}
+	return m, nil
+}
+
+// noteHookBlock adds a block reported over the stream to the hooks view,
+// when it is open.
+func (m *Model) noteHookBlock(v client.HookBlockedEvent) {
+	if m.hasModal(StateHooks) {
+		m.hooks.AddBlock(dialog.HookBlockEntry{Hook: v.HookName, Reason: v.Reason, At: time.Now()})
+	}
+}
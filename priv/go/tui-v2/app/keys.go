@@ -1,11 +1,53 @@
 package app
 
-import "charm.land/bubbles/v2/key"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+)
+
+// leaderKey starts the leader chords, such as "space s n".
+const leaderKey = "space"
+
+// chordTimeout is how long a key sequence waits for its next key.
+const chordTimeout = time.Second
+
+// Chord is a binding made of a key sequence, such as "g g", each key typed
+// within chordTimeout of the previous one. Keys are named as in key.Binding.
+// Chords only apply while the input is empty, so their keys still type text
+// in a draft.
+type Chord struct {
+	keys []string
+	help key.Help
+}
+
+// NewChord returns a chord for a space-separated key sequence.
+func NewChord(keys, desc string) Chord {
+	return Chord{keys: strings.Fields(keys), help: key.Help{Key: keys, Desc: desc}}
+}
+
+// Keys returns the key sequence.
+func (c Chord) Keys() []string { return c.keys }
+
+// Help returns the chord's help text.
+func (c Chord) Help() key.Help { return c.help }
+
+// Matches reports whether seq is the whole sequence.
+func (c Chord) Matches(seq []string) bool { return slices.Equal(c.keys, seq) }
+
+// Continues reports whether seq is a strict prefix of the sequence, so more
+// keys may complete it.
+func (c Chord) Continues(seq []string) bool {
+	return len(seq) < len(c.keys) && slices.Equal(c.keys[:len(seq)], seq)
+}
 
 // KeyMap defines all global keybindings.
 type KeyMap struct {
 	// Global
 	Submit  key.Binding
+	SendNow key.Binding // while processing: cancel and send the input instead
 	Cancel  key.Binding
 	QuitEOF key.Binding
 	Escape  key.Binding
@@ -24,9 +66,20 @@ type KeyMap struct {
 	// Toggles
 	ToggleExpand     key.Binding
 	ToggleThinking   key.Binding
+	ToggleRaw        key.Binding
 	ToggleBackground key.Binding
 	ToggleSidebar    key.Binding
 
+	// Pane sizing
+	SidebarShrink key.Binding
+	SidebarGrow   key.Binding
+	PanelGrow     key.Binding
+	PanelShrink   key.Binding
+
+	// Pinned pane
+	PinnedUp   key.Binding
+	PinnedDown key.Binding
+
 	// Editor
 	Tab        key.Binding
 	ClearInput key.Binding
@@ -34,9 +87,36 @@ type KeyMap struct {
 	// Commands
 	NewSession key.Binding
 	Palette    key.Binding
+	RepeatLast key.Binding
+	CycleModel key.Binding
+
+	// Messages
+	SelectPrev key.Binding
+	SelectNext key.Binding
+	Reply      key.Binding
+	Retry      key.Binding
+	RetryFail  key.Binding // r with an empty input, on a failed prompt
+	Reveal     key.Binding
+	Timeline   key.Binding
+	Notices    key.Binding // show or hide system notices
+	RateUp     key.Binding // + on a selected reply
+	RateDown   key.Binding // - on a selected reply
+	Apply      key.Binding // a on a selected reply
 
 	// Copy
 	CopyMessage key.Binding
+
+	// ToastAction runs an action of the newest toast offering any.
+	ToastAction key.Binding
+
+	// Chords, typed with an empty input
+	GotoTop       Chord // g g
+	GotoBottom    Chord // g e
+	LeaderNew     Chord // space s n
+	LeaderResume  Chord // space s l
+	LeaderModels  Chord // space m
+	LeaderTheme   Chord // space t
+	LeaderHistory Chord // space h
 }
 
 // DefaultKeyMap returns the default keybindings.
@@ -46,6 +126,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "submit"),
 		),
+		SendNow: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "cancel and send now"),
+		),
 		Cancel: key.NewBinding(
 			key.WithKeys("ctrl+c"),
 			key.WithHelp("ctrl+c", "cancel/quit"),
@@ -102,6 +186,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+t"),
 			key.WithHelp("ctrl+t", "toggle thinking"),
 		),
+		ToggleRaw: key.NewBinding(
+			key.WithKeys("alt+p"),
+			key.WithHelp("alt+p", "raw/rendered message"),
+		),
 		ToggleBackground: key.NewBinding(
 			key.WithKeys("ctrl+b"),
 			key.WithHelp("ctrl+b", "background"),
@@ -110,6 +198,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+l"),
 			key.WithHelp("ctrl+l", "toggle sidebar"),
 		),
+		SidebarShrink: key.NewBinding(
+			key.WithKeys("ctrl+left"),
+			key.WithHelp("ctrl+←", "narrow sidebar"),
+		),
+		SidebarGrow: key.NewBinding(
+			key.WithKeys("ctrl+right"),
+			key.WithHelp("ctrl+→", "widen sidebar"),
+		),
+		PanelGrow: key.NewBinding(
+			key.WithKeys("ctrl+up"),
+			key.WithHelp("ctrl+↑", "grow tasks/agents panel"),
+		),
+		PanelShrink: key.NewBinding(
+			key.WithKeys("ctrl+down"),
+			key.WithHelp("ctrl+↓", "shrink tasks/agents panel"),
+		),
+		PinnedUp: key.NewBinding(
+			key.WithKeys("alt+up"),
+			key.WithHelp("alt+↑", "scroll pinned pane up"),
+		),
+		PinnedDown: key.NewBinding(
+			key.WithKeys("alt+down"),
+			key.WithHelp("alt+↓", "scroll pinned pane down"),
+		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "autocomplete"),
@@ -126,9 +238,78 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+k"),
 			key.WithHelp("ctrl+k", "command palette"),
 		),
+		RepeatLast: key.NewBinding(
+			key.WithKeys("alt+."),
+			key.WithHelp("alt+.", "repeat last command"),
+		),
+		CycleModel: key.NewBinding(
+			key.WithKeys("alt+m"),
+			key.WithHelp("alt+m", "cycle favorite models"),
+		),
+		SelectPrev: key.NewBinding(
+			key.WithKeys("shift+up"),
+			key.WithHelp("shift+↑", "select previous message"),
+		),
+		SelectNext: key.NewBinding(
+			key.WithKeys("shift+down"),
+			key.WithHelp("shift+↓", "select next message"),
+		),
+		Reply: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reply to message"),
+		),
+		Retry: key.NewBinding(
+			key.WithKeys("alt+r"),
+			key.WithHelp("alt+r", "retry prompt"),
+		),
+		RetryFail: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry failed prompt"),
+		),
+		Reveal: key.NewBinding(
+			key.WithKeys("alt+v"),
+			key.WithHelp("alt+v", "reveal/mask secrets"),
+		),
+		Timeline: key.NewBinding(
+			key.WithKeys("alt+t"),
+			key.WithHelp("alt+t", "session timeline"),
+		),
+		Notices: key.NewBinding(
+			key.WithKeys("alt+n"),
+			key.WithHelp("alt+n", "show/hide system notices"),
+		),
+		RateUp: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "rate reply helpful"),
+		),
+		RateDown: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "rate reply wrong"),
+		),
+		Apply: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "apply suggested files"),
+		),
 		CopyMessage: key.NewBinding(
 			key.WithKeys("y", "c"),
 			key.WithHelp("y/c", "copy message"),
 		),
+		ToastAction: key.NewBinding(
+			key.WithKeys("alt+1", "alt+2", "alt+3"),
+			key.WithHelp("alt+1..3", "toast action"),
+		),
+
+		GotoTop:       NewChord("g g", "scroll to top"),
+		GotoBottom:    NewChord("g e", "scroll to bottom"),
+		LeaderNew:     NewChord(leaderKey+" s n", "new session"),
+		LeaderResume:  NewChord(leaderKey+" s l", "list sessions"),
+		LeaderModels:  NewChord(leaderKey+" m", "models"),
+		LeaderTheme:   NewChord(leaderKey+" t", "theme picker"),
+		LeaderHistory: NewChord(leaderKey+" h", "notification history"),
 	}
 }
+
+// Chords returns the chord bindings.
+func (km KeyMap) Chords() []Chord {
+	return []Chord{km.GotoTop, km.GotoBottom, km.LeaderNew, km.LeaderResume, km.LeaderModels, km.LeaderTheme, km.LeaderHistory}
+}
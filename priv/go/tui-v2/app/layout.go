@@ -13,10 +13,17 @@ const (
 	// layout regardless of user preference.
 	compactModeBreakpoint = 100
 
-	// Sidebar sizing bounds.
+	// Sidebar sizing bounds. sidebarMaxWidth caps the automatic width only;
+	// a width set by resizing may use anything the chat pane can spare.
 	sidebarMinWidth = 28
 	sidebarMaxWidth = 40
 
+	// pinnedMinWidth is the narrowest the pinned reference pane is shown.
+	pinnedMinWidth = 30
+
+	// sidebarResizeStep is the number of columns Ctrl+←/→ moves the divider.
+	sidebarResizeStep = 2
+
 	// Minimum chat pane width; enforced even if it means the sidebar is clipped.
 	chatMinWidth = 50
 
@@ -32,11 +39,12 @@ type Layout struct {
 	TermHeight    int
 	HeaderHeight  int // header line + separator
 	StatusHeight  int
-	InputHeight   int
+	InputHeight   int // input, or the plan or picker shown in its place
 	ChatWidth     int // width available for the chat pane
 	ChatHeight    int // height available for the chat pane
 	SidebarWidth  int // 0 in compact mode
 	SidebarHeight int
+	PinnedWidth   int  // pinned reference pane right of the chat; 0 when hidden
 	CompactMode   bool // true when the terminal is too narrow for sidebar layout
 }
 
@@ -44,17 +52,22 @@ type Layout struct {
 //
 // Responsive rules:
 //   - If termW < compactModeBreakpoint, force LayoutCompact regardless of mode.
-//   - Sidebar width is clamped between sidebarMinWidth and sidebarMaxWidth.
+//   - Sidebar width is sidebarWidth when set, clamped between sidebarMinWidth
+//     and what leaves chatMinWidth for the chat pane; otherwise about 20% of
+//     termW, clamped between sidebarMinWidth and sidebarMaxWidth.
 //   - Chat width is termW - sidebarWidth - 1 (divider) in sidebar mode, or
 //     termW in compact mode.
-//   - Heights: allocate header (1-2), input (3), status, tasks, agents; the
-//     remainder goes to the chat pane.
-func ComputeLayout(termW, termH int, mode LayoutMode, statusLines, taskLines, agentLines int) Layout {
+//   - With a pinned document, the pane takes about 40% of the chat width,
+//     as long as the chat keeps chatMinWidth; otherwise it is hidden.
+//   - Heights: allocate header (2), status, input and the panels between chat
+//     and status (tasks, agents, timing overlay); the remainder goes to the
+//     chat pane.
+func ComputeLayout(termW, termH int, mode LayoutMode, sidebarWidth int, pinned bool, statusLines, inputLines, panelLines int) Layout {
 	l := Layout{
 		TermWidth:    termW,
 		TermHeight:   termH,
 		HeaderHeight: 2, // header line + separator
-		InputHeight:  2, // separator + prompt line
+		InputHeight:  inputLines,
 	}
 
 	// Status height: at least 1 line for idle state.
@@ -74,14 +87,7 @@ func ComputeLayout(termW, termH int, mode LayoutMode, statusLines, taskLines, ag
 	if effectiveMode == LayoutSidebar && termW >= minSidebarWidth {
 		l.Mode = LayoutSidebar
 
-		// Clamp sidebar width proportional to terminal width.
-		sw := termW / 5 // roughly 20% of screen
-		if sw < sidebarMinWidth {
-			sw = sidebarMinWidth
-		}
-		if sw > sidebarMaxWidth {
-			sw = sidebarMaxWidth
-		}
+		sw := clampSidebarWidth(sidebarWidth, termW)
 		l.SidebarWidth = sw
 		l.ChatWidth = termW - sw - 1 // -1 for the divider border column
 	} else {
@@ -95,8 +101,20 @@ func ComputeLayout(termW, termH int, mode LayoutMode, statusLines, taskLines, ag
 		l.ChatWidth = chatMinWidth
 	}
 
-	// Chat height = total - header - status - input - tasks - agents.
-	reserved := l.HeaderHeight + l.StatusHeight + l.InputHeight + taskLines + agentLines
+	// Pinned reference pane.
+	if pinned {
+		pw := max(l.ChatWidth*2/5, pinnedMinWidth)
+		if l.ChatWidth-pw < chatMinWidth {
+			pw = l.ChatWidth - chatMinWidth
+		}
+		if pw >= pinnedMinWidth {
+			l.PinnedWidth = pw
+			l.ChatWidth -= pw
+		}
+	}
+
+	// Chat height = total - header - status - input - panels.
+	reserved := l.HeaderHeight + l.StatusHeight + l.InputHeight + panelLines
 	l.ChatHeight = termH - reserved
 	if l.ChatHeight < 5 {
 		l.ChatHeight = 5
@@ -106,3 +124,26 @@ func ComputeLayout(termW, termH int, mode LayoutMode, statusLines, taskLines, ag
 
 	return l
 }
+
+// clampSidebarWidth returns the sidebar width for a terminal termW columns
+// wide. want <= 0 selects the automatic width.
+func clampSidebarWidth(want, termW int) int {
+	if want <= 0 {
+		// Clamp sidebar width proportional to terminal width.
+		sw := termW / 5 // roughly 20% of screen
+		if sw < sidebarMinWidth {
+			sw = sidebarMinWidth
+		}
+		if sw > sidebarMaxWidth {
+			sw = sidebarMaxWidth
+		}
+		return sw
+	}
+	if maxW := termW - chatMinWidth - 1; want > maxW {
+		want = maxW
+	}
+	if want < sidebarMinWidth {
+		want = sidebarMinWidth
+	}
+	return want
+}
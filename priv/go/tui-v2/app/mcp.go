@@ -0,0 +1,188 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/sidebar"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /mcp opens a manager for the MCP servers configured in the backend's
+// mcp.json: their state, the tools each exposes, and keys to add, remove
+// and restart them. The server list is also fetched on connect and after
+// every action for the sidebar's MCP section.
+
+// mcpServersLoaded carries the configured MCP servers; open is set when
+// /mcp asked for them.
+type mcpServersLoaded struct {
+	servers []client.MCPServer
+	err     error
+	open    bool
+}
+
+// mcpActionDone reports the outcome of adding, removing or restarting a
+// server; server is its new state after an add or a restart.
+type mcpActionDone struct {
+	action dialog.MCPAction
+	server *client.MCPServer
+	err    error
+}
+
+func (m Model) fetchMCPServers(open bool) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		servers, err := c.ListMCPServers()
+		return mcpServersLoaded{servers: servers, err: err, open: open}
+	}
+}
+
+// openMCP implements /mcp.
+func (m Model) openMCP() (Model, tea.Cmd) {
+	m.mcp.Reset()
+	m.toasts.Add(i18n.T("Loading MCP servers..."), toast.ToastInfo)
+	return m, tea.Batch(m.fetchMCPServers(true), m.tickCmd())
+}
+
+// handleMCPServersLoaded updates the sidebar and, for /mcp, the manager.
+func (m Model) handleMCPServersLoaded(r mcpServersLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		switch {
+		case !r.open:
+		case errors.Is(r.err, client.ErrNotSupported):
+			m.chat.AddSystemWarning(i18n.T("This backend does not manage MCP servers."))
+		default:
+			m.chat.AddSystemError(i18n.T("Failed to load MCP servers: %v", r.err))
+		}
+		return m, nil
+	}
+	status := make([]sidebar.MCPStatus, len(r.servers))
+	entries := make([]dialog.MCPServerEntry, len(r.servers))
+	for i, s := range r.servers {
+		status[i] = sidebar.MCPStatus{Name: s.Name, State: s.Status, Tools: len(s.Tools)}
+		entries[i] = mcpServerEntry(s)
+	}
+	m.sidebar.SetMCPServers(status)
+	if !r.open && !m.hasModal(StateMCP) {
+		return m, nil
+	}
+	m.mcp.SetEntries(entries)
+	m.mcp.SetSize(m.width, m.height)
+	if !m.hasModal(StateMCP) {
+		m.pushModal(StateMCP)
+	}
+	return m, nil
+}
+
+// handleMCPAction opens the add form or sends a remove or restart.
+func (m Model) handleMCPAction(a dialog.MCPAction) (Model, tea.Cmd) {
+	if a.Action == "add" {
+		m.form = dialog.NewForm("mcp", i18n.T("Add MCP server"), []dialog.FormField{
+			{Label: i18n.T("Name")},
+			{Label: i18n.T("Command or URL")},
+			{Label: i18n.T("Env (KEY=VALUE, space separated)")},
+		})
+		m.form.SetSize(m.width, m.height)
+		m.pushModal(StateForm)
+		return m, nil
+	}
+	c := m.client
+	return m, func() tea.Msg {
+		var server *client.MCPServer
+		var err error
+		switch a.Action {
+		case "remove":
+			err = c.RemoveMCPServer(a.Name)
+		case "restart":
+			server, err = c.RestartMCPServer(a.Name)
+		}
+		return mcpActionDone{action: a, server: server, err: err}
+	}
+}
+
+// submitMCPForm adds the server described by the add form.
+func (m Model) submitMCPForm(values []string) (Model, tea.Cmd) {
+	for len(values) < 3 {
+		values = append(values, "")
+	}
+	cfg, err := mcpServerConfig(values[0], values[1], values[2])
+	if err != nil {
+		m.mcp.SetStatus(err.Error(), true)
+		return m, nil
+	}
+	m.mcp.SetStatus(i18n.T("Adding %s...", cfg.Name), false)
+	c := m.client
+	a := dialog.MCPAction{Action: "add", Name: cfg.Name}
+	return m, func() tea.Msg {
+		server, err := c.AddMCPServer(cfg)
+		return mcpActionDone{action: a, server: server, err: err}
+	}
+}
+
+// handleMCPActionDone reports the outcome in the manager and refreshes the
+// server list.
+func (m Model) handleMCPActionDone(r mcpActionDone) (Model, tea.Cmd) {
+	switch {
+	case r.err != nil:
+		m.mcp.SetStatus(fmt.Sprintf("%s: %v", r.action.Name, r.err), true)
+		return m, nil
+	case r.action.Action == "remove":
+		m.mcp.SetStatus(i18n.T("Removed %s", r.action.Name), false)
+	case r.server.Status == "error":
+		m.mcp.SetStatus(fmt.Sprintf("%s: %s", r.action.Name, r.server.Error), true)
+	case r.action.Action == "add":
+		m.mcp.SetStatus(i18n.T("Added %s", r.action.Name), false)
+	default:
+		m.mcp.SetStatus(i18n.T("Restarted %s", r.action.Name), false)
+	}
+	return m, m.fetchMCPServers(false)
+}
+
+// mcpServerConfig builds an add request from the form: target is a URL or
+// a command line split on spaces, env is KEY=VALUE pairs.
+func mcpServerConfig(name, target, env string) (client.MCPServerConfig, error) {
+	cfg := client.MCPServerConfig{Name: strings.TrimSpace(name)}
+	if cfg.Name == "" {
+		return cfg, errors.New(i18n.T("A name is required"))
+	}
+	target = strings.TrimSpace(target)
+	switch {
+	case target == "":
+		return cfg, errors.New(i18n.T("A command or URL is required"))
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		cfg.URL = target
+	default:
+		fields := strings.Fields(target)
+		cfg.Command, cfg.Args = fields[0], fields[1:]
+	}
+	for _, kv := range strings.Fields(env) {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return cfg, errors.New(i18n.T("Env entries must be KEY=VALUE: %s", kv))
+		}
+		if cfg.Env == nil {
+			cfg.Env = make(map[string]string)
+		}
+		cfg.Env[k] = v
+	}
+	return cfg, nil
+}
+
+func mcpServerEntry(s client.MCPServer) dialog.MCPServerEntry {
+	target := s.URL
+	if s.Command != "" {
+		target = strings.Join(append([]string{s.Command}, s.Args...), " ")
+	}
+	return dialog.MCPServerEntry{
+		Name:   s.Name,
+		Target: target,
+		State:  s.Status,
+		Error:  s.Error,
+		Tools:  s.Tools,
+	}
+}
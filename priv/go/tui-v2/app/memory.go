@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /memory opens a browser over the backend's long-term memory, all of it or
+// what matches the text given after the command. Entries can be edited,
+// deleted and pinned; a pinned entry is always recalled into the agent's
+// context. A backend without the entries endpoint gets its /memory command
+// instead.
+
+// memoriesLoaded carries the entries for the memory browser.
+type memoriesLoaded struct {
+	entries []client.MemoryEntry
+	err     error
+	query   string
+}
+
+// memoryActionDone reports the outcome of a memory browser action; entry is
+// the changed entry after an edit or a pin.
+type memoryActionDone struct {
+	action dialog.MemoryAction
+	entry  *client.MemoryEntry
+	err    error
+}
+
+func (m Model) fetchMemories(query string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		entries, err := c.ListMemories(query)
+		return memoriesLoaded{entries: entries, err: err, query: query}
+	}
+}
+
+// openMemory implements /memory [query].
+func (m Model) openMemory(query string) (Model, tea.Cmd) {
+	m.toasts.Add(i18n.T("Loading memories..."), toast.ToastInfo)
+	return m, tea.Batch(m.fetchMemories(query), m.tickCmd())
+}
+
+// handleMemoriesLoaded opens the memory browser on the loaded entries.
+func (m Model) handleMemoriesLoaded(r memoriesLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if errors.Is(r.err, client.ErrNotSupported) {
+			return m, m.executeCommand("memory", r.query)
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load memories: %v", r.err))
+		return m, nil
+	}
+	if len(r.entries) == 0 {
+		if r.query != "" {
+			m.chat.AddSystemMessage(i18n.T("No memories match %q.", r.query))
+		} else {
+			m.chat.AddSystemMessage(i18n.T("No memories saved yet."))
+		}
+		return m, nil
+	}
+	entries := make([]dialog.MemoryEntry, len(r.entries))
+	for i, e := range r.entries {
+		entries[i] = memoryEntry(e)
+	}
+	m.memory.SetEntries(entries)
+	m.memory.SetSize(m.width, m.height)
+	if !m.hasModal(StateMemory) {
+		m.pushModal(StateMemory)
+	}
+	return m, nil
+}
+
+func (m Model) applyMemoryAction(a dialog.MemoryAction) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		var entry *client.MemoryEntry
+		var err error
+		switch a.Action {
+		case "edit":
+			entry, err = c.UpdateMemory(a.ID, client.MemoryUpdate{Content: a.Content})
+		case "pin":
+			entry, err = c.UpdateMemory(a.ID, client.MemoryUpdate{Pinned: &a.Pinned})
+		case "delete":
+			err = c.DeleteMemory(a.ID)
+		}
+		return memoryActionDone{action: a, entry: entry, err: err}
+	}
+}
+
+// handleMemoryActionDone updates the browser after an action.
+func (m Model) handleMemoryActionDone(r memoryActionDone) (Model, tea.Cmd) {
+	if r.err != nil {
+		m.memory.SetStatus(i18n.T("Failed: %v", r.err), true)
+		return m, nil
+	}
+	switch {
+	case r.action.Action == "delete":
+		m.memory.Remove(r.action.ID)
+		m.memory.SetStatus(i18n.T("Memory deleted"), false)
+	case r.action.Action == "edit":
+		m.memory.Replace(r.action.ID, memoryEntry(*r.entry))
+		m.memory.SetStatus(i18n.T("Memory saved"), false)
+	case r.action.Pinned:
+		m.memory.Replace(r.action.ID, memoryEntry(*r.entry))
+		m.memory.SetStatus(i18n.T("Pinned: always recalled, never archived"), false)
+	default:
+		m.memory.Replace(r.action.ID, memoryEntry(*r.entry))
+		m.memory.SetStatus(i18n.T("Unpinned"), false)
+	}
+	return m, nil
+}
+
+func memoryEntry(e client.MemoryEntry) dialog.MemoryEntry {
+	return dialog.MemoryEntry{
+		ID:        e.ID,
+		Category:  e.Category,
+		Timestamp: e.Timestamp,
+		Content:   e.Content,
+		Pinned:    e.Pinned,
+	}
+}
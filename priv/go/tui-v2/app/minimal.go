@@ -0,0 +1,38 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+)
+
+// --minimal makes the TUI usable over high-latency SSH links and serial
+// consoles, where every byte written to the terminal counts. Gradients are
+// drawn in one color and spinners, phrase rotation and cursor blink stop,
+// as with --reduced-motion; the status tick that refreshes the elapsed time
+// and token counts slows down; main caps the frame rate at MinimalFPS; and
+// the event stream is delivered in batches, so a streaming reply re-renders
+// a few times a second rather than once per token.
+
+// MinimalFPS is the most frames a second drawn in --minimal mode.
+const MinimalFPS = 10
+
+// minimalTickInterval is the status tick in --minimal mode.
+const minimalTickInterval = 3 * time.Second
+
+// minimalBatchInterval is how long events of the stream are collected
+// before they are handled together in --minimal mode.
+const minimalBatchInterval = 250 * time.Millisecond
+
+// handleSSEBatch handles the events of a batch in order, as if each had
+// arrived alone.
+func (m Model) handleSSEBatch(b client.SSEBatch) (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for _, e := range b.Events {
+		mm, cmd := m.update(e)
+		m = mm.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
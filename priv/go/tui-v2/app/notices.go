@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// System notices, such as reconnects, background task updates and the
+// results of local commands, can be hidden from the chat with Alt+N or
+// /notices, leaving the conversation itself; errors and a notice waiting
+// for an answer still show. The setting is kept in tui.json. /export writes
+// the conversation as Markdown, without the notices unless asked for them.
+
+// handleNoticesCommand shows or hides system notices, toggling them without
+// an argument.
+func (m Model) handleNoticesCommand(arg string) (Model, tea.Cmd) {
+	switch arg {
+	case "":
+		return m.setNoticesHidden(!m.config.HideNotices)
+	case "on", "show":
+		return m.setNoticesHidden(false)
+	case "off", "hide":
+		return m.setNoticesHidden(true)
+	}
+	m.chat.AddSystemError(i18n.T("Usage: /notices [show|hide]"))
+	return m, nil
+}
+
+// setNoticesHidden hides or shows system notices and keeps the setting.
+func (m Model) setNoticesHidden(hidden bool) (Model, tea.Cmd) {
+	m.config.HideNotices = hidden
+	m.chat.SetNoticesHidden(hidden)
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Notices set but could not persist: %v", err))
+	}
+	if hidden {
+		m.toasts.Add(i18n.T("Hiding %d system notices (Alt+N shows them)", m.chat.HiddenNotices()), toast.ToastInfo)
+	} else {
+		m.toasts.Add(i18n.T("Showing system notices"), toast.ToastInfo)
+	}
+	return m, m.tickCmd()
+}
+
+// handleExportCommand implements /export [--notices] [path], writing the
+// conversation as Markdown to path, by default a dated file in the working
+// directory.
+func (m Model) handleExportCommand(arg string) (Model, tea.Cmd) {
+	var notices bool
+	var path string
+	for _, f := range strings.Fields(arg) {
+		switch {
+		case f == "--notices":
+			notices = true
+		case strings.HasPrefix(f, "-") || path != "":
+			m.chat.AddSystemError(i18n.T("Usage: /export [--notices] [path]"))
+			return m, nil
+		default:
+			path = f
+		}
+	}
+	if path == "" {
+		path = "osa-chat-" + time.Now().Format("2006-01-02-1504") + ".md"
+	}
+
+	md, n := m.chat.Markdown(notices)
+	if n == 0 {
+		m.chat.AddSystemMessage(i18n.T("Nothing to export yet."))
+		return m, nil
+	}
+	header := "# OSA conversation\n\n"
+	if m.sessionID != "" {
+		header += fmt.Sprintf("Session %s, exported %s.\n\n", m.sessionID, time.Now().Format(time.DateTime))
+	}
+	if err := os.WriteFile(path, []byte(header+md), 0o600); err != nil {
+		m.chat.AddSystemError(i18n.T("Export failed: %v", err))
+		return m, nil
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	m.toasts.Add(i18n.T("Exported %d messages to %s", n, path), toast.ToastInfo)
+	return m, m.tickCmd()
+}
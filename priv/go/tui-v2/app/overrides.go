@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+)
+
+// Session overrides let a conversation tweak the agent without touching the
+// backend config: /system adds text to the system prompt, /env sets
+// variables for the tools it runs and /tools disables tools. All are kept
+// per session ID in tui.json, like model pins, and sent with every
+// orchestrate request of that session.
+
+// envKey matches the variable names /env accepts.
+var envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sessionOverride returns the overrides of the current session.
+func (m Model) sessionOverride() config.SessionOverride {
+	return m.config.SessionOverrides[m.sessionID]
+}
+
+// setSessionOverride stores o for the current session and persists it. An
+// empty override is removed.
+func (m *Model) setSessionOverride(o config.SessionOverride) {
+	if m.config.SessionOverrides == nil {
+		m.config.SessionOverrides = make(map[string]config.SessionOverride)
+	}
+	if o.System == "" && len(o.Env) == 0 && len(o.DisabledTools) == 0 {
+		delete(m.config.SessionOverrides, m.sessionID)
+	} else {
+		m.config.SessionOverrides[m.sessionID] = o
+	}
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Session override applied but could not persist: %v", err))
+	}
+	m.syncSessionModel()
+}
+
+// handleSystemCommand shows the session's system prompt addition, replaces
+// it with arg, or with "clear" drops it.
+func (m Model) handleSystemCommand(arg string) (Model, tea.Cmd) {
+	o := m.sessionOverride()
+	switch arg {
+	case "":
+		if o.System == "" {
+			m.chat.AddSystemMessage(i18n.T("No system prompt addition in this session. Usage: /system <text> | /system clear"))
+			return m, nil
+		}
+		m.chat.AddSystemMessage(i18n.T("System prompt addition for session %s:\n%s", shortID(m.sessionID), o.System))
+		return m, nil
+	case "clear":
+		if o.System == "" {
+			m.chat.AddSystemMessage(i18n.T("No system prompt addition in this session."))
+			return m, nil
+		}
+		o.System = ""
+		m.setSessionOverride(o)
+		m.chat.AddSystemMessage(i18n.T("Session %s uses the default system prompt again", shortID(m.sessionID)))
+		return m, nil
+	}
+	o.System = arg
+	m.setSessionOverride(o)
+	m.chat.AddSystemMessage(i18n.T("System prompt addition set for session %s (%d chars)", shortID(m.sessionID), len([]rune(arg))))
+	return m, nil
+}
+
+// handleEnvCommand lists the session's variables, sets KEY=VALUE, unsets
+// KEY= or with "clear" drops them all.
+func (m Model) handleEnvCommand(arg string) (Model, tea.Cmd) {
+	o := m.sessionOverride()
+	switch arg {
+	case "":
+		if len(o.Env) == 0 {
+			m.chat.AddSystemMessage(i18n.T("No variables set in this session. Usage: /env KEY=VALUE | /env KEY= | /env clear"))
+			return m, nil
+		}
+		var sb strings.Builder
+		sb.WriteString(i18n.T("Variables for session %s:", shortID(m.sessionID)) + "\n")
+		for _, k := range slices.Sorted(maps.Keys(o.Env)) {
+			fmt.Fprintf(&sb, "  %s=%s\n", k, o.Env[k])
+		}
+		m.chat.AddSystemMessage(strings.TrimRight(sb.String(), "\n"))
+		return m, nil
+	case "clear":
+		if len(o.Env) == 0 {
+			m.chat.AddSystemMessage(i18n.T("No variables set in this session."))
+			return m, nil
+		}
+		o.Env = nil
+		m.setSessionOverride(o)
+		m.chat.AddSystemMessage(i18n.T("Cleared the variables of session %s", shortID(m.sessionID)))
+		return m, nil
+	}
+
+	key, value, ok := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !envKey.MatchString(key) {
+		m.chat.AddSystemError(i18n.T("Usage: /env KEY=VALUE (names are letters, digits and _, not starting with a digit)"))
+		return m, nil
+	}
+	env := maps.Clone(o.Env)
+	if value == "" {
+		if _, set := env[key]; !set {
+			m.chat.AddSystemMessage(i18n.T("%s is not set in this session.", key))
+			return m, nil
+		}
+		delete(env, key)
+		o.Env = env
+		m.setSessionOverride(o)
+		m.chat.AddSystemMessage(i18n.T("Unset %s for session %s", key, shortID(m.sessionID)))
+		return m, nil
+	}
+	if env == nil {
+		env = make(map[string]string)
+	}
+	env[key] = value
+	o.Env = env
+	m.setSessionOverride(o)
+	m.chat.AddSystemMessage(i18n.T("Set %s for session %s", key, shortID(m.sessionID)))
+	return m, nil
+}
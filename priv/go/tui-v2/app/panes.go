@@ -0,0 +1,49 @@
+package app
+
+import (
+	"log"
+
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/panestatus"
+)
+
+// Inside tmux or zellij, or with "pane_status": "on", the TUI publishes its
+// state and the replies that arrived since the user last typed in or
+// focused it, for `osa status` to show in the multiplexer's status bar.
+
+// openPaneStatus returns the writer of this TUI's status file, nil when
+// pane status is off.
+func openPaneStatus(cfg config.Config) *panestatus.Writer {
+	if cfg.PaneStatus == "off" || (cfg.PaneStatus != "on" && !panestatus.Multiplexed()) {
+		return nil
+	}
+	w, err := panestatus.Open(panestatus.Dir(config.BaseDir()))
+	if err != nil {
+		log.Printf("pane status: %v", err)
+		return nil
+	}
+	return w
+}
+
+// paneStatus returns the status to publish.
+func (m Model) paneStatus() panestatus.Status {
+	state := panestatus.StateIdle
+	switch {
+	case m.guardPending || m.hasModal(StatePlanReview):
+		state = panestatus.StateWaiting
+	case m.base == StateProcessing:
+		state = panestatus.StateWorking
+	}
+	return panestatus.Status{State: state, Unread: m.unread, Session: m.sessionID}
+}
+
+// publishPaneStatus writes the status when it changed, or with force
+// regardless, to show the TUI is still running.
+func (m Model) publishPaneStatus(force bool) {
+	if m.panes == nil {
+		return
+	}
+	if err := m.panes.Publish(m.paneStatus(), force); err != nil {
+		log.Printf("pane status: %v", err)
+	}
+}
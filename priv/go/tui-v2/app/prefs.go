@@ -0,0 +1,114 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+)
+
+// Reply preferences are set once with /prefs instead of being restated in
+// every session: the language replies are written in, how verbose they are
+// and how the agent comments code it writes. They are kept in tui.json for
+// all sessions and sent as metadata with every orchestrate request.
+
+var (
+	replyVerbosities = []string{"concise", "normal", "detailed"}
+	commentStyles    = []string{"none", "brief", "thorough"}
+)
+
+// replyPrefsMetadata returns the preferences for an orchestrate request, nil
+// when none are set.
+func (m Model) replyPrefsMetadata() map[string]string {
+	md := make(map[string]string)
+	if m.config.ReplyLanguage != "" {
+		md["reply_language"] = m.config.ReplyLanguage
+	}
+	if m.config.ReplyVerbosity != "" {
+		md["verbosity"] = m.config.ReplyVerbosity
+	}
+	if m.config.CommentStyle != "" {
+		md["comment_style"] = m.config.CommentStyle
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
+// handlePrefsCommand opens the preferences form, or with "clear" drops them.
+func (m Model) handlePrefsCommand(arg string) (Model, tea.Cmd) {
+	switch arg {
+	case "":
+	case "clear":
+		if m.replyPrefsMetadata() == nil {
+			m.chat.AddSystemMessage(i18n.T("No reply preferences set."))
+			return m, nil
+		}
+		m.setReplyPrefs("", "", "")
+		m.chat.AddSystemMessage(i18n.T("Cleared the reply preferences; the agent picks language, length and comments again"))
+		return m, nil
+	default:
+		m.chat.AddSystemError(i18n.T("Usage: /prefs | /prefs clear"))
+		return m, nil
+	}
+	m.form = dialog.NewForm("prefs", i18n.T("Reply preferences"), []dialog.FormField{
+		{Label: i18n.T("Reply language (e.g. German)"), Value: m.config.ReplyLanguage},
+		{Label: i18n.T("Verbosity (%s)", strings.Join(replyVerbosities, ", ")), Value: m.config.ReplyVerbosity},
+		{Label: i18n.T("Code comments (%s)", strings.Join(commentStyles, ", ")), Value: m.config.CommentStyle},
+	})
+	m.form.SetSize(m.width, m.height)
+	m.pushModal(StateForm)
+	return m, nil
+}
+
+// submitPrefsForm validates and saves the preferences form. Empty fields
+// leave that preference to the agent.
+func (m Model) submitPrefsForm(values []string) (Model, tea.Cmd) {
+	for len(values) < 3 {
+		values = append(values, "")
+	}
+	language := strings.TrimSpace(values[0])
+	verbosity := strings.ToLower(strings.TrimSpace(values[1]))
+	comments := strings.ToLower(strings.TrimSpace(values[2]))
+	switch {
+	case verbosity != "" && !slices.Contains(replyVerbosities, verbosity):
+		m.chat.AddSystemError(i18n.T("Unknown verbosity %q; use %s", verbosity, strings.Join(replyVerbosities, ", ")))
+		return m, m.focusInput()
+	case comments != "" && !slices.Contains(commentStyles, comments):
+		m.chat.AddSystemError(i18n.T("Unknown comment style %q; use %s", comments, strings.Join(commentStyles, ", ")))
+		return m, m.focusInput()
+	}
+	m.setReplyPrefs(language, verbosity, comments)
+	if m.replyPrefsMetadata() == nil {
+		m.chat.AddSystemMessage(i18n.T("No reply preferences set."))
+		return m, m.focusInput()
+	}
+	m.chat.AddSystemMessage(i18n.T("Reply preferences saved for all sessions:") + "\n" + m.describeReplyPrefs())
+	return m, m.focusInput()
+}
+
+// setReplyPrefs stores the preferences and persists them.
+func (m *Model) setReplyPrefs(language, verbosity, comments string) {
+	m.config.ReplyLanguage = language
+	m.config.ReplyVerbosity = verbosity
+	m.config.CommentStyle = comments
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Reply preferences applied but could not persist: %v", err))
+	}
+}
+
+// describeReplyPrefs lists the preferences, "agent's choice" for unset ones.
+func (m Model) describeReplyPrefs() string {
+	or := func(v string) string {
+		if v == "" {
+			return i18n.T("agent's choice")
+		}
+		return v
+	}
+	return i18n.T("  language   %s\n  verbosity  %s\n  comments   %s",
+		or(m.config.ReplyLanguage), or(m.config.ReplyVerbosity), or(m.config.CommentStyle))
+}
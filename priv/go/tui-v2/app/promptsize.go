@@ -0,0 +1,202 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/common"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// A prompt that would not fit in the model's context window, together with
+// the conversation so far and its attached files, is held back at submit
+// instead of failing in the middle of the request. The prompt is measured
+// with the tokenizer count of the draft when it is current, and with the
+// estimate otherwise; the user can send it truncated, compact the
+// conversation first, switch to a model with a larger window, or keep
+// editing.
+
+// promptReplyReserve is the room a prompt must leave in the context window
+// for the reply.
+const promptReplyReserve = 4096
+
+// minTruncatedTokens is the least of the prompt that truncation must keep
+// for it to be offered.
+const minTruncatedTokens = 512
+
+// sessionCompacted is the result of compacting the conversation, to make
+// room for a prompt that is to be sent the way how says, or on request when
+// how is "".
+type sessionCompacted struct {
+	id     string
+	how    string
+	before int
+	after  int
+	window int
+	err    error
+}
+
+// promptSize returns the tokens of text and of the files attached to the
+// input, and the context budget they go into; window is 0 when unknown.
+func (m Model) promptSize(text string) (tokens, attached, used, window int) {
+	tokens = estimateTokens(text)
+	if m.draftCount.text != "" && strings.TrimSpace(m.draftCount.text) == text {
+		tokens = m.draftCount.tokens
+	}
+	used, window = m.input.ContextBudget()
+	return tokens, attachmentTokens(m.input.Attachments()), used, window
+}
+
+// attachmentTokens estimates the tokens of the attached files the agent
+// will read. Images are left out, as their cost does not follow their size.
+func attachmentTokens(paths []string) int {
+	var n int
+	for _, p := range paths {
+		if imageExts[strings.ToLower(filepath.Ext(p))] {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil {
+			n += int((info.Size() + 3) / 4)
+		}
+	}
+	return n
+}
+
+// guardPromptSize holds back a prompt to be sent the given way ("send",
+// "queue" or "now") when it does not fit in the context window, reporting
+// whether it did. Slash commands are never held.
+func (m Model) guardPromptSize(text, how string) (Model, bool) {
+	if strings.HasPrefix(text, "/") {
+		return m, false
+	}
+	tokens, attached, used, window := m.promptSize(text)
+	prompt := tokens + attached
+	if window <= 0 || prompt+used+promptReplyReserve <= window {
+		return m, false
+	}
+
+	m.sizeConfirm = how
+	actions := []chat.ErrorAction{{ID: "size-edit", Label: i18n.T("Keep editing")}}
+	if window-used-promptReplyReserve-attached >= minTruncatedTokens {
+		actions = append(actions, chat.ErrorAction{ID: "size-truncate", Label: i18n.T("Truncate and send")})
+	}
+	if target := window - prompt - promptReplyReserve; m.sessionID != "" && used > target && target >= window/10 {
+		actions = append(actions, chat.ErrorAction{ID: "size-compact", Label: i18n.T("Compact and send")})
+	}
+	actions = append(actions, chat.ErrorAction{ID: "size-models", Label: i18n.T("Switch model")})
+
+	size := common.HumanTokens(prompt) + " tokens"
+	if attached > 0 {
+		size += fmt.Sprintf(" with %s of attached files", common.HumanTokens(attached))
+	}
+	if used > 0 {
+		m.chat.AddSystemConfirm(fmt.Sprintf(
+			"This prompt is about %s, and the conversation already takes %s of the %s-token context window. Together they leave no room for a reply.",
+			size, common.HumanTokens(used), common.HumanTokens(window)), actions)
+	} else {
+		m.chat.AddSystemConfirm(fmt.Sprintf(
+			"This prompt is about %s, more than the %s-token context window leaves room for with a reply.",
+			size, common.HumanTokens(window)), actions)
+	}
+	return m, true
+}
+
+// truncatePrompt sends the start of the input that fits in the context
+// window, the way guardPromptSize was asked to.
+func (m Model) truncatePrompt() (Model, tea.Cmd) {
+	how := m.sizeConfirm
+	m.sizeConfirm = ""
+	text := strings.TrimSpace(m.input.Content())
+	if text == "" {
+		return m, nil
+	}
+	tokens, attached, used, window := m.promptSize(text)
+	budget := window - used - promptReplyReserve - attached
+	if budget < minTruncatedTokens {
+		return m, nil
+	}
+	return m.sendAs(how, truncateText(text, tokens, budget))
+}
+
+// truncateText cuts text of the given tokens down to about budget tokens,
+// at a line break when there is one in the second half, and says so at the
+// end.
+func truncateText(text string, tokens, budget int) string {
+	if tokens <= budget {
+		return text
+	}
+	// The tokens are known for the whole text only; cut by its share of
+	// the bytes, with a margin for text denser than the average.
+	n := int(float64(len(text)) * float64(budget) / float64(tokens) * 0.95)
+	cut := text[:n]
+	if i := strings.LastIndexByte(cut, '\n'); i > n/2 {
+		cut = cut[:i]
+	}
+	cut = strings.ToValidUTF8(cut, "")
+	return fmt.Sprintf("%s\n\n[Truncated to fit the context window: the first %s of %s]",
+		strings.TrimRight(cut, " \t\n"), common.HumanSize(int64(len(cut))), common.HumanSize(int64(len(text))))
+}
+
+// compactForPrompt compacts the conversation down to what leaves room for
+// the input, which is sent once it fits.
+func (m Model) compactForPrompt() (Model, tea.Cmd) {
+	how := m.sizeConfirm
+	m.sizeConfirm = ""
+	tokens, attached, _, window := m.promptSize(strings.TrimSpace(m.input.Content()))
+	target := window - tokens - attached - promptReplyReserve
+	m.toasts.Add(i18n.T("Compacting the conversation..."), toast.ToastInfo)
+	return m, tea.Batch(m.compactSession(target, how), m.tickCmd())
+}
+
+// compactSession compacts the conversation down to target tokens, or to the
+// backend's default when target is 0, for a prompt to be sent the way how
+// says.
+func (m Model) compactSession(target int, how string) tea.Cmd {
+	c, id := m.client, m.sessionID
+	return func() tea.Msg {
+		res, err := c.CompactSession(id, target)
+		if err != nil {
+			return sessionCompacted{id: id, how: how, err: err}
+		}
+		return sessionCompacted{id: id, how: how, before: res.TokensBefore, after: res.TokensAfter, window: res.MaxTokens}
+	}
+}
+
+// handleSessionCompacted reports a compaction, then sends the prompt held
+// for it, or asks again when that still does not fit.
+func (m Model) handleSessionCompacted(v sessionCompacted) (Model, tea.Cmd) {
+	if v.id != m.sessionID {
+		return m, nil
+	}
+	switch {
+	case errors.Is(v.err, client.ErrSessionNotFound):
+		m.chat.AddSystemError(i18n.T("Cannot compact: the backend is not running this session."))
+		return m, nil
+	case v.err != nil:
+		m.chat.AddSystemError(i18n.T("Compaction failed: %v", v.err))
+		return m, nil
+	}
+	_, window := m.input.ContextBudget()
+	if v.window > 0 {
+		window = v.window
+	}
+	m.input.SetContextBudget(v.after, window)
+	m.chat.AddSystemMessage(i18n.T("Compacted the conversation from %s to %s tokens.",
+		common.HumanTokens(v.before), common.HumanTokens(v.after)))
+
+	text := strings.TrimSpace(m.input.Content())
+	if v.how == "" || text == "" {
+		return m, nil
+	}
+	if mm, ok := m.guardPromptSize(text, v.how); ok {
+		return mm, nil
+	}
+	return m.sendAs(v.how, text)
+}
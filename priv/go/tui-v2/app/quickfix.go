@@ -0,0 +1,266 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/diff"
+	"github.com/miosa/osa-tui/ui/pinned"
+)
+
+// A fenced code block in an agent reply whose info string names a file, as
+// in ```go app/main.go, ```go:app/main.go or ```go title="app/main.go",
+// suggests the new content of that file. /apply, or a on a selected reply,
+// pins the diff of what the reply's blocks change and asks to confirm; the
+// files are then written with the backend's file_edit tool, or file_write
+// for new and empty ones. An edit replaces the content the diff was made
+// from, so a file that changed in the meantime is left alone.
+
+// maxQuickFixBytes is the largest file, before or after, that /apply diffs.
+const maxQuickFixBytes = 512 << 10
+
+// quickFixTitle is the title of the pinned diff of the suggested changes.
+const quickFixTitle = "apply"
+
+// quickFix is the content a reply suggests for a file.
+type quickFix struct {
+	path string // as named in the reply
+	abs  string // resolved in the workspace
+	old  string // content the diff was made from; "" for a new file
+	new  string
+}
+
+// quickFixesApplied is the result of writeQuickFixes.
+type quickFixesApplied struct {
+	written []string
+	failed  []string // "path: reason"
+}
+
+// fileBlocks returns the fenced code blocks of md that name a file, the last
+// one for a file named by several. Blocks that are not closed are left out,
+// as they may be cut short.
+func fileBlocks(md string) []quickFix {
+	var out []quickFix
+	index := make(map[string]int)
+	lines := strings.Split(md, "\n")
+	for i := 0; i < len(lines); i++ {
+		l := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(l, "```") && !strings.HasPrefix(l, "~~~") {
+			continue
+		}
+		fence := l[:len(l)-len(strings.TrimLeft(l, l[:1]))]
+		path := fencePath(l[len(fence):])
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if t := strings.TrimSpace(lines[j]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			break
+		}
+		body := strings.Join(lines[i+1:end], "\n")
+		i = end
+		if path == "" || strings.TrimSpace(body) == "" {
+			continue
+		}
+		fix := quickFix{path: path, new: body + "\n"}
+		if n, ok := index[path]; ok {
+			out[n] = fix
+			continue
+		}
+		index[path] = len(out)
+		out = append(out, fix)
+	}
+	return out
+}
+
+// fencePath returns the file named by the info string of a code fence: a
+// path=, file=, filename= or title= attribute, the part after the language
+// in lang:path, or a word that looks like a path.
+func fencePath(info string) string {
+	for i, f := range strings.Fields(info) {
+		if k, v, ok := strings.Cut(f, "="); ok {
+			switch v = strings.Trim(v, `"'`); k {
+			case "path", "file", "filename", "title":
+				if looksLikePath(v) {
+					return v
+				}
+			}
+			continue
+		}
+		if lang, p, ok := strings.Cut(f, ":"); ok && i == 0 && lang != "" && looksLikePath(p) {
+			return p
+		}
+		if looksLikePath(f) {
+			return f
+		}
+	}
+	return ""
+}
+
+// looksLikePath reports whether s has a directory or an extension, and so is
+// not a language name.
+func looksLikePath(s string) bool {
+	if s == "" || strings.Contains(s, "://") {
+		return false
+	}
+	return strings.Contains(s, "/") || filepath.Ext(s) != ""
+}
+
+// handleApplyCommand implements /apply [n], which applies the code blocks of
+// the selected or latest agent reply that name a file, or only the nth.
+func (m Model) handleApplyCommand(arg string) (Model, tea.Cmd) {
+	q, ok := m.chat.SelectedQuote()
+	if !ok || q.Role != chat.RoleAgent {
+		m.chat.AddSystemError(i18n.T("Select an agent reply to apply."))
+		return m, nil
+	}
+	fixes := fileBlocks(q.Content)
+	if len(fixes) == 0 {
+		m.chat.AddSystemMessage(i18n.T("The reply has no code blocks that name a file, such as ```go app/main.go."))
+		return m, nil
+	}
+	if arg != "" {
+		n, err := strconv.Atoi(arg)
+		switch {
+		case err != nil:
+			m.chat.AddSystemError(i18n.T("Usage: /apply [n]"))
+			return m, nil
+		case n < 1 || n > len(fixes):
+			m.chat.AddSystemError(i18n.T("No block %d (the reply names %d files)", n, len(fixes)))
+			return m, nil
+		}
+		fixes = fixes[n-1 : n]
+	}
+	m.chat.ClearSelection()
+	return m.previewQuickFixes(fixes)
+}
+
+// previewQuickFixes pins the diff of fixes against the files as they are now
+// and asks to confirm writing them.
+func (m Model) previewQuickFixes(fixes []quickFix) (Model, tea.Cmd) {
+	var pending []quickFix
+	var diffs, summary []string
+	for _, f := range fixes {
+		f.abs = m.workspacePath(f.path)
+		data, err := os.ReadFile(f.abs)
+		exists := err == nil
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			m.chat.AddSystemError(i18n.T("Cannot read %s: %v", f.path, err))
+			return m, nil
+		case len(data) > maxQuickFixBytes:
+			m.chat.AddSystemError(i18n.T("%s is too large to apply a suggestion to.", f.path))
+			return m, nil
+		default:
+			f.old = string(data)
+		}
+		if len(f.new) > maxQuickFixBytes {
+			m.chat.AddSystemError(i18n.T("The suggestion for %s is too large to apply.", f.path))
+			return m, nil
+		}
+		if exists && f.old == f.new {
+			continue
+		}
+		d := diff.UnifiedDiff(f.path, f.old, f.new)
+		added, removed := diffStat(d)
+		line := fmt.Sprintf("  %s  +%d -%d", f.path, added, removed)
+		if !exists {
+			line += " " + i18n.T("(new file)")
+		}
+		pending = append(pending, f)
+		diffs = append(diffs, d)
+		summary = append(summary, line)
+	}
+	if len(pending) == 0 {
+		m.chat.AddSystemMessage(i18n.T("The files already have the suggested content."))
+		return m, nil
+	}
+
+	m.quickFixes = pending
+	m, _ = m.pin(pinned.KindDiff, quickFixTitle, strings.Join(diffs, ""))
+	files := i18n.T("1 file")
+	if len(pending) > 1 {
+		files = i18n.T("%d files", len(pending))
+	}
+	m.chat.AddSystemConfirm(i18n.T("Apply the suggested changes to %s? The diff is pinned beside the chat.", files)+
+		"\n"+strings.Join(summary, "\n"), []chat.ErrorAction{
+		{ID: "apply-cancel", Label: i18n.T("Cancel")},
+		{ID: "apply-write", Label: i18n.T("Apply")},
+	})
+	return m, nil
+}
+
+// diffStat counts the lines a unified diff adds and removes.
+func diffStat(d string) (added, removed int) {
+	for _, l := range strings.Split(d, "\n") {
+		switch {
+		case strings.HasPrefix(l, "+++") || strings.HasPrefix(l, "---"):
+		case strings.HasPrefix(l, "+"):
+			added++
+		case strings.HasPrefix(l, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// applyQuickFixes writes the confirmed fixes.
+func (m Model) applyQuickFixes() (Model, tea.Cmd) {
+	fixes := m.quickFixes
+	m.quickFixes = nil
+	if len(fixes) == 0 {
+		return m, nil
+	}
+	return m, m.writeQuickFixes(fixes)
+}
+
+// writeQuickFixes writes fixes with the backend's file tools, one at a time.
+func (m Model) writeQuickFixes(fixes []quickFix) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		var r quickFixesApplied
+		for _, f := range fixes {
+			var err error
+			if f.old == "" {
+				_, err = c.ExecuteTool("file_write", map[string]any{"path": f.abs, "content": f.new})
+			} else {
+				_, err = c.ExecuteTool("file_edit", map[string]any{"path": f.abs, "old_string": f.old, "new_string": f.new})
+			}
+			switch {
+			case err != nil && strings.Contains(err.Error(), "old_string not found"):
+				r.failed = append(r.failed, f.path+": "+i18n.T("changed since the diff; run /apply again"))
+			case err != nil:
+				r.failed = append(r.failed, fmt.Sprintf("%s: %v", f.path, err))
+			default:
+				r.written = append(r.written, f.path)
+			}
+		}
+		return r
+	}
+}
+
+// handleQuickFixesApplied reports the files written and those that failed.
+func (m Model) handleQuickFixesApplied(r quickFixesApplied) (Model, tea.Cmd) {
+	if len(r.written) > 0 {
+		m.chat.AddSystemMessage(i18n.T("Applied the suggested changes to %s.", strings.Join(r.written, ", ")))
+	}
+	if len(r.failed) > 0 {
+		m.chat.AddSystemError(i18n.T("Could not apply:") + "\n  " + strings.Join(r.failed, "\n  "))
+	}
+	if len(r.written) == 0 || !m.gitPolling {
+		return m, nil
+	}
+	return m, m.fetchGitStatus(false)
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// When the event stream drops, the backend is polled until it answers
+// again. The session is then checked: a backend that restarted no longer
+// runs it and has lost the agent's context, so the session is recreated
+// under its ID with the latest messages of the conversation shown here,
+// and the stream reopened, with one notice instead of a dead session.
+
+// restoreMessages is how many of the latest messages a restored session
+// is seeded with.
+const restoreMessages = 20
+
+// backendPollInterval is how often the backend is polled while down.
+const backendPollInterval = 5 * time.Second
+
+// backendPoll is the tick to check whether the backend is back.
+type backendPoll struct{}
+
+// backendHealth is the result of a backendPoll health check.
+type backendHealth struct{ err error }
+
+// sessionRecovered is the result of recoverSession: id differs from the
+// session checked when the backend could not restore it and started a new
+// one, and replayed counts the messages a restored session was seeded with.
+type sessionRecovered struct {
+	checked  string
+	id       string
+	restored bool
+	replayed int
+	err      error
+}
+
+// backendLost starts polling the backend, unless it is already.
+func (m *Model) backendLost() tea.Cmd {
+	if m.backendDown {
+		return nil
+	}
+	m.backendDown = true
+	return pollBackend()
+}
+
+func pollBackend() tea.Cmd {
+	return tea.Tick(backendPollInterval, func(time.Time) tea.Msg { return backendPoll{} })
+}
+
+// checkBackend checks the backend's health for a backendPoll.
+func (m Model) checkBackend() tea.Cmd {
+	if !m.backendDown || m.recovering {
+		return nil
+	}
+	c := m.client
+	return func() tea.Msg {
+		_, err := c.Health()
+		return backendHealth{err: err}
+	}
+}
+
+// handleBackendHealth recovers the session once the backend answers, or
+// polls again.
+func (m Model) handleBackendHealth(h backendHealth) (Model, tea.Cmd) {
+	if !m.backendDown || m.recovering {
+		return m, nil
+	}
+	if h.err != nil {
+		return m, pollBackend()
+	}
+	return m.startRecovery()
+}
+
+// startRecovery checks the session of a backend that is back.
+func (m Model) startRecovery() (Model, tea.Cmd) {
+	if m.sessionID == "" {
+		m.backendDown = false
+		return m, nil
+	}
+	m.recovering = true
+	return m, m.recoverSession(m.sessionID, m.restoreHistory())
+}
+
+// recoverSession checks that the backend still runs session id, and
+// recreates it with history when it does not.
+func (m Model) recoverSession(id string, history []client.SessionMessage) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		info, err := c.GetSession(id)
+		switch {
+		case err == nil && info.Alive:
+			return sessionRecovered{checked: id, id: id}
+		case err != nil && !errors.Is(err, client.ErrSessionNotFound):
+			return sessionRecovered{checked: id, err: err}
+		}
+		resp, err := c.RestoreSession(client.SessionRestoreRequest{SessionID: id, Messages: history})
+		if err != nil {
+			return sessionRecovered{checked: id, err: err}
+		}
+		if resp.Status == "exists" {
+			// Started by a prompt sent in the meantime.
+			return sessionRecovered{checked: id, id: id}
+		}
+		return sessionRecovered{checked: id, id: resp.ID, restored: true, replayed: resp.Restored}
+	}
+}
+
+// restoreHistory returns the latest prompts and replies in the chat, for a
+// restored session's context. Slash commands are left out.
+func (m Model) restoreHistory() []client.SessionMessage {
+	var out []client.SessionMessage
+	for _, e := range m.chat.Timeline() {
+		switch {
+		case e.Role == chat.RoleUser && !strings.HasPrefix(e.Text, "/"):
+			out = append(out, client.SessionMessage{Role: "user", Content: e.Text})
+		case e.Role == chat.RoleAgent:
+			out = append(out, client.SessionMessage{Role: "assistant", Content: e.Text})
+		}
+	}
+	if len(out) > restoreMessages {
+		out = out[len(out)-restoreMessages:]
+	}
+	return out
+}
+
+// handleSessionRecovered reopens the stream of the checked or restored
+// session and says what happened.
+func (m Model) handleSessionRecovered(r sessionRecovered) (Model, tea.Cmd) {
+	m.recovering = false
+	if r.err != nil {
+		log.Printf("recover session %s: %v", r.checked, r.err)
+		return m, pollBackend()
+	}
+	m.backendDown = false
+	if r.checked != m.sessionID {
+		// Switched sessions meanwhile; that one has its own stream.
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	switch {
+	case !r.restored:
+		m.toasts.Add(i18n.T("Reconnected"), toast.ToastInfo)
+		cmds = append(cmds, m.tickCmd())
+	case r.id != r.checked:
+		m.sessionID = r.id
+		m.syncSessionModel()
+		m.chat.AddSystemWarning(i18n.T("Backend restarted and could not restore the session; continuing in new session %s without the earlier context.", shortID(r.id)))
+	case r.replayed > 0:
+		m.chat.AddSystemMessage(i18n.T("Backend restarted, session restored (%d messages replayed).", r.replayed))
+	default:
+		m.chat.AddSystemMessage(i18n.T("Backend restarted, session restored."))
+	}
+	if r.restored || m.sse == nil || m.sseReconnecting {
+		m.closeSSE()
+		if cmd := m.startSSE(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
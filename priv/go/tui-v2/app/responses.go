@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/status"
+)
+
+// A turn's answer is delivered twice: in the REST reply to POST /orchestrate
+// and as an agent_response event on the SSE stream, in either order. The
+// response registry renders whichever arrives first and folds the other into
+// it, so an answer is never shown twice and its metadata does not depend on
+// which path won the race.
+
+// Delivery paths of an answer.
+const (
+	viaREST = "rest"
+	viaSSE  = "sse"
+)
+
+// maxTrackedResponses bounds the registry; only the latest requests can
+// still receive a late second delivery.
+const maxTrackedResponses = 16
+
+// delivery is one arrival of a turn's answer.
+type delivery struct {
+	via          string
+	responseType string
+	text         string
+	signal       *chat.Signal
+	durationMs   int64 // server-measured for REST, client-measured for SSE
+	err          error // REST only; failed replies are not registered first
+}
+
+// responseEntry is the registry's record of one request.
+type responseEntry struct {
+	first  delivery
+	itemID string // chat item of the rendered answer; "" for plans
+	second bool   // the other path has arrived too
+}
+
+// responseRegistry tracks the deliveries of recent requests by request ID.
+type responseRegistry struct {
+	entries map[string]*responseEntry
+	order   []string // request IDs, oldest first
+}
+
+func newResponseRegistry() *responseRegistry {
+	return &responseRegistry{entries: make(map[string]*responseEntry)}
+}
+
+// lookup returns the entry of a request whose answer has already arrived.
+func (r *responseRegistry) lookup(requestID string) (*responseEntry, bool) {
+	e, ok := r.entries[requestID]
+	return e, ok
+}
+
+// add records the first delivery of a request, rendered as chat item itemID,
+// and forgets the oldest requests beyond maxTrackedResponses.
+func (r *responseRegistry) add(requestID string, d delivery, itemID string) {
+	if _, ok := r.entries[requestID]; ok {
+		return
+	}
+	r.entries[requestID] = &responseEntry{first: d, itemID: itemID}
+	r.order = append(r.order, requestID)
+	for len(r.order) > maxTrackedResponses {
+		delete(r.entries, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// merge folds the second delivery d into e and returns the answer's metadata
+// as it should now be shown. The REST reply is authoritative whichever came
+// first: its signal and server-side execution time replace the SSE ones, and
+// the SSE values only fill in what REST lacked. Differences between the two
+// deliveries are returned as discrepancies; the rendered text is kept.
+func (e *responseEntry) merge(d delivery) (sig *chat.Signal, durationMs int64, discrepancies []string) {
+	e.second = true
+	rest, sse := e.first, d
+	if d.via == viaREST {
+		rest, sse = d, e.first
+	}
+
+	sig, durationMs = rest.signal, rest.durationMs
+	if sig == nil {
+		sig = sse.signal
+	}
+	if durationMs <= 0 {
+		durationMs = sse.durationMs
+	}
+
+	if rest.err != nil {
+		discrepancies = append(discrepancies, fmt.Sprintf("rest failed while sse answered: %v", rest.err))
+		return sig, durationMs, discrepancies
+	}
+	if rest.responseType != sse.responseType && rest.responseType != "" && sse.responseType != "" {
+		discrepancies = append(discrepancies, fmt.Sprintf("response type: rest %q, sse %q", rest.responseType, sse.responseType))
+	}
+	if strings.TrimSpace(rest.text) != strings.TrimSpace(sse.text) {
+		discrepancies = append(discrepancies, fmt.Sprintf("text: rest %d chars, sse %d chars", len(rest.text), len(sse.text)))
+	}
+	if rest.signal != nil && sse.signal != nil &&
+		(rest.signal.Mode != sse.signal.Mode || rest.signal.Genre != sse.signal.Genre || rest.signal.Type != sse.signal.Type) {
+		discrepancies = append(discrepancies, fmt.Sprintf("signal: rest %s/%s, sse %s/%s",
+			rest.signal.Mode, rest.signal.Genre, sse.signal.Mode, sse.signal.Genre))
+	}
+	return sig, durationMs, discrepancies
+}
+
+// reconcile folds the second delivery of requestID into the rendered answer
+// and logs any discrepancy between the two paths.
+func (m *Model) reconcile(requestID string, e *responseEntry, d delivery) {
+	if e.second {
+		log.Printf("response %s: third delivery via %s ignored", requestID, d.via)
+		return
+	}
+	sig, durationMs, discrepancies := e.merge(d)
+	for _, s := range discrepancies {
+		log.Printf("response %s (first via %s): %s", requestID, e.first.via, s)
+	}
+	if e.itemID == "" {
+		return
+	}
+	m.chat.SetAgentMeta(e.itemID, sig, durationMs)
+	if sig != nil && e.itemID == m.chat.LastAgentID() {
+		m.status.SetSignal(&status.Signal{Mode: sig.Mode, Genre: sig.Genre, Type: sig.Type})
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/dialog"
+)
+
+// The agent roster is fetched on connect and again by /agents, which lists
+// it in a dialog. A prompt starting with "@name" directs it at that agent:
+// the request names it in its agent field, and the mention stays in the
+// text for backends that only read the prompt.
+
+// agentsLoaded carries the roster; open is set when /agents asked for it,
+// with filter as the text given after the command.
+type agentsLoaded struct {
+	agents []client.AgentRole
+	err    error
+	open   bool
+	filter string
+}
+
+// mention matches an "@name" at the start of a prompt.
+var mention = regexp.MustCompile(`^@([A-Za-z0-9][A-Za-z0-9_.-]*)(\s|$)`)
+
+func (m Model) fetchAgents(open bool, filter string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		agents, err := c.ListAgents()
+		return agentsLoaded{agents: agents, err: err, open: open, filter: filter}
+	}
+}
+
+// handleAgentsLoaded stores the roster and, for /agents, opens the dialog.
+// A backend without the roster endpoint gets its /agents command instead.
+func (m Model) handleAgentsLoaded(r agentsLoaded) (Model, tea.Cmd) {
+	if r.err != nil {
+		if !r.open {
+			return m, nil
+		}
+		if errors.Is(r.err, client.ErrNotSupported) {
+			return m, m.executeCommand("agents", r.filter)
+		}
+		m.chat.AddSystemError(i18n.T("Failed to load agents: %v", r.err))
+		return m, nil
+	}
+	m.agentRoles = r.agents
+	if !r.open {
+		return m, nil
+	}
+	if len(r.agents) == 0 {
+		m.chat.AddSystemMessage(i18n.T("The backend reports no agents."))
+		return m, nil
+	}
+	entries := make([]dialog.RosterEntry, len(r.agents))
+	for i, a := range r.agents {
+		entries[i] = dialog.RosterEntry{
+			Name:        a.Name,
+			Tier:        a.Tier,
+			Role:        a.Role,
+			Model:       a.Model,
+			Description: a.Description,
+			Tools:       a.Tools,
+			Triggers:    a.Triggers,
+		}
+	}
+	m.roster.SetEntries(entries, r.filter)
+	m.roster.SetSize(m.width, m.height)
+	m.pushModal(StateRoster)
+	return m, nil
+}
+
+// handleRosterChoice closes the roster and starts the prompt with "@name",
+// replacing a mention the draft already has.
+func (m Model) handleRosterChoice(c dialog.RosterChoice) (Model, tea.Cmd) {
+	m.closeModal(StateRoster)
+	draft := m.input.Value()
+	if m.mentionedAgent(draft) != "" {
+		draft = strings.TrimLeft(mention.ReplaceAllString(draft, ""), " ")
+	}
+	m.input.SetValue("@" + c.Name + " " + draft)
+	return m, m.focusInput()
+}
+
+// mentionedAgent returns the roster agent text starts with a mention of, or
+// "" when it starts with none or with a name the roster does not have.
+func (m Model) mentionedAgent(text string) string {
+	match := mention.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	if !slices.ContainsFunc(m.agentRoles, func(a client.AgentRole) bool { return a.Name == match[1] }) {
+		return ""
+	}
+	return match[1]
+}
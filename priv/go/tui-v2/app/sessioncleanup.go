@@ -0,0 +1,250 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/ui/chat"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /sessions cleanup archives, or with --delete deletes, the sessions last
+// active more than --older-than days ago or with fewer than --fewer-than
+// messages; given both, a session has to meet both. It first lists what it
+// would remove, from a dry run of POST /api/v1/sessions/cleanup, and asks to
+// confirm; only the sessions listed are then removed, and none that started
+// running since. Running sessions are never touched. An archived session's
+// file moves to sessions/archive on the backend, out of /sessions.
+//
+// /sessions cleanup auto sets the same criteria as the profile's archival
+// policy, kept in tui.json and applied each time the TUI connects. It only
+// ever archives.
+
+// sessionCleanupUsage is shown for arguments /sessions cleanup does not take;
+// it is a message ID, translated where it is shown.
+const sessionCleanupUsage = "Usage: /sessions cleanup [--older-than <days>] [--fewer-than <messages>] [--delete]\n" +
+	"       /sessions cleanup auto [--older-than <days>] [--fewer-than <messages>] | off"
+
+// maxCleanupPreview is the most sessions the confirmation lists by name.
+const maxCleanupPreview = 10
+
+// sessionsCleanedUp is the result of a cleanup request: a dry run to confirm,
+// the cleanup itself, or the automatic archival on connecting.
+type sessionsCleanedUp struct {
+	req  client.SessionCleanupRequest
+	resp *client.SessionCleanupResponse
+	err  error
+	auto bool
+}
+
+// parseCleanupArgs reads the criteria of /sessions cleanup, which need at
+// least one of --older-than and --fewer-than.
+func parseCleanupArgs(args []string) (client.SessionCleanupRequest, error) {
+	req := client.SessionCleanupRequest{Action: "archive"}
+	usage := errors.New(i18n.T(sessionCleanupUsage))
+	for i := 0; i < len(args); i++ {
+		switch f := args[i]; f {
+		case "--delete":
+			req.Action = "delete"
+		case "--older-than", "--fewer-than":
+			if i+1 >= len(args) {
+				return req, usage
+			}
+			i++
+			n, err := strconv.Atoi(strings.TrimSuffix(args[i], "d"))
+			if err != nil || n <= 0 {
+				return req, errors.New(i18n.T("%s takes a positive number, not %q", f, args[i]))
+			}
+			if f == "--older-than" {
+				req.OlderThanDays = n
+			} else {
+				req.FewerThanMessages = n
+			}
+		default:
+			return req, usage
+		}
+	}
+	if req.OlderThanDays == 0 && req.FewerThanMessages == 0 {
+		return req, usage
+	}
+	return req, nil
+}
+
+// cleanupCriteria describes the sessions the criteria match, e.g. "inactive
+// for 30+ days and with fewer than 3 messages"; zero leaves a criterion out.
+func cleanupCriteria(days, messages int) string {
+	var parts []string
+	if days > 0 {
+		parts = append(parts, i18n.T("inactive for %d+ days", days))
+	}
+	if messages > 0 {
+		parts = append(parts, i18n.T("with fewer than %d messages", messages))
+	}
+	return strings.Join(parts, i18n.T(" and "))
+}
+
+// handleSessionCleanupCommand implements /sessions cleanup [args].
+func (m Model) handleSessionCleanupCommand(arg string) (Model, tea.Cmd) {
+	args := strings.Fields(arg)
+	if len(args) > 0 && args[0] == "auto" {
+		return m.setArchivePolicy(args[1:])
+	}
+	if len(args) == 0 {
+		policy := i18n.T("No automatic archival is set.")
+		if c := cleanupCriteria(m.config.ArchiveAfterDays, m.config.ArchiveBelowMessages); c != "" {
+			policy = i18n.T("Sessions %s are archived on connecting.", c)
+		}
+		m.chat.AddSystemMessage(policy + "\n" + i18n.T(sessionCleanupUsage))
+		return m, nil
+	}
+	req, err := parseCleanupArgs(args)
+	if err != nil {
+		m.chat.AddSystemError(err.Error())
+		return m, nil
+	}
+	req.DryRun = true
+	m.toasts.Add(i18n.T("Finding sessions to clean up..."), toast.ToastInfo)
+	return m, tea.Batch(m.cleanupSessions(req, false), m.tickCmd())
+}
+
+// setArchivePolicy implements /sessions cleanup auto, setting or, with off,
+// clearing the profile's automatic archival.
+func (m Model) setArchivePolicy(args []string) (Model, tea.Cmd) {
+	var req client.SessionCleanupRequest
+	if len(args) != 1 || args[0] != "off" {
+		var err error
+		if req, err = parseCleanupArgs(args); err != nil {
+			m.chat.AddSystemError(err.Error())
+			return m, nil
+		}
+		if req.Action == "delete" {
+			m.chat.AddSystemError(i18n.T("Automatic cleanup only archives; leave out --delete."))
+			return m, nil
+		}
+	}
+	m.config.ArchiveAfterDays, m.config.ArchiveBelowMessages = req.OlderThanDays, req.FewerThanMessages
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Archival policy set but could not persist: %v", err))
+	}
+	if req.OlderThanDays == 0 && req.FewerThanMessages == 0 {
+		m.toasts.Add(i18n.T("Automatic archival off"), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	m.toasts.Add(i18n.T("Archival policy saved"), toast.ToastInfo)
+	return m, tea.Batch(m.autoArchive(), m.tickCmd())
+}
+
+// autoArchive archives the sessions the profile's policy matches, or
+// returns nil when it has none.
+func (m Model) autoArchive() tea.Cmd {
+	if m.config.ArchiveAfterDays <= 0 && m.config.ArchiveBelowMessages <= 0 {
+		return nil
+	}
+	return m.cleanupSessions(client.SessionCleanupRequest{
+		Action:            "archive",
+		OlderThanDays:     max(m.config.ArchiveAfterDays, 0),
+		FewerThanMessages: max(m.config.ArchiveBelowMessages, 0),
+	}, true)
+}
+
+// cleanupSessions sends req to the backend.
+func (m Model) cleanupSessions(req client.SessionCleanupRequest, auto bool) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		resp, err := c.CleanupSessions(req)
+		return sessionsCleanedUp{req: req, resp: resp, err: err, auto: auto}
+	}
+}
+
+// handleSessionsCleanedUp asks to confirm what a dry run found, or reports
+// what was archived or deleted.
+func (m Model) handleSessionsCleanedUp(r sessionsCleanedUp) (Model, tea.Cmd) {
+	if r.auto {
+		if r.err != nil {
+			log.Printf("automatic session archival: %v", r.err)
+			return m, nil
+		}
+		if r.resp.Count == 0 {
+			return m, nil
+		}
+		m.toasts.Add(i18n.T("Archived %d sessions by the archival policy", r.resp.Count), toast.ToastInfo)
+		return m, m.tickCmd()
+	}
+	if r.err != nil {
+		m.chat.AddSystemError(i18n.T("Session cleanup failed: %v", r.err))
+		return m, nil
+	}
+	criteria := cleanupCriteria(r.req.OlderThanDays, r.req.FewerThanMessages)
+	if !r.req.DryRun {
+		done := i18n.T("Archived %s.", sessionCount(r.resp.Count))
+		if r.req.Action == "delete" {
+			done = i18n.T("Deleted %s.", sessionCount(r.resp.Count))
+		}
+		m.chat.AddSystemMessage(done)
+		return m, nil
+	}
+	if r.resp.Count == 0 {
+		m.chat.AddSystemMessage(i18n.T("No sessions %s.", criteria))
+		return m, nil
+	}
+
+	var sb strings.Builder
+	label := i18n.T("Archive")
+	question := i18n.T("Archive %s %s?", sessionCount(r.resp.Count), criteria)
+	if r.req.Action == "delete" {
+		label = i18n.T("Delete")
+		question = i18n.T("Delete %s %s? Their messages are removed for good.", sessionCount(r.resp.Count), criteria)
+	}
+	sb.WriteString(question)
+	ids := make([]string, 0, len(r.resp.Sessions))
+	for i, s := range r.resp.Sessions {
+		ids = append(ids, s.ID)
+		if i == maxCleanupPreview {
+			sb.WriteString("\n  " + i18n.T("...and %d more", len(r.resp.Sessions)-maxCleanupPreview))
+		}
+		if i >= maxCleanupPreview {
+			continue
+		}
+		title := s.Title
+		if title == "" {
+			title = i18n.T("(untitled)")
+		}
+		last := s.LastActive
+		if len(last) > len("2006-01-02") {
+			last = last[:len("2006-01-02")]
+		}
+		sb.WriteString("\n  " + i18n.T("%s — %s (%d messages, last active %s)", shortID(s.ID), title, s.MessageCount, last))
+	}
+	req := r.req
+	req.DryRun, req.IDs = false, ids
+	m.sessionCleanup = &req
+	m.chat.AddSystemConfirm(sb.String(), []chat.ErrorAction{
+		{ID: "cleanup-cancel", Label: i18n.T("Cancel")},
+		{ID: "cleanup-run", Label: label},
+	})
+	return m, nil
+}
+
+// runSessionCleanup removes the sessions the confirmed dry run listed.
+func (m Model) runSessionCleanup() (Model, tea.Cmd) {
+	req := m.sessionCleanup
+	m.sessionCleanup = nil
+	if req == nil {
+		return m, nil
+	}
+	return m, m.cleanupSessions(*req, false)
+}
+
+// sessionCount is "1 session" or "n sessions".
+func sessionCount(n int) string {
+	if n == 1 {
+		return i18n.T("1 session")
+	}
+	return i18n.T("%d sessions", n)
+}
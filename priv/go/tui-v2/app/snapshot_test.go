@@ -0,0 +1,302 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/msg"
+)
+
+// The snapshot tests drive the root Model through scripted message sequences
+// and compare the rendered frames with golden files in testdata/snapshots.
+// Commands returned by Update are never run, so a script stands in for the
+// backend: it sends the replies those commands would have produced, and
+// stream events replayed from recorded files in testdata/sse. Frames are
+// compared without styling, at each of snapshotSizes.
+//
+// After an intended change to a view, rewrite the golden files and review
+// the diff:
+//
+//	go test ./app -run TestSnapshots -update
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/snapshots")
+
+// snapshotSizes are the terminal sizes every scenario is rendered at: a
+// classic terminal, below the sidebar breakpoint, and a roomy one.
+var snapshotSizes = []struct{ w, h int }{{80, 24}, {120, 36}}
+
+// ---------------------------------------------------------------------------
+// Scripts
+// ---------------------------------------------------------------------------
+
+// step is one entry of a script: a message to send, a reply built from the
+// model's state, such as one echoing its request ID, a change to the model
+// that no message makes, or a frame to capture.
+type step struct {
+	msg   tea.Msg
+	reply func(Model) tea.Msg
+	do    func(*Model)
+	snap  string
+}
+
+func send(msgs ...tea.Msg) []step {
+	steps := make([]step, len(msgs))
+	for i, m := range msgs {
+		steps[i] = step{msg: m}
+	}
+	return steps
+}
+
+// snap captures the frame as name.
+func snap(name string) []step { return []step{{snap: name}} }
+
+// typeText types s into the focused surface one key at a time.
+func typeText(s string) []step {
+	var steps []step
+	for _, r := range s {
+		k := tea.KeyPressMsg{Code: r, Text: string(r)}
+		if r == ' ' {
+			k.Code = tea.KeySpace
+		}
+		steps = append(steps, step{msg: k})
+	}
+	return steps
+}
+
+// press sends a key without text, such as tea.KeyEnter.
+func press(code rune) []step { return send(tea.KeyPressMsg{Code: code}) }
+
+// reply sends the message f builds from the model at that point.
+func reply(f func(Model) tea.Msg) []step { return []step{{reply: f}} }
+
+// elapsed backdates the running turn by d, so durations measured from its
+// start render the same on every run.
+func elapsed(d time.Duration) []step {
+	return []step{{do: func(m *Model) { m.processingStart = time.Now().Add(-d) }}}
+}
+
+// replay sends the events of a recorded stream file in testdata/sse, parsed
+// exactly as the live stream is.
+func replay(t *testing.T, name string) []step {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "sse", name))
+	if err != nil {
+		t.Fatalf("open recorded stream: %v", err)
+	}
+	defer f.Close()
+	var msgs []tea.Msg
+	if err := client.ReadEvents(f, func(m tea.Msg) { msgs = append(msgs, m) }, nil); err != nil {
+		t.Fatalf("read recorded stream %s: %v", name, err)
+	}
+	return send(msgs...)
+}
+
+// connect brings the model from the connecting screen to the idle input, as
+// a healthy backend that needs no onboarding would.
+func connect() []step {
+	return send(
+		msg.HealthResult{Status: "ok", Version: "0.2.5", Provider: "ollama", Model: "qwen3:8b"},
+		bannerTimeout{},
+		msg.OnboardingStatusResult{},
+	)
+}
+
+// script concatenates steps.
+func script(parts ...[]step) []step {
+	var steps []step
+	for _, p := range parts {
+		steps = append(steps, p...)
+	}
+	return steps
+}
+
+// ---------------------------------------------------------------------------
+// Harness
+// ---------------------------------------------------------------------------
+
+// newSnapshotModel returns a model with a fresh profile, English strings and
+// a fixed workspace, sized w×h.
+func newSnapshotModel(t *testing.T, w, h int) Model {
+	t.Helper()
+	ProfileDir = t.TempDir()
+	ThemesDir = filepath.Join(ProfileDir, "themes")
+	LocalesDir = filepath.Join(ProfileDir, "locales")
+	if err := config.Save(ProfileDir, config.Config{Locale: "en"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := New(client.New("http://127.0.0.1:1"))
+	m.header.SetWorkspace("~/src/osa")
+	m.sidebar.SetSessionInfo("", "~/src/osa")
+	mm, _ := m.Update(tea.WindowSizeMsg{Width: w, Height: h})
+	return mm.(Model)
+}
+
+// run plays steps against m and returns the captured frames, in order.
+func run(t *testing.T, m Model, steps []step) []string {
+	t.Helper()
+	var frames []string
+	for _, s := range steps {
+		switch {
+		case s.snap != "":
+			frames = append(frames, fmt.Sprintf("── %s ──\n%s", s.snap, normalizeFrame(m.View().Content)))
+		case s.do != nil:
+			s.do(&m)
+		default:
+			next := s.msg
+			if s.reply != nil {
+				next = s.reply(m)
+			}
+			mm, _ := m.Update(next)
+			m = mm.(Model)
+			if m.sessionID != "" {
+				m.sessionID = "snapshot" // health picks a random one
+			}
+		}
+	}
+	return frames
+}
+
+// volatile masks the parts of a frame that differ between runs by design:
+// the activity line starts with a phrase picked at random, and its length
+// decides how much of the line's figures fit.
+var volatile = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	{regexp.MustCompile(`^(\s*⏺ ).*`), "${1}<activity>"},
+}
+
+// normalizeFrame strips styling, trailing blanks and volatile text, leaving
+// what a reader of the golden file needs to see.
+func normalizeFrame(frame string) string {
+	lines := strings.Split(ansi.Strip(frame), "\n")
+	for i, l := range lines {
+		for _, v := range volatile {
+			l = v.re.ReplaceAllString(l, v.with)
+		}
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// checkGolden compares got with testdata/snapshots/name.golden, or rewrites
+// the file with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "snapshots", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("frames differ from %s (run with -update if the change is intended):\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+// lineDiff lists the lines that differ between want and got.
+func lineDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var sb strings.Builder
+	for i := 0; i < max(len(w), len(g)); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			fmt.Fprintf(&sb, "line %d:\n  want %q\n  got  %q\n", i+1, wl, gl)
+		}
+	}
+	return sb.String()
+}
+
+// ---------------------------------------------------------------------------
+// Scenarios
+// ---------------------------------------------------------------------------
+
+func TestSnapshots(t *testing.T) {
+	scenarios := []struct {
+		name   string
+		script func(t *testing.T) []step
+	}{
+		{"startup", func(t *testing.T) []step {
+			return script(
+				snap("connecting"),
+				send(msg.HealthResult{Status: "ok", Version: "0.2.5", Provider: "ollama", Model: "qwen3:8b"}),
+				snap("banner"),
+				send(bannerTimeout{}, msg.OnboardingStatusResult{}),
+				snap("idle"),
+			)
+		}},
+		{"streamed_turn", func(t *testing.T) []step {
+			return script(
+				connect(),
+				typeText("list the go files"),
+				press(tea.KeyEnter),
+				elapsed(2*time.Second),
+				snap("submitted"),
+				replay(t, "tool_turn.sse"),
+				snap("answered"),
+			)
+		}},
+		{"rest_error", func(t *testing.T) []step {
+			return script(
+				connect(),
+				typeText("hello"),
+				press(tea.KeyEnter),
+				reply(func(m Model) tea.Msg {
+					return msg.OrchestrateResult{RequestID: m.requestID, Err: fmt.Errorf("backend returned 500")}
+				}),
+				snap("failed"),
+			)
+		}},
+		{"sidebar", func(t *testing.T) []step {
+			return script(
+				connect(),
+				send(msg.ToggleSidebar{}),
+				snap("open"),
+			)
+		}},
+		{"help", func(t *testing.T) []step {
+			return script(
+				connect(),
+				typeText("/help"),
+				press(tea.KeyEnter),
+				snap("help"),
+			)
+		}},
+	}
+
+	for _, sc := range scenarios {
+		for _, size := range snapshotSizes {
+			name := fmt.Sprintf("%s_%dx%d", sc.name, size.w, size.h)
+			t.Run(name, func(t *testing.T) {
+				m := newSnapshotModel(t, size.w, size.h)
+				frames := run(t, m, sc.script(t))
+				checkGolden(t, name, strings.Join(frames, "\n"))
+			})
+		}
+	}
+}
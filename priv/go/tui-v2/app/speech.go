@@ -0,0 +1,130 @@
+package app
+
+import (
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/speech"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// /speak reads agent replies aloud with a local text-to-speech engine, a
+// sentence at a time as they stream in. The toggle is kept in tui.json and
+// speech_command replaces the detected engine. Cancelling a turn or
+// "/speak stop" cuts off the reply being read.
+
+// loadSpeaker starts the speaker when cfg turns speech on.
+func loadSpeaker(cfg config.Config) (*speech.Speaker, error) {
+	if !cfg.Speak {
+		return nil, nil
+	}
+	e, err := speech.Detect(cfg.SpeechCommand)
+	if err != nil {
+		return nil, err
+	}
+	return speech.NewSpeaker(e), nil
+}
+
+// closeSpeaker stops speaking for good.
+func (m *Model) closeSpeaker() {
+	if m.speaker != nil {
+		m.speaker.Close()
+		m.speaker = nil
+	}
+	m.beginSpeechTurn()
+}
+
+// beginSpeechTurn readies speech for the reply of a new turn.
+func (m *Model) beginSpeechTurn() {
+	m.speechSplit = speech.Splitter{}
+	m.speechStreamed, m.speechMuted = false, false
+}
+
+// cutSpeech stops reading: the sentence being spoken, the queued ones and
+// what is still to stream of the reply.
+func (m *Model) cutSpeech() {
+	if m.speaker == nil {
+		return
+	}
+	m.speaker.Stop()
+	m.speechSplit = speech.Splitter{}
+	m.speechMuted = true
+}
+
+// speakDelta speaks the sentences streamed reply text completes.
+func (m *Model) speakDelta(text string) {
+	if m.speaker == nil || m.speechMuted {
+		return
+	}
+	for _, s := range m.speechSplit.Feed(text) {
+		m.speaker.Say(s)
+	}
+	m.speechStreamed = true
+}
+
+// speakReply speaks the rest of a finished reply: what streamed after its
+// last full sentence, or all of it when none of it streamed. An engine that
+// failed on the previous reply stops speech.
+func (m *Model) speakReply(text string) {
+	if m.speaker == nil {
+		return
+	}
+	if err := m.speaker.Err(); err != nil {
+		name := m.speaker.Engine().Name
+		m.closeSpeaker()
+		m.chat.AddSystemWarning(i18n.T("Stopped reading replies aloud: %s failed: %v", name, err))
+		return
+	}
+	sentences := m.speechSplit.Flush()
+	if !m.speechStreamed {
+		sentences = speech.Split(text)
+	}
+	if !m.speechMuted {
+		for _, s := range sentences {
+			m.speaker.Say(s)
+		}
+	}
+	m.speechStreamed, m.speechMuted = false, false
+}
+
+// handleSpeakCommand turns reading replies aloud on or off, toggling it
+// without an argument, or with "stop" cuts off the current reply.
+func (m Model) handleSpeakCommand(arg string) (Model, tea.Cmd) {
+	if arg == "" {
+		arg = "on"
+		if m.speaker != nil {
+			arg = "off"
+		}
+	}
+	switch arg {
+	case "stop":
+		if m.speaker == nil {
+			m.chat.AddSystemMessage(i18n.T("Replies are not being read aloud. Use /speak to start."))
+			return m, nil
+		}
+		m.cutSpeech()
+		return m, nil
+	case "on":
+		if m.speaker == nil {
+			e, err := speech.Detect(m.config.SpeechCommand)
+			if err != nil {
+				m.chat.AddSystemError(i18n.T("Cannot read replies aloud: %v", err))
+				return m, nil
+			}
+			m.speaker = speech.NewSpeaker(e)
+		}
+		m.toasts.Add(i18n.T("Reading replies aloud with %s", m.speaker.Engine().Name), toast.ToastInfo)
+	case "off":
+		m.closeSpeaker()
+		m.toasts.Add(i18n.T("Stopped reading replies aloud"), toast.ToastInfo)
+	default:
+		m.chat.AddSystemError(i18n.T("Usage: /speak [on|off|stop]"))
+		return m, nil
+	}
+	m.config.Speak = arg == "on"
+	if err := config.Save(profileDirPath(), m.config); err != nil {
+		m.chat.AddSystemWarning(i18n.T("Speech set but could not persist: %v", err))
+	}
+	return m, m.tickCmd()
+}
@@ -1,5 +1,11 @@
 package app
 
+import (
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+)
+
 // State represents the current application state.
 type State int
 
@@ -16,6 +22,20 @@ const (
 	StateSessions                 // Session browser dialog
 	StateModels                   // Enhanced model picker dialog
 	StateOnboarding               // First-run onboarding wizard
+	StateKeys                     // Provider API key manager
+	StateForm                     // Form dialog (prompt template placeholders)
+	StateSchedule                 // Scheduled prompts manager
+	StateTimeline                 // Session timeline overlay
+	StateThemePicker              // Theme picker with live preview
+	StateRoster                   // Agent roster browser
+	StateSwarmWizard              // Swarm launch wizard
+	StateMemory                   // Memory browser
+	StateHooks                    // Hook pipeline view
+	StateTools                    // Tool catalog
+	StateAudit                    // Tool call audit log
+	StateMCP                      // MCP server manager
+	StateChannels                 // Messaging channels settings
+	StateBudget                   // Spend budget limits
 )
 
 func (s State) String() string {
@@ -44,7 +64,98 @@ func (s State) String() string {
 		return "models"
 	case StateOnboarding:
 		return "onboarding"
+	case StateKeys:
+		return "keys"
+	case StateForm:
+		return "form"
+	case StateSchedule:
+		return "schedule"
+	case StateTimeline:
+		return "timeline"
+	case StateThemePicker:
+		return "theme_picker"
+	case StateRoster:
+		return "roster"
+	case StateSwarmWizard:
+		return "swarm_wizard"
+	case StateMemory:
+		return "memory"
+	case StateHooks:
+		return "hooks"
+	case StateTools:
+		return "tools"
+	case StateAudit:
+		return "audit"
+	case StateMCP:
+		return "mcp"
+	case StateChannels:
+		return "channels"
+	case StateBudget:
+		return "budget"
 	default:
 		return "unknown"
 	}
 }
+
+// The screen is a stack of surfaces: a base state — connecting, banner, idle
+// or processing — with modal states such as the palette or a picker opened
+// above it. m.state is the surface that receives keys, the top modal or else
+// the base. Agent turns move the base and dialogs push and pop modals, so a
+// turn that ends under a dialog leaves the dialog open, and closing a dialog
+// returns to whatever was below it, which may be a running turn.
+
+// modal reports whether s is opened above a base state.
+func (s State) modal() bool {
+	switch s {
+	case StateConnecting, StateBanner, StateIdle, StateProcessing:
+		return false
+	}
+	return true
+}
+
+// setBase changes the base state under any open modals.
+func (m *Model) setBase(s State) {
+	m.base = s
+	m.syncState()
+}
+
+// pushModal opens s on top. A modal already open, such as the models dialog
+// receiving a second model list, moves to the top instead of stacking twice.
+func (m *Model) pushModal(s State) {
+	m.modals = slices.DeleteFunc(m.modals, func(o State) bool { return o == s })
+	m.modals = append(m.modals, s)
+	m.input.Blur()
+	m.syncState()
+}
+
+// closeModal closes s wherever it is in the stack.
+func (m *Model) closeModal(s State) {
+	m.modals = slices.DeleteFunc(m.modals, func(o State) bool { return o == s })
+	m.syncState()
+}
+
+// closeModals closes every modal, as when the profile changes.
+func (m *Model) closeModals() {
+	m.modals = nil
+	m.syncState()
+}
+
+// hasModal reports whether s is open, on top or below another modal.
+func (m Model) hasModal(s State) bool { return slices.Contains(m.modals, s) }
+
+// focusInput focuses the input unless a modal still covers it.
+func (m *Model) focusInput() tea.Cmd {
+	if len(m.modals) > 0 {
+		return nil
+	}
+	return m.input.Focus()
+}
+
+// syncState points m.state at the top surface.
+func (m *Model) syncState() {
+	if n := len(m.modals); n > 0 {
+		m.state = m.modals[n-1]
+		return
+	}
+	m.state = m.base
+}
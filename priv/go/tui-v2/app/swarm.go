@@ -0,0 +1,139 @@
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/msg"
+	"github.com/miosa/osa-tui/ui/dialog"
+	"github.com/miosa/osa-tui/ui/toast"
+)
+
+// "/swarm new" opens a wizard for POST /api/v1/swarm/launch. While the
+// swarm runs the TUI is processing, as for a prompt, and the agents panel
+// follows the swarm's swarm_agent_* events; the swarm lifecycle handlers in
+// app.go end the turn. Other /swarm subcommands go to the backend.
+
+// swarmLaunched carries the backend's reply to a launch.
+type swarmLaunched struct {
+	resp *client.SwarmLaunchResponse
+	err  error
+}
+
+// swarmCancelled reports the outcome of cancelling a swarm.
+type swarmCancelled struct {
+	id  string
+	err error
+}
+
+// openSwarmWizard opens the launch wizard, with task prefilled.
+func (m Model) openSwarmWizard(task string) (Model, tea.Cmd) {
+	if m.base == StateProcessing {
+		m.chat.AddSystemWarning(i18n.T("Wait for the current request to finish before launching a swarm."))
+		return m, nil
+	}
+	m.swarmWizard = dialog.NewSwarmWizard(task)
+	m.swarmWizard.SetSize(m.width, m.height)
+	m.pushModal(StateSwarmWizard)
+	return m, nil
+}
+
+// handleSwarmLaunch submits the wizard's swarm and switches to the
+// processing view, with the agents panel waiting for the swarm's workers.
+func (m Model) handleSwarmLaunch(l dialog.SwarmLaunch) (Model, tea.Cmd) {
+	m.closeModal(StateSwarmWizard)
+	m.activity.Reset()
+	m.activity.Start()
+	m.agents.Reset()
+	m.agents.Start()
+	m.tasks.Reset()
+	m.requestID = ""
+	m.swarmID, m.swarmLaunching, m.swarmAbandon = "", true, false
+	m.setBase(StateProcessing)
+	m.processingStart = time.Now()
+	m.status.SetActive(true)
+	m.chat.SetProcessingView(m.activity.View())
+	m.toasts.Add(i18n.T("Launching swarm..."), toast.ToastInfo)
+
+	c, req := m.client, client.SwarmLaunchRequest{
+		Task:      l.Task,
+		Pattern:   l.Pattern,
+		MaxAgents: l.MaxAgents,
+		TimeoutMs: l.TimeoutMs,
+		SessionID: m.sessionID,
+	}
+	launch := func() tea.Msg {
+		resp, err := c.LaunchSwarm(req)
+		return swarmLaunched{resp: resp, err: err}
+	}
+	return m, tea.Batch(launch, m.tickCmd())
+}
+
+// handleSwarmLaunched records the swarm the agents panel follows, or ends
+// the turn when the launch failed.
+func (m Model) handleSwarmLaunched(r swarmLaunched) (Model, tea.Cmd) {
+	if m.swarmAbandon {
+		m.swarmAbandon = false
+		if r.err == nil {
+			return m, m.cancelSwarm(r.resp.SwarmID)
+		}
+		return m, nil
+	}
+	if !m.swarmLaunching {
+		return m, nil
+	}
+	m.swarmLaunching = false
+	if r.err != nil {
+		m.activity.Stop()
+		m.chat.ClearProcessingView()
+		m.status.SetActive(false)
+		m.setBase(StateIdle)
+		m.chat.AddSystemError(i18n.T("Failed to launch swarm: %v", r.err))
+		return m, m.focusInput()
+	}
+	m.swarmID = r.resp.SwarmID
+	return m, nil
+}
+
+// handleSwarmAgentStarted adds a worker of the swarm launched here to the
+// agents panel, with the start of its subtask as its action. Its name
+// already leads with the role, so the role is not repeated. Other swarms
+// only report their lifecycle.
+func (m Model) handleSwarmAgentStarted(v client.SwarmAgentStartedEvent) (Model, tea.Cmd) {
+	if v.SwarmID == "" || v.SwarmID != m.swarmID {
+		return m, nil
+	}
+	m.agents, _ = m.agents.Update(msg.OrchestratorAgentStarted{AgentName: v.AgentName, Model: v.Model})
+	m.agents, _ = m.agents.Update(msg.OrchestratorAgentProgress{AgentName: v.AgentName, CurrentAction: v.CurrentAction})
+	return m, nil
+}
+
+// handleSwarmAgentCompleted marks a worker of the swarm launched here done
+// or failed.
+func (m Model) handleSwarmAgentCompleted(v client.SwarmAgentCompletedEvent) (Model, tea.Cmd) {
+	if v.SwarmID == "" || v.SwarmID != m.swarmID {
+		return m, nil
+	}
+	if v.Status == "failed" {
+		m.agents, _ = m.agents.Update(msg.OrchestratorAgentFailed{AgentName: v.AgentName, Error: v.Error, TokensUsed: v.TokensUsed})
+	} else {
+		m.agents, _ = m.agents.Update(msg.OrchestratorAgentCompleted{AgentName: v.AgentName, TokensUsed: v.TokensUsed})
+	}
+	return m, nil
+}
+
+// swarmEnded forgets the swarm the agents panel follows when id ends.
+func (m *Model) swarmEnded(id string) {
+	if id == m.swarmID {
+		m.swarmID, m.swarmLaunching = "", false
+	}
+}
+
+func (m Model) cancelSwarm(id string) tea.Cmd {
+	c := m.client
+	return func() tea.Msg {
+		return swarmCancelled{id: id, err: c.CancelSwarm(id)}
+	}
+}
@@ -0,0 +1,328 @@
+// Package bundle moves a profile's settings between machines as one zip
+// archive, for `osa config export` and `osa config import`.
+//
+// An archive holds a manifest, the profile's tui.json with the state that
+// only means something on the machine it came from taken out, along with the
+// backend URL, the destructive-call guard and the speech command, the custom
+// themes and the prompt templates. Aliases and snippets are part of
+// tui.json. Credentials are kept in files of their own and are never
+// bundled, and neither are the per-session overrides, whose /env values
+// may hold secrets. Importing merges the settings into the profile's
+// tui.json and adds the themes and templates, leaving files that differ
+// from the archive alone unless asked to replace them.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/prompts"
+)
+
+// ManifestName is the file that marks an archive as a bundle.
+const ManifestName = "osa-config.json"
+
+// Format is the version of the archive layout written by Export.
+const Format = 1
+
+// maxFileBytes is the largest file Import reads from an archive.
+const maxFileBytes = 1 << 20
+
+// Archive entries besides the manifest.
+const (
+	configEntry  = "tui.json"
+	themesEntry  = "themes/"
+	promptsEntry = "prompts/"
+)
+
+var (
+	themeExts   = []string{".json", ".toml"}
+	promptExts  = []string{".md", ".txt"}
+	themeFileRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// Dirs are the directories a bundle is made from or imported into.
+type Dirs struct {
+	Profile string // holds tui.json
+	Themes  string
+	Prompts string
+}
+
+// Manifest describes an archive.
+type Manifest struct {
+	Format  int       `json:"format"`
+	Version string    `json:"version"` // of the osa that wrote it
+	Profile string    `json:"profile"`
+	Created time.Time `json:"created"`
+}
+
+// Result lists the files an Import wrote, left as they were because they
+// already held the same content, or skipped because they differ.
+type Result struct {
+	Written   []string
+	Unchanged []string
+	Skipped   []string
+}
+
+// Portable returns cfg without what is tied to this machine: the models,
+// overrides and recent commands of its sessions, the recent models and the
+// banner's rotation. Nor does it keep the settings an archive must not
+// change behind the user's back: the backend URL, the destructive-call
+// guard and its patterns, and the speech command, which is run as given.
+func Portable(cfg config.Config) config.Config {
+	cfg.BackendURL = ""
+	cfg.DestructiveGuard, cfg.DestructivePatterns = "", nil
+	cfg.SpeechCommand = nil
+	cfg.SessionModels = nil
+	cfg.SessionOverrides = nil
+	cfg.RecentModels = nil
+	cfg.RecentCommands = nil
+	cfg.TipIndex, cfg.LastVersion = 0, ""
+	return cfg
+}
+
+// Export writes the bundle of dirs to w as a zip archive and returns the
+// names of its entries.
+func Export(w io.Writer, dirs Dirs, m Manifest) ([]string, error) {
+	m.Format = Format
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := json.MarshalIndent(Portable(config.Load(dirs.Profile)), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	zw := zip.NewWriter(w)
+	var names []string
+	add := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: m.Created})
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	}
+	if err := add(ManifestName, manifest); err != nil {
+		return nil, err
+	}
+	if err := add(configEntry, cfg); err != nil {
+		return nil, err
+	}
+	for _, d := range []struct {
+		entry, dir string
+		exts       []string
+		nested     bool
+	}{
+		{themesEntry, dirs.Themes, themeExts, false},
+		{promptsEntry, dirs.Prompts, promptExts, true},
+	} {
+		files, err := listFiles(d.dir, d.exts, d.nested)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range files {
+			data, err := os.ReadFile(filepath.Join(d.dir, filepath.FromSlash(rel)))
+			if err != nil {
+				return nil, err
+			}
+			if err := add(d.entry+rel, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// listFiles returns the files in dir with one of exts, as slash-separated
+// paths relative to it, descending into subdirectories when nested. A
+// missing dir has none.
+func listFiles(dir string, exts []string, nested bool) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && !nested {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !slices.Contains(exts, strings.ToLower(filepath.Ext(p))) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		out = append(out, filepath.ToSlash(rel))
+		return nil
+	})
+	return out, err
+}
+
+// Import reads the archive at file into dirs. The settings it holds replace
+// the ones of the profile they set; themes and templates are added, and
+// one that exists with other content is skipped unless replace is set.
+// Nothing is written when the archive is not a bundle or holds a file that
+// does not belong in one.
+func Import(file string, dirs Dirs, replace bool) (Result, error) {
+	var res Result
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return res, err
+	}
+	defer zr.Close()
+
+	entries := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if !validEntry(f.Name) {
+			return res, fmt.Errorf("%s: unexpected file %q", file, f.Name)
+		}
+		data, err := readEntry(f)
+		if err != nil {
+			return res, fmt.Errorf("%s: %s: %w", file, f.Name, err)
+		}
+		entries[f.Name] = data
+	}
+
+	data, ok := entries[ManifestName]
+	if !ok {
+		return res, fmt.Errorf("%s is not an osa config archive (no %s)", file, ManifestName)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return res, fmt.Errorf("%s: %s: %w", file, ManifestName, err)
+	}
+	if m.Format > Format {
+		return res, fmt.Errorf("%s was made by a newer osa (format %d); upgrade to import it", file, m.Format)
+	}
+
+	var cfg config.Config
+	if data, ok := entries[configEntry]; ok {
+		if err := mergeConfig(data, dirs.Profile, &cfg); err != nil {
+			return res, fmt.Errorf("%s: %s: %w", file, configEntry, err)
+		}
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		var dest string
+		switch {
+		case name == configEntry:
+			if err := config.Save(dirs.Profile, cfg); err != nil {
+				return res, err
+			}
+			res.Written = append(res.Written, name)
+			continue
+		case strings.HasPrefix(name, themesEntry):
+			dest = filepath.Join(dirs.Themes, strings.TrimPrefix(name, themesEntry))
+		case strings.HasPrefix(name, promptsEntry):
+			dest = filepath.Join(dirs.Prompts, filepath.FromSlash(strings.TrimPrefix(name, promptsEntry)))
+		default:
+			continue
+		}
+		old, err := os.ReadFile(dest)
+		switch {
+		case err == nil && bytes.Equal(old, entries[name]):
+			res.Unchanged = append(res.Unchanged, name)
+			continue
+		case err == nil && !replace:
+			res.Skipped = append(res.Skipped, name)
+			continue
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return res, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return res, err
+		}
+		if err := os.WriteFile(dest, entries[name], 0o644); err != nil {
+			return res, err
+		}
+		res.Written = append(res.Written, name)
+	}
+	return res, nil
+}
+
+// validEntry reports whether name belongs in a bundle: the manifest,
+// tui.json, a theme file or a template whose name prompts accepts.
+func validEntry(name string) bool {
+	switch {
+	case name == ManifestName || name == configEntry:
+		return true
+	case strings.HasPrefix(name, themesEntry):
+		base := strings.TrimPrefix(name, themesEntry)
+		return themeFileRe.MatchString(base) && slices.Contains(themeExts, strings.ToLower(path.Ext(base)))
+	case strings.HasPrefix(name, promptsEntry):
+		rel := strings.TrimPrefix(name, promptsEntry)
+		ext := path.Ext(rel)
+		return slices.Contains(promptExts, strings.ToLower(ext)) && prompts.ValidateName(strings.TrimSuffix(rel, ext)) == nil
+	}
+	return false
+}
+
+// readEntry returns the content of f, refusing one over maxFileBytes.
+func readEntry(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxFileBytes {
+		return nil, fmt.Errorf("larger than %d bytes", maxFileBytes)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileBytes {
+		return nil, fmt.Errorf("larger than %d bytes", maxFileBytes)
+	}
+	return data, nil
+}
+
+// mergeConfig sets cfg to the profile's settings with the portable ones of
+// data laid over them.
+func mergeConfig(data []byte, profileDir string, cfg *config.Config) error {
+	var imported config.Config
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return err
+	}
+	portable, err := json.Marshal(Portable(imported))
+	if err != nil {
+		return err
+	}
+	*cfg = config.Load(profileDir)
+	return json.Unmarshal(portable, cfg)
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/colorprofile"
+
+	"github.com/miosa/osa-tui/app"
+	"github.com/miosa/osa-tui/bundle"
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/doctor"
+	"github.com/miosa/osa-tui/panestatus"
+	"github.com/miosa/osa-tui/prompts"
+	"github.com/miosa/osa-tui/style"
+)
+
+// subcommand is a non-interactive `osa <name>` command. Subcommands run
+// after flag parsing and exit without starting the TUI.
+type subcommand struct {
+	name    string
+	args    string   // argument synopsis for help and man output
+	choices []string // completions for the first argument
+	short   string
+	run     func(args []string) error
+}
+
+// subcommands lists the available subcommands in display order.
+// It is populated in init to break the reference cycle with runManPage.
+var subcommands []subcommand
+
+func init() {
+	subcommands = []subcommand{
+		{"completion", "bash|zsh|fish", []string{"bash", "zsh", "fish"}, "Print a shell completion script", runCompletion},
+		{"config", "export|import [file]", []string{"export", "import"}, "Export or import the profile's settings, themes and prompts", runConfig},
+		{"doctor", "", nil, "Check the backend, auth, sidecars, terminal and config", runDoctor},
+		{"man", "", nil, "Print the osa(1) man page in roff format", runManPage},
+		{"profile", "list|create|delete [name]", []string{"list", "create", "delete"}, "Manage named profiles", runProfile},
+		{"status", "[--pane ID] [--json]", nil, "Print what running TUIs are doing, for a tmux or zellij status bar", runStatus},
+	}
+}
+
+// runSubcommand dispatches args[0] to its subcommand. ok is false when
+// args[0] is not a known subcommand.
+func runSubcommand(args []string) (ok bool, err error) {
+	for _, sc := range subcommands {
+		if sc.name == args[0] {
+			return true, sc.run(args[1:])
+		}
+	}
+	return false, nil
+}
+
+// usage prints the flag defaults followed by the subcommands.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: osa [options] [prompt]\n       osa <command> [args]\n\nOptions:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nCommands:\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(out, "  %-26s %s\n", strings.TrimSpace(sc.name+" "+sc.args), sc.short)
+	}
+}
+
+// flagInfo describes one registered command-line flag.
+type flagInfo struct {
+	name   string
+	usage  string
+	isBool bool
+	values []string // fixed value choices, if any
+}
+
+// flagValues lists fixed choices for flags whose values can be completed.
+var flagValues = map[string][]string{
+	"colors": {"auto", "truecolor", "256", "16"},
+}
+
+// flags returns the registered flags sorted by name. Single-letter aliases
+// are listed with their long form.
+func flags() []flagInfo {
+	var out []flagInfo
+	flag.VisitAll(func(f *flag.Flag) {
+		bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+		out = append(out, flagInfo{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && bf.IsBoolFlag(),
+			values: flagValues[f.Name],
+		})
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// dashed returns the flag as typed on the command line.
+func (f flagInfo) dashed() string {
+	if len(f.name) == 1 {
+		return "-" + f.name
+	}
+	return "--" + f.name
+}
+
+// -- completion ---------------------------------------------------------------
+
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: osa completion bash|zsh|fish")
+	}
+	switch args[0] {
+	case "bash":
+		writeBashCompletion(os.Stdout)
+	case "zsh":
+		writeZshCompletion(os.Stdout)
+	case "fish":
+		writeFishCompletion(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q (want bash, zsh or fish)", args[0])
+	}
+	return nil
+}
+
+func subcommandNames() []string {
+	names := make([]string, len(subcommands))
+	for i, sc := range subcommands {
+		names[i] = sc.name
+	}
+	return names
+}
+
+func writeBashCompletion(w io.Writer) {
+	var opts []string
+	for _, f := range flags() {
+		opts = append(opts, f.dashed())
+	}
+
+	fmt.Fprintf(w, "# bash completion for osa\n")
+	fmt.Fprintf(w, "_osa() {\n")
+	fmt.Fprintf(w, "    local cur prev\n")
+	fmt.Fprintf(w, "    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(w, "    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	fmt.Fprintf(w, "    case \"$prev\" in\n")
+	fmt.Fprintf(w, "        --profile)\n")
+	fmt.Fprintf(w, "            COMPREPLY=($(compgen -W \"$(ls ~/.osa/profiles 2>/dev/null)\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "            return ;;\n")
+	for _, f := range flags() {
+		if len(f.values) > 0 {
+			fmt.Fprintf(w, "        %s)\n", f.dashed())
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(f.values, " "))
+			fmt.Fprintf(w, "            return ;;\n")
+		}
+	}
+	for _, sc := range subcommands {
+		if len(sc.choices) > 0 {
+			fmt.Fprintf(w, "        %s)\n", sc.name)
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(sc.choices, " "))
+			fmt.Fprintf(w, "            return ;;\n")
+		}
+	}
+	fmt.Fprintf(w, "        delete)\n")
+	fmt.Fprintf(w, "            [[ \"${COMP_WORDS[COMP_CWORD-2]}\" == profile ]] &&\n")
+	fmt.Fprintf(w, "                COMPREPLY=($(compgen -W \"$(ls ~/.osa/profiles 2>/dev/null)\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "            return ;;\n")
+	fmt.Fprintf(w, "    esac\n\n")
+	fmt.Fprintf(w, "    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(opts, " "))
+	fmt.Fprintf(w, "    else\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(subcommandNames(), " "))
+	fmt.Fprintf(w, "    fi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -F _osa osa\n")
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprintf(w, "#compdef osa\n\n")
+	fmt.Fprintf(w, "_osa() {\n")
+	fmt.Fprintf(w, "    local -a subcmds\n")
+	fmt.Fprintf(w, "    subcmds=(\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(w, "        '%s:%s'\n", sc.name, zshEscape(sc.short))
+	}
+	fmt.Fprintf(w, "    )\n\n")
+	fmt.Fprintf(w, "    _arguments -C \\\n")
+	for _, f := range flags() {
+		spec := fmt.Sprintf("'%s[%s]'", f.dashed(), zshEscape(f.usage))
+		switch {
+		case f.name == "profile":
+			spec = fmt.Sprintf("'%s=[%s]:profile:_files -W ~/.osa/profiles -/'", f.dashed(), zshEscape(f.usage))
+		case len(f.values) > 0:
+			spec = fmt.Sprintf("'%s=[%s]:value:(%s)'", f.dashed(), zshEscape(f.usage), strings.Join(f.values, " "))
+		case !f.isBool:
+			spec = fmt.Sprintf("'%s=[%s]:value:'", f.dashed(), zshEscape(f.usage))
+		}
+		fmt.Fprintf(w, "        %s \\\n", spec)
+	}
+	fmt.Fprintf(w, "        '1: :->cmd' \\\n")
+	fmt.Fprintf(w, "        '*:: :->args'\n\n")
+	fmt.Fprintf(w, "    case $state in\n")
+	fmt.Fprintf(w, "        cmd) _describe 'command' subcmds ;;\n")
+	fmt.Fprintf(w, "        args)\n")
+	fmt.Fprintf(w, "            case $words[1] in\n")
+	for _, sc := range subcommands {
+		if len(sc.choices) > 0 {
+			fmt.Fprintf(w, "                %s) (( CURRENT == 2 )) && _values '%s' %s ;;\n", sc.name, sc.name, strings.Join(sc.choices, " "))
+		}
+	}
+	fmt.Fprintf(w, "            esac\n")
+	fmt.Fprintf(w, "            if [[ $words[1] == profile && $words[2] == delete ]]; then\n")
+	fmt.Fprintf(w, "                _files -W ~/.osa/profiles -/\n")
+	fmt.Fprintf(w, "            fi ;;\n")
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "_osa \"$@\"\n")
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintf(w, "# fish completion for osa\n")
+	fmt.Fprintf(w, "complete -c osa -f\n")
+	cond := "__fish_use_subcommand"
+	for _, sc := range subcommands {
+		fmt.Fprintf(w, "complete -c osa -n %s -a %s -d %s\n", cond, sc.name, fishQuote(sc.short))
+	}
+	for _, sc := range subcommands {
+		if len(sc.choices) > 0 {
+			fmt.Fprintf(w, "complete -c osa -n '__fish_seen_subcommand_from %s' -a '%s'\n", sc.name, strings.Join(sc.choices, " "))
+		}
+	}
+	fmt.Fprintf(w, "complete -c osa -n '__fish_seen_subcommand_from delete' -a '(ls ~/.osa/profiles 2>/dev/null)'\n")
+	for _, f := range flags() {
+		opt := "-l " + f.name
+		if len(f.name) == 1 {
+			opt = "-s " + f.name
+		}
+		line := fmt.Sprintf("complete -c osa %s -d %s", opt, fishQuote(f.usage))
+		switch {
+		case f.name == "profile":
+			line += " -x -a '(ls ~/.osa/profiles 2>/dev/null)'"
+		case len(f.values) > 0:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(f.values, " "))
+		case !f.isBool:
+			line += " -r"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func zshEscape(s string) string {
+	r := strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`)
+	return r.Replace(s)
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// -- profile ------------------------------------------------------------------
+
+func runProfile(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: osa profile list|create|delete [name]")
+	}
+	switch args[0] {
+	case "list":
+		for _, name := range config.ListProfiles() {
+			fmt.Printf("%-16s %s\n", name, config.ProfilePath(name))
+		}
+		return nil
+	case "create":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: osa profile create <name>")
+		}
+		if err := config.CreateProfile(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Created profile %s (%s)\nStart it with: osa --profile %s\n", args[1], config.ProfilePath(args[1]), args[1])
+		return nil
+	case "delete":
+		fs := flag.NewFlagSet("profile delete", flag.ContinueOnError)
+		force := fs.Bool("force", false, "Delete without confirmation")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: osa profile delete [--force] <name>")
+		}
+		name := fs.Arg(0)
+		if err := config.ValidateProfileName(name); err != nil {
+			return err
+		}
+		if !config.ProfileExists(name) {
+			return fmt.Errorf("profile %q does not exist", name)
+		}
+		if !*force {
+			fmt.Printf("Delete profile %s and its stored credentials? [y/N] ", name)
+			var answer string
+			fmt.Scanln(&answer)
+			if a := strings.ToLower(answer); a != "y" && a != "yes" {
+				return fmt.Errorf("aborted")
+			}
+		}
+		if err := config.DeleteProfile(name); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted profile %s\n", name)
+		return nil
+	}
+	return fmt.Errorf("unknown profile command %q (want list, create or delete)", args[0])
+}
+
+// -- config -------------------------------------------------------------------
+
+// runConfig exports the settings, themes and prompt templates of the profile
+// selected by --profile and --dev to a zip archive, or imports one into it.
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: osa config export [file] | import [--force] <file>")
+	}
+	profile, _ := resolveTarget(flag.Lookup("profile").Value.String(), flag.Lookup("dev").Value.String() == "true")
+	if profile != "" && profile != config.DefaultProfile {
+		if err := config.ValidateProfileName(profile); err != nil {
+			return err
+		}
+	}
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	profileDir := config.ProfilePath(profile)
+	dirs := bundle.Dirs{
+		Profile: profileDir,
+		Themes:  filepath.Join(config.BaseDir(), "themes"),
+		Prompts: prompts.Dir(profileDir),
+	}
+
+	switch args[0] {
+	case "export":
+		if len(args) > 2 {
+			return fmt.Errorf("usage: osa config export [file]")
+		}
+		file := "osa-config-" + profile + "-" + time.Now().Format("2006-01-02") + ".zip"
+		if len(args) == 2 {
+			file = args[1]
+		}
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		names, err := bundle.Export(f, dirs, bundle.Manifest{Version: version, Profile: profile, Created: time.Now().UTC()})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(file)
+			return err
+		}
+		fmt.Printf("Exported profile %s to %s (%d files)\n", profile, file, len(names)-1)
+		return nil
+	case "import":
+		fs := flag.NewFlagSet("config import", flag.ContinueOnError)
+		force := fs.Bool("force", false, "Replace themes and prompts that differ from the bundle's")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: osa config import [--force] <file>")
+		}
+		if !config.ProfileExists(profile) {
+			return fmt.Errorf("profile %q does not exist (create it with: osa profile create %s)", profile, profile)
+		}
+		res, err := bundle.Import(fs.Arg(0), dirs, *force)
+		if err != nil {
+			return err
+		}
+		for _, name := range res.Written {
+			fmt.Printf("  wrote     %s\n", name)
+		}
+		for _, name := range res.Unchanged {
+			fmt.Printf("  unchanged %s\n", name)
+		}
+		for _, name := range res.Skipped {
+			fmt.Printf("  skipped   %s (differs; --force replaces it)\n", name)
+		}
+		fmt.Printf("Imported %s into profile %s\n", fs.Arg(0), profile)
+		return nil
+	}
+	return fmt.Errorf("unknown config command %q (want export or import)", args[0])
+}
+
+// -- doctor -------------------------------------------------------------------
+
+// runDoctor prints the doctor report for the profile and backend selected by
+// --profile, --dev and OSA_URL, and fails when any check failed.
+func runDoctor(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: osa doctor")
+	}
+	profile, baseURL := resolveTarget(flag.Lookup("profile").Value.String(), flag.Lookup("dev").Value.String() == "true")
+	if profile != "" && profile != config.DefaultProfile {
+		if err := config.ValidateProfileName(profile); err != nil {
+			return err
+		}
+	}
+	profileDir := config.ProfilePath(profile)
+
+	c := client.New(baseURL)
+	token := os.Getenv("OSA_TOKEN")
+	if token == "" {
+		token, _ = config.ReadCredentials(profileDir)
+	}
+	if token != "" {
+		c.SetToken(token)
+	}
+
+	themesDir := filepath.Join(config.BaseDir(), "themes")
+	_, themeErrs := style.LoadUserThemes(themesDir)
+	results := doctor.Run(doctor.Options{
+		Client:     c,
+		ProfileDir: profileDir,
+		ThemesDir:  themesDir,
+		LocalesDir: filepath.Join(config.BaseDir(), "locales"),
+		ThemeErrs:  themeErrs,
+		Colors:     colorprofile.Env(os.Environ()),
+	})
+	fmt.Println(doctor.Format(results))
+	if n := doctor.Failed(results); n > 0 {
+		return fmt.Errorf("%d of %d checks failed", n, len(results))
+	}
+	return nil
+}
+
+// -- status -------------------------------------------------------------------
+
+// runStatus prints what the running TUIs are doing in one line, such as
+// "1 working · 2 unread", and nothing when all are idle. --pane keeps the
+// TUI in one tmux or zellij pane.
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	pane := fs.String("pane", "", "Only the TUI in this tmux pane (#{pane_id}) or zellij pane ID")
+	asJSON := fs.Bool("json", false, "Print the status of each TUI as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: osa status [--pane ID] [--json]")
+	}
+	list, err := panestatus.Read(panestatus.Dir(config.BaseDir()))
+	if err != nil {
+		return err
+	}
+	if *pane != "" {
+		kept := list[:0]
+		for _, s := range list {
+			if s.TmuxPane == *pane || s.ZellijPane == *pane {
+				kept = append(kept, s)
+			}
+		}
+		list = kept
+	}
+	if *asJSON {
+		if list == nil {
+			list = []panestatus.Status{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	if line := panestatus.Summary(list); line != "" {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// -- man page -----------------------------------------------------------------
+
+func runManPage(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: osa man")
+	}
+	writeManPage(os.Stdout)
+	return nil
+}
+
+func writeManPage(w io.Writer) {
+	date := time.Now().Format("2006-01-02")
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		// Reproducible builds pin the date.
+		var sec int64
+		if _, err := fmt.Sscan(epoch, &sec); err == nil {
+			date = time.Unix(sec, 0).UTC().Format("2006-01-02")
+		}
+	}
+
+	fmt.Fprintf(w, ".TH OSA 1 %q %q \"OSA Manual\"\n", date, "osa "+version)
+	fmt.Fprintf(w, ".SH NAME\nosa \\- terminal client for the OSA agent\n")
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B osa\n[\\fIoptions\\fR] [\\fIprompt\\fR]\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(w, ".br\n.B osa %s\n", sc.name)
+		if sc.args != "" {
+			fmt.Fprintf(w, "%s\n", roff(sc.args))
+		}
+	}
+	fmt.Fprintf(w, ".SH DESCRIPTION\n")
+	fmt.Fprintf(w, "Interactive terminal UI for OSA. It connects to the OSA backend, streams\n")
+	fmt.Fprintf(w, "agent responses and tool activity, and manages sessions, models and keys.\n")
+	fmt.Fprintf(w, ".PP\nArguments that are not a command form a first prompt, submitted as soon as\n")
+	fmt.Fprintf(w, "the TUI is connected, after \\fB--session\\fR or \\fB--resume\\fR has opened the session.\n")
+
+	fmt.Fprintf(w, ".SH OPTIONS\n")
+	for _, f := range flags() {
+		arg := ""
+		if !f.isBool {
+			arg = " \\fIvalue\\fR"
+		}
+		fmt.Fprintf(w, ".TP\n.B %s%s\n%s\n", roff(f.dashed()), arg, roff(f.usage))
+	}
+
+	fmt.Fprintf(w, ".SH COMMANDS\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(w, ".TP\n.B %s %s\n%s\n", sc.name, roff(sc.args), roff(sc.short))
+	}
+
+	fmt.Fprintf(w, ".SH SLASH COMMANDS\nTyped at the input prompt.\n")
+	for _, c := range app.SlashCommands() {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(c.Name), roff(c.Description))
+	}
+
+	fmt.Fprintf(w, ".SH KEYBINDINGS\n")
+	for _, k := range app.KeyBindings() {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(k.Name), roff(k.Description))
+	}
+
+	fmt.Fprintf(w, ".SH ENVIRONMENT\n")
+	for _, e := range [][2]string{
+		{"OSA_URL", "Backend URL (default http://localhost:8089)."},
+		{"OSA_TOKEN", "Bearer token; overrides the stored token."},
+		{"OLLAMA_HOST", "Local Ollama daemon used during onboarding."},
+		{"NO_COLOR", "Disable colors when set."},
+		{"LC_ALL, LC_MESSAGES, LANG", "Interface language when no locale is configured."},
+	} {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(e[0]), roff(e[1]))
+	}
+
+	fmt.Fprintf(w, ".SH FILES\n")
+	for _, f := range [][2]string{
+		{"~/.osa/tui.json", "TUI settings: theme, colors, locale, accessibility, models."},
+		{"~/.osa/token, ~/.osa/refresh_token", "Stored credentials."},
+		{"~/.osa/profiles/<name>/", "Per-profile settings and credentials (--profile)."},
+		{"~/.osa/themes/", "Custom theme files (*.json, *.toml)."},
+		{"~/.osa/locales/", "User translation catalogs (<locale>.json)."},
+	} {
+		fmt.Fprintf(w, ".TP\n.I %s\n%s\n", roff(f[0]), roff(f[1]))
+	}
+}
+
+// roff escapes text for use in a man page line.
+func roff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
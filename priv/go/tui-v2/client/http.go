@@ -3,12 +3,30 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// ErrNotSupported is returned when the backend does not expose an endpoint
+// (HTTP 404), so callers can degrade gracefully against older backends.
+var ErrNotSupported = errors.New("not supported by backend")
+
+// ErrSessionNotFound is returned by GetSession and CompactSession when the
+// backend has no (running) session with the ID (HTTP 404).
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrNotRunning is returned by CancelOrchestrate when the request already
+// finished (HTTP 409).
+var ErrNotRunning = errors.New("request not running")
+
+// ErrUnauthorized is returned by CheckAuth when the backend rejects the token
+// or requires one and none is set (HTTP 401 or 403).
+var ErrUnauthorized = errors.New("unauthorized")
+
 type Client struct {
 	BaseURL    string
 	Token      string
@@ -60,6 +78,48 @@ func (c *Client) Orchestrate(req OrchestrateRequest) (*OrchestrateResponse, erro
 	return &result, nil
 }
 
+// CancelOrchestrate asks the backend to stop the session's in-flight
+// request. The stop itself is confirmed by a RequestCancelledEvent.
+func (c *Client) CancelOrchestrate(sessionID, requestID string) error {
+	resp, err := c.postJSON("/api/v1/orchestrate/cancel", CancelRequest{SessionID: sessionID, RequestID: requestID})
+	if err != nil {
+		return fmt.Errorf("cancel: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return ErrNotSupported
+	case http.StatusConflict:
+		return ErrNotRunning
+	}
+	return c.parseError(resp)
+}
+
+// CheckAuth makes an authenticated request and reports whether the backend
+// accepted it. A rejection wraps ErrUnauthorized with the backend's reason,
+// such as INVALID_TOKEN or MISSING_TOKEN.
+func (c *Client) CheckAuth() error {
+	resp, err := c.get("/api/v1/tools")
+	if err != nil {
+		return fmt.Errorf("check auth: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		var apiErr ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Code != "" {
+			return fmt.Errorf("%w: %s", ErrUnauthorized, apiErr.Code)
+		}
+		return ErrUnauthorized
+	}
+	return c.parseError(resp)
+}
+
 func (c *Client) ListTools() ([]ToolEntry, error) {
 	resp, err := c.get("/api/v1/tools")
 	if err != nil {
@@ -79,6 +139,29 @@ func (c *Client) ListTools() ([]ToolEntry, error) {
 	return wrapper.Tools, nil
 }
 
+// ListAgents returns the agent roster. Returns ErrNotSupported when the
+// backend has no roster endpoint.
+func (c *Client) ListAgents() ([]AgentRole, error) {
+	resp, err := c.get("/api/v1/agents")
+	if err != nil {
+		return nil, fmt.Errorf("list agents: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Agents []AgentRole `json:"agents"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode agents: %w", err)
+	}
+	return wrapper.Agents, nil
+}
+
 func (c *Client) ListCommands() ([]CommandEntry, error) {
 	resp, err := c.get("/api/v1/commands")
 	if err != nil {
@@ -195,6 +278,25 @@ func (c *Client) CreateSession() (*SessionCreateResponse, error) {
 	return &result, nil
 }
 
+// RestoreSession recreates session req.SessionID with its messages when
+// the backend no longer runs it, as after a restart. A backend that cannot
+// restore sessions creates one with a new ID.
+func (c *Client) RestoreSession(req SessionRestoreRequest) (*SessionCreateResponse, error) {
+	resp, err := c.postJSON("/api/v1/sessions", req)
+	if err != nil {
+		return nil, fmt.Errorf("restore session: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+	var result SessionCreateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode session: %w", err)
+	}
+	return &result, nil
+}
+
 func (c *Client) ListModels() (*ModelListResponse, error) {
 	resp, err := c.get("/api/v1/models")
 	if err != nil {
@@ -233,6 +335,9 @@ func (c *Client) GetSession(id string) (*SessionInfo, error) {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrSessionNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, c.parseError(resp)
 	}
@@ -243,6 +348,45 @@ func (c *Client) GetSession(id string) (*SessionInfo, error) {
 	return &result, nil
 }
 
+// CompactSession compacts the conversation of the running session id now,
+// down to targetTokens when it is not zero.
+func (c *Client) CompactSession(id string, targetTokens int) (*SessionCompactResponse, error) {
+	resp, err := c.postJSON(fmt.Sprintf("/api/v1/sessions/%s/compact", id), SessionCompactRequest{TargetTokens: targetTokens})
+	if err != nil {
+		return nil, fmt.Errorf("compact session: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrSessionNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result SessionCompactResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode compaction: %w", err)
+	}
+	return &result, nil
+}
+
+// CleanupSessions archives or deletes the sessions req matches, or with
+// DryRun only lists them.
+func (c *Client) CleanupSessions(req SessionCleanupRequest) (*SessionCleanupResponse, error) {
+	resp, err := c.postJSON("/api/v1/sessions/cleanup", req)
+	if err != nil {
+		return nil, fmt.Errorf("clean up sessions: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result SessionCleanupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode session cleanup: %w", err)
+	}
+	return &result, nil
+}
+
 func (c *Client) GetSessionMessages(id string) ([]SessionMessage, error) {
 	resp, err := c.get(fmt.Sprintf("/api/v1/sessions/%s/messages", id))
 	if err != nil {
@@ -279,6 +423,30 @@ func (c *Client) Classify(message, channel string) (*ClassifyResponse, error) {
 	return &result, nil
 }
 
+// -- Token counting -----------------------------------------------------------
+
+// CountTokens counts the tokens of text with the backend's tokenizer, and
+// reports the context sessionID's conversation uses when it is set.
+// Returns ErrNotSupported when the backend has no token-count endpoint.
+func (c *Client) CountTokens(text, sessionID string) (*TokenCountResponse, error) {
+	resp, err := c.postJSON("/api/v1/tokens/count", TokenCountRequest{Text: text, SessionID: sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("count tokens: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result TokenCountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode token count: %w", err)
+	}
+	return &result, nil
+}
+
 // -- Tool execution -----------------------------------------------------------
 
 func (c *Client) ExecuteTool(name string, args map[string]any) (*ToolExecuteResponse, error) {
@@ -481,6 +649,268 @@ func (c *Client) RecallMemory() (*MemoryRecallResponse, error) {
 	return &result, nil
 }
 
+// ListMemories returns the stored memory entries, newest first, or those
+// matching query, best match first. It returns ErrNotSupported when the
+// backend has no entries endpoint.
+func (c *Client) ListMemories(query string) ([]MemoryEntry, error) {
+	resp, err := c.get("/api/v1/memory/entries?q=" + url.QueryEscape(query))
+	if err != nil {
+		return nil, fmt.Errorf("list memories: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Entries []MemoryEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode memories: %w", err)
+	}
+	return wrapper.Entries, nil
+}
+
+// UpdateMemory edits, recategorises or pins a memory entry and returns it.
+// Editing the content or category changes the entry's ID.
+func (c *Client) UpdateMemory(id string, upd MemoryUpdate) (*MemoryEntry, error) {
+	resp, err := c.putJSON("/api/v1/memory/entries/"+url.PathEscape(id), upd)
+	if err != nil {
+		return nil, fmt.Errorf("update memory: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Entry MemoryEntry `json:"entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode memory: %w", err)
+	}
+	return &wrapper.Entry, nil
+}
+
+func (c *Client) DeleteMemory(id string) error {
+	resp, err := c.delete("/api/v1/memory/entries/" + url.PathEscape(id))
+	if err != nil {
+		return fmt.Errorf("delete memory: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// -- Hooks --------------------------------------------------------------------
+
+// ListHooks returns the hook pipeline and its recent blocks. It returns
+// ErrNotSupported when the backend has no hooks endpoint.
+func (c *Client) ListHooks() (*HooksStatus, error) {
+	resp, err := c.get("/api/v1/hooks")
+	if err != nil {
+		return nil, fmt.Errorf("list hooks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var status HooksStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decode hooks: %w", err)
+	}
+	return &status, nil
+}
+
+// SetHookEnabled enables or disables a hook. Protected hooks are refused.
+func (c *Client) SetHookEnabled(name string, enabled bool) error {
+	resp, err := c.putJSON("/api/v1/hooks/"+url.PathEscape(name), map[string]bool{"enabled": enabled})
+	if err != nil {
+		return fmt.Errorf("toggle hook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// -- Channels -----------------------------------------------------------------
+
+// ListChannels returns the messaging channels with their settings.
+func (c *Client) ListChannels() ([]Channel, error) {
+	resp, err := c.get("/api/v1/channels")
+	if err != nil {
+		return nil, fmt.Errorf("list channels: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Channels []Channel `json:"channels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode channels: %w", err)
+	}
+	return wrapper.Channels, nil
+}
+
+// UpdateChannel saves a channel's settings or enables or disables it, and
+// returns the channel.
+func (c *Client) UpdateChannel(name string, upd ChannelUpdate) (*Channel, error) {
+	resp, err := c.putJSON("/api/v1/channels/"+url.PathEscape(name), upd)
+	if err != nil {
+		return nil, fmt.Errorf("update channel: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var ch Channel
+	if err := json.NewDecoder(resp.Body).Decode(&ch); err != nil {
+		return nil, fmt.Errorf("decode channel: %w", err)
+	}
+	return &ch, nil
+}
+
+// TestChannel checks that a channel is running and connected.
+func (c *Client) TestChannel(name string) (*ChannelTest, error) {
+	resp, err := c.postJSON("/api/v1/channels/"+url.PathEscape(name)+"/test", struct{}{})
+	if err != nil {
+		return nil, fmt.Errorf("test channel: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result ChannelTest
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode channel test: %w", err)
+	}
+	return &result, nil
+}
+
+// -- MCP ----------------------------------------------------------------------
+
+// ListMCPServers returns the configured MCP servers with their status. It
+// returns ErrNotSupported when the backend has no MCP endpoints.
+func (c *Client) ListMCPServers() ([]MCPServer, error) {
+	resp, err := c.get("/api/v1/mcp/servers")
+	if err != nil {
+		return nil, fmt.Errorf("list mcp servers: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Servers []MCPServer `json:"servers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode mcp servers: %w", err)
+	}
+	return wrapper.Servers, nil
+}
+
+// AddMCPServer adds a server, or replaces the one with the same name, and
+// returns it.
+func (c *Client) AddMCPServer(cfg MCPServerConfig) (*MCPServer, error) {
+	resp, err := c.postJSON("/api/v1/mcp/servers", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("add mcp server: %w", err)
+	}
+	return c.decodeMCPServer(resp)
+}
+
+// RemoveMCPServer removes a server from the backend's configuration.
+func (c *Client) RemoveMCPServer(name string) error {
+	resp, err := c.delete("/api/v1/mcp/servers/" + url.PathEscape(name))
+	if err != nil {
+		return fmt.Errorf("remove mcp server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// RestartMCPServer restarts a server and returns its new status.
+func (c *Client) RestartMCPServer(name string) (*MCPServer, error) {
+	resp, err := c.postJSON("/api/v1/mcp/servers/"+url.PathEscape(name)+"/restart", struct{}{})
+	if err != nil {
+		return nil, fmt.Errorf("restart mcp server: %w", err)
+	}
+	return c.decodeMCPServer(resp)
+}
+
+func (c *Client) decodeMCPServer(resp *http.Response) (*MCPServer, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+	var server MCPServer
+	if err := json.NewDecoder(resp.Body).Decode(&server); err != nil {
+		return nil, fmt.Errorf("decode mcp server: %w", err)
+	}
+	return &server, nil
+}
+
+// -- Budget -------------------------------------------------------------------
+
+// GetBudget returns spend and limits, with the spend of sessionID.
+func (c *Client) GetBudget(sessionID string) (*Budget, error) {
+	resp, err := c.get("/api/v1/budget?session_id=" + url.QueryEscape(sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("get budget: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var b Budget
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode budget: %w", err)
+	}
+	return &b, nil
+}
+
+// SetBudgetLimits changes the limits named in limits ("daily_limit",
+// "monthly_limit", "per_call_limit" or "session_limit"), keeping the rest.
+// A nil session_limit removes it. It returns the budget without session
+// spend.
+func (c *Client) SetBudgetLimits(limits map[string]*float64) (*Budget, error) {
+	resp, err := c.putJSON("/api/v1/budget", limits)
+	if err != nil {
+		return nil, fmt.Errorf("set budget: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var b Budget
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode budget: %w", err)
+	}
+	return &b, nil
+}
+
 // -- Analytics ----------------------------------------------------------------
 
 func (c *Client) GetAnalytics() (*AnalyticsResponse, error) {
@@ -499,6 +929,23 @@ func (c *Client) GetAnalytics() (*AnalyticsResponse, error) {
 	return &result, nil
 }
 
+// SendFeedback records a rating of an agent response. Returns
+// ErrNotSupported when the backend does not take ratings.
+func (c *Client) SendFeedback(req FeedbackRequest) error {
+	resp, err := c.postJSON("/api/v1/analytics/feedback", req)
+	if err != nil {
+		return fmt.Errorf("send feedback: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
 // -- Scheduler ----------------------------------------------------------------
 
 func (c *Client) ListSchedulerJobs() ([]SchedulerJob, error) {
@@ -519,6 +966,68 @@ func (c *Client) ListSchedulerJobs() ([]SchedulerJob, error) {
 	return wrapper.Jobs, nil
 }
 
+// CreateSchedulerJob adds a cron job. Returns ErrNotSupported when the
+// backend cannot create jobs.
+func (c *Client) CreateSchedulerJob(req SchedulerJobRequest) (*SchedulerJob, error) {
+	resp, err := c.postJSON("/api/v1/scheduler/jobs", req)
+	if err != nil {
+		return nil, fmt.Errorf("create scheduler job: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Job SchedulerJob `json:"job"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode scheduler job: %w", err)
+	}
+	return &wrapper.Job, nil
+}
+
+// SetSchedulerJobEnabled enables or disables a cron job.
+func (c *Client) SetSchedulerJobEnabled(id string, enabled bool) error {
+	resp, err := c.postJSON(fmt.Sprintf("/api/v1/scheduler/jobs/%s/toggle", url.PathEscape(id)), map[string]bool{"enabled": enabled})
+	if err != nil {
+		return fmt.Errorf("toggle scheduler job: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return c.jobError(resp)
+	}
+	return nil
+}
+
+// DeleteSchedulerJob removes a cron job.
+func (c *Client) DeleteSchedulerJob(id string) error {
+	resp, err := c.delete(fmt.Sprintf("/api/v1/scheduler/jobs/%s", url.PathEscape(id)))
+	if err != nil {
+		return fmt.Errorf("delete scheduler job: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.jobError(resp)
+	}
+	return nil
+}
+
+// jobError maps a failed job request. A 404 for a missing job is reported as
+// such; any other 404 means the backend lacks the endpoint.
+func (c *Client) jobError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusNotFound {
+		return c.parseError(resp)
+	}
+	var apiErr ErrorResponse
+	if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error == "job_not_found" {
+		return fmt.Errorf("%s", apiErr.Details)
+	}
+	return ErrNotSupported
+}
+
 func (c *Client) ReloadScheduler() error {
 	resp, err := c.postJSON("/api/v1/scheduler/reload", nil)
 	if err != nil {
@@ -551,6 +1060,74 @@ func (c *Client) ListMachines() ([]MachineInfo, error) {
 	return wrapper.Machines, nil
 }
 
+// -- Git ----------------------------------------------------------------------
+
+// GitStatus returns the branch and changed files of the repository at path,
+// as reported by the backend's git sidecar. Returns ErrNotSupported when the
+// backend has no git endpoint.
+func (c *Client) GitStatus(path string) (*GitStatusResponse, error) {
+	resp, err := c.get("/api/v1/git/status?path=" + url.QueryEscape(path))
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result GitStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode git status: %w", err)
+	}
+	return &result, nil
+}
+
+// GitDiff returns the unified diff of uncommitted changes in the repository
+// at path. Returns ErrNotSupported when the backend has no git endpoint.
+func (c *Client) GitDiff(path string) (string, error) {
+	resp, err := c.get("/api/v1/git/diff?path=" + url.QueryEscape(path))
+	if err != nil {
+		return "", fmt.Errorf("git diff: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", c.parseError(resp)
+	}
+	var result GitDiffResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode git diff: %w", err)
+	}
+	return result.Diff, nil
+}
+
+// ListSidecars returns the backend's sidecars and their last polled health.
+// Returns ErrNotSupported when the backend has no sidecars endpoint.
+func (c *Client) ListSidecars() ([]SidecarInfo, error) {
+	resp, err := c.get("/api/v1/sidecars")
+	if err != nil {
+		return nil, fmt.Errorf("list sidecars: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var wrapper struct {
+		Sidecars []SidecarInfo `json:"sidecars"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		return nil, fmt.Errorf("decode sidecars: %w", err)
+	}
+	return wrapper.Sidecars, nil
+}
+
 // -- Onboarding ---------------------------------------------------------------
 
 func (c *Client) CheckOnboarding() (*OnboardingStatusResponse, error) {
@@ -585,6 +1162,84 @@ func (c *Client) CompleteOnboarding(req OnboardingSetupRequest) (*OnboardingSetu
 	return &result, nil
 }
 
+// VerifyProvider asks the backend to test the provider credentials before
+// setup is written. Returns ErrNotSupported when the backend predates the
+// verify endpoint.
+func (c *Client) VerifyProvider(req OnboardingVerifyRequest) (*OnboardingVerifyResponse, error) {
+	resp, err := c.postJSON("/onboarding/verify", req)
+	if err != nil {
+		return nil, fmt.Errorf("verify provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result OnboardingVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode onboarding verify: %w", err)
+	}
+	return &result, nil
+}
+
+// -- Provider keys ------------------------------------------------------------
+
+// ListProviderKeys returns the key state of every known provider. Returns
+// ErrNotSupported when the backend has no key management endpoint.
+func (c *Client) ListProviderKeys() ([]ProviderKeyEntry, error) {
+	resp, err := c.get("/api/v1/providers/keys")
+	if err != nil {
+		return nil, fmt.Errorf("list provider keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+	var result ProviderKeysResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode provider keys: %w", err)
+	}
+	return result.Providers, nil
+}
+
+// SetProviderKey stores or rotates the API key for a provider.
+func (c *Client) SetProviderKey(provider, apiKey string) error {
+	resp, err := c.postJSON(fmt.Sprintf("/api/v1/providers/keys/%s", provider), ProviderKeyRequest{APIKey: apiKey})
+	if err != nil {
+		return fmt.Errorf("set provider key: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// DeleteProviderKey removes the stored API key for a provider. Keys supplied
+// through the environment are not affected.
+func (c *Client) DeleteProviderKey(provider string) error {
+	resp, err := c.delete(fmt.Sprintf("/api/v1/providers/keys/%s", provider))
+	if err != nil {
+		return fmt.Errorf("delete provider key: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotSupported
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
 // -- HTTP helpers -------------------------------------------------------------
 
 func (c *Client) get(path string) (*http.Response, error) {
@@ -597,11 +1252,19 @@ func (c *Client) get(path string) (*http.Response, error) {
 }
 
 func (c *Client) postJSON(path string, body any) (*http.Response, error) {
+	return c.sendJSON("POST", path, body)
+}
+
+func (c *Client) putJSON(path string, body any) (*http.Response, error) {
+	return c.sendJSON("PUT", path, body)
+}
+
+func (c *Client) sendJSON(method, path string, body any) (*http.Response, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("marshal: %w", err)
 	}
-	req, err := http.NewRequest("POST", c.BaseURL+path, bytes.NewReader(data))
+	req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
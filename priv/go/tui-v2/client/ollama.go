@@ -0,0 +1,181 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// DefaultOllamaURL is used when OLLAMA_HOST is not set.
+const DefaultOllamaURL = "http://localhost:11434"
+
+// -- Raw Ollama pull event types ----------------------------------------------
+
+// OllamaPullProgressEvent is dispatched for each status line of a model pull.
+type OllamaPullProgressEvent struct {
+	Model     string
+	Status    string
+	Completed int64
+	Total     int64
+}
+
+// OllamaPullDoneEvent is dispatched when a model pull finishes or fails.
+// Err is nil on success and when the pull was cancelled via Close.
+type OllamaPullDoneEvent struct {
+	Model     string
+	Cancelled bool
+	Err       error
+}
+
+// -- OllamaClient -------------------------------------------------------------
+
+// OllamaClient talks to the local Ollama daemon directly. It is used during
+// onboarding to detect and pull a missing default model before the backend
+// is configured to use it.
+type OllamaClient struct {
+	BaseURL string
+	done    chan struct{}
+	httpCli *http.Client
+}
+
+// NewOllama creates an Ollama client honouring OLLAMA_HOST.
+func NewOllama() *OllamaClient {
+	base := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+	switch {
+	case base == "":
+		base = DefaultOllamaURL
+	case !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://"):
+		base = "http://" + base
+	}
+	return &OllamaClient{
+		BaseURL: strings.TrimRight(base, "/"),
+		done:    make(chan struct{}),
+		httpCli: &http.Client{Timeout: 0},
+	}
+}
+
+// Close cancels an in-flight pull.
+func (o *OllamaClient) Close() {
+	select {
+	case <-o.done:
+	default:
+		close(o.done)
+	}
+}
+
+// HasModel reports whether the named model is installed locally. A bare name
+// matches its ":latest" tag.
+func (o *OllamaClient) HasModel(name string) (bool, error) {
+	c := &http.Client{Timeout: 5 * time.Second}
+	resp, err := c.Get(o.BaseURL + "/api/tags")
+	if err != nil {
+		return false, fmt.Errorf("ollama tags: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("ollama tags: status %d", resp.StatusCode)
+	}
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("decode ollama tags: %w", err)
+	}
+	want := withLatestTag(name)
+	for _, m := range result.Models {
+		if withLatestTag(m.Name) == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PullCmd returns a tea.Cmd that streams a model pull, sending
+// OllamaPullProgressEvent messages and finishing with OllamaPullDoneEvent.
+func (o *OllamaClient) PullCmd(p *tea.Program, model string) tea.Cmd {
+	return func() tea.Msg {
+		body, err := json.Marshal(map[string]any{"model": model, "stream": true})
+		if err != nil {
+			return OllamaPullDoneEvent{Model: model, Err: err}
+		}
+		req, err := http.NewRequest("POST", o.BaseURL+"/api/pull", bytes.NewReader(body))
+		if err != nil {
+			return OllamaPullDoneEvent{Model: model, Err: err}
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := o.httpCli.Do(req)
+		if err != nil {
+			return OllamaPullDoneEvent{Model: model, Err: fmt.Errorf("ollama pull: %w", err)}
+		}
+		defer resp.Body.Close()
+
+		// Closing the body unblocks the scanner when Close is called.
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-o.done:
+				resp.Body.Close()
+			case <-finished:
+			}
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return OllamaPullDoneEvent{Model: model, Err: fmt.Errorf("ollama pull: status %d", resp.StatusCode)}
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0), 1024*1024)
+		for scanner.Scan() {
+			var line struct {
+				Status    string `json:"status"`
+				Error     string `json:"error"`
+				Completed int64  `json:"completed"`
+				Total     int64  `json:"total"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				continue
+			}
+			if line.Error != "" {
+				return OllamaPullDoneEvent{Model: model, Err: errors.New(line.Error)}
+			}
+			if line.Status == "success" {
+				return OllamaPullDoneEvent{Model: model}
+			}
+			p.Send(OllamaPullProgressEvent{
+				Model:     model,
+				Status:    line.Status,
+				Completed: line.Completed,
+				Total:     line.Total,
+			})
+		}
+
+		select {
+		case <-o.done:
+			return OllamaPullDoneEvent{Model: model, Cancelled: true}
+		default:
+		}
+		if err := scanner.Err(); err != nil {
+			return OllamaPullDoneEvent{Model: model, Err: err}
+		}
+		return OllamaPullDoneEvent{Model: model, Err: errors.New("ollama pull ended without success")}
+	}
+}
+
+func withLatestTag(name string) string {
+	if !strings.Contains(name, ":") {
+		return name + ":latest"
+	}
+	return name
+}
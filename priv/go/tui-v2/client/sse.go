@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -22,8 +24,11 @@ type SSEConnectedEvent struct {
 }
 
 // SSEDisconnectedEvent is dispatched when the SSE stream drops or closes.
+// Closed is set when the client was closed on purpose, so the stream ended
+// because it was replaced rather than lost.
 type SSEDisconnectedEvent struct {
-	Err error
+	Err    error
+	Closed bool
 }
 
 // SSEReconnectingEvent is dispatched before each reconnect attempt.
@@ -36,6 +41,7 @@ type AgentResponseEvent struct {
 	Response     string  `json:"response"`
 	ResponseType string  `json:"response_type,omitempty"`
 	Signal       *Signal `json:"signal,omitempty"`
+	RequestID    string  `json:"request_id,omitempty"`
 }
 
 // ToolCallStartEvent is dispatched when a tool invocation begins.
@@ -44,16 +50,19 @@ type ToolCallStartEvent struct {
 	Args string `json:"args"`
 }
 
-// ToolCallEndEvent is dispatched when a tool invocation completes.
+// ToolCallEndEvent is dispatched when a tool invocation completes. Args is
+// the same summary of the arguments as on the start.
 type ToolCallEndEvent struct {
 	Name       string `json:"name"`
+	Args       string `json:"args"`
 	DurationMs int64  `json:"duration_ms"`
 	Success    bool   `json:"success"`
 }
 
 // LLMRequestEvent signals the start of an LLM call.
 type LLMRequestEvent struct {
-	Iteration int `json:"iteration"`
+	Iteration     int `json:"iteration"`
+	MaxIterations int `json:"max_iterations"` // 0 from backends that do not send it
 }
 
 // LLMResponseEvent carries token usage and timing from the LLM.
@@ -170,21 +179,73 @@ type SwarmTimeoutEvent struct {
 	SwarmID string `json:"swarm_id"`
 }
 
+// SwarmAgentStartedEvent is emitted when a swarm worker starts on its
+// subtask. AgentName tells workers with the same role apart, e.g. "coder 2".
+type SwarmAgentStartedEvent struct {
+	SwarmID       string `json:"swarm_id"`
+	AgentName     string `json:"agent_name"`
+	Role          string `json:"role"`
+	Model         string `json:"model"`
+	CurrentAction string `json:"current_action"` // start of the subtask
+}
+
+// SwarmAgentCompletedEvent is emitted when a swarm worker finishes its
+// subtask, successfully or not.
+type SwarmAgentCompletedEvent struct {
+	SwarmID    string `json:"swarm_id"`
+	AgentName  string `json:"agent_name"`
+	Status     string `json:"status"` // "completed" or "failed"
+	Error      string `json:"error,omitempty"`
+	TokensUsed int    `json:"tokens_used"`
+}
+
 // HookBlockedEvent is emitted when a hook blocks an action (e.g. security_check).
 type HookBlockedEvent struct {
 	HookName string `json:"hook_name"`
 	Reason   string `json:"reason"`
 }
 
-// BudgetWarningEvent is emitted when spend crosses 80% of daily or monthly limit.
+// ScheduleResultEvent reports a scheduled job run to the session that
+// created the job.
+type ScheduleResultEvent struct {
+	JobID  string `json:"job_id"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // "ok" or "error"
+	Output string `json:"output"`
+}
+
+// RequestCancelledEvent confirms the backend stopped processing a cancelled
+// request.
+type RequestCancelledEvent struct {
+	RequestID string `json:"request_id"`
+	Iteration int    `json:"iteration"`
+}
+
+// BudgetWarningEvent is emitted when spend crosses 80% of the session, daily
+// or monthly limit.
 type BudgetWarningEvent struct {
+	Period      string  `json:"period"` // "session", "daily" or "monthly"
+	Spent       float64 `json:"spent"`
+	Limit       float64 `json:"limit"`
 	Utilization float64 `json:"utilization"`
 	Message     string  `json:"message"`
 }
 
 // BudgetExceededEvent is emitted when a budget limit is hit.
 type BudgetExceededEvent struct {
-	Message string `json:"message"`
+	Period  string  `json:"period"` // "session", "daily" or "monthly"
+	Spent   float64 `json:"spent"`
+	Limit   float64 `json:"limit"`
+	Message string  `json:"message"`
+}
+
+// ProviderRateLimitedEvent is emitted when the provider rejected an LLM call
+// with a rate limit. RetryAfter is in seconds, 0 when the provider named none.
+type ProviderRateLimitedEvent struct {
+	RequestID  string `json:"request_id"`
+	Provider   string `json:"provider"`
+	RetryAfter int    `json:"retry_after"`
+	Message    string `json:"message"`
 }
 
 // ThinkingDeltaEvent carries a partial thinking/reasoning token from the LLM.
@@ -192,6 +253,31 @@ type ThinkingDeltaEvent struct {
 	Text string `json:"text"`
 }
 
+// ThinkingFinishedEvent is emitted when the LLM stops thinking, with how
+// long it thought.
+type ThinkingFinishedEvent struct {
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// ToolOutputEvent carries a chunk of a running tool's output. Chunks can
+// arrive out of order; Offset is the chunk's byte offset in the output.
+type ToolOutputEvent struct {
+	Name   string `json:"name"`
+	Output string `json:"output"`
+	Offset int64  `json:"offset"`
+}
+
+// AgentToolCallEvent from system_event: a tool call a sub-agent of an
+// orchestrated task finished. These are not part of the tool_call stream
+// of the session's own agent.
+type AgentToolCallEvent struct {
+	AgentName  string `json:"agent_name"`
+	Name       string `json:"name"`
+	Args       string `json:"args"`
+	DurationMs int64  `json:"duration_ms"`
+	Success    bool   `json:"success"`
+}
+
 // SwarmIntelligenceStartedEvent from system_event.
 type SwarmIntelligenceStartedEvent struct {
 	SwarmID string `json:"swarm_id"`
@@ -239,6 +325,13 @@ type SSEParseWarning struct {
 	Message string
 }
 
+// SSEBatch carries the events of one batch interval when the client batches
+// them (see SetBatchInterval), in the order they arrived. Consecutive
+// StreamingTokenEvent and ThinkingDeltaEvent are joined into one.
+type SSEBatch struct {
+	Events []tea.Msg
+}
+
 // -- SSEClient ----------------------------------------------------------------
 
 // SSEClient manages the Server-Sent Events connection.
@@ -248,6 +341,7 @@ type SSEClient struct {
 	sessionID string
 	done      chan struct{}
 	httpCli   *http.Client
+	batch     time.Duration
 }
 
 // NewSSE creates an SSE client for the given session.
@@ -270,6 +364,14 @@ func (s *SSEClient) Close() {
 	}
 }
 
+// SetBatchInterval makes the client deliver events as SSEBatch messages, at
+// most one per interval d, instead of a message per event, so a fast stream
+// costs fewer updates and redraws. Zero, the default, turns batching off.
+// It applies to streams opened after the call.
+func (s *SSEClient) SetBatchInterval(d time.Duration) {
+	s.batch = d
+}
+
 // IsClosed reports whether the SSE client has been intentionally closed.
 func (s *SSEClient) IsClosed() bool {
 	select {
@@ -286,7 +388,7 @@ func (s *SSEClient) ListenCmd(p *tea.Program) tea.Cmd {
 		url := fmt.Sprintf("%s/api/v1/stream/%s", s.baseURL, s.sessionID)
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			return SSEDisconnectedEvent{Err: err}
+			return SSEDisconnectedEvent{Err: err, Closed: s.IsClosed()}
 		}
 		req.Header.Set("Accept", "text/event-stream")
 		req.Header.Set("Cache-Control", "no-cache")
@@ -297,7 +399,7 @@ func (s *SSEClient) ListenCmd(p *tea.Program) tea.Cmd {
 		c := s.httpCli
 		resp, err := c.Do(req)
 		if err != nil {
-			return SSEDisconnectedEvent{Err: err}
+			return SSEDisconnectedEvent{Err: err, Closed: s.IsClosed()}
 		}
 		defer resp.Body.Close()
 
@@ -313,43 +415,120 @@ func (s *SSEClient) ListenCmd(p *tea.Program) tea.Cmd {
 		// Signal connected.
 		p.Send(SSEConnectedEvent{SessionID: s.sessionID})
 
-		scanner := bufio.NewScanner(resp.Body)
-		scanner.Buffer(make([]byte, 0), 1024*1024) // 1 MB
+		send := p.Send
+		if s.batch > 0 {
+			b := &sseBatcher{send: p.Send, every: s.batch}
+			defer b.flush()
+			send = b.add
+		}
+		if err := ReadEvents(resp.Body, send, s.IsClosed); err != nil {
+			return SSEDisconnectedEvent{Err: err, Closed: s.IsClosed()}
+		}
+		return SSEDisconnectedEvent{Closed: s.IsClosed()}
+	}
+}
+
+// ReadEvents parses a text/event-stream from r, such as the stream body or
+// a recorded stream file, and passes each event to send as the same tea.Msg
+// the live stream delivers. It returns when r ends or stop reports true;
+// stop may be nil.
+func ReadEvents(r io.Reader, send func(tea.Msg), stop func() bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0), 1024*1024) // 1 MB
 
-		var eventType string
+	var eventType string
 
-		for scanner.Scan() {
-			select {
-			case <-s.done:
-				return SSEDisconnectedEvent{Err: nil}
-			default:
-			}
+	for scanner.Scan() {
+		if stop != nil && stop() {
+			return nil
+		}
 
-			line := scanner.Text()
+		line := scanner.Text()
 
-			switch {
-			case line == "":
-				eventType = ""
+		switch {
+		case line == "":
+			eventType = ""
 
-			case strings.HasPrefix(line, ":"):
-				// keepalive comment — ignore
+		case strings.HasPrefix(line, ":"):
+			// keepalive comment — ignore
 
-			case strings.HasPrefix(line, "event: "):
-				eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
 
-			case strings.HasPrefix(line, "data: "):
-				data := strings.TrimPrefix(line, "data: ")
-				if m := parseSSEEvent(eventType, []byte(data)); m != nil {
-					p.Send(m)
-				}
+		case strings.HasPrefix(line, "data: "):
+			data := strings.TrimPrefix(line, "data: ")
+			if m := parseSSEEvent(eventType, []byte(data)); m != nil {
+				send(m)
 			}
 		}
+	}
+	return scanner.Err()
+}
+
+// sseBatcher collects events and sends them as one SSEBatch an interval
+// after the first of them arrived.
+type sseBatcher struct {
+	send  func(tea.Msg)
+	every time.Duration
+
+	mu      sync.Mutex
+	pending []tea.Msg
+	timer   *time.Timer
 
-		if err := scanner.Err(); err != nil {
-			return SSEDisconnectedEvent{Err: err}
+	// sendMu keeps batches in order when the timer and a final flush race.
+	sendMu sync.Mutex
+}
+
+// add queues m, joining it to the last queued event when both are deltas
+// of the same kind.
+func (b *sseBatcher) add(m tea.Msg) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending = appendJoined(b.pending, m)
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.every, b.flush)
+	}
+}
+
+// flush sends the queued events, if any.
+func (b *sseBatcher) flush() {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+	b.mu.Lock()
+	events := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+	if len(events) > 0 {
+		b.send(SSEBatch{Events: events})
+	}
+}
+
+// appendJoined appends m to events, adding the text of a streaming or
+// thinking delta to the delta before it instead.
+func appendJoined(events []tea.Msg, m tea.Msg) []tea.Msg {
+	n := len(events)
+	if n == 0 {
+		return append(events, m)
+	}
+	switch last := events[n-1].(type) {
+	case StreamingTokenEvent:
+		if next, ok := m.(StreamingTokenEvent); ok && next.SessionID == last.SessionID {
+			last.Text += next.Text
+			events[n-1] = last
+			return events
+		}
+	case ThinkingDeltaEvent:
+		if next, ok := m.(ThinkingDeltaEvent); ok {
+			last.Text += next.Text
+			events[n-1] = last
+			return events
 		}
-		return SSEDisconnectedEvent{Err: nil}
 	}
+	return append(events, m)
 }
 
 // MaxReconnects is the maximum number of reconnect attempts before giving up.
@@ -366,7 +545,7 @@ func (s *SSEClient) ReconnectListenCmd(p *tea.Program) tea.Cmd {
 		for {
 			select {
 			case <-s.done:
-				return SSEDisconnectedEvent{Err: nil}
+				return SSEDisconnectedEvent{Closed: true}
 			default:
 			}
 
@@ -389,7 +568,7 @@ func (s *SSEClient) ReconnectListenCmd(p *tea.Program) tea.Cmd {
 			select {
 			case <-time.After(backoff):
 			case <-s.done:
-				return SSEDisconnectedEvent{Err: nil}
+				return SSEDisconnectedEvent{Closed: true}
 			}
 
 			// Attempt reconnect by running ListenCmd inline.
@@ -443,6 +622,7 @@ func parseSSEEvent(eventType string, data []byte) tea.Msg {
 			}
 			return ToolCallEndEvent{
 				Name:       raw.Name,
+				Args:       raw.Args,
 				DurationMs: raw.DurationMs,
 				Success:    success,
 			}
@@ -633,6 +813,20 @@ func parseSystemEvent(data []byte) tea.Msg {
 		}
 		return ev
 
+	case "swarm_agent_started":
+		var ev SwarmAgentStartedEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
+	case "swarm_agent_completed":
+		var ev SwarmAgentCompletedEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
 	case "hook_blocked":
 		var ev struct {
 			HookName string `json:"hook_name"`
@@ -643,24 +837,40 @@ func parseSystemEvent(data []byte) tea.Msg {
 		}
 		return HookBlockedEvent{HookName: ev.HookName, Reason: ev.Reason}
 
-	case "budget_warning":
-		var ev struct {
-			Utilization float64 `json:"utilization"`
-			Message     string  `json:"message"`
+	case "schedule_result":
+		var ev ScheduleResultEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
 		}
+		return ev
+
+	case "request_cancelled":
+		var ev RequestCancelledEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
+	case "budget_warning":
+		var ev BudgetWarningEvent
 		if err := json.Unmarshal(data, &ev); err != nil {
 			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
 		}
-		return BudgetWarningEvent{Utilization: ev.Utilization, Message: ev.Message}
+		return ev
 
 	case "budget_exceeded":
-		var ev struct {
-			Message string `json:"message"`
+		var ev BudgetExceededEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
 		}
+		return ev
+
+	case "provider_rate_limited":
+		var ev ProviderRateLimitedEvent
 		if err := json.Unmarshal(data, &ev); err != nil {
 			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
 		}
-		return BudgetExceededEvent{Message: ev.Message}
+		return ev
 
 	case "thinking_delta":
 		var ev ThinkingDeltaEvent
@@ -669,6 +879,27 @@ func parseSystemEvent(data []byte) tea.Msg {
 		}
 		return ev
 
+	case "thinking_finished":
+		var ev ThinkingFinishedEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
+	case "tool_output":
+		var ev ToolOutputEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
+	case "agent_tool_call":
+		var ev AgentToolCallEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return SSEParseWarning{Message: fmt.Sprintf("[sse] parse %s: %v", base.Event, err)}
+		}
+		return ev
+
 	case "swarm_intelligence_started":
 		var ev SwarmIntelligenceStartedEvent
 		if err := json.Unmarshal(data, &ev); err != nil {
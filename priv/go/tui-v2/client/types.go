@@ -16,6 +16,37 @@ type OrchestrateRequest struct {
 	UserID      string `json:"user_id,omitempty"`
 	WorkspaceID string `json:"workspace_id,omitempty"`
 	SkipPlan    bool   `json:"skip_plan,omitempty"`
+	// Client-chosen ID, one per prompt, so the request can be cancelled
+	// while in flight. Retrying a failed request sends the same ID again, so
+	// a backend that did get the first attempt can answer it only once.
+	RequestID string `json:"request_id,omitempty"`
+	// Per-session model override; empty uses the backend default.
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// Earlier message this prompt replies to. The quote itself is also part of
+	// Input, so backends that ignore this field still see the context.
+	ReplyTo *ReplyRef `json:"reply_to,omitempty"`
+	// Session overrides from /system and /env: text appended to the agent's
+	// system prompt, and variables set for the tools it runs.
+	SystemPrompt string            `json:"system_prompt,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	// Roster agent the prompt is directed at with "@name". The mention is
+	// also part of Input, so backends that ignore this field still see it.
+	Agent string `json:"agent,omitempty"`
+	// Tools the agent may call, when /tools disabled some in this session.
+	// nil allows all of them; an empty list allows none.
+	AllowedTools *[]string `json:"allowed_tools,omitempty"`
+	// Reply preferences from /prefs, under "reply_language", "verbosity"
+	// and "comment_style"; only the ones set are sent.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ReplyRef identifies the conversation message a prompt quotes.
+type ReplyRef struct {
+	MessageID string `json:"message_id"`
+	Role      string `json:"role"`      // "user" or "assistant"
+	Timestamp string `json:"timestamp"` // RFC 3339
+	Excerpt   string `json:"excerpt"`
 }
 
 // Signal classification metadata.
@@ -38,20 +69,65 @@ type OrchestrateResponse struct {
 	ToolsUsed      []string `json:"tools_used"`
 	IterationCount int      `json:"iteration_count"`
 	ExecutionMs    int64    `json:"execution_ms"`
+	RequestID      string   `json:"request_id,omitempty"`
+	Cancelled      bool     `json:"cancelled,omitempty"`
+}
+
+// CancelRequest for POST /api/v1/orchestrate/cancel.
+type CancelRequest struct {
+	SessionID string `json:"session_id"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // CommandEntry from GET /api/v1/commands.
 type CommandEntry struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Category    string `json:"category,omitempty"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Category    string        `json:"category,omitempty"`
+	Usage       string        `json:"usage,omitempty"`
+	Example     string        `json:"example,omitempty"`
+	Details     string        `json:"details,omitempty"`
+	Forms       []CommandForm `json:"forms,omitempty"`
+}
+
+// CommandForm is a form a command asks its values in when it is run with
+// Arg, such as "add" for /cron add or "" for the command alone.
+type CommandForm struct {
+	Arg    string         `json:"arg"`
+	Title  string         `json:"title,omitempty"`
+	Fields []CommandField `json:"fields"`
+}
+
+// CommandField is a value a CommandForm asks for, sent under Name. Pattern
+// is a regular expression the value must match; Hint says what it takes.
+type CommandField struct {
+	Name     string `json:"name"`
+	Label    string `json:"label,omitempty"`
+	Default  string `json:"default,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Secret   bool   `json:"secret,omitempty"`
+}
+
+// AgentRole is an agent of the roster, from GET /api/v1/agents.
+type AgentRole struct {
+	Name        string   `json:"name"`
+	Tier        string   `json:"tier"` // "elite", "specialist" or "utility"
+	Role        string   `json:"role,omitempty"`
+	Model       string   `json:"model,omitempty"` // model its tier maps to
+	Description string   `json:"description"`
+	Tools       []string `json:"tools,omitempty"`
+	Triggers    []string `json:"triggers,omitempty"`
+	EscalateTo  string   `json:"escalate_to,omitempty"`
 }
 
 // CommandExecuteRequest for POST /api/v1/commands/execute.
 type CommandExecuteRequest struct {
-	Command   string `json:"command"`
-	Arg       string `json:"arg"`
-	SessionID string `json:"session_id"`
+	Command   string            `json:"command"`
+	Arg       string            `json:"arg"`
+	SessionID string            `json:"session_id"`
+	Fields    map[string]string `json:"fields,omitempty"` // values of a CommandForm
 }
 
 // CommandExecuteResponse from POST /api/v1/commands/execute.
@@ -63,9 +139,23 @@ type CommandExecuteResponse struct {
 
 // ToolEntry from GET /api/v1/tools.
 type ToolEntry struct {
-	Name        string `json:"name"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Module      string         `json:"module,omitempty"`
+	Parameters  ToolParameters `json:"parameters"`
+}
+
+// ToolParameters is the JSON schema of a tool's arguments.
+type ToolParameters struct {
+	Properties map[string]ToolParameter `json:"properties"`
+	Required   []string                 `json:"required"`
+}
+
+// ToolParameter is one argument of a tool. Type is usually a string, but a
+// schema may give a list of types.
+type ToolParameter struct {
+	Type        any    `json:"type"`
 	Description string `json:"description"`
-	Module      string `json:"module,omitempty"`
 }
 
 // ErrorResponse for API errors.
@@ -94,6 +184,7 @@ type SessionInfo struct {
 	Title        string           `json:"title"`
 	MessageCount int              `json:"message_count"`
 	Messages     []SessionMessage `json:"messages,omitempty"`
+	Alive        bool             `json:"alive"` // an agent loop is running for it
 }
 
 // SessionMessage is a single message in a session's history.
@@ -103,11 +194,64 @@ type SessionMessage struct {
 	Timestamp string `json:"timestamp,omitempty"`
 }
 
-// SessionCreateResponse from POST /api/v1/sessions.
+// SessionCreateResponse from POST /api/v1/sessions. Status is "exists"
+// when a session restored by ID was still running; Restored counts the
+// messages a restored session was seeded with.
 type SessionCreateResponse struct {
 	ID        string `json:"id"`
 	CreatedAt string `json:"created_at"`
 	Title     string `json:"title"`
+	Status    string `json:"status,omitempty"`
+	Restored  int    `json:"restored,omitempty"`
+}
+
+// SessionRestoreRequest for POST /api/v1/sessions recreates a session the
+// backend lost under its ID, with the latest messages as the agent's context.
+type SessionRestoreRequest struct {
+	SessionID string           `json:"session_id"`
+	Messages  []SessionMessage `json:"messages"`
+}
+
+// SessionCompactRequest for POST /api/v1/sessions/:id/compact. A zero
+// TargetTokens compacts to the backend's default, half the context window.
+type SessionCompactRequest struct {
+	TargetTokens int `json:"target_tokens,omitempty"`
+}
+
+// SessionCompactResponse from POST /api/v1/sessions/:id/compact.
+type SessionCompactResponse struct {
+	SessionID    string `json:"session_id"`
+	TokensBefore int    `json:"tokens_before"`
+	TokensAfter  int    `json:"tokens_after"`
+	MaxTokens    int    `json:"max_tokens"`
+}
+
+// SessionCleanupRequest for POST /api/v1/sessions/cleanup. A session that is
+// not running matches when it meets every criterion given; IDs, when set,
+// limits the cleanup to those sessions, such as the ones a dry run listed.
+type SessionCleanupRequest struct {
+	Action            string   `json:"action"` // "archive" | "delete"
+	OlderThanDays     int      `json:"older_than_days,omitempty"`
+	FewerThanMessages int      `json:"fewer_than_messages,omitempty"`
+	IDs               []string `json:"ids,omitempty"`
+	DryRun            bool     `json:"dry_run,omitempty"`
+}
+
+// SessionCleanupResponse from POST /api/v1/sessions/cleanup: the sessions
+// archived or deleted, or that would be on a dry run.
+type SessionCleanupResponse struct {
+	Action   string           `json:"action"`
+	DryRun   bool             `json:"dry_run"`
+	Sessions []CleanedSession `json:"sessions"`
+	Count    int              `json:"count"`
+}
+
+// CleanedSession is a session in a SessionCleanupResponse.
+type CleanedSession struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	MessageCount int    `json:"message_count"`
+	LastActive   string `json:"last_active"`
 }
 
 // ModelEntry describes a single available model.
@@ -116,6 +260,14 @@ type ModelEntry struct {
 	Provider string `json:"provider"`
 	Size     int64  `json:"size,omitempty"`
 	Active   bool   `json:"active,omitempty"`
+
+	// Capability metadata; zero values mean "unknown".
+	ContextWindow int     `json:"context_window,omitempty"`
+	Vision        bool    `json:"vision,omitempty"`
+	Tools         bool    `json:"tools,omitempty"`
+	Reasoning     bool    `json:"reasoning,omitempty"`
+	InputPrice    float64 `json:"input_price,omitempty"`  // USD per 1M input tokens
+	OutputPrice   float64 `json:"output_price,omitempty"` // USD per 1M output tokens
 }
 
 // ModelListResponse from GET /api/v1/models.
@@ -151,6 +303,26 @@ type ClassifyResponse struct {
 	Signal Signal `json:"signal"`
 }
 
+// -- Token counting -----------------------------------------------------------
+
+// TokenCountRequest for POST /api/v1/tokens/count. With SessionID the
+// response also reports the tokens that session's conversation uses.
+type TokenCountRequest struct {
+	Text      string `json:"text"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// TokenCountResponse from POST /api/v1/tokens/count. Exact is false when the
+// tokenizer sidecar is down and Tokens is the backend's heuristic estimate.
+// ContextUsed is nil when the session is not running or is busy; both
+// context fields are zero on backends that predate them.
+type TokenCountResponse struct {
+	Tokens        int  `json:"tokens"`
+	Exact         bool `json:"exact"`
+	ContextWindow int  `json:"context_window,omitempty"`
+	ContextUsed   *int `json:"context_used,omitempty"`
+}
+
 // -- Tool execution -----------------------------------------------------------
 
 // ToolExecuteRequest for POST /api/v1/tools/:name/execute.
@@ -289,6 +461,123 @@ type MemoryRecallResponse struct {
 	Content string `json:"content"`
 }
 
+// MemoryEntry from GET /api/v1/memory/entries: one entry of MEMORY.md.
+type MemoryEntry struct {
+	ID         string  `json:"id"`
+	Category   string  `json:"category"`
+	Timestamp  string  `json:"timestamp"`
+	Content    string  `json:"content"`
+	Importance float64 `json:"importance"`
+	Pinned     bool    `json:"pinned"` // always recalled, never archived
+}
+
+// MemoryUpdate for PUT /api/v1/memory/entries/:id. Empty fields are left
+// as they are.
+type MemoryUpdate struct {
+	Content  string `json:"content,omitempty"`
+	Category string `json:"category,omitempty"`
+	Pinned   *bool  `json:"pinned,omitempty"`
+}
+
+// -- Hooks --------------------------------------------------------------------
+
+// Hook is one handler of the backend's hook pipeline.
+type Hook struct {
+	Name      string `json:"name"`
+	Event     string `json:"event"` // e.g. "pre_tool_use"
+	Priority  int    `json:"priority"`
+	Enabled   bool   `json:"enabled"`
+	Protected bool   `json:"protected"` // a guardrail that cannot be disabled
+}
+
+// HookBlock is an action a hook blocked.
+type HookBlock struct {
+	HookName  string `json:"hook_name"`
+	HookEvent string `json:"hook_event"`
+	Reason    string `json:"reason"`
+	SessionID string `json:"session_id"`
+	At        string `json:"at"`
+}
+
+// HooksStatus from GET /api/v1/hooks. RecentBlocks is newest first.
+type HooksStatus struct {
+	Hooks        []Hook      `json:"hooks"`
+	RecentBlocks []HookBlock `json:"recent_blocks"`
+}
+
+// -- Channels -----------------------------------------------------------------
+
+// ChannelField is one setting of a messaging channel. Secret values come
+// back masked to their last four characters.
+type ChannelField struct {
+	Key    string `json:"key"`
+	Secret bool   `json:"secret"`
+	Set    bool   `json:"set"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Channel is a messaging channel adapter with its settings.
+type Channel struct {
+	Name       string         `json:"name"`
+	Connected  bool           `json:"connected"`
+	Configured bool           `json:"configured"`
+	Enabled    bool           `json:"enabled"`
+	Fields     []ChannelField `json:"fields"`
+}
+
+// ChannelUpdate for PUT /api/v1/channels/:name. An empty field value clears
+// the field; fields left out are kept.
+type ChannelUpdate struct {
+	Fields  map[string]string `json:"fields,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+}
+
+// ChannelTest from POST /api/v1/channels/:name/test. Status is "connected"
+// or why the check failed, e.g. "not_running".
+type ChannelTest struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Status string `json:"status"`
+}
+
+// -- MCP ----------------------------------------------------------------------
+
+// MCPServer is an MCP server configured in the backend's mcp.json.
+type MCPServer struct {
+	Name      string   `json:"name"`
+	Transport string   `json:"transport"` // "stdio" or "http"
+	Command   string   `json:"command,omitempty"`
+	Args      []string `json:"args,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	Status    string   `json:"status"` // "connected", "connecting" or "error"
+	Error     string   `json:"error,omitempty"`
+	Tools     []string `json:"tools"`
+}
+
+// MCPServerConfig for POST /api/v1/mcp/servers: Command with its Args and
+// Env, or URL.
+type MCPServerConfig struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+}
+
+// -- Budget -------------------------------------------------------------------
+
+// Budget from GET /api/v1/budget, in USD. SessionLimit is nil when sessions
+// have no limit; SessionSpent is the spend of the session asked for.
+type Budget struct {
+	DailyLimit   float64  `json:"daily_limit"`
+	MonthlyLimit float64  `json:"monthly_limit"`
+	PerCallLimit float64  `json:"per_call_limit"`
+	SessionLimit *float64 `json:"session_limit"`
+	DailySpent   float64  `json:"daily_spent"`
+	MonthlySpent float64  `json:"monthly_spent"`
+	SessionSpent float64  `json:"session_spent"`
+}
+
 // -- Analytics ----------------------------------------------------------------
 
 // AnalyticsResponse from GET /api/v1/analytics.
@@ -300,14 +589,41 @@ type AnalyticsResponse struct {
 	Compactor map[string]any `json:"compactor"`
 }
 
+// FeedbackRequest for POST /api/v1/analytics/feedback rates an agent
+// response "up" or "down". Excerpt is the start of the rated response.
+type FeedbackRequest struct {
+	SessionID string `json:"session_id"`
+	MessageID string `json:"message_id,omitempty"`
+	Rating    string `json:"rating"`
+	Comment   string `json:"comment,omitempty"`
+	Excerpt   string `json:"excerpt,omitempty"`
+}
+
 // -- Scheduler ----------------------------------------------------------------
 
 // SchedulerJob from GET /api/v1/scheduler/jobs.
 type SchedulerJob struct {
-	Name         string `json:"name"`
-	Schedule     string `json:"schedule"`
-	FailureCount int    `json:"failure_count"`
-	CircuitOpen  bool   `json:"circuit_open"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Schedule      string `json:"schedule"` // cron expression, UTC
+	Type          string `json:"type"`     // "agent", "command" or "webhook"
+	Job           string `json:"job"`      // agent task
+	Enabled       bool   `json:"enabled"`
+	Spec          string `json:"spec,omitempty"` // schedule phrase the job was created from
+	NotifySession string `json:"notify_session,omitempty"`
+	FailureCount  int    `json:"failure_count"`
+	CircuitOpen   bool   `json:"circuit_open"`
+}
+
+// SchedulerJobRequest for POST /api/v1/scheduler/jobs. NotifySession receives
+// a schedule_result event after each run.
+type SchedulerJobRequest struct {
+	Name          string `json:"name"`
+	Schedule      string `json:"schedule"`
+	Type          string `json:"type"`
+	Job           string `json:"job"`
+	Spec          string `json:"spec,omitempty"`
+	NotifySession string `json:"notify_session,omitempty"`
 }
 
 // -- Machines -----------------------------------------------------------------
@@ -318,6 +634,35 @@ type MachineInfo struct {
 	Status string `json:"status"`
 }
 
+// -- Git ----------------------------------------------------------------------
+
+// GitFileStatus is one changed file from GET /api/v1/git/status. Path is
+// relative to the repository root.
+type GitFileStatus struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // "modified", "added", "deleted", "untracked", ...
+}
+
+// GitStatusResponse from GET /api/v1/git/status.
+type GitStatusResponse struct {
+	Branch string          `json:"branch"`
+	Clean  bool            `json:"clean"`
+	Files  []GitFileStatus `json:"files"`
+}
+
+// GitDiffResponse from GET /api/v1/git/diff.
+type GitDiffResponse struct {
+	Diff string `json:"diff"`
+}
+
+// SidecarInfo is one entry of GET /api/v1/sidecars. Health is "ready",
+// "degraded" or "unavailable".
+type SidecarInfo struct {
+	Name         string   `json:"name"`
+	Health       string   `json:"health"`
+	Capabilities []string `json:"capabilities"`
+}
+
 // -- Onboarding ---------------------------------------------------------------
 
 // OnboardingProvider describes a provider available for setup.
@@ -382,3 +727,41 @@ type OnboardingSetupResponse struct {
 	Provider string `json:"provider"`
 	Model    string `json:"model"`
 }
+
+// OnboardingVerifyRequest for POST /onboarding/verify.
+type OnboardingVerifyRequest struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	APIKey   string `json:"api_key,omitempty"`
+	EnvVar   string `json:"env_var,omitempty"`
+}
+
+// OnboardingVerifyResponse from POST /onboarding/verify.
+type OnboardingVerifyResponse struct {
+	Status  string `json:"status"` // "ok" | "error"
+	Message string `json:"message,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// -- Provider keys ------------------------------------------------------------
+
+// ProviderKeyEntry describes the key state of one LLM provider. The raw key
+// is never returned; Masked holds a display-safe form such as "sk-…a1b2".
+type ProviderKeyEntry struct {
+	Provider   string `json:"provider"`
+	Name       string `json:"name"`
+	EnvVar     string `json:"env_var"`
+	Configured bool   `json:"configured"`
+	Masked     string `json:"masked,omitempty"`
+	Source     string `json:"source,omitempty"` // "env" | "config"
+}
+
+// ProviderKeysResponse from GET /api/v1/providers/keys.
+type ProviderKeysResponse struct {
+	Providers []ProviderKeyEntry `json:"providers"`
+}
+
+// ProviderKeyRequest for POST /api/v1/providers/keys/:provider.
+type ProviderKeyRequest struct {
+	APIKey string `json:"api_key"`
+}
@@ -12,6 +12,127 @@ type Config struct {
 	DefaultModel string `json:"default_model,omitempty"`
 	BackendURL   string `json:"backend_url,omitempty"`
 	SidebarOpen  bool   `json:"sidebar_open,omitempty"`
+
+	// Pane sizes adjusted with Ctrl+arrows or by dragging. SidebarWidth is in
+	// columns; TasksHeight and AgentsHeight cap those panels in lines, where
+	// 1 collapses a panel to its summary line. 0 means automatic.
+	SidebarWidth int `json:"sidebar_width,omitempty"`
+	TasksHeight  int `json:"tasks_height,omitempty"`
+	AgentsHeight int `json:"agents_height,omitempty"`
+
+	// ColorMode forces "truecolor", "256" or "16" colors. Empty or "auto"
+	// follows the detected terminal. Theme "auto" follows the terminal
+	// background between the dark and light themes.
+	ColorMode string `json:"color_mode,omitempty"`
+
+	// Locale selects the interface language catalog, e.g. "de". Empty or
+	// "auto" follows LC_ALL / LC_MESSAGES / LANG.
+	Locale string `json:"locale,omitempty"`
+
+	// Accessibility: ScreenReader renders plain linear output with role
+	// prefixes; ReducedMotion stops spinners and cursor animation.
+	ScreenReader  bool `json:"screen_reader,omitempty"`
+	ReducedMotion bool `json:"reduced_motion,omitempty"`
+
+	// Speak reads agent replies aloud, toggled with /speak. SpeechCommand
+	// replaces the detected engine with a command and its arguments that
+	// reads the text to say from stdin, e.g. ["piper", "--model", "en.onnx",
+	// "--output-raw"].
+	Speak         bool     `json:"speak,omitempty"`
+	SpeechCommand []string `json:"speech_command,omitempty"`
+
+	// PaneStatus publishes the TUI's state for `osa status`: empty does so
+	// inside tmux or zellij, "on" always and "off" never.
+	PaneStatus string `json:"pane_status,omitempty"`
+
+	// Timestamps shows message times in the chat as "relative" ("2m ago")
+	// or "absolute" ("14:02"). Empty or "off" hides them.
+	Timestamps string `json:"timestamps,omitempty"`
+
+	// ContextWarnAt and ContextAlertAt are the percentages of the context
+	// window at which the context bar turns amber and red, each notifying
+	// once with a way to compact the conversation. 0 means 80 and 95.
+	ContextWarnAt  int `json:"context_warn_at,omitempty"`
+	ContextAlertAt int `json:"context_alert_at,omitempty"`
+
+	// HideNotices leaves system notices out of the chat, toggled with Alt+N
+	// or /notices.
+	HideNotices bool `json:"hide_notices,omitempty"`
+
+	// Automatic session archival, set with /sessions cleanup auto: on
+	// connecting, sessions last active more than ArchiveAfterDays ago and
+	// with fewer than ArchiveBelowMessages messages are archived, each
+	// criterion applying when it is not zero.
+	ArchiveAfterDays     int `json:"archive_after_days,omitempty"`
+	ArchiveBelowMessages int `json:"archive_below_messages,omitempty"`
+
+	// Model picker preferences, as "provider/model" keys.
+	FavoriteModels []string `json:"favorite_models,omitempty"`
+	RecentModels   []string `json:"recent_models,omitempty"`
+
+	// RecentCommands are the slash commands last run, with their arguments,
+	// most recent first. The palette lists them under "Recent".
+	RecentCommands []string `json:"recent_commands,omitempty"`
+
+	// SessionModels pins a "provider/model" to a session ID, overriding the
+	// global default for requests in that session.
+	SessionModels map[string]string `json:"session_models,omitempty"`
+
+	// SessionOverrides holds what /system and /env set for a session ID,
+	// sent with every request in that session.
+	SessionOverrides map[string]SessionOverride `json:"session_overrides,omitempty"`
+
+	// Reply preferences set with /prefs and sent as metadata with every
+	// orchestrate request: the language replies are written in, such as
+	// "German"; ReplyVerbosity "concise", "normal" or "detailed"; and
+	// CommentStyle "none", "brief" or "thorough" for comments in code the
+	// agent writes. Empty leaves each to the agent.
+	ReplyLanguage  string `json:"reply_language,omitempty"`
+	ReplyVerbosity string `json:"reply_verbosity,omitempty"`
+	CommentStyle   string `json:"comment_style,omitempty"`
+
+	// DestructiveGuard controls tool calls that look destructive: empty or
+	// "confirm" pauses output until the call is acknowledged, "warn" only
+	// flags it and "off" ignores it. DestructivePatterns adds regular
+	// expressions to the built-in ones.
+	DestructiveGuard    string   `json:"destructive_guard,omitempty"`
+	DestructivePatterns []string `json:"destructive_patterns,omitempty"`
+
+	// Snippets maps abbreviations to the text they expand to in the input
+	// on Tab or space, such as ";rev" to "Review this diff for bugs and
+	// style issues:".
+	Snippets map[string]string `json:"snippets,omitempty"`
+
+	// Aliases maps short slash commands to the commands they stand for,
+	// such as "/r" to "/review" or "/m4" to "/model
+	// anthropic/claude-sonnet-4"; arguments typed after an alias are
+	// appended.
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// StatusSegments picks the status bar segments, in order, from
+	// status.Segments, e.g. ["model", "context", "git", "clock"]. Empty
+	// keeps the default layout.
+	StatusSegments []string `json:"status_segments,omitempty"`
+
+	// The startup banner shows one of header.Tips per run, advancing
+	// TipIndex, and a "what's new" line when the version is newer than
+	// LastVersion, the one of the previous run. HideBannerTips turns both off.
+	TipIndex       int    `json:"tip_index,omitempty"`
+	LastVersion    string `json:"last_version,omitempty"`
+	HideBannerTips bool   `json:"hide_banner_tips,omitempty"`
+
+	// FrameBudgetMS is the time an update or a frame render may take before
+	// /stats counts it as slow. 0 means 16, one frame at 60 Hz.
+	FrameBudgetMS int `json:"frame_budget_ms,omitempty"`
+}
+
+// SessionOverride is a session's addition to the system prompt, the
+// environment variables its requests carry and the tools /tools disabled
+// for it.
+type SessionOverride struct {
+	System        string            `json:"system,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	DisabledTools []string          `json:"disabled_tools,omitempty"`
 }
 
 const filename = "tui.json"
@@ -43,7 +164,7 @@ func Save(profileDir string, cfg Config) error {
 
 func defaults() Config {
 	return Config{
-		Theme:       "dark",
+		Theme:       "auto",
 		SidebarOpen: false,
 	}
 }
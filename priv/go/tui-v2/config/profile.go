@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile names the unnamed profile stored directly in ~/.osa.
+const DefaultProfile = "default"
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// BaseDir returns ~/.osa.
+func BaseDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".osa")
+}
+
+// ProfilesDir returns the directory holding named profiles.
+func ProfilesDir() string { return filepath.Join(BaseDir(), "profiles") }
+
+// ProfilePath returns the state directory for a profile. The empty name and
+// DefaultProfile map to ~/.osa itself.
+func ProfilePath(name string) string {
+	if name == "" || name == DefaultProfile {
+		return BaseDir()
+	}
+	return filepath.Join(ProfilesDir(), name)
+}
+
+// ValidateProfileName rejects names that are empty, reserved or unsafe to
+// use as a directory name.
+func ValidateProfileName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("profile name is required")
+	case name == DefaultProfile:
+		return fmt.Errorf("profile name %q is reserved", name)
+	case !profileNameRe.MatchString(name):
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// ProfileExists reports whether name is DefaultProfile or has a directory.
+func ProfileExists(name string) bool {
+	if name == "" || name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(ProfilePath(name))
+	return err == nil && info.IsDir()
+}
+
+// ListProfiles returns DefaultProfile followed by the named profiles, sorted.
+func ListProfiles() []string {
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(ProfilesDir())
+	if err != nil {
+		return names
+	}
+	var named []string
+	for _, e := range entries {
+		if e.IsDir() && profileNameRe.MatchString(e.Name()) {
+			named = append(named, e.Name())
+		}
+	}
+	sort.Strings(named)
+	return append(names, named...)
+}
+
+// CreateProfile creates an empty named profile directory.
+func CreateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if ProfileExists(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return os.MkdirAll(ProfilePath(name), 0o755)
+}
+
+// DeleteProfile removes a named profile with its settings and credentials.
+func DeleteProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	return os.RemoveAll(ProfilePath(name))
+}
+
+// ReadCredentials returns the stored access and refresh tokens in
+// profileDir. Missing files yield empty strings.
+func ReadCredentials(profileDir string) (token, refreshToken string) {
+	if data, err := os.ReadFile(filepath.Join(profileDir, "token")); err == nil {
+		token = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(profileDir, "refresh_token")); err == nil {
+		refreshToken = strings.TrimSpace(string(data))
+	}
+	return token, refreshToken
+}
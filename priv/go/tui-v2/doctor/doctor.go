@@ -0,0 +1,387 @@
+// Package doctor runs the checks behind `osa doctor` and /doctor: whether
+// the backend is reachable and accepts the stored token, whether its
+// sidecars run, what the terminal supports and whether the profile's config
+// makes sense.
+//
+// Every check reports a result instead of failing silently, and each warning
+// or failure says what to do about it.
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/colorprofile"
+
+	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/config"
+	"github.com/miosa/osa-tui/guard"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/clipboard"
+	"github.com/miosa/osa-tui/ui/image"
+	"github.com/miosa/osa-tui/ui/status"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	OK Status = iota
+	Warn
+	Fail
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string // what to do about a warning or failure
+}
+
+// Options says what to check.
+type Options struct {
+	Client     *client.Client
+	ProfileDir string
+	ThemesDir  string
+	LocalesDir string
+	ThemeErrs  []error              // from loading the custom themes
+	Colors     colorprofile.Profile // color depth of the terminal
+}
+
+// timeout bounds each backend request, so an unresponsive backend fails its
+// check instead of hanging the report.
+const timeout = 5 * time.Second
+
+// Run performs every check in display order.
+func Run(o Options) []Result {
+	c := *o.Client
+	c.HTTPClient = &http.Client{Timeout: timeout}
+
+	backend := checkBackend(&c)
+	results := []Result{backend}
+	if backend.Status == Fail {
+		skipped := "skipped: backend unreachable"
+		results = append(results,
+			Result{Name: "auth", Status: Warn, Detail: skipped},
+			Result{Name: "sidecars", Status: Warn, Detail: skipped})
+	} else {
+		results = append(results, checkAuth(&c), checkSidecars(&c))
+	}
+	results = append(results, checkColors(o.Colors), checkImages(), checkClipboard())
+	return append(results, checkConfig(o)...)
+}
+
+// Failed returns the number of failed checks.
+func Failed(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == Fail {
+			n++
+		}
+	}
+	return n
+}
+
+// Format renders results as a plain-text report, one line per check with
+// the fix indented below it.
+func Format(results []Result) string {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+	var sb strings.Builder
+	for _, r := range results {
+		mark := style.Glyph("✓", "ok")
+		switch r.Status {
+		case Warn:
+			mark = style.Glyph("!", "warn")
+		case Fail:
+			mark = style.Glyph("✗", "fail")
+		}
+		fmt.Fprintf(&sb, "%-4s %-*s  %s\n", mark, width, r.Name, r.Detail)
+		if r.Fix != "" && r.Status != OK {
+			fmt.Fprintf(&sb, "     %*s  %s %s\n", width, "", style.Glyph("→", "fix:"), r.Fix)
+		}
+	}
+	failed, warned := Failed(results), 0
+	for _, r := range results {
+		if r.Status == Warn {
+			warned++
+		}
+	}
+	switch {
+	case failed > 0:
+		fmt.Fprintf(&sb, "\n%d failed, %d warnings", failed, warned)
+	case warned > 0:
+		fmt.Fprintf(&sb, "\nAll checks passed, %d warnings", warned)
+	default:
+		sb.WriteString("\nAll checks passed")
+	}
+	return sb.String()
+}
+
+// -- Backend ------------------------------------------------------------------
+
+func checkBackend(c *client.Client) Result {
+	r := Result{Name: "backend"}
+	h, err := c.Health()
+	if err != nil {
+		r.Status = Fail
+		r.Detail = fmt.Sprintf("%s unreachable: %v", c.BaseURL, err)
+		r.Fix = "start the backend with bin/osa, or point OSA_URL at a running one"
+		return r
+	}
+	r.Detail = fmt.Sprintf("%s · OSA %s · %s/%s", c.BaseURL, h.Version, h.Provider, h.Model)
+	if h.Status != "ok" {
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("%s reports status %q", c.BaseURL, h.Status)
+		r.Fix = "check the backend logs"
+	}
+	return r
+}
+
+func checkAuth(c *client.Client) Result {
+	r := Result{Name: "auth"}
+	err := c.CheckAuth()
+	switch {
+	case err == nil && c.Token == "":
+		r.Detail = "no token; the backend does not require one"
+	case err == nil:
+		r.Detail = "token accepted"
+	case errors.Is(err, client.ErrUnauthorized) && c.Token == "":
+		r.Status = Fail
+		r.Detail = "the backend requires a token and none is stored"
+		r.Fix = "run /login in the TUI, or set OSA_TOKEN"
+	case errors.Is(err, client.ErrUnauthorized):
+		r.Status = Fail
+		r.Detail = fmt.Sprintf("token rejected (%v)", err)
+		r.Fix = "run /login again; tokens expire, and OSA_TOKEN overrides the stored one"
+	default:
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("could not verify: %v", err)
+		r.Fix = "check the backend logs"
+	}
+	return r
+}
+
+func checkSidecars(c *client.Client) Result {
+	r := Result{Name: "sidecars"}
+	sidecars, err := c.ListSidecars()
+	if errors.Is(err, client.ErrNotSupported) {
+		return checkGit(c)
+	}
+	if err != nil {
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("could not list: %v", err)
+		r.Fix = "check the backend logs"
+		return r
+	}
+	if len(sidecars) == 0 {
+		r.Status = Warn
+		r.Detail = "none running; git status, diffs and token counts fall back or are missing"
+		r.Fix = "build osa-<name> with go build in priv/go/<name>, or install it in ~/.osa/bin, then restart the backend"
+		return r
+	}
+	var parts, down []string
+	for _, sc := range sidecars {
+		parts = append(parts, sc.Name+" "+sc.Health)
+		if sc.Health != "ready" {
+			down = append(down, sc.Name)
+		}
+	}
+	r.Detail = strings.Join(parts, " · ")
+	if len(down) > 0 {
+		r.Status = Warn
+		r.Fix = fmt.Sprintf("check the backend logs for %s; Go sidecars need their osa-<name> binary in priv/go/<name> or ~/.osa/bin", strings.Join(down, ", "))
+	}
+	return r
+}
+
+// checkGit probes the git sidecar through its status endpoint, for backends
+// without GET /api/v1/sidecars.
+func checkGit(c *client.Client) Result {
+	r := Result{Name: "sidecars"}
+	wd, _ := os.Getwd()
+	st, err := c.GitStatus(wd)
+	switch {
+	case err == nil:
+		r.Detail = "git running"
+		if st.Branch != "" {
+			r.Detail += " · branch " + st.Branch
+		}
+	case errors.Is(err, client.ErrNotSupported):
+		r.Status = Warn
+		r.Detail = "the backend has no git endpoint"
+		r.Fix = "upgrade the backend for /diff and the branch in the header"
+	default:
+		r.Status = Warn
+		r.Detail = fmt.Sprintf("git unavailable: %v", err)
+		r.Fix = "build osa-git with go build in priv/go/git, or install it in ~/.osa/bin, then restart the backend"
+	}
+	return r
+}
+
+// -- Terminal -----------------------------------------------------------------
+
+func checkColors(p colorprofile.Profile) Result {
+	r := Result{Name: "colors"}
+	switch p {
+	case colorprofile.TrueColor:
+		r.Detail = "truecolor"
+	case colorprofile.ANSI256:
+		r.Status = Warn
+		r.Detail = "256 colors; themes are approximated"
+		r.Fix = "set COLORTERM=truecolor if the terminal supports it"
+	case colorprofile.ANSI:
+		r.Status = Warn
+		r.Detail = "16 colors; themes are approximated"
+		r.Fix = "use a terminal with truecolor support, or set COLORTERM=truecolor"
+	default:
+		r.Status = Warn
+		r.Detail = "no colors"
+		r.Fix = "unset NO_COLOR, or check TERM"
+	}
+	return r
+}
+
+func checkImages() Result {
+	r := Result{Name: "images"}
+	if p := image.DetectProtocol(); p != image.ProtocolNone {
+		r.Detail = p.String() + " graphics"
+		return r
+	}
+	r.Status = Warn
+	r.Detail = "no inline image protocol; images show as placeholders"
+	r.Fix = "use kitty, WezTerm, Ghostty or iTerm2 for inline images"
+	return r
+}
+
+func checkClipboard() Result {
+	r := Result{Name: "clipboard"}
+	if cmd := clipboard.NativeCommand(); cmd != "" {
+		r.Detail = "OSC 52, falling back to " + filepath.Base(cmd)
+		return r
+	}
+	r.Status = Warn
+	r.Detail = "OSC 52 only; copies fail silently in terminals without it"
+	r.Fix = "install wl-clipboard, xclip or xsel"
+	return r
+}
+
+// -- Config -------------------------------------------------------------------
+
+// checkConfig validates tui.json. config.Load falls back to the defaults on
+// a parse error, so a broken file is otherwise ignored without a word.
+func checkConfig(o Options) []Result {
+	path := filepath.Join(o.ProfileDir, "tui.json")
+	r := Result{Name: "config", Detail: path}
+	var cfg config.Config
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		r.Detail = "no tui.json; using the defaults"
+	case err != nil:
+		r.Status = Fail
+		r.Detail = err.Error()
+		r.Fix = "check the permissions of " + o.ProfileDir
+		return []Result{r}
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			r.Status = Fail
+			r.Detail = fmt.Sprintf("%s: %v; every setting is ignored", path, err)
+			r.Fix = "fix the JSON, or delete the file to start over"
+			return []Result{r}
+		}
+	}
+
+	var problems, fixes []string
+	problem := func(p, fix string) {
+		problems = append(problems, p)
+		fixes = append(fixes, fix)
+	}
+	for _, err := range o.ThemeErrs {
+		problem("theme file "+err.Error(), "fix or remove the file in "+o.ThemesDir)
+	}
+	if cfg.Theme != "" && cfg.Theme != "auto" {
+		if _, ok := style.Themes[cfg.Theme]; !ok {
+			problem(fmt.Sprintf("unknown theme %q", cfg.Theme), "pick one with /theme")
+		}
+	}
+	if cfg.ColorMode != "" && cfg.ColorMode != "auto" {
+		if _, ok := style.ParseColorMode(cfg.ColorMode); !ok {
+			problem(fmt.Sprintf("invalid color_mode %q", cfg.ColorMode), `use "auto", "truecolor", "256" or "16"`)
+		}
+	}
+	if cfg.Locale != "" && cfg.Locale != "auto" {
+		// A region such as de_AT falls back to its language's catalog.
+		avail := i18n.Available(o.LocalesDir)
+		lang, _, _ := strings.Cut(strings.ReplaceAll(cfg.Locale, "-", "_"), "_")
+		if !slices.Contains(avail, cfg.Locale) && !slices.Contains(avail, lang) {
+			problem(fmt.Sprintf("no catalog for locale %q", cfg.Locale), "use one of "+strings.Join(avail, ", "))
+		}
+	}
+	if !slices.Contains([]string{"", "off", "relative", "absolute"}, cfg.Timestamps) {
+		problem(fmt.Sprintf("invalid timestamps %q", cfg.Timestamps), `use "relative", "absolute" or "off"`)
+	}
+	if !slices.Contains([]string{"", "confirm", "warn", "off"}, cfg.DestructiveGuard) {
+		problem(fmt.Sprintf("invalid destructive_guard %q", cfg.DestructiveGuard), `use "confirm", "warn" or "off"`)
+	}
+	if _, err := guard.New(cfg.DestructivePatterns); err != nil {
+		problem(err.Error(), "fix the regular expression in destructive_patterns")
+	}
+	for _, abbr := range slices.Sorted(maps.Keys(cfg.Snippets)) {
+		if abbr == "" || strings.ContainsFunc(abbr, unicode.IsSpace) {
+			problem(fmt.Sprintf("snippet abbreviation %q contains spaces and never expands", abbr), "use a single word such as ;rev")
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Aliases)) {
+		switch {
+		case len(name) < 2 || !strings.HasPrefix(name, "/") || strings.ContainsFunc(name, unicode.IsSpace):
+			problem(fmt.Sprintf("alias %q is not a slash command name and is never used", name), "use a single word such as /r")
+		case !strings.HasPrefix(strings.TrimSpace(cfg.Aliases[name]), "/"):
+			problem(fmt.Sprintf("alias %s stands for %q, not a slash command, and is never used", name, cfg.Aliases[name]), "make it stand for a command such as /review")
+		}
+	}
+	for _, t := range []struct {
+		key string
+		pct int
+	}{{"context_warn_at", cfg.ContextWarnAt}, {"context_alert_at", cfg.ContextAlertAt}} {
+		if t.pct < 0 || t.pct > 100 {
+			problem(fmt.Sprintf("%s %d is not a percentage", t.key, t.pct), "use 1 to 100, or leave it out for the default")
+		}
+	}
+	if cfg.ContextWarnAt > 0 && cfg.ContextAlertAt > 0 && cfg.ContextAlertAt < cfg.ContextWarnAt {
+		problem(fmt.Sprintf("context_alert_at %d is below context_warn_at %d", cfg.ContextAlertAt, cfg.ContextWarnAt), "make context_alert_at the higher one")
+	}
+	for _, seg := range cfg.StatusSegments {
+		if !slices.Contains(status.Segments, seg) {
+			problem(fmt.Sprintf("unknown status segment %q", seg), "use any of "+strings.Join(status.Segments, ", "))
+		}
+	}
+	if cfg.BackendURL != "" {
+		if u, err := url.Parse(cfg.BackendURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problem(fmt.Sprintf("invalid backend_url %q", cfg.BackendURL), "use a URL such as http://localhost:8089")
+		}
+	}
+
+	if len(problems) == 0 {
+		return []Result{r}
+	}
+	out := make([]Result, len(problems))
+	for i, p := range problems {
+		out[i] = Result{Name: "config", Status: Warn, Detail: p, Fix: fixes[i]}
+	}
+	return out
+}
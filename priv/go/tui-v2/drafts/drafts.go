@@ -0,0 +1,100 @@
+// Package drafts keeps the unsent input of each session, so switching
+// sessions or quitting does not lose a half-written prompt.
+//
+// The drafts of a profile share one file, <profileDir>/drafts.json, keyed by
+// session ID. Every save reads the file again, so TUIs running on the same
+// profile only overwrite each other's draft of the same session. The file is
+// readable by the owner only since prompts may contain secrets, and drafts
+// untouched for maxAge are dropped.
+package drafts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxAge is how long an untouched draft is kept.
+const maxAge = 30 * 24 * time.Hour
+
+// Draft is the unsent input of a session.
+type Draft struct {
+	Text   string    `json:"text,omitempty"`
+	Pastes []string  `json:"pastes,omitempty"` // blocks held as chips
+	Saved  time.Time `json:"saved"`
+}
+
+// Empty reports whether d holds nothing worth keeping.
+func (d Draft) Empty() bool { return d.Text == "" && len(d.Pastes) == 0 }
+
+// Path returns the drafts file of a profile state directory.
+func Path(profileDir string) string { return filepath.Join(profileDir, "drafts.json") }
+
+// Load returns the drafts of a profile by session ID. A missing or broken
+// file holds none.
+func Load(profileDir string) map[string]Draft {
+	out := make(map[string]Draft)
+	data, err := os.ReadFile(Path(profileDir))
+	if err != nil {
+		return out
+	}
+	if json.Unmarshal(data, &out) != nil {
+		return make(map[string]Draft)
+	}
+	for id, d := range out {
+		if d.Empty() || time.Since(d.Saved) > maxAge {
+			delete(out, id)
+		}
+	}
+	return out
+}
+
+// Get returns the draft of a session.
+func Get(profileDir, sessionID string) (Draft, bool) {
+	d, ok := Load(profileDir)[sessionID]
+	return d, ok
+}
+
+// Latest returns the most recently saved draft and its session, ok false
+// when none was saved within the last window.
+func Latest(profileDir string, window time.Duration) (sessionID string, d Draft, ok bool) {
+	for id, dd := range Load(profileDir) {
+		if time.Since(dd.Saved) <= window && (!ok || dd.Saved.After(d.Saved)) {
+			sessionID, d, ok = id, dd, true
+		}
+	}
+	return sessionID, d, ok
+}
+
+// Save stores the draft of a session, stamping it with the current time. An
+// empty draft removes the session's entry.
+func Save(profileDir, sessionID string, d Draft) error {
+	if sessionID == "" {
+		return nil
+	}
+	all := Load(profileDir)
+	if d.Empty() {
+		if _, ok := all[sessionID]; !ok {
+			return nil
+		}
+		delete(all, sessionID)
+	} else {
+		d.Saved = time.Now()
+		all[sessionID] = d
+	}
+	if err := os.MkdirAll(profileDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write a temporary file and rename it, so a crash mid-write cannot
+	// truncate every draft.
+	tmp := Path(profileDir) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, Path(profileDir))
+}
@@ -0,0 +1,66 @@
+// Package guard recognises destructive operations, such as recursive deletes,
+// force pushes and dropped tables, in the arguments of a tool call.
+//
+// The TUI only sees the hint the backend sends with a tool call start, the
+// first 60 characters of a command or a path, so matching is best effort:
+// it catches the common shapes and leaves blocking to the backend's hooks.
+package guard
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rule names a destructive operation and the pattern that recognises it.
+type Rule struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+// Rules are the built-in destructive operations.
+var Rules = []Rule{
+	{"recursive delete", regexp.MustCompile(`\brm\s+(?:-\S+\s+)*(?:-[A-Za-z]*[rR]|--recursive\b)`)},
+	{"force push", regexp.MustCompile(`\bgit\s+push\b.*\s(?:--force(?:-with-lease)?\b|-[A-Za-z]*f\b|\+\S)`)},
+	{"hard reset", regexp.MustCompile(`\bgit\s+reset\s+(?:\S+\s+)*--hard\b`)},
+	{"git clean", regexp.MustCompile(`\bgit\s+clean\s+(?:\S+\s+)*-[A-Za-z]*f`)},
+	{"branch delete", regexp.MustCompile(`\bgit\s+branch\s+(?:\S+\s+)*-D\b`)},
+	{"drop table", regexp.MustCompile(`(?i)\bdrop\s+(?:table|database|schema)\b`)},
+	{"truncate table", regexp.MustCompile(`(?i)\btruncate\s+table\b`)},
+	{"delete without where", regexp.MustCompile(`(?i)\bdelete\s+from\s+[\w."]+\s*(?:;|$)`)},
+	{"disk write", regexp.MustCompile(`\b(?:mkfs(?:\.\w+)?|dd\s+(?:\S+\s+)*of=/dev/)`)},
+	{"recursive chmod", regexp.MustCompile(`\bchmod\s+(?:\S+\s+)*-R\s+0?777\b`)},
+	{"infrastructure destroy", regexp.MustCompile(`\bterraform\s+destroy\b|\bkubectl\s+delete\b`)},
+}
+
+// Guard matches tool calls against the built-in Rules and extra patterns.
+type Guard struct {
+	rules []Rule
+}
+
+// New returns a Guard using the built-in Rules plus the regular expressions
+// in extra, each reported under its own source text.
+func New(extra []string) (*Guard, error) {
+	g := &Guard{rules: append([]Rule(nil), Rules...)}
+	for _, p := range extra {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("destructive pattern %q: %w", p, err)
+		}
+		g.rules = append(g.rules, Rule{Name: p, Re: re})
+	}
+	return g, nil
+}
+
+// Match returns the name of the first rule matching the arguments of a tool
+// call, and whether one matched.
+func (g *Guard) Match(args string) (string, bool) {
+	if g == nil || args == "" {
+		return "", false
+	}
+	for _, r := range g.rules {
+		if r.Re.MatchString(args) {
+			return r.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,170 @@
+// Package i18n provides the message catalog for user-facing TUI strings.
+//
+// Catalogs are keyed by the English source text (gettext style), so
+// untranslated strings fall back to English without a separate default
+// table. A catalog is a flat JSON object mapping source text to its
+// translation; format verbs must be kept in the same order:
+//
+//	{ "Loading %s models...": "Lade %s-Modelle..." }
+//
+// Built-in catalogs are embedded from locales/. A file with the same name
+// in the user locale directory (~/.osa/locales/<locale>.json) is layered on
+// top, so community translations need no rebuild.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+var (
+	mu      sync.RWMutex
+	locale  = "en"
+	catalog = map[string]string{}
+)
+
+// T returns the translation of msgid in the active locale, falling back to
+// msgid itself. With args, the result is formatted with fmt.Sprintf.
+func T(msgid string, args ...any) string {
+	mu.RLock()
+	s, ok := catalog[msgid]
+	mu.RUnlock()
+	if !ok || s == "" {
+		s = msgid
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(s, args...)
+	}
+	return s
+}
+
+// Locale returns the active locale tag, e.g. "de" or "pt_BR".
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Init activates a locale. An empty name selects one from the environment
+// (LC_ALL, LC_MESSAGES, LANG). A region-qualified locale such as "de_AT"
+// falls back to its language catalog "de". English needs no catalog; an
+// unknown locale leaves English active and returns an error.
+func Init(name, userDir string) error {
+	if name == "" || name == "auto" {
+		name = DetectLocale()
+	}
+	name = normalize(name)
+
+	merged := map[string]string{}
+	found := name == "en" || strings.HasPrefix(name, "en_")
+	for _, tag := range candidates(name) {
+		if loadInto(merged, tag, userDir) {
+			found = true
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !found {
+		locale, catalog = "en", map[string]string{}
+		return fmt.Errorf("no catalog for locale %q", name)
+	}
+	locale, catalog = name, merged
+	return nil
+}
+
+// Available lists built-in and user-provided locales, plus "en".
+func Available(userDir string) []string {
+	seen := map[string]bool{"en": true}
+	if entries, err := builtin.ReadDir("locales"); err == nil {
+		for _, e := range entries {
+			seen[strings.TrimSuffix(e.Name(), ".json")] = true
+		}
+	}
+	if entries, err := os.ReadDir(userDir); err == nil {
+		for _, e := range entries {
+			if filepath.Ext(e.Name()) == ".json" {
+				seen[strings.TrimSuffix(e.Name(), ".json")] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DetectLocale reads the POSIX locale environment variables in precedence
+// order. "C" and "POSIX" map to English.
+func DetectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if v == "C" || v == "POSIX" || strings.HasPrefix(v, "C.") {
+			return "en"
+		}
+		return normalize(v)
+	}
+	return "en"
+}
+
+// normalize turns "de_DE.UTF-8@euro" or "pt-br" into "de_DE" / "pt_BR".
+func normalize(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i]
+	}
+	lang, region, ok := strings.Cut(strings.ReplaceAll(s, "-", "_"), "_")
+	lang = strings.ToLower(lang)
+	if !ok || region == "" {
+		return lang
+	}
+	return lang + "_" + strings.ToUpper(region)
+}
+
+// candidates returns catalogs to layer, least specific first.
+func candidates(name string) []string {
+	if lang, _, ok := strings.Cut(name, "_"); ok {
+		return []string{lang, name}
+	}
+	return []string{name}
+}
+
+// loadInto merges the built-in and user catalogs for tag into dst and
+// reports whether either existed.
+func loadInto(dst map[string]string, tag, userDir string) bool {
+	found := false
+	if data, err := builtin.ReadFile("locales/" + tag + ".json"); err == nil {
+		found = mergeJSON(dst, data) || found
+	}
+	if userDir != "" {
+		if data, err := os.ReadFile(filepath.Join(userDir, tag+".json")); err == nil {
+			found = mergeJSON(dst, data) || found
+		}
+	}
+	return found
+}
+
+func mergeJSON(dst map[string]string, data []byte) bool {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return false
+	}
+	for k, v := range m {
+		dst[k] = v
+	}
+	return true
+}
@@ -3,16 +3,21 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/miosa/osa-tui/app"
 	"github.com/miosa/osa-tui/client"
+	"github.com/miosa/osa-tui/config"
 	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/transcript"
 )
 
 var version = "dev"
@@ -21,10 +26,38 @@ func main() {
 	profileFlag := flag.String("profile", "", "Named profile for state isolation (~/.osa/profiles/<name>)")
 	devFlag := flag.Bool("dev", false, "Dev mode (alias for --profile dev, port 19001)")
 	noColor := flag.Bool("no-color", false, "Disable ANSI colors")
+	accessibleFlag := flag.Bool("accessible", false, "Screen-reader mode: plain linear output, no animation")
+	reducedMotion := flag.Bool("reduced-motion", false, "Disable animated spinners and cursor blink")
+	minimal := flag.Bool("minimal", false, "Low-bandwidth mode for slow SSH links and serial consoles: no gradients or animation, fewer redraws")
+	colorsFlag := flag.String("colors", "", "Force color depth: truecolor, 256 or 16 (default: detect)")
+	sessionFlag := flag.String("session", "", "Open session <id> once connected")
+	resumeFlag := flag.Bool("resume", false, "Open the most recently active session once connected")
+	logTranscript := flag.Bool("log-transcript", false, "Append prompts, replies and tool calls to <profile>/transcripts/<date>.jsonl")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	flag.BoolVar(showVersion, "V", false, "Show version and exit")
+	flag.Usage = usage
 	flag.Parse()
 
+	// Arguments that don't name a subcommand are the first prompt.
+	if flag.NArg() > 0 {
+		ok, err := runSubcommand(flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "osa: %v\n", err)
+			os.Exit(2)
+		}
+		if ok {
+			os.Exit(0)
+		}
+		app.StartPrompt = strings.Join(flag.Args(), " ")
+	}
+
+	if *sessionFlag != "" && *resumeFlag {
+		fmt.Fprintln(os.Stderr, "osa: --session and --resume are mutually exclusive")
+		os.Exit(2)
+	}
+	app.StartSession = *sessionFlag
+	app.ResumeLatest = *resumeFlag
+
 	if *showVersion {
 		fmt.Printf("osa %s\n", version)
 		os.Exit(0)
@@ -35,48 +68,56 @@ func main() {
 		os.Setenv("NO_COLOR", "1")
 	}
 
-	baseURL := os.Getenv("OSA_URL")
-	if baseURL == "" {
-		baseURL = "http://localhost:8089"
-	}
+	profile, baseURL := resolveTarget(*profileFlag, *devFlag)
 	token := os.Getenv("OSA_TOKEN")
 
-	profile := *profileFlag
-	if *devFlag {
-		profile = "dev"
-		if baseURL == "http://localhost:8089" {
-			baseURL = "http://localhost:19001"
+	if profile != "" && profile != config.DefaultProfile {
+		if err := config.ValidateProfileName(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "osa: %v\n", err)
+			os.Exit(2)
 		}
 	}
+	app.ProfileName = profile
+	app.ProfileDir = config.ProfilePath(profile)
+	os.MkdirAll(app.ProfileDir, 0755)
+
+	// Diagnostics, such as differences between the REST and SSE deliveries
+	// of an answer, go to a log file: the terminal belongs to the UI.
+	if f, err := tea.LogToFile(filepath.Join(app.ProfileDir, "tui.log"), "osa"); err == nil {
+		defer f.Close()
+	} else {
+		log.SetOutput(io.Discard)
+	}
 
-	var refreshToken string
-
-	if profile != "" {
-		home, _ := os.UserHomeDir()
-		app.ProfileDir = filepath.Join(home, ".osa", "profiles", profile)
-		os.MkdirAll(app.ProfileDir, 0755)
+	storedToken, refreshToken := config.ReadCredentials(app.ProfileDir)
+	if token == "" {
+		token = storedToken
+	}
 
-		if token == "" {
-			if data, err := os.ReadFile(filepath.Join(app.ProfileDir, "token")); err == nil {
-				token = strings.TrimSpace(string(data))
-			}
+	if *colorsFlag != "" {
+		if _, ok := style.ParseColorMode(*colorsFlag); !ok && *colorsFlag != "auto" {
+			fmt.Fprintf(os.Stderr, "osa: invalid --colors %q (want truecolor, 256 or 16)\n", *colorsFlag)
+			os.Exit(2)
 		}
-		if data, err := os.ReadFile(filepath.Join(app.ProfileDir, "refresh_token")); err == nil {
-			refreshToken = strings.TrimSpace(string(data))
-		}
-	} else {
-		home, _ := os.UserHomeDir()
-		app.ProfileDir = filepath.Join(home, ".osa")
-		if token == "" {
-			if data, err := os.ReadFile(filepath.Join(app.ProfileDir, "token")); err == nil {
-				token = strings.TrimSpace(string(data))
-			}
-		}
-		if data, err := os.ReadFile(filepath.Join(app.ProfileDir, "refresh_token")); err == nil {
-			refreshToken = strings.TrimSpace(string(data))
+		app.ColorModeFlag = *colorsFlag
+	}
+
+	if *logTranscript {
+		log, err := transcript.Open(transcript.Dir(app.ProfileDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "osa: --log-transcript: %v\n", err)
+			os.Exit(2)
 		}
+		app.TranscriptLog = log
 	}
 
+	app.ScreenReaderFlag = *accessibleFlag
+	app.ReducedMotionFlag = *reducedMotion
+	app.MinimalFlag = *minimal
+
+	app.ThemesDir = filepath.Join(config.BaseDir(), "themes")
+	app.LocalesDir = filepath.Join(config.BaseDir(), "locales")
+
 	// Auto-detect terminal background and set theme before any rendering.
 	if lipgloss.HasDarkBackground(os.Stdin, os.Stdout) {
 		style.SetTheme("dark")
@@ -96,15 +137,42 @@ func main() {
 
 	// In bubbletea v2, WithAltScreen and WithMouseCellMotion are no longer
 	// ProgramOptions. They are configured on the View struct returned by the
-	// model's View() method. Pass no options here.
-	p := tea.NewProgram(m)
+	// model's View() method. Only --minimal passes an option, to draw
+	// fewer frames.
+	var opts []tea.ProgramOption
+	if *minimal {
+		opts = append(opts, tea.WithFPS(app.MinimalFPS))
+	}
+	p := tea.NewProgram(m, opts...)
 
 	go func() {
 		p.Send(app.ProgramReady{Program: p})
 	}()
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if fm, ok := final.(app.Model); ok {
+		fm.Close()
+	}
+	// Stop OS appearance reports enabled in Init so they don't leak into the shell.
+	fmt.Fprint(os.Stdout, ansi.ResetModeLightDark)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "osa: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// resolveTarget returns the profile and backend URL selected by --profile,
+// --dev and OSA_URL.
+func resolveTarget(profile string, dev bool) (string, string) {
+	baseURL := os.Getenv("OSA_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8089"
+	}
+	if dev {
+		profile = "dev"
+		if baseURL == "http://localhost:8089" {
+			baseURL = "http://localhost:19001"
+		}
+	}
+	return profile, baseURL
+}
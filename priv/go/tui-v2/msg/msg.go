@@ -38,6 +38,8 @@ type OrchestrateResult struct {
 	ToolsUsed      []string
 	IterationCount int
 	ExecutionMs    int64
+	RequestID      string // ID sent with the request, set even on error
+	Cancelled      bool   // backend stopped the request after a cancel
 	Err            error
 }
 
@@ -75,9 +77,18 @@ type ToolCallEnd struct {
 	Success    bool   `json:"success"`
 }
 
+// ToolOutput from SSE system_event "tool_output": a chunk of a running
+// tool's output at byte Offset.
+type ToolOutput struct {
+	Name   string `json:"name"`
+	Output string `json:"output"`
+	Offset int64  `json:"offset"`
+}
+
 // LLMRequest from SSE event "llm_request".
 type LLMRequest struct {
-	Iteration int `json:"iteration"`
+	Iteration     int `json:"iteration"`
+	MaxIterations int `json:"max_iterations"`
 }
 
 // LLMResponse from SSE event "llm_response".
@@ -134,6 +145,11 @@ type ThinkingDelta struct {
 	Text string
 }
 
+// StreamingDelta is a chunk of streamed response text, for throughput.
+type StreamingDelta struct {
+	Text string
+}
+
 // -- UI events --
 
 type TickMsg struct{}
@@ -181,10 +197,16 @@ type SSEParseWarning struct {
 // -- Model selection --
 
 type ModelEntry struct {
-	Name     string
-	Provider string
-	Size     int64
-	Active   bool
+	Name          string
+	Provider      string
+	Size          int64
+	Active        bool
+	ContextWindow int
+	Vision        bool
+	Tools         bool
+	Reasoning     bool
+	InputPrice    float64
+	OutputPrice   float64
 }
 
 type ModelListResult struct {
@@ -253,3 +275,39 @@ type OnboardingComplete struct {
 type OnboardingSetupError struct {
 	Err error
 }
+
+// OnboardingVerifyResult carries the outcome of the provider connection test.
+type OnboardingVerifyResult struct {
+	Seq          int // of the OnboardingVerifyRequest it answers
+	OK           bool
+	Skipped      bool   // backend cannot verify; setup may proceed unverified
+	MissingModel string // local Ollama model that is not installed yet
+	Message      string
+	Hint         string
+	Err          error
+}
+
+// -- Provider keys --
+
+// ProviderKey mirrors client.ProviderKeyEntry for the msg layer.
+type ProviderKey struct {
+	Provider   string
+	Name       string
+	EnvVar     string
+	Configured bool
+	Masked     string
+	Source     string
+}
+
+// ProviderKeysResult carries the provider key listing for the /keys dialog.
+type ProviderKeysResult struct {
+	Keys []ProviderKey
+	Err  error
+}
+
+// ProviderKeyUpdated reports the outcome of storing or removing a key.
+type ProviderKeyUpdated struct {
+	Provider string
+	Removed  bool
+	Err      error
+}
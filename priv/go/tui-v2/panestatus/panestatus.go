@@ -0,0 +1,157 @@
+// Package panestatus publishes what each running TUI is doing, so a
+// terminal multiplexer can show it for panes in the background.
+//
+// Every TUI keeps a small JSON file, <dir>/<pid>.json, with its state and
+// the replies that arrived since the user last looked, and rewrites it when
+// either changes and once a minute. `osa status` reads the files back for a
+// tmux status line or a zellij bar. A file whose process exited without
+// removing it goes stale after StaleAfter and is skipped.
+package panestatus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// States of a TUI.
+const (
+	StateIdle    = "idle"
+	StateWorking = "working" // a turn is being processed
+	StateWaiting = "waiting" // a plan or a tool call awaits the user's answer
+)
+
+// StaleAfter is how long a file lasts without being rewritten before its
+// TUI is taken to have exited.
+const StaleAfter = 3 * time.Minute
+
+// Status is the published state of one TUI.
+type Status struct {
+	PID        int       `json:"pid"`
+	State      string    `json:"state"`
+	Unread     int       `json:"unread"` // replies since the user last typed in or focused the pane
+	Session    string    `json:"session,omitempty"`
+	TmuxPane   string    `json:"tmux_pane,omitempty"`   // $TMUX_PANE, e.g. "%3"
+	ZellijPane string    `json:"zellij_pane,omitempty"` // $ZELLIJ_PANE_ID
+	Updated    time.Time `json:"updated"`
+}
+
+// Dir returns the directory the files are kept in below the base state
+// directory, shared by all profiles.
+func Dir(baseDir string) string { return filepath.Join(baseDir, "panes") }
+
+// Multiplexed reports whether the process runs inside tmux or zellij.
+func Multiplexed() bool {
+	return os.Getenv("TMUX") != "" || os.Getenv("ZELLIJ") != ""
+}
+
+// Writer keeps the file of the current process.
+type Writer struct {
+	path string
+	last Status
+}
+
+// Open creates dir if needed and returns a Writer for the current process.
+func Open(dir string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create pane status dir: %w", err)
+	}
+	return &Writer{path: filepath.Join(dir, fmt.Sprintf("%d.json", os.Getpid()))}, nil
+}
+
+// Publish writes s when it differs from what was last written, or always
+// when force is set, to show the process is still running.
+func (w *Writer) Publish(s Status, force bool) error {
+	s.PID = os.Getpid()
+	s.TmuxPane = os.Getenv("TMUX_PANE")
+	s.ZellijPane = os.Getenv("ZELLIJ_PANE_ID")
+	s.Updated = w.last.Updated
+	if s == w.last && !force {
+		return nil
+	}
+	s.Updated = time.Now()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// Write a temporary file and rename it, so readers never see half a file.
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return err
+	}
+	w.last = s
+	return nil
+}
+
+// Remove deletes the file, when the TUI exits.
+func (w *Writer) Remove() {
+	_ = os.Remove(w.path)
+}
+
+// Read returns the statuses of the running TUIs by process ID, dropping
+// stale files.
+func Read(dir string) ([]Status, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Status
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var s Status
+		if json.Unmarshal(data, &s) != nil {
+			continue
+		}
+		if time.Since(s.Updated) > StaleAfter {
+			_ = os.Remove(path)
+			continue
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PID < list[j].PID })
+	return list, nil
+}
+
+// Summary describes the statuses in one line, such as
+// "1 working · 2 unread", or "" when all are idle with nothing unread.
+func Summary(list []Status) string {
+	var working, waiting, unread int
+	for _, s := range list {
+		switch s.State {
+		case StateWorking:
+			working++
+		case StateWaiting:
+			waiting++
+		}
+		unread += s.Unread
+	}
+	var parts []string
+	if working > 0 {
+		parts = append(parts, fmt.Sprintf("%d working", working))
+	}
+	if waiting > 0 {
+		parts = append(parts, fmt.Sprintf("%d waiting", waiting))
+	}
+	if unread > 0 {
+		parts = append(parts, fmt.Sprintf("%d unread", unread))
+	}
+	return strings.Join(parts, " · ")
+}
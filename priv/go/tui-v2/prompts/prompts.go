@@ -0,0 +1,203 @@
+// Package prompts stores reusable prompt templates as plain files.
+//
+// A library is a directory of *.md or *.txt files. Subdirectories group
+// templates, so "review/go.md" is the template "review/go". Placeholders are
+// written {{name}} or {{name|default}} and are filled in before the prompt is
+// used. Templates are shared by copying their files between libraries.
+package prompts
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// exts lists the accepted template file extensions; new templates use the
+// first one.
+var exts = []string{".md", ".txt"}
+
+var (
+	segmentRe     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*(?:\|([^}]*))?\}\}`)
+)
+
+// Template is a stored prompt.
+type Template struct {
+	Name string // slash-separated path relative to the library, without extension
+	Path string
+	Body string
+}
+
+// Var is a placeholder in a template body.
+type Var struct {
+	Name    string
+	Default string
+}
+
+// Dir returns the template library of a profile state directory.
+func Dir(profileDir string) string { return filepath.Join(profileDir, "prompts") }
+
+// ValidateName rejects names that are empty or would escape the library.
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	for _, seg := range strings.Split(name, "/") {
+		if !segmentRe.MatchString(seg) {
+			return fmt.Errorf("invalid template name %q (use letters, digits, '.', '_', '-' and '/' for groups)", name)
+		}
+	}
+	return nil
+}
+
+// List returns every template in dir sorted by name. A missing directory is
+// an empty library.
+func List(dir string) ([]Template, error) {
+	var out []Template
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !hasExt(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		if ValidateName(name) != nil {
+			return nil
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out = append(out, Template{Name: name, Path: path, Body: string(body)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Load reads the named template.
+func Load(dir, name string) (Template, error) {
+	if err := ValidateName(name); err != nil {
+		return Template{}, err
+	}
+	for _, ext := range exts {
+		path := filepath.Join(dir, filepath.FromSlash(name)+ext)
+		body, err := os.ReadFile(path)
+		if err == nil {
+			return Template{Name: name, Path: path, Body: string(body)}, nil
+		}
+		if !os.IsNotExist(err) {
+			return Template{}, err
+		}
+	}
+	return Template{}, fmt.Errorf("template %q not found", name)
+}
+
+// Save writes body as the named template, replacing an existing one.
+func Save(dir, name, body string) (Template, error) {
+	if err := ValidateName(name); err != nil {
+		return Template{}, err
+	}
+	path := filepath.Join(dir, filepath.FromSlash(name)+exts[0])
+	if t, err := Load(dir, name); err == nil {
+		path = t.Path
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Template{}, err
+	}
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		return Template{}, err
+	}
+	return Template{Name: name, Path: path, Body: body}, nil
+}
+
+// Delete removes the named template.
+func Delete(dir, name string) error {
+	t, err := Load(dir, name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(t.Path)
+}
+
+// Import copies the template file at src into dir, named after the file.
+// It refuses to overwrite an existing template.
+func Import(dir, src string) (Template, error) {
+	if !hasExt(src) {
+		return Template{}, fmt.Errorf("%s: templates must be %s files", src, strings.Join(exts, " or "))
+	}
+	body, err := os.ReadFile(src)
+	if err != nil {
+		return Template{}, err
+	}
+	name := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	if _, err := Load(dir, name); err == nil {
+		return Template{}, fmt.Errorf("template %q already exists", name)
+	}
+	return Save(dir, name, string(body))
+}
+
+// Export writes the named template to dst.
+func Export(dir, name, dst string) error {
+	t, err := Load(dir, name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, []byte(t.Body), 0o644)
+}
+
+// Vars returns the template's placeholders in order of first appearance.
+// A repeated placeholder keeps the first default given for it.
+func (t Template) Vars() []Var {
+	var vars []Var
+	seen := make(map[string]bool)
+	for _, m := range placeholderRe.FindAllStringSubmatch(t.Body, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		vars = append(vars, Var{Name: m[1], Default: strings.TrimSpace(m[2])})
+	}
+	return vars
+}
+
+// Fill substitutes values into the placeholders. Placeholders without a
+// value use their default.
+func (t Template) Fill(values map[string]string) string {
+	out := placeholderRe.ReplaceAllStringFunc(t.Body, func(s string) string {
+		m := placeholderRe.FindStringSubmatch(s)
+		if v, ok := values[m[1]]; ok && v != "" {
+			return v
+		}
+		return strings.TrimSpace(m[2])
+	})
+	return strings.TrimRight(out, "\n")
+}
+
+func hasExt(path string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Package redact masks secrets such as API keys, tokens and private key
+// blocks in text before it is shown or copied.
+//
+// Matching is pattern based and errs towards masking: a value assigned to a
+// name containing "token" or "password" is hidden even when it is not a
+// credential. Masked values keep their first characters so the kind of key
+// stays recognisable, e.g. "sk-a…[redacted]".
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Marker replaces the hidden part of a secret.
+const Marker = "…" + blank
+
+// blank replaces a secret too short to keep a prefix of.
+const blank = "[redacted]"
+
+// keep is the number of leading characters a masked token keeps.
+const keep = 4
+
+// tokenRes match secrets with a recognisable shape; the whole match is masked.
+var tokenRes = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),                                         // Anthropic, OpenAI
+	regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{30,}`),                      // GitHub
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{40,}`),                                  // GitHub fine-grained
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),                                      // GitLab
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),                                   // AWS access key ID
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                                 // Slack
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                                         // Google
+	regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}`),                          // Stripe
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), // JWT
+}
+
+// prefixRes match a label followed by a secret; group 1 is the label, kept
+// as is, and group 2 the value, which is masked.
+var prefixRes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(bearer\s+)([A-Za-z0-9._~+/-]{16,}=*)`),
+	regexp.MustCompile(`(?i)\b([A-Za-z0-9_.-]*(?:api[_-]?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*["']?)([^\s"'<>,;]{8,})`),
+}
+
+var pemRe = regexp.MustCompile(`(?s)(-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----).*?(-----END [A-Z0-9 ]*PRIVATE KEY-----)`)
+
+var revealed atomic.Bool
+
+// SetRevealed turns masking by Display off (true) or back on (false).
+func SetRevealed(on bool) { revealed.Store(on) }
+
+// Revealed reports whether Display shows secrets unmasked.
+func Revealed() bool { return revealed.Load() }
+
+// Display returns s with secrets masked, or s unchanged while secrets are
+// revealed.
+func Display(s string) string {
+	if revealed.Load() {
+		return s
+	}
+	out, _ := Scrub(s)
+	return out
+}
+
+// Scrub masks every secret in s and returns the result and the number of
+// secrets masked.
+func Scrub(s string) (string, int) {
+	n := 0
+	s = pemRe.ReplaceAllStringFunc(s, func(m string) string {
+		n++
+		sub := pemRe.FindStringSubmatch(m)
+		return sub[1] + "\n" + blank + "\n" + sub[2]
+	})
+	for _, re := range tokenRes {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			n++
+			return mask(m)
+		})
+	}
+	for _, re := range prefixRes {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			sub := re.FindStringSubmatch(m)
+			if strings.Contains(sub[2], Marker) {
+				return m // already masked by a token pattern
+			}
+			n++
+			return sub[1] + mask(sub[2])
+		})
+	}
+	return s, n
+}
+
+// mask keeps the first characters of a secret and replaces the rest.
+func mask(s string) string {
+	if strings.HasSuffix(s, Marker) {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= keep*2 {
+		return blank
+	}
+	return string(r[:keep]) + Marker
+}
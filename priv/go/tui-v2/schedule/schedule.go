@@ -0,0 +1,294 @@
+// Package schedule turns schedule phrases such as "every weekday 9am" into
+// the 5-field cron expressions run by the backend scheduler, and computes
+// upcoming run times.
+//
+// The backend evaluates cron expressions in UTC. Phrases name local times,
+// which Parse converts with the current UTC offset, so a schedule created
+// before a daylight saving change runs an hour off afterwards.
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	cronRe    = regexp.MustCompile(`^[0-9*/,-]+( [0-9*/,-]+){4}$`)
+	clockRe   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	ordinalRe = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+)
+
+var weekdays = map[string]int{
+	"sun": 0, "sunday": 0, "sundays": 0,
+	"mon": 1, "monday": 1, "mondays": 1,
+	"tue": 2, "tues": 2, "tuesday": 2, "tuesdays": 2,
+	"wed": 3, "wednesday": 3, "wednesdays": 3,
+	"thu": 4, "thur": 4, "thurs": 4, "thursday": 4, "thursdays": 4,
+	"fri": 5, "friday": 5, "fridays": 5,
+	"sat": 6, "saturday": 6, "saturdays": 6,
+}
+
+// filler words carry no meaning in a phrase.
+var filler = map[string]bool{"every": true, "each": true, "at": true, "on": true, "the": true, "and": true, "of": true}
+
+// Parse converts spec to a cron expression in UTC. spec is either a raw cron
+// expression, taken as UTC, or a phrase in loc:
+//
+//	every minute | every 15 minutes | hourly | every 2 hours
+//	every day 9am | daily at 18:30 | every weekday 9am | weekends at noon
+//	every monday 9am | every mon, thu at 14:15 | every month on the 1st 8am
+func Parse(spec string, loc *time.Location, now time.Time) (string, error) {
+	spec = strings.Join(strings.Fields(strings.ToLower(spec)), " ")
+	if cronRe.MatchString(spec) {
+		if _, err := parseCron(spec); err != nil {
+			return "", err
+		}
+		return spec, nil
+	}
+
+	var toks []string
+	for _, t := range strings.Fields(strings.ReplaceAll(spec, ",", " ")) {
+		if !filler[t] {
+			toks = append(toks, t)
+		}
+	}
+	if len(toks) == 0 {
+		return "", fmt.Errorf("empty schedule")
+	}
+
+	// Interval schedules need no clock time.
+	switch {
+	case len(toks) == 1 && (toks[0] == "minute" || toks[0] == "minutely"):
+		return "* * * * *", nil
+	case len(toks) == 1 && (toks[0] == "hour" || toks[0] == "hourly"):
+		return "0 * * * *", nil
+	case len(toks) == 2 && isUnit(toks[1], "minute", "min"):
+		n, err := strconv.Atoi(toks[0])
+		if err != nil || n < 1 || n > 59 {
+			return "", fmt.Errorf("minutes must be 1-59, got %q", toks[0])
+		}
+		return fmt.Sprintf("*/%d * * * *", n), nil
+	case len(toks) == 2 && isUnit(toks[1], "hour", "hr"):
+		n, err := strconv.Atoi(toks[0])
+		if err != nil || n < 1 || n > 23 {
+			return "", fmt.Errorf("hours must be 1-23, got %q", toks[0])
+		}
+		return fmt.Sprintf("0 */%d * * *", n), nil
+	}
+
+	// Calendar schedules: days plus a clock time.
+	hour, minute := -1, 0
+	dow := map[int]bool{}
+	dom := 0
+	daily, monthly := false, false
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if i+1 < len(toks) && (toks[i+1] == "am" || toks[i+1] == "pm") {
+			t += toks[i+1]
+			i++
+		}
+		if h, m, ok := parseClock(t); ok {
+			if hour >= 0 {
+				return "", fmt.Errorf("more than one time in %q", spec)
+			}
+			hour, minute = h, m
+			continue
+		}
+		switch d, isDay := weekdays[t]; {
+		case isDay:
+			dow[d] = true
+		case t == "day" || t == "days" || t == "daily":
+			daily = true
+		case t == "weekday" || t == "weekdays":
+			for d := 1; d <= 5; d++ {
+				dow[d] = true
+			}
+		case t == "weekend" || t == "weekends":
+			dow[0], dow[6] = true, true
+		case t == "month" || t == "monthly":
+			monthly = true
+		case monthly && ordinalRe.MatchString(t):
+			n, _ := strconv.Atoi(ordinalRe.FindStringSubmatch(t)[1])
+			if n < 1 || n > 31 {
+				return "", fmt.Errorf("day of month must be 1-31, got %q", t)
+			}
+			dom = n
+		default:
+			return "", fmt.Errorf("cannot parse %q in schedule %q", t, spec)
+		}
+	}
+	if hour < 0 {
+		return "", fmt.Errorf("add a time to %q, e.g. 9am or 18:30", spec)
+	}
+	if !daily && !monthly && len(dow) == 0 {
+		return "", fmt.Errorf("add the days to %q, e.g. every day, weekday or monday", spec)
+	}
+	if monthly && dom == 0 {
+		dom = 1
+	}
+
+	// Convert the local clock time to UTC; the date may move by a day.
+	local := time.Date(now.In(loc).Year(), now.In(loc).Month(), now.In(loc).Day(), hour, minute, 0, 0, loc)
+	utc := local.UTC()
+	shift := int(civil(utc).Sub(civil(local)).Hours() / 24)
+
+	domField, dowField := "*", "*"
+	if monthly {
+		d := dom + shift
+		if d < 1 || d > 31 {
+			return "", fmt.Errorf("%q falls on another day in UTC; use a cron expression instead", spec)
+		}
+		domField = strconv.Itoa(d)
+	}
+	if len(dow) > 0 && len(dow) < 7 {
+		var days []string
+		for d := 0; d < 7; d++ {
+			if dow[(d-shift+7)%7] {
+				days = append(days, strconv.Itoa(d))
+			}
+		}
+		dowField = strings.Join(days, ",")
+	}
+	return fmt.Sprintf("%d %d %s * %s", utc.Minute(), utc.Hour(), domField, dowField), nil
+}
+
+// Next returns up to n run times of a cron expression after from, searching
+// at most a year ahead. Times are in from's location.
+func Next(expr string, from time.Time, n int) ([]time.Time, error) {
+	c, err := parseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	loc := from.Location()
+	t := from.UTC().Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(1, 0, 0)
+	var out []time.Time
+	for len(out) < n && t.Before(end) {
+		switch {
+		case !c.month[int(t.Month())] || !c.dom[t.Day()] || !c.dow[int(t.Weekday())]:
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !c.hour[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !c.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			out = append(out, t.In(loc))
+			t = t.Add(time.Minute)
+		}
+	}
+	return out, nil
+}
+
+// cron holds the allowed values of each field.
+type cron struct {
+	minute, hour, dom, month, dow []bool
+}
+
+func parseCron(expr string) (cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return cron{}, fmt.Errorf("cron expression needs 5 fields, got %d", len(parts))
+	}
+	var c cron
+	var err error
+	fields := []struct {
+		dst      *[]bool
+		min, max int
+	}{
+		{&c.minute, 0, 59}, {&c.hour, 0, 23}, {&c.dom, 1, 31}, {&c.month, 1, 12}, {&c.dow, 0, 6},
+	}
+	for i, f := range fields {
+		if *f.dst, err = parseField(parts[i], f.min, f.max); err != nil {
+			return cron{}, err
+		}
+	}
+	return c, nil
+}
+
+// parseField accepts the same syntax as the backend: *, */n, n, n-m and
+// comma-separated lists of values and ranges.
+func parseField(field string, lo, hi int) ([]bool, error) {
+	set := make([]bool, hi+1)
+	if step, ok := strings.CutPrefix(field, "*/"); ok {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid step %q", field)
+		}
+		for v := lo; v <= hi; v += n {
+			set[v] = true
+		}
+		return set, nil
+	}
+	for _, part := range strings.Split(field, ",") {
+		if part == "*" {
+			for v := lo; v <= hi; v++ {
+				set[v] = true
+			}
+			continue
+		}
+		a, b, isRange := strings.Cut(part, "-")
+		from, err1 := strconv.Atoi(a)
+		to := from
+		var err2 error
+		if isRange {
+			to, err2 = strconv.Atoi(b)
+		}
+		if err1 != nil || err2 != nil || from < lo || to > hi || from > to {
+			return nil, fmt.Errorf("invalid cron value %q (range %d-%d)", part, lo, hi)
+		}
+		for v := from; v <= to; v++ {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// parseClock reads 9am, 9:30pm, 18:30, noon and midnight. Bare numbers are
+// not times.
+func parseClock(s string) (hour, minute int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+func isUnit(tok string, names ...string) bool {
+	for _, n := range names {
+		if tok == n || tok == n+"s" {
+			return true
+		}
+	}
+	return false
+}
+
+// civil returns t's calendar date as midnight UTC, for day arithmetic.
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,287 @@
+// Package speech reads agent replies aloud with a local text-to-speech
+// engine: say on macOS, espeak-ng, espeak or spd-say elsewhere, and the
+// System.Speech synthesizer through PowerShell on Windows.
+//
+// Replies are spoken a sentence at a time as they stream in. Splitter cuts
+// the streamed text into sentences, leaving out code blocks and markdown
+// markup, and Speaker queues them for the engine, one process per sentence,
+// so a reply can be cut short between or during sentences.
+package speech
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Engine is a text-to-speech command that reads the text to say from stdin.
+type Engine struct {
+	Name string
+	Path string
+	Args []string
+}
+
+// ErrNoEngine is returned by Detect when no speech engine is installed.
+var ErrNoEngine = errors.New("no speech engine found; install espeak-ng or set speech_command in tui.json")
+
+// windowsScript speaks stdin with the built-in synthesizer.
+const windowsScript = "Add-Type -AssemblyName System.Speech; " +
+	"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak([Console]::In.ReadToEnd())"
+
+// Detect returns the engine to speak with: custom, a command and its
+// arguments, when set, otherwise the first one installed for the platform.
+func Detect(custom []string) (Engine, error) {
+	if len(custom) > 0 {
+		path, err := exec.LookPath(custom[0])
+		if err != nil {
+			return Engine{}, err
+		}
+		return Engine{Name: custom[0], Path: path, Args: custom[1:]}, nil
+	}
+
+	var candidates []Engine
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = []Engine{{Name: "say", Args: []string{"-f", "-"}}}
+	case "windows":
+		candidates = []Engine{
+			{Name: "powershell", Args: []string{"-NoProfile", "-NonInteractive", "-Command", windowsScript}},
+			{Name: "pwsh", Args: []string{"-NoProfile", "-NonInteractive", "-Command", windowsScript}},
+		}
+	default:
+		candidates = []Engine{
+			{Name: "espeak-ng", Args: []string{"--stdin"}},
+			{Name: "espeak", Args: []string{"--stdin"}},
+			{Name: "spd-say", Args: []string{"--wait", "--pipe-mode"}},
+		}
+	}
+	for _, e := range candidates {
+		if path, err := exec.LookPath(e.Name); err == nil {
+			e.Path = path
+			return e, nil
+		}
+	}
+	return Engine{}, ErrNoEngine
+}
+
+// queueSize bounds the sentences waiting to be spoken; further ones are
+// dropped rather than blocking the UI.
+const queueSize = 256
+
+// utterance is a queued sentence, with the generation it was queued in so
+// a Stop also drops the ones already taken off the queue.
+type utterance struct {
+	text string
+	gen  int
+}
+
+// Speaker speaks queued sentences in order, one engine process at a time.
+// Its methods are safe to call from any goroutine.
+type Speaker struct {
+	engine Engine
+	queue  chan utterance
+
+	mu     sync.Mutex
+	cur    *exec.Cmd
+	gen    int
+	closed bool
+	err    error
+}
+
+// NewSpeaker starts a Speaker using engine.
+func NewSpeaker(engine Engine) *Speaker {
+	s := &Speaker{engine: engine, queue: make(chan utterance, queueSize)}
+	go s.run()
+	return s
+}
+
+// Engine returns the engine the speaker uses.
+func (s *Speaker) Engine() Engine { return s.engine }
+
+// Say queues text to be spoken after the sentences already queued.
+func (s *Speaker) Say(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- utterance{text: text, gen: s.gen}:
+	default:
+	}
+}
+
+// Stop drops the queued sentences and cuts off the one being spoken.
+func (s *Speaker) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gen++
+	if s.cur != nil && s.cur.Process != nil {
+		_ = s.cur.Process.Kill()
+	}
+}
+
+// Close stops the speaker for good.
+func (s *Speaker) Close() {
+	s.Stop()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+}
+
+// Err returns the error of the last sentence the engine failed to speak,
+// other than one cut off by Stop, and clears it.
+func (s *Speaker) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	s.err = nil
+	return err
+}
+
+func (s *Speaker) run() {
+	for u := range s.queue {
+		cmd := exec.Command(s.engine.Path, s.engine.Args...)
+		cmd.Stdin = strings.NewReader(u.text)
+
+		s.mu.Lock()
+		if u.gen != s.gen {
+			s.mu.Unlock()
+			continue
+		}
+		if err := cmd.Start(); err != nil {
+			s.err = err
+			s.mu.Unlock()
+			continue
+		}
+		s.cur = cmd
+		s.mu.Unlock()
+
+		err := cmd.Wait()
+
+		s.mu.Lock()
+		s.cur = nil
+		if err != nil && u.gen == s.gen {
+			s.err = err
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Splitter cuts streamed reply text into sentences to speak. Text inside
+// ``` fences is left out.
+type Splitter struct {
+	line   string // text of the current line not yet returned
+	inCode bool
+}
+
+// Feed adds streamed text and returns the sentences it completed.
+func (s *Splitter) Feed(text string) []string {
+	var out []string
+	s.line += text
+	for {
+		i := strings.IndexByte(s.line, '\n')
+		if i < 0 {
+			break
+		}
+		line := s.line[:i]
+		s.line = s.line[i+1:]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			s.inCode = !s.inCode
+			continue
+		}
+		if s.inCode {
+			continue
+		}
+		// A line ends the sentence on it: headings and list items often
+		// have no full stop.
+		done, rest := sentences(line)
+		out = append(out, done...)
+		out = appendClean(out, rest)
+	}
+	if !s.inCode && !strings.HasPrefix(strings.TrimSpace(s.line), "`") {
+		var done []string
+		done, s.line = sentences(s.line)
+		out = append(out, done...)
+	}
+	return out
+}
+
+// Flush returns what is left of the text as a final sentence and resets the
+// splitter for the next reply.
+func (s *Splitter) Flush() []string {
+	var out []string
+	if !s.inCode {
+		out = appendClean(out, s.line)
+	}
+	*s = Splitter{}
+	return out
+}
+
+// Split returns the sentences of a whole reply.
+func Split(text string) []string {
+	var s Splitter
+	return append(s.Feed(text), s.Flush()...)
+}
+
+// sentences returns the complete sentences at the start of text, cleaned,
+// and the rest. A sentence ends at '.', '!' or '?' followed by a space,
+// except after a number or a single letter, as in "1. " or "e.g. ".
+func sentences(text string) ([]string, string) {
+	var out []string
+	start := 0
+	for i := 0; i+1 < len(text); i++ {
+		c := text[i]
+		if (c != '.' && c != '!' && c != '?') || text[i+1] != ' ' {
+			continue
+		}
+		if c == '.' && abbreviation(text[start:i]) {
+			continue
+		}
+		out = appendClean(out, text[start:i+1])
+		start = i + 2
+	}
+	return out, text[start:]
+}
+
+// abbreviation reports whether the last word of text is a number or a
+// single letter, so a following '.' does not end a sentence.
+func abbreviation(text string) bool {
+	word := text[strings.LastIndexAny(text, " (")+1:]
+	if len(word) == 0 {
+		return false
+	}
+	if len(word) == 1 || word[len(word)-2] == '.' {
+		return true
+	}
+	return strings.Trim(word, "0123456789") == ""
+}
+
+var (
+	linePrefix = regexp.MustCompile(`^\s*(?:#{1,6}\s+|>\s*|[-*+]\s+|\d+[.)]\s+)+`)
+	link       = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasis   = strings.NewReplacer("**", "", "__", "", "~~", "", "`", "", "*", "", "|", " ")
+	spaces     = regexp.MustCompile(`\s+`)
+)
+
+// appendClean appends text without markdown markup, unless nothing is left
+// to say.
+func appendClean(out []string, text string) []string {
+	text = linePrefix.ReplaceAllString(text, "")
+	text = link.ReplaceAllString(text, "$1")
+	text = emphasis.Replace(text)
+	text = strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+	if strings.Trim(text, ".!?:;,-|= ") == "" {
+		return out
+	}
+	return append(out, text)
+}
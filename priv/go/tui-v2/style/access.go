@@ -0,0 +1,47 @@
+package style
+
+import "charm.land/lipgloss/v2"
+
+// Accessibility and low-bandwidth switches. Change them with
+// SetAccessibility so dependent styles are rebuilt.
+var (
+	// ScreenReader renders plain, linear output: no box-drawing characters
+	// or decorative glyphs, and every chat message starts with an explicit
+	// role prefix such as "You:" or "Error:".
+	ScreenReader bool
+
+	// ReducedMotion freezes spinners, phrase rotation and the streaming
+	// cursor. It is implied by ScreenReader and Minimal.
+	ReducedMotion bool
+
+	// Minimal draws gradients in a single color, so a title costs one
+	// escape sequence instead of one per character, for slow SSH links and
+	// serial consoles.
+	Minimal bool
+)
+
+// SetAccessibility applies the accessibility and low-bandwidth switches and
+// rebuilds styles.
+func SetAccessibility(screenReader, reducedMotion, minimal bool) {
+	ScreenReader = screenReader
+	Minimal = minimal
+	ReducedMotion = reducedMotion || screenReader || minimal
+	rebuildStyles()
+}
+
+// Frame returns b, or a border of blank cells in screen-reader mode so the
+// layout is unchanged but no line-drawing characters reach the terminal.
+func Frame(b lipgloss.Border) lipgloss.Border {
+	if ScreenReader {
+		return lipgloss.HiddenBorder()
+	}
+	return b
+}
+
+// Glyph returns decorated, or plain in screen-reader mode.
+func Glyph(decorated, plain string) string {
+	if ScreenReader {
+		return plain
+	}
+	return decorated
+}
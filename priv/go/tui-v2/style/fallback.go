@@ -0,0 +1,175 @@
+package style
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+)
+
+// ColorMode selects how theme colors are mapped onto the terminal palette.
+type ColorMode int
+
+const (
+	ColorModeTrue ColorMode = iota // 24-bit hex colors as defined
+	ColorMode256                   // snapped to the xterm 256-color cube
+	ColorMode16                    // curated ANSI palette, follows the terminal's own colors
+)
+
+// CurrentColorMode is the active color mode. Change it with SetColorMode.
+var CurrentColorMode = ColorModeTrue
+
+func (c ColorMode) String() string {
+	switch c {
+	case ColorMode256:
+		return "256"
+	case ColorMode16:
+		return "16"
+	default:
+		return "truecolor"
+	}
+}
+
+// ParseColorMode parses "truecolor", "256" or "16". "auto" and "" return
+// ok=false so callers fall back to terminal detection.
+func ParseColorMode(s string) (ColorMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "truecolor", "24bit":
+		return ColorModeTrue, true
+	case "256", "ansi256":
+		return ColorMode256, true
+	case "16", "ansi":
+		return ColorMode16, true
+	}
+	return ColorModeTrue, false
+}
+
+// ColorModeForProfile returns the color mode matching a detected profile.
+func ColorModeForProfile(p colorprofile.Profile) ColorMode {
+	switch {
+	case p >= colorprofile.TrueColor:
+		return ColorModeTrue
+	case p == colorprofile.ANSI256:
+		return ColorMode256
+	default:
+		return ColorMode16
+	}
+}
+
+// Profile returns the colorprofile for mode.
+func (c ColorMode) Profile() colorprofile.Profile {
+	switch c {
+	case ColorMode256:
+		return colorprofile.ANSI256
+	case ColorMode16:
+		return colorprofile.ANSI
+	default:
+		return colorprofile.TrueColor
+	}
+}
+
+// SetColorMode switches the color mode and re-applies the current theme.
+func SetColorMode(mode ColorMode) {
+	if mode == CurrentColorMode {
+		return
+	}
+	CurrentColorMode = mode
+	SetTheme(CurrentThemeName)
+}
+
+// adaptTheme maps t onto the palette available in mode. In 256-color mode
+// every color is snapped to its nearest xterm index up front so gradients
+// and blends are computed on the colors that will actually be drawn. With
+// only 16 colors, hex approximations become unreadable, so a fixed role-based
+// ANSI palette is used instead; it inherits whatever colors the user has
+// configured in their terminal.
+func adaptTheme(t Theme, mode ColorMode) Theme {
+	switch mode {
+	case ColorMode256:
+		for _, f := range themeFields {
+			p := f.ptr(&t)
+			if *p != nil {
+				*p = colorprofile.ANSI256.Convert(*p)
+			}
+		}
+		return t
+	case ColorMode16:
+		a := ansiDarkTheme
+		if !themeIsDark(t) {
+			a = ansiLightTheme
+		}
+		a.Name = t.Name
+		return a
+	}
+	return t
+}
+
+// themeIsDark guesses whether t targets a dark background from the
+// luminance of its sidebar background.
+func themeIsDark(t Theme) bool {
+	if t.SidebarBg == nil {
+		return true
+	}
+	r, g, b, _ := t.SidebarBg.RGBA()
+	lum := 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)
+	return lum < 0.5*0xffff
+}
+
+// ansiColor returns one of the 16 terminal palette colors.
+func ansiColor(n int) color.Color { return lipgloss.Color(strconv.Itoa(n)) }
+
+var (
+	ansiDarkTheme = Theme{
+		Primary:          ansiColor(5),
+		Secondary:        ansiColor(6),
+		Success:          ansiColor(2),
+		Warning:          ansiColor(3),
+		Error:            ansiColor(1),
+		Muted:            ansiColor(8),
+		Dim:              ansiColor(8),
+		Border:           ansiColor(8),
+		MsgBorderUser:    ansiColor(6),
+		MsgBorderAgent:   ansiColor(5),
+		MsgBorderSystem:  ansiColor(8),
+		MsgBorderWarning: ansiColor(3),
+		MsgBorderError:   ansiColor(1),
+		SidebarBg:        ansiColor(0),
+		ModalBg:          ansiColor(0),
+		TooltipBg:        ansiColor(0),
+		InputBg:          ansiColor(0),
+		SelectionBg:      ansiColor(4),
+		DialogBg:         ansiColor(0),
+		ButtonActiveBg:   ansiColor(5),
+		ButtonActiveText: ansiColor(15),
+		GradA:            ansiColor(5),
+		GradB:            ansiColor(6),
+	}
+
+	ansiLightTheme = Theme{
+		Primary:          ansiColor(5),
+		Secondary:        ansiColor(4),
+		Success:          ansiColor(2),
+		Warning:          ansiColor(3),
+		Error:            ansiColor(1),
+		Muted:            ansiColor(8),
+		Dim:              ansiColor(7),
+		Border:           ansiColor(7),
+		MsgBorderUser:    ansiColor(4),
+		MsgBorderAgent:   ansiColor(5),
+		MsgBorderSystem:  ansiColor(7),
+		MsgBorderWarning: ansiColor(3),
+		MsgBorderError:   ansiColor(1),
+		SidebarBg:        ansiColor(15),
+		ModalBg:          ansiColor(15),
+		TooltipBg:        ansiColor(7),
+		InputBg:          ansiColor(15),
+		SelectionBg:      ansiColor(14),
+		DialogBg:         ansiColor(15),
+		ButtonActiveBg:   ansiColor(5),
+		ButtonActiveText: ansiColor(15),
+		GradA:            ansiColor(5),
+		GradB:            ansiColor(4),
+	}
+)
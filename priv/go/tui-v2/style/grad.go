@@ -45,14 +45,15 @@ func nrgbaToHex(c color.Color) string {
 }
 
 // GradientText renders text with a left-to-right horizontal color gradient
-// from `from` to `to`, coloring each rune individually.
+// from `from` to `to`, coloring each rune individually. In Minimal mode the
+// whole text is in `from`.
 func GradientText(text string, from, to color.Color) string {
 	runes := []rune(text)
 	n := len(runes)
 	if n == 0 {
 		return ""
 	}
-	if n == 1 {
+	if n == 1 || Minimal {
 		hex := nrgbaToHex(from)
 		return lipgloss.NewStyle().Foreground(lipgloss.Color(hex)).Render(string(runes))
 	}
@@ -74,7 +75,7 @@ func GradientTextBold(text string, from, to color.Color) string {
 	if n == 0 {
 		return ""
 	}
-	if n == 1 {
+	if n == 1 || Minimal {
 		hex := nrgbaToHex(from)
 		return lipgloss.NewStyle().Foreground(lipgloss.Color(hex)).Bold(true).Render(string(runes))
 	}
@@ -124,6 +124,9 @@ var (
 	SidebarFileItem  lipgloss.Style
 	SidebarSeparator lipgloss.Style
 
+	// Pinned reference pane
+	PinnedStyle lipgloss.Style
+
 	// Thinking box
 	ThinkingHeader  lipgloss.Style
 	ThinkingContent lipgloss.Style
@@ -281,6 +284,7 @@ func SetTheme(name string) bool {
 	if !ok {
 		return false
 	}
+	t = adaptTheme(t, CurrentColorMode)
 	CurrentThemeName = name
 	Primary = t.Primary
 	Secondary = t.Secondary
@@ -341,7 +345,7 @@ func rebuildStyles() {
 	StatusSignal = lipgloss.NewStyle().Foreground(Secondary)
 	ContextBar = lipgloss.NewStyle().Foreground(Primary)
 
-	PlanBorder = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(Border).Padding(1, 2)
+	PlanBorder = lipgloss.NewStyle().Border(Frame(lipgloss.RoundedBorder())).BorderForeground(Border).Padding(1, 2)
 	PlanSelected = lipgloss.NewStyle().Foreground(Primary).Bold(true)
 	PlanUnselected = lipgloss.NewStyle().Foreground(Muted)
 
@@ -374,7 +378,7 @@ func rebuildStyles() {
 
 	// Sidebar
 	SidebarStyle = lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, true, false, false).
+		Border(Frame(lipgloss.NormalBorder()), false, true, false, false).
 		BorderForeground(Border).
 		PaddingLeft(1).PaddingRight(1)
 	SidebarTitle = lipgloss.NewStyle().Foreground(Primary).Bold(true)
@@ -383,6 +387,12 @@ func rebuildStyles() {
 	SidebarFileItem = lipgloss.NewStyle().Foreground(Muted)
 	SidebarSeparator = lipgloss.NewStyle().Foreground(Dim)
 
+	// Pinned pane
+	PinnedStyle = lipgloss.NewStyle().
+		Border(Frame(lipgloss.NormalBorder()), false, false, false, true).
+		BorderForeground(Border).
+		PaddingLeft(1).PaddingRight(1)
+
 	// Thinking
 	ThinkingHeader = lipgloss.NewStyle().Foreground(Warning).Bold(true)
 	ThinkingContent = lipgloss.NewStyle().Foreground(Dim)
@@ -395,7 +405,7 @@ func rebuildStyles() {
 		Background(ModalBgColor).
 		Foreground(Muted)
 	ModalBorder = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(Frame(lipgloss.RoundedBorder())).
 		BorderForeground(Primary).
 		Background(ModalBgColor)
 	ModalTitle = lipgloss.NewStyle().
@@ -421,7 +431,7 @@ func rebuildStyles() {
 	// -------------------------------------------------------------------------
 
 	ToolBox = lipgloss.NewStyle().
-		Border(lipgloss.ThickBorder(), false, false, false, true).
+		Border(Frame(lipgloss.ThickBorder()), false, false, false, true).
 		BorderForeground(Border).
 		PaddingLeft(1)
 
@@ -459,7 +469,7 @@ func rebuildStyles() {
 	// -------------------------------------------------------------------------
 
 	InputBorder = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(Frame(lipgloss.RoundedBorder())).
 		BorderForeground(Border)
 	InputPlaceholder = lipgloss.NewStyle().Foreground(Dim)
 	InputCursor = lipgloss.NewStyle().Foreground(Primary)
@@ -484,7 +494,7 @@ func rebuildStyles() {
 
 	TooltipBg = lipgloss.NewStyle().
 		Background(TooltipBgColor).
-		Border(lipgloss.RoundedBorder()).
+		Border(Frame(lipgloss.RoundedBorder())).
 		BorderForeground(Border).
 		Padding(0, 1)
 	TooltipText = lipgloss.NewStyle().Foreground(Muted)
@@ -499,7 +509,7 @@ func rebuildStyles() {
 	// -------------------------------------------------------------------------
 
 	DialogBorder = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(Frame(lipgloss.RoundedBorder())).
 		BorderForeground(Primary).
 		Background(DialogBgColor).
 		Padding(1, 2)
@@ -556,7 +566,7 @@ func rebuildStyles() {
 
 	SectionTitle = lipgloss.NewStyle().Foreground(Primary).Bold(true)
 	SectionBorder = lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder()).
+		Border(Frame(lipgloss.NormalBorder())).
 		BorderForeground(Border)
 
 	// -------------------------------------------------------------------------
@@ -579,24 +589,37 @@ func rebuildStyles() {
 
 // ContextBarRender renders a context utilization bar like: ██████░░░░ 62%
 func ContextBarRender(utilization float64, width int) string {
+	return ContextBarRenderAt(utilization, width, 0, 0)
+}
+
+// ContextBarRenderAt renders a context utilization bar that turns amber at
+// warnAt and red at alertAt. Zero thresholds mean 0.75 and 0.90.
+func ContextBarRenderAt(utilization float64, width int, warnAt, alertAt float64) string {
 	filled := int(utilization * float64(width))
 	if filled > width {
 		filled = width
 	}
 	empty := width - filled
 
-	var c color.Color
-	switch {
-	case utilization >= 0.90:
-		c = Error
-	case utilization >= 0.75:
-		c = Warning
-	default:
-		c = Primary
-	}
-
+	c := ContextLevelColor(utilization, warnAt, alertAt)
 	bar := lipgloss.NewStyle().Foreground(c).Render(strings.Repeat("█", filled)) +
 		lipgloss.NewStyle().Foreground(Dim).Render(strings.Repeat("░", empty))
 
 	return bar
 }
+
+// ContextLevelColor returns the color of a context utilization: Primary
+// below warnAt, Warning from it and Error from alertAt. Zero thresholds mean
+// 0.75 and 0.90.
+func ContextLevelColor(utilization, warnAt, alertAt float64) color.Color {
+	if warnAt <= 0 || alertAt <= 0 {
+		warnAt, alertAt = 0.75, 0.90
+	}
+	switch {
+	case utilization >= alertAt:
+		return Error
+	case utilization >= warnAt:
+		return Warning
+	}
+	return Primary
+}
@@ -0,0 +1,204 @@
+package style
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// userThemeNames tracks themes loaded from disk so a reload can drop themes
+// whose files were removed.
+var userThemeNames = map[string]bool{}
+
+var hexColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// themeFields maps file keys to the Theme color they populate.
+var themeFields = []struct {
+	key string
+	ptr func(*Theme) *color.Color
+}{
+	{"primary", func(t *Theme) *color.Color { return &t.Primary }},
+	{"secondary", func(t *Theme) *color.Color { return &t.Secondary }},
+	{"success", func(t *Theme) *color.Color { return &t.Success }},
+	{"warning", func(t *Theme) *color.Color { return &t.Warning }},
+	{"error", func(t *Theme) *color.Color { return &t.Error }},
+	{"muted", func(t *Theme) *color.Color { return &t.Muted }},
+	{"dim", func(t *Theme) *color.Color { return &t.Dim }},
+	{"border", func(t *Theme) *color.Color { return &t.Border }},
+	{"msg_border_user", func(t *Theme) *color.Color { return &t.MsgBorderUser }},
+	{"msg_border_agent", func(t *Theme) *color.Color { return &t.MsgBorderAgent }},
+	{"msg_border_system", func(t *Theme) *color.Color { return &t.MsgBorderSystem }},
+	{"msg_border_warning", func(t *Theme) *color.Color { return &t.MsgBorderWarning }},
+	{"msg_border_error", func(t *Theme) *color.Color { return &t.MsgBorderError }},
+	{"sidebar_bg", func(t *Theme) *color.Color { return &t.SidebarBg }},
+	{"modal_bg", func(t *Theme) *color.Color { return &t.ModalBg }},
+	{"tooltip_bg", func(t *Theme) *color.Color { return &t.TooltipBg }},
+	{"input_bg", func(t *Theme) *color.Color { return &t.InputBg }},
+	{"selection_bg", func(t *Theme) *color.Color { return &t.SelectionBg }},
+	{"dialog_bg", func(t *Theme) *color.Color { return &t.DialogBg }},
+	{"button_active_bg", func(t *Theme) *color.Color { return &t.ButtonActiveBg }},
+	{"button_active_text", func(t *Theme) *color.Color { return &t.ButtonActiveText }},
+	{"grad_a", func(t *Theme) *color.Color { return &t.GradA }},
+	{"grad_b", func(t *Theme) *color.Color { return &t.GradB }},
+}
+
+// IsUserTheme reports whether name was loaded from a theme file.
+func IsUserTheme(name string) bool { return userThemeNames[name] }
+
+// LoadUserThemes reads every *.json and *.toml file in dir and registers it
+// in Themes. Previously loaded user themes are replaced. A missing directory
+// is not an error. Files that fail to parse are skipped and reported.
+//
+// A theme file holds "name", an optional "base" built-in theme to inherit
+// unset colors from (default "dark"), and hex colors keyed by the snake_case
+// Theme field name, e.g. "msg_border_user" or "grad_a".
+func LoadUserThemes(dir string) (loaded []string, errs []error) {
+	names := ThemeNames[:0]
+	for _, name := range ThemeNames {
+		if userThemeNames[name] {
+			delete(Themes, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	ThemeNames = names
+	userThemeNames = map[string]bool{}
+
+	for _, path := range themeFiles(dir) {
+		t, err := loadThemeFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+			continue
+		}
+		if _, builtin := Themes[t.Name]; builtin {
+			errs = append(errs, fmt.Errorf("%s: theme %q already exists", filepath.Base(path), t.Name))
+			continue
+		}
+		Themes[t.Name] = t
+		userThemeNames[t.Name] = true
+		ThemeNames = append(ThemeNames, t.Name)
+		loaded = append(loaded, t.Name)
+	}
+	return loaded, errs
+}
+
+// UserThemesSignature summarises the theme files in dir by name, size and
+// modification time. Callers poll it to detect changes for hot reloading.
+func UserThemesSignature(dir string) string {
+	var sb strings.Builder
+	for _, path := range themeFiles(dir) {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String()
+}
+
+func themeFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".toml":
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func loadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var fields map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		fields, err = parseFlatTOML(data)
+	} else {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("parse: %w", err)
+	}
+
+	name := strings.TrimSpace(fields["name"])
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	baseName := fields["base"]
+	if baseName == "" {
+		baseName = "dark"
+	}
+	base, ok := Themes[baseName]
+	if !ok || userThemeNames[baseName] {
+		return Theme{}, fmt.Errorf("unknown base theme %q", baseName)
+	}
+
+	t := base
+	t.Name = name
+	known := map[string]bool{"name": true, "base": true}
+	for _, f := range themeFields {
+		known[f.key] = true
+		v, ok := fields[f.key]
+		if !ok {
+			continue
+		}
+		if !hexColorRe.MatchString(v) {
+			return Theme{}, fmt.Errorf("%s: invalid color %q", f.key, v)
+		}
+		*f.ptr(&t) = lipgloss.Color(v)
+	}
+	for k := range fields {
+		if !known[k] {
+			return Theme{}, fmt.Errorf("unknown key %q", k)
+		}
+	}
+	return t, nil
+}
+
+// parseFlatTOML parses the subset of TOML used by theme files: one
+// `key = "value"` pair per line, with # comments and blank lines.
+func parseFlatTOML(data []byte) (map[string]string, error) {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = \"value\"", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, "#"); i > 0 && strings.Count(value[:i], `"`)%2 == 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+			return nil, fmt.Errorf("line %d: value must be a quoted string", lineNo)
+		}
+		fields[key] = value[1 : len(value)-1]
+	}
+	return fields, scanner.Err()
+}
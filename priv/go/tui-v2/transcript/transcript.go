@@ -0,0 +1,89 @@
+// Package transcript appends a record of a conversation to dated JSONL files
+// for compliance and later grepping.
+//
+// Each day has its own file, <dir>/2006-01-02.jsonl in local time, holding
+// one JSON object per line. Files are opened for every record, so nothing is
+// buffered and lost when the process exits, and created readable by the
+// owner only since prompts and tool output may contain secrets.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Kinds of records.
+const (
+	KindPrompt     = "prompt"
+	KindReply      = "reply"
+	KindToolCall   = "tool_call"
+	KindToolResult = "tool_result"
+	KindError      = "error"
+)
+
+// Record is one transcript line.
+type Record struct {
+	Time       time.Time `json:"time"`
+	Session    string    `json:"session,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Kind       string    `json:"kind"`
+	Text       string    `json:"text,omitempty"`
+	Model      string    `json:"model,omitempty"`       // reply
+	Tool       string    `json:"tool,omitempty"`        // tool_call, tool_result
+	Args       string    `json:"args,omitempty"`        // tool_call
+	Success    *bool     `json:"success,omitempty"`     // tool_result
+	DurationMs int64     `json:"duration_ms,omitempty"` // reply
+}
+
+// Log writes records below a directory. A nil *Log discards records.
+type Log struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// Dir returns the transcript directory of a profile state directory.
+func Dir(profileDir string) string { return filepath.Join(profileDir, "transcripts") }
+
+// Open creates dir if needed and returns a Log writing into it.
+func Open(dir string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create transcript dir: %w", err)
+	}
+	return &Log{dir: dir}, nil
+}
+
+// Path returns the file records written at t go to.
+func (l *Log) Path(t time.Time) string {
+	return filepath.Join(l.dir, t.Format("2006-01-02")+".jsonl")
+}
+
+// Write appends r, stamping it with the current time when r.Time is zero.
+func (l *Log) Write(r Record) error {
+	if l == nil {
+		return nil
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encode transcript record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.Path(r.Time), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open transcript: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	return f.Close()
+}
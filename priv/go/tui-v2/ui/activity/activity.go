@@ -3,16 +3,24 @@ package activity
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/miosa/osa-tui/msg"
+	"github.com/miosa/osa-tui/redact"
 	"github.com/miosa/osa-tui/style"
 )
 
 const maxCollapsed = 3
 
+// charsPerToken approximates tokens in streamed text until the LLM reports
+// the real count.
+const charsPerToken = 4
+
 // ToolCallInfo tracks a single tool invocation.
 type ToolCallInfo struct {
 	Name       string
@@ -20,6 +28,64 @@ type ToolCallInfo struct {
 	DurationMs int64
 	Done       bool
 	Success    bool
+	output     []outputChunk // streamed output while running, by offset
+}
+
+// outputChunk is a piece of a running tool's output at its byte offset.
+type outputChunk struct {
+	offset int64
+	text   string
+}
+
+// Streamed output of a running tool call shows as a tail of its latest
+// lines, and only the latest maxToolOutput bytes of it are kept.
+const (
+	toolTailLines = 5
+	maxToolOutput = 8 << 10
+)
+
+// addOutput files a chunk of output by its offset, since chunks can arrive
+// out of order, and drops the oldest beyond maxToolOutput.
+func (tc *ToolCallInfo) addOutput(text string, offset int64) {
+	i := sort.Search(len(tc.output), func(i int) bool { return tc.output[i].offset >= offset })
+	if i < len(tc.output) && tc.output[i].offset == offset {
+		return
+	}
+	tc.output = slices.Insert(tc.output, i, outputChunk{offset, text})
+	size := 0
+	for _, c := range tc.output {
+		size += len(c.text)
+	}
+	for len(tc.output) > 1 && size > maxToolOutput {
+		size -= len(tc.output[0].text)
+		tc.output = tc.output[1:]
+	}
+}
+
+// outputTail returns the last toolTailLines lines of the streamed output,
+// with escape sequences stripped and carriage-return progress redraws
+// reduced to their final state.
+func (tc ToolCallInfo) outputTail() []string {
+	var sb strings.Builder
+	for _, c := range tc.output {
+		sb.WriteString(c.text)
+	}
+	text := strings.TrimRight(ansi.Strip(redact.Display(sb.String())), "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > toolTailLines {
+		lines = lines[len(lines)-toolTailLines:]
+	}
+	for i, l := range lines {
+		l = strings.TrimRight(l, "\r")
+		if j := strings.LastIndexByte(l, '\r'); j >= 0 {
+			l = l[j+1:]
+		}
+		lines[i] = strings.ReplaceAll(l, "\t", "    ")
+	}
+	return lines
 }
 
 // Model renders an elapsed timer and tool call feed.
@@ -31,13 +97,19 @@ type Model struct {
 	inputTokens      int
 	outputTokens     int
 	expanded         bool
-	thinkingMs       int64     // LLM thinking/reasoning duration
-	isThinking       bool      // true while receiving thinking deltas
-	thinkingStart    time.Time // when thinking started (for live duration)
-	iterationCount   int       // current iteration (from llm_request)
-	currentPhrase    string    // current witty phrase displayed in header
-	currentPhraseIdx int       // index of current phrase for avoiding repeats
-	phraseRotateTime time.Time // when the current phrase was set
+	thinkingMs       int64         // LLM thinking/reasoning duration
+	isThinking       bool          // true while receiving thinking deltas
+	thinkingStart    time.Time     // when thinking started (for live duration)
+	iterationCount   int           // current iteration (from llm_request)
+	maxIterations    int           // backend iteration limit, 0 when unknown
+	iterStart        time.Time     // when the current iteration's LLM call began
+	iterAvg          time.Duration // moving average of iteration durations
+	streamChars      int           // response text streamed in the current call
+	streamStart      time.Time     // first streamed chunk of the current call
+	tokensPerSec     float64       // output rate of the latest finished call
+	currentPhrase    string        // current witty phrase displayed in header
+	currentPhraseIdx int           // index of current phrase for avoiding repeats
+	phraseRotateTime time.Time     // when the current phrase was set
 }
 
 // New constructs a zero-value activity Model.
@@ -71,6 +143,12 @@ func (m *Model) Reset() {
 	m.isThinking = false
 	m.thinkingStart = time.Time{}
 	m.iterationCount = 0
+	m.maxIterations = 0
+	m.iterStart = time.Time{}
+	m.iterAvg = 0
+	m.streamChars = 0
+	m.streamStart = time.Time{}
+	m.tokensPerSec = 0
 	m.currentPhrase = ""
 	m.currentPhraseIdx = -1
 	m.phraseRotateTime = time.Time{}
@@ -111,14 +189,36 @@ func (m Model) Update(teaMsg tea.Msg) (Model, tea.Cmd) {
 	switch v := teaMsg.(type) {
 	case msg.TickMsg:
 		// Rotate witty phrase every 4 seconds.
-		if m.active && time.Since(m.phraseRotateTime) >= 4*time.Second {
+		if m.active && !style.ReducedMotion && time.Since(m.phraseRotateTime) >= 4*time.Second {
 			m.currentPhrase, m.currentPhraseIdx = pickPhrase(m.currentPhraseIdx)
 			m.phraseRotateTime = time.Now()
 		}
 		return m, nil
 
 	case msg.LLMRequest:
+		now := time.Now()
+		if !m.iterStart.IsZero() {
+			// Weight recent iterations: tool rounds late in a task tend to
+			// look like the ones just before them.
+			d := now.Sub(m.iterStart)
+			if m.iterAvg == 0 {
+				m.iterAvg = d
+			} else {
+				m.iterAvg = (m.iterAvg + d) / 2
+			}
+		}
+		m.iterStart = now
 		m.iterationCount = v.Iteration + 1
+		m.maxIterations = v.MaxIterations
+		m.streamChars = 0
+		m.streamStart = time.Time{}
+		return m, nil
+
+	case msg.StreamingDelta:
+		if m.streamStart.IsZero() {
+			m.streamStart = time.Now()
+		}
+		m.streamChars += len(v.Text)
 		return m, nil
 
 	case msg.ToolCallStart:
@@ -143,6 +243,15 @@ func (m Model) Update(teaMsg tea.Msg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case msg.ToolOutput:
+		for i := len(m.toolCalls) - 1; i >= 0; i-- {
+			if m.toolCalls[i].Name == v.Name && !m.toolCalls[i].Done {
+				m.toolCalls[i].addOutput(v.Output, v.Offset)
+				break
+			}
+		}
+		return m, nil
+
 	case msg.ToolResult:
 		// Annotate the most recent matching tool call with its success state.
 		for i := len(m.toolCalls) - 1; i >= 0; i-- {
@@ -163,6 +272,11 @@ func (m Model) Update(teaMsg tea.Msg) (Model, tea.Cmd) {
 	case msg.LLMResponse:
 		m.inputTokens += v.InputTokens
 		m.outputTokens += v.OutputTokens
+		if v.OutputTokens > 0 && v.DurationMs > 0 {
+			m.tokensPerSec = float64(v.OutputTokens) / (float64(v.DurationMs) / 1000)
+		}
+		m.streamChars = 0
+		m.streamStart = time.Time{}
 		if m.isThinking {
 			m.thinkingMs = time.Since(m.thinkingStart).Milliseconds()
 			m.isThinking = false
@@ -188,13 +302,13 @@ func (m Model) View() string {
 	elapsed := time.Since(m.startTime)
 
 	phrase := m.currentPhrase
-	if phrase == "" {
+	if phrase == "" || style.ScreenReader {
 		phrase = "Reasoning…"
 	}
 
-	// Header: ⏺ Filtering noise… (8s · 2 tools · ↓ 4.2k ↑ 1.1k · iter 3 · thought for 3s)
+	// Header: ⏺ Filtering noise… (8s · 2 tools · ↓ 4.2k ↑ 1.1k · 42 tok/s · iter 3/20 · ≤2m 30s left · thought for 3s)
 	var hdr strings.Builder
-	hdr.WriteString(style.PrefixActive.Render("⏺"))
+	hdr.WriteString(style.PrefixActive.Render(style.Glyph("⏺", "Working:")))
 	hdr.WriteString(fmt.Sprintf(" %s (", phrase))
 	hdr.WriteString(formatElapsed(elapsed))
 	hdr.WriteString(" · ")
@@ -203,8 +317,18 @@ func (m Model) View() string {
 	hdr.WriteString(formatTokens(m.inputTokens))
 	hdr.WriteString(" ↑ ")
 	hdr.WriteString(formatTokens(m.outputTokens))
+	if rate := m.throughput(); rate > 0 {
+		hdr.WriteString(fmt.Sprintf(" · %.0f tok/s", rate))
+	}
 	if m.iterationCount > 1 {
-		hdr.WriteString(fmt.Sprintf(" · iter %d", m.iterationCount))
+		if m.maxIterations > 0 {
+			hdr.WriteString(fmt.Sprintf(" · iter %d/%d", m.iterationCount, m.maxIterations))
+		} else {
+			hdr.WriteString(fmt.Sprintf(" · iter %d", m.iterationCount))
+		}
+	}
+	if eta := m.eta(); eta > 0 {
+		hdr.WriteString(" · ≤" + formatElapsed(eta) + " left")
 	}
 	if m.isThinking {
 		thinkElapsed := time.Since(m.thinkingStart).Milliseconds()
@@ -249,29 +373,63 @@ func (m Model) View() string {
 	return sb.String()
 }
 
+// throughput returns the output rate in tokens per second: live while a
+// response streams, otherwise that of the latest finished LLM call.
+func (m Model) throughput() float64 {
+	if !m.streamStart.IsZero() {
+		if secs := time.Since(m.streamStart).Seconds(); secs >= 1 {
+			return float64(m.streamChars) / charsPerToken / secs
+		}
+	}
+	return m.tokensPerSec
+}
+
+// eta estimates the time left if the agent used every remaining iteration
+// at the recent pace. It is an upper bound, as most requests finish well
+// before the limit, and 0 until an iteration has completed.
+func (m Model) eta() time.Duration {
+	if m.iterAvg == 0 || m.maxIterations == 0 || m.iterationCount >= m.maxIterations {
+		return 0
+	}
+	left := time.Duration(m.maxIterations-m.iterationCount)*m.iterAvg + max(m.iterAvg-time.Since(m.iterStart), 0)
+	return left.Round(time.Second)
+}
+
 // renderToolCall formats a single tool call line with tree connector.
 func renderToolCall(tc ToolCallInfo, isLast bool) string {
-	connector := "  ├─ "
+	connector := style.Glyph("  ├─ ", "  - ")
 	if isLast {
-		connector = "  └─ "
+		connector = style.Glyph("  └─ ", "  - ")
 	}
 
 	name := style.ToolName.Render(tc.Name)
-	desc := contextualDescription(tc.Name, tc.Args)
+	desc := contextualDescription(tc.Name, redact.Display(tc.Args))
 
 	var suffix string
 	if tc.Done {
 		dur := formatToolDuration(tc.DurationMs)
 		if tc.Success {
-			suffix = style.ToolDuration.Render(fmt.Sprintf(" ✓ %s", dur))
+			suffix = style.ToolDuration.Render(fmt.Sprintf(" %s %s", style.Glyph("✓", "done in"), dur))
 		} else {
-			suffix = style.ErrorText.Render(fmt.Sprintf(" ✘ %s", dur))
+			suffix = style.ErrorText.Render(fmt.Sprintf(" %s %s", style.Glyph("✘", "failed after"), dur))
 		}
 	} else {
 		suffix = style.ToolDuration.Render(" (running…)")
 	}
 
-	return connector + name + " " + desc + suffix
+	line := connector + name + " " + desc + suffix
+	if tc.Done {
+		return line
+	}
+	// Tail of the output streamed so far, under the running call.
+	indent := style.Glyph("  │    ", "      ")
+	if isLast {
+		indent = "       "
+	}
+	for _, l := range tc.outputTail() {
+		line += "\n" + style.Faint.Render(indent+ansi.Truncate(l, 100, "…"))
+	}
+	return line
 }
 
 // contextualDescription returns a human-readable description for a tool call.
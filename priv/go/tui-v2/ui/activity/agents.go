@@ -199,6 +199,31 @@ func (m AgentsModel) View() string {
 	return strings.TrimRight(sb.String(), "\n")
 }
 
+// Height returns the number of lines View renders: the header, a row per
+// shown agent plus its sub-status, and the collapse hint.
+func (m AgentsModel) Height() int {
+	if !m.active {
+		return 0
+	}
+	visible := m.agentOrder
+	h := 1
+	if !m.expanded && len(visible) > collapseThreshold {
+		visible = visible[:collapseShow]
+		h++
+	}
+	for _, name := range visible {
+		agent, ok := m.agents[name]
+		if !ok {
+			continue
+		}
+		h++
+		if agentSubStatus(agent) != "" {
+			h++
+		}
+	}
+	return h
+}
+
 // renderAgentLine builds one agent row with status prefix.
 func renderAgentLine(branch string, agent *AgentInfo) string {
 	var sb strings.Builder
@@ -93,6 +93,9 @@ func (m TasksModel) View() string {
 	return out
 }
 
+// Height returns the number of lines View renders: one per task.
+func (m TasksModel) Height() int { return len(m.tasks) }
+
 func (m TasksModel) renderTask(t Task) string {
 	switch t.Status {
 	case "completed":
@@ -7,6 +7,7 @@
 //   - Configurable label with optional label color
 //   - Ellipsis animation cycling through "", ".", "..", "..." at 400ms per state
 //   - 20 FPS tick rate (50ms per frame)
+//   - Static rendering without ticks when style.ReducedMotion is set
 package anim
 
 import (
@@ -17,6 +18,8 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+
+	"github.com/miosa/osa-tui/style"
 )
 
 // ---------------------------------------------------------------------------
@@ -153,6 +156,10 @@ func (m Model) View() string {
 		return ""
 	}
 
+	if style.ReducedMotion {
+		return m.staticView()
+	}
+
 	glyph := m.cache.frames[m.frame%len(m.cache.frames)]
 
 	if m.opts.Label == "" {
@@ -202,9 +209,29 @@ func (m *Model) Stop() {
 // Internal helpers
 // ---------------------------------------------------------------------------
 
+// staticView renders the spinner without animation: the first frame and a
+// fixed ellipsis, or just the label in screen-reader mode.
+func (m Model) staticView() string {
+	label := m.opts.Label + "..."
+	if m.opts.LabelColor != nil && !style.ScreenReader {
+		label = lipgloss.NewStyle().Foreground(m.opts.LabelColor).Render(label)
+	}
+	if style.ScreenReader {
+		return label
+	}
+	if m.opts.Label == "" {
+		return m.cache.frames[0] + "..."
+	}
+	return m.cache.frames[0] + " " + label
+}
+
 // tick returns a tea.Cmd that fires a TickMsg for this model after one frame
-// duration, respecting the BirthOffset on the very first tick.
+// duration, respecting the BirthOffset on the very first tick. No frames are
+// scheduled when motion is reduced.
 func (m Model) tick() tea.Cmd {
+	if style.ReducedMotion {
+		return nil
+	}
 	id := m.id
 	delay := frameDuration
 
@@ -4,6 +4,10 @@ package attachments
 
 import (
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,6 +33,10 @@ type Attachment struct {
 	Name     string // display name (basename), possibly truncated
 	Size     int64
 	FileType FileType
+
+	// Width and Height are the pixel size of a FileImage in a format the
+	// standard library decodes (PNG, JPEG, GIF), 0 otherwise.
+	Width, Height int
 }
 
 // Model holds the list of attachments and interactive delete-mode state.
@@ -74,12 +82,16 @@ func (m *Model) Add(path string) error {
 		}
 	}
 
-	m.items = append(m.items, Attachment{
+	a := Attachment{
 		Path:     abs,
 		Name:     filepath.Base(abs),
 		Size:     info.Size(),
 		FileType: detectFileType(abs),
-	})
+	}
+	if a.FileType == FileImage {
+		a.Width, a.Height = imageSize(abs)
+	}
+	m.items = append(m.items, a)
 	return nil
 }
 
@@ -222,6 +234,9 @@ func (m Model) renderChip(idx int, a Attachment) string {
 	size := humanSize(a.Size)
 
 	label := fmt.Sprintf("%s %s (%s)", icon, name, size)
+	if a.Width > 0 {
+		label = fmt.Sprintf("%s %s %d×%d (%s)", icon, name, a.Width, a.Height, size)
+	}
 
 	var deleteMarker string
 	if m.deleteMode {
@@ -262,6 +277,21 @@ func detectFileType(path string) FileType {
 	}
 }
 
+// imageSize reads the pixel size from an image file's header, returning
+// zeros for formats it cannot decode.
+func imageSize(path string) (int, int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
 // fileIcon returns the appropriate Unicode icon for a FileType.
 func fileIcon(ft FileType) string {
 	switch ft {
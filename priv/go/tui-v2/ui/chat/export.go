@@ -0,0 +1,62 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miosa/osa-tui/redact"
+)
+
+// Markdown renders the conversation for export, prompts and replies under a
+// heading each with their time. System notices are left out unless notices
+// is set, when they are quoted. Secrets are masked. n counts the messages
+// written.
+func (m Model) Markdown(notices bool) (md string, n int) {
+	var b strings.Builder
+	for _, it := range m.items {
+		var heading, body string
+		switch v := it.(type) {
+		case *userMessageItem:
+			heading, body = "You", v.content
+			if !v.ts.IsZero() {
+				heading += " · " + v.ts.Format(time.DateTime)
+			}
+		case *assistantMessageItem:
+			if strings.TrimSpace(v.content) == "" {
+				continue
+			}
+			heading, body = "OSA", v.content
+			if v.modelName != "" {
+				heading += " (" + v.modelName + ")"
+			}
+			if !v.ts.IsZero() {
+				heading += " · " + v.ts.Format(time.DateTime)
+			}
+		case *systemMessageItem:
+			if !notices {
+				continue
+			}
+			text := v.content
+			switch v.level {
+			case LevelWarning:
+				text = "Warning: " + text
+			case LevelError:
+				text = "Error: " + text
+			}
+			body = "> " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n> ")
+		default:
+			continue
+		}
+		if n > 0 {
+			b.WriteString("\n")
+		}
+		if heading != "" {
+			fmt.Fprintf(&b, "## %s\n\n", heading)
+		}
+		body, _ = redact.Scrub(strings.TrimSpace(body))
+		b.WriteString(body + "\n")
+		n++
+	}
+	return b.String(), n
+}
@@ -13,6 +13,8 @@ package chat
 
 import (
 	"fmt"
+	"math"
+	"regexp"
 	"strings"
 	"time"
 
@@ -20,6 +22,8 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/redact"
 	"github.com/miosa/osa-tui/style"
 	"github.com/miosa/osa-tui/ui/tools"
 )
@@ -67,6 +71,23 @@ type ToolCallDisplay struct {
 	Success    bool
 }
 
+// Quote is a conversation message picked for a reply.
+type Quote struct {
+	ID        string // stable item ID, e.g. "msg-3"
+	Role      MessageRole
+	Content   string
+	Timestamp time.Time
+}
+
+// Timestamps selects how message times show in their meta line.
+type Timestamps int
+
+const (
+	TimestampsOff      Timestamps = iota
+	TimestampsRelative            // "2m ago"
+	TimestampsAbsolute            // "14:02"
+)
+
 // ToolStatus describes the lifecycle state of a tool call.
 type ToolStatus int
 
@@ -82,7 +103,7 @@ const (
 // Item interface — trait system for message items
 // ---------------------------------------------------------------------------
 
-// Item is the trait every message variant must satisfy.
+// Item is the trait every message variant must satisfy. It matches list.Item.
 // Height and Render receive the current usable content width (not the full
 // terminal width) so callers do not need to account for borders/padding.
 type Item interface {
@@ -91,8 +112,10 @@ type Item interface {
 	// ContentVersion is incremented whenever the item's content changes.
 	// The render cache is invalidated when this value or width change.
 	ContentVersion() int
-	// Height returns the number of terminal lines this item occupies at width.
-	Height(width int) string
+	// Height returns the number of terminal lines this item occupies at width
+	// without rendering it: exact from the render cache when it is valid,
+	// otherwise measured from the raw content (see the measurement helpers).
+	Height(width int) int
 	// Render produces the full styled display string at the given width.
 	Render(width int) string
 }
@@ -105,10 +128,15 @@ type renderCache struct {
 	output        string
 	cachedWidth   int
 	cachedVersion int
+	cachedGen     int
 }
 
+// cacheGen is bumped by InvalidateCache to drop every item's cached render
+// when something other than the item changes how it renders.
+var cacheGen int
+
 func (c *renderCache) get(width, version int) (string, bool) {
-	if c.cachedWidth == width && c.cachedVersion == version {
+	if c.cachedWidth == width && c.cachedVersion == version && c.cachedGen == cacheGen {
 		return c.output, true
 	}
 	return "", false
@@ -117,6 +145,7 @@ func (c *renderCache) get(width, version int) (string, bool) {
 func (c *renderCache) set(width, version int, output string) {
 	c.cachedWidth = width
 	c.cachedVersion = version
+	c.cachedGen = cacheGen
 	c.output = output
 }
 
@@ -134,6 +163,138 @@ func cappedWidth(w int) int {
 	return w
 }
 
+// ---------------------------------------------------------------------------
+// Height measurement
+// ---------------------------------------------------------------------------
+
+// Messages render inside a left border and one column of padding, so their
+// text wraps two columns short of the content width.
+const messageInset = 2
+
+// wrappedLines returns how many lines s takes when word-wrapped at width the
+// way lipgloss wraps block content, without styling it.
+func wrappedLines(s string, width int) int {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if width <= 0 {
+		return strings.Count(s, "\n") + 1
+	}
+	s = strings.ReplaceAll(s, "\t", "    ")
+	return strings.Count(ansi.Wrap(s, width, ""), "\n") + 1
+}
+
+// cachedHeight returns the height of a valid cached render.
+func (c *renderCache) height(width, version int) (int, bool) {
+	out, ok := c.get(width, version)
+	if !ok {
+		return 0, false
+	}
+	return strings.Count(out, "\n") + 1, true
+}
+
+// markdownLines estimates the height of md as rendered by renderMarkdown at
+// width, following glamour's layout: a blank line above the document, a
+// margin of two columns on each side, a blank line between blocks, and one
+// more above a leading list, quote or code block. Paragraphs and quotes
+// reflow; list items and code keep their lines. Tables are laid out as renderMarkdown lays them out.
+func markdownLines(md string, width int) int {
+	if strings.TrimSpace(md) == "" || style.ScreenReader {
+		return wrappedLines(md, width)
+	}
+	if segs := splitTables(md); len(segs) > 1 || (len(segs) == 1 && segs[0].table != nil) {
+		lines := 0
+		for _, seg := range segs {
+			if seg.table != nil {
+				lines += 1 + len(seg.table.render(width))
+			} else {
+				lines += markdownLines(seg.text, width)
+			}
+		}
+		return lines
+	}
+	text := max(width-4, 1)
+	lines, blocks := 1, 0
+	var para []string
+	kind := ""
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		if blocks > 0 || kind != "para" {
+			lines++
+		}
+		switch kind {
+		case "para":
+			lines += wrappedLines(strings.Join(para, " "), text)
+		case "quote":
+			lines += wrappedLines(strings.Join(para, " "), max(text-2, 1))
+		default:
+			for _, l := range para {
+				lines += wrappedLines(l, max(text-2, 1))
+			}
+		}
+		blocks++
+		para, kind = nil, ""
+	}
+
+	src := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(src); i++ {
+		l := strings.TrimSpace(src[i])
+		switch {
+		case strings.HasPrefix(l, "```") || strings.HasPrefix(l, "~~~"):
+			flush()
+			fence := l[:3]
+			n := 0
+			for i++; i < len(src) && !strings.HasPrefix(strings.TrimSpace(src[i]), fence); i++ {
+				n++
+			}
+			lines += 1 + n // the blank line above, then the code
+			blocks++
+		case l == "":
+			flush()
+		case strings.HasPrefix(l, "#"):
+			flush()
+			para, kind = []string{l}, "para"
+			flush()
+		case strings.HasPrefix(l, ">"):
+			if kind != "quote" {
+				flush()
+			}
+			para, kind = append(para, strings.TrimSpace(strings.TrimLeft(l, ">"))), "quote"
+		case isListLine(l):
+			if kind != "list" {
+				flush()
+			}
+			// A task item draws its checkbox where the bullet and the
+			// brackets were.
+			para, kind = append(para, taskItem.ReplaceAllString(l, "- ")), "list"
+		default:
+			// A line under a list item stays a line of its own; one under a
+			// quote joins it.
+			if kind == "" {
+				kind = "para"
+			}
+			para = append(para, l)
+		}
+	}
+	flush()
+	return lines
+}
+
+// isListLine reports whether l starts a bullet or numbered list item.
+// taskItem matches the marker of a GFM task list item, "- [x] ".
+var taskItem = regexp.MustCompile(`^[-*+] \[[ xX]\] `)
+
+func isListLine(l string) bool {
+	if strings.HasPrefix(l, "- ") || strings.HasPrefix(l, "* ") || strings.HasPrefix(l, "+ ") {
+		return true
+	}
+	digits := 0
+	for digits < len(l) && l[digits] >= '0' && l[digits] <= '9' {
+		digits++
+	}
+	return digits > 0 && digits+1 < len(l) && (l[digits] == '.' || l[digits] == ')') && l[digits+1] == ' '
+}
+
 // ---------------------------------------------------------------------------
 // ThinkingBox — collapsible extended-thinking widget
 // ---------------------------------------------------------------------------
@@ -197,7 +358,7 @@ func (tb *ThinkingBox) Render(contentWidth int) string {
 		toggle = "▾"
 	}
 
-	lines := strings.Split(tb.content, "\n")
+	lines := strings.Split(redact.Display(tb.content), "\n")
 	totalLines := len(lines)
 
 	dur := tb.activeDurationLabel()
@@ -210,7 +371,7 @@ func (tb *ThinkingBox) Render(contentWidth int) string {
 	if !tb.expanded && totalLines > thinkingCollapsedLines {
 		headerSuffix = fmt.Sprintf(" (%d lines)", totalLines)
 	}
-	header := style.ThinkingHeader.Render(toggle + " Thinking" + durLabel + headerSuffix)
+	header := style.ThinkingHeader.Render(style.Glyph(toggle+" Thinking", "Thinking:") + durLabel + headerSuffix)
 
 	displayLines := lines
 	if !tb.expanded && totalLines > thinkingCollapsedLines {
@@ -224,13 +385,34 @@ func (tb *ThinkingBox) Render(contentWidth int) string {
 	body := style.ThinkingContent.Render(strings.Join(displayLines, "\n"))
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.NormalBorder()), false, false, false, true).
 		BorderForeground(style.Warning).
 		PaddingLeft(1).
 		Width(cw)
 	return box.Render(header + "\n" + body)
 }
 
+// renderBlock renders the thinking kept on an answer: one header line with
+// the final duration until expanded, then the full text below it.
+func (tb *ThinkingBox) renderBlock() string {
+	lines := strings.Count(tb.content, "\n") + 1
+	label := style.Glyph("▸ Thinking", "Thinking:")
+	if tb.expanded {
+		label = style.Glyph("▾ Thinking", "Thinking:")
+	}
+	if tb.durationMs > 0 {
+		label += " (" + formatDuration(tb.durationMs) + ")"
+	}
+	if !tb.expanded {
+		n := "1 line"
+		if lines > 1 {
+			n = fmt.Sprintf("%d lines", lines)
+		}
+		return style.ThinkingHeader.Render(label+" · "+n) + style.Faint.Render(" (ctrl+t to expand)")
+	}
+	return style.ThinkingHeader.Render(label) + "\n" + style.ThinkingContent.Render(redact.Display(tb.content))
+}
+
 // ---------------------------------------------------------------------------
 // Concrete Item implementations
 // ---------------------------------------------------------------------------
@@ -240,6 +422,8 @@ type userMessageItem struct {
 	id      string
 	content string
 	ts      time.Time
+	failed  string // why the request for this prompt failed, "" unless it did
+	request string // ID of the failed request, sent again by a retry
 	version int
 	cache   renderCache
 }
@@ -248,22 +432,60 @@ func newUserItem(id, content string) *userMessageItem {
 	return &userMessageItem{id: id, content: content, ts: time.Now()}
 }
 
-func (u *userMessageItem) ID() string              { return u.id }
-func (u *userMessageItem) ContentVersion() int     { return u.version }
-func (u *userMessageItem) Height(width int) string { return u.Render(width) }
+func (u *userMessageItem) ID() string          { return u.id }
+func (u *userMessageItem) ContentVersion() int { return u.version }
+
+func (u *userMessageItem) Height(cw int) int {
+	cw = cappedWidth(cw)
+	if h, ok := u.cache.height(cw, u.version); ok {
+		return h
+	}
+	h := wrappedLines(u.label()+"\n"+redact.Display(u.content), cw-messageInset)
+	if u.failed != "" {
+		h += wrappedLines(u.failedMarker(), cw-messageInset)
+	}
+	return h
+}
+
+// failedMarker renders the line under a prompt whose request failed.
+func (u *userMessageItem) failedMarker() string {
+	return style.ErrorText.Render(style.Glyph("✗ ", "") + "failed: " + redact.Display(u.failed) + " — press r to retry")
+}
+
+// body renders the prompt text, with the failure marker under it.
+func (u *userMessageItem) body() string {
+	body := redact.Display(u.content)
+	if u.failed != "" {
+		body += "\n" + u.failedMarker()
+	}
+	return body
+}
+
+// label renders the label line, followed by the message time when
+// timestamps are shown.
+func (u *userMessageItem) label() string {
+	label := style.UserLabel.Render(userLabelText())
+	if st := stamp(u.ts); st != "" {
+		label += style.MsgMeta.Render(" · " + st)
+	}
+	return label
+}
+
+// userLabelText is the unstyled label above a user message.
+func userLabelText() string { return style.Glyph("❯  You", "You:") }
 
 func (u *userMessageItem) Render(cw int) string {
 	cw = cappedWidth(cw)
 	if out, ok := u.cache.get(cw, u.version); ok {
 		return out
 	}
-	label := style.UserLabel.Render("❯  You")
+	label := u.label()
 	border := lipgloss.NewStyle().
-		Border(lipgloss.ThickBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.ThickBorder()), false, false, false, true).
 		BorderForeground(style.MsgBorderUser).
 		PaddingLeft(1).
 		Width(cw)
-	out := border.Render(label + "\n" + u.content)
+	out := border.Render(label + "\n" + u.body())
 	u.cache.set(cw, u.version, out)
 	return out
 }
@@ -280,9 +502,110 @@ type assistantMessageItem struct {
 	outputTokens int64
 	ts           time.Time
 	version      int
-	isError      bool // render with error styling
-	isCancelled  bool // render as cancelled/faded
+	isError      bool         // render with error styling
+	isCancelled  bool         // render as cancelled/faded
+	altOf        string       // ID of the answer this one is an alternative to
+	alts         int          // number of alternative answers to this one
+	expanded     bool         // show all of a long body
+	long         bool         // the body exceeded collapseLines when last rendered
+	showTools    bool         // list grouped tool calls instead of their summary
+	raw          bool         // show the markdown source instead of rendering it
+	thinking     *ThinkingBox // reasoning behind the answer, nil when none
 	cache        renderCache
+	tools        renderCache // the tool section, which Height measures too
+}
+
+// thinkingSection renders the kept thinking above the body, or "" when the
+// answer has none.
+func (a *assistantMessageItem) thinkingSection() string {
+	if a.thinking == nil {
+		return ""
+	}
+	return a.thinking.renderBlock() + "\n"
+}
+
+// groupToolCalls is the number of tool calls from which an answer shows
+// them as one summary row until expanded.
+const groupToolCalls = 4
+
+// toolsGrouped reports whether the tool calls show as a summary row.
+func (a *assistantMessageItem) toolsGrouped() bool {
+	return len(a.toolCalls) >= groupToolCalls && !a.showTools
+}
+
+// toolSection renders the tool calls below the body: each in its own box,
+// or one summary row when there are many.
+func (a *assistantMessageItem) toolSection(cw int) string {
+	if len(a.toolCalls) == 0 {
+		return ""
+	}
+	if a.toolsGrouped() {
+		return "\n" + a.toolSummary()
+	}
+	if out, ok := a.tools.get(cw, a.version); ok {
+		return out
+	}
+	var tb strings.Builder
+	for _, tc := range a.toolCalls {
+		tb.WriteString("\n")
+		status := toolCallStatus(tc)
+		tb.WriteString(tools.RenderToolCall(
+			tc.Name, redact.Display(tc.Args), redact.Display(tc.Result),
+			tools.RenderOpts{
+				Status:     tools.ToolStatus(status),
+				Width:      cw - 2,
+				Expanded:   false,
+				DurationMs: tc.DurationMs,
+			},
+		))
+	}
+	a.tools.set(cw, a.version, tb.String())
+	return tb.String()
+}
+
+// toolSummary renders the summary row of grouped tool calls:
+// "🛠 12 tool calls, 3 files changed, 1 failed (ctrl+o to expand)".
+func (a *assistantMessageItem) toolSummary() string {
+	files := make(map[string]bool)
+	failed := 0
+	for _, tc := range a.toolCalls {
+		if f := tools.ChangedFile(tc.Name, tc.Args); f != "" {
+			files[f] = true
+		}
+		if toolCallStatus(tc) == ToolError {
+			failed++
+		}
+	}
+	parts := []string{fmt.Sprintf("%d tool calls", len(a.toolCalls))}
+	switch len(files) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 file changed")
+	default:
+		parts = append(parts, fmt.Sprintf("%d files changed", len(files)))
+	}
+	summary := style.ToolName.Render(style.Glyph("🛠 ", "Tools: ") + strings.Join(parts, ", "))
+	if failed > 0 {
+		summary += style.ErrorText.Render(fmt.Sprintf(", %d failed", failed))
+	}
+	return summary + style.Faint.Render(" (ctrl+o to expand)")
+}
+
+// collapseLines is how much of a long answer's body is shown until the
+// answer is expanded, so one huge response does not bury the rest of the
+// conversation.
+const collapseLines = 30
+
+// collapse cuts body to collapseLines with a footer counting the rest,
+// unless the answer is expanded.
+func (a *assistantMessageItem) collapse(body string) string {
+	lines := strings.Split(body, "\n")
+	a.long = len(lines) > collapseLines
+	if !a.long || a.expanded {
+		return body
+	}
+	more := fmt.Sprintf("%s %d more lines (enter to expand)", style.Glyph("…", "..."), len(lines)-collapseLines)
+	return strings.Join(lines[:collapseLines], "\n") + "\n" + style.Faint.Render(more)
 }
 
 func newAssistantItem(id, content string, sig *Signal, durationMs int64, model string) *assistantMessageItem {
@@ -296,9 +619,118 @@ func newAssistantItem(id, content string, sig *Signal, durationMs int64, model s
 	}
 }
 
-func (a *assistantMessageItem) ID() string              { return a.id }
-func (a *assistantMessageItem) ContentVersion() int     { return a.version }
-func (a *assistantMessageItem) Height(width int) string { return a.Render(width) }
+// linkLabel describes how the answer relates to retries of the same prompt:
+// " ↻ alternative to msg-3" or " ↻ 2 alternatives below".
+func (a *assistantMessageItem) linkLabel() string {
+	switch {
+	case a.altOf != "":
+		return style.MsgMeta.Render(" " + style.Glyph("↻", "-") + " alternative to " + a.altOf)
+	case a.alts == 1:
+		return style.MsgMeta.Render(" " + style.Glyph("↻", "-") + " 1 alternative below")
+	case a.alts > 1:
+		return style.MsgMeta.Render(fmt.Sprintf(" %s %d alternatives below", style.Glyph("↻", "-"), a.alts))
+	}
+	return ""
+}
+
+// rawLabel marks an answer shown as its markdown source.
+func (a *assistantMessageItem) rawLabel() string {
+	if !a.raw {
+		return ""
+	}
+	return style.MsgMeta.Render(" · raw")
+}
+
+// markdownBody renders content as markdown, or returns it as written while
+// the answer shows its source.
+func (a *assistantMessageItem) markdownBody(content string, cw int) string {
+	if a.raw {
+		return content
+	}
+	return renderMarkdown(content, cw-2)
+}
+
+func (a *assistantMessageItem) ID() string          { return a.id }
+func (a *assistantMessageItem) ContentVersion() int { return a.version }
+
+// Height is exact once the item has rendered at cw. Before that the markdown
+// body is estimated, since measuring it exactly means rendering it. The tool
+// calls are measured from their boxes: each renderer caps its preview its own
+// way, and the boxes are cheap next to markdown.
+func (a *assistantMessageItem) Height(cw int) int {
+	cw = cappedWidth(cw)
+	if h, ok := a.cache.height(cw, a.version); ok {
+		return h
+	}
+	w := cw - messageInset
+	h := wrappedLines(a.label(), w)
+	if a.thinking != nil {
+		h += wrappedLines(a.thinking.renderBlock(), w)
+	}
+	content := redact.Display(a.content)
+	body := 0
+	if a.isCancelled || a.isError || a.raw {
+		body = wrappedLines(content, w)
+	} else {
+		body = markdownLines(content, w)
+	}
+	if body > collapseLines && !a.expanded {
+		body = collapseLines + 1
+	}
+	h += body
+	if section := a.toolSection(cw); section != "" {
+		h += wrappedLines(strings.TrimPrefix(section, "\n"), w)
+	}
+	if meta := a.meta(); meta != "" {
+		h += wrappedLines(meta, w)
+	}
+	return h
+}
+
+// label renders the label line: the role, the signal badge and the links to
+// alternative answers.
+func (a *assistantMessageItem) label() string {
+	labelText := style.Glyph("◈ OSA", "OSA:")
+	if a.isError {
+		labelText = style.Glyph("✗ OSA", "OSA error:")
+	} else if a.isCancelled {
+		labelText = style.Glyph("◈ OSA (cancelled)", "OSA (cancelled):")
+	}
+	label := style.AgentLabel.Render(labelText)
+	if a.signal != nil && a.signal.Mode != "" && a.signal.Genre != "" {
+		badge := style.StatusSignal.Render(
+			fmt.Sprintf(" [%s/%s]", a.signal.Mode, a.signal.Genre),
+		)
+		label += badge
+	}
+	return label + a.linkLabel() + a.rawLabel()
+}
+
+// meta renders the metadata footer, "— model-name · 2.3s · ↓1.2k ↑0.8k · 14:02",
+// or "" when there is none.
+func (a *assistantMessageItem) meta() string {
+	st := stamp(a.ts)
+	if a.modelName == "" && a.durationMs <= 0 && st == "" {
+		return ""
+	}
+	var parts []string
+	if a.modelName != "" {
+		parts = append(parts, a.modelName)
+	}
+	if a.durationMs > 0 {
+		parts = append(parts, formatDuration(a.durationMs))
+	}
+	if a.inputTokens > 0 || a.outputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("↓%s ↑%s",
+			formatTokens(a.inputTokens),
+			formatTokens(a.outputTokens),
+		))
+	}
+	if st != "" {
+		parts = append(parts, st)
+	}
+	return style.MsgMeta.Render("— " + strings.Join(parts, " · "))
+}
 
 // shouldSkip returns true when this message has no content and no tool calls —
 // nothing meaningful to render.
@@ -320,76 +752,35 @@ func (a *assistantMessageItem) Render(cw int) string {
 		borderColor = style.MsgBorderSystem
 	}
 
-	// Label + optional signal badge
-	labelText := "◈ OSA"
-	if a.isError {
-		labelText = "✗ OSA"
-	} else if a.isCancelled {
-		labelText = "◈ OSA (cancelled)"
-	}
-	label := style.AgentLabel.Render(labelText)
-	if a.signal != nil && a.signal.Mode != "" && a.signal.Genre != "" {
-		badge := style.StatusSignal.Render(
-			fmt.Sprintf(" [%s/%s]", a.signal.Mode, a.signal.Genre),
-		)
-		label += badge
-	}
+	label := a.label()
 
 	// Markdown-rendered body
 	var body string
+	content := redact.Display(a.content)
 	if a.isCancelled {
-		body = style.Faint.Render(a.content)
+		body = style.Faint.Render(content)
 	} else if a.isError {
-		body = style.ErrorText.Render(a.content)
+		body = style.ErrorText.Render(content)
 	} else {
-		body = renderMarkdown(a.content, cw-2)
+		body = a.markdownBody(content, cw)
 	}
+	body = a.collapse(body)
 
 	// Tool calls dispatched to the tools registry
-	var toolSection string
-	if len(a.toolCalls) > 0 {
-		var tb strings.Builder
-		for _, tc := range a.toolCalls {
-			tb.WriteString("\n")
-			status := toolCallStatus(tc)
-			tb.WriteString(tools.RenderToolCall(
-				tc.Name, tc.Args, tc.Result,
-				tools.RenderOpts{
-					Status:     tools.ToolStatus(status),
-					Width:      cw - 2,
-					Expanded:   false,
-					DurationMs: tc.DurationMs,
-				},
-			))
-		}
-		toolSection = tb.String()
-	}
+	toolSection := a.toolSection(cw)
 
 	// Metadata footer: — model-name · 2.3s · ↓1.2k ↑0.8k
 	var meta string
-	if a.modelName != "" || a.durationMs > 0 {
-		var parts []string
-		if a.modelName != "" {
-			parts = append(parts, a.modelName)
-		}
-		if a.durationMs > 0 {
-			parts = append(parts, formatDuration(a.durationMs))
-		}
-		if a.inputTokens > 0 || a.outputTokens > 0 {
-			parts = append(parts, fmt.Sprintf("↓%s ↑%s",
-				formatTokens(a.inputTokens),
-				formatTokens(a.outputTokens),
-			))
-		}
-		meta = "\n" + style.MsgMeta.Render("— "+strings.Join(parts, " · "))
+	if m := a.meta(); m != "" {
+		meta = "\n" + m
 	}
 
 	border := lipgloss.NewStyle().
-		Border(lipgloss.ThickBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.ThickBorder()), false, false, false, true).
 		BorderForeground(borderColor).
 		PaddingLeft(1).
 		Width(cw)
-	out := border.Render(label + "\n" + body + toolSection + meta)
+	out := border.Render(label + "\n" + a.thinkingSection() + body + toolSection + meta)
 	a.cache.set(cw, a.version, out)
 	return out
 }
@@ -413,15 +804,36 @@ type systemMessageItem struct {
 	ts      time.Time
 	version int
 	cache   renderCache
+
+	// Remediation actions offered under an error; actionSel is the
+	// highlighted one, -1 while none is. confirm marks a question the
+	// actions answer, where esc picks the first action.
+	actions   []ErrorAction
+	actionSel int
+	confirm   bool
 }
 
 func newSystemItem(id, content string, level SystemLevel) *systemMessageItem {
 	return &systemMessageItem{id: id, content: content, level: level, ts: time.Now()}
 }
 
-func (s *systemMessageItem) ID() string              { return s.id }
-func (s *systemMessageItem) ContentVersion() int     { return s.version }
-func (s *systemMessageItem) Height(width int) string { return s.Render(width) }
+func (s *systemMessageItem) ID() string          { return s.id }
+func (s *systemMessageItem) ContentVersion() int { return s.version }
+
+func (s *systemMessageItem) Height(cw int) int {
+	cw = cappedWidth(cw)
+	if h, ok := s.cache.height(cw, s.version); ok {
+		return h
+	}
+	text := redact.Display(s.content)
+	if style.ScreenReader {
+		text = systemRolePrefix(s.level) + text
+	}
+	if len(s.actions) > 0 {
+		text += "\n" + s.renderActions()
+	}
+	return wrappedLines(text, cw-messageInset)
+}
 
 func (s *systemMessageItem) Render(cw int) string {
 	cw = cappedWidth(cw)
@@ -437,18 +849,27 @@ func (s *systemMessageItem) Render(cw int) string {
 		borderColor = style.MsgBorderError
 	}
 
+	content := redact.Display(s.content)
+	if style.ScreenReader {
+		content = systemRolePrefix(s.level) + content
+	}
+
 	var text string
 	switch s.level {
 	case LevelError:
-		text = style.ErrorText.Render(s.content)
+		text = style.ErrorText.Render(content)
 	case LevelWarning:
-		text = lipgloss.NewStyle().Foreground(style.Warning).Render(s.content)
+		text = lipgloss.NewStyle().Foreground(style.Warning).Render(content)
 	default:
-		text = style.Faint.Render(s.content)
+		text = style.Faint.Render(content)
+	}
+
+	if len(s.actions) > 0 {
+		text += "\n" + s.renderActions()
 	}
 
 	border := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.NormalBorder()), false, false, false, true).
 		BorderForeground(borderColor).
 		PaddingLeft(1).
 		Width(cw)
@@ -457,6 +878,45 @@ func (s *systemMessageItem) Render(cw int) string {
 	return out
 }
 
+// renderActions renders the action chips and the key hint below an error.
+func (s *systemMessageItem) renderActions() string {
+	chips := make([]string, len(s.actions))
+	for i, a := range s.actions {
+		if i == s.actionSel {
+			chips[i] = style.CompletionSelected.Render(" " + a.Label + " ")
+		} else {
+			chips[i] = style.CompletionNormal.Render(" " + a.Label + " ")
+		}
+	}
+	hint := "tab choose"
+	switch {
+	case s.confirm:
+		hint = "tab choose · enter confirm · esc " + strings.ToLower(s.actions[0].Label)
+	case s.actionSel >= 0:
+		hint = "enter run · esc dismiss"
+	}
+	return strings.Join(chips, " ") + "  " + style.Hint.Render(hint)
+}
+
+// systemRolePrefix returns the role prefix announced in screen-reader mode.
+func systemRolePrefix(level SystemLevel) string {
+	switch level {
+	case LevelError:
+		return "Error: "
+	case LevelWarning:
+		return "Warning: "
+	default:
+		return "System: "
+	}
+}
+
+// ErrorAction is a remediation offered under an error message, such as
+// retrying the prompt or switching model. ID is interpreted by the caller.
+type ErrorAction struct {
+	ID    string
+	Label string
+}
+
 // ---------------------------------------------------------------------------
 // ChatMessage — legacy data type kept for internal use
 // ---------------------------------------------------------------------------
@@ -499,6 +959,18 @@ type Model struct {
 	// Tool call accumulator — populated during processing, attached to agent message on completion.
 	pendingToolCalls []ToolCallDisplay
 
+	// Message selection for quote/reply: index into items plus one, 0 when
+	// nothing is selected. selLine is the selected item's first rendered line.
+	selected int
+	selLine  int
+
+	// The content lines [winFrom, winTo) are rendered; items outside them
+	// stand in as blank lines of their Height.
+	winFrom, winTo int
+
+	// Error whose remediation actions are live, nil when none are.
+	actionItem *systemMessageItem
+
 	// ID counter for stable item IDs
 	nextID int
 }
@@ -547,6 +1019,7 @@ func (m *Model) SetFocused(focused bool) {
 
 // AddUserMessage appends a user message and scrolls to bottom.
 func (m *Model) AddUserMessage(text string) {
+	m.dismissActions()
 	m.items = append(m.items, newUserItem(m.genID(), text))
 	m.refresh()
 }
@@ -560,10 +1033,40 @@ func (m *Model) AddAgentMessage(text string, sig *Signal, durationMs int64, mode
 		copy(item.toolCalls, m.pendingToolCalls)
 		m.pendingToolCalls = m.pendingToolCalls[:0]
 	}
+	// The turn's thinking moves onto its answer, collapsed, so it can be
+	// reviewed after the fact. Without a duration from the backend the
+	// time since the first thinking token stands in.
+	if m.thinkingBox.HasContent() {
+		tb := m.thinkingBox
+		if tb.durationMs <= 0 && !tb.startedAt.IsZero() {
+			tb.durationMs = time.Since(tb.startedAt).Milliseconds()
+		}
+		tb.expanded = false
+		item.thinking = &tb
+		m.thinkingBox = ThinkingBox{}
+	}
 	m.items = append(m.items, item)
 	m.refresh()
 }
 
+// AddAlternativeMessage appends an agent message as an alternative answer to
+// the agent message origID, and links the original to it.
+func (m *Model) AddAlternativeMessage(origID, text string, sig *Signal, durationMs int64, modelName string) {
+	for _, it := range m.items {
+		if a, ok := it.(*assistantMessageItem); ok && a.id == origID {
+			a.alts++
+			a.version++
+			break
+		}
+	}
+	m.AddAgentMessage(text, sig, durationMs, modelName)
+	if a, ok := m.items[len(m.items)-1].(*assistantMessageItem); ok {
+		a.altOf = origID
+		a.version++
+		m.refresh()
+	}
+}
+
 // TrackToolStart records the start of a tool invocation during processing.
 func (m *Model) TrackToolStart(name, args string) {
 	m.pendingToolCalls = append(m.pendingToolCalls, ToolCallDisplay{
@@ -572,10 +1075,12 @@ func (m *Model) TrackToolStart(name, args string) {
 	})
 }
 
-// TrackToolResult attaches the result to the most recent matching tool call.
+// TrackToolResult attaches the result to the most recent matching tool call
+// without one. The backend ends a call before sending its result, so the call
+// may already be done.
 func (m *Model) TrackToolResult(name, result string, success bool) {
 	for i := len(m.pendingToolCalls) - 1; i >= 0; i-- {
-		if m.pendingToolCalls[i].Name == name && !m.pendingToolCalls[i].Done {
+		if m.pendingToolCalls[i].Name == name && m.pendingToolCalls[i].Result == "" {
 			m.pendingToolCalls[i].Result = result
 			m.pendingToolCalls[i].Success = success
 			return
@@ -625,6 +1130,123 @@ func (m *Model) AddSystemError(text string) {
 	m.refresh()
 }
 
+// AddSystemErrorWithActions appends an error-level system message offering
+// actions below it. Only the latest such error keeps its actions; they are
+// dropped when another is added, a prompt is sent or DismissErrorActions is
+// called.
+func (m *Model) AddSystemErrorWithActions(text string, actions []ErrorAction) {
+	m.dismissActions()
+	item := newSystemItem(m.genID(), text, LevelError)
+	if len(actions) > 0 {
+		item.actions = actions
+		item.actionSel = -1
+		m.actionItem = item
+	}
+	m.items = append(m.items, item)
+	m.refresh()
+}
+
+// AddSystemConfirm appends an error-level question answered by one of
+// actions. The first action should be the safe answer: it starts highlighted
+// and is what esc stands for in the key hint. It replaces the actions of an
+// earlier error like AddSystemErrorWithActions.
+func (m *Model) AddSystemConfirm(text string, actions []ErrorAction) {
+	m.dismissActions()
+	item := newSystemItem(m.genID(), text, LevelError)
+	item.actions = actions
+	item.confirm = true
+	m.actionItem = item
+	m.items = append(m.items, item)
+	m.refresh()
+}
+
+// HasErrorActions reports whether an error is offering actions.
+func (m Model) HasErrorActions() bool {
+	return m.actionItem != nil
+}
+
+// CycleErrorAction moves the highlight by delta, wrapping around. The first
+// call highlights the first action, or the last one for a negative delta.
+func (m *Model) CycleErrorAction(delta int) {
+	it := m.actionItem
+	if it == nil {
+		return
+	}
+	n := len(it.actions)
+	switch {
+	case it.actionSel < 0 && delta < 0:
+		it.actionSel = n - 1
+	case it.actionSel < 0:
+		it.actionSel = 0
+	default:
+		it.actionSel = ((it.actionSel+delta)%n + n) % n
+	}
+	it.version++
+	m.refresh()
+}
+
+// SelectedErrorAction returns the highlighted action. ok is false when no
+// action is highlighted.
+func (m Model) SelectedErrorAction() (a ErrorAction, ok bool) {
+	if m.actionItem == nil || m.actionItem.actionSel < 0 {
+		return ErrorAction{}, false
+	}
+	return m.actionItem.actions[m.actionItem.actionSel], true
+}
+
+// DismissErrorActions removes the actions of the current error, leaving its
+// text in place.
+func (m *Model) DismissErrorActions() {
+	if m.actionItem == nil {
+		return
+	}
+	m.dismissActions()
+	m.refresh()
+}
+
+func (m *Model) dismissActions() {
+	if it := m.actionItem; it != nil {
+		it.actions = nil
+		it.version++
+		m.actionItem = nil
+	}
+}
+
+// SetNoticesHidden hides or shows the system notices, such as reconnects
+// and background task updates. Errors and a notice waiting for an answer
+// always show.
+func (m *Model) SetNoticesHidden(hidden bool) {
+	hideNotices = hidden
+	m.refresh()
+}
+
+// NoticesHidden reports whether system notices are hidden.
+func (m Model) NoticesHidden() bool { return hideNotices }
+
+// HiddenNotices counts the system notices SetNoticesHidden hides now.
+func (m Model) HiddenNotices() int {
+	var n int
+	for _, it := range m.items {
+		if s, ok := it.(*systemMessageItem); ok && s.level != LevelError && s != m.actionItem {
+			n++
+		}
+	}
+	return n
+}
+
+// noticeHidden reports whether it is a system notice left out of the list.
+func (m Model) noticeHidden(it Item) bool {
+	s, ok := it.(*systemMessageItem)
+	return ok && hideNotices && s.level != LevelError && s != m.actionItem
+}
+
+// InvalidateCache drops every cached item render and re-renders, e.g. after
+// secrets were revealed or hidden.
+func (m *Model) InvalidateCache() {
+	cacheGen++
+	m.refresh()
+}
+
 // SetWelcomeData populates the welcome screen fields shown before any messages.
 func (m *Model) SetWelcomeData(version, detail, cwd string) {
 	m.welcomeVersion = version
@@ -666,32 +1288,442 @@ func (m *Model) ToggleThinkingExpanded() {
 	m.refresh()
 }
 
+// FinishThinking adds the backend's measure of a finished thinking phase to
+// the ThinkingBox's duration; a turn thinks once per LLM call.
+func (m *Model) FinishThinking(durationMs int64) {
+	m.thinkingBox.durationMs += durationMs
+	m.refresh()
+}
+
+// ClearThinking empties the ThinkingBox before a new turn.
+func (m *Model) ClearThinking() {
+	m.thinkingBox = ThinkingBox{}
+	m.refresh()
+}
+
+// ToggleThinking expands or collapses the thinking kept on the selected
+// agent message, or on the latest one with thinking when nothing is
+// selected. It reports false when there is no such message.
+func (m *Model) ToggleThinking() bool {
+	var target *assistantMessageItem
+	if m.selected > 0 {
+		if a, ok := m.items[m.selected-1].(*assistantMessageItem); ok && a.thinking != nil {
+			target = a
+		}
+	} else {
+		for i := len(m.items) - 1; i >= 0; i-- {
+			if a, ok := m.items[i].(*assistantMessageItem); ok && a.thinking != nil {
+				target = a
+				break
+			}
+		}
+	}
+	if target == nil {
+		return false
+	}
+	target.thinking.Toggle()
+	target.version++
+	m.refresh()
+	return true
+}
+
+// SetTimestamps selects how message times show in their meta line.
+func (m *Model) SetTimestamps(t Timestamps) {
+	timestamps = t
+	m.InvalidateCache()
+}
+
+// RefreshTimestamps re-renders relative timestamps, which age while their
+// messages do not change, keeping the scroll position.
+func (m *Model) RefreshTimestamps() {
+	if timestamps != TimestampsRelative || len(m.items) == 0 {
+		return
+	}
+	cacheGen++
+	if m.selected > 0 || m.vp.AtBottom() {
+		m.refresh()
+		return
+	}
+	off := m.vp.YOffset()
+	m.setWindow(m.winFrom, m.winTo)
+	m.vp.SetYOffset(off)
+}
+
 // HasMessages reports whether any conversation items have been added.
 func (m Model) HasMessages() bool {
 	return len(m.items) > 0
 }
 
+// Len returns the number of conversation items.
+func (m Model) Len() int { return len(m.items) }
+
+// Lines returns the number of rendered lines in the conversation.
+func (m Model) Lines() int { return m.vp.TotalLineCount() }
+
 // ScrollToTop scrolls the viewport to the very top.
 func (m *Model) ScrollToTop() {
+	m.setWindow(0, (renderWindow+1)*m.viewHeight())
 	m.vp.GotoTop()
 }
 
 // ScrollToBottom scrolls the viewport to the very bottom.
 func (m *Model) ScrollToBottom() {
+	m.setWindow(m.vp.TotalLineCount()-(renderWindow+1)*m.viewHeight(), math.MaxInt)
 	_ = m.vp.GotoBottom()
 }
 
-// CopyLastMessage returns the raw text of the most recent agent message.
+// CopyLastMessage returns the text of the most recent agent message, with
+// secrets masked unless they are revealed.
 // Returns an empty string when there are no agent messages.
 func (m Model) CopyLastMessage() string {
 	for i := len(m.items) - 1; i >= 0; i-- {
 		if a, ok := m.items[i].(*assistantMessageItem); ok {
-			return a.content
+			return redact.Display(a.content)
 		}
 	}
 	return ""
 }
 
+// SelectPrev moves the message selection to the previous user or agent
+// message. With nothing selected it starts at the latest one.
+func (m *Model) SelectPrev() {
+	start := len(m.items)
+	if m.selected > 0 {
+		start = m.selected - 1
+	}
+	for i := start - 1; i >= 0; i-- {
+		if selectable(m.items[i]) {
+			m.selected = i + 1
+			m.refresh()
+			return
+		}
+	}
+}
+
+// SelectNext moves the message selection to the next user or agent message.
+// Moving past the latest message clears the selection.
+func (m *Model) SelectNext() {
+	if m.selected == 0 {
+		return
+	}
+	for i := m.selected; i < len(m.items); i++ {
+		if selectable(m.items[i]) {
+			m.selected = i + 1
+			m.refresh()
+			return
+		}
+	}
+	m.ClearSelection()
+}
+
+// ToggleCollapsed expands or collapses the selected agent message, or the
+// latest long one when nothing is selected. It reports false when there is
+// no long message to toggle.
+func (m *Model) ToggleCollapsed() bool {
+	var target *assistantMessageItem
+	if m.selected > 0 {
+		if a, ok := m.items[m.selected-1].(*assistantMessageItem); ok && a.long {
+			target = a
+		}
+	} else {
+		for i := len(m.items) - 1; i >= 0; i-- {
+			if a, ok := m.items[i].(*assistantMessageItem); ok && a.long {
+				target = a
+				break
+			}
+		}
+	}
+	if target == nil {
+		return false
+	}
+	target.expanded = !target.expanded
+	target.version++
+	m.refresh()
+	return true
+}
+
+// ToggleRaw switches the selected agent message, or the latest one when
+// nothing is selected, between rendered markdown and its source. It reports
+// false when there is no answer to switch.
+func (m *Model) ToggleRaw() bool {
+	var target *assistantMessageItem
+	if m.selected > 0 {
+		if a, ok := m.items[m.selected-1].(*assistantMessageItem); ok && !a.isError && !a.isCancelled {
+			target = a
+		}
+	} else {
+		for i := len(m.items) - 1; i >= 0; i-- {
+			if a, ok := m.items[i].(*assistantMessageItem); ok && !a.isError && !a.isCancelled && !a.shouldSkip() {
+				target = a
+				break
+			}
+		}
+	}
+	if target == nil {
+		return false
+	}
+	target.raw = !target.raw
+	target.version++
+	m.refresh()
+	return true
+}
+
+// ToggleToolCalls lists or regroups the tool calls of the selected agent
+// message, or of the latest one with many when nothing is selected. It
+// reports false when there is no such group.
+func (m *Model) ToggleToolCalls() bool {
+	var target *assistantMessageItem
+	if m.selected > 0 {
+		if a, ok := m.items[m.selected-1].(*assistantMessageItem); ok && len(a.toolCalls) >= groupToolCalls {
+			target = a
+		}
+	} else {
+		for i := len(m.items) - 1; i >= 0; i-- {
+			if a, ok := m.items[i].(*assistantMessageItem); ok && len(a.toolCalls) >= groupToolCalls {
+				target = a
+				break
+			}
+		}
+	}
+	if target == nil {
+		return false
+	}
+	target.showTools = !target.showTools
+	target.version++
+	m.refresh()
+	return true
+}
+
+// ClearSelection drops the message selection and follows the bottom again.
+func (m *Model) ClearSelection() {
+	if m.selected == 0 {
+		return
+	}
+	m.selected = 0
+	m.refresh()
+}
+
+// HasSelection reports whether a message is selected.
+func (m Model) HasSelection() bool {
+	return m.selected > 0
+}
+
+// SelectedQuote returns the selected message, or the latest agent message
+// when nothing is selected. ok is false when there is nothing to quote.
+func (m Model) SelectedQuote() (q Quote, ok bool) {
+	if m.selected > 0 {
+		return quoteOf(m.items[m.selected-1])
+	}
+	for i := len(m.items) - 1; i >= 0; i-- {
+		if _, isAgent := m.items[i].(*assistantMessageItem); isAgent && selectable(m.items[i]) {
+			return quoteOf(m.items[i])
+		}
+	}
+	return Quote{}, false
+}
+
+// TimelineEntry is a user or agent message as listed by Timeline.
+type TimelineEntry struct {
+	Index int // item position, for JumpTo
+	Role  MessageRole
+	Time  time.Time
+	Text  string
+}
+
+// Timeline lists the selectable messages in chat order.
+func (m Model) Timeline() []TimelineEntry {
+	var out []TimelineEntry
+	for i, it := range m.items {
+		if !selectable(it) {
+			continue
+		}
+		if q, ok := quoteOf(it); ok {
+			out = append(out, TimelineEntry{Index: i, Role: q.Role, Time: q.Timestamp, Text: q.Content})
+		}
+	}
+	return out
+}
+
+// JumpTo selects the message at item position index, scrolling it into
+// view. It reports false when there is no selectable message there.
+func (m *Model) JumpTo(index int) bool {
+	if index < 0 || index >= len(m.items) || !selectable(m.items[index]) {
+		return false
+	}
+	m.selected = index + 1
+	m.refresh()
+	return true
+}
+
+// StampLast sets the time of the latest message, e.g. to the original time
+// of a message restored from a resumed session.
+func (m *Model) StampLast(t time.Time) {
+	if len(m.items) == 0 {
+		return
+	}
+	switch v := m.items[len(m.items)-1].(type) {
+	case *userMessageItem:
+		v.ts = t
+	case *assistantMessageItem:
+		v.ts = t
+	case *systemMessageItem:
+		v.ts = t
+	}
+}
+
+// latestPrompt returns the latest prompt that is not a slash command.
+func (m Model) latestPrompt() (*userMessageItem, bool) {
+	for i := len(m.items) - 1; i >= 0; i-- {
+		if u, ok := m.items[i].(*userMessageItem); ok && !strings.HasPrefix(u.content, "/") {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// MarkPromptFailed marks the latest prompt as failed for reason, shown
+// under it with the key to retry it, and remembers requestID for the retry.
+// It reports false when there is no prompt to mark.
+func (m *Model) MarkPromptFailed(reason, requestID string) bool {
+	u, ok := m.latestPrompt()
+	if !ok {
+		return false
+	}
+	u.failed, u.request = reason, requestID
+	u.version++
+	m.refresh()
+	return true
+}
+
+// FailedPrompt returns the failed prompt r retries: the selected message when
+// it is one, otherwise the latest prompt when it failed. ok is false when
+// there is none; a prompt answered since does not count.
+func (m Model) FailedPrompt() (t RetryTarget, ok bool) {
+	u, ok := m.latestPrompt()
+	if m.selected > 0 {
+		u, ok = m.items[m.selected-1].(*userMessageItem)
+	}
+	if !ok || u.failed == "" {
+		return RetryTarget{}, false
+	}
+	return RetryTarget{Prompt: u.content, PromptID: u.id, RequestID: u.request}, true
+}
+
+// ClearFailed removes the failure marker from the prompt with the given ID,
+// e.g. when it is retried.
+func (m *Model) ClearFailed(id string) {
+	for _, it := range m.items {
+		if u, ok := it.(*userMessageItem); ok && u.id == id && u.failed != "" {
+			u.failed, u.request = "", ""
+			u.version++
+			m.refresh()
+			return
+		}
+	}
+}
+
+// LastAgentID returns the ID of the latest agent message, or "" when there
+// is none.
+func (m Model) LastAgentID() string {
+	for i := len(m.items) - 1; i >= 0; i-- {
+		if a, ok := m.items[i].(*assistantMessageItem); ok {
+			return a.id
+		}
+	}
+	return ""
+}
+
+// SetAgentMeta replaces the signal and duration of the agent message id,
+// such as when a second delivery of the same answer carries better values.
+// A nil sig or a durationMs of 0 leaves that value unchanged.
+func (m *Model) SetAgentMeta(id string, sig *Signal, durationMs int64) {
+	for _, it := range m.items {
+		a, ok := it.(*assistantMessageItem)
+		if !ok || a.id != id {
+			continue
+		}
+		if sig != nil {
+			a.signal = sig
+		}
+		if durationMs > 0 {
+			a.durationMs = durationMs
+		}
+		a.version++
+		m.refresh()
+		return
+	}
+}
+
+// RetryTarget identifies the prompt to resubmit for a retry.
+type RetryTarget struct {
+	Prompt    string
+	PromptID  string
+	AnswerID  string // original answer, or "" when the request produced none
+	RequestID string // request of a failed prompt, or "" when it did not fail
+}
+
+// RetryTarget returns the prompt behind the selected message, or behind the
+// latest message when nothing is selected. Selecting a prompt retries it;
+// selecting an answer retries the prompt before it. Alternatives link back to
+// the first answer. ok is false when there is no prompt to retry.
+func (m Model) RetryTarget() (t RetryTarget, ok bool) {
+	end := len(m.items) - 1
+	if m.selected > 0 {
+		end = m.selected - 1
+	}
+	pi := -1
+	for i := end; i >= 0; i-- {
+		if u, isUser := m.items[i].(*userMessageItem); isUser && !strings.HasPrefix(u.content, "/") {
+			pi = i
+			break
+		}
+	}
+	if pi < 0 {
+		return RetryTarget{}, false
+	}
+	u := m.items[pi].(*userMessageItem)
+	t.Prompt, t.PromptID = u.content, u.id
+	if u.failed != "" {
+		t.RequestID = u.request
+	}
+	// The first answer after the prompt, up to the next prompt.
+	for i := pi + 1; i < len(m.items); i++ {
+		if _, isUser := m.items[i].(*userMessageItem); isUser {
+			break
+		}
+		if a, isAgent := m.items[i].(*assistantMessageItem); isAgent && !a.shouldSkip() {
+			t.AnswerID = a.id
+			if a.altOf != "" {
+				t.AnswerID = a.altOf
+			}
+			break
+		}
+	}
+	return t, true
+}
+
+// selectable reports whether an item can be selected for a reply: user
+// messages and agent messages with text.
+func selectable(it Item) bool {
+	switch v := it.(type) {
+	case *userMessageItem:
+		return true
+	case *assistantMessageItem:
+		return strings.TrimSpace(v.content) != ""
+	}
+	return false
+}
+
+func quoteOf(it Item) (Quote, bool) {
+	switch v := it.(type) {
+	case *userMessageItem:
+		return Quote{ID: v.id, Role: RoleUser, Content: v.content, Timestamp: v.ts}, true
+	case *assistantMessageItem:
+		return Quote{ID: v.id, Role: RoleAgent, Content: v.content, Timestamp: v.ts}, true
+	}
+	return Quote{}, false
+}
+
 // ---------------------------------------------------------------------------
 // Bubble Tea interface
 // ---------------------------------------------------------------------------
@@ -700,6 +1732,7 @@ func (m Model) CopyLastMessage() string {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.vp, cmd = m.vp.Update(msg)
+	m.ensureRendered()
 	return m, cmd
 }
 
@@ -734,18 +1767,54 @@ func (m *Model) contentWidth() int {
 	return cw
 }
 
-// refresh re-renders all content into the viewport and scrolls to bottom.
+// refresh re-renders all content into the viewport and scrolls to bottom, or
+// to the selected message while one is selected.
 func (m *Model) refresh() {
-	m.vp.SetContent(m.renderAll())
+	h := m.viewHeight()
+	// Laid out without rendering an item, which places the selection and
+	// measures the conversation.
+	layout := m.renderAll(0, 0)
+	if m.selected > 0 {
+		m.setWindow(m.selLine-renderWindow*h, m.selLine+(renderWindow+1)*h)
+		m.vp.SetYOffset(m.selLine)
+		return
+	}
+	m.setWindow(lipgloss.Height(layout)-(renderWindow+1)*h, math.MaxInt)
 	_ = m.vp.GotoBottom()
 }
 
+// renderWindow is how many viewport heights above and below the visible
+// lines are rendered, so scrolling a little does not render again.
+const renderWindow = 2
+
+// viewHeight returns the viewport height, at least one line.
+func (m *Model) viewHeight() int { return max(m.vp.Height(), 1) }
+
+// setWindow renders the content lines [from, to) into the viewport.
+func (m *Model) setWindow(from, to int) {
+	m.winFrom, m.winTo = from, to
+	m.vp.SetContent(m.renderAll(from, to))
+}
+
+// ensureRendered renders around the viewport once it has scrolled out of
+// the rendered lines.
+func (m *Model) ensureRendered() {
+	off, h := m.vp.YOffset(), m.viewHeight()
+	if len(m.items) == 0 || (off >= m.winFrom && off+h <= m.winTo) {
+		return
+	}
+	m.setWindow(off-renderWindow*h, off+(renderWindow+1)*h)
+	m.vp.SetYOffset(off)
+}
+
 // streamingCursor returns the blinking block cursor appended to streaming text.
 // Uses a simple Unicode block — bubbletea will re-render each frame while streaming.
 const streamingCursor = "█"
 
-// renderAll builds the complete display string: items + thinking box + streaming overlay.
-func (m *Model) renderAll() string {
+// renderAll builds the complete display string: items + thinking box +
+// streaming overlay. Items entirely outside the lines [from, to) are left
+// blank, as many lines as their Height.
+func (m *Model) renderAll(from, to int) string {
 	if len(m.items) == 0 {
 		return renderWelcome(m.width, m.welcomeVersion, m.welcomeDetail, m.welcomeCwd)
 	}
@@ -753,37 +1822,67 @@ func (m *Model) renderAll() string {
 	cw := m.contentWidth()
 	var sb strings.Builder
 
-	// Track the index of the last non-skipped assistant item to apply focus border.
-	lastAgentIdx := -1
-	if m.focused {
+	// The selected message gets the focus border; without a selection it goes
+	// to the last non-skipped assistant item.
+	focusIdx := -1
+	if m.selected > 0 {
+		focusIdx = m.selected - 1
+	} else if m.focused {
 		for i := len(m.items) - 1; i >= 0; i-- {
 			if a, ok := m.items[i].(*assistantMessageItem); ok && !a.shouldSkip() {
-				lastAgentIdx = i
+				focusIdx = i
 				break
 			}
 		}
 	}
 
+	// line counts the lines written so far, letting an item be placed
+	// without looking back at the output.
+	line := 0
+	write := func(s string) {
+		sb.WriteString(s)
+		line += strings.Count(s, "\n")
+	}
+
 	rendered := 0
+	var prevTime time.Time
 	for i, item := range m.items {
 		// Skip assistant messages that have no content and no tool calls.
 		if a, ok := item.(*assistantMessageItem); ok && a.shouldSkip() {
 			continue
 		}
+		if m.noticeHidden(item) {
+			continue
+		}
 
 		if rendered > 0 {
 			// One blank line between messages for readability.
-			sb.WriteString("\n\n")
+			write("\n\n")
+		}
+		// A rule names the new day where the date changes.
+		if t := itemTime(item); !t.IsZero() {
+			if !prevTime.IsZero() && !sameDay(prevTime, t) {
+				write(daySeparator(t, cw) + "\n\n")
+			}
+			prevTime = t
 		}
 
-		if m.focused && i == lastAgentIdx {
-			if a, ok := item.(*assistantMessageItem); ok {
-				sb.WriteString(renderFocusedAssistant(a, cw))
-			} else {
-				sb.WriteString(item.Render(cw))
+		if i == focusIdx {
+			m.selLine = line
+		}
+		if h := item.Height(cw); line+h <= from || line >= to {
+			write(strings.Repeat("\n", max(h-1, 0)))
+		} else if i == focusIdx {
+			switch v := item.(type) {
+			case *assistantMessageItem:
+				write(renderFocusedAssistant(v, cw))
+			case *userMessageItem:
+				write(renderFocusedUser(v, cw))
+			default:
+				write(item.Render(cw))
 			}
 		} else {
-			sb.WriteString(item.Render(cw))
+			write(item.Render(cw))
 		}
 		rendered++
 	}
@@ -799,18 +1898,21 @@ func (m *Model) renderAll() string {
 
 	// Streaming partial response takes priority over processingView.
 	if m.streamingContent != "" {
-		label := style.AgentLabel.Render("◈ OSA")
+		label := style.AgentLabel.Render(style.Glyph("◈ OSA", "OSA:"))
 		border := lipgloss.NewStyle().
-			Border(lipgloss.ThickBorder(), false, false, false, true).
+			Border(style.Frame(lipgloss.ThickBorder()), false, false, false, true).
 			BorderForeground(style.MsgBorderAgent).
 			PaddingLeft(1).
 			Width(cappedWidth(cw))
 		if rendered > 0 {
 			sb.WriteString("\n\n")
 		}
-		// Append cursor to indicate active streaming.
-		cursorStyle := lipgloss.NewStyle().Foreground(style.Primary)
-		sb.WriteString(border.Render(label + "\n" + m.streamingContent + cursorStyle.Render(streamingCursor)))
+		// Append cursor to indicate active streaming, unless motion is reduced.
+		cursor := ""
+		if !style.ReducedMotion {
+			cursor = lipgloss.NewStyle().Foreground(style.Primary).Render(streamingCursor)
+		}
+		sb.WriteString(border.Render(label + "\n" + redact.Display(m.streamingContent) + cursor))
 	} else if m.processingView != "" {
 		if rendered > 0 {
 			sb.WriteString("\n\n")
@@ -827,60 +1929,43 @@ func renderFocusedAssistant(a *assistantMessageItem, cw int) string {
 	cw = cappedWidth(cw)
 
 	// Label + optional signal badge
-	label := style.AgentLabel.Render("◈ OSA")
+	label := style.AgentLabel.Render(style.Glyph("◈ OSA", "OSA:"))
 	if a.signal != nil && a.signal.Mode != "" && a.signal.Genre != "" {
 		badge := style.StatusSignal.Render(
 			fmt.Sprintf(" [%s/%s]", a.signal.Mode, a.signal.Genre),
 		)
 		label += badge
 	}
+	label += a.linkLabel() + a.rawLabel()
 
-	body := renderMarkdown(a.content, cw-2)
+	body := a.collapse(a.markdownBody(redact.Display(a.content), cw))
 
-	var toolSection string
-	if len(a.toolCalls) > 0 {
-		var tb strings.Builder
-		for _, tc := range a.toolCalls {
-			tb.WriteString("\n")
-			status := toolCallStatus(tc)
-			tb.WriteString(tools.RenderToolCall(
-				tc.Name, tc.Args, tc.Result,
-				tools.RenderOpts{
-					Status:     tools.ToolStatus(status),
-					Width:      cw - 2,
-					Expanded:   false,
-					DurationMs: tc.DurationMs,
-				},
-			))
-		}
-		toolSection = tb.String()
-	}
+	toolSection := a.toolSection(cw)
 
 	var meta string
-	if a.modelName != "" || a.durationMs > 0 {
-		var parts []string
-		if a.modelName != "" {
-			parts = append(parts, a.modelName)
-		}
-		if a.durationMs > 0 {
-			parts = append(parts, formatDuration(a.durationMs))
-		}
-		if a.inputTokens > 0 || a.outputTokens > 0 {
-			parts = append(parts, fmt.Sprintf("↓%s ↑%s",
-				formatTokens(a.inputTokens),
-				formatTokens(a.outputTokens),
-			))
-		}
-		meta = "\n" + style.MsgMeta.Render("— "+strings.Join(parts, " · "))
+	if m := a.meta(); m != "" {
+		meta = "\n" + m
 	}
 
 	// Use Primary (brighter) color for the border when focused.
 	border := lipgloss.NewStyle().
-		Border(lipgloss.ThickBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.ThickBorder()), false, false, false, true).
+		BorderForeground(style.Primary).
+		PaddingLeft(1).
+		Width(cw)
+	return border.Render(label + "\n" + a.thinkingSection() + body + toolSection + meta)
+}
+
+// renderFocusedUser renders a userMessageItem with the brighter focus border.
+func renderFocusedUser(u *userMessageItem, cw int) string {
+	cw = cappedWidth(cw)
+	label := u.label()
+	border := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.ThickBorder()), false, false, false, true).
 		BorderForeground(style.Primary).
 		PaddingLeft(1).
 		Width(cw)
-	return border.Render(label + "\n" + body + toolSection + meta)
+	return border.Render(label + "\n" + u.body())
 }
 
 // ---------------------------------------------------------------------------
@@ -888,12 +1973,40 @@ func renderFocusedAssistant(a *assistantMessageItem, cw int) string {
 // ---------------------------------------------------------------------------
 
 // renderMarkdown renders markdown text using glamour, falling back to plain text on error.
+// Screen-reader mode keeps the source text, which reads linearly. Tables are
+// laid out by mdTable between the glamour-rendered runs around them.
 func renderMarkdown(md string, width int) string {
-	if strings.TrimSpace(md) == "" {
+	if strings.TrimSpace(md) == "" || style.ScreenReader {
 		return md
 	}
+	segs := splitTables(md)
+	if len(segs) == 1 && segs[0].table == nil {
+		return renderGlamour(md, width)
+	}
+	var sb strings.Builder
+	for i, seg := range segs {
+		if seg.table == nil {
+			// Glamour starts with a blank line, which separates the run
+			// from a table above it.
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(renderGlamour(seg.text, width))
+			continue
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n" + strings.Join(seg.table.render(width), "\n"))
+	}
+	return sb.String()
+}
+
+// renderGlamour renders md with glamour and swaps task checkboxes for the
+// task panel glyphs.
+func renderGlamour(md string, width int) string {
 	r, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+		glamour.WithStyles(markdownStyle()),
 		glamour.WithWordWrap(width),
 	)
 	if err != nil {
@@ -903,13 +2016,88 @@ func renderMarkdown(md string, width int) string {
 	if err != nil {
 		return md
 	}
-	return strings.TrimRight(out, "\n")
+	// Some blocks end on a line holding nothing but a reset sequence.
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for len(lines) > 1 && strings.TrimSpace(ansi.Strip(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return taskGlyphs(strings.Join(lines, "\n"))
 }
 
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
 
+// timestamps is how message times show, set by SetTimestamps. Like cacheGen
+// it is package state, since items render without their Model.
+var timestamps Timestamps
+
+// hideNotices leaves system notices out of every chat list, as set by
+// SetNoticesHidden.
+var hideNotices bool
+
+// stamp formats t for a meta line, or returns "" when timestamps are off or
+// the time is unknown.
+func stamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch timestamps {
+	case TimestampsRelative:
+		return relativeTime(t, time.Now())
+	case TimestampsAbsolute:
+		return t.Format("15:04")
+	}
+	return ""
+}
+
+// relativeTime formats how long before now t was: "just now", "5m ago",
+// "3h ago" or "2d ago".
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+}
+
+// itemTime returns when a message was sent, zero when unknown.
+func itemTime(it Item) time.Time {
+	switch v := it.(type) {
+	case *userMessageItem:
+		return v.ts
+	case *assistantMessageItem:
+		return v.ts
+	case *systemMessageItem:
+		return v.ts
+	}
+	return time.Time{}
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// daySeparator renders the rule between messages of different days,
+// "──── Monday, March 2 ────", with the year when it is not this one.
+func daySeparator(t time.Time, cw int) string {
+	label := t.Format("Monday, January 2")
+	if t.Year() != time.Now().Year() {
+		label = t.Format("Monday, January 2, 2006")
+	}
+	label = " " + label + " "
+	rule := style.Glyph("─", "-")
+	side := max((cappedWidth(cw)-ansi.StringWidth(label))/2, 2)
+	return style.Faint.Render(strings.Repeat(rule, side) + label + strings.Repeat(rule, side))
+}
+
 // formatDuration converts milliseconds to a human-readable string.
 func formatDuration(ms int64) string {
 	if ms < 1000 {
@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+)
+
+// TestHeightMatchesRender checks that Height measured from the raw content,
+// before any render is cached, equals the height of the rendered item.
+func TestHeightMatchesRender(t *testing.T) {
+	long := strings.Repeat("the quick brown fox jumps over the lazy dog ", 12)
+	withTools := newAssistantItem("msg-4", "Done.", nil, 1200, "")
+	withTools.toolCalls = []ToolCallDisplay{
+		{Name: "file_read", Args: "app/main.go", Result: "package main\n\nfunc main() {}", Done: true, Success: true},
+		{Name: "shell_execute", Args: "ls", Result: strings.Repeat("line\n", 30), Done: true, Success: true},
+	}
+
+	items := []struct {
+		name string
+		item func() Item
+	}{
+		{"plain", func() Item { return newUserItem("msg-1", "hello") }},
+		{"wrapped", func() Item { return newUserItem("msg-2", long) }},
+		{"system wrapped", func() Item { return newSystemItem("msg-3", long, LevelInfo) }},
+		{"markdown", func() Item {
+			md := "# Plan\n\nFirst a paragraph that is long enough to wrap at the narrow width " +
+				"used here.\n\n- one\n- two\n  continued\n\n```go\nfunc f() {}\n```\n\n> quoted"
+			return newAssistantItem("msg-5", md, nil, 0, "")
+		}},
+		{"tool calls", func() Item { return withTools }},
+	}
+	for _, width := range []int{40, 80, 200} {
+		for _, tc := range items {
+			it := tc.item()
+			got := it.Height(width)
+			want := lipgloss.Height(it.Render(width))
+			if got != want {
+				t.Errorf("%s at %d: Height = %d, rendered height = %d", tc.name, width, got, want)
+			}
+			// withTools is shared; drop its cache for the next width.
+			withTools.version++
+		}
+	}
+}
+
+// TestWindowedRender checks that after a resize only the items near the
+// viewport render and that the conversation keeps the length of a full
+// render.
+func TestWindowedRender(t *testing.T) {
+	m := New(80, 10)
+	for range 50 {
+		m.AddUserMessage("question")
+		m.AddSystemMessage("answer")
+	}
+	m.SetSize(100, 10)
+	first := m.items[0].(*userMessageItem)
+	if _, ok := first.cache.get(cappedWidth(m.contentWidth()), first.version); ok {
+		t.Fatal("the first item was rendered although it is far above the viewport")
+	}
+	last := m.items[len(m.items)-1].(*systemMessageItem)
+	if _, ok := last.cache.get(cappedWidth(m.contentWidth()), last.version); !ok {
+		t.Fatal("the last item was not rendered")
+	}
+
+	full := m.renderAll(0, math.MaxInt)
+	if got, want := m.Lines(), lipgloss.Height(full); got != want {
+		t.Errorf("Lines() = %d, full render has %d lines", got, want)
+	}
+
+	m.ScrollToTop()
+	if !strings.Contains(m.View(), "question") {
+		t.Errorf("top of the conversation not rendered after scrolling up:\n%s", m.View())
+	}
+}
@@ -0,0 +1,283 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/style"
+)
+
+// Glamour lays tables out by splitting the width evenly and wrapping every
+// cell, which shreds wide tables at narrow widths, and draws task lists as
+// "[x]". The chat renders GFM tables itself and swaps task checkboxes for the
+// task panel's glyphs.
+
+// Task checkbox markers glamour is told to draw, replaced by the task panel
+// glyphs after rendering. Private-use runes never occur in answers.
+const (
+	tickedMarker   = "\uE000"
+	untickedMarker = "\uE001"
+)
+
+// markdownStyle returns glamour's style for the active theme, with the task
+// checkbox markers.
+func markdownStyle() ansi.StyleConfig {
+	cfg := styles.LightStyleConfig
+	if style.IsDark() {
+		cfg = styles.DarkStyleConfig
+	}
+	cfg.Task.Ticked = tickedMarker + " "
+	cfg.Task.Unticked = untickedMarker + " "
+	return cfg
+}
+
+// taskGlyphs replaces the checkbox markers in rendered markdown with ✔ and ◻
+// styled as done and pending tasks.
+func taskGlyphs(s string) string {
+	if !strings.Contains(s, tickedMarker) && !strings.Contains(s, untickedMarker) {
+		return s
+	}
+	return strings.NewReplacer(
+		tickedMarker, style.TaskDone.Render("✔"),
+		untickedMarker, style.TaskPending.Render("◻"),
+	).Replace(s)
+}
+
+// ---------------------------------------------------------------------------
+// Tables
+// ---------------------------------------------------------------------------
+
+// tableMinColumn is the narrowest a column is shrunk to before columns are
+// dropped from the right.
+const tableMinColumn = 8
+
+// mdAlign is the alignment of a table column.
+type mdAlign int
+
+const (
+	alignLeft mdAlign = iota
+	alignCenter
+	alignRight
+)
+
+// mdTable is a GFM table parsed from an answer.
+type mdTable struct {
+	header []string
+	align  []mdAlign
+	rows   [][]string
+}
+
+// mdSegment is a run of markdown source, or a table when table is set.
+type mdSegment struct {
+	text  string
+	table *mdTable
+}
+
+// delimiterCell matches a cell of a table's delimiter row, such as ":--:".
+var delimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+// splitTables cuts md into markdown runs and the GFM tables between them.
+// Tables inside code fences stay markdown.
+func splitTables(md string) []mdSegment {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var segs []mdSegment
+	var text []string
+	flush := func() {
+		if strings.TrimSpace(strings.Join(text, "\n")) != "" {
+			segs = append(segs, mdSegment{text: strings.Join(text, "\n")})
+		}
+		text = nil
+	}
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		l := strings.TrimSpace(lines[i])
+		switch {
+		case fence != "":
+			if strings.HasPrefix(l, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(l, "```") || strings.HasPrefix(l, "~~~"):
+			fence = l[:3]
+		case i+1 < len(lines) && strings.Contains(l, "|"):
+			t, n := parseTable(lines[i:])
+			if t == nil {
+				break
+			}
+			flush()
+			segs = append(segs, mdSegment{table: t})
+			i += n - 1
+			continue
+		}
+		text = append(text, lines[i])
+	}
+	flush()
+	return segs
+}
+
+// parseTable parses the table at the start of lines and returns it with the
+// number of lines it spans, or nil when lines do not start with a header row
+// and a delimiter row of the same width.
+func parseTable(lines []string) (*mdTable, int) {
+	header := tableCells(lines[0])
+	delim := tableCells(lines[1])
+	if len(header) == 0 || len(delim) != len(header) {
+		return nil, 0
+	}
+	t := &mdTable{header: header, align: make([]mdAlign, len(delim))}
+	for i, d := range delim {
+		if !delimiterCell.MatchString(d) {
+			return nil, 0
+		}
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			t.align[i] = alignCenter
+		case strings.HasSuffix(d, ":"):
+			t.align[i] = alignRight
+		}
+	}
+	n := 2
+	for ; n < len(lines); n++ {
+		l := strings.TrimSpace(lines[n])
+		if l == "" || !strings.Contains(l, "|") {
+			break
+		}
+		row := tableCells(l)
+		row = append(row, make([]string, max(len(header)-len(row), 0))...)
+		t.rows = append(t.rows, row[:len(header)])
+	}
+	return t, n
+}
+
+// inlineLink matches a markdown link, kept as its text in table cells.
+var inlineLink = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+
+// tableCells splits a table row on unescaped pipes, dropping the outer ones,
+// and strips inline emphasis, code and link markup from each cell.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	cells = append(cells, cell.String())
+	for i, c := range cells {
+		c = inlineLink.ReplaceAllString(c, "$1")
+		c = strings.NewReplacer("**", "", "__", "", "`", "").Replace(c)
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
+
+// render lays the table out within width, glamour's wrap width, indented by
+// glamour's margin. Columns keep their natural width when the table fits;
+// otherwise the widest shrink down to tableMinColumn, wrapping their cells,
+// and columns that still do not fit are dropped from the right behind a
+// hint naming them.
+func (t *mdTable) render(width int) []string {
+	avail := max(width-4, 1)
+	const sep = 3 // " │ "
+	widths := make([]int, len(t.header))
+	for i, h := range t.header {
+		widths[i] = xansi.StringWidth(h)
+		for _, r := range t.rows {
+			widths[i] = max(widths[i], xansi.StringWidth(r[i]))
+		}
+		widths[i] = max(widths[i], 1)
+	}
+	total := sep * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > avail {
+		widest := -1
+		for i, w := range widths {
+			if w > tableMinColumn && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	shown := len(widths)
+	for shown > 1 && total > avail {
+		shown--
+		total -= widths[shown] + sep
+	}
+	if total > avail {
+		widths[0] = avail
+	}
+
+	rule := lipgloss.NewStyle().Foreground(style.Border)
+	head := lipgloss.NewStyle().Bold(true)
+	var out []string
+	row := func(cells []string, s lipgloss.Style) {
+		wrapped := make([][]string, shown)
+		height := 1
+		for i := range shown {
+			wrapped[i] = strings.Split(xansi.Wrap(cells[i], widths[i], ""), "\n")
+			height = max(height, len(wrapped[i]))
+		}
+		for l := range height {
+			parts := make([]string, shown)
+			for i := range shown {
+				text := ""
+				if l < len(wrapped[i]) {
+					text = wrapped[i][l]
+				}
+				parts[i] = s.Render(alignCell(text, widths[i], t.align[i]))
+			}
+			out = append(out, "  "+strings.Join(parts, rule.Render(" │ ")))
+		}
+	}
+	row(t.header, head)
+	dashes := make([]string, shown)
+	for i := range shown {
+		dashes[i] = strings.Repeat("─", widths[i])
+	}
+	out = append(out, "  "+rule.Render(strings.Join(dashes, "─┼─")))
+	for _, r := range t.rows {
+		row(r, lipgloss.NewStyle())
+	}
+	if hidden := t.header[shown:]; len(hidden) > 0 {
+		hint := fmt.Sprintf("→ %d more columns: %s", len(hidden), strings.Join(hidden, ", "))
+		if len(hidden) == 1 {
+			hint = "→ 1 more column: " + hidden[0]
+		}
+		out = append(out, "  "+style.Faint.Render(xansi.Truncate(hint+" (widen the terminal to see them)", avail, "…")))
+	}
+	return out
+}
+
+// alignCell pads text to width as the column is aligned.
+func alignCell(text string, width int, a mdAlign) string {
+	pad := max(width-xansi.StringWidth(text), 0)
+	switch a {
+	case alignRight:
+		return strings.Repeat(" ", pad) + text
+	case alignCenter:
+		return strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2)
+	}
+	return text + strings.Repeat(" ", pad)
+}
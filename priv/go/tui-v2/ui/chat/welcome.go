@@ -23,7 +23,7 @@ func renderWelcome(width int, version, detail, cwd string) string {
 	logoStyle := lipgloss.NewStyle().Foreground(style.Primary)
 	logo := logoStyle.Render(OsaLogo)
 
-	title := style.WelcomeTitle.Render("◈ OSA Agent  " + version)
+	title := style.WelcomeTitle.Render(style.Glyph("◈ ", "") + "OSA Agent  " + version)
 	detailLine := style.WelcomeMeta.Render(detail)
 
 	maxCwd := width - 10
@@ -47,10 +47,12 @@ func renderWelcome(width int, version, detail, cwd string) string {
 	}
 
 	var lines []string
-	for _, l := range strings.Split(logo, "\n") {
-		lines = append(lines, center(l))
+	if !style.ScreenReader {
+		for _, l := range strings.Split(logo, "\n") {
+			lines = append(lines, center(l))
+		}
+		lines = append(lines, "")
 	}
-	lines = append(lines, "")
 	lines = append(lines, center(title))
 	if detail != "" {
 		lines = append(lines, center(detailLine))
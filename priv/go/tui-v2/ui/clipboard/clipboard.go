@@ -1,15 +1,21 @@
 // Package clipboard copies text to the system clipboard using the best
 // available mechanism: OSC 52 escape sequences (works over SSH and in
-// modern terminals) with a fallback to native OS commands.
+// modern terminals) with a fallback to native OS commands. It also reads
+// images from the clipboard with native commands.
 package clipboard
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // Copy copies text to the system clipboard.
@@ -84,6 +90,13 @@ func CopyNative(text string) error {
 	return nil
 }
 
+// NativeCommand returns the native command Copy falls back to, or "" when
+// the platform has none and copies rely on OSC 52 alone.
+func NativeCommand() string {
+	cmd, _ := detectClipboardCmd()
+	return cmd
+}
+
 // detectClipboardCmd returns the native clipboard command and its arguments
 // for the current operating system. Returns ("", nil) when none is available.
 func detectClipboardCmd() (string, []string) {
@@ -112,3 +125,70 @@ func detectClipboardCmd() (string, []string) {
 		return "", nil
 	}
 }
+
+// ErrNoImage is returned by ReadImage when the clipboard holds no image.
+var ErrNoImage = errors.New("clipboard: no image in clipboard")
+
+// ReadImage returns the image on the system clipboard as PNG data, using
+// pngpaste or osascript on macOS, wl-paste or xclip on Linux and PowerShell
+// on Windows. Terminals cannot paste binary data, so this reads the
+// clipboard directly and only works on the machine running the TUI.
+func ReadImage() ([]byte, error) {
+	var cmds [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("pngpaste"); err == nil {
+			cmds = append(cmds, []string{path, "-"})
+		}
+		cmds = append(cmds, []string{"osascript", "-e", "the clipboard as «class PNGf»"})
+	case "windows":
+		cmds = append(cmds, []string{"powershell", "-NoProfile", "-Command", windowsReadImage})
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			if path, err := exec.LookPath("wl-paste"); err == nil {
+				cmds = append(cmds, []string{path, "--no-newline", "--type", "image/png"})
+			}
+		}
+		if path, err := exec.LookPath("xclip"); err == nil {
+			cmds = append(cmds, []string{path, "-selection", "clipboard", "-t", "image/png", "-o"})
+		}
+	}
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("clipboard: no image clipboard command found for %s", runtime.GOOS)
+	}
+
+	for _, c := range cmds {
+		out, err := exec.Command(c[0], c[1:]...).Output()
+		if err != nil || len(out) == 0 {
+			continue
+		}
+		if filepath.Base(c[0]) == "osascript" {
+			out = decodeAppleScriptData(out)
+		}
+		if bytes.HasPrefix(out, pngMagic) {
+			return out, nil
+		}
+	}
+	return nil, ErrNoImage
+}
+
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+// windowsReadImage writes the clipboard image to stdout as PNG.
+const windowsReadImage = `Add-Type -AssemblyName System.Windows.Forms,System.Drawing;` +
+	`$i=[Windows.Forms.Clipboard]::GetImage(); if ($i -eq $null) { exit 1 };` +
+	`$s=New-Object IO.MemoryStream; $i.Save($s,[Drawing.Imaging.ImageFormat]::Png);` +
+	`$o=[Console]::OpenStandardOutput(); $o.Write($s.ToArray(),0,$s.Length)`
+
+// decodeAppleScriptData turns osascript's «data PNGf89504E47…» output into
+// the bytes it spells out in hex.
+func decodeAppleScriptData(out []byte) []byte {
+	s := strings.TrimSpace(string(out))
+	s = strings.TrimPrefix(s, "«data PNGf")
+	s = strings.TrimSuffix(s, "»")
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	return data
+}
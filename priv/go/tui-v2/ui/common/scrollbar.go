@@ -42,12 +42,32 @@ func (s *ScrollbarModel) SetDimensions(viewportHeight, contentHeight, offset int
 // content fits within the viewport the returned string is empty.
 func (s ScrollbarModel) View() string {
 	vh := s.viewportHeight
-	ch := s.contentHeight
-
-	if vh <= 0 || ch <= vh {
+	if vh <= 0 || s.contentHeight <= vh {
 		// No scrollbar needed.
 		return ""
 	}
+	thumbTop, thumbH := ScrollbarThumbSpan(vh, s.contentHeight, s.offset)
+
+	rows := make([]string, vh)
+	for i := range rows {
+		if i >= thumbTop && i < thumbTop+thumbH {
+			rows[i] = style.ScrollbarThumb.Render(scrollThumbChar)
+		} else {
+			rows[i] = style.ScrollbarTrack.Render(scrollTrackChar)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// ScrollbarThumbSpan returns the first row and the height of the thumb in a
+// track of viewportHeight rows. Callers that drag the thumb use it to map
+// rows back to offsets.
+func ScrollbarThumbSpan(viewportHeight, contentHeight, offset int) (top, height int) {
+	vh := viewportHeight
+	ch := contentHeight
+	if vh <= 0 || ch <= vh {
+		return 0, max(vh, 0)
+	}
 
 	// Thumb height — at least 1 row.
 	thumbH := vh * vh / ch
@@ -62,7 +82,7 @@ func (s ScrollbarModel) View() string {
 	scrollable := ch - vh
 	thumbTop := 0
 	if scrollable > 0 {
-		thumbTop = (s.offset * (vh - thumbH)) / scrollable
+		thumbTop = (offset * (vh - thumbH)) / scrollable
 	}
 	if thumbTop+thumbH > vh {
 		thumbTop = vh - thumbH
@@ -70,16 +90,7 @@ func (s ScrollbarModel) View() string {
 	if thumbTop < 0 {
 		thumbTop = 0
 	}
-
-	rows := make([]string, vh)
-	for i := range rows {
-		if i >= thumbTop && i < thumbTop+thumbH {
-			rows[i] = style.ScrollbarThumb.Render(scrollThumbChar)
-		} else {
-			rows[i] = style.ScrollbarTrack.Render(scrollTrackChar)
-		}
-	}
-	return strings.Join(rows, "\n")
+	return thumbTop, thumbH
 }
 
 // Scrollbar is a convenience function that builds a one-shot scrollbar string
@@ -20,6 +20,11 @@ type CompletionItem struct {
 	Category    string // "command", "file", "resource", "session", "config"
 	Icon        string // optional prefix icon; CategoryIcon used when empty
 	Type        string // "command", "file", "resource" — typed dispatch hint
+
+	// Documentation shown in the preview pane while the item is highlighted.
+	Usage   string // arguments, e.g. "/think fast|normal|deep"
+	Example string // an example invocation
+	Details string // full description; Description when empty
 }
 
 // SelectedMsg is emitted when the user accepts a completion item.
@@ -142,8 +147,8 @@ func (m Model) Selected() *CompletionItem {
 func (m Model) optimalWidth() int {
 	maxW := 40
 	for _, item := range m.filtered {
-		// icon(1) + spaces(3) + name + gap(2) + description
-		w := 4 + len(item.Name) + 2 + len(item.Description)
+		// border(2) + padding(2) + marker(2) + icon(1) + space(1) + name + gap(2) + description
+		w := 4 + 4 + lipgloss.Width(item.Name) + 2 + lipgloss.Width(item.Description)
 		if w > maxW {
 			maxW = w
 		}
@@ -295,13 +300,22 @@ func (m Model) View() string {
 	content := strings.Join(rows, "\n")
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Width(boxWidth).
 		Padding(0, 1).
 		Render(content)
 
-	return box
+	return m.withPreview(box)
+}
+
+// Height returns the number of lines View renders. Rows and the preview wrap
+// to the box, so the popup is measured rather than counted.
+func (m Model) Height() int {
+	if !m.visible || len(m.filtered) == 0 {
+		return 0
+	}
+	return lipgloss.Height(m.View())
 }
 
 // renderRow renders a single completion row with icon, name (with fuzzy-match
@@ -0,0 +1,65 @@
+package completions
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/style"
+)
+
+// Preview pane bounds: it sits beside the list when the terminal leaves
+// previewMinWidth columns for it, and below otherwise.
+const (
+	previewMinWidth = 30
+	previewMaxWidth = 56
+)
+
+// hasDocs reports whether item documents more than its list row shows.
+func hasDocs(item CompletionItem) bool {
+	return item.Usage != "" || item.Example != "" || item.Details != ""
+}
+
+// withPreview joins the list box with the documentation of the highlighted
+// item: beside it when there is room, below it otherwise.
+func (m Model) withPreview(box string) string {
+	item := m.Selected()
+	if item == nil || !hasDocs(*item) {
+		return box
+	}
+	if room := m.width - lipgloss.Width(box) - 1; room >= previewMinWidth {
+		return lipgloss.JoinHorizontal(lipgloss.Top, box, " ", previewBox(*item, min(room, previewMaxWidth)))
+	}
+	return box + "\n" + previewBox(*item, max(min(m.width, lipgloss.Width(box)), previewMinWidth))
+}
+
+// previewBox renders the full description, the usage line and an example
+// invocation of item in a frame width cells wide.
+func previewBox(item CompletionItem, width int) string {
+	inner := width - 4 // border + padding
+	wrap := lipgloss.NewStyle().Width(inner)
+	label := lipgloss.NewStyle().Foreground(style.Muted).Bold(true)
+
+	var sections []string
+	desc := item.Details
+	if desc == "" {
+		desc = item.Description
+	}
+	if desc != "" {
+		sections = append(sections, wrap.Render(desc))
+	}
+	if item.Usage != "" {
+		sections = append(sections, label.Render("Usage")+"\n"+
+			wrap.Foreground(style.Secondary).Render(item.Usage))
+	}
+	if item.Example != "" {
+		sections = append(sections, label.Render("Example")+"\n"+
+			wrap.Foreground(style.Secondary).Render(item.Example))
+	}
+
+	return lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Width(width).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n\n"))
+}
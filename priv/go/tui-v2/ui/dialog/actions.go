@@ -22,6 +22,14 @@ type ModelChoice struct {
 // ModelCancel is sent when the models dialog is dismissed without selecting.
 type ModelCancel struct{}
 
+// ModelFavoriteToggle is emitted when a model is starred or unstarred in the
+// models dialog. The caller persists the change.
+type ModelFavoriteToggle struct {
+	Provider string
+	Model    string
+	Favorite bool
+}
+
 // QuitConfirmed signals the user confirmed the quit prompt.
 type QuitConfirmed struct{}
 
@@ -36,6 +44,34 @@ type FilePickerResult struct {
 // FilePickerCancel signals that the file picker was dismissed.
 type FilePickerCancel struct{}
 
+// KeyAction is emitted by the provider keys dialog.
+// Action is one of: "set", "remove".
+type KeyAction struct {
+	Action   string
+	Provider string
+	APIKey   string // only for "set"
+}
+
+// OnboardingVerifyRequest is emitted when the wizard reaches the connection
+// test step. The app performs the request and reports back via
+// OnboardingModel.SetVerifyResult, with the same Seq.
+type OnboardingVerifyRequest struct {
+	Seq      int
+	Provider string
+	Model    string
+	APIKey   string
+	EnvVar   string
+}
+
+// OnboardingPullRequest is emitted when the user accepts pulling a missing
+// Ollama model from the connection test step.
+type OnboardingPullRequest struct {
+	Model string
+}
+
+// OnboardingPullCancel is emitted when the user aborts an in-flight pull.
+type OnboardingPullCancel struct{}
+
 // OnboardingDone is emitted when the onboarding wizard completes.
 type OnboardingDone struct {
 	Provider    string
@@ -0,0 +1,286 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+)
+
+// AuditEntry is one finished tool call shown by AuditModel.
+type AuditEntry struct {
+	Time       time.Time // when the call started
+	Agent      string    // sub-agent that made the call; "" for the session's agent
+	Tool       string
+	Args       string // summary of the arguments
+	DurationMs int64
+	Success    bool
+}
+
+// AuditExport is emitted when the user exports the entries the filter
+// keeps.
+type AuditExport struct {
+	Entries []AuditEntry
+	Filter  string
+}
+
+// AuditModel is the audit log opened by /audit: the tool calls of the
+// session in the order they started, filtered by tool, agent, arguments or
+// "failed", with the full arguments of the call under the cursor below.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type AuditModel struct {
+	entries    []AuditEntry
+	filtered   []AuditEntry
+	cursor     int
+	offset     int
+	filterText string
+
+	width, height int
+	pageSize      int
+}
+
+// NewAudit returns an empty AuditModel.
+func NewAudit() AuditModel {
+	return AuditModel{pageSize: 10}
+}
+
+// SetEntries populates the log, filtered by filter, such as the text given
+// to /audit, and puts the cursor on the latest call.
+func (m *AuditModel) SetEntries(entries []AuditEntry, filter string) {
+	m.entries = entries
+	m.filterText = filter
+	m.applyFilter()
+}
+
+// SetSize updates terminal dimensions.
+func (m *AuditModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-20, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the log.
+//
+//	↑/↓       → move cursor
+//	ctrl+e    → export the calls the filter keeps as CSV
+//	esc       → dismiss dialog (no action emitted)
+//	any char  → append to filter
+//	backspace → remove last filter char
+func (m AuditModel) Update(message tea.Msg) (AuditModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if kp.Code == 'e' && kp.Mod&tea.ModCtrl != 0 {
+		entries, filter := m.filtered, m.filterText
+		return m, func() tea.Msg { return AuditExport{Entries: entries, Filter: filter} }
+	}
+	switch kp.Code {
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeyBackspace:
+		if m.filterText != "" {
+			runes := []rune(m.filterText)
+			m.filterText = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	default:
+		if kp.Code >= 32 && kp.Code != tea.KeyDelete && kp.Code < 127 && kp.Mod&tea.ModCtrl == 0 {
+			m.filterText += string(rune(kp.Code))
+			m.applyFilter()
+		}
+	}
+	return m, nil
+}
+
+// FilterAudit returns the entries whose tool, agent or arguments contain
+// each word of filter; the word "failed" keeps the calls that failed.
+func FilterAudit(entries []AuditEntry, filter string) []AuditEntry {
+	words := strings.Fields(strings.ToLower(filter))
+	var out []AuditEntry
+	for _, e := range entries {
+		text := strings.ToLower(e.Tool + " " + auditAgent(e.Agent) + " " + e.Args)
+		keep := true
+		for _, w := range words {
+			if w == "failed" {
+				keep = keep && !e.Success
+				continue
+			}
+			keep = keep && strings.Contains(text, w)
+		}
+		if keep {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// applyFilter keeps the calls the filter text matches and puts the cursor on
+// the latest.
+func (m *AuditModel) applyFilter() {
+	m.filtered = FilterAudit(m.entries, m.filterText)
+	m.cursor = max(len(m.filtered)-1, 0)
+	m.offset = 0
+	m.scrollToCursor()
+}
+
+func (m *AuditModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the audit log dialog.
+func (m AuditModel) View() string {
+	dw := min(max(m.width-4, 40), 110)
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	failed := 0
+	for _, e := range m.entries {
+		if !e.Success {
+			failed++
+		}
+	}
+	title := i18n.T("Audit · %d tool calls", len(m.entries))
+	if failed > 0 {
+		title += " · " + i18n.T("%d failed", failed)
+	}
+	sb.WriteString(GradientTitle(title))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	filterVal := style.Faint.Render(i18n.T("type to filter..."))
+	if m.filterText != "" {
+		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(m.filterText) +
+			style.Faint.Render("  "+i18n.T("%d of %d", len(m.filtered), len(m.entries)))
+	}
+	sb.WriteString(style.DialogHelpKey.Render(i18n.T("Filter: ")) + filterVal)
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	agentW := min(14, inner/6)
+	toolW := min(18, inner/5)
+	if len(m.filtered) == 0 {
+		msg := i18n.T("No tool calls in this session yet")
+		if len(m.entries) > 0 {
+			msg = i18n.T("No tool calls found")
+		}
+		sb.WriteString(style.Faint.Render("  " + msg))
+		sb.WriteByte('\n')
+	} else {
+		head := fmt.Sprintf("    %-8s %-*s %-*s %7s  %s", i18n.T("Time"), agentW, i18n.T("Agent"), toolW, i18n.T("Tool"),
+			i18n.T("Took"), i18n.T("Arguments"))
+		sb.WriteString(style.DialogHelpKey.Render(ansi.Truncate(head, inner, "")))
+		sb.WriteByte('\n')
+		end := min(m.offset+m.pageSize, len(m.filtered))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.filtered[i], i == m.cursor, inner, agentW, toolW))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.filtered) {
+			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.cursor < len(m.filtered) {
+		e := m.filtered[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		status := i18n.T("succeeded")
+		if !e.Success {
+			status = i18n.T("failed")
+		}
+		sb.WriteString(style.Faint.Render(fmt.Sprintf("%s · %s · %s · %s", e.Time.Format(time.DateTime),
+			auditAgent(e.Agent), common.HumanDuration(e.DurationMs), status)))
+		sb.WriteByte('\n')
+		if e.Args != "" {
+			sb.WriteString(lipgloss.NewStyle().Width(inner).Render(e.Tool + " " + e.Args))
+			sb.WriteByte('\n')
+		}
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "ctrl+e", Desc: "export CSV"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// auditAgent names the agent of a call.
+func auditAgent(agent string) string {
+	if agent == "" {
+		return i18n.T("main")
+	}
+	return agent
+}
+
+// renderEntry renders one call: mark, start time, agent, tool, duration and
+// the start of the arguments.
+func (m AuditModel) renderEntry(e AuditEntry, isCursor bool, width, agentW, toolW int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+	mark := style.TaskDone.Render("✓ ")
+	if !e.Success {
+		mark = style.TaskFailed.Render("✗ ")
+	}
+
+	tool := fmt.Sprintf("%-*s", toolW, ansi.Truncate(e.Tool, toolW, "…"))
+	if isCursor {
+		tool = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(tool)
+	}
+	agent := fmt.Sprintf("%-*s", agentW, ansi.Truncate(auditAgent(e.Agent), agentW, "…"))
+	took := fmt.Sprintf("%7s", common.HumanDuration(e.DurationMs))
+	args := ansi.Truncate(strings.ReplaceAll(e.Args, "\n", " "), max(width-4-9-agentW-toolW-10, 8), "…")
+
+	return cursor + mark + style.Faint.Render(e.Time.Format("15:04:05")) + " " + style.Faint.Render(agent) + " " +
+		tool + " " + style.Faint.Render(took) + "  " + style.Faint.Render(args)
+}
@@ -0,0 +1,278 @@
+package dialog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// BudgetEntry is one limit shown by BudgetModel.
+type BudgetEntry struct {
+	Key      string // "session_limit", "daily_limit", "monthly_limit" or "per_call_limit"
+	Label    string
+	Spent    float64
+	HasSpend bool    // false for the per-call limit, which has no running spend
+	Limit    float64 // 0 when not set
+	Optional bool    // the limit can be removed
+}
+
+// BudgetLimit is emitted to change a limit, in USD. Limit is 0 to remove an
+// optional limit.
+type BudgetLimit struct {
+	Key   string
+	Limit float64
+}
+
+// BudgetModel is the spend budget dialog opened by /budget: spend against
+// each limit with a progress bar, and Enter to change a limit.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type BudgetModel struct {
+	entries   []BudgetEntry
+	cursor    int
+	editing   bool
+	editor    InputCursor
+	status    string
+	statusErr bool
+
+	width, height int
+}
+
+// NewBudget returns an empty BudgetModel.
+func NewBudget() BudgetModel {
+	return BudgetModel{}
+}
+
+// SetEntries populates the limits, keeping the cursor where it was.
+func (m *BudgetModel) SetEntries(entries []BudgetEntry) {
+	m.entries = entries
+	m.cursor = min(m.cursor, max(len(entries)-1, 0))
+}
+
+// SetStatus shows a one-line status message below the limits.
+func (m *BudgetModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Reset leaves any edit and clears the status before the dialog is opened.
+func (m *BudgetModel) Reset() {
+	m.editing = false
+	m.status = ""
+	m.statusErr = false
+}
+
+// Busy reports whether Esc is handled internally, cancelling an edit,
+// rather than closing the dialog.
+func (m BudgetModel) Busy() bool { return m.editing }
+
+// Editing reports whether a limit is being typed, so pastes go to it.
+func (m BudgetModel) Editing() bool { return m.editing }
+
+// SetSize updates terminal dimensions.
+func (m *BudgetModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Update handles keyboard input for the budget dialog.
+//
+//	↑/k, ↓/j → move cursor
+//	enter    → change the limit (enter saves, esc cancels)
+//	esc      → dismiss dialog (no action emitted)
+func (m BudgetModel) Update(message tea.Msg) (BudgetModel, tea.Cmd) {
+	if m.editing {
+		return m.updateEditor(message)
+	}
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		m.cursor = max(m.cursor-1, 0)
+	case tea.KeyDown, 'j':
+		m.cursor = min(m.cursor+1, max(len(m.entries)-1, 0))
+	case tea.KeyEnter:
+		if m.cursor < len(m.entries) {
+			m.editor = InputCursor{}
+			if e := m.entries[m.cursor]; e.Limit > 0 {
+				m.editor.SetValue(strconv.FormatFloat(e.Limit, 'f', -1, 64))
+			}
+			m.editing = true
+			m.status = ""
+		}
+	}
+	return m, nil
+}
+
+// updateEditor handles input while a limit is being typed.
+func (m BudgetModel) updateEditor(message tea.Msg) (BudgetModel, tea.Cmd) {
+	var text string
+	switch v := message.(type) {
+	case tea.PasteMsg:
+		text = v.Content
+	case tea.KeyPressMsg:
+		switch v.Code {
+		case tea.KeyEscape:
+			m.editing = false
+			return m, nil
+		case tea.KeyEnter:
+			if m.cursor >= len(m.entries) {
+				m.editing = false
+				return m, nil
+			}
+			e := m.entries[m.cursor]
+			value := strings.TrimPrefix(strings.TrimSpace(m.editor.Value), "$")
+			var limit float64
+			if value != "" || !e.Optional {
+				f, err := strconv.ParseFloat(value, 64)
+				if err != nil || f <= 0 {
+					m.SetStatus(i18n.T("Enter an amount in USD, like 5 or 2.50"), true)
+					return m, nil
+				}
+				limit = f
+			}
+			m.editing = false
+			if limit == e.Limit {
+				return m, nil
+			}
+			m.SetStatus(i18n.T("Saving..."), false)
+			a := BudgetLimit{Key: e.Key, Limit: limit}
+			return m, func() tea.Msg { return a }
+		case tea.KeyBackspace:
+			m.editor.Backspace()
+			return m, nil
+		case tea.KeyLeft:
+			m.editor.Cursor = max(m.editor.Cursor-1, 0)
+			return m, nil
+		case tea.KeyRight:
+			m.editor.Cursor = min(m.editor.Cursor+1, len([]rune(m.editor.Value)))
+			return m, nil
+		}
+		text = v.Text
+	default:
+		return m, nil
+	}
+	for _, r := range strings.TrimSpace(text) {
+		m.editor.Insert(r)
+	}
+	return m, nil
+}
+
+// View renders the budget dialog.
+func (m BudgetModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Budget")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	for i, e := range m.entries {
+		sb.WriteString(m.renderEntry(e, i == m.cursor, inner))
+		sb.WriteByte('\n')
+	}
+	if m.editing && m.cursor < len(m.entries) && m.entries[m.cursor].Optional {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("Enter an empty value to remove the limit")))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(style.Faint.Render(i18n.T("Limits set here last until the backend restarts")))
+	sb.WriteByte('\n')
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	help := []HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "enter", Desc: "set limit"},
+		{Key: "esc", Desc: "close"},
+	}
+	if m.editing {
+		help = []HelpItem{
+			{Key: "enter", Desc: "save"},
+			{Key: "esc", Desc: "cancel"},
+		}
+	}
+	sb.WriteString(RenderHelpBar(help, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderEntry renders a limit row: label, a bar of spend against the limit
+// and the amounts, or the editor while the limit is changed.
+func (m BudgetModel) renderEntry(e BudgetEntry, isCursor bool, width int) string {
+	cursor := "  "
+	label := fmt.Sprintf("%-10s", e.Label)
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+		label = style.DialogHelpKey.Render(label)
+	} else {
+		label = style.Faint.Render(label)
+	}
+	line := cursor + label + " "
+
+	if isCursor && m.editing {
+		ed := m.editor
+		ed.Focused = true
+		ed.Width = max(width-16, 10)
+		return line + "$ " + ed.View()
+	}
+
+	switch {
+	case !e.HasSpend && e.Limit > 0:
+		return line + style.Faint.Render(i18n.T("$%.2f per call", e.Limit))
+	case e.Limit <= 0:
+		return line + style.Faint.Render(i18n.T("no limit · $%.2f spent", e.Spent))
+	}
+	u := e.Spent / e.Limit
+	amounts := fmt.Sprintf(" %4d%%  %-19s", int(u*100), fmt.Sprintf("$%.2f / $%.2f", e.Spent, e.Limit))
+	barW := max(width-13-len(amounts), 5)
+	if u >= 1 {
+		amounts = style.ErrorText.Render(amounts)
+	} else {
+		amounts = style.Faint.Render(amounts)
+	}
+	return line + style.ContextBarRender(u, barW) + amounts
+}
@@ -0,0 +1,426 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// ChannelField is one setting of a channel shown by ChannelsModel.
+type ChannelField struct {
+	Key    string
+	Secret bool
+	Set    bool
+	Value  string // masked by the backend when Secret
+}
+
+// ChannelEntry is one messaging channel shown by ChannelsModel.
+type ChannelEntry struct {
+	Name       string
+	Connected  bool
+	Configured bool
+	Enabled    bool
+	Fields     []ChannelField
+}
+
+// ChannelAction is emitted for a channel action: "save" stores Value in
+// Field (an empty Value clears it), "enable", "disable" and "test" act on
+// the channel.
+type ChannelAction struct {
+	Action string
+	Name   string
+	Field  string
+	Value  string
+}
+
+// channelsMode is the level of the channels dialog.
+type channelsMode int
+
+const (
+	channelsList   channelsMode = iota // the channels
+	channelsFields                     // one channel's settings
+	channelsEdit                       // editing a setting
+)
+
+// ChannelsModel is the messaging channels dialog opened by /channels: every
+// channel with its state, and for the chosen one its settings. Secret
+// settings are typed masked and shown masked.
+//
+// Pressing Esc in the list emits nothing and the caller should dismiss the
+// dialog.
+type ChannelsModel struct {
+	channels  []ChannelEntry
+	cursor    int
+	offset    int
+	mode      channelsMode
+	field     int
+	editor    InputCursor
+	status    string
+	statusErr bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewChannels returns an empty ChannelsModel.
+func NewChannels() ChannelsModel {
+	return ChannelsModel{pageSize: 10}
+}
+
+// SetEntries populates the list, keeping the cursor on the same channel
+// when it is refreshed.
+func (m *ChannelsModel) SetEntries(channels []ChannelEntry) {
+	prev := ""
+	if m.cursor < len(m.channels) {
+		prev = m.channels[m.cursor].Name
+	}
+	m.channels = channels
+	m.cursor = min(m.cursor, max(len(channels)-1, 0))
+	for i, c := range channels {
+		if c.Name == prev {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// Replace updates a channel after an action.
+func (m *ChannelsModel) Replace(c ChannelEntry) {
+	for i := range m.channels {
+		if m.channels[i].Name == c.Name {
+			m.channels[i] = c
+		}
+	}
+}
+
+// SetStatus shows a one-line status message below the list.
+func (m *ChannelsModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Reset returns to the list and clears the status before the dialog is
+// opened.
+func (m *ChannelsModel) Reset() {
+	m.mode = channelsList
+	m.status = ""
+	m.statusErr = false
+}
+
+// Busy reports whether Esc is handled internally, stepping back from a
+// channel's settings or an edit, rather than closing the dialog.
+func (m ChannelsModel) Busy() bool { return m.mode != channelsList }
+
+// Editing reports whether a setting is being typed, so pastes go to it.
+func (m ChannelsModel) Editing() bool { return m.mode == channelsEdit }
+
+// SetSize updates terminal dimensions.
+func (m *ChannelsModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-22, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the channels dialog.
+//
+// In the list:
+//
+//	↑/k, ↓/j → move cursor
+//	enter    → show the channel's settings
+//	space/e  → enable or disable the channel
+//	t        → test the connection
+//	esc      → dismiss dialog (no action emitted)
+//
+// In a channel's settings:
+//
+//	↑/k, ↓/j → move between settings
+//	enter    → edit the setting (enter saves, an empty value clears it)
+//	space/e  → enable or disable the channel
+//	t        → test the connection
+//	esc      → back to the list
+func (m ChannelsModel) Update(message tea.Msg) (ChannelsModel, tea.Cmd) {
+	if m.mode == channelsEdit {
+		return m.updateEditor(message)
+	}
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok || m.cursor >= len(m.channels) {
+		return m, nil
+	}
+	ch := m.channels[m.cursor]
+
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		if m.mode == channelsFields {
+			m.field = max(m.field-1, 0)
+		} else if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown, 'j':
+		if m.mode == channelsFields {
+			m.field = min(m.field+1, max(len(ch.Fields)-1, 0))
+		} else if m.cursor < len(m.channels)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeyEnter:
+		switch {
+		case m.mode == channelsList:
+			m.mode = channelsFields
+			m.field = 0
+			m.status = ""
+		case m.field < len(ch.Fields):
+			m.editor = InputCursor{}
+			if !ch.Fields[m.field].Secret {
+				m.editor.SetValue(ch.Fields[m.field].Value)
+			}
+			m.mode = channelsEdit
+			m.status = ""
+		}
+	case tea.KeyEscape:
+		m.mode = channelsList
+		m.status = ""
+	case tea.KeySpace, 'e':
+		action := "enable"
+		if ch.Enabled {
+			action = "disable"
+		}
+		m.SetStatus(i18n.T("Working..."), false)
+		return m, func() tea.Msg { return ChannelAction{Action: action, Name: ch.Name} }
+	case 't':
+		m.SetStatus(i18n.T("Testing %s...", ch.Name), false)
+		return m, func() tea.Msg { return ChannelAction{Action: "test", Name: ch.Name} }
+	}
+	return m, nil
+}
+
+// updateEditor handles input while a setting is being typed.
+func (m ChannelsModel) updateEditor(message tea.Msg) (ChannelsModel, tea.Cmd) {
+	var text string
+	switch v := message.(type) {
+	case tea.PasteMsg:
+		text = v.Content
+	case tea.KeyPressMsg:
+		switch v.Code {
+		case tea.KeyEscape:
+			m.mode = channelsFields
+			return m, nil
+		case tea.KeyEnter:
+			m.mode = channelsFields
+			if m.cursor >= len(m.channels) || m.field >= len(m.channels[m.cursor].Fields) {
+				return m, nil
+			}
+			ch := m.channels[m.cursor]
+			a := ChannelAction{Action: "save", Name: ch.Name, Field: ch.Fields[m.field].Key, Value: strings.TrimSpace(m.editor.Value)}
+			if a.Value == "" && !ch.Fields[m.field].Set {
+				return m, nil
+			}
+			m.SetStatus(i18n.T("Saving..."), false)
+			return m, func() tea.Msg { return a }
+		case tea.KeyBackspace:
+			m.editor.Backspace()
+			return m, nil
+		case tea.KeyLeft:
+			m.editor.Cursor = max(m.editor.Cursor-1, 0)
+			return m, nil
+		case tea.KeyRight:
+			m.editor.Cursor = min(m.editor.Cursor+1, len([]rune(m.editor.Value)))
+			return m, nil
+		}
+		text = v.Text
+	default:
+		return m, nil
+	}
+	for _, r := range strings.TrimSpace(text) {
+		m.editor.Insert(r)
+	}
+	return m, nil
+}
+
+func (m *ChannelsModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the channels dialog.
+func (m ChannelsModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	var help []HelpItem
+	if m.mode == channelsList || m.cursor >= len(m.channels) {
+		sb.WriteString(GradientTitle(i18n.T("Channels")))
+		sb.WriteByte('\n')
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		m.viewList(&sb)
+		help = []HelpItem{
+			{Key: "↑↓", Desc: "navigate"},
+			{Key: "enter", Desc: "settings"},
+			{Key: "space", Desc: "enable/disable"},
+			{Key: "t", Desc: "test"},
+			{Key: "esc", Desc: "close"},
+		}
+	} else {
+		ch := m.channels[m.cursor]
+		sb.WriteString(GradientTitle(i18n.T("Channels · %s", ch.Name)))
+		sb.WriteByte('\n')
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		sb.WriteString(channelState(ch))
+		sb.WriteByte('\n')
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		m.viewFields(&sb, ch, inner)
+		if m.mode == channelsEdit {
+			help = []HelpItem{{Key: "enter", Desc: "save"}, {Key: "esc", Desc: "cancel"}}
+		} else {
+			help = []HelpItem{
+				{Key: "↑↓", Desc: "navigate"},
+				{Key: "enter", Desc: "edit"},
+				{Key: "space", Desc: "enable/disable"},
+				{Key: "t", Desc: "test"},
+				{Key: "esc", Desc: "back"},
+			}
+		}
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar(help, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+func (m ChannelsModel) viewList(sb *strings.Builder) {
+	if len(m.channels) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("The backend has no channel adapters")))
+		sb.WriteByte('\n')
+		return
+	}
+	end := min(m.offset+m.pageSize, len(m.channels))
+	if m.offset > 0 {
+		sb.WriteString(style.Faint.Render("  ↑ more above"))
+		sb.WriteByte('\n')
+	}
+	for i := m.offset; i < end; i++ {
+		ch := m.channels[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = style.PlanSelected.Render("> ")
+		}
+		name := fmt.Sprintf("%-12s", ch.Name)
+		if i == m.cursor {
+			name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+		} else {
+			name = style.Faint.Render(name)
+		}
+		sb.WriteString(cursor + name + " " + channelState(ch))
+		sb.WriteByte('\n')
+	}
+	if end < len(m.channels) {
+		sb.WriteString(style.Faint.Render("  ↓ more below"))
+		sb.WriteByte('\n')
+	}
+}
+
+func (m ChannelsModel) viewFields(sb *strings.Builder, ch ChannelEntry, inner int) {
+	if len(ch.Fields) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("This channel has no settings here")))
+		sb.WriteByte('\n')
+		return
+	}
+	for i, f := range ch.Fields {
+		cursor := "  "
+		if i == m.field {
+			cursor = style.PlanSelected.Render("> ")
+		}
+		label := fmt.Sprintf("%-16s", f.Key)
+		var value string
+		switch {
+		case i == m.field && m.mode == channelsEdit:
+			ed := m.editor
+			ed.Focused = true
+			ed.Width = max(inner-20, 10)
+			if f.Secret {
+				value = ed.MaskedView()
+			} else {
+				value = ed.View()
+			}
+		case !f.Set:
+			value = style.Faint.Render(i18n.T("not set"))
+		default:
+			value = ansi.Truncate(f.Value, max(inner-20, 10), "…")
+		}
+		if i == m.field {
+			label = style.DialogHelpKey.Render(label)
+		} else {
+			label = style.Faint.Render(label)
+		}
+		sb.WriteString(cursor + label + " " + value)
+		sb.WriteByte('\n')
+	}
+	if m.mode == channelsEdit && m.field < len(ch.Fields) && ch.Fields[m.field].Set {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("Enter an empty value to clear it")))
+		sb.WriteByte('\n')
+	}
+}
+
+// channelState describes a channel: enabled, and connected or configured.
+func channelState(ch ChannelEntry) string {
+	switch {
+	case !ch.Enabled:
+		return style.RadioOff.Render("○ ") + style.Faint.Render(i18n.T("disabled"))
+	case ch.Connected:
+		return style.MCPConnected.Render("● ") + style.MCPConnected.Render(i18n.T("connected"))
+	case ch.Configured:
+		return style.MCPError.Render("● ") + style.MCPError.Render(i18n.T("not connected"))
+	default:
+		return style.RadioOff.Render("○ ") + style.Faint.Render(i18n.T("not configured"))
+	}
+}
@@ -2,9 +2,11 @@ package dialog
 
 import (
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -16,7 +18,7 @@ type RenderContext struct {
 
 // GradientTitle renders a dialog title with the theme gradient coloring.
 func GradientTitle(title string) string {
-	return style.ApplyBoldForegroundGrad(title)
+	return style.ApplyBoldForegroundGrad(i18n.T(title))
 }
 
 // ButtonDef defines a single button in a dialog button group.
@@ -28,18 +30,38 @@ type ButtonDef struct {
 	Underline int  // index of char to underline as hotkey (-1 for none)
 }
 
+// translateLabel translates a button label and moves the hotkey underline to
+// the same letter in the translation, or drops it if the letter is absent.
+func translateLabel(label string, underline int) (string, int) {
+	tr := i18n.T(label)
+	if tr == label || underline < 0 {
+		return tr, underline
+	}
+	runes := []rune(label)
+	if underline >= len(runes) {
+		return tr, -1
+	}
+	hot := unicode.ToLower(runes[underline])
+	for i, r := range []rune(tr) {
+		if unicode.ToLower(r) == hot {
+			return tr, i
+		}
+	}
+	return tr, -1
+}
+
 // RenderButtons renders a horizontal button group centered within width.
 // Active button uses ButtonActive style; Danger uses ButtonDanger; others
 // use ButtonInactive.
 func RenderButtons(buttons []ButtonDef, width int) string {
 	var parts []string
 	for _, btn := range buttons {
-		label := btn.Label
-		if btn.Underline >= 0 && btn.Underline < utf8.RuneCountInString(label) {
+		label, underline := translateLabel(btn.Label, btn.Underline)
+		if underline >= 0 && underline < utf8.RuneCountInString(label) {
 			runes := []rune(label)
-			label = string(runes[:btn.Underline]) +
-				lipgloss.NewStyle().Underline(true).Render(string(runes[btn.Underline:btn.Underline+1])) +
-				string(runes[btn.Underline+1:])
+			label = string(runes[:underline]) +
+				lipgloss.NewStyle().Underline(true).Render(string(runes[underline:underline+1])) +
+				string(runes[underline+1:])
 		}
 
 		var rendered string
@@ -79,7 +101,7 @@ func RenderHelpBar(items []HelpItem, width int) string {
 	var parts []string
 	for _, item := range items {
 		k := style.DialogHelpKey.Render(item.Key)
-		d := style.DialogHelp.Render(" " + item.Desc)
+		d := style.DialogHelp.Render(" " + i18n.T(item.Desc))
 		parts = append(parts, k+d)
 	}
 
@@ -146,6 +168,28 @@ func (ic InputCursor) View() string {
 	return st.Render(line)
 }
 
+// MaskedView renders the value as bullets, revealing only the last four
+// characters, for secret inputs such as API keys.
+func (ic InputCursor) MaskedView() string {
+	runes := []rune(ic.Value)
+	n := len(runes)
+	if n == 0 {
+		if ic.Focused {
+			return style.ButtonActive.Render(" ")
+		}
+		return style.Faint.Render("(empty)")
+	}
+
+	masked := strings.Repeat("\u2022", n)
+	if n > 4 {
+		masked = strings.Repeat("\u2022", n-4) + string(runes[n-4:])
+	}
+	if ic.Focused {
+		return masked + style.ButtonActive.Render(" ")
+	}
+	return style.Faint.Render(masked)
+}
+
 // Insert inserts a rune at the current cursor position and advances the cursor.
 func (ic *InputCursor) Insert(ch rune) {
 	runes := []rune(ic.Value)
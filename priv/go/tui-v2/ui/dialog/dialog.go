@@ -138,7 +138,7 @@ func (o Overlay) View(behindContent string) string {
 	}
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(boxW).
@@ -177,7 +177,7 @@ func RenderDialog(title, content string, width, termW, termH int) string {
 	body := titleRendered + "\n\n" + content
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(boxWidth).
@@ -9,6 +9,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -217,7 +218,7 @@ func (m FilePickerModel) View() string {
 	var sb strings.Builder
 
 	// Title + current path.
-	sb.WriteString(GradientTitle("Select File"))
+	sb.WriteString(GradientTitle(i18n.T("Select File")))
 	sb.WriteByte('\n')
 	dirLabel := style.FilePath.Render(truncatePathLeft(m.currentDir, dw-8))
 	sb.WriteString(dirLabel)
@@ -226,10 +227,10 @@ func (m FilePickerModel) View() string {
 	sb.WriteByte('\n')
 
 	// Filter bar.
-	filterPrompt := style.DialogHelpKey.Render("Filter: ")
+	filterPrompt := style.DialogHelpKey.Render(i18n.T("Filter: "))
 	filterVal := m.filter
 	if filterVal == "" {
-		filterVal = style.Faint.Render("type to filter...")
+		filterVal = style.Faint.Render(i18n.T("type to filter..."))
 	} else {
 		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(filterVal)
 	}
@@ -240,7 +241,7 @@ func (m FilePickerModel) View() string {
 
 	// Entry list.
 	if len(m.filtered) == 0 {
-		sb.WriteString(style.Faint.Render("  No entries found"))
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No entries found")))
 		sb.WriteByte('\n')
 	} else {
 		end := m.offset + m.pageSize
@@ -249,7 +250,7 @@ func (m FilePickerModel) View() string {
 		}
 
 		if m.offset > 0 {
-			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
 			sb.WriteByte('\n')
 		}
 
@@ -261,7 +262,7 @@ func (m FilePickerModel) View() string {
 		}
 
 		if end < len(m.filtered) {
-			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
 			sb.WriteByte('\n')
 		}
 	}
@@ -278,7 +279,7 @@ func (m FilePickerModel) View() string {
 	sb.WriteString(RenderHelpBar(helpItems, dw-6))
 
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(dw)
@@ -0,0 +1,241 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// FormField is a labelled single-line input in a FormModel. The form is not
+// submitted while a Required field is empty or Validate, when set, rejects
+// the value of a field that is not; what is wrong shows below the field.
+// A Masked field shows its value as bullets, for secrets.
+type FormField struct {
+	Label    string
+	Value    string // initial value
+	Hint     string // shown below the field while it is focused
+	Required bool
+	Masked   bool
+	Validate func(string) error
+}
+
+// FormSubmit is emitted when the form is submitted. Values are in field order.
+type FormSubmit struct {
+	ID     string
+	Values []string
+}
+
+// FormCancel is emitted when the form is dismissed with Esc.
+type FormCancel struct {
+	ID string
+}
+
+// FormModel is a small dialog of labelled text inputs. ID is echoed in the
+// emitted FormSubmit / FormCancel so the caller can tell forms apart.
+type FormModel struct {
+	id     string
+	title  string
+	fields []FormField
+	inputs []InputCursor
+	errs   []string // what is wrong with each field, after a submit
+	focus  int
+
+	width, height int
+}
+
+// NewForm returns a form with the first field focused.
+func NewForm(id, title string, fields []FormField) FormModel {
+	m := FormModel{id: id, title: title, fields: fields, errs: make([]string, len(fields))}
+	m.inputs = make([]InputCursor, len(fields))
+	for i, f := range fields {
+		m.inputs[i].SetValue(f.Value)
+	}
+	m.setFocus(0)
+	return m
+}
+
+// SetSize updates terminal dimensions.
+func (m *FormModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Update handles keyboard input for the form.
+//
+//	tab/↓        → next field
+//	shift+tab/↑  → previous field
+//	enter        → next field, or submit on the last one
+//	ctrl+s       → submit
+//	esc          → cancel
+func (m FormModel) Update(message tea.Msg) (FormModel, tea.Cmd) {
+	var text string
+	switch v := message.(type) {
+	case tea.PasteMsg:
+		text = v.Content
+	case tea.KeyPressMsg:
+		switch {
+		case v.Code == tea.KeyEscape:
+			id := m.id
+			return m, func() tea.Msg { return FormCancel{ID: id} }
+		case v.String() == "ctrl+s":
+			cmd := m.submit()
+			return m, cmd
+		case v.Code == tea.KeyEnter:
+			if m.focus == len(m.inputs)-1 {
+				cmd := m.submit()
+				return m, cmd
+			}
+			m.setFocus(m.focus + 1)
+			return m, nil
+		case v.String() == "shift+tab" || v.Code == tea.KeyUp:
+			m.setFocus(m.focus - 1)
+			return m, nil
+		case v.Code == tea.KeyTab || v.Code == tea.KeyDown:
+			m.setFocus(m.focus + 1)
+			return m, nil
+		case v.Code == tea.KeyBackspace:
+			if m.focus < len(m.inputs) {
+				m.inputs[m.focus].Backspace()
+				m.errs[m.focus] = ""
+			}
+			return m, nil
+		}
+		text = v.Text
+	default:
+		return m, nil
+	}
+	if m.focus < len(m.inputs) {
+		// Single-line fields: pasted newlines become spaces.
+		for _, r := range strings.ReplaceAll(text, "\n", " ") {
+			m.inputs[m.focus].Insert(r)
+		}
+		m.errs[m.focus] = ""
+	}
+	return m, nil
+}
+
+func (m *FormModel) setFocus(i int) {
+	if len(m.inputs) == 0 {
+		return
+	}
+	m.focus = (i + len(m.inputs)) % len(m.inputs)
+	for j := range m.inputs {
+		m.inputs[j].Focused = j == m.focus
+	}
+}
+
+// submit emits the values, or focuses the first wrong field instead.
+func (m *FormModel) submit() tea.Cmd {
+	if !m.validate() {
+		return nil
+	}
+	id := m.id
+	values := make([]string, len(m.inputs))
+	for i, in := range m.inputs {
+		values[i] = strings.TrimSpace(in.Value)
+	}
+	return func() tea.Msg { return FormSubmit{ID: id, Values: values} }
+}
+
+// validate notes what is wrong with each field and reports whether all are
+// right.
+func (m *FormModel) validate() bool {
+	first := -1
+	for i, f := range m.fields {
+		v := strings.TrimSpace(m.inputs[i].Value)
+		m.errs[i] = ""
+		switch {
+		case v == "" && f.Required:
+			m.errs[i] = i18n.T("required")
+		case v != "" && f.Validate != nil:
+			if err := f.Validate(v); err != nil {
+				m.errs[i] = err.Error()
+			}
+		}
+		if m.errs[i] != "" && first < 0 {
+			first = i
+		}
+	}
+	if first < 0 {
+		return true
+	}
+	m.setFocus(first)
+	return false
+}
+
+// View renders the form dialog centered on screen.
+func (m FormModel) View() string {
+	dw := m.width - 4
+	if dw > 72 {
+		dw = 72
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	rule := style.DiffContext.Render(strings.Repeat("─", dw-6))
+
+	labelW := 0
+	for _, f := range m.fields {
+		labelW = max(labelW, lipgloss.Width(f.Label))
+	}
+	labelW = min(labelW, (dw-6)/2)
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(m.title))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	for i, f := range m.fields {
+		label := lipgloss.NewStyle().Width(labelW).Render(f.Label)
+		if i == m.focus {
+			label = style.DialogHelpKey.Render(label)
+		} else {
+			label = style.Faint.Render(label)
+		}
+		in := m.inputs[i]
+		in.Width = dw - 6 - labelW - 2
+		if f.Masked {
+			sb.WriteString(label + "  " + in.MaskedView())
+		} else {
+			sb.WriteString(label + "  " + in.View())
+		}
+		sb.WriteByte('\n')
+		below := lipgloss.NewStyle().PaddingLeft(labelW + 2).Width(dw - 6)
+		switch {
+		case m.errs[i] != "":
+			sb.WriteString(below.Render(style.ErrorText.Render(m.errs[i])) + "\n")
+		case i == m.focus && f.Hint != "":
+			sb.WriteString(below.Render(style.Faint.Render(f.Hint)) + "\n")
+		}
+	}
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	submit := "next"
+	if m.focus == len(m.fields)-1 {
+		submit = "submit"
+	}
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "tab", Desc: "next field"},
+		{Key: "enter", Desc: submit},
+		{Key: "esc", Desc: "cancel"},
+	}, dw-6))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
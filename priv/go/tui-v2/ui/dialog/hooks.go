@@ -0,0 +1,293 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// HookEntry is one hook shown by HooksModel.
+type HookEntry struct {
+	Name      string
+	Event     string
+	Priority  int
+	Enabled   bool
+	Protected bool // a guardrail the backend keeps on
+}
+
+// HookBlockEntry is an action a hook blocked.
+type HookBlockEntry struct {
+	Hook   string
+	Event  string
+	Reason string
+	At     time.Time // zero when the backend did not say
+}
+
+// HookToggle is emitted when the user enables or disables a hook.
+type HookToggle struct {
+	Name    string
+	Enabled bool // new state
+}
+
+// maxHookBlocks is how many recent blocks the hooks view keeps.
+const maxHookBlocks = 20
+
+// HooksModel is the hook pipeline view opened by /hooks: the registered
+// hooks with their state, and below them the actions hooks blocked most
+// recently, with the reasons.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type HooksModel struct {
+	hooks     []HookEntry
+	blocks    []HookBlockEntry // newest first
+	cursor    int
+	offset    int
+	status    string
+	statusErr bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewHooks returns an empty HooksModel.
+func NewHooks() HooksModel {
+	return HooksModel{pageSize: 10}
+}
+
+// SetEntries populates the view, keeping the cursor on the same hook when
+// it is refreshed.
+func (m *HooksModel) SetEntries(hooks []HookEntry, blocks []HookBlockEntry) {
+	prev := ""
+	if m.cursor < len(m.hooks) {
+		prev = m.hooks[m.cursor].Name
+	}
+	m.hooks = hooks
+	m.blocks = blocks
+	m.cursor = min(m.cursor, max(len(hooks)-1, 0))
+	for i, h := range hooks {
+		if h.Name == prev {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// AddBlock records a block reported while the view is open.
+func (m *HooksModel) AddBlock(b HookBlockEntry) {
+	m.blocks = append([]HookBlockEntry{b}, m.blocks...)
+	if len(m.blocks) > maxHookBlocks {
+		m.blocks = m.blocks[:maxHookBlocks]
+	}
+}
+
+// SetEnabled updates the state of the named hook after a toggle. A hook
+// registered for several events has a row for each.
+func (m *HooksModel) SetEnabled(name string, enabled bool) {
+	for i := range m.hooks {
+		if m.hooks[i].Name == name {
+			m.hooks[i].Enabled = enabled
+		}
+	}
+}
+
+// SetStatus shows a one-line status message below the list.
+func (m *HooksModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Reset clears the status before the view is opened.
+func (m *HooksModel) Reset() {
+	m.status = ""
+	m.statusErr = false
+}
+
+// SetSize updates terminal dimensions.
+func (m *HooksModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max((h-18)/2, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the hooks view.
+//
+//	↑/k, ↓/j → move cursor
+//	space/e  → enable or disable the selected hook
+//	esc      → dismiss dialog (no action emitted)
+func (m HooksModel) Update(message tea.Msg) (HooksModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown, 'j':
+		if m.cursor < len(m.hooks)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeySpace, 'e':
+		if m.cursor >= len(m.hooks) {
+			break
+		}
+		h := m.hooks[m.cursor]
+		if h.Protected {
+			m.SetStatus(i18n.T("%s is a guardrail and always runs", h.Name), true)
+			break
+		}
+		enable := !h.Enabled
+		return m, func() tea.Msg { return HookToggle{Name: h.Name, Enabled: enable} }
+	}
+	return m, nil
+}
+
+func (m *HooksModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the hooks view.
+func (m HooksModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Hooks")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.hooks) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No hooks registered")))
+		sb.WriteByte('\n')
+	} else {
+		end := min(m.offset+m.pageSize, len(m.hooks))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderHook(m.hooks[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.hooks) {
+			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteByte('\n')
+		}
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(style.Bold.Render(i18n.T("Recent blocks")))
+	sb.WriteByte('\n')
+	if len(m.blocks) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("Nothing blocked yet")))
+		sb.WriteByte('\n')
+	}
+	for _, b := range m.blocks[:min(len(m.blocks), max(m.pageSize/2, 3))] {
+		sb.WriteString(m.renderBlock(b, inner))
+		sb.WriteByte('\n')
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "space", Desc: "enable/disable"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderHook renders a single hook row: state, name, event and priority.
+func (m HooksModel) renderHook(h HookEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+
+	var mark, state string
+	switch {
+	case h.Protected:
+		mark = style.RadioOn.Render("● ")
+		state = style.Faint.Render(i18n.T("guardrail"))
+	case h.Enabled:
+		mark = style.RadioOn.Render("● ")
+	default:
+		mark = style.RadioOff.Render("○ ")
+		state = style.Faint.Render(i18n.T("disabled"))
+	}
+
+	nameW := min(26, width/3)
+	name := fmt.Sprintf("%-*s", nameW, ansi.Truncate(h.Name, nameW, "…"))
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+	event := fmt.Sprintf("%-15s", h.Event)
+	prio := fmt.Sprintf("p%-3d", h.Priority)
+
+	return cursor + mark + name + " " + style.Faint.Render(event) + " " + style.Faint.Render(prio) + " " + state
+}
+
+// renderBlock renders a recent block: time, hook and reason.
+func (m HooksModel) renderBlock(b HookBlockEntry, width int) string {
+	at := "        "
+	if !b.At.IsZero() {
+		at = b.At.Format("15:04:05")
+	}
+	head := at + " " + b.Hook + ": "
+	reason := ansi.Truncate(strings.ReplaceAll(b.Reason, "\n", " "), max(width-2-ansi.StringWidth(head), 8), "…")
+	return "  " + style.Faint.Render(at) + " " + style.ErrorText.Render(b.Hook+": ") + reason
+}
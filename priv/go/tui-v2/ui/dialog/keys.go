@@ -0,0 +1,441 @@
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/msg"
+	"github.com/miosa/osa-tui/style"
+)
+
+// keyPrefixes lists the well-known key prefixes used to catch pasting a key
+// into the wrong provider. Providers not listed accept any non-blank key.
+var keyPrefixes = map[string][]string{
+	"anthropic":  {"sk-ant-"},
+	"openai":     {"sk-"},
+	"openrouter": {"sk-or-"},
+	"groq":       {"gsk_"},
+	"xai":        {"xai-"},
+}
+
+// minKeyLength rejects obviously truncated pastes.
+const minKeyLength = 8
+
+// KeysModel is the provider API key manager opened by /keys.
+//
+// Emits KeyAction messages for set / remove. Pressing Esc outside of an edit
+// or confirmation emits nothing and the caller should dismiss the dialog.
+type KeysModel struct {
+	keys       []msg.ProviderKey
+	cursor     int
+	offset     int
+	editing    bool
+	keyInput   InputCursor
+	delConfirm bool
+	pending    string // provider with a request in flight
+	status     string
+	statusErr  bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewKeys returns a zero-value KeysModel.
+func NewKeys() KeysModel {
+	return KeysModel{pageSize: 18}
+}
+
+// SetKeys populates the dialog, keeping the cursor on the same provider when
+// the list is refreshed after an update.
+func (m *KeysModel) SetKeys(keys []msg.ProviderKey) {
+	prev := ""
+	if m.cursor < len(m.keys) {
+		prev = m.keys[m.cursor].Provider
+	}
+	m.keys = keys
+	m.cursor = 0
+	for i, k := range keys {
+		if k.Provider == prev {
+			m.cursor = i
+			break
+		}
+	}
+	m.pending = ""
+	m.scrollToCursor()
+}
+
+// SetResult reports the outcome of a KeyAction.
+func (m *KeysModel) SetResult(provider string, removed bool, err error) {
+	m.pending = ""
+	if err != nil {
+		m.status = fmt.Sprintf("%s: %v", provider, err)
+		m.statusErr = true
+		return
+	}
+	if removed {
+		m.status = i18n.T("Removed key for %s", provider)
+	} else {
+		m.status = i18n.T("Saved key for %s", provider)
+	}
+	m.statusErr = false
+}
+
+// Reset clears transient edit and status state before the dialog is opened.
+func (m *KeysModel) Reset() {
+	m.editing = false
+	m.delConfirm = false
+	m.pending = ""
+	m.status = ""
+	m.statusErr = false
+}
+
+// Busy reports whether the dialog is mid-edit or confirmation, in which case
+// Esc is handled internally rather than closing the dialog.
+func (m KeysModel) Busy() bool {
+	return m.editing || m.delConfirm
+}
+
+// SetSize updates terminal dimensions.
+func (m *KeysModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = h - 14
+	if m.pageSize < 4 {
+		m.pageSize = 4
+	}
+	m.scrollToCursor()
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Update
+// ──────────────────────────────────────────────────────────────────────────────
+
+// Update handles keyboard input for the key manager.
+//
+// Normal mode:
+//
+//	↑/k        → move cursor up
+//	↓/j        → move cursor down
+//	enter/a/e  → add or rotate the key for the selected provider
+//	d/delete   → prompt remove confirmation
+//	esc        → dismiss dialog (no action emitted)
+//
+// Edit mode:
+//
+//	enter → validate and save
+//	esc   → cancel edit
+//	char  → edit key input (masked)
+//
+// Remove confirmation mode:
+//
+//	y / enter → confirm remove
+//	any key   → cancel
+func (m KeysModel) Update(message tea.Msg) (KeysModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing {
+		return m.updateEditing(kp)
+	}
+	if m.delConfirm {
+		return m.updateDeleteConfirm(kp)
+	}
+	return m.updateNormal(kp)
+}
+
+func (m KeysModel) updateEditing(kp tea.KeyPressMsg) (KeysModel, tea.Cmd) {
+	switch kp.Code {
+	case tea.KeyEnter:
+		if m.cursor >= len(m.keys) {
+			m.editing = false
+			return m, nil
+		}
+		entry := m.keys[m.cursor]
+		apiKey := strings.TrimSpace(m.keyInput.Value)
+		if err := validateKey(entry.Provider, apiKey); err != nil {
+			m.status = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		m.editing = false
+		m.pending = entry.Provider
+		m.status = i18n.T("Validating key for %s...", entry.Name)
+		m.statusErr = false
+		provider := entry.Provider
+		return m, func() tea.Msg {
+			return KeyAction{Action: "set", Provider: provider, APIKey: apiKey}
+		}
+
+	case tea.KeyEscape:
+		m.editing = false
+		m.status = ""
+		return m, nil
+
+	case tea.KeyBackspace:
+		m.keyInput.Backspace()
+		return m, nil
+
+	default:
+		// Pasted keys arrive as a single text event.
+		for _, r := range kp.Text {
+			m.keyInput.Insert(r)
+		}
+		return m, nil
+	}
+}
+
+func (m KeysModel) updateDeleteConfirm(kp tea.KeyPressMsg) (KeysModel, tea.Cmd) {
+	m.delConfirm = false
+	if kp.Code != 'y' && kp.Code != tea.KeyEnter {
+		return m, nil
+	}
+	if m.cursor >= len(m.keys) {
+		return m, nil
+	}
+	provider := m.keys[m.cursor].Provider
+	m.pending = provider
+	return m, func() tea.Msg {
+		return KeyAction{Action: "remove", Provider: provider}
+	}
+}
+
+func (m KeysModel) updateNormal(kp tea.KeyPressMsg) (KeysModel, tea.Cmd) {
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+		return m, nil
+
+	case tea.KeyDown, 'j':
+		if m.cursor < len(m.keys)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+		return m, nil
+
+	case tea.KeyEnter, 'a', 'e':
+		if m.cursor < len(m.keys) && m.pending == "" {
+			if m.keys[m.cursor].EnvVar == "" {
+				m.status = i18n.T("%s does not use an API key", m.keys[m.cursor].Name)
+				m.statusErr = false
+				return m, nil
+			}
+			m.editing = true
+			m.keyInput = InputCursor{Focused: true}
+			m.status = ""
+		}
+		return m, nil
+
+	case 'd', tea.KeyDelete:
+		if m.cursor >= len(m.keys) || m.pending != "" {
+			return m, nil
+		}
+		entry := m.keys[m.cursor]
+		switch {
+		case !entry.Configured:
+			m.status = i18n.T("%s has no key to remove", entry.Name)
+			m.statusErr = false
+		case entry.Source == "env":
+			m.status = i18n.T("Key comes from $%s — unset it in your shell", entry.EnvVar)
+			m.statusErr = true
+		default:
+			m.delConfirm = true
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *KeysModel) scrollToCursor() {
+	if m.pageSize <= 0 {
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// validateKey performs local sanity checks before a key is sent anywhere.
+func validateKey(provider, apiKey string) error {
+	if apiKey == "" {
+		return errors.New(i18n.T("key is empty"))
+	}
+	if strings.IndexFunc(apiKey, unicode.IsSpace) >= 0 {
+		return errors.New(i18n.T("key must not contain spaces"))
+	}
+	if len(apiKey) < minKeyLength {
+		return errors.New(i18n.T("key is too short"))
+	}
+	if prefixes, ok := keyPrefixes[provider]; ok {
+		for _, p := range prefixes {
+			if strings.HasPrefix(apiKey, p) {
+				return nil
+			}
+		}
+		return errors.New(i18n.T("%s keys start with %q", provider, prefixes[0]))
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// View
+// ──────────────────────────────────────────────────────────────────────────────
+
+// View renders the key manager dialog.
+func (m KeysModel) View() string {
+	dw := m.width - 4
+	if dw > 80 {
+		dw = 80
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	rule := style.DiffContext.Render(strings.Repeat("─", dw-6))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Provider Keys")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.keys) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No providers reported by backend")))
+		sb.WriteByte('\n')
+	} else {
+		end := m.offset + m.pageSize
+		if end > len(m.keys) {
+			end = len(m.keys)
+		}
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.keys[i], i == m.cursor))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.keys) {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.editing && m.cursor < len(m.keys) {
+		entry := m.keys[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		sb.WriteString(style.DialogHelpKey.Render(i18n.T("%s key: ", entry.Name)) + m.keyInput.MaskedView())
+		sb.WriteByte('\n')
+	}
+
+	if m.delConfirm && m.cursor < len(m.keys) {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		sb.WriteString(style.ErrorText.Render(i18n.T("Remove key for %s? ", m.keys[m.cursor].Name)))
+		sb.WriteString(style.DialogHelp.Render(i18n.T("y to confirm · any key to cancel")))
+		sb.WriteByte('\n')
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	var helpItems []HelpItem
+	if m.editing {
+		helpItems = []HelpItem{
+			{Key: "enter", Desc: "save"},
+			{Key: "esc", Desc: "cancel"},
+		}
+	} else {
+		helpItems = []HelpItem{
+			{Key: "↑↓", Desc: "navigate"},
+			{Key: "enter", Desc: "add/rotate"},
+			{Key: "d", Desc: "remove"},
+			{Key: "esc", Desc: "close"},
+		}
+	}
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar(helpItems, dw-6))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+
+	box := frameStyle.Render(sb.String())
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderEntry renders a single provider row.
+func (m KeysModel) renderEntry(entry msg.ProviderKey, isCursor bool) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+
+	var mark string
+	switch {
+	case entry.EnvVar == "":
+		mark = style.Faint.Render("– ")
+	case entry.Configured:
+		mark = style.RadioOn.Render("● ")
+	default:
+		mark = style.RadioOff.Render("○ ")
+	}
+
+	name := fmt.Sprintf("%-14s", entry.Name)
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+
+	var detail string
+	switch {
+	case entry.Provider == m.pending:
+		detail = style.Faint.Render(i18n.T("working..."))
+	case entry.EnvVar == "":
+		detail = style.Faint.Render(i18n.T("no key needed"))
+	case entry.Configured:
+		detail = entry.Masked
+		if entry.Source == "env" {
+			detail += style.Faint.Render("  ($" + entry.EnvVar + ")")
+		}
+	default:
+		detail = style.Faint.Render(i18n.T("not set") + "  " + entry.EnvVar)
+	}
+
+	return cursor + mark + name + " " + detail
+}
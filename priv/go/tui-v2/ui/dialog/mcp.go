@@ -0,0 +1,298 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// MCPServerEntry is one MCP server shown by MCPModel.
+type MCPServerEntry struct {
+	Name   string
+	Target string // command line or URL
+	State  string // "connected", "connecting" or "error"
+	Error  string
+	Tools  []string
+}
+
+// MCPAction is emitted for a server action: "add" (Name is empty),
+// "remove" or "restart".
+type MCPAction struct {
+	Action string
+	Name   string
+}
+
+// MCPModel is the MCP server manager opened by /mcp: the configured servers
+// with their state, and the command, error and tools of the server under
+// the cursor below them.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type MCPModel struct {
+	servers    []MCPServerEntry
+	cursor     int
+	offset     int
+	delConfirm bool
+	status     string
+	statusErr  bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewMCP returns an empty MCPModel.
+func NewMCP() MCPModel {
+	return MCPModel{pageSize: 8}
+}
+
+// SetEntries populates the list, keeping the cursor on the same server when
+// it is refreshed.
+func (m *MCPModel) SetEntries(servers []MCPServerEntry) {
+	prev := ""
+	if m.cursor < len(m.servers) {
+		prev = m.servers[m.cursor].Name
+	}
+	m.servers = servers
+	m.cursor = min(m.cursor, max(len(servers)-1, 0))
+	for i, s := range servers {
+		if s.Name == prev {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// SetStatus shows a one-line status message below the list.
+func (m *MCPModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Reset clears the status and any pending confirmation before the dialog
+// is opened.
+func (m *MCPModel) Reset() {
+	m.status = ""
+	m.statusErr = false
+	m.delConfirm = false
+}
+
+// Busy reports whether Esc is handled internally, cancelling a removal,
+// rather than closing the dialog.
+func (m MCPModel) Busy() bool { return m.delConfirm }
+
+// SetSize updates terminal dimensions.
+func (m *MCPModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max((h-20)/2, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the server list.
+//
+//	↑/k, ↓/j → move cursor
+//	a        → add a server
+//	d        → remove the selected server (y / enter confirms)
+//	r        → restart the selected server
+//	esc      → dismiss dialog (no action emitted)
+func (m MCPModel) Update(message tea.Msg) (MCPModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.delConfirm {
+		m.delConfirm = false
+		if (kp.Code != 'y' && kp.Code != tea.KeyEnter) || m.cursor >= len(m.servers) {
+			return m, nil
+		}
+		name := m.servers[m.cursor].Name
+		m.SetStatus(i18n.T("Removing %s...", name), false)
+		return m, func() tea.Msg { return MCPAction{Action: "remove", Name: name} }
+	}
+
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown, 'j':
+		if m.cursor < len(m.servers)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case 'a':
+		m.status = ""
+		return m, func() tea.Msg { return MCPAction{Action: "add"} }
+	case 'd', tea.KeyDelete:
+		if m.cursor < len(m.servers) {
+			m.delConfirm = true
+			m.status = ""
+		}
+	case 'r':
+		if m.cursor < len(m.servers) {
+			name := m.servers[m.cursor].Name
+			m.SetStatus(i18n.T("Restarting %s...", name), false)
+			return m, func() tea.Msg { return MCPAction{Action: "restart", Name: name} }
+		}
+	}
+	return m, nil
+}
+
+func (m *MCPModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the server manager.
+func (m MCPModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("MCP servers")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.servers) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No MCP servers configured — press a to add one")))
+		sb.WriteByte('\n')
+	} else {
+		end := min(m.offset+m.pageSize, len(m.servers))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderServer(m.servers[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.servers) {
+			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.cursor < len(m.servers) {
+		s := m.servers[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		sb.WriteString(style.DialogHelpKey.Render(i18n.T("Runs: ")) + ansi.Truncate(s.Target, max(inner-6, 8), "…"))
+		sb.WriteByte('\n')
+		if s.Error != "" {
+			sb.WriteString(style.ErrorText.Render(lipgloss.NewStyle().Width(inner).Render(s.Error)))
+			sb.WriteByte('\n')
+		}
+		if len(s.Tools) == 0 {
+			sb.WriteString(style.Faint.Render(i18n.T("No tools registered")))
+			sb.WriteByte('\n')
+		} else {
+			sb.WriteString(style.Bold.Render(i18n.T("Tools")))
+			sb.WriteByte('\n')
+			shown := min(len(s.Tools), max(m.pageSize/2, 3))
+			for _, t := range s.Tools[:shown] {
+				sb.WriteString("  " + ansi.Truncate(t, inner-2, "…"))
+				sb.WriteByte('\n')
+			}
+			if shown < len(s.Tools) {
+				sb.WriteString(style.Faint.Render("  " + i18n.T("… and %d more", len(s.Tools)-shown)))
+				sb.WriteByte('\n')
+			}
+		}
+		if m.delConfirm {
+			sb.WriteString(rule)
+			sb.WriteByte('\n')
+			sb.WriteString(style.ErrorText.Render(i18n.T("Remove %s? ", s.Name)))
+			sb.WriteString(style.DialogHelp.Render(i18n.T("y to confirm · any key to cancel")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "a", Desc: "add"},
+		{Key: "d", Desc: "remove"},
+		{Key: "r", Desc: "restart"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderServer renders a single server row: state, name, tool count and
+// the state in words.
+func (m MCPModel) renderServer(s MCPServerEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+
+	var mark, state string
+	switch s.State {
+	case "connected":
+		mark = style.MCPConnected.Render("● ")
+		state = style.MCPConnected.Render(i18n.T("connected"))
+	case "error":
+		mark = style.MCPError.Render("● ")
+		state = style.MCPError.Render(i18n.T("error"))
+	default:
+		mark = style.LSPStarting.Render("◐ ")
+		state = style.LSPStarting.Render(i18n.T("connecting"))
+	}
+
+	nameW := min(26, width/3)
+	name := fmt.Sprintf("%-*s", nameW, ansi.Truncate(s.Name, nameW, "…"))
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+	tools := fmt.Sprintf("%-9s", i18n.T("%d tools", len(s.Tools)))
+
+	return cursor + mark + name + " " + style.Faint.Render(tools) + " " + state
+}
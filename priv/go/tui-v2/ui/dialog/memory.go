@@ -0,0 +1,441 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// MemoryEntry is one stored memory shown by MemoryModel.
+type MemoryEntry struct {
+	ID        string
+	Category  string
+	Timestamp string
+	Content   string
+	Pinned    bool
+}
+
+// MemoryAction is emitted by the memory browser.
+// Action is one of: "edit", "delete", "pin".
+type MemoryAction struct {
+	Action  string
+	ID      string
+	Content string // new text, only for "edit"
+	Pinned  bool   // new state, only for "pin"
+}
+
+// memoryMode is the screen the memory browser shows.
+type memoryMode int
+
+const (
+	memoryList   memoryMode = iota // searchable list
+	memoryDetail                   // one entry in full
+	memoryEdit                     // the entry's text in an editor
+)
+
+// MemoryModel is the memory browser opened by /memory. The list is filtered
+// as the user types; Enter shows an entry in full, where it can be edited,
+// deleted or pinned.
+//
+// Pressing Esc on the list emits nothing and the caller should dismiss the
+// dialog.
+type MemoryModel struct {
+	entries    []MemoryEntry
+	filtered   []MemoryEntry
+	cursor     int
+	offset     int
+	filterText string
+	mode       memoryMode
+	editor     InputCursor
+	delConfirm bool
+	status     string
+	statusErr  bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewMemory returns an empty MemoryModel.
+func NewMemory() MemoryModel {
+	return MemoryModel{pageSize: 10}
+}
+
+// SetEntries populates the browser and returns it to the list, with the
+// filter cleared.
+func (m *MemoryModel) SetEntries(entries []MemoryEntry) {
+	m.entries = entries
+	m.filterText = ""
+	m.mode = memoryList
+	m.delConfirm = false
+	m.status = ""
+	m.statusErr = false
+	m.applyFilter()
+}
+
+// Replace swaps the entry with ID id for e, after an edit or a pin. Editing
+// gives the entry a new ID, so the old one is looked up.
+func (m *MemoryModel) Replace(id string, e MemoryEntry) {
+	for i := range m.entries {
+		if m.entries[i].ID == id {
+			m.entries[i] = e
+		}
+	}
+	for i := range m.filtered {
+		if m.filtered[i].ID == id {
+			m.filtered[i] = e
+		}
+	}
+}
+
+// Remove drops the entry with ID id and returns to the list.
+func (m *MemoryModel) Remove(id string) {
+	drop := func(entries []MemoryEntry) []MemoryEntry {
+		out := entries[:0]
+		for _, e := range entries {
+			if e.ID != id {
+				out = append(out, e)
+			}
+		}
+		return out
+	}
+	m.entries = drop(m.entries)
+	m.filtered = drop(m.filtered)
+	m.cursor = min(m.cursor, max(len(m.filtered)-1, 0))
+	m.mode = memoryList
+	m.scrollToCursor()
+}
+
+// SetStatus shows a one-line status message at the bottom of the dialog.
+func (m *MemoryModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Busy reports whether Esc is handled internally, stepping back from an
+// entry, the editor or a confirmation, rather than closing the dialog.
+func (m MemoryModel) Busy() bool { return m.mode != memoryList || m.delConfirm }
+
+// Editing reports whether the editor is open, so pasted text is routed to
+// the dialog.
+func (m MemoryModel) Editing() bool { return m.mode == memoryEdit }
+
+// SetSize updates terminal dimensions.
+func (m *MemoryModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-20, 4)
+	m.scrollToCursor()
+}
+
+// Update handles input for the memory browser.
+//
+// On the list:
+//
+//	↑/↓       → move cursor
+//	enter     → show the selected entry
+//	esc       → dismiss dialog (no action emitted)
+//	any char  → append to filter
+//	backspace → remove last filter char
+//
+// On an entry:
+//
+//	e → edit the text (enter saves, esc cancels)
+//	d → delete (y / enter confirms)
+//	p → pin or unpin
+//	esc → back to the list
+func (m MemoryModel) Update(message tea.Msg) (MemoryModel, tea.Cmd) {
+	if m.mode == memoryEdit {
+		return m.updateEditor(message)
+	}
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.mode == memoryDetail {
+		return m.updateDetail(kp)
+	}
+
+	switch kp.Code {
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeyEnter:
+		if m.cursor < len(m.filtered) {
+			m.mode = memoryDetail
+			m.status = ""
+		}
+	case tea.KeyBackspace:
+		if m.filterText != "" {
+			runes := []rune(m.filterText)
+			m.filterText = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	default:
+		if kp.Code >= 32 && kp.Code != tea.KeyDelete && kp.Code < 127 {
+			m.filterText += string(rune(kp.Code))
+			m.applyFilter()
+		}
+	}
+	return m, nil
+}
+
+// updateDetail handles keys while an entry is shown in full.
+func (m MemoryModel) updateDetail(kp tea.KeyPressMsg) (MemoryModel, tea.Cmd) {
+	if m.cursor >= len(m.filtered) {
+		m.mode = memoryList
+		return m, nil
+	}
+	e := m.filtered[m.cursor]
+	if m.delConfirm {
+		m.delConfirm = false
+		if kp.Code != 'y' && kp.Code != tea.KeyEnter {
+			return m, nil
+		}
+		m.status = i18n.T("Deleting...")
+		m.statusErr = false
+		return m, func() tea.Msg { return MemoryAction{Action: "delete", ID: e.ID} }
+	}
+
+	switch kp.Code {
+	case tea.KeyEscape:
+		m.mode = memoryList
+		m.status = ""
+	case 'e':
+		m.editor = InputCursor{}
+		m.editor.SetValue(e.Content)
+		m.mode = memoryEdit
+		m.status = ""
+	case 'd', tea.KeyDelete:
+		m.delConfirm = true
+		m.status = ""
+	case 'p':
+		pinned := !e.Pinned
+		return m, func() tea.Msg { return MemoryAction{Action: "pin", ID: e.ID, Pinned: pinned} }
+	}
+	return m, nil
+}
+
+// updateEditor handles input while the entry's text is being edited.
+func (m MemoryModel) updateEditor(message tea.Msg) (MemoryModel, tea.Cmd) {
+	var text string
+	switch v := message.(type) {
+	case tea.PasteMsg:
+		text = v.Content
+	case tea.KeyPressMsg:
+		switch v.Code {
+		case tea.KeyEscape:
+			m.mode = memoryDetail
+			return m, nil
+		case tea.KeyEnter:
+			content := strings.TrimSpace(m.editor.Value)
+			if content == "" {
+				m.SetStatus(i18n.T("A memory cannot be empty; press d to delete it"), true)
+				return m, nil
+			}
+			m.mode = memoryDetail
+			if m.cursor >= len(m.filtered) || content == m.filtered[m.cursor].Content {
+				return m, nil
+			}
+			id := m.filtered[m.cursor].ID
+			m.status = i18n.T("Saving...")
+			m.statusErr = false
+			return m, func() tea.Msg { return MemoryAction{Action: "edit", ID: id, Content: content} }
+		case tea.KeyBackspace:
+			m.editor.Backspace()
+			return m, nil
+		case tea.KeyLeft:
+			m.editor.Cursor = max(m.editor.Cursor-1, 0)
+			return m, nil
+		case tea.KeyRight:
+			m.editor.Cursor = min(m.editor.Cursor+1, len([]rune(m.editor.Value)))
+			return m, nil
+		}
+		text = v.Text
+	default:
+		return m, nil
+	}
+	for _, r := range strings.ReplaceAll(text, "\n", " ") {
+		m.editor.Insert(r)
+	}
+	return m, nil
+}
+
+// applyFilter keeps the entries whose category or text contain the filter
+// text.
+func (m *MemoryModel) applyFilter() {
+	m.filtered = m.filtered[:0]
+	q := strings.ToLower(m.filterText)
+	for _, e := range m.entries {
+		if strings.Contains(strings.ToLower(e.Category+" "+e.Content), q) {
+			m.filtered = append(m.filtered, e)
+		}
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *MemoryModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the memory browser.
+func (m MemoryModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Memory")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	var help []HelpItem
+	if m.mode == memoryList || m.cursor >= len(m.filtered) {
+		m.viewList(&sb, inner, rule)
+		help = []HelpItem{{Key: "↑↓", Desc: "navigate"}, {Key: "enter", Desc: "view"}, {Key: "esc", Desc: "close"}}
+	} else {
+		e := m.filtered[m.cursor]
+		meta := e.Category + " · " + e.Timestamp
+		if e.Pinned {
+			meta += " · " + i18n.T("pinned")
+		}
+		sb.WriteString(style.DialogHelpKey.Render(meta))
+		sb.WriteString("\n\n")
+		if m.mode == memoryEdit {
+			ed := m.editor
+			ed.Focused = true
+			ed.Width = inner
+			sb.WriteString(ed.View())
+			help = []HelpItem{{Key: "enter", Desc: "save"}, {Key: "esc", Desc: "cancel"}}
+		} else {
+			sb.WriteString(lipgloss.NewStyle().Width(inner).Render(e.Content))
+			pin := "pin"
+			if e.Pinned {
+				pin = "unpin"
+			}
+			help = []HelpItem{{Key: "e", Desc: "edit"}, {Key: "d", Desc: "delete"}, {Key: "p", Desc: pin}, {Key: "esc", Desc: "back"}}
+		}
+		sb.WriteByte('\n')
+		if m.delConfirm {
+			sb.WriteString(rule)
+			sb.WriteByte('\n')
+			sb.WriteString(style.ErrorText.Render(i18n.T("Delete this memory? ")))
+			sb.WriteString(style.DialogHelp.Render(i18n.T("y to confirm · any key to cancel")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar(help, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// viewList writes the filter line and the entries in view.
+func (m MemoryModel) viewList(sb *strings.Builder, inner int, rule string) {
+	filterVal := style.Faint.Render(i18n.T("type to filter..."))
+	if m.filterText != "" {
+		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(m.filterText)
+	}
+	sb.WriteString(style.DialogHelpKey.Render(i18n.T("Filter: ")) + filterVal)
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.filtered) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No memories found")))
+		sb.WriteByte('\n')
+		return
+	}
+	end := min(m.offset+m.pageSize, len(m.filtered))
+	if m.offset > 0 {
+		sb.WriteString(style.Faint.Render("  ↑ more above"))
+		sb.WriteByte('\n')
+	}
+	for i := m.offset; i < end; i++ {
+		sb.WriteString(m.renderEntry(m.filtered[i], i == m.cursor, inner))
+		sb.WriteByte('\n')
+	}
+	if end < len(m.filtered) {
+		sb.WriteString(style.Faint.Render("  ↓ more below"))
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(style.Faint.Render(i18n.T("%d of %d memories", len(m.filtered), len(m.entries))))
+	sb.WriteByte('\n')
+}
+
+// renderEntry renders a single entry row: pin marker, category, date and the
+// start of its text.
+func (m MemoryModel) renderEntry(e MemoryEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+	mark := "  "
+	if e.Pinned {
+		mark = style.RadioOn.Render("● ")
+	}
+
+	cat := fmt.Sprintf("%-12s", ansi.Truncate(e.Category, 12, "…"))
+	date := fmt.Sprintf("%-10s", ansi.Truncate(e.Timestamp, 10, ""))
+	first, _, _ := strings.Cut(e.Content, "\n")
+	text := ansi.Truncate(first, max(width-4-13-11, 8), "…")
+	if isCursor {
+		text = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(text)
+	} else {
+		text = style.Faint.Render(text)
+	}
+
+	return cursor + mark + style.Faint.Render(cat) + " " + style.Faint.Render(date) + " " + text
+}
@@ -2,11 +2,14 @@ package dialog
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -17,6 +20,14 @@ type ModelEntry struct {
 	Active    bool   // currently selected model
 	Reasoning bool   // supports extended thinking / reasoning
 	Recent    bool   // recently used
+	Favorite  bool   // starred by the user
+
+	// Capability metadata; zero values render as unknown.
+	ContextWindow int     // tokens
+	Vision        bool    // accepts image input
+	Tools         bool    // supports tool calling
+	InputPrice    float64 // USD per 1M input tokens
+	OutputPrice   float64 // USD per 1M output tokens
 }
 
 // ModelGroup groups models under a named provider header.
@@ -26,22 +37,31 @@ type ModelGroup struct {
 }
 
 // flatEntry holds a flattened entry for cursor arithmetic across groups.
+// Header sentinels have entryIdx == -1 and carry their label in header.
 type flatEntry struct {
 	groupIdx int
 	entryIdx int
 	model    ModelEntry
+	header   string
+	count    int // models under a header sentinel
 }
 
-// ModelsModel is an enhanced model picker with provider grouping, filtering,
-// size display, reasoning badges, and a recently-used section.
+// maxRecentModels caps the "Recently Used" section.
+const maxRecentModels = 5
+
+// ModelsModel is an enhanced model picker with provider grouping, fuzzy
+// filtering, capability columns, favorites, and a recently-used section.
 //
-// Emits ModelChoice on selection, ModelCancel on Esc.
+// Emits ModelChoice on selection, ModelFavoriteToggle on ctrl+s, ModelCancel
+// on Esc.
 type ModelsModel struct {
-	groups   []ModelGroup
-	flat     []flatEntry // flattened, filtered view
-	cursor   int
-	filter   string
-	viewport viewport.Model
+	groups    []ModelGroup
+	flat      []flatEntry // flattened, filtered view
+	cursor    int
+	filter    string
+	favorites map[string]bool
+	recent    []string // "provider/model", most recent first
+	viewport  viewport.Model
 
 	width, height int
 }
@@ -50,7 +70,7 @@ type ModelsModel struct {
 func NewModels() ModelsModel {
 	vp := viewport.New(viewport.WithWidth(60), viewport.WithHeight(20))
 	vp.SoftWrap = true
-	return ModelsModel{viewport: vp}
+	return ModelsModel{viewport: vp, favorites: make(map[string]bool)}
 }
 
 // SetModels populates the picker with provider groups and rebuilds the flat
@@ -58,6 +78,7 @@ func NewModels() ModelsModel {
 func (m *ModelsModel) SetModels(groups []ModelGroup) {
 	m.groups = groups
 	m.filter = ""
+	m.applyPreferences()
 	m.rebuildFlat()
 	m.cursor = 0
 	// Position cursor on active model.
@@ -67,9 +88,51 @@ func (m *ModelsModel) SetModels(groups []ModelGroup) {
 			break
 		}
 	}
+	m.moveCursor(0)
+	m.syncViewport()
+}
+
+// SetPreferences sets the persisted favorites and recently-used models, both
+// as "provider/model" keys. Recent is ordered most recent first.
+func (m *ModelsModel) SetPreferences(favorites, recent []string) {
+	m.favorites = make(map[string]bool, len(favorites))
+	for _, f := range favorites {
+		m.favorites[f] = true
+	}
+	m.recent = recent
+	if len(m.recent) > maxRecentModels {
+		m.recent = m.recent[:maxRecentModels]
+	}
+	m.applyPreferences()
+	m.rebuildFlat()
+	m.syncViewport()
+}
+
+// SetFilter presets the filter text, e.g. to a provider name.
+func (m *ModelsModel) SetFilter(q string) {
+	m.filter = q
+	m.rebuildFlat()
+	m.cursor = 0
+	m.moveCursor(0)
 	m.syncViewport()
 }
 
+// applyPreferences stamps Favorite / Recent flags onto the entries.
+func (m *ModelsModel) applyPreferences() {
+	recent := make(map[string]bool, len(m.recent))
+	for _, r := range m.recent {
+		recent[r] = true
+	}
+	for gi := range m.groups {
+		g := &m.groups[gi]
+		for ei := range g.Models {
+			k := modelKey(g.Provider, g.Models[ei].Name)
+			g.Models[ei].Favorite = m.favorites[k]
+			g.Models[ei].Recent = recent[k]
+		}
+	}
+}
+
 // SetSize updates terminal dimensions and resizes the viewport.
 func (m *ModelsModel) SetSize(w, h int) {
 	m.width = w
@@ -78,7 +141,7 @@ func (m *ModelsModel) SetSize(w, h int) {
 	if vpW < 20 {
 		vpW = 20
 	}
-	vpH := h - 12
+	vpH := h - 13
 	if vpH < 5 {
 		vpH = 5
 	}
@@ -89,8 +152,8 @@ func (m *ModelsModel) SetSize(w, h int) {
 
 func (m ModelsModel) dialogWidth() int {
 	dw := m.width - 4
-	if dw > 80 {
-		dw = 80
+	if dw > 96 {
+		dw = 96
 	}
 	if dw < 50 {
 		dw = 50
@@ -98,124 +161,252 @@ func (m ModelsModel) dialogWidth() int {
 	return dw
 }
 
+func modelKey(provider, name string) string {
+	return provider + "/" + name
+}
+
+// scoredEntry pairs an entry with its fuzzy match score for sorting.
+type scoredEntry struct {
+	fe    flatEntry
+	score int
+}
+
 // rebuildFlat flattens the groups into a single list, applying the filter.
-// Recently-used models are prepended as a synthetic group at the top.
+// Favorites and recently-used models are prepended as synthetic sections;
+// models shown there are not repeated under their provider.
 func (m *ModelsModel) rebuildFlat() {
 	m.flat = m.flat[:0]
 
-	q := strings.ToLower(m.filter)
+	q := strings.ToLower(strings.TrimSpace(m.filter))
+
+	// match returns the filter score for a model; ok is false when filtered out.
+	match := func(provider string, model ModelEntry) (int, bool) {
+		if q == "" {
+			return 0, true
+		}
+		return fuzzyScore(q, strings.ToLower(modelKey(provider, model.Name)))
+	}
 
-	// Recent models come first (synthetic group).
+	// appendSection adds a header and its entries, best match first.
+	appendSection := func(header string, groupIdx int, entries []scoredEntry) {
+		if len(entries) == 0 {
+			return
+		}
+		if q != "" {
+			sort.SliceStable(entries, func(i, j int) bool { return entries[i].score > entries[j].score })
+		}
+		m.flat = append(m.flat, flatEntry{groupIdx: groupIdx, entryIdx: -1, header: header, count: len(entries)})
+		for _, se := range entries {
+			m.flat = append(m.flat, se.fe)
+		}
+	}
+
+	var favs []scoredEntry
 	for gi, g := range m.groups {
 		for ei, model := range g.Models {
-			if !model.Recent {
+			if !model.Favorite {
 				continue
 			}
-			if q != "" && !matchesModelFilter(model, g.Provider, q) {
-				continue
+			if score, ok := match(g.Provider, model); ok {
+				favs = append(favs, scoredEntry{flatEntry{groupIdx: gi, entryIdx: ei, model: model}, score})
 			}
-			m.flat = append(m.flat, flatEntry{groupIdx: gi, entryIdx: ei, model: model})
 		}
 	}
+	appendSection("★ "+i18n.T("Favorites"), -1, favs)
+
+	// Recent follows the recorded order rather than group order.
+	var recent []scoredEntry
+	for _, key := range m.recent {
+		for gi, g := range m.groups {
+			for ei, model := range g.Models {
+				if model.Favorite || modelKey(g.Provider, model.Name) != key {
+					continue
+				}
+				if score, ok := match(g.Provider, model); ok {
+					recent = append(recent, scoredEntry{flatEntry{groupIdx: gi, entryIdx: ei, model: model}, score})
+				}
+			}
+		}
+	}
+	appendSection(i18n.T("Recently Used"), -1, recent)
 
-	// Then normal groups.
 	for gi, g := range m.groups {
-		groupAdded := false
+		var entries []scoredEntry
 		for ei, model := range g.Models {
-			if model.Recent {
-				continue // already in recent section
+			if model.Favorite || model.Recent {
+				continue // already listed above
 			}
-			if q != "" && !matchesModelFilter(model, g.Provider, q) {
-				continue
-			}
-			if !groupAdded {
-				// Sentinel entry for group header (entryIdx == -1).
-				m.flat = append(m.flat, flatEntry{groupIdx: gi, entryIdx: -1})
-				groupAdded = true
+			if score, ok := match(g.Provider, model); ok {
+				entries = append(entries, scoredEntry{flatEntry{groupIdx: gi, entryIdx: ei, model: model}, score})
 			}
-			m.flat = append(m.flat, flatEntry{groupIdx: gi, entryIdx: ei, model: model})
 		}
+		appendSection(g.Provider, gi, entries)
 	}
 }
 
-func matchesModelFilter(model ModelEntry, provider, q string) bool {
-	return strings.Contains(strings.ToLower(model.Name), q) ||
-		strings.Contains(strings.ToLower(provider), q) ||
-		strings.Contains(strings.ToLower(model.Size), q)
+// fuzzyScore reports whether every rune of q appears in s in order, and a
+// score that rewards consecutive runs and matches at word starts.
+func fuzzyScore(q, s string) (int, bool) {
+	qr := []rune(q)
+	if len(qr) == 0 {
+		return 0, true
+	}
+	score, qi, run := 0, 0, 0
+	prev := '/'
+	for _, r := range s {
+		if qi < len(qr) && r == qr[qi] {
+			run++
+			score += run * 2
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3 // start of a word: "gpt-4o" matches "4" at a boundary
+			}
+			qi++
+		} else {
+			run = 0
+			if qi > 0 && qi < len(qr) {
+				score-- // gaps inside the match
+			}
+		}
+		prev = r
+	}
+	if qi < len(qr) {
+		return 0, false
+	}
+	return score, true
 }
 
-// syncViewport rebuilds the viewport content from the current flat list.
+// syncViewport rebuilds the viewport content and keeps the cursor visible.
 func (m *ModelsModel) syncViewport() {
-	m.viewport.SetContent(m.renderContent())
+	content, line := m.renderContent()
+	m.viewport.SetContent(content)
+	h := m.viewport.Height()
+	if h <= 0 {
+		return
+	}
+	off := m.viewport.YOffset()
+	if line < off {
+		m.viewport.SetYOffset(line)
+	} else if line >= off+h {
+		m.viewport.SetYOffset(line - h + 1)
+	}
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // Update
 // ──────────────────────────────────────────────────────────────────────────────
 
-// Update handles keyboard input.
+// Update handles keyboard input. Printable keys always go to the filter, so
+// navigation uses the arrow keys only.
 //
-//	↑/k    → move cursor up (skipping header sentinels)
-//	↓/j    → move cursor down
-//	enter  → emit ModelChoice for selected model
-//	esc    → emit ModelCancel
-//	char   → append to filter
+//	↑/↓       → move cursor (skipping header sentinels)
+//	enter     → emit ModelChoice for selected model
+//	ctrl+s    → star / unstar the selected model
+//	esc       → emit ModelCancel
+//	char      → append to filter
 //	backspace → remove last filter char
+//	ctrl+u    → clear filter
 func (m ModelsModel) Update(msg tea.Msg) (ModelsModel, tea.Cmd) {
 	kp, ok := msg.(tea.KeyPressMsg)
 	if !ok {
 		return m, nil
 	}
 
-	switch kp.Code {
-	case tea.KeyUp, 'k':
+	switch kp.String() {
+	case "up":
 		m.moveCursor(-1)
 		m.syncViewport()
 		return m, nil
 
-	case tea.KeyDown, 'j':
+	case "down":
 		m.moveCursor(1)
 		m.syncViewport()
 		return m, nil
 
-	case tea.KeyEnter:
-		if m.cursor < len(m.flat) {
-			fe := m.flat[m.cursor]
-			if fe.entryIdx >= 0 {
-				provider := m.groups[fe.groupIdx].Provider
-				modelName := fe.model.Name
-				return m, func() tea.Msg {
-					return ModelChoice{Provider: provider, Model: modelName}
-				}
+	case "enter":
+		if fe, ok := m.selected(); ok {
+			provider := m.groups[fe.groupIdx].Provider
+			modelName := fe.model.Name
+			return m, func() tea.Msg {
+				return ModelChoice{Provider: provider, Model: modelName}
 			}
 		}
 		return m, nil
 
-	case tea.KeyEscape:
+	case "ctrl+s":
+		fe, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		provider := m.groups[fe.groupIdx].Provider
+		modelName := fe.model.Name
+		k := modelKey(provider, modelName)
+		fav := !m.favorites[k]
+		if fav {
+			m.favorites[k] = true
+		} else {
+			delete(m.favorites, k)
+		}
+		m.applyPreferences()
+		m.rebuildFlat()
+		m.focusModel(provider, modelName)
+		m.syncViewport()
+		return m, func() tea.Msg {
+			return ModelFavoriteToggle{Provider: provider, Model: modelName, Favorite: fav}
+		}
+
+	case "esc":
 		return m, func() tea.Msg { return ModelCancel{} }
 
-	case tea.KeyBackspace:
+	case "backspace":
 		if len(m.filter) > 0 {
 			runes := []rune(m.filter)
 			m.filter = string(runes[:len(runes)-1])
-			m.rebuildFlat()
-			m.cursor = 0
-			m.syncViewport()
+			m.refilter()
 		}
 		return m, nil
 
-	default:
-		if kp.Code >= 32 && kp.Code < 127 {
-			m.filter += string(rune(kp.Code))
-			m.rebuildFlat()
-			m.cursor = 0
-			m.syncViewport()
-		}
+	case "ctrl+u":
+		m.filter = ""
+		m.refilter()
 		return m, nil
 	}
+
+	if kp.Text != "" {
+		m.filter += kp.Text
+		m.refilter()
+	}
+	return m, nil
+}
+
+func (m *ModelsModel) refilter() {
+	m.rebuildFlat()
+	m.cursor = 0
+	m.moveCursor(0)
+	m.syncViewport()
 }
 
-// moveCursor advances cursor by delta, skipping header sentinels.
+// selected returns the entry under the cursor, if it is a model row.
+func (m ModelsModel) selected() (flatEntry, bool) {
+	if m.cursor < len(m.flat) && m.flat[m.cursor].entryIdx >= 0 {
+		return m.flat[m.cursor], true
+	}
+	return flatEntry{}, false
+}
+
+// focusModel moves the cursor to the given model after a rebuild.
+func (m *ModelsModel) focusModel(provider, name string) {
+	for i, fe := range m.flat {
+		if fe.entryIdx >= 0 && fe.model.Name == name && m.groups[fe.groupIdx].Provider == provider {
+			m.cursor = i
+			return
+		}
+	}
+	m.cursor = 0
+	m.moveCursor(0)
+}
+
+// moveCursor advances cursor by delta, skipping header sentinels. A delta of
+// zero settles the cursor on the nearest selectable entry at or after it.
 func (m *ModelsModel) moveCursor(delta int) {
 	n := len(m.flat)
 	if n == 0 {
@@ -232,6 +423,10 @@ func (m *ModelsModel) moveCursor(delta int) {
 	if !hasSelectable {
 		return
 	}
+	step := delta
+	if step == 0 {
+		step = 1
+	}
 	next := m.cursor + delta
 	for i := 0; i < n; i++ {
 		if next < 0 {
@@ -243,7 +438,7 @@ func (m *ModelsModel) moveCursor(delta int) {
 		if m.flat[next].entryIdx >= 0 {
 			break
 		}
-		next += delta
+		next += step
 	}
 	m.cursor = next
 }
@@ -259,29 +454,38 @@ func (m ModelsModel) View() string {
 	var sb strings.Builder
 
 	// Title.
-	sb.WriteString(GradientTitle("Select Model"))
+	sb.WriteString(GradientTitle(i18n.T("Select Model")))
 	sb.WriteByte('\n')
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
 	sb.WriteByte('\n')
 
 	// Filter bar.
-	filterPrompt := style.DialogHelpKey.Render("Filter: ")
+	filterPrompt := style.DialogHelpKey.Render(i18n.T("Filter: "))
 	filterVal := m.filter
 	if filterVal == "" {
-		filterVal = style.Faint.Render("type to filter...")
+		filterVal = style.Faint.Render(i18n.T("type to fuzzy-filter..."))
 	} else {
 		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(filterVal)
 	}
-	total := 0
+	total, shown := 0, 0
 	for _, g := range m.groups {
 		total += len(g.Models)
 	}
-	count := style.Faint.Render(fmt.Sprintf("  (%d model(s))", total))
+	for _, fe := range m.flat {
+		if fe.entryIdx >= 0 {
+			shown++
+		}
+	}
+	count := style.Faint.Render("  " + i18n.T("(%d/%d model(s))", shown, total))
 	sb.WriteString(filterPrompt + filterVal + count)
 	sb.WriteByte('\n')
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
 	sb.WriteByte('\n')
 
+	// Column header.
+	sb.WriteString(style.Faint.Render(m.columnsHeader()))
+	sb.WriteByte('\n')
+
 	// Scrollable model list.
 	sb.WriteString(m.viewport.View())
 	sb.WriteByte('\n')
@@ -290,6 +494,8 @@ func (m ModelsModel) View() string {
 	helpItems := []HelpItem{
 		{Key: "↑↓", Desc: "navigate"},
 		{Key: "enter", Desc: "select"},
+		{Key: "ctrl+s", Desc: "favorite"},
+		{Key: "ctrl+u", Desc: "clear filter"},
 		{Key: "esc", Desc: "cancel"},
 	}
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
@@ -297,7 +503,7 @@ func (m ModelsModel) View() string {
 	sb.WriteString(RenderHelpBar(helpItems, dw-6))
 
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(dw)
@@ -315,60 +521,78 @@ func (m ModelsModel) View() string {
 	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, box)
 }
 
-// renderContent builds the full text content for the viewport.
-func (m ModelsModel) renderContent() string {
+// Column widths for the capability table, widened by colWidth when a
+// translated header needs more room.
+const (
+	colCtx    = 7
+	colVision = 7
+	colTools  = 6
+	colPrice  = 14
+)
+
+// colWidth is the width of the column under header, at least minWidth.
+func colWidth(header string, minWidth int) int {
+	return max(minWidth, lipgloss.Width(i18n.T(header))+1)
+}
+
+// nameWidth is the width left for the model name after the fixed columns.
+func (m ModelsModel) nameWidth() int {
+	w := m.dialogWidth() - 6 - 6 - colWidth("Context", colCtx) - colWidth("Vision", colVision) -
+		colWidth("Tools", colTools) - colWidth("$/1M in/out", colPrice)
+	if w < 16 {
+		w = 16
+	}
+	return w
+}
+
+func (m ModelsModel) columnsHeader() string {
+	return "      " + padRight(i18n.T("Model"), m.nameWidth()) +
+		padLeft(i18n.T("Context"), colWidth("Context", colCtx)) +
+		padLeft(i18n.T("Vision"), colWidth("Vision", colVision)) +
+		padLeft(i18n.T("Tools"), colWidth("Tools", colTools)) +
+		padLeft(i18n.T("$/1M in/out"), colWidth("$/1M in/out", colPrice))
+}
+
+// renderContent builds the full text content for the viewport and reports
+// the line the cursor is on.
+func (m ModelsModel) renderContent() (string, int) {
 	if len(m.flat) == 0 {
-		return style.Faint.Render("  No models found")
+		return style.Faint.Render("  " + i18n.T("No models found")), 0
 	}
 
-	recentHeaderEmitted := false
 	var sb strings.Builder
-	lastGroupIdx := -2
+	line, cursorLine := 0, 0
 
 	for i, fe := range m.flat {
-		isCursor := i == m.cursor
-
-		// Handle the recent block header.
-		if fe.model.Recent && !recentHeaderEmitted {
-			recentHeaderEmitted = true
-			header := lipgloss.NewStyle().
-				Foreground(style.Warning).Bold(true).
-				Render("  Recently Used")
-			sb.WriteString(header + "\n")
-		}
-
-		// Group header sentinel.
 		if fe.entryIdx == -1 {
-			if fe.groupIdx != lastGroupIdx {
-				lastGroupIdx = fe.groupIdx
-				g := m.groups[fe.groupIdx]
-				count := 0
-				for _, model := range g.Models {
-					if !model.Recent {
-						count++
-					}
-				}
-				header := lipgloss.NewStyle().
-					Foreground(style.Secondary).Bold(true).
-					Render(fmt.Sprintf("  %s", g.Provider))
-				cntLabel := style.Faint.Render(fmt.Sprintf("  (%d)", count))
-				sb.WriteString(header + cntLabel + "\n")
+			var hs lipgloss.Style
+			switch {
+			case fe.groupIdx >= 0:
+				hs = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true)
+			default:
+				hs = lipgloss.NewStyle().Foreground(style.Warning).Bold(true)
 			}
+			sb.WriteString(hs.Render("  "+fe.header) + style.Faint.Render(fmt.Sprintf("  (%d)", fe.count)) + "\n")
+			line++
 			continue
 		}
 
-		sb.WriteString(m.renderModelEntry(fe, isCursor))
+		if i == m.cursor {
+			cursorLine = line
+		}
+		sb.WriteString(m.renderModelEntry(fe, i == m.cursor))
 		sb.WriteByte('\n')
+		line++
 	}
 
-	return strings.TrimRight(sb.String(), "\n")
+	return strings.TrimRight(sb.String(), "\n"), cursorLine
 }
 
 // renderModelEntry renders a single model row.
 func (m ModelsModel) renderModelEntry(fe flatEntry, isCursor bool) string {
-	cursor := "    "
+	cursor := "  "
 	if isCursor {
-		cursor = style.PlanSelected.Render("  > ")
+		cursor = style.PlanSelected.Render("> ")
 	}
 
 	var radio string
@@ -378,31 +602,95 @@ func (m ModelsModel) renderModelEntry(fe flatEntry, isCursor bool) string {
 		radio = style.RadioOff.Render("○ ")
 	}
 
-	var name string
-	if isCursor {
-		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(fe.model.Name)
-	} else {
-		name = style.Faint.Render(fe.model.Name)
+	star := "  "
+	if fe.model.Favorite {
+		star = lipgloss.NewStyle().Foreground(style.Warning).Render("★ ")
 	}
 
+	// Favorites and recents mix providers, so qualify the name there.
+	label := fe.model.Name
+	if fe.model.Favorite || fe.model.Recent {
+		label = modelKey(m.groups[fe.groupIdx].Provider, fe.model.Name)
+	}
+	nw := m.nameWidth()
 	var badges []string
 	if fe.model.Size != "" {
-		badges = append(badges, style.Faint.Render(fe.model.Size))
+		badges = append(badges, fe.model.Size)
 	}
 	if fe.model.Reasoning {
-		badges = append(badges, lipgloss.NewStyle().Foreground(style.Warning).Render("⚡reasoning"))
-	}
-	if fe.model.Recent {
-		badges = append(badges, lipgloss.NewStyle().Foreground(style.Muted).Render("recent"))
+		badges = append(badges, "⚡")
 	}
 	if fe.model.Active {
-		badges = append(badges, lipgloss.NewStyle().Foreground(style.Success).Render("active"))
+		badges = append(badges, i18n.T("active"))
 	}
-
-	var suffix string
+	suffix := ""
 	if len(badges) > 0 {
-		suffix = "  " + strings.Join(badges, "  ")
+		suffix = " " + strings.Join(badges, " ")
+	}
+	label = truncateStr(label, max(nw-lipgloss.Width(suffix), 8))
+
+	var name string
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(label)
+	} else {
+		name = style.Faint.Render(label)
+	}
+	if suffix != "" {
+		suffixStyle := style.Faint
+		if fe.model.Active {
+			suffixStyle = lipgloss.NewStyle().Foreground(style.Success)
+		}
+		name += suffixStyle.Render(suffix)
+	}
+	name += strings.Repeat(" ", max(0, nw-lipgloss.Width(label)-lipgloss.Width(suffix)))
+
+	cols := padLeft(formatContext(fe.model.ContextWindow), colWidth("Context", colCtx)) +
+		padLeft(checkMark(fe.model.Vision), colWidth("Vision", colVision)) +
+		padLeft(checkMark(fe.model.Tools), colWidth("Tools", colTools)) +
+		padLeft(formatPrice(fe.model.InputPrice, fe.model.OutputPrice), colWidth("$/1M in/out", colPrice))
+
+	return cursor + radio + star + name + style.Faint.Render(cols)
+}
+
+// formatContext renders a context window as e.g. "128k" or "1M".
+func formatContext(tokens int) string {
+	switch {
+	case tokens <= 0:
+		return "—"
+	case tokens >= 1_000_000 && tokens%1_000_000 == 0:
+		return fmt.Sprintf("%dM", tokens/1_000_000)
+	case tokens >= 1000:
+		return fmt.Sprintf("%dk", tokens/1000)
+	default:
+		return fmt.Sprintf("%d", tokens)
 	}
+}
 
-	return cursor + radio + name + suffix
+// formatPrice renders input/output pricing per 1M tokens, or a dash when the
+// backend reports no price (local models).
+func formatPrice(in, out float64) string {
+	if in <= 0 && out <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%s/%s", trimFloat(in), trimFloat(out))
+}
+
+func trimFloat(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return "$" + s
+}
+
+func checkMark(b bool) string {
+	if b {
+		return "✓"
+	}
+	return "·"
+}
+
+func padLeft(s string, w int) string {
+	if n := lipgloss.Width(s); n < w {
+		return strings.Repeat(" ", w-n) + s
+	}
+	return s
 }
@@ -8,8 +8,10 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/msg"
 	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/anim"
 	"github.com/miosa/osa-tui/ui/logo"
 )
 
@@ -22,9 +24,23 @@ const (
 	stepTemplate                       // 3. OS template / use case
 	stepProvider                       // 4. LLM provider
 	stepAPIKey                         // 5. API key (skip for Ollama)
-	stepMachines                       // 6. Skill groups
-	stepChannels                       // 7. Messaging channels
-	stepConfirm                        // 8. Review + write
+	stepVerify                         // 6. Connection test
+	stepMachines                       // 7. Skill groups
+	stepChannels                       // 8. Messaging channels
+	stepConfirm                        // 9. Review + write
+)
+
+// verifyState tracks the outcome of the connection test step.
+type verifyState int
+
+const (
+	verifyNone    verifyState = iota // not attempted yet
+	verifyPending                    // request in flight
+	verifyOK                         // provider reachable, credentials accepted
+	verifyFailed                     // provider rejected or unreachable
+	verifySkipped                    // backend cannot verify, or user skipped
+	verifyMissing                    // Ollama model not installed locally
+	verifyPulling                    // Ollama model pull in progress
 )
 
 // OnboardingModel implements the first-run onboarding wizard.
@@ -55,13 +71,26 @@ type OnboardingModel struct {
 	// Step 5: API Key
 	keyInput InputCursor
 
-	// Step 6: Machines
+	// Step 6: Connection test
+	verify     verifyState
+	verifySeq  int // of the connection test in flight
+	verifyMsg  string
+	verifyHint string
+	spinner    anim.Model
+
+	// Ollama model pull (offered when the default model is missing)
+	pullModel     string
+	pullStatus    string
+	pullCompleted int64
+	pullTotal     int64
+
+	// Step 7: Machines
 	machineToggles map[string]bool
 
-	// Step 7: Channels
+	// Step 8: Channels
 	channelToggles map[string]bool
 
-	// Step 8: Confirm
+	// Step 9: Confirm
 	confirmFocused int // 0=Confirm, 1=Back
 
 	err string
@@ -79,6 +108,7 @@ func NewOnboarding() OnboardingModel {
 		height:           24,
 		machineToggles:   make(map[string]bool),
 		channelToggles:   make(map[string]bool),
+		spinner:          anim.New(anim.Opts{Label: i18n.T("Testing connection")}),
 	}
 }
 
@@ -121,6 +151,68 @@ func (m *OnboardingModel) SetError(err string) {
 	m.confirmFocused = 0
 }
 
+// SetVerifyResult reports the outcome of the connection test. Results that
+// arrive after the user has left the verify step, or for an earlier test,
+// are ignored.
+func (m *OnboardingModel) SetVerifyResult(r msg.OnboardingVerifyResult) {
+	if m.step != stepVerify || m.verify != verifyPending || r.Seq != m.verifySeq {
+		return
+	}
+	m.spinner.Stop()
+	m.verifyMsg = r.Message
+	m.verifyHint = r.Hint
+	switch {
+	case r.MissingModel != "":
+		m.verify = verifyMissing
+		m.pullModel = r.MissingModel
+	case r.Skipped:
+		m.verify = verifySkipped
+	case r.OK:
+		m.verify = verifyOK
+	default:
+		m.verify = verifyFailed
+		if m.verifyHint == "" {
+			m.verifyHint = m.defaultVerifyHint()
+		}
+	}
+}
+
+// SetPullProgress updates the model pull progress bar.
+func (m *OnboardingModel) SetPullProgress(status string, completed, total int64) {
+	if m.verify != verifyPulling {
+		return
+	}
+	m.pullStatus = status
+	m.pullCompleted = completed
+	m.pullTotal = total
+}
+
+// SetPullDone finishes a model pull. On success the connection test is re-run;
+// on failure the error is shown with the option to retry the pull.
+func (m *OnboardingModel) SetPullDone(err error, cancelled bool) tea.Cmd {
+	if m.step != stepVerify || m.verify != verifyPulling {
+		return nil
+	}
+	switch {
+	case cancelled:
+		m.verify = verifyMissing
+		m.verifyMsg = "Pull cancelled"
+		return nil
+	case err != nil:
+		m.verify = verifyMissing
+		m.verifyMsg = "Pull failed: " + err.Error()
+		return nil
+	}
+	return m.startVerify()
+}
+
+// UpdateSpinner forwards animation ticks to the connection test spinner.
+func (m *OnboardingModel) UpdateSpinner(t tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(t)
+	return cmd
+}
+
 // Update processes a key press and returns an optional tea.Cmd.
 func (m *OnboardingModel) Update(k tea.KeyPressMsg) tea.Cmd {
 	if key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("ctrl+c"))) {
@@ -138,6 +230,8 @@ func (m *OnboardingModel) Update(k tea.KeyPressMsg) tea.Cmd {
 		return m.updateProvider(k)
 	case stepAPIKey:
 		return m.updateAPIKey(k)
+	case stepVerify:
+		return m.updateVerify(k)
 	case stepMachines:
 		return m.updateMachines(k)
 	case stepChannels:
@@ -277,7 +371,7 @@ func (m *OnboardingModel) updateProvider(k tea.KeyPressMsg) tea.Cmd {
 	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("enter"))):
 		selected := m.selectedProvider() // safe fallback if providers is empty
 		if selected.EnvVar == "" {
-			m.step = stepMachines
+			return m.startVerify()
 		} else {
 			m.step = stepAPIKey
 			m.keyInput = InputCursor{Focused: true}
@@ -295,9 +389,8 @@ func (m *OnboardingModel) updateProvider(k tea.KeyPressMsg) tea.Cmd {
 func (m *OnboardingModel) updateAPIKey(k tea.KeyPressMsg) tea.Cmd {
 	switch {
 	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("enter"))):
-		m.step = stepMachines
 		m.keyInput.Focused = false
-		return nil
+		return m.startVerify()
 	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("escape"))):
 		m.step = stepProvider
 		m.keyInput.Focused = false
@@ -315,7 +408,87 @@ func (m *OnboardingModel) updateAPIKey(k tea.KeyPressMsg) tea.Cmd {
 	}
 }
 
-// ── Step 6: Machines ─────────────────────────────────────────
+// ── Step 6: Connection Test ─────────────────────────────────
+
+// startVerify enters the verify step and asks the app to test the selected
+// provider with the entered key.
+func (m *OnboardingModel) startVerify() tea.Cmd {
+	selected := m.selectedProvider()
+	m.step = stepVerify
+	m.verify = verifyPending
+	m.verifySeq++
+	m.verifyMsg = ""
+	m.verifyHint = ""
+	m.spinner.Start()
+	req := OnboardingVerifyRequest{
+		Seq:      m.verifySeq,
+		Provider: selected.Key,
+		Model:    selected.DefaultModel,
+		APIKey:   m.keyInput.Value,
+		EnvVar:   selected.EnvVar,
+	}
+	return tea.Batch(m.spinner.Tick(), func() tea.Msg { return req })
+}
+
+// leaveVerify returns to the step that precedes the connection test.
+func (m *OnboardingModel) leaveVerify() {
+	m.spinner.Stop()
+	m.verify = verifyNone
+	if m.selectedProvider().EnvVar == "" {
+		m.step = stepProvider
+	} else {
+		m.step = stepAPIKey
+		m.keyInput.Focused = true
+	}
+}
+
+func (m *OnboardingModel) updateVerify(k tea.KeyPressMsg) tea.Cmd {
+	switch {
+	case m.verify == verifyPulling:
+		if key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("escape"))) {
+			return func() tea.Msg { return OnboardingPullCancel{} }
+		}
+		return nil
+	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("escape"))):
+		m.leaveVerify()
+		return nil
+	case m.verify == verifyPending:
+		return nil
+	case m.verify == verifyMissing:
+		switch {
+		case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("enter", "p"))):
+			m.verify = verifyPulling
+			m.pullStatus = "starting"
+			m.pullCompleted, m.pullTotal = 0, 0
+			model := m.pullModel
+			return func() tea.Msg { return OnboardingPullRequest{Model: model} }
+		case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("s"))):
+			m.verify = verifySkipped
+			m.verifyMsg = m.pullModel + " not installed \u2014 pull it later with `ollama pull " + m.pullModel + "`"
+			m.step = stepMachines
+		}
+		return nil
+	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("enter"))):
+		if m.verify == verifyFailed {
+			return m.startVerify()
+		}
+		m.step = stepMachines
+		return nil
+	case m.verify != verifyFailed:
+		return nil
+	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("r"))):
+		return m.startVerify()
+	case key.Matches[tea.KeyPressMsg](k, key.NewBinding(key.WithKeys("s"))):
+		m.verify = verifySkipped
+		m.verifyMsg = "Skipped by user"
+		m.verifyHint = ""
+		m.step = stepMachines
+		return nil
+	}
+	return nil
+}
+
+// ── Step 7: Machines ─────────────────────────────────────────
 
 func (m *OnboardingModel) updateMachines(k tea.KeyPressMsg) tea.Cmd {
 	switch {
@@ -345,7 +518,7 @@ func (m *OnboardingModel) updateMachines(k tea.KeyPressMsg) tea.Cmd {
 	}
 }
 
-// ── Step 7: Channels ─────────────────────────────────────────
+// ── Step 8: Channels ─────────────────────────────────────────
 
 func (m *OnboardingModel) updateChannels(k tea.KeyPressMsg) tea.Cmd {
 	switch {
@@ -369,7 +542,7 @@ func (m *OnboardingModel) updateChannels(k tea.KeyPressMsg) tea.Cmd {
 	}
 }
 
-// ── Step 8: Confirm ─────────────────────────────────────────
+// ── Step 9: Confirm ─────────────────────────────────────────
 
 func (m *OnboardingModel) updateConfirm(k tea.KeyPressMsg) tea.Cmd {
 	switch {
@@ -434,6 +607,8 @@ func (m OnboardingModel) View() string {
 		content = m.viewProvider()
 	case stepAPIKey:
 		content = m.viewAPIKey()
+	case stepVerify:
+		content = m.viewVerify()
 	case stepMachines:
 		content = m.viewMachines()
 	case stepChannels:
@@ -457,8 +632,9 @@ func (m OnboardingModel) boxWidth() int {
 
 func (m OnboardingModel) stepIndicator(current int) string {
 	var parts []string
-	labels := []string{"Name", "Profile", "Template", "Provider", "Key", "Skills", "Channels", "Confirm"}
+	labels := []string{"Name", "Profile", "Template", "Provider", "Key", "Verify", "Skills", "Channels", "Confirm"}
 	for i, label := range labels {
+		label = i18n.T(label)
 		num := fmt.Sprintf("%d", i+1)
 		if i == current {
 			parts = append(parts, style.RadioOn.Render(num)+style.Bold.Render(" "+label))
@@ -480,7 +656,7 @@ func (m OnboardingModel) viewWelcome() string {
 	b.WriteString(logo.RenderWithGradient(w))
 	b.WriteString("\n\n")
 
-	b.WriteString(style.Bold.Render("Welcome to OSA") + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Welcome to OSA")) + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n")
 
 	// System info
@@ -517,7 +693,7 @@ func (m OnboardingModel) viewWelcome() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(style.Faint.Render("Agent name: ") + m.nameInput.View() + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("Agent name: ")) + m.nameInput.View() + "\n\n")
 
 	b.WriteString(RenderButtons([]ButtonDef{
 		{Label: "Continue", Active: true, Underline: -1},
@@ -540,22 +716,22 @@ func (m OnboardingModel) viewProfile() string {
 	b.WriteString(m.stepIndicator(1) + "\n\n")
 	b.WriteString(GradientTitle("Your Profile") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n")
-	b.WriteString(style.Faint.Render("Tell me about yourself (optional):") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("Tell me about yourself (optional):")) + "\n\n")
 
 	// Name field
-	nameLabel := style.Faint.Render("Name:    ")
+	nameLabel := style.Faint.Render(i18n.T("Name:    "))
 	if m.profileFocus == 0 {
-		nameLabel = style.Bold.Render("Name:    ")
+		nameLabel = style.Bold.Render(i18n.T("Name:    "))
 	}
 	b.WriteString(nameLabel + m.userNameInput.View() + "\n\n")
 
 	// Context field
-	ctxLabel := style.Faint.Render("Work on: ")
+	ctxLabel := style.Faint.Render(i18n.T("Work on: "))
 	if m.profileFocus == 1 {
-		ctxLabel = style.Bold.Render("Work on: ")
+		ctxLabel = style.Bold.Render(i18n.T("Work on: "))
 	}
 	b.WriteString(ctxLabel + m.userContextInput.View() + "\n")
-	b.WriteString(style.Faint.Render("  (e.g., \"SaaS platform in Go/Svelte\")") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("  (e.g., \"SaaS platform in Go/Svelte\")")) + "\n\n")
 
 	b.WriteString(RenderButtons([]ButtonDef{
 		{Label: "Continue", Active: true, Underline: -1},
@@ -579,7 +755,7 @@ func (m OnboardingModel) viewTemplate() string {
 	b.WriteString(m.stepIndicator(2) + "\n\n")
 	b.WriteString(GradientTitle("Use Case") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n")
-	b.WriteString(style.Faint.Render("How will you use OSA?") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("How will you use OSA?")) + "\n\n")
 
 	// Option 0: Blank / General Purpose
 	cursor := "  "
@@ -590,9 +766,9 @@ func (m OnboardingModel) viewTemplate() string {
 		radio = style.RadioOn.Render("\u25cf")
 		nameStyle = style.Bold
 	}
-	b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, radio, nameStyle.Render("Blank / General Purpose")))
+	b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, radio, nameStyle.Render(i18n.T("Blank / General Purpose"))))
 	if m.templateCursor == 0 {
-		b.WriteString(style.Faint.Render("     Custom setup \u2014 configure everything yourself") + "\n")
+		b.WriteString(style.Faint.Render(i18n.T("     Custom setup \u2014 configure everything yourself")) + "\n")
 	}
 
 	// Discovered templates
@@ -632,8 +808,8 @@ func (m OnboardingModel) viewTemplate() string {
 	}
 
 	if len(m.templates) == 0 {
-		b.WriteString("\n" + style.Faint.Render("  No OS templates discovered.") + "\n")
-		b.WriteString(style.Faint.Render("  Add .osa-manifest.json to a project to connect it.") + "\n")
+		b.WriteString("\n" + style.Faint.Render(i18n.T("  No OS templates discovered.")) + "\n")
+		b.WriteString(style.Faint.Render(i18n.T("  Add .osa-manifest.json to a project to connect it.")) + "\n")
 	}
 
 	b.WriteString("\n")
@@ -656,8 +832,8 @@ func (m OnboardingModel) viewProvider() string {
 	b.WriteString(GradientTitle("Select Provider") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n\n")
 
-	localHeader := style.SectionTitle.Render("Local")
-	cloudHeader := style.SectionTitle.Render("Cloud")
+	localHeader := style.SectionTitle.Render(i18n.T("Local"))
+	cloudHeader := style.SectionTitle.Render(i18n.T("Cloud"))
 	localWritten := false
 	cloudWritten := false
 
@@ -729,15 +905,15 @@ func (m OnboardingModel) viewAPIKey() string {
 	b.WriteString(GradientTitle("API Key") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n\n")
 
-	b.WriteString(style.Bold.Render("Provider: ") + style.AgentName.Render(selected.Name) + "\n")
-	b.WriteString(style.Bold.Render("Model:    ") + style.Faint.Render(selected.DefaultModel) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Provider: ")) + style.AgentName.Render(selected.Name) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Model:    ")) + style.Faint.Render(selected.DefaultModel) + "\n")
 	if selected.EnvVar != "" {
-		b.WriteString(style.Bold.Render("Env var:  ") + style.Faint.Render(selected.EnvVar) + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Env var:  ")) + style.Faint.Render(selected.EnvVar) + "\n")
 	}
 	b.WriteString("\n")
 
-	b.WriteString(style.Faint.Render("API key: ") + m.maskedKeyView() + "\n")
-	b.WriteString(style.Faint.Render("  (leave empty if already set via env var)") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("API key: ")) + m.maskedKeyView() + "\n")
+	b.WriteString(style.Faint.Render(i18n.T("  (leave empty if already set via env var)")) + "\n\n")
 
 	b.WriteString(RenderButtons([]ButtonDef{
 		{Label: "Continue", Active: true, Underline: -1},
@@ -751,16 +927,92 @@ func (m OnboardingModel) viewAPIKey() string {
 	return style.DialogBorder.Width(w).Render(b.String())
 }
 
-// ── Step 6: Machines View ───────────────────────────────────
+// ── Step 6: Connection Test View ────────────────────────────
 
-func (m OnboardingModel) viewMachines() string {
+func (m OnboardingModel) viewVerify() string {
 	w := m.boxWidth()
+	selected := m.selectedProvider()
 	var b strings.Builder
 
 	b.WriteString(m.stepIndicator(5) + "\n\n")
+	b.WriteString(GradientTitle("Connection Test") + "\n")
+	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n\n")
+
+	b.WriteString(style.Bold.Render(i18n.T("Provider: ")) + style.AgentName.Render(selected.Name) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Model:    ")) + style.Faint.Render(selected.DefaultModel) + "\n\n")
+
+	var help []HelpItem
+	switch m.verify {
+	case verifyPending:
+		b.WriteString("  " + m.spinner.View() + "\n\n")
+		help = []HelpItem{{Key: "esc", Desc: "cancel"}}
+	case verifyOK:
+		b.WriteString(style.TaskDone.Render(i18n.T("\u2713 Connected")) + "\n")
+		if m.verifyMsg != "" {
+			b.WriteString(style.Faint.Render("  "+m.verifyMsg) + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(RenderButtons([]ButtonDef{
+			{Label: "Continue", Active: true, Underline: -1},
+		}, w) + "\n")
+		help = []HelpItem{{Key: "enter", Desc: "continue"}, {Key: "esc", Desc: "back"}}
+	case verifySkipped:
+		b.WriteString(style.Faint.Render(i18n.T("\u2013 Connection not verified")) + "\n")
+		if m.verifyMsg != "" {
+			b.WriteString(style.Faint.Render("  "+m.verifyMsg) + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(RenderButtons([]ButtonDef{
+			{Label: "Continue", Active: true, Underline: -1},
+		}, w) + "\n")
+		help = []HelpItem{{Key: "enter", Desc: "continue"}, {Key: "esc", Desc: "back"}}
+	case verifyFailed:
+		b.WriteString(style.ErrorText.Render(i18n.T("\u2717 Connection failed")) + "\n")
+		if m.verifyMsg != "" {
+			b.WriteString(style.Faint.Render("  "+m.verifyMsg) + "\n")
+		}
+		if m.verifyHint != "" {
+			b.WriteString("\n" + style.Bold.Render(i18n.T("Fix: ")) + m.verifyHint + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(RenderButtons([]ButtonDef{
+			{Label: "Retry", Active: true, Underline: 0},
+			{Label: "Skip", Active: false, Underline: 0},
+		}, w) + "\n")
+		help = []HelpItem{{Key: "r", Desc: "retry"}, {Key: "s", Desc: "skip"}, {Key: "esc", Desc: "back"}}
+	case verifyMissing:
+		b.WriteString(lipgloss.NewStyle().Foreground(style.Warning).Render(i18n.T("! Model %s is not installed", m.pullModel)) + "\n")
+		if m.verifyMsg != "" {
+			b.WriteString(style.Faint.Render("  "+m.verifyMsg) + "\n")
+		}
+		b.WriteString("\n" + style.Faint.Render(i18n.T("Pull it now from the local Ollama daemon?")) + "\n\n")
+		b.WriteString(RenderButtons([]ButtonDef{
+			{Label: "Pull", Active: true, Underline: 0},
+			{Label: "Skip", Active: false, Underline: 0},
+		}, w) + "\n")
+		help = []HelpItem{{Key: "p", Desc: "pull"}, {Key: "s", Desc: "skip"}, {Key: "esc", Desc: "back"}}
+	case verifyPulling:
+		b.WriteString(style.Bold.Render(i18n.T("Pulling %s", m.pullModel)) + "\n")
+		b.WriteString(m.pullProgressView(w-4) + "\n")
+		b.WriteString(style.Faint.Render(m.pullStatus) + "\n\n")
+		help = []HelpItem{{Key: "esc", Desc: "cancel"}}
+	}
+
+	b.WriteString(RenderHelpBar(help, w))
+
+	return style.DialogBorder.Width(w).Render(b.String())
+}
+
+// ── Step 7: Machines View ───────────────────────────────────
+
+func (m OnboardingModel) viewMachines() string {
+	w := m.boxWidth()
+	var b strings.Builder
+
+	b.WriteString(m.stepIndicator(6) + "\n\n")
 	b.WriteString(GradientTitle("Skill Groups") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n")
-	b.WriteString(style.Faint.Render("Enable tool groups for your agent:") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("Enable tool groups for your agent:")) + "\n\n")
 
 	for i, mach := range m.machines {
 		num := style.AgentName.Render(fmt.Sprintf("[%d]", i+1))
@@ -774,7 +1026,7 @@ func (m OnboardingModel) viewMachines() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(style.Faint.Render("  Core tools (file, shell, web) are always enabled.") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("  Core tools (file, shell, web) are always enabled.")) + "\n\n")
 
 	b.WriteString(RenderButtons([]ButtonDef{
 		{Label: "Continue", Active: true, Underline: -1},
@@ -789,16 +1041,16 @@ func (m OnboardingModel) viewMachines() string {
 	return style.DialogBorder.Width(w).Render(b.String())
 }
 
-// ── Step 7: Channels View ───────────────────────────────────
+// ── Step 8: Channels View ───────────────────────────────────
 
 func (m OnboardingModel) viewChannels() string {
 	w := m.boxWidth()
 	var b strings.Builder
 
-	b.WriteString(m.stepIndicator(6) + "\n\n")
+	b.WriteString(m.stepIndicator(7) + "\n\n")
 	b.WriteString(GradientTitle("Channels") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n")
-	b.WriteString(style.Faint.Render("Connect messaging platforms (configure tokens later):") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("Connect messaging platforms (configure tokens later):")) + "\n\n")
 
 	for i, ch := range m.channels {
 		num := style.AgentName.Render(fmt.Sprintf("[%d]", i+1))
@@ -812,7 +1064,7 @@ func (m OnboardingModel) viewChannels() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(style.Faint.Render("  Channel tokens configured after setup via /channels.") + "\n\n")
+	b.WriteString(style.Faint.Render(i18n.T("  Channel tokens configured after setup via /channels.")) + "\n\n")
 
 	b.WriteString(RenderButtons([]ButtonDef{
 		{Label: "Continue", Active: true, Underline: -1},
@@ -827,19 +1079,19 @@ func (m OnboardingModel) viewChannels() string {
 	return style.DialogBorder.Width(w).Render(b.String())
 }
 
-// ── Step 8: Confirm View ────────────────────────────────────
+// ── Step 9: Confirm View ────────────────────────────────────
 
 func (m OnboardingModel) viewConfirm() string {
 	w := m.boxWidth()
 	selected := m.selectedProvider()
 	var b strings.Builder
 
-	b.WriteString(m.stepIndicator(7) + "\n\n")
+	b.WriteString(m.stepIndicator(8) + "\n\n")
 	b.WriteString(GradientTitle("Confirm Setup") + "\n")
 	b.WriteString(style.Faint.Render(strings.Repeat("\u2500", 24)) + "\n\n")
 
 	// Summary
-	b.WriteString(style.Bold.Render("Agent:    ") + style.AgentName.Render(m.nameInput.Value) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Agent:    ")) + style.AgentName.Render(m.nameInput.Value) + "\n")
 
 	// User profile
 	if m.userNameInput.Value != "" || m.userContextInput.Value != "" {
@@ -850,19 +1102,19 @@ func (m OnboardingModel) viewConfirm() string {
 		if m.userContextInput.Value != "" {
 			profileParts = append(profileParts, m.userContextInput.Value)
 		}
-		b.WriteString(style.Bold.Render("User:     ") + style.Faint.Render(strings.Join(profileParts, " — ")) + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("User:     ")) + style.Faint.Render(strings.Join(profileParts, " — ")) + "\n")
 	}
 
 	// Template
 	if m.templateCursor == 0 {
-		b.WriteString(style.Bold.Render("Template: ") + style.Faint.Render("Blank") + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Template: ")) + style.Faint.Render(i18n.T("Blank")) + "\n")
 	} else if m.templateCursor <= len(m.templates) {
 		t := m.templates[m.templateCursor-1]
-		b.WriteString(style.Bold.Render("Template: ") + style.AgentName.Render(t.Name) + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Template: ")) + style.AgentName.Render(t.Name) + "\n")
 	}
 
-	b.WriteString(style.Bold.Render("Provider: ") + style.AgentName.Render(selected.Name) + "\n")
-	b.WriteString(style.Bold.Render("Model:    ") + style.Faint.Render(selected.DefaultModel) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Provider: ")) + style.AgentName.Render(selected.Name) + "\n")
+	b.WriteString(style.Bold.Render(i18n.T("Model:    ")) + style.Faint.Render(selected.DefaultModel) + "\n")
 
 	if m.keyInput.Value != "" {
 		b.WriteString(style.Bold.Render("API Key:  ") + style.Faint.Render("\u2022\u2022\u2022\u2022\u2022\u2022\u2022\u2022") + "\n")
@@ -870,6 +1122,13 @@ func (m OnboardingModel) viewConfirm() string {
 		b.WriteString(style.Bold.Render("API Key:  ") + style.Faint.Render("(from "+selected.EnvVar+")") + "\n")
 	}
 
+	switch m.verify {
+	case verifyOK:
+		b.WriteString(style.Bold.Render(i18n.T("Verified: ")) + style.TaskDone.Render(i18n.T("\u2713 connected")) + "\n")
+	case verifySkipped, verifyFailed:
+		b.WriteString(style.Bold.Render(i18n.T("Verified: ")) + style.Faint.Render(i18n.T("not verified")) + "\n")
+	}
+
 	// Machines
 	var enabledMachines []string
 	for _, mach := range m.machines {
@@ -878,9 +1137,9 @@ func (m OnboardingModel) viewConfirm() string {
 		}
 	}
 	if len(enabledMachines) > 0 {
-		b.WriteString(style.Bold.Render("Skills:   ") + style.Faint.Render(strings.Join(enabledMachines, ", ")) + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Skills:   ")) + style.Faint.Render(strings.Join(enabledMachines, ", ")) + "\n")
 	} else {
-		b.WriteString(style.Bold.Render("Skills:   ") + style.Faint.Render("Core only") + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Skills:   ")) + style.Faint.Render(i18n.T("Core only")) + "\n")
 	}
 
 	// Channels
@@ -891,13 +1150,13 @@ func (m OnboardingModel) viewConfirm() string {
 		}
 	}
 	if len(enabledChannels) > 0 {
-		b.WriteString(style.Bold.Render("Channels: ") + style.Faint.Render(strings.Join(enabledChannels, ", ")) + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Channels: ")) + style.Faint.Render(strings.Join(enabledChannels, ", ")) + "\n")
 	} else {
-		b.WriteString(style.Bold.Render("Channels: ") + style.Faint.Render("None (CLI only)") + "\n")
+		b.WriteString(style.Bold.Render(i18n.T("Channels: ")) + style.Faint.Render(i18n.T("None (CLI only)")) + "\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(style.Faint.Render("Files to write:") + "\n")
+	b.WriteString(style.Faint.Render(i18n.T("Files to write:")) + "\n")
 	b.WriteString(style.Faint.Render("  ~/.osa/config.json") + "\n")
 	b.WriteString(style.Faint.Render("  ~/.osa/IDENTITY.md") + "\n")
 	b.WriteString(style.Faint.Render("  ~/.osa/USER.md") + "\n")
@@ -905,7 +1164,7 @@ func (m OnboardingModel) viewConfirm() string {
 	b.WriteString("\n")
 
 	if m.err != "" {
-		b.WriteString(style.ErrorText.Render("Error: "+m.err) + "\n\n")
+		b.WriteString(style.ErrorText.Render(i18n.T("Error: %s", m.err)) + "\n\n")
 	}
 
 	b.WriteString(RenderButtons([]ButtonDef{
@@ -931,28 +1190,39 @@ func (m OnboardingModel) selectedProvider() msg.OnboardingProvider {
 	return msg.OnboardingProvider{Key: "ollama", Name: "Ollama", DefaultModel: "llama3.2:latest"}
 }
 
-func (m OnboardingModel) maskedKeyView() string {
-	val := m.keyInput.Value
-	if val == "" {
-		if m.keyInput.Focused {
-			return style.ButtonActive.Render(" ")
-		}
-		return style.Faint.Render("(empty)")
+// pullProgressView renders the pull progress bar with a byte counter.
+func (m OnboardingModel) pullProgressView(width int) string {
+	if m.pullTotal <= 0 {
+		return style.ContextBarRender(0, width-10)
 	}
+	frac := float64(m.pullCompleted) / float64(m.pullTotal)
+	return style.ContextBarRender(frac, width-10) + style.Faint.Render(fmt.Sprintf(" %3.0f%%", frac*100)) +
+		"\n" + style.Faint.Render(fmt.Sprintf("%s / %s", formatBytes(m.pullCompleted), formatBytes(m.pullTotal)))
+}
 
-	runes := []rune(val)
-	n := len(runes)
-	if n <= 4 {
-		masked := strings.Repeat("\u2022", n)
-		if m.keyInput.Focused {
-			return masked + style.ButtonActive.Render(" ")
-		}
-		return style.Faint.Render(masked)
+// formatBytes renders a byte count with a binary unit suffix.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
 
-	masked := strings.Repeat("\u2022", n-4) + string(runes[n-4:])
-	if m.keyInput.Focused {
-		return masked + style.ButtonActive.Render(" ")
+// defaultVerifyHint suggests a fix when the backend gives no remediation.
+func (m OnboardingModel) defaultVerifyHint() string {
+	selected := m.selectedProvider()
+	if selected.EnvVar == "" {
+		return fmt.Sprintf("make sure %s is running (e.g. `ollama serve`) and %s is available", selected.Name, selected.DefaultModel)
 	}
-	return style.Faint.Render(masked)
+	return fmt.Sprintf("check the key entered, or press esc and leave it empty to use %s", selected.EnvVar)
+}
+
+func (m OnboardingModel) maskedKeyView() string {
+	return m.keyInput.MaskedView()
 }
@@ -7,6 +7,7 @@ import (
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -23,6 +24,7 @@ type PaletteItem struct {
 	Name        string // e.g. "/help"
 	Description string // e.g. "Show available commands"
 	Category    string // e.g. "system"
+	Recent      bool   // listed in the "Recent" section
 }
 
 func (p PaletteItem) filterValue() string {
@@ -161,7 +163,7 @@ func (m PaletteModel) View() string {
 	sb.WriteString(m.filter.View())
 	sb.WriteByte('\n')
 
-	sb.WriteString(lipgloss.NewStyle().Foreground(style.Border).Render(strings.Repeat("─", boxWidth-4)))
+	sb.WriteString(lipgloss.NewStyle().Foreground(style.Border).Render(strings.Repeat("─", boxWidth-6)))
 	sb.WriteByte('\n')
 
 	visible := m.filtered
@@ -203,6 +205,17 @@ func (m PaletteModel) View() string {
 
 		isCursor := actualIdx == m.cursor
 
+		// Section headers where the recent commands start and end.
+		prevRecent := actualIdx > 0 && m.filtered[actualIdx-1].Recent
+		switch {
+		case item.Recent && !prevRecent:
+			sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Bold(true).Render(i18n.T("Recent")))
+			sb.WriteByte('\n')
+		case !item.Recent && prevRecent:
+			sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Bold(true).Render(i18n.T("All commands")))
+			sb.WriteByte('\n')
+		}
+
 		var line string
 		if isCursor {
 			marker := lipgloss.NewStyle().Foreground(style.Primary).Bold(true).Render("> ")
@@ -233,7 +246,7 @@ func (m PaletteModel) View() string {
 	}
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(boxWidth)
@@ -7,6 +7,7 @@ import (
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -147,7 +148,7 @@ func (m PermissionsModel) buildArgsContent() string {
 	if m.toolArgs != "" {
 		return lipgloss.NewStyle().Foreground(style.Secondary).Render(m.toolArgs)
 	}
-	return lipgloss.NewStyle().Foreground(style.Muted).Render("(no arguments)")
+	return lipgloss.NewStyle().Foreground(style.Muted).Render(i18n.T("(no arguments)"))
 }
 
 func (m PermissionsModel) buildUnifiedDiff() string {
@@ -230,7 +231,7 @@ func renderDiffLines(diff string) string {
 // generateSimpleDiff produces a minimal unified diff header from old/new content.
 func generateSimpleDiff(oldContent, newContent, filename string) string {
 	if oldContent == "" && newContent == "" {
-		return "(empty diff)"
+		return i18n.T("(empty diff)")
 	}
 	var sb strings.Builder
 	if filename != "" {
@@ -380,7 +381,7 @@ func (m PermissionsModel) View() string {
 	var sb strings.Builder
 
 	// Title row.
-	title := GradientTitle("Permission Request")
+	title := GradientTitle(i18n.T("Permission Request"))
 	sb.WriteString(title)
 	sb.WriteByte('\n')
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
@@ -399,14 +400,11 @@ func (m PermissionsModel) View() string {
 
 	// Diff mode toggle hint (only when diff is present).
 	if m.hasDiff {
-		var modeStr string
+		modeStr := i18n.T("diff: unified  ctrl+s to toggle")
 		if m.splitView {
-			modeStr = "split"
-		} else {
-			modeStr = "unified"
+			modeStr = i18n.T("diff: split  ctrl+s to toggle")
 		}
-		modeLabel := lipgloss.NewStyle().Foreground(style.Muted).
-			Render(fmt.Sprintf("  diff: %s  ctrl+s to toggle", modeStr))
+		modeLabel := lipgloss.NewStyle().Foreground(style.Muted).Render("  " + modeStr)
 		sb.WriteString(modeLabel)
 		sb.WriteByte('\n')
 	}
@@ -442,7 +440,7 @@ func (m PermissionsModel) View() string {
 
 	// Frame.
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Primary).
 		Padding(1, 2).
 		Width(dw)
@@ -6,6 +6,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -149,6 +150,14 @@ func (m PickerModel) Update(msg tea.Msg) (PickerModel, tea.Cmd) {
 	return m, nil
 }
 
+// Height returns the number of lines View renders; 0 when inactive.
+func (m PickerModel) Height() int {
+	if !m.active {
+		return 0
+	}
+	return lipgloss.Height(m.View())
+}
+
 // View renders the picker panel with a rounded border.
 func (m PickerModel) View() string {
 	if !m.active || len(m.items) == 0 {
@@ -161,10 +170,10 @@ func (m PickerModel) View() string {
 	header := lipgloss.NewStyle().
 		Foreground(style.Primary).
 		Bold(true).
-		Render("◈ Select Model")
+		Render("◈ " + i18n.T("Select Model"))
 	hint := lipgloss.NewStyle().
 		Foreground(style.Muted).
-		Render("  ↑↓ navigate · Enter select · Esc cancel")
+		Render("  " + i18n.T("↑↓ navigate · Enter select · Esc cancel"))
 	sb.WriteString(header + hint + "\n\n")
 
 	// Visible window
@@ -174,7 +183,7 @@ func (m PickerModel) View() string {
 	}
 
 	if m.offset > 0 {
-		sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render("  ↑ more above") + "\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render("  "+i18n.T("↑ more above")) + "\n")
 	}
 
 	lastProvider := ""
@@ -193,16 +202,16 @@ func (m PickerModel) View() string {
 	}
 
 	if end < len(m.items) {
-		sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render("  ↓ more below") + "\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render("  "+i18n.T("↓ more below")) + "\n")
 	}
 
 	countText := lipgloss.NewStyle().
 		Foreground(style.Muted).
-		Render(fmt.Sprintf("\n  %d model(s) available", len(m.items)))
+		Render("\n  " + i18n.T("%d model(s) available", len(m.items)))
 	sb.WriteString(countText)
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(0, 1)
 	if m.width > 0 {
@@ -246,7 +255,7 @@ func (m PickerModel) renderItem(item PickerItem, isCursor bool) string {
 	if item.Active {
 		activeLabel = lipgloss.NewStyle().
 			Foreground(style.Success).
-			Render("  active")
+			Render("  " + i18n.T("active"))
 	}
 
 	return cur + marker + " " + name + sizeBadge + activeLabel
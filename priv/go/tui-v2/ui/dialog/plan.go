@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/markdown"
 	"github.com/miosa/osa-tui/style"
 )
@@ -94,6 +96,14 @@ func (m PlanModel) Update(msg tea.Msg) (PlanModel, tea.Cmd) {
 	return m, nil
 }
 
+// Height returns the number of lines View renders; 0 when inactive.
+func (m PlanModel) Height() int {
+	if !m.active {
+		return 0
+	}
+	return lipgloss.Height(m.View())
+}
+
 // View renders the plan panel. Returns an empty string when inactive.
 func (m PlanModel) View() string {
 	if !m.active {
@@ -123,9 +133,9 @@ func buildPlanSelector(selected int) string {
 	var parts []string
 	for i, opt := range planOptions {
 		if i == selected {
-			parts = append(parts, style.PlanSelected.Render("> "+opt))
+			parts = append(parts, style.PlanSelected.Render("> "+i18n.T(opt)))
 		} else {
-			parts = append(parts, style.PlanUnselected.Render("○ "+opt))
+			parts = append(parts, style.PlanUnselected.Render("○ "+i18n.T(opt)))
 		}
 	}
 	return strings.Join(parts, "  ")
@@ -5,6 +5,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -88,7 +89,7 @@ func (m QuitModel) View() string {
 	sb.WriteByte('\n')
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
 	sb.WriteByte('\n')
-	sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render("Are you sure you want to quit?"))
+	sb.WriteString(lipgloss.NewStyle().Foreground(style.Muted).Render(i18n.T("Are you sure you want to quit?")))
 	sb.WriteByte('\n')
 	sb.WriteByte('\n')
 
@@ -109,7 +110,7 @@ func (m QuitModel) View() string {
 	sb.WriteString(RenderHelpBar(help, dw-6))
 
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Warning).
 		Padding(1, 2).
 		Width(dw)
@@ -5,6 +5,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -185,7 +186,7 @@ func (d *ReasoningDialog) Width() int { return 44 }
 func (d *ReasoningDialog) Height() int { return len(reasoningEntries) + 1 }
 
 // Title implements Dialog.
-func (d *ReasoningDialog) Title() string { return "Reasoning Level" }
+func (d *ReasoningDialog) Title() string { return i18n.T("Reasoning Level") }
 
 // ──────────────────────────────────────────────────────────────────────────────
 // Shared rendering
@@ -195,7 +196,7 @@ func (d *ReasoningDialog) Title() string { return "Reasoning Level" }
 func renderReasoningContent(cursor int, current ReasoningLevel) string {
 	var sb strings.Builder
 
-	hint := lipgloss.NewStyle().Foreground(style.Muted).Render("↑↓ navigate · Enter select · Esc cancel")
+	hint := lipgloss.NewStyle().Foreground(style.Muted).Render(i18n.T("↑↓ navigate · Enter select · Esc cancel"))
 	sb.WriteString(hint + "\n\n")
 
 	for i, entry := range reasoningEntries {
@@ -209,17 +210,18 @@ func renderReasoningContent(cursor int, current ReasoningLevel) string {
 			marker = "  "
 		}
 
+		label := i18n.T(entry.level.String())
 		var levelStr string
 		if isCursor {
-			levelStr = style.PlanSelected.Render(entry.level.String())
+			levelStr = style.PlanSelected.Render(label)
 		} else if isCurrent {
-			levelStr = lipgloss.NewStyle().Foreground(style.Secondary).Render(entry.level.String())
+			levelStr = lipgloss.NewStyle().Foreground(style.Secondary).Render(label)
 		} else {
-			levelStr = style.Faint.Render(entry.level.String())
+			levelStr = style.Faint.Render(label)
 		}
 
 		// Pad the level label to a fixed width so descriptions align.
-		padded := padRight(entry.level.String(), 8)
+		padded := padRight(label, 8)
 		if isCursor {
 			padded = style.PlanSelected.Render(padded)
 		} else if isCurrent {
@@ -229,14 +231,14 @@ func renderReasoningContent(cursor int, current ReasoningLevel) string {
 		}
 		// Override with the styled levelStr for cursor to get bold.
 		if isCursor {
-			padded = levelStr + strings.Repeat(" ", max(0, 8-len(entry.level.String())))
+			padded = levelStr + strings.Repeat(" ", max(0, 8-len(label)))
 		}
 
 		var desc string
 		if isCursor {
-			desc = lipgloss.NewStyle().Foreground(style.Muted).Render(entry.desc)
+			desc = lipgloss.NewStyle().Foreground(style.Muted).Render(i18n.T(entry.desc))
 		} else {
-			desc = style.Faint.Render(entry.desc)
+			desc = style.Faint.Render(i18n.T(entry.desc))
 		}
 
 		// Active indicator badge.
@@ -0,0 +1,245 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// RosterEntry is one agent role shown by RosterModel.
+type RosterEntry struct {
+	Name        string
+	Tier        string // "elite", "specialist" or "utility"
+	Role        string
+	Model       string
+	Description string
+	Tools       []string
+	Triggers    []string
+}
+
+// RosterChoice is emitted when the user picks an agent with Enter, to direct
+// the next prompt at it.
+type RosterChoice struct{ Name string }
+
+// RosterModel is the filterable agent roster opened by /agents, with the
+// details of the agent under the cursor below the list.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type RosterModel struct {
+	entries    []RosterEntry
+	filtered   []RosterEntry
+	cursor     int
+	offset     int
+	filterText string
+
+	width, height int
+	pageSize      int
+}
+
+// NewRoster returns an empty RosterModel.
+func NewRoster() RosterModel {
+	return RosterModel{pageSize: 10}
+}
+
+// SetEntries populates the roster, filtered by filter, such as a name given
+// to /agents.
+func (m *RosterModel) SetEntries(entries []RosterEntry, filter string) {
+	m.entries = entries
+	m.filterText = filter
+	m.applyFilter()
+}
+
+// SetSize updates terminal dimensions.
+func (m *RosterModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-22, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the roster.
+//
+//	↑/↓       → move cursor
+//	enter     → direct the next prompt at the selected agent
+//	esc       → dismiss dialog (no action emitted)
+//	any char  → append to filter
+//	backspace → remove last filter char
+func (m RosterModel) Update(message tea.Msg) (RosterModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch kp.Code {
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeyEnter:
+		if m.cursor < len(m.filtered) {
+			name := m.filtered[m.cursor].Name
+			return m, func() tea.Msg { return RosterChoice{Name: name} }
+		}
+	case tea.KeyBackspace:
+		if m.filterText != "" {
+			runes := []rune(m.filterText)
+			m.filterText = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	default:
+		if kp.Code >= 32 && kp.Code != tea.KeyDelete && kp.Code < 127 {
+			m.filterText += string(rune(kp.Code))
+			m.applyFilter()
+		}
+	}
+	return m, nil
+}
+
+// applyFilter keeps the agents whose name, role, tier or description contain
+// the filter text.
+func (m *RosterModel) applyFilter() {
+	m.filtered = m.filtered[:0]
+	q := strings.ToLower(m.filterText)
+	for _, e := range m.entries {
+		hay := strings.ToLower(e.Name + " " + e.Role + " " + e.Tier + " " + e.Description)
+		if strings.Contains(hay, q) {
+			m.filtered = append(m.filtered, e)
+		}
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *RosterModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the roster dialog.
+func (m RosterModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Agents")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	filterVal := style.Faint.Render(i18n.T("type to filter..."))
+	if m.filterText != "" {
+		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(m.filterText)
+	}
+	sb.WriteString(style.DialogHelpKey.Render(i18n.T("Filter: ")) + filterVal)
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.filtered) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No agents found")))
+		sb.WriteByte('\n')
+	} else {
+		end := min(m.offset+m.pageSize, len(m.filtered))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.filtered[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.filtered) {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.cursor < len(m.filtered) {
+		e := m.filtered[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if e.Description != "" {
+			sb.WriteString(lipgloss.NewStyle().Width(inner).Render(e.Description))
+			sb.WriteByte('\n')
+		}
+		detail := func(label string, values []string) {
+			if len(values) == 0 {
+				return
+			}
+			label = i18n.T(label)
+			line := ansi.Truncate(strings.Join(values, ", "), inner-lipgloss.Width(label), "…")
+			sb.WriteString(style.DialogHelpKey.Render(label) + style.Faint.Render(line))
+			sb.WriteByte('\n')
+		}
+		detail("Tools: ", e.Tools)
+		detail("Triggers: ", e.Triggers)
+		sb.WriteString(style.Faint.Render(i18n.T("Enter to send the next prompt as @%s <prompt>", e.Name)))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "enter", Desc: "address"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderEntry renders a single agent row: name, tier and model.
+func (m RosterModel) renderEntry(e RosterEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+
+	nameW := min(26, width/3)
+	name := fmt.Sprintf("%-*s", nameW, ansi.Truncate(e.Name, nameW, "…"))
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+	tier := fmt.Sprintf("%-11s", e.Tier)
+	model := ansi.Truncate(e.Model, max(width-nameW-2-12, 8), "…")
+
+	return cursor + name + " " + style.Faint.Render(tier) + " " + style.Faint.Render(model)
+}
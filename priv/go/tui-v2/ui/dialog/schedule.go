@@ -0,0 +1,293 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// ScheduleEntry is one scheduled job shown by ScheduleModel.
+type ScheduleEntry struct {
+	ID          string
+	Name        string
+	When        string // schedule phrase, or the cron expression
+	Prompt      string
+	Upcoming    []time.Time // next runs, local time; empty when none within a year
+	Enabled     bool
+	CircuitOpen bool // disabled by the backend after repeated failures
+}
+
+// ScheduleAction is emitted by the schedule manager.
+// Action is one of: "toggle", "delete".
+type ScheduleAction struct {
+	Action  string
+	ID      string
+	Name    string
+	Enabled bool // new state, only for "toggle"
+}
+
+// ScheduleModel is the scheduled prompts manager opened by /schedule.
+//
+// Pressing Esc outside of a confirmation emits nothing and the caller should
+// dismiss the dialog.
+type ScheduleModel struct {
+	entries    []ScheduleEntry
+	cursor     int
+	offset     int
+	delConfirm bool
+	status     string
+	statusErr  bool
+
+	width, height int
+	pageSize      int
+}
+
+// NewSchedule returns an empty ScheduleModel.
+func NewSchedule() ScheduleModel {
+	return ScheduleModel{pageSize: 12}
+}
+
+// SetEntries populates the dialog, keeping the cursor on the same job when
+// the list is refreshed after an action.
+func (m *ScheduleModel) SetEntries(entries []ScheduleEntry) {
+	prev := ""
+	if m.cursor < len(m.entries) {
+		prev = m.entries[m.cursor].ID
+	}
+	m.entries = entries
+	m.cursor = min(m.cursor, max(len(entries)-1, 0))
+	for i, e := range entries {
+		if e.ID == prev {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// SetStatus shows a one-line status message below the list.
+func (m *ScheduleModel) SetStatus(text string, isErr bool) {
+	m.status = text
+	m.statusErr = isErr
+}
+
+// Reset clears transient confirmation and status state before the dialog is
+// opened.
+func (m *ScheduleModel) Reset() {
+	m.delConfirm = false
+	m.status = ""
+	m.statusErr = false
+}
+
+// Busy reports whether a confirmation is showing, in which case Esc is
+// handled internally rather than closing the dialog.
+func (m ScheduleModel) Busy() bool { return m.delConfirm }
+
+// SetSize updates terminal dimensions.
+func (m *ScheduleModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max((h-16)/2, 3)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the schedule manager.
+//
+//	↑/k, ↓/j   → move cursor
+//	space/e    → enable or disable the selected job
+//	d/delete   → prompt remove confirmation (y / enter confirms)
+//	esc        → dismiss dialog (no action emitted)
+func (m ScheduleModel) Update(message tea.Msg) (ScheduleModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.delConfirm {
+		m.delConfirm = false
+		if (kp.Code != 'y' && kp.Code != tea.KeyEnter) || m.cursor >= len(m.entries) {
+			return m, nil
+		}
+		e := m.entries[m.cursor]
+		m.status = i18n.T("Removing %s...", e.Name)
+		m.statusErr = false
+		return m, func() tea.Msg { return ScheduleAction{Action: "delete", ID: e.ID, Name: e.Name} }
+	}
+
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown, 'j':
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeySpace, 'e':
+		if m.cursor < len(m.entries) {
+			e := m.entries[m.cursor]
+			enable := !e.Enabled || e.CircuitOpen
+			return m, func() tea.Msg {
+				return ScheduleAction{Action: "toggle", ID: e.ID, Name: e.Name, Enabled: enable}
+			}
+		}
+	case 'd', tea.KeyDelete:
+		if m.cursor < len(m.entries) {
+			m.delConfirm = true
+		}
+	}
+	return m, nil
+}
+
+func (m *ScheduleModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the schedule manager dialog.
+func (m ScheduleModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Scheduled Prompts")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.entries) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T(`No scheduled jobs. Add one with /schedule "every weekday 9am" <prompt>`)))
+		sb.WriteByte('\n')
+	} else {
+		end := min(m.offset+m.pageSize, len(m.entries))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.entries[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.entries) {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.cursor < len(m.entries) {
+		e := m.entries[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if e.Prompt != "" {
+			sb.WriteString(style.Faint.Render(ansi.Truncate(strings.ReplaceAll(e.Prompt, "\n", " "), inner, "…")))
+			sb.WriteByte('\n')
+		}
+		var runs []string
+		for _, t := range e.Upcoming {
+			runs = append(runs, t.Format("Mon Jan 2 15:04"))
+		}
+		if len(runs) == 0 {
+			runs = []string{i18n.T("none within a year")}
+		}
+		sb.WriteString(style.DialogHelpKey.Render(i18n.T("Upcoming: ")) + style.Faint.Render(strings.Join(runs, " · ")))
+		sb.WriteByte('\n')
+	}
+
+	if m.delConfirm && m.cursor < len(m.entries) {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		sb.WriteString(style.ErrorText.Render(i18n.T("Remove %s? ", m.entries[m.cursor].Name)))
+		sb.WriteString(style.DialogHelp.Render(i18n.T("y to confirm · any key to cancel")))
+		sb.WriteByte('\n')
+	}
+
+	if m.status != "" {
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if m.statusErr {
+			sb.WriteString(style.ErrorText.Render(m.status))
+		} else {
+			sb.WriteString(style.Faint.Render(m.status))
+		}
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "space", Desc: "enable/disable"},
+		{Key: "d", Desc: "remove"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderEntry renders a single job row: state, name, schedule and next run.
+func (m ScheduleModel) renderEntry(e ScheduleEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+
+	var mark, next string
+	switch {
+	case e.CircuitOpen:
+		mark = style.ErrorText.Render("✗ ")
+		next = style.ErrorText.Render(i18n.T("failing"))
+	case !e.Enabled:
+		mark = style.RadioOff.Render("○ ")
+		next = style.Faint.Render(i18n.T("paused"))
+	default:
+		mark = style.RadioOn.Render("● ")
+		if len(e.Upcoming) > 0 {
+			next = style.Faint.Render(e.Upcoming[0].Format("Mon 15:04"))
+		}
+	}
+
+	nameW := min(24, width/3)
+	name := fmt.Sprintf("%-*s", nameW, ansi.Truncate(e.Name, nameW, "…"))
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+	whenW := max(width-nameW-4-12, 8)
+	when := fmt.Sprintf("%-*s", whenW, ansi.Truncate(e.When, whenW, "…"))
+
+	return cursor + mark + name + " " + when + " " + next
+}
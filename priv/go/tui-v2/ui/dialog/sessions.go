@@ -1,11 +1,11 @@
 package dialog
 
 import (
-	"fmt"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -293,16 +293,16 @@ func (m SessionsModel) View() string {
 	var sb strings.Builder
 
 	// Title.
-	sb.WriteString(GradientTitle("Sessions"))
+	sb.WriteString(GradientTitle(i18n.T("Sessions")))
 	sb.WriteByte('\n')
 	sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
 	sb.WriteByte('\n')
 
 	// Filter box.
-	filterPrompt := style.DialogHelpKey.Render("Filter: ")
+	filterPrompt := style.DialogHelpKey.Render(i18n.T("Filter: "))
 	filterVal := m.filterText
 	if filterVal == "" {
-		filterVal = style.Faint.Render("type to filter...")
+		filterVal = style.Faint.Render(i18n.T("type to filter..."))
 	} else {
 		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(filterVal)
 	}
@@ -315,8 +315,8 @@ func (m SessionsModel) View() string {
 	if m.delConfirm && m.cursor < len(m.filtered) {
 		entry := m.filtered[m.cursor]
 		warning := style.ErrorText.Render(
-			fmt.Sprintf("Delete \"%s\"? ", entry.Title))
-		hint := style.DialogHelp.Render("y to confirm · any key to cancel")
+			i18n.T("Delete \"%s\"? ", entry.Title))
+		hint := style.DialogHelp.Render(i18n.T("y to confirm · any key to cancel"))
 		sb.WriteString(warning + hint)
 		sb.WriteByte('\n')
 		sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
@@ -325,7 +325,7 @@ func (m SessionsModel) View() string {
 
 	// Session list.
 	if len(m.filtered) == 0 {
-		sb.WriteString(style.Faint.Render("  No sessions found"))
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No sessions found")))
 		sb.WriteByte('\n')
 	} else {
 		end := m.offset + m.pageSize
@@ -333,7 +333,7 @@ func (m SessionsModel) View() string {
 			end = len(m.filtered)
 		}
 		if m.offset > 0 {
-			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
 			sb.WriteByte('\n')
 		}
 		for i := m.offset; i < end; i++ {
@@ -343,7 +343,7 @@ func (m SessionsModel) View() string {
 			sb.WriteByte('\n')
 		}
 		if end < len(m.filtered) {
-			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
 			sb.WriteByte('\n')
 		}
 	}
@@ -352,7 +352,7 @@ func (m SessionsModel) View() string {
 	if m.renaming {
 		sb.WriteString(style.DiffContext.Render(strings.Repeat("─", dw-6)))
 		sb.WriteByte('\n')
-		prompt := style.DialogHelpKey.Render("Rename: ")
+		prompt := style.DialogHelpKey.Render(i18n.T("Rename: "))
 		sb.WriteString(prompt + m.renameInput.View())
 		sb.WriteByte('\n')
 	}
@@ -371,7 +371,7 @@ func (m SessionsModel) View() string {
 	sb.WriteString(RenderHelpBar(helpItems, dw-6))
 
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(1, 2).
 		Width(dw)
@@ -417,7 +417,7 @@ func (m SessionsModel) renderEntry(entry SessionEntry, isCursor bool) string {
 			parts = append(parts, entry.CreatedAt)
 		}
 		if entry.MessageCount > 0 {
-			parts = append(parts, fmt.Sprintf("%d msgs", entry.MessageCount))
+			parts = append(parts, i18n.T("%d msgs", entry.MessageCount))
 		}
 		meta = style.Faint.Render("  " + strings.Join(parts, " · "))
 	}
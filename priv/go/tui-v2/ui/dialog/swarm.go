@@ -0,0 +1,312 @@
+package dialog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// SwarmLaunch is emitted when the swarm wizard is confirmed. Pattern is ""
+// to let the backend's planner pick one.
+type SwarmLaunch struct {
+	Pattern   string
+	Task      string
+	MaxAgents int
+	TimeoutMs int
+}
+
+// swarmStep tracks which wizard screen is active.
+type swarmStep int
+
+const (
+	swarmStepPattern swarmStep = iota // 1. Coordination pattern
+	swarmStepAgents                   // 2. Agent count
+	swarmStepTask                     // 3. Task description
+	swarmStepBudget                   // 4. Time budget
+	swarmStepConfirm                  // 5. Review + launch
+)
+
+const (
+	maxSwarmAgents    = 10 // the backend's per-swarm cap
+	maxSwarmBudgetMin = 60
+)
+
+// swarmPatterns are the coordination patterns the backend accepts, after
+// the automatic choice.
+var swarmPatterns = []struct{ name, desc string }{
+	{"", "Let the planner pick from the task"},
+	{"parallel", "Agents work independently; results are merged"},
+	{"pipeline", "Each agent builds on the previous one's output"},
+	{"debate", "Agents propose; the last one weighs the proposals"},
+	{"review", "A coder works and a reviewer checks, in rounds"},
+}
+
+// SwarmWizardModel is the guided dialog opened by /swarm new. It steps
+// through the pattern, the agent count, the task and the time budget, then
+// emits SwarmLaunch.
+//
+// Esc goes back a step. On the first step it emits nothing and the caller
+// should dismiss the dialog.
+type SwarmWizardModel struct {
+	step    swarmStep
+	pattern int
+	agents  InputCursor
+	task    InputCursor
+	budget  InputCursor // minutes
+	err     string
+
+	width, height int
+}
+
+// NewSwarmWizard returns a wizard on its first step, with task prefilled
+// from the arguments of /swarm new.
+func NewSwarmWizard(task string) SwarmWizardModel {
+	m := SwarmWizardModel{}
+	m.agents.SetValue("5")
+	m.task.SetValue(task)
+	m.budget.SetValue("5")
+	return m
+}
+
+// Busy reports whether Esc steps back rather than dismissing the dialog.
+func (m SwarmWizardModel) Busy() bool { return m.step > swarmStepPattern }
+
+// SetSize updates terminal dimensions.
+func (m *SwarmWizardModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Update handles keyboard input for the wizard.
+//
+//	↑/↓   → pick a pattern (first step)
+//	enter → next step, or launch on the last one
+//	esc   → previous step
+func (m SwarmWizardModel) Update(message tea.Msg) (SwarmWizardModel, tea.Cmd) {
+	var text string
+	switch v := message.(type) {
+	case tea.PasteMsg:
+		text = v.Content
+	case tea.KeyPressMsg:
+		switch v.Code {
+		case tea.KeyEscape:
+			if m.step > swarmStepPattern {
+				m.step--
+				m.err = ""
+			}
+			return m, nil
+		case tea.KeyEnter:
+			return m.next()
+		case tea.KeyUp:
+			if m.step == swarmStepPattern && m.pattern > 0 {
+				m.pattern--
+			}
+			return m, nil
+		case tea.KeyDown:
+			if m.step == swarmStepPattern && m.pattern < len(swarmPatterns)-1 {
+				m.pattern++
+			}
+			return m, nil
+		case tea.KeyBackspace:
+			if in := m.input(); in != nil {
+				in.Backspace()
+			}
+			return m, nil
+		}
+		text = v.Text
+	default:
+		return m, nil
+	}
+	if in := m.input(); in != nil {
+		for _, r := range strings.ReplaceAll(text, "\n", " ") {
+			in.Insert(r)
+		}
+	}
+	return m, nil
+}
+
+// input returns the text input of the current step, or nil.
+func (m *SwarmWizardModel) input() *InputCursor {
+	switch m.step {
+	case swarmStepAgents:
+		return &m.agents
+	case swarmStepTask:
+		return &m.task
+	case swarmStepBudget:
+		return &m.budget
+	}
+	return nil
+}
+
+// next validates the current step and moves on, or launches from the
+// review step.
+func (m SwarmWizardModel) next() (SwarmWizardModel, tea.Cmd) {
+	m.err = ""
+	switch m.step {
+	case swarmStepAgents:
+		if _, ok := parseRange(m.agents.Value, 1, maxSwarmAgents); !ok {
+			m.err = i18n.T("Enter a number of agents from 1 to %d", maxSwarmAgents)
+			return m, nil
+		}
+	case swarmStepTask:
+		if strings.TrimSpace(m.task.Value) == "" {
+			m.err = i18n.T("Describe the task for the swarm")
+			return m, nil
+		}
+	case swarmStepBudget:
+		if _, ok := parseRange(m.budget.Value, 1, maxSwarmBudgetMin); !ok {
+			m.err = i18n.T("Enter a time budget from 1 to %d minutes", maxSwarmBudgetMin)
+			return m, nil
+		}
+	case swarmStepConfirm:
+		agents, _ := parseRange(m.agents.Value, 1, maxSwarmAgents)
+		minutes, _ := parseRange(m.budget.Value, 1, maxSwarmBudgetMin)
+		launch := SwarmLaunch{
+			Pattern:   swarmPatterns[m.pattern].name,
+			Task:      strings.TrimSpace(m.task.Value),
+			MaxAgents: agents,
+			TimeoutMs: minutes * 60_000,
+		}
+		return m, func() tea.Msg { return launch }
+	}
+	m.step++
+	return m, nil
+}
+
+// parseRange parses s as an integer in [lo, hi].
+func parseRange(s string, lo, hi int) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	return n, err == nil && n >= lo && n <= hi
+}
+
+// patternLabel is the display name of the pattern at i.
+func patternLabel(i int) string {
+	if swarmPatterns[i].name == "" {
+		return i18n.T("auto")
+	}
+	return swarmPatterns[i].name
+}
+
+func (m SwarmWizardModel) stepIndicator() string {
+	var parts []string
+	labels := []string{"Pattern", "Agents", "Task", "Budget", "Launch"}
+	for i, label := range labels {
+		label = i18n.T(label)
+		num := fmt.Sprintf("%d", i+1)
+		switch {
+		case i == int(m.step):
+			parts = append(parts, style.RadioOn.Render(num)+style.Bold.Render(" "+label))
+		case i < int(m.step):
+			parts = append(parts, style.TaskDone.Render(num+" "+label))
+		default:
+			parts = append(parts, style.Faint.Render(num+" "+label))
+		}
+	}
+	return style.Faint.Render(strings.Join(parts, " · "))
+}
+
+// View renders the wizard centered on screen.
+func (m SwarmWizardModel) View() string {
+	dw := m.width - 4
+	if dw > 72 {
+		dw = 72
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("New swarm")))
+	sb.WriteByte('\n')
+	sb.WriteString(m.stepIndicator())
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	field := func(prompt string, in InputCursor, hint string) {
+		sb.WriteString(style.Bold.Render(prompt))
+		sb.WriteString("\n\n")
+		in.Focused = true
+		in.Width = inner
+		sb.WriteString(in.View())
+		sb.WriteByte('\n')
+		sb.WriteString(style.Faint.Render(hint))
+		sb.WriteByte('\n')
+	}
+
+	switch m.step {
+	case swarmStepPattern:
+		sb.WriteString(style.Bold.Render(i18n.T("How should the agents work together?")))
+		sb.WriteString("\n\n")
+		for i, p := range swarmPatterns {
+			name := fmt.Sprintf("%-10s", patternLabel(i))
+			if i == m.pattern {
+				sb.WriteString(style.PlanSelected.Render("> ") +
+					lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name))
+			} else {
+				sb.WriteString("  " + style.Faint.Render(name))
+			}
+			sb.WriteString(" " + style.Faint.Render(i18n.T(p.desc)))
+			sb.WriteByte('\n')
+		}
+	case swarmStepAgents:
+		field(i18n.T("How many agents, at most?"), m.agents,
+			i18n.T("1 to %d; the planner may use fewer", maxSwarmAgents))
+	case swarmStepTask:
+		field(i18n.T("What should the swarm do?"), m.task,
+			i18n.T("The planner splits it into a subtask per agent"))
+	case swarmStepBudget:
+		field(i18n.T("Time budget, in minutes"), m.budget,
+			i18n.T("The swarm is stopped when it runs out"))
+	case swarmStepConfirm:
+		sb.WriteString(style.Bold.Render(i18n.T("Launch this swarm?")))
+		sb.WriteString("\n\n")
+		row := func(label, value string) {
+			sb.WriteString(style.DialogHelpKey.Render(fmt.Sprintf("%-9s", i18n.T(label))))
+			sb.WriteString(lipgloss.NewStyle().Width(inner - 9).Render(value))
+			sb.WriteByte('\n')
+		}
+		row("Pattern", patternLabel(m.pattern))
+		row("Agents", strings.TrimSpace(m.agents.Value))
+		row("Task", strings.TrimSpace(m.task.Value))
+		row("Budget", i18n.T("%s min", strings.TrimSpace(m.budget.Value)))
+	}
+	if m.err != "" {
+		sb.WriteString(style.ErrorText.Render(m.err))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	items := []HelpItem{{Key: "enter", Desc: "next"}, {Key: "esc", Desc: "back"}}
+	switch m.step {
+	case swarmStepPattern:
+		items = []HelpItem{{Key: "↑↓", Desc: "choose"}, {Key: "enter", Desc: "next"}, {Key: "esc", Desc: "cancel"}}
+	case swarmStepConfirm:
+		items[0].Desc = "launch"
+	}
+	sb.WriteString(RenderHelpBar(items, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
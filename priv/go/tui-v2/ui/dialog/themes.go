@@ -0,0 +1,235 @@
+package dialog
+
+import (
+	"image/color"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// ThemeItem is a single entry in the theme picker.
+type ThemeItem struct {
+	Name   string
+	Detail string // e.g. "custom" or "follow terminal, now dark"
+	Active bool
+}
+
+// ThemePreview is emitted when the cursor moves onto a theme, so the app can
+// show it on the chat behind the picker.
+type ThemePreview struct{ Name string }
+
+// ThemeChoice is emitted when the user applies a theme with Enter.
+type ThemeChoice struct{ Name string }
+
+// ThemeCancel is emitted when the user presses Esc; the theme in use when
+// the picker opened should be restored.
+type ThemeCancel struct{}
+
+// ThemePickerModel lists the themes in place of the input, previewing each
+// one as the cursor reaches it.
+type ThemePickerModel struct {
+	items    []ThemeItem
+	notes    []string // shown below the list, e.g. skipped theme files
+	cursor   int
+	active   bool
+	width    int
+	offset   int
+	pageSize int
+}
+
+// NewThemePicker returns a zero-value ThemePickerModel.
+func NewThemePicker() ThemePickerModel {
+	return ThemePickerModel{pageSize: 10}
+}
+
+// Open populates and activates the picker with the cursor on the active
+// theme. notes are shown faintly below the list.
+func (m *ThemePickerModel) Open(items []ThemeItem, notes []string) {
+	m.items = items
+	m.notes = notes
+	m.cursor = 0
+	m.offset = 0
+	m.active = true
+	for i, item := range items {
+		if item.Active {
+			m.cursor = i
+			break
+		}
+	}
+	m.scroll()
+}
+
+// Clear deactivates the picker.
+func (m *ThemePickerModel) Clear() {
+	m.active = false
+	m.items = nil
+	m.notes = nil
+	m.cursor = 0
+	m.offset = 0
+}
+
+// IsActive reports whether the picker is currently visible.
+func (m ThemePickerModel) IsActive() bool { return m.active }
+
+// SetWidth constrains the picker to the terminal width.
+func (m *ThemePickerModel) SetWidth(w int) { m.width = w }
+
+// Update handles keyboard and mouse input when the picker is active.
+func (m ThemePickerModel) Update(msg tea.Msg) (ThemePickerModel, tea.Cmd) {
+	if !m.active || len(m.items) == 0 {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseWheelMsg:
+		switch msg.Button {
+		case tea.MouseWheelUp:
+			return m.move(-1, false)
+		case tea.MouseWheelDown:
+			return m.move(1, false)
+		}
+
+	case tea.KeyPressMsg:
+		switch msg.Code {
+		case tea.KeyUp:
+			return m.move(-1, true)
+		case tea.KeyDown:
+			return m.move(1, true)
+		case tea.KeyHome:
+			return m.move(-m.cursor, false)
+		case tea.KeyEnd:
+			return m.move(len(m.items)-1-m.cursor, false)
+
+		case tea.KeyEnter:
+			name := m.items[m.cursor].Name
+			m.Clear()
+			return m, func() tea.Msg { return ThemeChoice{Name: name} }
+
+		case tea.KeyEscape:
+			m.Clear()
+			return m, func() tea.Msg { return ThemeCancel{} }
+		}
+	}
+	return m, nil
+}
+
+// move shifts the cursor by delta, wrapping around the ends when wrap is
+// set, and previews the theme it lands on.
+func (m ThemePickerModel) move(delta int, wrap bool) (ThemePickerModel, tea.Cmd) {
+	n := len(m.items)
+	next := m.cursor + delta
+	switch {
+	case wrap:
+		next = (next%n + n) % n
+	case next < 0:
+		next = 0
+	case next >= n:
+		next = n - 1
+	}
+	if next == m.cursor {
+		return m, nil
+	}
+	m.cursor = next
+	m.scroll()
+	name := m.items[next].Name
+	return m, func() tea.Msg { return ThemePreview{Name: name} }
+}
+
+// scroll keeps the cursor inside the visible window.
+func (m *ThemePickerModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+}
+
+// Height returns the number of lines View renders; 0 when inactive.
+func (m ThemePickerModel) Height() int {
+	if !m.active {
+		return 0
+	}
+	return lipgloss.Height(m.View())
+}
+
+// View renders the picker panel with a rounded border.
+func (m ThemePickerModel) View() string {
+	if !m.active || len(m.items) == 0 {
+		return ""
+	}
+	muted := lipgloss.NewStyle().Foreground(style.Muted)
+
+	var sb strings.Builder
+	header := lipgloss.NewStyle().
+		Foreground(style.Primary).
+		Bold(true).
+		Render("◈ " + i18n.T("Select Theme"))
+	sb.WriteString(header + muted.Render("  "+i18n.T("↑↓ preview · Enter apply · Esc revert")) + "\n\n")
+
+	end := min(m.offset+m.pageSize, len(m.items))
+	if m.offset > 0 {
+		sb.WriteString(muted.Render("  "+i18n.T("↑ more above")) + "\n")
+	}
+	for i := m.offset; i < end; i++ {
+		sb.WriteString(m.renderItem(m.items[i], i == m.cursor))
+		sb.WriteByte('\n')
+	}
+	if end < len(m.items) {
+		sb.WriteString(muted.Render("  "+i18n.T("↓ more below")) + "\n")
+	}
+	for _, note := range m.notes {
+		sb.WriteString("\n" + style.Faint.Render("  "+note))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(0, 1)
+	if m.width > 0 {
+		boxStyle = boxStyle.Width(m.width - 2)
+	}
+	return boxStyle.Render(strings.TrimRight(sb.String(), "\n"))
+}
+
+// renderItem renders a single theme entry line: cursor, active marker, name,
+// detail and a swatch of the theme's main colors.
+func (m ThemePickerModel) renderItem(item ThemeItem, isCursor bool) string {
+	cur := "    "
+	if isCursor {
+		cur = lipgloss.NewStyle().Foreground(style.Primary).Bold(true).Render("  > ")
+	}
+
+	marker := lipgloss.NewStyle().Foreground(style.Muted).Render("○")
+	if item.Active {
+		marker = lipgloss.NewStyle().Foreground(style.Success).Render("●")
+	}
+
+	nameStyle := lipgloss.NewStyle()
+	if isCursor {
+		nameStyle = nameStyle.Bold(true)
+	}
+	line := cur + marker + " " + nameStyle.Render(padRight(item.Name, 14))
+	if isCursor {
+		line += " " + swatch()
+	} else {
+		line += strings.Repeat(" ", 6) // keep details aligned with the swatch row
+	}
+	if item.Detail != "" {
+		line += lipgloss.NewStyle().Foreground(style.Muted).Render(" " + item.Detail)
+	}
+	return line
+}
+
+// swatch shows the current palette's accent colors as blocks. While
+// previewing, that is the theme under the cursor.
+func swatch() string {
+	var sb strings.Builder
+	for _, c := range []color.Color{style.Primary, style.Secondary, style.Success, style.Warning, style.Error} {
+		sb.WriteString(lipgloss.NewStyle().Foreground(c).Render("■"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,323 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+)
+
+// TimelineEntry is one chat message shown by TimelineModel.
+type TimelineEntry struct {
+	Index   int    // position in the chat, passed back in TimelineJump
+	Role    string // "user" or "agent"
+	Time    time.Time
+	Excerpt string
+}
+
+// TimelineJump is emitted when a message is chosen in the timeline.
+type TimelineJump struct{ Index int }
+
+// densityBars draws bucket counts from low to high.
+var densityBars = []rune("▁▂▃▄▅▆▇█")
+
+// TimelineModel is the session timeline opened by /timeline: a density strip
+// of messages over time above the list of messages, for jumping through long
+// sessions.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type TimelineModel struct {
+	entries []TimelineEntry
+	bucket  []int // bucket of each entry
+	counts  []int // entries per bucket
+	cursor  int
+	offset  int
+
+	width, height int
+	pageSize      int
+}
+
+// NewTimeline returns an empty TimelineModel.
+func NewTimeline() TimelineModel {
+	return TimelineModel{pageSize: 10}
+}
+
+// SetEntries populates the timeline, in chat order, and puts the cursor on
+// the latest message.
+func (m *TimelineModel) SetEntries(entries []TimelineEntry) {
+	m.entries = entries
+	m.cursor = max(len(entries)-1, 0)
+	m.offset = 0
+	m.layoutBuckets()
+	m.scrollToCursor()
+}
+
+// SetSize updates terminal dimensions.
+func (m *TimelineModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-18, 4)
+	m.layoutBuckets()
+	m.scrollToCursor()
+}
+
+// stripWidth is the number of density buckets, one per column.
+func (m TimelineModel) stripWidth() int {
+	return m.innerWidth()
+}
+
+func (m TimelineModel) dialogWidth() int {
+	return min(max(m.width-4, 40), 100)
+}
+
+func (m TimelineModel) innerWidth() int {
+	return m.dialogWidth() - 6
+}
+
+// layoutBuckets spreads the entries over the strip by time. Entries out of
+// order, such as resumed messages without a timestamp, keep the bucket of
+// the entry before them so the strip follows the chat order.
+func (m *TimelineModel) layoutBuckets() {
+	n := m.stripWidth()
+	m.counts = make([]int, n)
+	m.bucket = make([]int, len(m.entries))
+	if len(m.entries) == 0 {
+		return
+	}
+	first, last := m.entries[0].Time, m.entries[len(m.entries)-1].Time
+	span := last.Sub(first)
+	b := 0
+	for i, e := range m.entries {
+		if span > 0 && !e.Time.Before(first) {
+			b = max(b, min(int(float64(e.Time.Sub(first))/float64(span)*float64(n-1)), n-1))
+		} else if span <= 0 {
+			b = i * (n - 1) / max(len(m.entries)-1, 1)
+		}
+		m.bucket[i] = b
+		m.counts[b]++
+	}
+}
+
+// Update handles keyboard input for the timeline.
+//
+//	←/h, →/l   → previous / next busy stretch
+//	↑/k, ↓/j   → previous / next message
+//	pgup/pgdn  → move a page
+//	home/g     → first message, end/G → latest
+//	enter      → jump to the message
+//	esc        → dismiss dialog (no action emitted)
+func (m TimelineModel) Update(message tea.Msg) (TimelineModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok || len(m.entries) == 0 {
+		return m, nil
+	}
+	switch kp.Code {
+	case tea.KeyUp, 'k':
+		m.cursor--
+	case tea.KeyDown, 'j':
+		m.cursor++
+	case tea.KeyPgUp:
+		m.cursor -= m.pageSize
+	case tea.KeyPgDown:
+		m.cursor += m.pageSize
+	case tea.KeyHome, 'g':
+		m.cursor = 0
+	case tea.KeyEnd, 'G':
+		m.cursor = len(m.entries) - 1
+	case tea.KeyLeft, 'h':
+		// First entry of the previous bucket holding messages.
+		b := m.bucket[m.cursor]
+		for m.cursor > 0 && m.bucket[m.cursor] == b {
+			m.cursor--
+		}
+		for m.cursor > 0 && m.bucket[m.cursor-1] == m.bucket[m.cursor] {
+			m.cursor--
+		}
+	case tea.KeyRight, 'l':
+		b := m.bucket[m.cursor]
+		for m.cursor < len(m.entries)-1 && m.bucket[m.cursor] == b {
+			m.cursor++
+		}
+	case tea.KeyEnter:
+		idx := m.entries[m.cursor].Index
+		return m, func() tea.Msg { return TimelineJump{Index: idx} }
+	}
+	m.cursor = min(max(m.cursor, 0), len(m.entries)-1)
+	m.scrollToCursor()
+	return m, nil
+}
+
+func (m *TimelineModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the timeline dialog.
+func (m TimelineModel) View() string {
+	inner := m.innerWidth()
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	sb.WriteString(GradientTitle(i18n.T("Timeline")))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.entries) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No messages yet.")))
+		sb.WriteByte('\n')
+	} else {
+		sb.WriteString(m.renderStrip())
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		end := min(m.offset+m.pageSize, len(m.entries))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↑ more above")))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.entries[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.entries) {
+			sb.WriteString(style.Faint.Render("  " + i18n.T("↓ more below")))
+			sb.WriteByte('\n')
+		}
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "←→", Desc: "busy stretch"},
+		{Key: "↑↓", Desc: "message"},
+		{Key: "enter", Desc: "jump"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(m.dialogWidth())
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// renderStrip renders the density strip, a marker under the cursor's bucket,
+// the time axis and a summary of the cursor's bucket.
+func (m TimelineModel) renderStrip() string {
+	peak := 1
+	for _, c := range m.counts {
+		peak = max(peak, c)
+	}
+	cur := m.bucket[m.cursor]
+	var strip, marker strings.Builder
+	for b, c := range m.counts {
+		cell := style.Faint.Render("·")
+		if c > 0 {
+			bar := string(densityBars[(c*(len(densityBars)-1)+peak-1)/peak])
+			if b == cur {
+				cell = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(bar)
+			} else {
+				cell = lipgloss.NewStyle().Foreground(style.Primary).Render(bar)
+			}
+		}
+		strip.WriteString(cell)
+		if b == cur {
+			marker.WriteString(style.PlanSelected.Render("▲"))
+		} else {
+			marker.WriteByte(' ')
+		}
+	}
+
+	first, last := m.entries[0].Time, m.entries[len(m.entries)-1].Time
+	from, to := timelineStamp(first, last), timelineStamp(last, first)
+	gap := max(m.stripWidth()-ansi.StringWidth(from)-ansi.StringWidth(to), 1)
+	axis := style.Faint.Render(from + strings.Repeat(" ", gap) + to)
+
+	var inBucket []TimelineEntry
+	for i, e := range m.entries {
+		if m.bucket[i] == cur {
+			inBucket = append(inBucket, e)
+		}
+	}
+	summary := i18n.T("%d messages", len(inBucket))
+	if len(inBucket) == 1 {
+		summary = i18n.T("1 message")
+	}
+	if a, z := inBucket[0].Time, inBucket[len(inBucket)-1].Time; !a.IsZero() {
+		rng := timelineStamp(a, last)
+		if !z.Equal(a) {
+			rng += "–" + z.Format("15:04")
+		}
+		summary = rng + " · " + summary
+	}
+
+	return strip.String() + "\n" + marker.String() + "\n" + axis + "\n" +
+		style.DialogHelpKey.Render(summary) + "\n"
+}
+
+// renderEntry renders one message row: time, role and a one-line excerpt.
+func (m TimelineModel) renderEntry(e TimelineEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+	// Dates are shown when the session spans days; rows stay aligned.
+	last := m.entries[len(m.entries)-1].Time
+	stampW := 5
+	if !sameDay(m.entries[0].Time, last) {
+		stampW = 12
+	}
+	stamp := fmt.Sprintf("%-*s", stampW, timelineStamp(e.Time, last))
+	osa, you := "osa", i18n.T("you")
+	roleW := max(lipgloss.Width(osa), lipgloss.Width(you))
+	role := osa
+	if e.Role == "user" {
+		role = you
+	}
+	role += strings.Repeat(" ", roleW-lipgloss.Width(role))
+	excerpt := ansi.Truncate(strings.Join(strings.Fields(e.Excerpt), " "), max(width-stampW-roleW-6, 8), "…")
+	if isCursor {
+		excerpt = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(excerpt)
+	} else {
+		excerpt = style.Faint.Render(excerpt)
+	}
+	return cursor + style.Faint.Render(stamp+" "+role+" ") + " " + excerpt
+}
+
+// timelineStamp formats t as a clock time, with the date when t and other
+// fall on different days.
+func timelineStamp(t, other time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if sameDay(t, other) {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,316 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/i18n"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+)
+
+// ToolParam is one argument of a tool shown by ToolsModel.
+type ToolParam struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// ToolUsage is how a tool was used since the TUI started.
+type ToolUsage struct {
+	Calls    int
+	Failures int
+	TotalMs  int64
+	Last     time.Time
+}
+
+// ToolCatalogEntry is one tool shown by ToolsModel.
+type ToolCatalogEntry struct {
+	Name        string
+	Description string
+	Params      []ToolParam
+	Usage       ToolUsage
+	Enabled     bool // in this session
+}
+
+// ToolToggle is emitted when the user enables or disables a tool for the
+// session.
+type ToolToggle struct {
+	Name    string
+	Enabled bool // new state
+}
+
+// ToolsModel is the tool catalog opened by /tools: a filterable list of the
+// backend's tools, with the description, parameters and session usage of
+// the tool under the cursor below it. Usage counts the calls seen since the
+// TUI started; Enabled is per chat session.
+//
+// Pressing Esc emits nothing and the caller should dismiss the dialog.
+type ToolsModel struct {
+	entries    []ToolCatalogEntry
+	filtered   []ToolCatalogEntry
+	cursor     int
+	offset     int
+	filterText string
+
+	width, height int
+	pageSize      int
+}
+
+// NewTools returns an empty ToolsModel.
+func NewTools() ToolsModel {
+	return ToolsModel{pageSize: 10}
+}
+
+// SetEntries populates the catalog, filtered by filter, such as a name given
+// to /tools.
+func (m *ToolsModel) SetEntries(entries []ToolCatalogEntry, filter string) {
+	m.entries = entries
+	m.filterText = filter
+	m.applyFilter()
+}
+
+// SetEnabled updates the state of the named tool after a toggle.
+func (m *ToolsModel) SetEnabled(name string, enabled bool) {
+	for i := range m.entries {
+		if m.entries[i].Name == name {
+			m.entries[i].Enabled = enabled
+		}
+	}
+	for i := range m.filtered {
+		if m.filtered[i].Name == name {
+			m.filtered[i].Enabled = enabled
+		}
+	}
+}
+
+// SetSize updates terminal dimensions.
+func (m *ToolsModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.pageSize = max(h-26, 4)
+	m.scrollToCursor()
+}
+
+// Update handles keyboard input for the catalog.
+//
+//	↑/↓       → move cursor
+//	space     → enable or disable the selected tool for this session
+//	esc       → dismiss dialog (no action emitted)
+//	any char  → append to filter
+//	backspace → remove last filter char
+func (m ToolsModel) Update(message tea.Msg) (ToolsModel, tea.Cmd) {
+	kp, ok := message.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	switch kp.Code {
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.scrollToCursor()
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+			m.scrollToCursor()
+		}
+	case tea.KeySpace:
+		if m.cursor < len(m.filtered) {
+			e := m.filtered[m.cursor]
+			return m, func() tea.Msg { return ToolToggle{Name: e.Name, Enabled: !e.Enabled} }
+		}
+	case tea.KeyBackspace:
+		if m.filterText != "" {
+			runes := []rune(m.filterText)
+			m.filterText = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	default:
+		if kp.Code > 32 && kp.Code != tea.KeyDelete && kp.Code < 127 {
+			m.filterText += string(rune(kp.Code))
+			m.applyFilter()
+		}
+	}
+	return m, nil
+}
+
+// applyFilter keeps the tools whose name or description contain the filter
+// text.
+func (m *ToolsModel) applyFilter() {
+	m.filtered = m.filtered[:0]
+	q := strings.ToLower(m.filterText)
+	for _, e := range m.entries {
+		if strings.Contains(strings.ToLower(e.Name+" "+e.Description), q) {
+			m.filtered = append(m.filtered, e)
+		}
+	}
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *ToolsModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.pageSize {
+		m.offset = m.cursor - m.pageSize + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the catalog dialog.
+func (m ToolsModel) View() string {
+	dw := m.width - 4
+	if dw > 90 {
+		dw = 90
+	}
+	if dw < 40 {
+		dw = 40
+	}
+	inner := dw - 6
+	rule := style.DiffContext.Render(strings.Repeat("─", inner))
+
+	var sb strings.Builder
+	disabled := 0
+	for _, e := range m.entries {
+		if !e.Enabled {
+			disabled++
+		}
+	}
+	title := i18n.T("Tools")
+	if disabled > 0 {
+		title = i18n.T("Tools · %d disabled in this session", disabled)
+	}
+	sb.WriteString(GradientTitle(title))
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	filterVal := style.Faint.Render(i18n.T("type to filter..."))
+	if m.filterText != "" {
+		filterVal = lipgloss.NewStyle().Foreground(style.Secondary).Render(m.filterText)
+	}
+	sb.WriteString(style.DialogHelpKey.Render(i18n.T("Filter: ")) + filterVal)
+	sb.WriteByte('\n')
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+
+	if len(m.filtered) == 0 {
+		sb.WriteString(style.Faint.Render("  " + i18n.T("No tools found")))
+		sb.WriteByte('\n')
+	} else {
+		end := min(m.offset+m.pageSize, len(m.filtered))
+		if m.offset > 0 {
+			sb.WriteString(style.Faint.Render("  ↑ more above"))
+			sb.WriteByte('\n')
+		}
+		for i := m.offset; i < end; i++ {
+			sb.WriteString(m.renderEntry(m.filtered[i], i == m.cursor, inner))
+			sb.WriteByte('\n')
+		}
+		if end < len(m.filtered) {
+			sb.WriteString(style.Faint.Render("  ↓ more below"))
+			sb.WriteByte('\n')
+		}
+	}
+
+	if m.cursor < len(m.filtered) {
+		e := m.filtered[m.cursor]
+		sb.WriteString(rule)
+		sb.WriteByte('\n')
+		if e.Description != "" {
+			sb.WriteString(lipgloss.NewStyle().Width(inner).Render(e.Description))
+			sb.WriteByte('\n')
+		}
+		for _, p := range e.Params {
+			head := "  " + p.Name
+			if p.Type != "" {
+				head += " " + p.Type
+			}
+			if p.Required {
+				head += " *"
+			}
+			line := style.DialogHelpKey.Render(head)
+			if p.Description != "" {
+				desc := ansi.Truncate(strings.ReplaceAll(p.Description, "\n", " "), max(inner-ansi.StringWidth(head)-3, 8), "…")
+				line += style.Faint.Render(" — " + desc)
+			}
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(style.Faint.Render(usageLine(e.Usage)))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(rule)
+	sb.WriteByte('\n')
+	sb.WriteString(RenderHelpBar([]HelpItem{
+		{Key: "↑↓", Desc: "navigate"},
+		{Key: "space", Desc: "enable/disable"},
+		{Key: "esc", Desc: "close"},
+	}, inner))
+
+	frameStyle := lipgloss.NewStyle().
+		Border(style.Frame(lipgloss.RoundedBorder())).
+		BorderForeground(style.Border).
+		Padding(1, 2).
+		Width(dw)
+
+	termW := m.width
+	if termW <= 0 {
+		termW = 80
+	}
+	termH := m.height
+	if termH <= 0 {
+		termH = 40
+	}
+	return lipgloss.Place(termW, termH, lipgloss.Center, lipgloss.Center, frameStyle.Render(sb.String()))
+}
+
+// usageLine describes a tool's use in this session.
+func usageLine(u ToolUsage) string {
+	if u.Calls == 0 {
+		return i18n.T("Not used since the TUI started")
+	}
+	line := i18n.T("Used %d× since the TUI started, avg %s, last at %s", u.Calls,
+		common.HumanDuration(u.TotalMs/int64(u.Calls)), u.Last.Format("15:04"))
+	if u.Failures > 0 {
+		line += " · " + i18n.T("%d failed", u.Failures)
+	}
+	return line
+}
+
+// renderEntry renders a single tool row: state, name, session calls and the
+// start of the description.
+func (m ToolsModel) renderEntry(e ToolCatalogEntry, isCursor bool, width int) string {
+	cursor := "  "
+	if isCursor {
+		cursor = style.PlanSelected.Render("> ")
+	}
+	mark := style.RadioOn.Render("● ")
+	if !e.Enabled {
+		mark = style.RadioOff.Render("○ ")
+	}
+
+	nameW := min(24, width/3)
+	name := fmt.Sprintf("%-*s", nameW, ansi.Truncate(e.Name, nameW, "…"))
+	if isCursor {
+		name = lipgloss.NewStyle().Foreground(style.Secondary).Bold(true).Render(name)
+	} else {
+		name = style.Faint.Render(name)
+	}
+	calls := "    "
+	if e.Usage.Calls > 0 {
+		calls = fmt.Sprintf("%3d×", e.Usage.Calls)
+	}
+	first, _, _ := strings.Cut(e.Description, "\n")
+	desc := ansi.Truncate(first, max(width-4-nameW-6, 8), "…")
+
+	return cursor + mark + name + " " + style.Faint.Render(calls) + " " + style.Faint.Render(desc)
+}
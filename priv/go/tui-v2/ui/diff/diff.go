@@ -165,11 +165,87 @@ func RenderInlineDiff(filename, diffText string, width int) string {
 	return header + "\n" + body
 }
 
+// UnifiedDiff returns the unified diff of oldContent and newContent, with
+// filename in the --- and +++ headers, or "" when they have the same lines.
+// As in git's output, the lines a change removes come before those it adds.
+func UnifiedDiff(filename, oldContent, newContent string) string {
+	hunks := computeHunks(splitLines(oldContent), splitLines(newContent), defaultContext)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", filename, filename)
+	for _, h := range hunks {
+		oldCount, newCount := hunkCounts(h)
+		oldStart, newStart := h.oldStart, h.newStart
+		// An empty range starts at the line before it.
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		var added []string
+		for _, dl := range h.lines {
+			switch dl.Op {
+			case diffAdd:
+				added = append(added, dl.Content)
+				continue
+			case diffRemove:
+				sb.WriteString("-" + dl.Content + "\n")
+				continue
+			}
+			for _, a := range added {
+				sb.WriteString("+" + a + "\n")
+			}
+			added = added[:0]
+			sb.WriteString(" " + dl.Content + "\n")
+		}
+		for _, a := range added {
+			sb.WriteString("+" + a + "\n")
+		}
+	}
+	return sb.String()
+}
+
 // --- diff engine ---
 
 // computeEdits produces a flat annotated list using LCS-based diff.
-// O(m*n) — adequate for TUI file sizes.
+// O(m*n) in the lines between the common prefix and suffix — adequate for
+// TUI file sizes.
 func computeEdits(old, new []string) []diffLine {
+	pre := 0
+	for pre < len(old) && pre < len(new) && old[pre] == new[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(old)-pre && suf < len(new)-pre && old[len(old)-1-suf] == new[len(new)-1-suf] {
+		suf++
+	}
+
+	var edits []diffLine
+	for i := 0; i < pre; i++ {
+		edits = append(edits, diffLine{diffContext, old[i], i + 1, i + 1})
+	}
+	for _, e := range computeMiddle(old[pre:len(old)-suf], new[pre:len(new)-suf]) {
+		if e.OldNum > 0 {
+			e.OldNum += pre
+		}
+		if e.NewNum > 0 {
+			e.NewNum += pre
+		}
+		edits = append(edits, e)
+	}
+	for k := suf; k > 0; k-- {
+		i, j := len(old)-k, len(new)-k
+		edits = append(edits, diffLine{diffContext, old[i], i + 1, j + 1})
+	}
+	return edits
+}
+
+// computeMiddle diffs old and new by their longest common subsequence.
+func computeMiddle(old, new []string) []diffLine {
 	m, n := len(old), len(new)
 	dp := make([][]int, m+1)
 	for i := range dp {
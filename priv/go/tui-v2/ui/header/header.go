@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/miosa/osa-tui/msg"
 	"github.com/miosa/osa-tui/style"
 	"github.com/miosa/osa-tui/ui/logo"
@@ -20,9 +21,15 @@ const OsaLogo = logo.FullLogo
 type Model struct {
 	provider  string
 	modelName string
+	pinProv   string // per-session override, shown instead of the default
+	pinModel  string
 	version   string
 	toolCount int
 	workspace string
+	branch    string // workspace git branch, "" outside a repository
+	dirty     bool   // the workspace has uncommitted changes
+	tip       string // banner tip, "" when tips are off
+	whatsNew  string // banner changelog line after an upgrade
 	width     int
 }
 
@@ -46,6 +53,20 @@ func (m *Model) SetToolCount(n int) { m.toolCount = n }
 // SetWorkspace updates the displayed workspace path.
 func (m *Model) SetWorkspace(path string) { m.workspace = path }
 
+// SetGit shows the workspace git branch, with a marker when there are
+// uncommitted changes. An empty branch hides it.
+func (m *Model) SetGit(branch string, dirty bool) {
+	m.branch = branch
+	m.dirty = dirty
+}
+
+// SetBannerNotes sets the tip and the "what's new" line shown on the startup
+// banner. Empty values hide them.
+func (m *Model) SetBannerNotes(tip, whatsNew string) {
+	m.tip = tip
+	m.whatsNew = whatsNew
+}
+
 // SetWidth updates the terminal width used for separator and box sizing.
 func (m *Model) SetWidth(w int) { m.width = w }
 
@@ -55,6 +76,12 @@ func (m *Model) SetModelOverride(provider, modelName string) {
 	m.modelName = modelName
 }
 
+// SetSessionModel shows a per-session model override. Empty values clear it.
+func (m *Model) SetSessionModel(provider, modelName string) {
+	m.pinProv = provider
+	m.pinModel = modelName
+}
+
 // Provider returns the current provider string.
 func (m Model) Provider() string { return m.provider }
 
@@ -90,7 +117,16 @@ func (m Model) View() string {
 	sep := muted.Render(" · ")
 	provider := style.BannerDetail.Render(m.provider)
 	tools := style.BannerDetail.Render(fmt.Sprintf("%d tools", m.toolCount))
+	if git := m.gitView(); git != "" {
+		tools += sep + git
+	}
 
+	if m.pinModel != "" {
+		slash := muted.Render(" / ")
+		modelStr := primary.Render(m.pinModel)
+		pin := muted.Render(" (session)")
+		return title + sep + style.BannerDetail.Render(m.pinProv) + slash + modelStr + pin + sep + tools
+	}
 	if m.modelName != "" {
 		slash := muted.Render(" / ")
 		modelStr := primary.Render(m.modelName)
@@ -99,6 +135,18 @@ func (m Model) View() string {
 	return title + sep + provider + sep + tools
 }
 
+// gitView renders the branch as "⎇ main", followed by "●" when dirty.
+func (m Model) gitView() string {
+	if m.branch == "" {
+		return ""
+	}
+	s := style.BannerDetail.Render("⎇ " + m.branch)
+	if m.dirty {
+		s += " " + lipgloss.NewStyle().Foreground(style.Warning).Render("●")
+	}
+	return s
+}
+
 // HeaderView returns the compact header plus a thin separator line.
 func (m Model) HeaderView() string {
 	header := m.View()
@@ -158,10 +206,17 @@ func (m Model) ViewFull() string {
 		content.WriteString("\n  " + wsLine)
 	}
 	content.WriteString("\n")
+	if m.whatsNew != "" {
+		news := lipgloss.NewStyle().Foreground(style.Secondary).Render("✦ " + m.whatsNew)
+		content.WriteString("\n  " + ansi.Truncate(news, boxWidth-8, "…"))
+	}
 	content.WriteString("\n  " + hintLine)
+	if m.tip != "" {
+		content.WriteString("\n  " + ansi.Truncate(muted.Render("Tip: "+m.tip), boxWidth-8, "…"))
+	}
 
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(0, 2).
 		Width(boxWidth)
@@ -0,0 +1,79 @@
+package header
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Tips are the short feature tips the startup banner rotates through.
+var Tips = []string{
+	"Ctrl+K opens the command palette; recent commands are listed first",
+	"Alt+. repeats the last slash command",
+	"/theme previews each theme on the chat before you apply it",
+	"Ctrl+L shows the sidebar with git changes and recently touched files",
+	"/pin keeps a reference document open beside the chat",
+	"Snippets from tui.json, such as ;rev, expand with Tab or Space",
+	"Ctrl+B sends the running task to the background",
+	"Alt+T opens the session timeline",
+	"/notifications lists toasts you missed",
+	"Shift+↑ selects a message to copy, reply to or retry",
+	"status_segments in tui.json picks and orders the status bar",
+}
+
+// changelog is a one-line summary of each release, keyed by "major.minor".
+var changelog = map[string]string{
+	"0.2": "theme preview, custom status bar, toast actions",
+}
+
+// Tip returns the nth tip, wrapping around the list.
+func Tip(n int) string {
+	if len(Tips) == 0 {
+		return ""
+	}
+	return Tips[(n%len(Tips)+len(Tips))%len(Tips)]
+}
+
+// WhatsNew returns a line such as "What's new in v0.2: theme previews, …"
+// when current is a newer release series than last, the version of the
+// previous run. It returns "" on the first run, for development builds and
+// for releases without a changelog entry.
+func WhatsNew(last, current string) string {
+	lm, lok := minorVersion(last)
+	cm, cok := minorVersion(current)
+	if !lok || !cok || !newer(cm, lm) {
+		return ""
+	}
+	note, ok := changelog[cm]
+	if !ok {
+		return ""
+	}
+	return "What's new in v" + cm + ": " + note
+}
+
+// minorVersion reduces "v0.2.5" or "0.2.5-rc1" to "0.2".
+func minorVersion(v string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(v), "v"), ".", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	for _, p := range parts[:2] {
+		if _, err := strconv.Atoi(p); err != nil {
+			return "", false
+		}
+	}
+	return parts[0] + "." + parts[1], true
+}
+
+// newer reports whether "major.minor" a is after b.
+func newer(a, b string) bool {
+	am, an, _ := strings.Cut(a, ".")
+	bm, bn, _ := strings.Cut(b, ".")
+	x, _ := strconv.Atoi(am)
+	y, _ := strconv.Atoi(bm)
+	if x != y {
+		return x > y
+	}
+	x, _ = strconv.Atoi(an)
+	y, _ = strconv.Atoi(bn)
+	return x > y
+}
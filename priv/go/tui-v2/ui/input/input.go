@@ -5,8 +5,11 @@
 //   - Command history (up/down when single-line)
 //   - Tab-cycle completion (legacy fallback)
 //   - Completions popup integration (ui/completions)
+//   - Snippet abbreviations expanded on Tab or space
 //   - File attachment chips (ui/attachments)
+//   - Bracketed paste as one edit, with large blocks kept as chips
 //   - Character-count indicator when approaching limit
+//   - Token estimate of the draft, colored by the context budget
 //   - Line-count indicator for multi-line content
 //   - Focused/blurred prompt character rendering
 package input
@@ -42,7 +45,17 @@ type Model struct {
 	width       int
 	multiline   bool
 	completions completions.Model
+	cmdItems    []completions.CompletionItem // commands offered for a "/" prefix
+	snippets    map[string]string            // abbreviation → expansion
+	snippetPop  bool                         // the popup lists snippets
 	attachs     attachments.Model
+	pastes      []string // pasted blocks shown as chips, submitted after the text
+	pasteOpen   bool     // paste chips show their first lines
+	tokens      int      // token estimate of the draft; 0 hides it
+	tokensExact bool     // tokens was counted by the tokenizer
+	ctxUsed     int      // tokens the conversation already takes
+	ctxMax      int      // the model's context window; 0 when unknown
+	chordHint   string   // keys of a pending chord and how it continues
 }
 
 // New returns a configured input Model ready for use.
@@ -60,6 +73,7 @@ func New() Model {
 	s := ta.Styles()
 	s.Focused.CursorLine = lipgloss.NewStyle()
 	s.Blurred.CursorLine = lipgloss.NewStyle()
+	s.Cursor.Blink = !style.ReducedMotion
 	ta.SetStyles(s)
 
 	// Alt+Enter inserts a newline; bare Enter is intercepted by the parent
@@ -88,8 +102,13 @@ func (m *Model) SetWidth(w int) {
 	m.attachs.SetWidth(w)
 }
 
+// SetChordHint shows the keys of a pending chord and its continuations, such
+// as "space s … n new session · l list sessions", in place of the usual hint.
+// Empty clears it.
+func (m *Model) SetChordHint(hint string) { m.chordHint = hint }
+
 // Focus grants keyboard focus to the textarea and returns the init command.
-func (m Model) Focus() tea.Cmd { return m.ta.Focus() }
+func (m *Model) Focus() tea.Cmd { return m.ta.Focus() }
 
 // Blur removes keyboard focus from the textarea.
 func (m *Model) Blur() { m.ta.Blur() }
@@ -114,6 +133,8 @@ func (m *Model) Reset() {
 	m.ta.SetHeight(1)
 	m.resetTab()
 	m.completions.Hide()
+	m.clearPastes()
+	m.tokens = 0
 }
 
 // Submit records text in history and then resets the input.
@@ -124,6 +145,17 @@ func (m *Model) Submit(text string) {
 	m.Reset()
 }
 
+// LastPrompt returns the most recent history entry that is not a slash
+// command, or "" when there is none.
+func (m Model) LastPrompt() string {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(m.history[i], "/") {
+			return m.history[i]
+		}
+	}
+	return ""
+}
+
 // ClearInput clears content without recording history.
 func (m *Model) ClearInput() {
 	m.ta.SetValue("")
@@ -131,6 +163,8 @@ func (m *Model) ClearInput() {
 	m.ta.SetHeight(1)
 	m.resetTab()
 	m.completions.Hide()
+	m.clearPastes()
+	m.tokens = 0
 }
 
 // ─── Completions popup ──────────────────────────────────────────────────────
@@ -138,7 +172,10 @@ func (m *Model) ClearInput() {
 // SetCompletions stores the items available in the completions popup.
 // The popup remains hidden until the user types a "/" prefix.
 func (m *Model) SetCompletions(items []completions.CompletionItem) {
-	m.completions.SetItems(items)
+	m.cmdItems = items
+	if !m.snippetPop {
+		m.completions.SetItems(items)
+	}
 }
 
 // ShowCompletions makes the completions popup visible, pre-filtered to the
@@ -148,12 +185,24 @@ func (m *Model) ShowCompletions() {
 	if v := m.ta.Value(); strings.HasPrefix(v, "/") {
 		filter = strings.TrimPrefix(v, "/")
 	}
+	m.showCommands(filter)
+}
+
+// showCommands shows the popup listing commands, filtered by filter.
+func (m *Model) showCommands(filter string) {
+	if m.snippetPop {
+		m.snippetPop = false
+		m.completions.SetItems(m.cmdItems)
+	}
 	m.completions.Show(nil, filter, m.width)
 }
 
 // HideCompletions dismisses the completions popup.
 func (m *Model) HideCompletions() { m.completions.Hide() }
 
+// CompletionsVisible reports whether the completions popup is showing.
+func (m Model) CompletionsVisible() bool { return m.completions.IsVisible() }
+
 // ─── Attachments ─────────────────────────────────────────────────────────────
 
 // AttachFile adds a file to the attachment list. Returns an error if the path
@@ -162,6 +211,17 @@ func (m *Model) AttachFile(path string) error {
 	return m.attachs.Add(path)
 }
 
+// DetachLast removes the most recently attached file, reporting whether there
+// was one.
+func (m *Model) DetachLast() bool {
+	n := m.attachs.Count()
+	if n == 0 {
+		return false
+	}
+	m.attachs.Remove(n - 1)
+	return true
+}
+
 // ClearAttachments removes all file attachments.
 func (m *Model) ClearAttachments() { m.attachs.Clear() }
 
@@ -172,6 +232,15 @@ func (m Model) Attachments() []string { return m.attachs.Paths() }
 
 // Update handles messages. Key events are tea.KeyPressMsg in bubbletea v2.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	// ── An exact abbreviation expands even while the popup lists others ────
+	if key, ok := msg.(tea.KeyPressMsg); ok && m.SnippetsVisible() &&
+		(key.Code == tea.KeyTab || key.Code == tea.KeySpace) && m.expandSnippet() {
+		m.completions.Hide()
+		if key.Code == tea.KeyTab {
+			return m, nil
+		}
+	}
+
 	// ── Route to completions popup first when visible ──────────────────────
 	if m.completions.IsVisible() {
 		var popupCmd tea.Cmd
@@ -190,6 +259,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case tea.PasteMsg:
+		return m.paste(msg.Content), nil
+
 	case tea.KeyPressMsg:
 		k := msg.Code
 		switch {
@@ -202,8 +274,17 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m = m.navigateHistory(+1)
 			return m, nil
 
-		// Tab-cycle completion.
+		// Backspace on an empty input drops the last pasted block, then the
+		// last attachment.
+		case k == tea.KeyBackspace && m.ta.Value() == "" && (m.dropLastPaste() || m.DetachLast()):
+			return m, nil
+
+		// Tab expands a snippet, or cycles completions.
 		case k == tea.KeyTab:
+			if m.expandSnippet() {
+				m.completions.Hide()
+				return m, nil
+			}
 			m = m.cycleComplete()
 			return m, nil
 
@@ -211,6 +292,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// or deletes, so the popup tracks the current "/" prefix.
 		case k >= ' ' || k == tea.KeyBackspace || k == tea.KeyDelete:
 			m.resetTab()
+			if k == tea.KeySpace {
+				m.expandSnippet()
+			}
 			// Let the textarea handle the key first, then re-evaluate.
 			var cmd tea.Cmd
 			m.ta, cmd = m.ta.Update(msg)
@@ -222,10 +306,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.resetTab()
 		}
 
-	// Completions popup: item selected → fill input.
+	// Completions popup: item selected → fill input, or expand the snippet.
 	case completions.SelectedMsg:
-		m.ta.SetValue(msg.Item.Name)
-		m.updateHeight()
+		if m.snippetPop {
+			m.replaceWord(m.snippets[msg.Item.Name])
+		} else {
+			m.ta.SetValue(msg.Item.Name)
+			m.updateHeight()
+		}
 		m.completions.Hide()
 		return m, nil
 
@@ -250,9 +338,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders:
 //  1. Attachment chips row (if any files attached)
-//  2. Completions popup (if visible), rendered above the separator
-//  3. Horizontal separator
-//  4. Prompt character + textarea + char-count / line-count hint
+//  2. Paste chips (if any blocks pasted)
+//  3. Completions popup (if visible), rendered above the separator
+//  4. Horizontal separator
+//  5. Prompt character + textarea + char-count / line-count hint
 func (m Model) View() string {
 	w := m.width
 	if w < 10 {
@@ -267,6 +356,12 @@ func (m Model) View() string {
 		sb.WriteByte('\n')
 	}
 
+	// Paste chips.
+	if len(m.pastes) > 0 {
+		sb.WriteString(m.pasteView(w))
+		sb.WriteByte('\n')
+	}
+
 	// Completions popup (lives above the separator).
 	if m.completions.IsVisible() {
 		popup := m.completions.View()
@@ -300,17 +395,32 @@ func (m Model) View() string {
 	return sb.String()
 }
 
+// Height returns the number of lines View renders, so the layout can reserve
+// them: the chips and pasted blocks, the completions popup, the separator and
+// the prompt's lines.
+func (m Model) Height() int {
+	h := 1 + m.ta.Height() + m.pasteHeight() + m.completions.Height()
+	if !m.attachs.IsEmpty() {
+		h++
+	}
+	return h
+}
+
 // ─── Internal helpers ────────────────────────────────────────────────────────
 
-// hintText builds the trailing hint: char count when approaching limit,
-// line count when multi-line.
+// hintText builds the trailing hint: the draft's token estimate, then the
+// char count when approaching limit, or line count when multi-line.
 func (m Model) hintText() string {
+	if m.chordHint != "" {
+		return " " + style.Hint.Render(m.chordHint)
+	}
 	val := m.ta.Value()
 	chars := len([]rune(val))
+	tok := m.tokenHint()
 
 	if m.multiline {
 		lines := strings.Count(val, "\n") + 1
-		return " " + style.Hint.Render(fmt.Sprintf("[%d lines · alt+enter newline]", lines))
+		return tok + " " + style.Hint.Render(fmt.Sprintf("[%d lines · alt+enter newline]", lines))
 	}
 
 	if chars >= charWarnAt {
@@ -321,10 +431,10 @@ func (m Model) hintText() string {
 		} else {
 			cs = lipgloss.NewStyle().Foreground(style.Warning)
 		}
-		return " " + cs.Render(fmt.Sprintf("%d/%d", chars, charLimit))
+		return tok + " " + cs.Render(fmt.Sprintf("%d/%d", chars, charLimit))
 	}
 
-	return ""
+	return tok
 }
 
 // updateHeight adjusts textarea height based on newline count.
@@ -349,17 +459,26 @@ func (m *Model) resetTab() {
 }
 
 // autoShowCompletions shows/updates the completions popup when the current
-// input starts with "/", or hides it otherwise.
+// input starts with "/", listing commands, or when the word before the cursor
+// starts a snippet abbreviation, listing snippets. It hides it otherwise.
 func (m *Model) autoShowCompletions() {
 	v := m.ta.Value()
-	if strings.HasPrefix(v, "/") {
+	switch word := m.wordBeforeCursor(); {
+	case strings.HasPrefix(v, "/"):
 		filter := strings.TrimPrefix(v, "/")
-		if m.completions.IsVisible() {
+		if m.completions.IsVisible() && !m.snippetPop {
 			m.completions.SetFilter(filter)
 		} else {
-			m.completions.Show(nil, filter, m.width)
+			m.showCommands(filter)
 		}
-	} else {
+	case m.snippetCandidate(word):
+		if m.completions.IsVisible() && m.snippetPop {
+			m.completions.SetFilter(word)
+		} else {
+			m.snippetPop = true
+			m.completions.Show(m.snippetItems(), word, m.width)
+		}
+	default:
 		m.completions.Hide()
 	}
 }
@@ -0,0 +1,152 @@
+package input
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+)
+
+// pastePreviewLines is how many lines of a pasted block its expanded chip
+// shows.
+const pastePreviewLines = 5
+
+// paste inserts bracketed-paste content as one edit. A block taller than the
+// input grows, or one that would overflow the character limit, is kept whole
+// as a chip above the input instead of being typed into it. Pasting never
+// opens the completions popup.
+func (m Model) paste(content string) Model {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	if strings.TrimSpace(content) == "" {
+		return m
+	}
+	m.resetTab()
+	m.completions.Hide()
+	lines := strings.Count(strings.TrimRight(content, "\n"), "\n") + 1
+	if lines > maxHistoryHeight || len([]rune(m.ta.Value()))+len([]rune(content)) > charLimit {
+		m.pastes = append(m.pastes, strings.TrimRight(content, "\n"))
+		return m
+	}
+	m.ta.InsertString(content)
+	m.updateHeight()
+	return m
+}
+
+// Content returns what the input submits: the typed text followed by the
+// pasted blocks, separated by blank lines.
+func (m Model) Content() string {
+	var parts []string
+	if v := m.ta.Value(); strings.TrimSpace(v) != "" {
+		parts = append(parts, v)
+	}
+	parts = append(parts, m.pastes...)
+	return strings.Join(parts, "\n\n")
+}
+
+// Draft returns the typed text and the pasted blocks, for keeping an unsent
+// draft.
+func (m Model) Draft() (text string, pastes []string) {
+	return m.ta.Value(), slices.Clone(m.pastes)
+}
+
+// RestoreDraft replaces the input with a draft returned by Draft.
+func (m *Model) RestoreDraft(text string, pastes []string) {
+	m.ClearInput()
+	m.ta.SetValue(text)
+	m.pastes = slices.Clone(pastes)
+	m.updateHeight()
+}
+
+// HasPastes reports whether pasted blocks are waiting as chips.
+func (m Model) HasPastes() bool { return len(m.pastes) > 0 }
+
+// PasteSize returns the total lines and bytes of the pasted blocks.
+func (m Model) PasteSize() (lines, bytes int) {
+	for _, p := range m.pastes {
+		lines += strings.Count(p, "\n") + 1
+		bytes += len(p)
+	}
+	return lines, bytes
+}
+
+// TogglePastePreview expands or collapses the latest pasted block's chip,
+// reporting whether there is one.
+func (m *Model) TogglePastePreview() bool {
+	if len(m.pastes) == 0 {
+		return false
+	}
+	m.pasteOpen = !m.pasteOpen
+	return true
+}
+
+// dropLastPaste removes the most recent pasted block, reporting whether
+// there was one.
+func (m *Model) dropLastPaste() bool {
+	if len(m.pastes) == 0 {
+		return false
+	}
+	m.pastes = m.pastes[:len(m.pastes)-1]
+	if len(m.pastes) == 0 {
+		m.pasteOpen = false
+	}
+	return true
+}
+
+// clearPastes removes every pasted block.
+func (m *Model) clearPastes() {
+	m.pastes = nil
+	m.pasteOpen = false
+}
+
+// pasteHeight returns the number of lines pasteView renders.
+func (m Model) pasteHeight() int {
+	h := len(m.pastes)
+	if n := len(m.pastes); n > 0 && m.pasteOpen {
+		lines := strings.Count(m.pastes[n-1], "\n") + 1
+		h += min(lines, pastePreviewLines)
+		if lines > pastePreviewLines {
+			h++
+		}
+	}
+	return h
+}
+
+// pasteView renders a chip per pasted block, such as "▸ pasted 213 lines
+// (8.4KB)", with the first lines of the latest below it when expanded.
+func (m Model) pasteView(width int) string {
+	var rows []string
+	for i, p := range m.pastes {
+		last := i == len(m.pastes)-1
+		open := last && m.pasteOpen
+		marker, hint := "▸", "ctrl+o preview · backspace removes"
+		if open {
+			marker, hint = "▾", "ctrl+o collapse · backspace removes"
+		}
+		lines := strings.Split(p, "\n")
+		label := fmt.Sprintf("%s pasted %d lines (%s)", marker, len(lines), common.HumanSize(int64(len(p))))
+		if len(lines) == 1 {
+			label = fmt.Sprintf("%s pasted %d chars (%s)", marker, len([]rune(p)), common.HumanSize(int64(len(p))))
+		}
+		row := "  " + lipgloss.NewStyle().Foreground(style.Secondary).Render(label)
+		if last {
+			row += "  " + style.Hint.Render(hint)
+		}
+		rows = append(rows, row)
+		if !open {
+			continue
+		}
+		for _, l := range lines[:min(len(lines), pastePreviewLines)] {
+			l = strings.ReplaceAll(ansi.Strip(l), "\t", "    ")
+			rows = append(rows, style.Faint.Render(ansi.Truncate("    │ "+l, width-1, "…")))
+		}
+		if more := len(lines) - pastePreviewLines; more > 0 {
+			rows = append(rows, style.Faint.Render(fmt.Sprintf("    … %d more lines", more)))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,98 @@
+package input
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/ui/completions"
+)
+
+// snippetDescWidth bounds the expansion shown beside an abbreviation in the
+// completions popup.
+const snippetDescWidth = 56
+
+// SetSnippets sets the abbreviations expanded in the input, such as ";rev"
+// for "Review this diff for bugs and style issues:".
+func (m *Model) SetSnippets(snippets map[string]string) { m.snippets = snippets }
+
+// SnippetsVisible reports whether the completions popup is listing snippets
+// rather than commands.
+func (m Model) SnippetsVisible() bool { return m.snippetPop && m.completions.IsVisible() }
+
+// wordBeforeCursor returns the run of non-space characters ending at the
+// cursor.
+func (m Model) wordBeforeCursor() string {
+	lines := strings.Split(m.ta.Value(), "\n")
+	row := m.ta.Line()
+	if row >= len(lines) {
+		return ""
+	}
+	line := []rune(lines[row])
+	col := min(m.ta.Column(), len(line))
+	start := col
+	for start > 0 && !unicode.IsSpace(line[start-1]) {
+		start--
+	}
+	return string(line[start:col])
+}
+
+// expandSnippet replaces the abbreviation before the cursor with its
+// expansion, reporting whether there was one.
+func (m *Model) expandSnippet() bool {
+	word := m.wordBeforeCursor()
+	text, ok := m.snippets[word]
+	if word == "" || !ok {
+		return false
+	}
+	m.replaceWord(text)
+	return true
+}
+
+// replaceWord replaces the word before the cursor with text.
+func (m *Model) replaceWord(text string) {
+	for range []rune(m.wordBeforeCursor()) {
+		m.ta, _ = m.ta.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	m.ta.InsertString(text)
+	m.updateHeight()
+}
+
+// snippetCandidate reports whether word starts an abbreviation listed in
+// the popup. Only abbreviations starting with punctuation, such as ";rev",
+// are listed, so typing an ordinary word never opens it.
+func (m Model) snippetCandidate(word string) bool {
+	if word == "" {
+		return false
+	}
+	for abbr := range m.snippets {
+		r := []rune(abbr)[0]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && strings.HasPrefix(abbr, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// snippetItems returns the snippets as completion items, sorted by
+// abbreviation.
+func (m Model) snippetItems() []completions.CompletionItem {
+	items := make([]completions.CompletionItem, 0, len(m.snippets))
+	for abbr, text := range m.snippets {
+		desc := ansi.Truncate(strings.ReplaceAll(text, "\n", " ⏎ "), snippetDescWidth, "…")
+		item := completions.CompletionItem{
+			Name:        abbr,
+			Description: desc,
+			Category:    "snippet",
+			Icon:        "»",
+		}
+		if desc != text {
+			item.Details = text // the preview shows what the row cuts short
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
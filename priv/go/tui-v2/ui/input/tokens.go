@@ -0,0 +1,60 @@
+package input
+
+import (
+	"fmt"
+
+	"charm.land/lipgloss/v2"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+)
+
+// Shares of the context window at which the token estimate turns amber and
+// red, counting the conversation and the draft together.
+const (
+	tokensWarnAt  = 0.80
+	tokensAlertAt = 0.95
+)
+
+// SetDraftTokens stores the token estimate of the current draft; exact is
+// set when the tokenizer counted it rather than a heuristic.
+func (m *Model) SetDraftTokens(n int, exact bool) {
+	m.tokens = n
+	m.tokensExact = exact
+}
+
+// SetContextBudget stores how many tokens the conversation takes of the
+// model's context window, against which the draft's estimate is colored.
+func (m *Model) SetContextBudget(used, max int) {
+	m.ctxUsed = used
+	m.ctxMax = max
+}
+
+// ContextBudget returns the budget stored by SetContextBudget; max is 0 when
+// the context window is unknown.
+func (m Model) ContextBudget() (used, max int) {
+	return m.ctxUsed, m.ctxMax
+}
+
+// tokenHint renders the draft's token estimate: "~340 tok" for a heuristic
+// count, amber or red with the context share once the conversation and the
+// draft near the context window.
+func (m Model) tokenHint() string {
+	if m.tokens <= 0 || m.Content() == "" {
+		return ""
+	}
+	label := common.HumanTokens(m.tokens) + " tok"
+	if !m.tokensExact {
+		label = "~" + label
+	}
+	if m.ctxMax <= 0 {
+		return " " + style.Hint.Render(label)
+	}
+	share := float64(m.ctxUsed+m.tokens) / float64(m.ctxMax)
+	switch {
+	case share >= tokensAlertAt:
+		return " " + lipgloss.NewStyle().Foreground(style.Error).Render(fmt.Sprintf("%s · ctx %d%%", label, int(share*100)))
+	case share >= tokensWarnAt:
+		return " " + lipgloss.NewStyle().Foreground(style.Warning).Render(fmt.Sprintf("%s · ctx %d%%", label, int(share*100)))
+	}
+	return " " + style.Hint.Render(label)
+}
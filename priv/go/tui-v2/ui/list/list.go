@@ -13,12 +13,27 @@
 //     each View() call — everything else is skipped entirely.
 //   - Gap lines between items are configurable and factored into all
 //     height/scroll calculations.
+//   - Sticky section headers stay pinned to the top of the viewport while
+//     their section is visible.
+//   - Optional smooth scrolling: keyboard scrolls glide over a few frames
+//     instead of jumping, unless motion is reduced.
+//   - Optional scrollbar on the right edge, with a draggable thumb.
+//   - An OnNearTop hook for loading older items on demand as the user
+//     scrolls toward the first one.
+//   - Mouse selection across item boundaries, highlighted through
+//     Highlightable and copied with SelectedText.
 package list
 
 import (
 	"strings"
+	"sync/atomic"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
 )
 
 // ---------------------------------------------------------------------------
@@ -37,6 +52,9 @@ type Item interface {
 
 	// Height returns the rendered height in terminal lines for the given width.
 	// The result must be stable for the same (width, ContentVersion) pair.
+	// The list calls it for every item, including those off screen, so it
+	// should measure the content rather than render it; once the item has
+	// rendered, the list uses the height of the cached render instead.
 	Height(width int) int
 
 	// Render returns the rendered string for the given width.
@@ -54,12 +72,23 @@ type MatchSettable interface {
 	SetMatches(positions []int)
 }
 
-// Highlightable items support text selection.
+// Highlightable items support text selection. Lines and columns refer to the
+// item's rendered lines: the highlight runs from startCol on startLine to just
+// before endCol on endLine, and an endCol of -1 runs to the end of the line.
+// The list discards the item's cached render whenever its highlight changes.
 type Highlightable interface {
 	SetHighlight(startLine, endLine, startCol, endCol int)
 	ClearHighlight()
 }
 
+// Sticky items are section headers, such as "Today" or "Session resumed".
+// A section runs from its header to the next one. Once the header scrolls
+// off the top while the section is still visible, it is drawn over the top
+// of the viewport; the next header pushes it out as it arrives.
+type Sticky interface {
+	StickyHeader() bool
+}
+
 // ---------------------------------------------------------------------------
 // Options
 // ---------------------------------------------------------------------------
@@ -93,6 +122,21 @@ func WithGap(g int) Option {
 	}
 }
 
+// WithSmoothScroll enables smooth scrolling; see SetSmoothScroll.
+func WithSmoothScroll(on bool) Option {
+	return func(m *Model) { m.smooth = on }
+}
+
+// WithScrollbar enables the scrollbar; see SetScrollbar.
+func WithScrollbar(on bool) Option {
+	return func(m *Model) { m.scrollbar = on }
+}
+
+// WithOnNearTop sets the near-top hook; see SetOnNearTop.
+func WithOnNearTop(lines int, fn func() tea.Cmd) Option {
+	return func(m *Model) { m.SetOnNearTop(lines, fn) }
+}
+
 // ---------------------------------------------------------------------------
 // Cache
 // ---------------------------------------------------------------------------
@@ -135,12 +179,43 @@ type Model struct {
 
 	// cache stores rendered output keyed by item ID.
 	cache map[string]cachedRender
+
+	// Smooth scrolling. pending is the number of lines still to scroll
+	// (negative is up), covered in framesLeft frames; ticking is set while
+	// a ScrollFrameMsg is scheduled. id routes the frames to this list.
+	smooth     bool
+	id         int64
+	pending    int
+	framesLeft int
+	ticking    bool
+
+	// Scrollbar. scrollbar reserves the rightmost column for it; dragging is
+	// set while the thumb is held, grab being the row of the thumb held.
+	scrollbar bool
+	dragging  bool
+	grab      int
+
+	// Near-top hook. nearTopFired is set once onNearTop has run, until new
+	// items are prepended or the viewport leaves the zone.
+	onNearTop    func() tea.Cmd
+	nearTopLines int
+	nearTopFired bool
+
+	// Selection, in content coordinates so it survives scrolling. selecting
+	// is set while the mouse button is held; highlighted lists the items
+	// currently given a highlight.
+	selActive   bool
+	selecting   bool
+	selAnchor   Position
+	selHead     Position
+	highlighted []int
 }
 
 // New constructs a Model with the supplied options.
 func New(opts ...Option) Model {
 	m := Model{
 		cache: make(map[string]cachedRender),
+		id:    idCounter.Add(1),
 	}
 	for _, o := range opts {
 		o(&m)
@@ -155,11 +230,15 @@ func New(opts ...Option) Model {
 // SetSize updates the viewport dimensions. The cache is invalidated when
 // width changes because every item must be re-rendered at the new width.
 func (m *Model) SetSize(w, h int) {
-	if w != m.width {
+	resized := w != m.width
+	if resized {
 		m.cache = make(map[string]cachedRender)
 	}
 	m.width = w
 	m.height = h
+	if resized {
+		m.recomputeTotal()
+	}
 	m.clampScroll()
 }
 
@@ -177,10 +256,50 @@ func (m *Model) SetReverse(r bool) {
 	m.reverse = r
 }
 
+// SetScrollbar shows or hides the scrollbar. While shown, items render one
+// column narrower and the bar is drawn in the last column whenever the
+// content overflows the viewport.
+func (m *Model) SetScrollbar(on bool) {
+	if on == m.scrollbar {
+		return
+	}
+	m.scrollbar = on
+	m.dragging = false
+	m.cache = make(map[string]cachedRender)
+	m.recomputeTotal()
+	m.clampScroll()
+}
+
+// SetOnNearTop sets fn to run when a scroll brings the top of the viewport
+// within lines lines of the first item. fn returns the command that fetches
+// older items; the caller adds them with PrependItems, which keeps the
+// visible content in place. fn runs once per approach: again only after
+// PrependItems or once the viewport has left the zone, so a slow fetch is
+// not repeated on every scroll. A nil fn removes the hook.
+//
+// Only scrolls by the user fire it: ScrollUp, ScrollDown, the page moves,
+// the wheel and the scrollbar. Their commands include fn's.
+func (m *Model) SetOnNearTop(lines int, fn func() tea.Cmd) {
+	m.onNearTop = fn
+	m.nearTopLines = max(lines, 0)
+	m.nearTopFired = false
+}
+
+// SetSmoothScroll turns smooth scrolling on or off. When on, ScrollDown,
+// ScrollUp and the page moves glide to their target over a few frames and
+// return the command that drives them. style.ReducedMotion overrides it.
+func (m *Model) SetSmoothScroll(on bool) {
+	m.smooth = on
+	if !on {
+		m.finishScroll()
+	}
+}
+
 // SetItems replaces the item slice wholesale and recomputes the total height.
 // The cache is preserved: items whose ID+version are unchanged are not
 // re-rendered.
 func (m *Model) SetItems(items []Item) {
+	m.ClearSelection()
 	m.items = items
 	m.recomputeTotal()
 	m.clampScroll()
@@ -206,8 +325,17 @@ func (m *Model) PrependItems(items []Item) {
 		return
 	}
 	m.items = append(items, m.items...)
-	// Adjust offsetIdx to account for the prepended items.
-	m.offsetIdx += len(items)
+	// Adjust offsetIdx to account for the prepended items. In reverse mode
+	// it counts from the end and already points at the same item.
+	if !m.reverse {
+		m.offsetIdx += len(items)
+	}
+	m.selAnchor.Item += len(items)
+	m.selHead.Item += len(items)
+	for i := range m.highlighted {
+		m.highlighted[i] += len(items)
+	}
+	m.nearTopFired = false
 	m.recomputeTotal()
 	m.clampScroll()
 }
@@ -232,6 +360,7 @@ func (m *Model) UpdateItem(id string, item Item) {
 
 // ScrollToBottom positions the viewport so the last item is fully visible.
 func (m *Model) ScrollToBottom() {
+	m.pending = 0
 	if len(m.items) == 0 {
 		m.offsetIdx = 0
 		m.offsetLine = 0
@@ -251,6 +380,7 @@ func (m *Model) ScrollToBottom() {
 
 // ScrollToTop positions the viewport at the very first item.
 func (m *Model) ScrollToTop() {
+	m.pending = 0
 	m.offsetIdx = 0
 	m.offsetLine = 0
 }
@@ -267,43 +397,105 @@ func (m *Model) WrapToEnd() {
 
 // ScrollDown scrolls the content down by lines lines (viewport moves down,
 // content scrolls up — earlier items disappear from top, later items appear).
-func (m *Model) ScrollDown(lines int) {
+//
+// With smooth scrolling the first frame moves at once and the returned
+// command schedules the rest; forward its ScrollFrameMsg to Update. The
+// command also carries the near-top hook's, and is nil when there is
+// neither.
+func (m *Model) ScrollDown(lines int) tea.Cmd {
 	if lines <= 0 || len(m.items) == 0 {
-		return
+		return nil
 	}
-	if m.reverse {
-		m.scrollReverseDown(lines)
-	} else {
-		m.scrollForwardDown(lines)
+	if m.animated() {
+		return tea.Batch(m.animate(lines), m.nearTop())
 	}
-	m.clampScroll()
+	m.scrollBy(lines)
+	return m.nearTop()
 }
 
 // ScrollUp scrolls the content up by lines lines (viewport moves up, earlier
-// content reappears at the top).
-func (m *Model) ScrollUp(lines int) {
+// content reappears at the top). It returns a command like ScrollDown.
+func (m *Model) ScrollUp(lines int) tea.Cmd {
 	if lines <= 0 || len(m.items) == 0 {
-		return
+		return nil
 	}
-	if m.reverse {
-		m.scrollReverseUp(lines)
-	} else {
-		m.scrollForwardUp(lines)
+	if m.animated() {
+		return tea.Batch(m.animate(-lines), m.nearTop())
 	}
-	m.clampScroll()
+	m.scrollBy(-lines)
+	return m.nearTop()
 }
 
 // PageDown scrolls down by one full viewport height.
-func (m *Model) PageDown() { m.ScrollDown(m.height) }
+func (m *Model) PageDown() tea.Cmd { return m.ScrollDown(m.height) }
 
 // PageUp scrolls up by one full viewport height.
-func (m *Model) PageUp() { m.ScrollUp(m.height) }
+func (m *Model) PageUp() tea.Cmd { return m.ScrollUp(m.height) }
 
 // HalfPageDown scrolls down by half the viewport height.
-func (m *Model) HalfPageDown() { m.ScrollDown(m.height / 2) }
+func (m *Model) HalfPageDown() tea.Cmd { return m.ScrollDown(m.height / 2) }
 
 // HalfPageUp scrolls up by half the viewport height.
-func (m *Model) HalfPageUp() { m.ScrollUp(m.height / 2) }
+func (m *Model) HalfPageUp() tea.Cmd { return m.ScrollUp(m.height / 2) }
+
+// TotalHeight returns the height of all items and the gaps between them.
+func (m Model) TotalHeight() int {
+	return m.totalHeight
+}
+
+// ScrollOffset returns how many lines of content are above the top of the
+// viewport, in either mode.
+func (m Model) ScrollOffset() int {
+	if len(m.items) == 0 {
+		return 0
+	}
+	if !m.reverse {
+		off := m.offsetLine
+		for i := 0; i < m.offsetIdx && i < len(m.items); i++ {
+			off += m.itemHeight(m.items[i]) + m.gap
+		}
+		return off
+	}
+	below := m.offsetLine
+	for i := len(m.items) - m.offsetIdx; i < len(m.items); i++ {
+		below += m.gap + m.itemHeight(m.items[i])
+	}
+	return max(m.totalHeight-m.height-below, 0)
+}
+
+// scrollToOffset positions the viewport so that off lines of content are
+// above its top, cancelling any smooth scroll.
+func (m *Model) scrollToOffset(off int) {
+	maxOff := max(m.totalHeight-m.height, 0)
+	off = min(max(off, 0), maxOff)
+	if m.reverse {
+		m.ScrollToBottom()
+		m.scrollBy(off - maxOff)
+		return
+	}
+	m.ScrollToTop()
+	m.scrollBy(off)
+}
+
+// Scrolling reports whether a smooth scroll is still under way.
+func (m Model) Scrolling() bool {
+	return m.pending != 0
+}
+
+// scrollBy moves the viewport by n lines at once, down when n is positive.
+func (m *Model) scrollBy(n int) {
+	switch {
+	case n > 0 && m.reverse:
+		m.scrollReverseDown(n)
+	case n > 0:
+		m.scrollForwardDown(n)
+	case n < 0 && m.reverse:
+		m.scrollReverseUp(-n)
+	case n < 0:
+		m.scrollForwardUp(-n)
+	}
+	m.clampScroll()
+}
 
 // AtBottom reports whether the viewport is currently showing the bottom of
 // the list (i.e., auto-scroll would be a no-op).
@@ -329,6 +521,9 @@ func (m Model) ItemIndexAtPosition(y int) int {
 	if y < 0 || y >= m.height || len(m.items) == 0 {
 		return -1
 	}
+	if idx, _, n := m.pinnedHeader(); idx >= 0 && y < n {
+		return idx
+	}
 
 	if m.reverse {
 		return m.itemIndexAtPositionReverse(y)
@@ -364,14 +559,48 @@ func (m Model) itemIndexAtPositionForward(y int) int {
 }
 
 func (m Model) itemIndexAtPositionReverse(y int) int {
-	// Build a layout matching viewReverse, then look up y.
-	type entry struct {
-		itemIdx    int // -1 for gap/padding
-		lineInItem int
+	layout := m.reverseLayout()
+	if y < len(layout) {
+		return layout[y].itemIdx
 	}
-	layout := make([]entry, m.height)
+	return -1
+}
+
+// lineRef identifies the item line drawn on one viewport line.
+type lineRef struct {
+	itemIdx    int // -1 for gap/padding
+	lineInItem int
+}
+
+// lineLayout returns what each viewport line shows, top to bottom, without
+// rendering any item.
+func (m Model) lineLayout() []lineRef {
+	if m.reverse {
+		return m.reverseLayout()
+	}
+	layout := make([]lineRef, 0, m.height)
+	for i := m.offsetIdx; i < len(m.items) && len(layout) < m.height; i++ {
+		start := 0
+		if i == m.offsetIdx {
+			start = m.offsetLine
+		}
+		for j := start; j < m.itemHeight(m.items[i]) && len(layout) < m.height; j++ {
+			layout = append(layout, lineRef{itemIdx: i, lineInItem: j})
+		}
+		if i < len(m.items)-1 {
+			for g := 0; g < m.gap && len(layout) < m.height; g++ {
+				layout = append(layout, lineRef{itemIdx: -1})
+			}
+		}
+	}
+	return layout
+}
+
+// reverseLayout builds the viewport layout matching viewReverse.
+func (m Model) reverseLayout() []lineRef {
+	layout := make([]lineRef, m.height)
 	for i := range layout {
-		layout[i] = entry{itemIdx: -1}
+		layout[i] = lineRef{itemIdx: -1}
 	}
 
 	remaining := m.height
@@ -380,10 +609,7 @@ func (m Model) itemIndexAtPositionReverse(y int) int {
 		anchorIdx = 0
 	}
 
-	type seg struct {
-		entries []entry
-	}
-	var segs []seg
+	var segs [][]lineRef
 
 	for i := anchorIdx; i >= 0 && remaining > 0; i-- {
 		h := m.itemHeight(m.items[i])
@@ -400,12 +626,12 @@ func (m Model) itemIndexAtPositionReverse(y int) int {
 			startLine = visLines - remaining
 			visLines = remaining
 		}
-		entries := make([]entry, visLines)
+		entries := make([]lineRef, visLines)
 		for j := 0; j < visLines; j++ {
-			entries[j] = entry{itemIdx: i, lineInItem: startLine + j}
+			entries[j] = lineRef{itemIdx: i, lineInItem: startLine + j}
 		}
 		if len(entries) > 0 {
-			segs = append(segs, seg{entries: entries})
+			segs = append(segs, entries)
 			remaining -= len(entries)
 		}
 		if remaining > 0 && i > 0 && m.gap > 0 {
@@ -413,11 +639,11 @@ func (m Model) itemIndexAtPositionReverse(y int) int {
 			if gapLines > remaining {
 				gapLines = remaining
 			}
-			gapEntries := make([]entry, gapLines)
+			gapEntries := make([]lineRef, gapLines)
 			for j := range gapEntries {
-				gapEntries[j] = entry{itemIdx: -1}
+				gapEntries[j] = lineRef{itemIdx: -1}
 			}
-			segs = append(segs, seg{entries: gapEntries})
+			segs = append(segs, gapEntries)
 			remaining -= gapLines
 		}
 	}
@@ -428,18 +654,14 @@ func (m Model) itemIndexAtPositionReverse(y int) int {
 		pos += remaining
 	}
 	for i := len(segs) - 1; i >= 0; i-- {
-		for _, e := range segs[i].entries {
+		for _, e := range segs[i] {
 			if pos < m.height {
 				layout[pos] = e
 			}
 			pos++
 		}
 	}
-
-	if y < len(layout) {
-		return layout[y].itemIdx
-	}
-	return -1
+	return layout
 }
 
 // VisibleItemIndices returns the indices of items currently in the viewport.
@@ -477,18 +699,41 @@ func (m *Model) InvalidateItem(id string) {
 // Update (bubbletea)
 // ---------------------------------------------------------------------------
 
-// Update handles mouse wheel events for scrolling. Callers forward whichever
-// tea.Msg events they want the list to respond to.
+// Update handles mouse wheel events for scrolling and the frames of smooth
+// scrolls. Callers forward whichever tea.Msg events they want the list to
+// respond to.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case ScrollFrameMsg:
+		if msg.ID != m.id {
+			break
+		}
+		m.ticking = false
+		if m.pending == 0 {
+			break
+		}
+		m.stepScroll()
+		return m, tea.Batch(m.scheduleFrame(), m.nearTop())
 	case tea.MouseWheelMsg:
+		// The wheel scrolls at once: its events already come in small steps.
+		if len(m.items) == 0 {
+			break
+		}
 		switch msg.Button {
 		case tea.MouseWheelUp:
-			m.ScrollUp(3)
+			m.scrollBy(-3)
 		case tea.MouseWheelDown:
-			m.ScrollDown(3)
+			m.scrollBy(3)
 		}
+		return m, m.nearTop()
 	case tea.MouseClickMsg:
+		if msg.Button == tea.MouseLeft && m.onScrollbar(msg.X) {
+			m.grabThumb(msg.Y)
+			return m, m.nearTop()
+		}
+		if msg.Button == tea.MouseLeft {
+			m.StartSelection(msg.X, msg.Y)
+		}
 		// Forward click events to MouseClickable items.
 		idx := m.ItemIndexAtPosition(msg.Y)
 		if idx >= 0 && idx < len(m.items) {
@@ -496,6 +741,21 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				return m, mc.HandleClick(msg.X, msg.Y)
 			}
 		}
+	case tea.MouseMotionMsg:
+		if m.dragging {
+			m.dragThumb(msg.Y)
+			return m, m.nearTop()
+		}
+		if m.selecting {
+			m.ExtendSelection(msg.X, msg.Y)
+			return m, m.nearTop()
+		}
+	case tea.MouseReleaseMsg:
+		m.dragging = false
+		if m.selecting {
+			m.ExtendSelection(msg.X, msg.Y)
+			m.selecting = false
+		}
 	}
 	return m, nil
 }
@@ -514,10 +774,455 @@ func (m Model) View() string {
 		return ""
 	}
 
+	var out string
 	if m.reverse {
-		return m.viewReverse()
+		out = m.viewReverse()
+	} else {
+		out = m.viewForward()
+	}
+	out = m.overlayStickyHeader(out)
+	if m.contentWidth() < m.width {
+		out = m.drawScrollbar(out)
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Sticky headers
+// ---------------------------------------------------------------------------
+
+// isSticky reports whether item i is a sticky section header.
+func (m Model) isSticky(i int) bool {
+	s, ok := m.items[i].(Sticky)
+	return ok && s.StickyHeader()
+}
+
+// pinnedHeader returns the index of the header pinned to the top of the
+// viewport, the first of its lines shown and how many lines it covers. idx
+// is -1 when nothing is pinned: the section's header is itself at the top,
+// there is no header above the top line, or the header is taller than the
+// viewport.
+func (m Model) pinnedHeader() (idx, from, n int) {
+	layout := m.lineLayout()
+	top := -1
+	for y, ref := range layout {
+		if ref.itemIdx >= 0 {
+			top = y
+			break
+		}
+	}
+	if top < 0 {
+		return -1, 0, 0
+	}
+	first := layout[top]
+	if top > 0 || (first.lineInItem == 0 && m.isSticky(first.itemIdx)) {
+		// The content starts below the top (reverse-mode padding), or a
+		// header starts exactly at the top.
+		return -1, 0, 0
+	}
+	idx = -1
+	for i := first.itemIdx; i >= 0; i-- {
+		if m.isSticky(i) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return -1, 0, 0
+	}
+	n = m.itemHeight(m.items[idx])
+	if n >= m.height {
+		return -1, 0, 0
+	}
+
+	// The next section's header pushes the pinned one up.
+	for y, ref := range layout {
+		if y >= n {
+			break
+		}
+		if ref.itemIdx > idx && ref.lineInItem == 0 && m.isSticky(ref.itemIdx) {
+			from = n - y
+			break
+		}
+	}
+	if from >= n {
+		return -1, 0, 0
+	}
+	return idx, from, n - from
+}
+
+// overlayStickyHeader draws the pinned header, if any, over the top lines
+// of a rendered viewport.
+func (m Model) overlayStickyHeader(view string) string {
+	idx, from, n := m.pinnedHeader()
+	if idx < 0 {
+		return view
+	}
+	header := splitLines(m.renderItem(m.items[idx]))
+	lines := splitLines(view)
+	for i := 0; i < n && i < len(lines) && from+i < len(header); i++ {
+		lines[i] = header[from+i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ---------------------------------------------------------------------------
+// Scrollbar
+// ---------------------------------------------------------------------------
+
+// contentWidth is the width items render at: the viewport width less the
+// scrollbar's column.
+func (m Model) contentWidth() int {
+	if m.scrollbar && m.width > 1 {
+		return m.width - 1
+	}
+	return m.width
+}
+
+// drawScrollbar pads a rendered viewport to its full size and draws the
+// scrollbar in the last column, or leaves the column blank when everything
+// fits.
+func (m Model) drawScrollbar(view string) string {
+	cw := m.contentWidth()
+	lines := splitLines(view)
+	for len(lines) < m.height {
+		lines = append(lines, "")
+	}
+	bar := make([]string, m.height)
+	if s := common.Scrollbar(m.height, m.totalHeight, m.ScrollOffset()); s != "" {
+		bar = strings.Split(s, "\n")
+	}
+	for i, l := range lines {
+		if w := ansi.StringWidth(l); w > cw {
+			l = ansi.Truncate(l, cw, "")
+		} else {
+			l += strings.Repeat(" ", cw-w)
+		}
+		if bar[i] == "" {
+			bar[i] = " "
+		}
+		lines[i] = l + bar[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// onScrollbar reports whether column x holds a scrollbar that can be
+// dragged.
+func (m Model) onScrollbar(x int) bool {
+	return m.contentWidth() < m.width && x == m.width-1 && m.totalHeight > m.height
+}
+
+// grabThumb starts a drag at row y. Grabbing the thumb keeps the point held
+// under the pointer; clicking the track centers the thumb there first.
+func (m *Model) grabThumb(y int) {
+	top, size := common.ScrollbarThumbSpan(m.height, m.totalHeight, m.ScrollOffset())
+	m.grab = y - top
+	if y < top || y >= top+size {
+		m.grab = size / 2
+	}
+	m.dragging = true
+	m.dragThumb(y)
+}
+
+// dragThumb moves the thumb so the row held is at y, scrolling the content
+// accordingly.
+func (m *Model) dragThumb(y int) {
+	_, size := common.ScrollbarThumbSpan(m.height, m.totalHeight, m.ScrollOffset())
+	track := m.height - size
+	maxOff := m.totalHeight - m.height
+	if track <= 0 || maxOff <= 0 {
+		return
+	}
+	top := min(max(y-m.grab, 0), track)
+	m.scrollToOffset((top*maxOff + track/2) / track)
+}
+
+// ---------------------------------------------------------------------------
+// Selection
+// ---------------------------------------------------------------------------
+
+// Position is a cell of the content: a line of an item and a column of it.
+type Position struct {
+	Item int // item index
+	Line int // line within the item's render
+	Col  int // terminal column
+}
+
+// before reports whether p comes before q in reading order.
+func (p Position) before(q Position) bool {
+	if p.Item != q.Item {
+		return p.Item < q.Item
+	}
+	if p.Line != q.Line {
+		return p.Line < q.Line
+	}
+	return p.Col < q.Col
+}
+
+// PositionAt maps viewport coordinates to a content position. Coordinates
+// outside the viewport are clamped to it. A gap line or padding resolves to
+// the end of the item above it, or the start of the first item below when
+// there is none, so a selection dragged across gaps stays continuous. ok is
+// false when nothing is shown.
+func (m Model) PositionAt(x, y int) (pos Position, ok bool) {
+	if len(m.items) == 0 || m.height <= 0 || m.width <= 0 {
+		return Position{}, false
+	}
+	y = min(max(y, 0), m.height-1)
+	x = min(max(x, 0), m.contentWidth())
+	if idx, from, n := m.pinnedHeader(); idx >= 0 && y < n {
+		return Position{Item: idx, Line: from + y, Col: x}, true
+	}
+	layout := m.lineLayout()
+	if y < len(layout) && layout[y].itemIdx >= 0 {
+		return Position{Item: layout[y].itemIdx, Line: layout[y].lineInItem, Col: x}, true
+	}
+	for j := min(y, len(layout)-1); j >= 0; j-- {
+		if ref := layout[j]; ref.itemIdx >= 0 {
+			return Position{Item: ref.itemIdx, Line: ref.lineInItem, Col: m.contentWidth()}, true
+		}
+	}
+	for j := y + 1; j < len(layout); j++ {
+		if ref := layout[j]; ref.itemIdx >= 0 {
+			return Position{Item: ref.itemIdx, Line: ref.lineInItem}, true
+		}
+	}
+	return Position{}, false
+}
+
+// StartSelection drops any selection and anchors a new one at viewport
+// coordinates x, y. The left mouse button calls it through Update.
+func (m *Model) StartSelection(x, y int) {
+	m.ClearSelection()
+	pos, ok := m.PositionAt(x, y)
+	if !ok {
+		return
+	}
+	m.selActive, m.selecting = true, true
+	m.selAnchor, m.selHead = pos, pos
+}
+
+// ExtendSelection moves the selection's free end to viewport coordinates
+// x, y, scrolling a line when they are above or below the viewport. Mouse
+// motion while the button is held calls it through Update.
+func (m *Model) ExtendSelection(x, y int) {
+	if !m.selActive {
+		return
+	}
+	switch {
+	case y < 0:
+		m.scrollBy(-1)
+	case y >= m.height:
+		m.scrollBy(1)
+	}
+	pos, ok := m.PositionAt(x, y)
+	if !ok {
+		return
+	}
+	m.selHead = pos
+	m.applySelection()
+}
+
+// ClearSelection drops the selection and its highlights.
+func (m *Model) ClearSelection() {
+	m.selActive, m.selecting = false, false
+	m.applySelection()
+}
+
+// HasSelection reports whether any content is selected.
+func (m Model) HasSelection() bool {
+	return m.selActive && m.selAnchor != m.selHead
+}
+
+// Selection returns the selected range in reading order: start is the first
+// cell selected and end the last.
+func (m Model) Selection() (start, end Position, ok bool) {
+	if !m.HasSelection() {
+		return Position{}, Position{}, false
+	}
+	start, end = m.selAnchor, m.selHead
+	if end.before(start) {
+		start, end = end, start
+	}
+	return start, end, true
+}
+
+// SelectedText returns the selected content as rendered, without styling
+// or trailing spaces. Items are separated as on screen, by a newline plus
+// the gap's blank lines.
+func (m Model) SelectedText() string {
+	start, end, ok := m.Selection()
+	if !ok {
+		return ""
+	}
+	var parts []string
+	for i := start.Item; i <= end.Item && i < len(m.items); i++ {
+		lines := splitLines(m.renderItem(m.items[i]))
+		first, last := 0, len(lines)-1
+		if i == start.Item {
+			first = start.Line
+		}
+		if i == end.Item {
+			last = min(end.Line, last)
+		}
+		var sb strings.Builder
+		for j := first; j <= last; j++ {
+			from, to := 0, ansi.StringWidth(lines[j])
+			if i == start.Item && j == start.Line {
+				from = start.Col
+			}
+			if i == end.Item && j == end.Line {
+				to = min(end.Col+1, to)
+			}
+			if j > first {
+				sb.WriteByte('\n')
+			}
+			if from < to {
+				sb.WriteString(strings.TrimRight(ansi.Strip(ansi.Cut(lines[j], from, to)), " "))
+			}
+		}
+		parts = append(parts, sb.String())
+	}
+	return strings.Join(parts, "\n"+strings.Repeat("\n", m.gap))
+}
+
+// applySelection clears the previous highlights and highlights the current
+// selection on every Highlightable item it covers.
+func (m *Model) applySelection() {
+	for _, i := range m.highlighted {
+		if i < len(m.items) {
+			if h, ok := m.items[i].(Highlightable); ok {
+				h.ClearHighlight()
+			}
+			delete(m.cache, m.items[i].ID())
+		}
+	}
+	m.highlighted = m.highlighted[:0]
+	start, end, ok := m.Selection()
+	if !ok {
+		return
+	}
+	for i := start.Item; i <= end.Item && i < len(m.items); i++ {
+		h, ok := m.items[i].(Highlightable)
+		if !ok {
+			continue
+		}
+		startLine, startCol := 0, 0
+		endLine, endCol := m.itemHeight(m.items[i])-1, -1
+		if i == start.Item {
+			startLine, startCol = start.Line, start.Col
+		}
+		if i == end.Item {
+			endLine, endCol = end.Line, end.Col+1
+		}
+		h.SetHighlight(startLine, endLine, startCol, endCol)
+		delete(m.cache, m.items[i].ID())
+		m.highlighted = append(m.highlighted, i)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Near-top hook
+// ---------------------------------------------------------------------------
+
+// nearTop runs the near-top hook if a scroll has just brought the viewport
+// into its zone, and re-arms it once the viewport has left.
+func (m *Model) nearTop() tea.Cmd {
+	if m.onNearTop == nil {
+		return nil
+	}
+	if m.ScrollOffset() > m.nearTopLines {
+		m.nearTopFired = false
+		return nil
+	}
+	if m.nearTopFired {
+		return nil
+	}
+	m.nearTopFired = true
+	return m.onNearTop()
+}
+
+// ---------------------------------------------------------------------------
+// Smooth scrolling
+// ---------------------------------------------------------------------------
+
+const (
+	// scrollFrames is how many frames a smooth scroll takes.
+	scrollFrames = 4
+
+	// scrollFrameDuration is the time between frames of a smooth scroll.
+	scrollFrameDuration = 20 * time.Millisecond
+)
+
+// idCounter gives each list a unique ID so ScrollFrameMsg events don't
+// cross-talk between lists.
+var idCounter atomic.Int64
+
+// ScrollFrameMsg advances a smooth scroll by one frame. The ID field ensures
+// that only the list that scheduled it responds.
+type ScrollFrameMsg struct {
+	ID int64
+}
+
+// animated reports whether scrolls glide rather than jump.
+func (m Model) animated() bool {
+	return m.smooth && !style.ReducedMotion && m.height > 0
+}
+
+// animate adds n lines to the scroll under way, or starts one, moves the
+// first frame and returns the command for the next.
+func (m *Model) animate(n int) tea.Cmd {
+	m.pending += n
+	m.framesLeft = scrollFrames
+	m.stepScroll()
+	return m.scheduleFrame()
+}
+
+// stepScroll moves one frame of the pending scroll. Frames ease out: each
+// covers a smaller share of the distance than the one before, and the last
+// covers what is left. Reaching either end of the list ends the scroll.
+func (m *Model) stepScroll() {
+	if m.pending == 0 {
+		return
+	}
+	if m.framesLeft < 1 {
+		m.framesLeft = 1
+	}
+	dist, sign := m.pending, 1
+	if dist < 0 {
+		dist, sign = -dist, -1
+	}
+	step := (2*dist + m.framesLeft) / (m.framesLeft + 1) // ceil(2d / (f+1))
+	step = min(max(step, 1), dist)
+
+	idx, line := m.offsetIdx, m.offsetLine
+	m.scrollBy(sign * step)
+	m.pending -= sign * step
+	m.framesLeft--
+	if m.framesLeft == 0 || (m.offsetIdx == idx && m.offsetLine == line) {
+		m.pending = 0
+	}
+}
+
+// scheduleFrame returns the command for the next frame of the pending
+// scroll, or nil when it is done or a frame is already scheduled.
+func (m *Model) scheduleFrame() tea.Cmd {
+	if m.pending == 0 || m.ticking {
+		return nil
+	}
+	m.ticking = true
+	id := m.id
+	return tea.Tick(scrollFrameDuration, func(time.Time) tea.Msg {
+		return ScrollFrameMsg{ID: id}
+	})
+}
+
+// finishScroll jumps to the target of the scroll under way.
+func (m *Model) finishScroll() {
+	if m.pending != 0 {
+		m.scrollBy(m.pending)
+		m.pending = 0
 	}
-	return m.viewForward()
 }
 
 // ---------------------------------------------------------------------------
@@ -806,14 +1511,19 @@ func (m Model) computeTopForBottom() (int, int) {
 			return i + 1, 0
 		}
 		remaining -= h
-		if i > 0 && m.gap > 0 {
-			remaining -= m.gap
-		}
 		if remaining <= 0 {
 			// This item is partially visible at the top.
 			// offsetLine = how many lines of it are hidden above the viewport.
 			return i, -remaining // -remaining is the number of lines cut from top
 		}
+		if i > 0 && m.gap > 0 {
+			remaining -= m.gap
+			if remaining <= 0 {
+				// The gap above this item reaches the top; gaps have no
+				// sub-line offsets, so the item is the topmost.
+				return i, 0
+			}
+		}
 	}
 	// All content fits without filling the viewport.
 	return 0, 0
@@ -891,15 +1601,16 @@ func (m *Model) clampScroll() {
 // the render cache. The render cache is only written by renderItem, which is
 // called exclusively from the View() path.
 func (m Model) itemHeight(item Item) int {
-	if m.width <= 0 {
+	width := m.contentWidth()
+	if width <= 0 {
 		return 1
 	}
 	if cr, ok := m.cache[item.ID()]; ok {
-		if cr.width == m.width && cr.version == item.ContentVersion() {
+		if cr.width == width && cr.version == item.ContentVersion() {
 			return cr.height
 		}
 	}
-	h := item.Height(m.width)
+	h := item.Height(width)
 	if h <= 0 {
 		h = 1
 	}
@@ -908,17 +1619,18 @@ func (m Model) itemHeight(item Item) int {
 
 // renderItem returns the cached or freshly rendered content for an item.
 func (m Model) renderItem(item Item) string {
-	if m.width <= 0 {
+	width := m.contentWidth()
+	if width <= 0 {
 		return ""
 	}
 	id := item.ID()
 	ver := item.ContentVersion()
 	if cr, ok := m.cache[id]; ok {
-		if cr.width == m.width && cr.version == ver {
+		if cr.width == width && cr.version == ver {
 			return cr.content
 		}
 	}
-	rendered := item.Render(m.width)
+	rendered := item.Render(width)
 	h := countLines(rendered)
 	if h == 0 {
 		h = 1
@@ -926,7 +1638,7 @@ func (m Model) renderItem(item Item) string {
 	m.cache[id] = cachedRender{
 		content: rendered,
 		height:  h,
-		width:   m.width,
+		width:   width,
 		version: ver,
 	}
 	return rendered
@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/miosa/osa-tui/style"
 )
 
 // ---------------------------------------------------------------------------
@@ -35,6 +38,15 @@ func multiLineItem(id string, lines int) testItem {
 	return testItem{id: id, content: strings.Join(parts, "\n"), version: 1}
 }
 
+// manyItems returns n one-line items.
+func manyItems(n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = makeItem(fmt.Sprintf("i%d", i), fmt.Sprintf("item %d", i))
+	}
+	return items
+}
+
 // ---------------------------------------------------------------------------
 // New / options
 // ---------------------------------------------------------------------------
@@ -430,6 +442,548 @@ func TestUpdate_MouseWheelUp_ScrollsUp(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Sticky headers
+// ---------------------------------------------------------------------------
+
+type headerItem struct{ testItem }
+
+func (headerItem) StickyHeader() bool { return true }
+
+func makeHeader(id, content string) headerItem {
+	return headerItem{makeItem(id, content)}
+}
+
+func sectionedItems() []Item {
+	return []Item{
+		makeHeader("h1", "Today"),
+		multiLineItem("a", 3),
+		multiLineItem("b", 3),
+		makeHeader("h2", "Yesterday"),
+		multiLineItem("c", 3),
+	}
+}
+
+func TestSticky_NotPinnedAtTop(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(4))
+	m.SetItems(sectionedItems())
+	m.ScrollToTop()
+	want := "Today\na-L0\na-L1\na-L2"
+	if got := m.View(); got != want {
+		t.Errorf("View:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSticky_PinnedWhileSectionVisible(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(4))
+	m.SetItems(sectionedItems())
+	m.ScrollToTop()
+	m.ScrollDown(2) // header and a-L0 scroll off
+	want := "Today\na-L2\nb-L0\nb-L1"
+	if got := m.View(); got != want {
+		t.Errorf("View:\n got %q\nwant %q", got, want)
+	}
+	if idx := m.ItemIndexAtPosition(0); idx != 0 {
+		t.Errorf("pinned header line should resolve to item 0, got %d", idx)
+	}
+	if idx := m.ItemIndexAtPosition(1); idx != 1 {
+		t.Errorf("line below the pinned header should resolve to item 1, got %d", idx)
+	}
+}
+
+func TestSticky_NextHeaderAtTopReplacesPinned(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(4))
+	m.SetItems(sectionedItems())
+	m.ScrollToTop()
+	m.ScrollDown(7) // "Yesterday" is the top line
+	want := "Yesterday\nc-L0\nc-L1\nc-L2"
+	if got := m.View(); got != want {
+		t.Errorf("View:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSticky_PushedUpByNextHeader(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(4))
+	m.SetItems([]Item{
+		headerItem{testItem{id: "h1", content: "H1-L0\nH1-L1", version: 1}},
+		multiLineItem("a", 3),
+		headerItem{testItem{id: "h2", content: "H2-L0\nH2-L1", version: 1}},
+		multiLineItem("b", 3),
+	})
+	m.ScrollToTop()
+	m.ScrollDown(4) // a-L2 on top, the next header on line 1
+	want := "H1-L1\nH2-L0\nH2-L1\nb-L0"
+	if got := m.View(); got != want {
+		t.Errorf("View:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSticky_HeaderTallerThanViewportNotPinned(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(2))
+	m.SetItems([]Item{
+		headerItem{testItem{id: "h", content: "H-L0\nH-L1\nH-L2", version: 1}},
+		multiLineItem("a", 4),
+	})
+	m.ScrollToTop()
+	m.ScrollDown(4)
+	if got := m.View(); got != "a-L1\na-L2" {
+		t.Errorf("tall header must not be pinned, got %q", got)
+	}
+}
+
+func TestSticky_Reverse(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(4), WithReverse(true))
+	m.SetItems(sectionedItems())
+	m.ScrollToBottom()
+	if got, want := m.View(), "Yesterday\nc-L0\nc-L1\nc-L2"; got != want {
+		t.Errorf("at bottom:\n got %q\nwant %q", got, want)
+	}
+	m.ScrollUp(1)
+	if got, want := m.View(), "Today\nYesterday\nc-L0\nc-L1"; got != want {
+		t.Errorf("scrolled up:\n got %q\nwant %q", got, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Smooth scrolling
+// ---------------------------------------------------------------------------
+
+// runFrames delivers the frames of a smooth scroll without waiting for them
+// and returns how many there were.
+func runFrames(m *Model, cmd tea.Cmd) int {
+	frames := 0
+	for cmd != nil {
+		frames++
+		*m, cmd = m.Update(ScrollFrameMsg{ID: m.id})
+	}
+	return frames
+}
+
+func TestSmoothScroll_GlidesToSameTarget(t *testing.T) {
+	items := manyItems(40)
+	instant := New(WithWidth(80), WithHeight(10))
+	instant.SetItems(items)
+	instant.ScrollToTop()
+	if cmd := instant.PageDown(); cmd != nil {
+		t.Error("PageDown without smooth scrolling should not return a command")
+	}
+
+	m := New(WithWidth(80), WithHeight(10), WithSmoothScroll(true))
+	m.SetItems(items)
+	m.ScrollToTop()
+	cmd := m.PageDown()
+	if cmd == nil {
+		t.Fatal("smooth PageDown should return a frame command")
+	}
+	if m.offsetIdx == 0 || m.offsetIdx >= instant.offsetIdx {
+		t.Errorf("first frame should move part of the way, offsetIdx=%d (target %d)", m.offsetIdx, instant.offsetIdx)
+	}
+	if !m.Scrolling() {
+		t.Error("Scrolling should report the scroll under way")
+	}
+	if frames := runFrames(&m, cmd); frames != scrollFrames-1 {
+		t.Errorf("got %d more frames, want %d", frames, scrollFrames-1)
+	}
+	if m.View() != instant.View() {
+		t.Errorf("smooth scroll ended at\n%s\nwant\n%s", m.View(), instant.View())
+	}
+	if m.Scrolling() {
+		t.Error("Scrolling should be false once the scroll is done")
+	}
+}
+
+func TestSmoothScroll_EasesOut(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(40), WithSmoothScroll(true))
+	m.SetItems(manyItems(100))
+	m.ScrollToTop()
+	var steps []int
+	prev := 0
+	cmd := m.ScrollDown(40)
+	for {
+		steps = append(steps, m.offsetIdx-prev)
+		prev = m.offsetIdx
+		if cmd == nil {
+			break
+		}
+		m, cmd = m.Update(ScrollFrameMsg{ID: m.id})
+	}
+	want := []int{16, 12, 8, 4}
+	if fmt.Sprint(steps) != fmt.Sprint(want) {
+		t.Errorf("steps = %v, want %v", steps, want)
+	}
+}
+
+func TestSmoothScroll_StopsAtEnd(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(5), WithSmoothScroll(true))
+	m.SetItems(manyItems(8))
+	m.ScrollToTop()
+	runFrames(&m, m.ScrollDown(100))
+	if !m.AtBottom() || m.Scrolling() {
+		t.Errorf("scroll past the end should stop at the bottom, AtBottom=%v Scrolling=%v", m.AtBottom(), m.Scrolling())
+	}
+}
+
+func TestSmoothScroll_IgnoresOtherListsFrames(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(10), WithSmoothScroll(true))
+	m.SetItems(manyItems(40))
+	m.ScrollToTop()
+	m.PageDown()
+	before := m.offsetIdx
+	m, cmd := m.Update(ScrollFrameMsg{ID: m.id + 1})
+	if cmd != nil || m.offsetIdx != before {
+		t.Error("a frame for another list should be ignored")
+	}
+}
+
+func TestSmoothScroll_ReducedMotion(t *testing.T) {
+	style.ReducedMotion = true
+	defer func() { style.ReducedMotion = false }()
+
+	m := New(WithWidth(80), WithHeight(10), WithSmoothScroll(true))
+	m.SetItems(manyItems(40))
+	m.ScrollToTop()
+	if cmd := m.PageDown(); cmd != nil {
+		t.Error("reduced motion should scroll at once")
+	}
+	if m.offsetIdx != 10 {
+		t.Errorf("offsetIdx = %d, want 10", m.offsetIdx)
+	}
+}
+
+func TestSmoothScroll_JumpCancels(t *testing.T) {
+	m := New(WithWidth(80), WithHeight(10), WithSmoothScroll(true))
+	m.SetItems(manyItems(40))
+	m.ScrollToTop()
+	cmd := m.PageDown()
+	m.ScrollToTop()
+	if runFrames(&m, cmd); m.offsetIdx != 0 {
+		t.Errorf("ScrollToTop should cancel the scroll under way, offsetIdx=%d", m.offsetIdx)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Scrollbar
+// ---------------------------------------------------------------------------
+
+// barColumn returns the last column of each line of a rendered viewport.
+func barColumn(view string) string {
+	var col []string
+	for _, l := range strings.Split(ansi.Strip(view), "\n") {
+		r := []rune(l)
+		col = append(col, string(r[len(r)-1]))
+	}
+	return strings.Join(col, "")
+}
+
+func TestScrollbar_DrawnWhenOverflowing(t *testing.T) {
+	m := New(WithWidth(20), WithHeight(4), WithScrollbar(true))
+	m.SetItems(manyItems(8))
+	m.ScrollToTop()
+	view := m.View()
+	for i, l := range strings.Split(view, "\n") {
+		if w := ansi.StringWidth(l); w != 20 {
+			t.Errorf("line %d is %d wide, want 20", i, w)
+		}
+	}
+	if got := barColumn(view); got != "██││" {
+		t.Errorf("bar at top = %q, want %q", got, "██││")
+	}
+	m.ScrollToBottom()
+	if got := barColumn(m.View()); got != "││██" {
+		t.Errorf("bar at bottom = %q, want %q", got, "││██")
+	}
+}
+
+func TestScrollbar_BlankWhenContentFits(t *testing.T) {
+	m := New(WithWidth(20), WithHeight(4), WithScrollbar(true))
+	m.SetItems(manyItems(2))
+	view := m.View()
+	if got := barColumn(view); got != "    " {
+		t.Errorf("bar column = %q, want blanks", got)
+	}
+	if n := len(strings.Split(view, "\n")); n != 4 {
+		t.Errorf("view should be padded to the viewport, got %d lines", n)
+	}
+}
+
+func TestScrollbar_ItemsRenderNarrower(t *testing.T) {
+	m := New(WithWidth(20), WithHeight(4))
+	m.SetItems(manyItems(8))
+	m.View()
+	if w := m.cache["i0"].width; w != 20 {
+		t.Fatalf("without a scrollbar items render at %d, want 20", w)
+	}
+	m.SetScrollbar(true)
+	m.View()
+	if w := m.cache["i0"].width; w != 19 {
+		t.Errorf("with a scrollbar items render at %d, want 19", w)
+	}
+}
+
+func TestScrollOffset(t *testing.T) {
+	for _, reverse := range []bool{false, true} {
+		m := New(WithWidth(20), WithHeight(5), WithGap(1), WithReverse(reverse))
+		m.SetItems(manyItems(8)) // 8 lines + 7 gaps
+		m.ScrollToBottom()
+		if got := m.ScrollOffset(); got != 10 {
+			t.Errorf("reverse=%v: offset at bottom = %d, want 10", reverse, got)
+		}
+		m.ScrollToTop()
+		if reverse {
+			m.ScrollUp(100)
+		}
+		if got := m.ScrollOffset(); got != 0 {
+			t.Errorf("reverse=%v: offset at top = %d, want 0", reverse, got)
+		}
+	}
+}
+
+func TestScrollbar_ClickAndDrag(t *testing.T) {
+	for _, reverse := range []bool{false, true} {
+		m := New(WithWidth(20), WithHeight(4), WithScrollbar(true), WithReverse(reverse))
+		m.SetItems(manyItems(8))
+		m.ScrollToTop()
+		if reverse {
+			m.ScrollUp(100)
+		}
+
+		m, _ = m.Update(tea.MouseClickMsg{X: 19, Y: 3, Button: tea.MouseLeft})
+		if !m.AtBottom() {
+			t.Errorf("reverse=%v: clicking the bottom of the track should scroll to the bottom, offset %d", reverse, m.ScrollOffset())
+		}
+		m, _ = m.Update(tea.MouseMotionMsg{X: 19, Y: 0, Button: tea.MouseLeft})
+		if got := m.ScrollOffset(); got != 0 {
+			t.Errorf("reverse=%v: dragging to the top gave offset %d, want 0", reverse, got)
+		}
+		m, _ = m.Update(tea.MouseReleaseMsg{X: 19, Y: 0, Button: tea.MouseLeft})
+		m, _ = m.Update(tea.MouseMotionMsg{X: 19, Y: 3})
+		if got := m.ScrollOffset(); got != 0 {
+			t.Errorf("reverse=%v: motion after release should not scroll, offset %d", reverse, got)
+		}
+	}
+}
+
+func TestScrollbar_ClickOffBarGoesToItems(t *testing.T) {
+	m := New(WithWidth(20), WithHeight(4))
+	m.SetItems(manyItems(8))
+	m.ScrollToTop()
+	m, _ = m.Update(tea.MouseClickMsg{X: 19, Y: 3, Button: tea.MouseLeft})
+	if m.ScrollOffset() != 0 {
+		t.Error("without a scrollbar the last column should not scroll")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// History loading (OnNearTop / PrependItems)
+// ---------------------------------------------------------------------------
+
+type historyLoaded struct{}
+
+// nearTopCounter returns a hook that counts its calls.
+func nearTopCounter(calls *int) func() tea.Cmd {
+	return func() tea.Cmd {
+		*calls++
+		return func() tea.Msg { return historyLoaded{} }
+	}
+}
+
+func TestOnNearTop_FiresOnceWithinThreshold(t *testing.T) {
+	calls := 0
+	m := New(WithWidth(80), WithHeight(5), WithReverse(true), WithOnNearTop(3, nearTopCounter(&calls)))
+	m.SetItems(manyItems(20))
+	m.ScrollToBottom()
+
+	if cmd := m.ScrollUp(5); cmd != nil || calls != 0 {
+		t.Fatalf("far from the top: calls=%d, cmd=%v", calls, cmd != nil)
+	}
+	cmd := m.ScrollUp(8) // 2 lines from the top
+	if calls != 1 || cmd == nil {
+		t.Fatalf("within the threshold: calls=%d, want 1 with a command", calls)
+	}
+	if _, ok := cmd().(historyLoaded); !ok {
+		t.Error("the hook's command should be returned")
+	}
+	m.ScrollUp(1)
+	m.ScrollUp(5)
+	if calls != 1 {
+		t.Errorf("the hook should not repeat while the fetch is pending, calls=%d", calls)
+	}
+}
+
+func TestOnNearTop_RearmsAfterLeavingOrPrepending(t *testing.T) {
+	calls := 0
+	m := New(WithWidth(80), WithHeight(5), WithOnNearTop(2, nearTopCounter(&calls)))
+	m.SetItems(manyItems(20))
+	m.ScrollToBottom()
+	m.PageUp()
+	m.PageUp()
+	m.PageUp()
+	if calls != 1 {
+		t.Fatalf("calls=%d, want 1", calls)
+	}
+	m.ScrollDown(5)
+	m.ScrollUp(5)
+	if calls != 2 {
+		t.Errorf("leaving and re-entering the zone should fire again, calls=%d", calls)
+	}
+	m.PrependItems([]Item{makeItem("old", "old")})
+	m.ScrollUp(1)
+	if calls != 3 {
+		t.Errorf("prepending should re-arm the hook, calls=%d", calls)
+	}
+}
+
+func TestOnNearTop_Wheel(t *testing.T) {
+	calls := 0
+	m := New(WithWidth(80), WithHeight(5), WithOnNearTop(0, nearTopCounter(&calls)))
+	m.SetItems(manyItems(8))
+	m.ScrollToTop()
+	m.ScrollDown(3)
+	m, cmd := m.Update(tea.MouseWheelMsg{Button: tea.MouseWheelUp})
+	if calls != 1 || cmd == nil {
+		t.Errorf("wheel to the top should fire the hook, calls=%d", calls)
+	}
+}
+
+func TestPrependItems_KeepsViewStable(t *testing.T) {
+	older := []Item{multiLineItem("x", 2), multiLineItem("y", 2)}
+	for _, reverse := range []bool{false, true} {
+		m := New(WithWidth(80), WithHeight(3), WithGap(1), WithReverse(reverse))
+		m.SetItems(manyItems(10))
+		m.ScrollToBottom()
+		m.ScrollUp(4)
+		before := m.View()
+		m.PrependItems(older)
+		if got := m.View(); got != before {
+			t.Errorf("reverse=%v: view changed after PrependItems:\n got %q\nwant %q", reverse, got, before)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Selection
+// ---------------------------------------------------------------------------
+
+// hlItem records the highlight the list gives it.
+type hlItem struct {
+	testItem
+	hl [4]int // startLine, endLine, startCol, endCol
+	on bool
+}
+
+func (h *hlItem) SetHighlight(startLine, endLine, startCol, endCol int) {
+	h.hl, h.on = [4]int{startLine, endLine, startCol, endCol}, true
+}
+
+func (h *hlItem) ClearHighlight() { h.on = false }
+
+// selectionList returns a list of three two-line items, "a-L0 … c-L1", with
+// a one-line gap, and the items.
+func selectionList() (Model, []*hlItem) {
+	hs := []*hlItem{
+		{testItem: multiLineItem("a", 2)},
+		{testItem: multiLineItem("b", 2)},
+		{testItem: multiLineItem("c", 2)},
+	}
+	m := New(WithWidth(20), WithHeight(8), WithGap(1))
+	m.SetItems([]Item{hs[0], hs[1], hs[2]})
+	m.ScrollToTop()
+	return m, hs
+}
+
+func TestPositionAt(t *testing.T) {
+	m, _ := selectionList()
+	cases := []struct {
+		x, y int
+		want Position
+	}{
+		{2, 0, Position{0, 0, 2}},
+		{1, 4, Position{1, 1, 1}},
+		{3, 2, Position{0, 1, 20}}, // gap: end of the item above
+		{0, 99, Position{2, 1, 0}}, // clamped to the last line
+	}
+	for _, c := range cases {
+		got, ok := m.PositionAt(c.x, c.y)
+		if !ok || got != c.want {
+			t.Errorf("PositionAt(%d, %d) = %+v, %v; want %+v", c.x, c.y, got, ok, c.want)
+		}
+	}
+}
+
+func TestSelection_DragAcrossItems(t *testing.T) {
+	m, hs := selectionList()
+	m, _ = m.Update(tea.MouseClickMsg{X: 2, Y: 1, Button: tea.MouseLeft}) // a-L1, "L1"
+	m, _ = m.Update(tea.MouseMotionMsg{X: 1, Y: 6, Button: tea.MouseLeft})
+	m, _ = m.Update(tea.MouseReleaseMsg{X: 1, Y: 6, Button: tea.MouseLeft}) // c-L0, "c-"
+
+	if got, want := m.SelectedText(), "L1\n\nb-L0\nb-L1\n\nc-"; got != want {
+		t.Errorf("SelectedText = %q, want %q", got, want)
+	}
+	if !hs[0].on || hs[0].hl != [4]int{1, 1, 2, -1} {
+		t.Errorf("first item highlight = %v (on=%v)", hs[0].hl, hs[0].on)
+	}
+	if !hs[1].on || hs[1].hl != [4]int{0, 1, 0, -1} {
+		t.Errorf("middle item highlight = %v (on=%v)", hs[1].hl, hs[1].on)
+	}
+	if !hs[2].on || hs[2].hl != [4]int{0, 0, 0, 2} {
+		t.Errorf("last item highlight = %v (on=%v)", hs[2].hl, hs[2].on)
+	}
+
+	// Dragging back above the anchor selects backwards from it and clears
+	// the items it left.
+	m.ExtendSelection(3, 0)
+	if hs[1].on || hs[2].on {
+		t.Error("items no longer selected should lose their highlight")
+	}
+	if got, want := m.SelectedText(), "0\na-L"; got != want {
+		t.Errorf("SelectedText after shrinking = %q, want %q", got, want)
+	}
+}
+
+func TestSelection_ClickWithoutDragSelectsNothing(t *testing.T) {
+	m, hs := selectionList()
+	m.StartSelection(0, 0)
+	m.ExtendSelection(3, 3)
+	m, _ = m.Update(tea.MouseClickMsg{X: 1, Y: 1, Button: tea.MouseLeft})
+	m, _ = m.Update(tea.MouseReleaseMsg{X: 1, Y: 1, Button: tea.MouseLeft})
+	if m.HasSelection() || m.SelectedText() != "" {
+		t.Error("a click without a drag should leave nothing selected")
+	}
+	for i, h := range hs {
+		if h.on {
+			t.Errorf("item %d still highlighted after a new click", i)
+		}
+	}
+}
+
+func TestSelection_SurvivesScrollAndPrepend(t *testing.T) {
+	m, _ := selectionList()
+	m.SetSize(20, 3)
+	m.ScrollToTop()
+	m.StartSelection(0, 0)
+	m.ExtendSelection(4, 1) // all of a-L0, a-L1
+	m.ScrollDown(3)
+	m.PrependItems([]Item{makeItem("old", "old")})
+	start, end, ok := m.Selection()
+	if !ok || start != (Position{1, 0, 0}) || end != (Position{1, 1, 4}) {
+		t.Errorf("selection = %+v..%+v, %v; want item 1 after prepending", start, end, ok)
+	}
+	if got := m.SelectedText(); got != "a-L0\na-L1" {
+		t.Errorf("SelectedText = %q", got)
+	}
+}
+
+func TestSelection_DragPastEdgeScrolls(t *testing.T) {
+	m, _ := selectionList()
+	m.SetSize(20, 3)
+	m.ScrollToTop()
+	m.StartSelection(0, 0)
+	m.ExtendSelection(0, 3)
+	if m.offsetIdx == 0 && m.offsetLine == 0 {
+		t.Error("dragging below the viewport should scroll down")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Edge cases
 // ---------------------------------------------------------------------------
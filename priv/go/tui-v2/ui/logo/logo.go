@@ -95,7 +95,7 @@ func RenderBanner(width int, version string) string {
 	content.WriteString(fmt.Sprintf("\n  %s", ver))
 
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(style.Frame(lipgloss.RoundedBorder())).
 		BorderForeground(style.Border).
 		Padding(0, 2).
 		Width(boxWidth).
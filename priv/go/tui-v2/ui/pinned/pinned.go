@@ -0,0 +1,197 @@
+// Package pinned provides the reference pane shown beside the chat.
+//
+// A pinned document — a file, a diff, the current plan or a tool's output —
+// stays visible and scrollable while the conversation continues, so the user
+// can read referenced material without scrolling the chat back.
+package pinned
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/markdown"
+	"github.com/miosa/osa-tui/redact"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+	"github.com/miosa/osa-tui/ui/diff"
+)
+
+// Kind selects how pinned content is rendered.
+type Kind int
+
+const (
+	KindText     Kind = iota // plain text, e.g. tool output
+	KindFile                 // source file, syntax highlighted with line numbers
+	KindDiff                 // unified diff
+	KindMarkdown             // markdown, e.g. a plan
+)
+
+// Model is the reference pane. It is inactive until Pin is called.
+type Model struct {
+	kind    Kind
+	title   string
+	content string
+	active  bool
+
+	lines  []string // content rendered at the current width
+	offset int      // first visible line
+
+	width  int
+	height int
+}
+
+// New returns an inactive pane.
+func New() Model {
+	return Model{}
+}
+
+// Pin shows content in the pane, replacing any pinned document. title names
+// the document; for KindFile it is also used to pick the syntax lexer.
+func (m *Model) Pin(kind Kind, title, content string) {
+	m.kind = kind
+	m.title = title
+	m.content = content
+	m.active = true
+	m.offset = 0
+	m.render()
+}
+
+// Close hides the pane and drops its content.
+func (m *Model) Close() {
+	*m = Model{width: m.width, height: m.height}
+}
+
+// IsActive reports whether a document is pinned.
+func (m Model) IsActive() bool { return m.active }
+
+// Title returns the pinned document's title.
+func (m Model) Title() string { return m.title }
+
+// SetSize updates the pane dimensions and re-renders for the new width.
+func (m *Model) SetSize(w, h int) {
+	resized := w != m.width
+	m.width = w
+	m.height = h
+	if resized && m.active {
+		m.render()
+	}
+	m.clampOffset()
+}
+
+// Refresh re-renders the pinned document, e.g. after secrets were revealed.
+func (m *Model) Refresh() {
+	if m.active {
+		m.render()
+	}
+}
+
+// ScrollUp moves the view up by n lines.
+func (m *Model) ScrollUp(n int) {
+	m.offset -= n
+	m.clampOffset()
+}
+
+// ScrollDown moves the view down by n lines.
+func (m *Model) ScrollDown(n int) {
+	m.offset += n
+	m.clampOffset()
+}
+
+// HalfPage returns the number of lines in half a view, at least 1.
+func (m Model) HalfPage() int {
+	return max(m.bodyHeight()/2, 1)
+}
+
+// View renders the pane: a title line, a separator and the visible part of
+// the document with a scrollbar.
+func (m Model) View() string {
+	if !m.active || m.width <= 0 || m.height <= 0 {
+		return ""
+	}
+	inner := m.innerWidth()
+
+	title := style.SidebarTitle.Render(ansi.Truncate(m.title, inner-12, "…"))
+	pos := ""
+	if len(m.lines) > m.bodyHeight() {
+		pos = style.Hint.Render(fmt.Sprintf(" %d/%d", m.offset+1, len(m.lines)))
+	}
+	header := title + pos
+	sep := style.SidebarSeparator.Render(strings.Repeat(style.Glyph("─", " "), inner))
+
+	body := m.visibleLines()
+	for len(body) < m.bodyHeight() {
+		body = append(body, "")
+	}
+	for i, l := range body {
+		body[i] = lipgloss.NewStyle().Width(inner - 1).Render(ansi.Truncate(l, inner-1, "…"))
+	}
+	content := strings.Join(body, "\n")
+	if bar := common.Scrollbar(m.bodyHeight(), len(m.lines), m.offset); bar != "" {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, content, bar)
+	}
+
+	return style.PinnedStyle.
+		Width(m.width).
+		Height(m.height).
+		Render(header + "\n" + sep + "\n" + content)
+}
+
+func (m Model) visibleLines() []string {
+	end := min(m.offset+m.bodyHeight(), len(m.lines))
+	if m.offset >= end {
+		return nil
+	}
+	out := make([]string, end-m.offset)
+	copy(out, m.lines[m.offset:end])
+	return out
+}
+
+// innerWidth is the width inside the left border and padding.
+func (m Model) innerWidth() int { return max(m.width-3, 10) }
+
+// bodyHeight is the number of document lines shown below the title and
+// separator.
+func (m Model) bodyHeight() int { return max(m.height-2, 1) }
+
+func (m *Model) clampOffset() {
+	m.offset = min(m.offset, len(m.lines)-m.bodyHeight())
+	m.offset = max(m.offset, 0)
+}
+
+// render formats the content for the current width.
+func (m *Model) render() {
+	w := m.innerWidth() - 1 // scrollbar column
+	// Expand tabs up front so truncation and padding agree on line widths.
+	content := strings.ReplaceAll(redact.Display(m.content), "\t", "    ")
+	var out string
+	switch m.kind {
+	case KindFile:
+		out = numberLines(diff.HighlightBlock(m.title, content, 0))
+	case KindDiff:
+		out = diff.RenderUnifiedDiff(content, w)
+	case KindMarkdown:
+		if style.ScreenReader {
+			out = content
+		} else {
+			out = markdown.RenderWidth(content, w)
+		}
+	default:
+		out = content
+	}
+	if m.kind != KindFile {
+		out = ansi.Wrap(out, w, "")
+	}
+	m.lines = strings.Split(strings.TrimRight(out, "\n"), "\n")
+}
+
+// numberLines prefixes each line with its 1-based number.
+func numberLines(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	w := len(fmt.Sprint(len(lines)))
+	for i, l := range lines {
+		lines[i] = style.Hint.Render(fmt.Sprintf("%*d ", w, i+1)) + l
+	}
+	return strings.Join(lines, "\n")
+}
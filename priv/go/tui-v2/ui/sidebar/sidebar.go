@@ -2,10 +2,14 @@ package sidebar
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/miosa/osa-tui/style"
 	"github.com/miosa/osa-tui/ui/logo"
 )
@@ -17,6 +21,12 @@ type FileChange struct {
 	Deletions int
 }
 
+// GitFile is a changed file reported by the workspace git status.
+type GitFile struct {
+	Path   string // relative to the repository root
+	Status string // "modified", "added", "deleted", "untracked", ...
+}
+
 // LSPStatus holds the current state of an LSP language server.
 type LSPStatus struct {
 	Name     string
@@ -25,6 +35,13 @@ type LSPStatus struct {
 	Warnings int
 }
 
+// BudgetStatus is spend against one budget limit, in USD.
+type BudgetStatus struct {
+	Period string // "session", "daily" or "monthly"
+	Spent  float64
+	Limit  float64
+}
+
 // MCPStatus holds the current state of an MCP server connection.
 type MCPStatus struct {
 	Name    string
@@ -46,9 +63,18 @@ type Model struct {
 	cost      float64 // session cost in cents
 
 	// Context utilization
-	contextPct  float64
-	contextMax  int
-	contextUsed int
+	contextPct     float64
+	contextMax     int
+	contextUsed    int
+	contextWarnAt  float64 // shares at which the bar turns amber and red
+	contextAlertAt float64
+
+	// Spend against the budget limits that are set
+	budgets []BudgetStatus
+
+	// Session overrides sent with each request (/system, /env)
+	system string
+	env    map[string]string
 
 	// Tool / background counts
 	toolCount int
@@ -59,6 +85,14 @@ type Model struct {
 	lspServers []LSPStatus
 	mcpServers []MCPStatus
 
+	// Git status and recently touched files. Both lists are numbered in the
+	// view so items can be opened by index or clicked.
+	gitKnown  bool
+	gitBranch string
+	gitRoot   string
+	gitFiles  []GitFile
+	recent    []string
+
 	// Dimensions
 	width  int
 	height int
@@ -92,6 +126,40 @@ func (m *Model) SetContext(pct float64, max, used int) {
 	m.contextUsed = used
 }
 
+// SetContextThresholds sets the shares of the context window at which the
+// context bar turns amber and red.
+func (m *Model) SetContextThresholds(warn, alert float64) {
+	m.contextWarnAt = warn
+	m.contextAlertAt = alert
+}
+
+// SetOverrides shows the session's system prompt addition and variables
+// under the context bar. Empty values hide them.
+func (m *Model) SetOverrides(system string, env map[string]string) {
+	m.system = system
+	m.env = env
+}
+
+// SetBudget replaces the budget bars. Limits that are not set are left out.
+func (m *Model) SetBudget(budgets []BudgetStatus) {
+	m.budgets = slices.DeleteFunc(slices.Clone(budgets), func(b BudgetStatus) bool { return b.Limit <= 0 })
+}
+
+// SetBudgetSpend updates the bar of one period, as reported by a budget
+// event.
+func (m *Model) SetBudgetSpend(period string, spent, limit float64) {
+	for i, b := range m.budgets {
+		if b.Period == period {
+			m.budgets[i] = BudgetStatus{Period: period, Spent: spent, Limit: limit}
+			return
+		}
+	}
+	m.SetBudget(append(m.budgets, BudgetStatus{Period: period, Spent: spent, Limit: limit}))
+}
+
+// HasBudget reports whether any budget bar is showing.
+func (m Model) HasBudget() bool { return len(m.budgets) > 0 }
+
 // SetToolCount sets the number of available tools.
 func (m *Model) SetToolCount(n int) { m.toolCount = n }
 
@@ -124,6 +192,85 @@ func (m *Model) SetMCPServers(servers []MCPStatus) {
 	copy(m.mcpServers, servers)
 }
 
+// SetGitStatus shows the branch and changed files of the repository rooted
+// at root.
+func (m *Model) SetGitStatus(root, branch string, files []GitFile) {
+	m.gitKnown = true
+	m.gitRoot = root
+	m.gitBranch = branch
+	m.gitFiles = make([]GitFile, len(files))
+	copy(m.gitFiles, files)
+}
+
+// ClearGitStatus hides the git section.
+func (m *Model) ClearGitStatus() {
+	m.gitKnown = false
+	m.gitBranch = ""
+	m.gitFiles = nil
+}
+
+// maxRecentFiles caps the recent files list.
+const maxRecentFiles = 8
+
+// maxGitFiles caps the changed files listed in the git section.
+const maxGitFiles = 8
+
+// TouchFile moves path to the top of the recent files list.
+func (m *Model) TouchFile(path string) {
+	if path == "" {
+		return
+	}
+	recent := []string{path}
+	for _, p := range m.recent {
+		if p != path && len(recent) < maxRecentFiles {
+			recent = append(recent, p)
+		}
+	}
+	m.recent = recent
+}
+
+// OpenableItems returns the absolute paths of the numbered git and recent
+// file entries, in display order. Item n in the view is OpenableItems()[n-1].
+func (m Model) OpenableItems() []string {
+	var items []string
+	for i, f := range m.gitFiles {
+		if i == maxGitFiles {
+			break
+		}
+		items = append(items, m.gitPath(f.Path))
+	}
+	for _, p := range m.recent {
+		items = append(items, m.absPath(p))
+	}
+	return items
+}
+
+// ItemAt returns the openable path rendered on row y of the sidebar, where
+// row 0 is the sidebar's top line.
+func (m Model) ItemAt(y int) (string, bool) {
+	_, rows := m.render()
+	for _, r := range rows {
+		if r.line == y {
+			return r.path, true
+		}
+	}
+	return "", false
+}
+
+func (m Model) gitPath(p string) string {
+	if filepath.IsAbs(p) || m.gitRoot == "" {
+		return p
+	}
+	return filepath.Join(m.gitRoot, p)
+}
+
+func (m Model) absPath(p string) string {
+	if filepath.IsAbs(p) || m.workDir == "" {
+		return p
+	}
+	return filepath.Join(m.workDir, p)
+}
+
 // SetCost sets the session cost in cents.
 func (m *Model) SetCost(cents float64) { m.cost = cents }
 
@@ -148,11 +295,30 @@ func (m *Model) SetSize(w, h int) {
 //  3. Working directory
 //  4. Model info (provider/model + reasoning + cost)
 //  5. Context utilization bar with percentage
-//  6. Files section with +N/-N diff stats (max 10)
-//  7. LSP section with server statuses
-//  8. MCP section with server statuses
-//  9. Tool count + background count
+//  6. Budget bars for the limits that are set
+//  7. Files section with +N/-N diff stats (max 10)
+//  8. LSP section with server statuses
+//  9. MCP section with server statuses
+//
+// 10. Git branch + changed files (numbered, max 8)
+// 11. Recently touched files from tool calls (numbered, max 8)
+// 12. Tool count + background count
 func (m Model) View() string {
+	body, _ := m.render()
+	return style.SidebarStyle.
+		Width(m.width).
+		Height(m.height).
+		Render(body)
+}
+
+// itemRow records the line an openable item was rendered on.
+type itemRow struct {
+	line int
+	path string
+}
+
+// render builds the sidebar content and the rows holding openable items.
+func (m Model) render() (string, []itemRow) {
 	innerWidth := m.width - 4 // account for border + padding
 	if innerWidth < 10 {
 		innerWidth = 10
@@ -227,9 +393,13 @@ func (m Model) View() string {
 	if barWidth < 5 {
 		barWidth = 5
 	}
-	bar := style.ContextBarRender(m.contextPct, barWidth)
-	pct := fmt.Sprintf(" %d%%", int(m.contextPct*100))
-	sb.WriteString(bar + style.SidebarLabel.Render(pct))
+	bar := style.ContextBarRenderAt(m.contextPct, barWidth, m.contextWarnAt, m.contextAlertAt)
+	pctText := fmt.Sprintf(" %d%%", int(m.contextPct*100))
+	pct := style.SidebarLabel.Render(pctText)
+	if c := style.ContextLevelColor(m.contextPct, m.contextWarnAt, m.contextAlertAt); c != style.Primary {
+		pct = lipgloss.NewStyle().Foreground(c).Render(pctText)
+	}
+	sb.WriteString(bar + pct)
 	sb.WriteByte('\n')
 	if m.contextMax > 0 {
 		ctxDetail := fmt.Sprintf("%s / %s",
@@ -239,8 +409,35 @@ func (m Model) View() string {
 		sb.WriteString(style.SidebarLabel.Render(ctxDetail))
 		sb.WriteByte('\n')
 	}
+	if m.system != "" {
+		first, _, _ := strings.Cut(m.system, "\n")
+		sb.WriteString(style.SidebarLabel.Render("system ") + style.SidebarValue.Render(ansi.Truncate(first, innerWidth-7, "…")))
+		sb.WriteByte('\n')
+	}
+	if len(m.env) > 0 {
+		keys := slices.Sorted(maps.Keys(m.env))
+		sb.WriteString(style.SidebarLabel.Render("env ") + style.SidebarValue.Render(truncateToWidth(strings.Join(keys, " "), innerWidth-4)))
+		sb.WriteByte('\n')
+	}
 
-	// 6. Files section
+	// 6. Budget bars
+	if len(m.budgets) > 0 {
+		sb.WriteString(renderSectionHeader("Budget", innerWidth))
+		budgetBar := max(innerWidth-13, 5) // "monthly " + " 100%"
+		for _, b := range m.budgets {
+			u := b.Spent / b.Limit
+			pct := fmt.Sprintf(" %d%%", int(u*100))
+			if u >= 1 {
+				pct = style.LSPError.Render(pct)
+			} else {
+				pct = style.SidebarLabel.Render(pct)
+			}
+			sb.WriteString(style.SidebarLabel.Render(fmt.Sprintf("%-8s", b.Period)) + style.ContextBarRender(u, budgetBar) + pct)
+			sb.WriteByte('\n')
+		}
+	}
+
+	// 7. Files section
 	sb.WriteString(renderSectionHeader("Files", innerWidth))
 	if len(m.files) == 0 {
 		sb.WriteString(style.SidebarLabel.Render("none"))
@@ -273,7 +470,7 @@ func (m Model) View() string {
 		}
 	}
 
-	// 7. LSP section
+	// 8. LSP section
 	if len(m.lspServers) > 0 {
 		sb.WriteString(renderSectionHeader("LSP", innerWidth))
 		for _, srv := range m.lspServers {
@@ -294,7 +491,7 @@ func (m Model) View() string {
 		}
 	}
 
-	// 8. MCP section
+	// 9. MCP section
 	if len(m.mcpServers) > 0 {
 		sb.WriteString(renderSectionHeader("MCP", innerWidth))
 		for _, srv := range m.mcpServers {
@@ -312,7 +509,49 @@ func (m Model) View() string {
 		}
 	}
 
-	// 9. Tool count + background count
+	// 10. Git section
+	var rows []itemRow
+	n := 0
+	addItem := func(path, label string) {
+		n++
+		rows = append(rows, itemRow{line: strings.Count(sb.String(), "\n"), path: path})
+		num := style.SidebarLabel.Render(fmt.Sprintf("%d", n))
+		sb.WriteString(num + " " + label)
+		sb.WriteByte('\n')
+	}
+	if m.gitKnown {
+		sb.WriteString(renderSectionHeader("Git", innerWidth))
+		branch := style.Glyph("⎇ ", "branch ") + m.gitBranch
+		sb.WriteString(style.SidebarValue.Render(truncateToWidth(branch, innerWidth)))
+		sb.WriteByte('\n')
+		if len(m.gitFiles) == 0 {
+			sb.WriteString(style.SidebarLabel.Render("clean"))
+			sb.WriteByte('\n')
+		}
+		for i, f := range m.gitFiles {
+			if i == maxGitFiles {
+				sb.WriteString(style.SidebarLabel.Render(
+					fmt.Sprintf("+%d more", len(m.gitFiles)-maxGitFiles),
+				))
+				sb.WriteByte('\n')
+				break
+			}
+			code := gitStatusCode(f.Status)
+			display := shortenPath(f.Path, innerWidth-6)
+			addItem(m.gitPath(f.Path), gitStatusStyle(code).Render(code)+" "+style.SidebarFileItem.Render(display))
+		}
+	}
+
+	// 11. Recent files section
+	if len(m.recent) > 0 {
+		sb.WriteString(renderSectionHeader("Recent", innerWidth))
+		for _, p := range m.recent {
+			display := shortenPath(abbreviateHome(p), innerWidth-3)
+			addItem(m.absPath(p), style.SidebarFileItem.Render(display))
+		}
+	}
+
+	// 12. Tool count + background count
 	sb.WriteString(style.SidebarSeparator.Render(strings.Repeat("─", innerWidth)))
 	sb.WriteByte('\n')
 	statsLine := fmt.Sprintf("%d tools", m.toolCount)
@@ -322,12 +561,8 @@ func (m Model) View() string {
 	sb.WriteString(style.SidebarLabel.Render(statsLine))
 	sb.WriteByte('\n')
 
-	// Wrap in sidebar border style.
 	_ = lipgloss.NewStyle().Background(style.SidebarBg) // kept for future use
-	return style.SidebarStyle.
-		Width(m.width).
-		Height(m.height).
-		Render(strings.TrimRight(sb.String(), "\n"))
+	return strings.TrimRight(sb.String(), "\n"), rows
 }
 
 // ---------------------------------------------------------------------------
@@ -377,6 +612,40 @@ func mcpStateStyle(state string) (string, lipgloss.Style) {
 	}
 }
 
+// gitStatusCode abbreviates a git status label to its porcelain letter.
+func gitStatusCode(status string) string {
+	switch status {
+	case "modified":
+		return "M"
+	case "added":
+		return "A"
+	case "deleted":
+		return "D"
+	case "renamed":
+		return "R"
+	case "copied":
+		return "C"
+	case "conflict":
+		return "U"
+	case "untracked":
+		return "?"
+	default:
+		return "·"
+	}
+}
+
+// gitStatusStyle colors a porcelain letter.
+func gitStatusStyle(code string) lipgloss.Style {
+	switch code {
+	case "A", "?":
+		return style.DiffAdditions
+	case "D", "U":
+		return style.DiffDeletions
+	default:
+		return style.LSPStarting
+	}
+}
+
 // ---------------------------------------------------------------------------
 // String / format helpers
 // ---------------------------------------------------------------------------
@@ -403,6 +672,17 @@ func truncateToWidth(s string, maxLen int) string {
 	return s[:maxLen-1] + "…"
 }
 
+// shortenPath keeps the tail of path within maxLen bytes.
+func shortenPath(path string, maxLen int) string {
+	if maxLen < 10 {
+		maxLen = 10
+	}
+	if len(path) <= maxLen {
+		return path
+	}
+	return "…" + path[len(path)-maxLen+1:]
+}
+
 // abbreviateHome replaces the user home directory prefix with "~".
 func abbreviateHome(path string) string {
 	if path == "" {
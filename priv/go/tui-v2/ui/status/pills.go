@@ -17,7 +17,7 @@ func TodoPill(done, total int) string {
 	return style.Faint.Render("Tasks ") + doneStr + style.Faint.Render("/") + totalStr
 }
 
-// QueuePill renders a background queue indicator, e.g. "Queue: 2".
+// QueuePill renders a queued prompts indicator, e.g. "Queue: 2".
 // Returns an empty string when count is zero.
 func QueuePill(count int) string {
 	if count <= 0 {
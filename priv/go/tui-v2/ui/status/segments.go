@@ -0,0 +1,199 @@
+package status
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/miosa/osa-tui/style"
+	"github.com/miosa/osa-tui/ui/common"
+)
+
+// Segments lists the names accepted by SetSegments.
+var Segments = []string{
+	"model", "signal", "background", "tokens", "cost", "context",
+	"budget", "ratelimit", "git", "connection", "queue", "clock",
+}
+
+// Connection states for SetConnection.
+const (
+	Connected    = "connected"
+	Reconnecting = "reconnecting"
+	Offline      = "offline"
+)
+
+// segment is a rendered segment in its full and compact forms.
+type segment struct{ full, short string }
+
+// SetSegments picks the segments shown, in order, on a single line. Empty
+// restores the default layout. Unknown names are ignored.
+func (m *Model) SetSegments(names []string) {
+	m.segments = nil
+	for _, n := range names {
+		if slices.Contains(Segments, n) {
+			m.segments = append(m.segments, n)
+		}
+	}
+}
+
+// ShowsSegment reports whether a configured segment list includes name.
+func (m Model) ShowsSegment(name string) bool { return slices.Contains(m.segments, name) }
+
+// SetWidth sets the width configured segments are fitted to.
+func (m *Model) SetWidth(w int) { m.width = w }
+
+// SetGitBranch sets the branch of the workspace repository, "" outside one.
+func (m *Model) SetGitBranch(branch string) { m.branch = branch }
+
+// SetConnection sets the event stream state: Connected, Reconnecting or
+// Offline.
+func (m *Model) SetConnection(state string) { m.connection = state }
+
+// SetSpend sets today's spend for the cost segment.
+func (m *Model) SetSpend(today float64) {
+	m.spendToday = today
+	m.hasSpend = true
+}
+
+// segmentsView renders the configured segments on one line. When the line
+// is wider than the terminal, every segment switches to its compact form
+// first, then segments are dropped from the end of the list.
+func (m Model) segmentsView() string {
+	var segs []segment
+	for _, name := range m.segments {
+		if s := m.segment(name); s.full != "" {
+			segs = append(segs, s)
+		}
+	}
+	if len(segs) == 0 {
+		return ""
+	}
+	sep := style.Hint.Render(" · ")
+	join := func(segs []segment, short bool) string {
+		line := ""
+		for i, s := range segs {
+			if i > 0 {
+				line += sep
+			}
+			if short {
+				line += s.short
+			} else {
+				line += s.full
+			}
+		}
+		return line
+	}
+	if m.width <= 0 {
+		return join(segs, false)
+	}
+	for n := len(segs); n > 0; n-- {
+		for _, short := range []bool{false, true} {
+			if line := join(segs[:n], short); lipgloss.Width(line) <= m.width {
+				return line
+			}
+		}
+	}
+	return ansi.Truncate(segs[0].short, m.width, "…")
+}
+
+// segment renders one named segment; an empty full form hides it.
+func (m Model) segment(name string) segment {
+	switch name {
+	case "model":
+		if m.provider == "" && m.modelName == "" {
+			return segment{}
+		}
+		short := m.modelName
+		if short == "" {
+			short = m.provider
+		}
+		return segment{m.idleLine(), style.StatusBar.Render(short)}
+
+	case "signal":
+		if m.signal == nil || m.signal.Mode == "" {
+			return segment{}
+		}
+		return segment{
+			style.StatusSignal.Render(fmt.Sprintf("%s/%s", m.signal.Mode, m.signal.Genre)),
+			style.StatusSignal.Render(m.signal.Mode),
+		}
+
+	case "background":
+		if m.bgCount <= 0 {
+			return segment{}
+		}
+		s := style.Hint.Render(fmt.Sprintf("%d bg", m.bgCount))
+		return segment{s, s}
+
+	case "tokens":
+		if m.inputTokens == 0 && m.outputTokens == 0 {
+			return segment{}
+		}
+		s := fmt.Sprintf("↑%s ↓%s", common.HumanTokens(m.inputTokens), common.HumanTokens(m.outputTokens))
+		return segment{style.Hint.Render(s + " tok"), style.Hint.Render(s)}
+
+	case "cost":
+		if !m.hasSpend {
+			return segment{}
+		}
+		s := fmt.Sprintf("$%.2f", m.spendToday)
+		return segment{style.Hint.Render(s + " today"), style.Hint.Render(s)}
+
+	case "context":
+		if m.contextMax <= 0 {
+			return segment{}
+		}
+		return segment{m.contextLine(), style.ContextBar.Render(fmt.Sprintf("ctx %d%%", int(m.contextUtil*100)))}
+
+	case "budget":
+		if m.budgetPeriod == "" {
+			return segment{}
+		}
+		short := fmt.Sprintf("%s %d%%", m.budgetPeriod, int(m.budgetSpent/m.budgetLimit*100))
+		if m.budgetSpent >= m.budgetLimit {
+			return segment{m.budgetSegment(), style.ErrorText.Render(short)}
+		}
+		return segment{m.budgetSegment(), style.ProgressLabel.Render(short)}
+
+	case "ratelimit":
+		if m.rateProvider == "" {
+			return segment{}
+		}
+		return segment{m.rateSegment(), lipgloss.NewStyle().Foreground(style.Warning).Render("rate limited")}
+
+	case "git":
+		if m.branch == "" {
+			return segment{}
+		}
+		return segment{
+			style.Hint.Render("⎇ " + m.branch),
+			style.Hint.Render("⎇ " + ansi.Truncate(m.branch, 16, "…")),
+		}
+
+	case "connection":
+		icon, col := "●", style.Success
+		switch m.connection {
+		case "":
+			return segment{}
+		case Reconnecting:
+			icon, col = "◌", style.Warning
+		case Offline:
+			icon, col = "○", style.Error
+		}
+		st := lipgloss.NewStyle().Foreground(col)
+		return segment{st.Render(icon + " " + m.connection), st.Render(icon)}
+
+	case "queue":
+		if m.queued <= 0 {
+			return segment{}
+		}
+		return segment{m.queueLine(), QueuePill(m.queued)}
+
+	case "clock":
+		s := style.Hint.Render(time.Now().Format("15:04"))
+		return segment{s, s}
+	}
+	return segment{}
+}
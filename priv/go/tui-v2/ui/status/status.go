@@ -1,5 +1,7 @@
 // Package status provides the bottom status bar model for OSA TUI v2.
-// It renders provider/model info, signal classification, and context utilization.
+// It renders provider/model info, signal classification, context utilization,
+// and spend budget and provider rate-limit state. A profile can instead pick
+// the segments shown and their order; see SetSegments.
 package status
 
 import (
@@ -7,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"charm.land/lipgloss/v2"
 	"github.com/miosa/osa-tui/style"
 )
 
@@ -28,10 +31,28 @@ type Model struct {
 	contextUtil     float64 // 0.0–1.0
 	contextMax      int
 	estimatedTokens int
+	contextWarnAt   float64 // shares at which the context bar turns amber and red
+	contextAlertAt  float64
 	active          bool
 	provider        string
 	modelName       string
 	bgCount         int
+	queued          int
+	queueNext       string
+
+	budgetPeriod string // "daily" or "monthly"; "" hides the budget segment
+	budgetSpent  float64
+	budgetLimit  float64
+	rateProvider string // "" hides the rate-limit segment
+	rateSince    time.Time
+	rateUntil    time.Time // zero when the provider named no wait
+
+	segments   []string // configured segments; nil keeps the default layout
+	width      int
+	branch     string
+	connection string
+	spendToday float64
+	hasSpend   bool
 }
 
 // New returns a zero-value Model.
@@ -57,6 +78,13 @@ func (m *Model) SetContext(util float64, max int, estimated int) {
 	m.estimatedTokens = estimated
 }
 
+// SetContextThresholds sets the shares of the context window at which the
+// context segment turns amber and red.
+func (m *Model) SetContextThresholds(warn, alert float64) {
+	m.contextWarnAt = warn
+	m.contextAlertAt = alert
+}
+
 // SetStats updates elapsed time and token/tool counts.
 func (m *Model) SetStats(elapsed time.Duration, tools, inputTok, outputTok int) {
 	m.elapsed = elapsed
@@ -70,6 +98,53 @@ func (m *Model) SetBackgroundCount(n int) {
 	m.bgCount = n
 }
 
+// SetQueue updates the number of queued prompts and a preview of the next one.
+func (m *Model) SetQueue(n int, next string) {
+	m.queued = n
+	m.queueNext = next
+}
+
+// SetBudget shows spend against the daily or monthly limit. A period with
+// lower utilisation than the one shown does not replace it, so the tighter
+// limit stays visible.
+func (m *Model) SetBudget(period string, spent, limit float64) {
+	if limit <= 0 {
+		return
+	}
+	if m.budgetPeriod != "" && period != m.budgetPeriod && spent/limit < m.budgetSpent/m.budgetLimit {
+		return
+	}
+	m.budgetPeriod = period
+	m.budgetSpent = spent
+	m.budgetLimit = limit
+}
+
+// ClearBudget hides the budget segment.
+func (m *Model) ClearBudget() {
+	m.budgetPeriod = ""
+}
+
+// HasBudget reports whether the budget segment is showing.
+func (m Model) HasBudget() bool {
+	return m.budgetPeriod != ""
+}
+
+// SetRateLimit marks provider as rate limiting requests since now. retryAfter
+// is the wait the provider asked for, 0 when unknown.
+func (m *Model) SetRateLimit(provider string, retryAfter time.Duration, now time.Time) {
+	m.rateProvider = provider
+	m.rateSince = now
+	m.rateUntil = time.Time{}
+	if retryAfter > 0 {
+		m.rateUntil = now.Add(retryAfter)
+	}
+}
+
+// ClearRateLimit hides the rate-limit segment, e.g. after a call succeeded.
+func (m *Model) ClearRateLimit() {
+	m.rateProvider = ""
+}
+
 // SetActive marks the model as processing (true) or idle (false).
 func (m *Model) SetActive(active bool) {
 	m.active = active
@@ -79,9 +154,19 @@ func (m *Model) SetActive(active bool) {
 //
 // Active: context bar only (activity panel already shows timing/tokens).
 // Idle: provider/model footer + optional signal badge + optional context bar.
+// Configured segments replace both with a single line.
 func (m Model) View() string {
+	if len(m.segments) > 0 {
+		return m.segmentsView()
+	}
 	if m.active {
-		return m.contextLine()
+		var parts []string
+		for _, l := range []string{m.contextLine(), m.limitsLine(), m.queueLine()} {
+			if l != "" {
+				parts = append(parts, l)
+			}
+		}
+		return strings.Join(parts, "\n")
 	}
 
 	var parts []string
@@ -100,12 +185,89 @@ func (m Model) View() string {
 	if m.contextMax > 0 {
 		parts = append(parts, m.contextLine())
 	}
+	if l := m.limitsLine(); l != "" {
+		parts = append(parts, l)
+	}
+	if q := m.queueLine(); q != "" {
+		parts = append(parts, q)
+	}
 	if len(parts) == 0 {
 		return ""
 	}
 	return strings.Join(parts, "\n")
 }
 
+// Height returns the number of lines View renders, worked out from the state
+// alone so the layout can reserve them without rendering the bar. Configured
+// segments always take one line.
+func (m Model) Height() int {
+	if len(m.segments) > 0 {
+		return 1
+	}
+	lines := []bool{m.contextMax > 0, m.budgetPeriod != "" || m.rateProvider != "", m.queued > 0}
+	if !m.active {
+		lines = append(lines, m.provider != "" || m.modelName != "")
+	}
+	n := 0
+	for _, shown := range lines {
+		if shown {
+			n++
+		}
+	}
+	return n
+}
+
+// queueLine renders the queued prompts indicator:
+// "Queue: 2 · next: summarize the diff · ctrl+s send now"
+func (m Model) queueLine() string {
+	if m.queued <= 0 {
+		return ""
+	}
+	line := QueuePill(m.queued)
+	if m.queueNext != "" {
+		line += style.Faint.Render(" · next: " + m.queueNext)
+	}
+	if m.active {
+		line += style.Hint.Render(" · ctrl+s send now")
+	}
+	return line
+}
+
+// limitsLine renders the budget and rate-limit segments:
+// "██████░░ daily $41.20/$50.00 · anthropic rate limited · retry in 20s"
+func (m Model) limitsLine() string {
+	var segs []string
+	if m.budgetPeriod != "" {
+		segs = append(segs, m.budgetSegment())
+	}
+	if m.rateProvider != "" {
+		segs = append(segs, m.rateSegment())
+	}
+	return strings.Join(segs, style.Hint.Render(" · "))
+}
+
+// budgetSegment renders spend against the limit: "██████░░ daily $41.20/$50.00"
+func (m Model) budgetSegment() string {
+	util := m.budgetSpent / m.budgetLimit
+	filled := min(int(util*8), 8)
+	bar := style.ProgressFilled.Render(strings.Repeat("█", filled)) +
+		style.ProgressEmpty.Render(strings.Repeat("░", 8-filled))
+	label := fmt.Sprintf(" %s $%.2f/$%.2f", m.budgetPeriod, m.budgetSpent, m.budgetLimit)
+	if util >= 1 {
+		return bar + style.ErrorText.Render(label+" exceeded")
+	}
+	return bar + style.ProgressLabel.Render(label)
+}
+
+// rateSegment renders the rate limit: "anthropic rate limited · retry in 20s"
+func (m Model) rateSegment() string {
+	seg := lipgloss.NewStyle().Foreground(style.Warning).Render(m.rateProvider + " rate limited")
+	if left := time.Until(m.rateUntil).Round(time.Second); left > 0 {
+		return seg + style.Hint.Render(fmt.Sprintf(" · retry in %s", left))
+	}
+	return seg + style.Hint.Render(" · since "+m.rateSince.Format("15:04"))
+}
+
 // idleLine renders provider/model info: "ollama / llama3.2"
 func (m Model) idleLine() string {
 	info := m.provider
@@ -124,14 +286,18 @@ func (m Model) contextLine() string {
 	if m.contextMax <= 0 {
 		return ""
 	}
-	bar := style.ContextBarRender(m.contextUtil, 10)
+	bar := style.ContextBarRenderAt(m.contextUtil, 10, m.contextWarnAt, m.contextAlertAt)
 	pct := int(m.contextUtil * 100)
+	labelStyle := style.ContextBar
+	if c := style.ContextLevelColor(m.contextUtil, m.contextWarnAt, m.contextAlertAt); c != style.Primary {
+		labelStyle = labelStyle.Foreground(c)
+	}
 	if m.estimatedTokens > 0 && m.contextMax > 0 {
-		label := style.ContextBar.Render(fmt.Sprintf(" ctx %d%% (%s/%s)",
+		label := labelStyle.Render(fmt.Sprintf(" ctx %d%% (%s/%s)",
 			pct, formatTokens(m.estimatedTokens), formatTokens(m.contextMax)))
 		return bar + label
 	}
-	label := style.ContextBar.Render(fmt.Sprintf(" ctx %d%%", pct))
+	label := labelStyle.Render(fmt.Sprintf(" ctx %d%%", pct))
 	return bar + label
 }
 
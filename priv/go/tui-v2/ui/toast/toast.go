@@ -1,4 +1,9 @@
 // Package toast provides auto-dismissing notification toasts for OSA TUI v2.
+//
+// Besides plain notices a toast can carry actions, such as "Retry" or
+// "Open log", run with Alt+1..Alt+3 while it is the newest toast offering
+// any, and a progress bar for work like a model pull, which keeps it up
+// until the work finishes. Dismissed toasts are kept in a short history.
 package toast
 
 import (
@@ -21,19 +26,43 @@ const (
 )
 
 const (
-	maxToasts = 3
-	toastTTL  = 4 * time.Second
+	maxToasts   = 3
+	maxHistory  = 50
+	toastTTL    = 4 * time.Second
+	actionTTL   = 10 * time.Second // toasts with actions stay longer
+	progressBar = 16               // cells of a progress bar
 )
 
+// Action is a button on a toast. Its ID is handed back by Trigger.
+type Action struct {
+	ID    string
+	Label string
+}
+
+// Entry is a dismissed toast in the history.
+type Entry struct {
+	Message string
+	Level   ToastLevel
+	At      time.Time
+}
+
 type toast struct {
 	message string
 	level   ToastLevel
 	expiry  time.Time
+	actions []Action
+	at      time.Time
+
+	// Progress toasts, identified by key, stay until FinishProgress.
+	key              string
+	progress         bool
+	completed, total int64
 }
 
 // ToastsModel manages a queue of auto-dismissing toast notifications.
 type ToastsModel struct {
-	queue []toast
+	queue   []toast
+	history []Entry // oldest first
 }
 
 // NewToasts creates an empty ToastsModel.
@@ -44,26 +73,134 @@ func NewToasts() ToastsModel {
 // Add enqueues a toast notification. Oldest toasts are dropped when the queue
 // exceeds maxToasts.
 func (m *ToastsModel) Add(message string, level ToastLevel) {
-	m.queue = append(m.queue, toast{
+	m.AddWithActions(message, level)
+}
+
+// AddWithActions enqueues a toast offering actions, which stays up longer
+// than a plain one so there is time to pick one.
+func (m *ToastsModel) AddWithActions(message string, level ToastLevel, actions ...Action) {
+	ttl := toastTTL
+	if len(actions) > 0 {
+		ttl = actionTTL
+	}
+	now := time.Now()
+	m.push(toast{
 		message: message,
 		level:   level,
-		expiry:  time.Now().Add(toastTTL),
+		expiry:  now.Add(ttl),
+		actions: actions,
+		at:      now,
+	})
+}
+
+// SetProgress shows or updates the progress toast identified by key. A total
+// of 0 shows the message without a bar. The toast stays until FinishProgress.
+func (m *ToastsModel) SetProgress(key, message string, completed, total int64, actions ...Action) {
+	for i := range m.queue {
+		if t := &m.queue[i]; t.progress && t.key == key {
+			t.message, t.completed, t.total, t.actions = message, completed, total, actions
+			return
+		}
+	}
+	m.push(toast{
+		message:   message,
+		level:     ToastInfo,
+		actions:   actions,
+		at:        time.Now(),
+		key:       key,
+		progress:  true,
+		completed: completed,
+		total:     total,
 	})
-	if len(m.queue) > maxToasts {
-		m.queue = m.queue[len(m.queue)-maxToasts:]
+}
+
+// FinishProgress replaces the progress toast identified by key with an
+// ordinary toast, such as "Pulled qwen3:8b", that expires as usual.
+func (m *ToastsModel) FinishProgress(key, message string, level ToastLevel, actions ...Action) {
+	for i, t := range m.queue {
+		if t.progress && t.key == key {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			break
+		}
 	}
+	m.AddWithActions(message, level, actions...)
+}
+
+// push appends t, dismissing the oldest toasts beyond maxToasts. Progress
+// toasts are never dropped this way; they only stack.
+func (m *ToastsModel) push(t toast) {
+	m.queue = append(m.queue, t)
+	for len(m.queue) > maxToasts {
+		i := 0
+		for i < len(m.queue) && m.queue[i].progress {
+			i++
+		}
+		if i == len(m.queue) {
+			return
+		}
+		m.dismiss(i)
+	}
+}
+
+// dismiss removes the toast at i and records it in the history.
+func (m *ToastsModel) dismiss(i int) {
+	t := m.queue[i]
+	m.queue = append(m.queue[:i], m.queue[i+1:]...)
+	m.history = append(m.history, Entry{Message: t.message, Level: t.level, At: t.at})
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+}
+
+// Trigger runs the nth action (1-based) of the newest toast offering any,
+// dismissing that toast. ok is false when there is no such action.
+func (m *ToastsModel) Trigger(n int) (action Action, ok bool) {
+	for i := len(m.queue) - 1; i >= 0; i-- {
+		acts := m.queue[i].actions
+		if len(acts) == 0 {
+			continue
+		}
+		if n < 1 || n > len(acts) {
+			return Action{}, false
+		}
+		action = acts[n-1]
+		if !m.queue[i].progress {
+			m.dismiss(i)
+		}
+		return action, true
+	}
+	return Action{}, false
+}
+
+// HasActions reports whether a visible toast offers actions.
+func (m ToastsModel) HasActions() bool {
+	for _, t := range m.queue {
+		if len(t.actions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// History returns the dismissed toasts, most recent first.
+func (m ToastsModel) History() []Entry {
+	out := make([]Entry, len(m.history))
+	for i, e := range m.history {
+		out[len(m.history)-1-i] = e
+	}
+	return out
 }
 
 // Tick prunes expired toasts. Call on every tick message.
 func (m *ToastsModel) Tick() {
 	now := time.Now()
-	alive := m.queue[:0]
-	for _, t := range m.queue {
-		if now.Before(t.expiry) {
-			alive = append(alive, t)
+	for i := 0; i < len(m.queue); {
+		if t := m.queue[i]; !t.progress && !now.Before(t.expiry) {
+			m.dismiss(i)
+			continue
 		}
+		i++
 	}
-	m.queue = alive
 }
 
 // HasToasts reports whether any toasts are currently visible.
@@ -71,16 +208,33 @@ func (m ToastsModel) HasToasts() bool {
 	return len(m.queue) > 0
 }
 
-// View renders visible toasts as right-aligned colored lines.
+// View renders visible toasts as right-aligned colored lines. Only the
+// newest toast offering actions shows their keys.
 func (m ToastsModel) View(termWidth int) string {
 	if len(m.queue) == 0 {
 		return ""
 	}
+	active := -1
+	for i := len(m.queue) - 1; i >= 0; i-- {
+		if len(m.queue[i].actions) > 0 {
+			active = i
+			break
+		}
+	}
 	var lines []string
-	for _, t := range m.queue {
+	for i, t := range m.queue {
 		icon, col := toastIconColor(t.level)
+		if t.progress {
+			icon, col = "\u21BB", style.Secondary // ↻
+		}
 		text := fmt.Sprintf(" %s %s ", icon, t.message)
 		rendered := lipgloss.NewStyle().Foreground(col).Render(text)
+		if t.progress && t.total > 0 {
+			rendered += renderBar(t.completed, t.total) + " "
+		}
+		if i == active {
+			rendered += renderActions(t.actions)
+		}
 		w := lipgloss.Width(rendered)
 		pad := termWidth - w
 		if pad < 0 {
@@ -91,6 +245,26 @@ func (m ToastsModel) View(termWidth int) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderBar draws a determinate progress bar with its percentage.
+func renderBar(completed, total int64) string {
+	frac := min(max(float64(completed)/float64(total), 0), 1)
+	filled := int(frac * progressBar)
+	bar := lipgloss.NewStyle().Foreground(style.Secondary).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(style.Border).Render(strings.Repeat("░", progressBar-filled))
+	return bar + style.Faint.Render(fmt.Sprintf(" %3d%%", int(frac*100)))
+}
+
+// renderActions draws the action buttons with their keys, such as
+// "[alt+1 Retry]".
+func renderActions(actions []Action) string {
+	var parts []string
+	for i, a := range actions {
+		parts = append(parts, style.Hint.Render(fmt.Sprintf("alt+%d", i+1))+" "+
+			lipgloss.NewStyle().Foreground(style.Primary).Bold(true).Render(a.Label))
+	}
+	return "[" + strings.Join(parts, "] [") + "] "
+}
+
 // toastIconColor returns the icon rune and color.Color for the given level.
 // style.Success/Warning/Error are already color.Color values.
 func toastIconColor(level ToastLevel) (string, color.Color) {
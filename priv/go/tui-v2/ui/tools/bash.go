@@ -3,19 +3,30 @@ package tools
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/miosa/osa-tui/style"
 )
 
-// BashRenderer renders bash/shell tool invocations.
+// BashRenderer renders bash/shell tool invocations: the exit code, and the
+// command's stdout and stderr as tabs when it reports them apart.
 //
 // Collapsed output (15 lines max):
 //
 //	✓ Bash  go build ./...                       2.3s
+//	  │ exit 0
 //	  │ ok  github.com/miosa/osa-tui
 //
-// Error output is highlighted in red. Background jobs show a job indicator.
+//	✘ Shell  go vet ./...                        1.1s
+//	  │ exit 1 · stdout 2 lines · [stderr 14 lines]
+//	  │ ... (4 earlier lines)
+//	  │ ./main.go:12:2: unreachable code
+//
+// A failed command shows the end of its output, where the error usually is;
+// collapsed, only the stderr tab shows, and expanding shows both. Background
+// jobs show a job indicator.
 type BashRenderer struct{}
 
 const bashMaxLines = 15
@@ -23,7 +34,11 @@ const bashMaxLines = 15
 // Render implements ToolRenderer.
 func (r BashRenderer) Render(name, args, result string, opts RenderOpts) string {
 	cmd := extractBashCommand(args)
-	header := renderToolHeader(opts.Status, "Bash", cmd, opts)
+	title := "Bash"
+	if name == "shell_execute" {
+		title = "Shell"
+	}
+	header := renderToolHeader(opts.Status, title, cmd, opts)
 
 	// Compact mode: header only.
 	if opts.Compact {
@@ -32,7 +47,7 @@ func (r BashRenderer) Render(name, args, result string, opts RenderOpts) string
 
 	// Awaiting permission: show pending content instead of output.
 	if opts.Status == ToolAwaitingPermission {
-		content := pendingToolContent("Bash")
+		content := pendingToolContent(title)
 		return renderToolBox(header+"\n"+content, opts.Width)
 	}
 
@@ -46,7 +61,7 @@ func (r BashRenderer) Render(name, args, result string, opts RenderOpts) string
 		return header
 	}
 
-	output := strings.TrimRight(result, "\n")
+	out := parseShellOutput(result, opts.Status)
 
 	// Detect background job.
 	bg := isBackgroundJob(args)
@@ -63,15 +78,129 @@ func (r BashRenderer) Render(name, args, result string, opts RenderOpts) string
 		body.WriteString(jobLine + "\n")
 	}
 
-	// Main output block.
+	// Exit code and stream tabs.
+	showErr := out.stderr != "" && (out.failed || out.stdout == "")
+	var status []string
+	switch {
+	case out.exit > 0:
+		status = append(status, style.ErrorText.Render(fmt.Sprintf("exit %d", out.exit)))
+	case out.exit == 0:
+		status = append(status, style.PrefixDone.Render("exit 0"))
+	}
+	tabs := out.stdout != "" && out.stderr != ""
+	if tabs {
+		status = append(status,
+			shellTab("stdout", out.stdout, !showErr || opts.Expanded),
+			shellTab("stderr", out.stderr, showErr || opts.Expanded))
+	}
+	if len(status) > 0 {
+		body.WriteString(strings.Join(status, style.Faint.Render(" · ")) + "\n")
+	}
+
+	// Output blocks: the active tab collapsed, both expanded.
 	cap := maxDisplayLines(opts.Expanded, bashMaxLines)
-	if opts.Status == ToolError {
-		body.WriteString(style.ErrorText.Render(truncateLines(output, cap)))
-	} else {
-		body.WriteString(style.ToolOutput.Render(truncateLines(output, cap)))
+	var blocks []string
+	if out.stdout != "" && (!showErr || opts.Expanded) {
+		text := trimShellOutput(out.stdout, cap, out.failed)
+		if tabs && opts.Expanded {
+			text = style.Faint.Render("stdout") + "\n" + text
+		}
+		s := style.ToolOutput
+		if out.failed && out.stderr == "" {
+			s = style.ErrorText
+		}
+		blocks = append(blocks, s.Render(text))
+	}
+	if showErr || (out.stderr != "" && opts.Expanded) {
+		text := trimShellOutput(out.stderr, cap, true)
+		if tabs && opts.Expanded {
+			text = style.Faint.Render("stderr") + "\n" + text
+		}
+		blocks = append(blocks, style.ErrorText.Render(text))
+	}
+	body.WriteString(strings.Join(blocks, "\n"))
+
+	return renderToolBox(header+"\n"+strings.TrimRight(body.String(), "\n"), opts.Width)
+}
+
+// shellOutput is a command's result split into its parts.
+type shellOutput struct {
+	stdout string
+	stderr string
+	exit   int // -1 when unknown
+	failed bool
+}
+
+// exitPrefix matches the backend's "Exit 2:" line in front of a failed
+// command's output.
+var exitPrefix = regexp.MustCompile(`^Exit (\d+):\n?`)
+
+// parseShellOutput splits a shell tool's result. JSON results with stdout,
+// stderr and exit_code keep the streams apart; the backend's own results
+// carry the combined output, prefixed with "Error: Exit N:" when the command
+// failed. A failure without an exit code, such as a blocked command, shows
+// none.
+func parseShellOutput(result string, status ToolStatus) shellOutput {
+	out := shellOutput{exit: -1, failed: status == ToolError}
+	trimmed := strings.TrimSpace(result)
+	var m map[string]interface{}
+	if strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &m) == nil {
+		if _, ok := m["stdout"]; ok {
+			out.stdout, _ = m["stdout"].(string)
+			out.stderr, _ = m["stderr"].(string)
+			out.stdout = strings.TrimRight(out.stdout, "\n")
+			out.stderr = strings.TrimRight(out.stderr, "\n")
+			for _, key := range []string{"exit_code", "exitCode", "code"} {
+				if v, ok := m[key].(float64); ok {
+					out.exit = int(v)
+					break
+				}
+			}
+			if out.exit < 0 && status == ToolSuccess {
+				out.exit = 0
+			}
+			out.failed = out.failed || out.exit > 0
+			return out
+		}
 	}
 
-	return renderToolBox(header+"\n"+body.String(), opts.Width)
+	text := strings.TrimRight(result, "\n")
+	rest := strings.TrimPrefix(text, "Error: ")
+	if mm := exitPrefix.FindStringSubmatch(rest); mm != nil {
+		out.exit, _ = strconv.Atoi(mm[1])
+		out.failed = true
+		text = rest[len(mm[0]):]
+	} else if status == ToolError {
+		text = rest
+	} else if status == ToolSuccess {
+		out.exit = 0
+	}
+	out.stdout = text
+	return out
+}
+
+// shellTab renders a stream's tab label with its line count, bracketed when
+// the stream is shown.
+func shellTab(label, text string, active bool) string {
+	tab := fmt.Sprintf("%s %s", label, countLabel(strings.Count(text, "\n")+1, "line"))
+	if active {
+		return style.ToolName.Render("[" + tab + "]")
+	}
+	return style.Faint.Render(tab)
+}
+
+// trimShellOutput cuts output to maxLines: its start, or its end when tail
+// is set, with a hint counting the lines left out.
+func trimShellOutput(output string, maxLines int, tail bool) string {
+	if !tail {
+		return truncateLines(output, maxLines)
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) <= maxLines {
+		return output
+	}
+	skipped := len(lines) - maxLines
+	return style.Faint.Render(fmt.Sprintf("... (%d earlier lines)", skipped)) + "\n" + strings.Join(lines[skipped:], "\n")
 }
 
 // extractBashCommand parses the command string from JSON args.
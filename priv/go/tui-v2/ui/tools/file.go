@@ -3,6 +3,8 @@ package tools
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/miosa/osa-tui/style"
@@ -13,15 +15,17 @@ import (
 // FileViewRenderer — Read / View
 // ---------------------------------------------------------------------------
 
-// FileViewRenderer renders file read/view tool invocations with line numbers.
+// FileViewRenderer renders file read/view tool invocations as a line-numbered
+// excerpt. Numbers continue from the read's offset, and output already
+// numbered `cat -n`-style keeps its own numbers.
 //
 // Example:
 //
 //	✓ Read  config/runtime.exs                  23ms
-//	  │  1 │ import Config
-//	  │  2 │
-//	  │  3 │ config :app, :key, "value"
-//	  │    │ ... (47 more lines)
+//	  │    1 │ import Config
+//	  │    2 │
+//	  │    3 │ config :app, :key, "value"
+//	  │      │ ... (47 more lines)
 type FileViewRenderer struct{}
 
 const fileViewMaxLines = 10
@@ -48,26 +52,40 @@ func (r FileViewRenderer) Render(name, args, result string, opts RenderOpts) str
 		return header
 	}
 
-	preview := buildReadPreview(result, maxDisplayLines(opts.Expanded, fileViewMaxLines))
+	if opts.Status == ToolError {
+		return renderToolBox(header+"\n"+toolErrorContent(result, fileViewMaxLines, opts.Expanded), opts.Width)
+	}
+
+	preview := buildReadPreview(result, extractReadOffset(args), maxDisplayLines(opts.Expanded, fileViewMaxLines))
 	return renderToolBox(header+"\n"+preview, opts.Width)
 }
 
-// buildReadPreview produces a numbered-line preview of file content, truncated
-// to maxLines. It respects `cat -n`-style numeric prefixes already present in
-// the content (e.g. from the Read tool's output format).
-func buildReadPreview(content string, maxLines int) string {
+// numberedLine matches a line of `cat -n`-style output, "    12\tcode" or
+// "    12→code".
+var numberedLine = regexp.MustCompile(`^\s*(\d+)(?:\t|→)(.*)$`)
+
+// buildReadPreview produces a numbered-line preview of file content starting
+// at line offset, truncated to maxLines. Content whose first line is already
+// numbered keeps its numbers instead.
+func buildReadPreview(content string, offset, maxLines int) string {
 	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
 	total := len(lines)
 
-	// Detect line offset from `cat -n` style numeric prefix on the first line.
-	offset := 1
-	if len(lines) > 0 {
-		first := strings.TrimSpace(lines[0])
-		var n int
-		if _, err := fmt.Sscanf(first, "%d", &n); err == nil && n > 1 {
-			offset = n
+	numbers := make([]string, total)
+	numbered := numberedLine.MatchString(lines[0])
+	last := offset + total - 1
+	for i, line := range lines {
+		numbers[i] = strconv.Itoa(offset + i)
+		if !numbered {
+			continue
+		}
+		numbers[i] = ""
+		if m := numberedLine.FindStringSubmatch(line); m != nil {
+			numbers[i], lines[i] = m[1], m[2]
+			last, _ = strconv.Atoi(m[1])
 		}
 	}
+	numW := max(len(strconv.Itoa(last)), 4)
 
 	visible := lines
 	truncated := false
@@ -77,15 +95,15 @@ func buildReadPreview(content string, maxLines int) string {
 	}
 
 	var sb strings.Builder
+	sep := style.Faint.Render(" │ ")
 	for i, line := range visible {
-		lineNo := style.LineNumber.Render(fmt.Sprintf("%4d", offset+i))
-		sep := style.Faint.Render(" │ ")
+		lineNo := style.LineNumber.Render(fmt.Sprintf("%*s", numW, numbers[i]))
 		sb.WriteString(lineNo + sep + line + "\n")
 	}
 
 	if truncated {
 		remaining := total - maxLines
-		hint := style.Faint.Render(fmt.Sprintf("     │ ... (%d more lines)", remaining))
+		hint := style.Faint.Render(fmt.Sprintf("%*s │ ... (%d more lines)", numW, "", remaining))
 		sb.WriteString(hint)
 	} else {
 		result := sb.String()
@@ -95,6 +113,21 @@ func buildReadPreview(content string, maxLines int) string {
 	return sb.String()
 }
 
+// extractReadOffset parses the first line read from JSON args.
+// Keys checked: "offset", "start_line", "line". Defaults to 1.
+func extractReadOffset(args string) int {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(args)), &m); err != nil {
+		return 1
+	}
+	for _, key := range []string{"offset", "start_line", "line"} {
+		if v, ok := m[key].(float64); ok && v >= 1 {
+			return int(v)
+		}
+	}
+	return 1
+}
+
 // ---------------------------------------------------------------------------
 // FileWriteRenderer — Write / Create
 // ---------------------------------------------------------------------------
@@ -208,6 +241,10 @@ func (r FileEditRenderer) Render(name, args, result string, opts RenderOpts) str
 		return header
 	}
 
+	if opts.Status == ToolError {
+		return renderToolBox(header+"\n"+toolErrorContent(result, fileEditMaxLines, opts.Expanded), opts.Width)
+	}
+
 	diffContent := buildEditDiff(args, result, opts.Width-4)
 	if diffContent == "" {
 		return header
@@ -230,7 +267,8 @@ func resolveEditDisplayName(name string) string {
 }
 
 // extractFilePath parses the file path from JSON args.
-// Keys checked: "path", "file_path", "filename", "target_file".
+// Keys checked: "path", "file_path", "filename", "target_file". Args that are
+// not JSON are the path itself, as the backend sends it.
 func extractFilePath(args string) string {
 	args = strings.TrimSpace(args)
 	if args == "" {
@@ -245,24 +283,26 @@ func extractFilePath(args string) string {
 				}
 			}
 		}
+		return ""
 	}
-	return ""
+	if strings.ContainsAny(args, "{}\n") {
+		return ""
+	}
+	return args
 }
 
 // buildEditDiff produces a colored diff string from the args JSON.
 //
 //   - str_replace_editor / Edit: uses old_string vs new_string via diff.RenderDiff.
 //   - Write: shows all content lines as additions.
-//   - Fallback: renders result verbatim via ToolOutput style.
+//   - Fallback: renders the diff in the result, or the result verbatim via
+//     ToolOutput style.
 func buildEditDiff(args, result string, width int) string {
 	args = strings.TrimSpace(args)
 
 	var m map[string]interface{}
 	if err := json.Unmarshal([]byte(args), &m); err != nil {
-		if result != "" {
-			return style.ToolOutput.Render(strings.TrimSpace(result))
-		}
-		return ""
+		return resultDiff(result, width)
 	}
 
 	oldStr, _ := m["old_string"].(string)
@@ -281,10 +321,25 @@ func buildEditDiff(args, result string, width int) string {
 	}
 
 	// Fallback to result text.
-	if result != "" {
-		return style.ToolOutput.Render(strings.TrimSpace(result))
+	return resultDiff(result, width)
+}
+
+// resultDiff renders the unified diff in an edit tool's result, such as the
+// backend's "Replaced in lib/app.ex" followed by the changed hunk. The lines
+// before the first hunk, which name the file already in the header, are
+// dropped. Results without a hunk render verbatim.
+func resultDiff(result string, width int) string {
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return ""
 	}
-	return ""
+	lines := strings.Split(result, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			return diff.RenderUnifiedDiff(strings.Join(lines[i:], "\n"), width)
+		}
+	}
+	return style.ToolOutput.Render(result)
 }
 
 // renderAdditions renders every line as a diff-add (green "+") for new file writes.
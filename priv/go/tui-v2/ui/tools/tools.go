@@ -46,6 +46,7 @@ var Registry = map[string]ToolRenderer{
 	"bash":             BashRenderer{},
 	"Bash":             BashRenderer{},
 	"run_bash_command": BashRenderer{},
+	"shell_execute":    BashRenderer{},
 
 	// File read / view
 	"Read":      FileViewRenderer{},
@@ -55,6 +56,7 @@ var Registry = map[string]ToolRenderer{
 	// File write / create
 	"Write":      FileWriteRenderer{},
 	"write_file": FileWriteRenderer{},
+	"file_write": FileWriteRenderer{},
 
 	// File edit
 	"Edit":               FileEditRenderer{},
@@ -83,6 +85,7 @@ var Registry = map[string]ToolRenderer{
 	"LS":             LSRenderer{},
 	"ls":             LSRenderer{},
 	"list_directory": LSRenderer{},
+	"dir_list":       LSRenderer{},
 
 	// Web
 	"web_fetch": WebFetchRenderer{},
@@ -129,25 +132,46 @@ func RenderToolCall(name, args, result string, opts RenderOpts) string {
 	return r.Render(name, args, result, opts)
 }
 
+// TouchedFile returns the path a file tool (read, write, edit, download)
+// operated on, or "" for other tools.
+func TouchedFile(name, args string) string {
+	switch Registry[name].(type) {
+	case FileViewRenderer, FileWriteRenderer, FileEditRenderer, MultiEditRenderer, FileDownloadRenderer:
+		return extractFilePath(args)
+	}
+	return ""
+}
+
+// ChangedFile returns the path a file-changing tool (write, edit) modified,
+// or "" for other tools, including reads.
+func ChangedFile(name, args string) string {
+	switch Registry[name].(type) {
+	case FileWriteRenderer, FileEditRenderer, MultiEditRenderer:
+		return extractFilePath(args)
+	}
+	return ""
+}
+
 // ---------------------------------------------------------------------------
 // Status helpers
 // ---------------------------------------------------------------------------
 
-// StatusIcon returns the terminal glyph for a given ToolStatus.
+// StatusIcon returns the terminal glyph for a given ToolStatus, or a spoken
+// status word in screen-reader mode.
 func StatusIcon(s ToolStatus) string {
 	switch s {
 	case ToolPending:
-		return style.Faint.Render("○")
+		return style.Faint.Render(style.Glyph("○", "Pending:"))
 	case ToolAwaitingPermission:
-		return style.ToolStatusRunning.Render("◐")
+		return style.ToolStatusRunning.Render(style.Glyph("◐", "Awaiting permission:"))
 	case ToolRunning:
-		return style.PrefixActive.Render("⏺")
+		return style.PrefixActive.Render(style.Glyph("⏺", "Running:"))
 	case ToolSuccess:
-		return style.PrefixDone.Render("✓")
+		return style.PrefixDone.Render(style.Glyph("✓", "Done:"))
 	case ToolError:
-		return style.ErrorText.Render("✘")
+		return style.ErrorText.Render(style.Glyph("✘", "Failed:"))
 	case ToolCanceled:
-		return style.Faint.Render("⊘")
+		return style.Faint.Render(style.Glyph("⊘", "Cancelled:"))
 	default:
 		return " "
 	}
@@ -163,7 +187,7 @@ func renderToolBox(content string, width int) string {
 		return ""
 	}
 	box := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
+		Border(style.Frame(lipgloss.NormalBorder()), false, false, false, true).
 		BorderForeground(style.Border).
 		PaddingLeft(1).
 		Width(width - 2)
@@ -283,6 +307,14 @@ func toolOutputMarkdownContent(md string, width int) string {
 	return style.ToolOutput.Render(strings.TrimRight(md, "\n"))
 }
 
+// toolErrorContent renders the result of a failed call as error text,
+// without the backend's "Error: " prefix, truncated to maxLines when not
+// expanded.
+func toolErrorContent(result string, maxLines int, expanded bool) string {
+	text := strings.TrimPrefix(strings.TrimSpace(result), "Error: ")
+	return style.ErrorText.Render(truncateLines(text, maxDisplayLines(expanded, maxLines)))
+}
+
 // pendingToolContent returns a "waiting for permission" message.
 func pendingToolContent(name string) string {
 	return style.ToolStatusRunning.Render("◐ ") +
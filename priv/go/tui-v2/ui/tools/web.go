@@ -3,6 +3,8 @@ package tools
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/miosa/osa-tui/style"
@@ -12,21 +14,28 @@ import (
 // WebFetchRenderer
 // ---------------------------------------------------------------------------
 
-// WebFetchRenderer renders web fetch/HTTP tool invocations.
+// WebFetchRenderer renders web fetch/HTTP tool invocations as the page's
+// title, URL and a summary of its text.
 //
 // Example:
 //
-//	✓ WebFetch  https://api.example.com          1.2s
-//	  │ (4.2KB)
-//	  │ {"status": "ok", ...}
+//	✓ WebFetch  go.dev                           1.2s
+//	  │ Effective Go - The Go Programming Language
+//	  │ https://go.dev/doc/effective_go  (42.1KB)
+//	  │ Go is a new language. Although it borrows ideas from existing
+//	  │ languages, it has unusual properties that make effective Go…
 type WebFetchRenderer struct{}
 
-const webFetchMaxLines = 15
+const (
+	webFetchMaxLines = 15
+	// webFetchSummary is the length of the collapsed summary in characters.
+	webFetchSummary = 280
+)
 
 // Render implements ToolRenderer.
 func (r WebFetchRenderer) Render(name, args, result string, opts RenderOpts) string {
-	url := extractURL(args)
-	header := renderToolHeader(opts.Status, "WebFetch", url, opts)
+	page := parseFetchResult(extractURL(args), result)
+	header := renderToolHeader(opts.Status, "WebFetch", page.host(), opts)
 
 	if opts.Compact {
 		return header
@@ -36,25 +45,93 @@ func (r WebFetchRenderer) Render(name, args, result string, opts RenderOpts) str
 		return header
 	}
 
-	body := buildFetchBody(result, maxDisplayLines(opts.Expanded, webFetchMaxLines))
+	if opts.Status == ToolError {
+		return renderToolBox(header+"\n"+toolErrorContent(result, webFetchMaxLines, opts.Expanded), opts.Width)
+	}
+
+	body := buildFetchBody(page, opts.Expanded)
 	return renderToolBox(header+"\n"+body, opts.Width)
 }
 
-// buildFetchBody formats the fetch response with a size annotation.
-func buildFetchBody(result string, maxLines int) string {
+// fetchedPage is a fetch result split into its parts.
+type fetchedPage struct {
+	title string
+	url   string
+	text  string
+}
+
+// host returns the page's host for the header, or the URL when it has none.
+func (p fetchedPage) host() string {
+	if u, err := url.Parse(p.url); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return p.url
+}
+
+// fetchPreamble matches the backend's "Content from https://…:" line above
+// the page text.
+var fetchPreamble = regexp.MustCompile(`^.* from (\S+):$`)
+
+// parseFetchResult splits a fetch result. JSON results name their title, URL
+// and text; the backend's own results open with a "… from <url>:" line and
+// an optional "Title: …" line. rawURL, from the call's args, is the URL when
+// the result names none.
+func parseFetchResult(rawURL, result string) fetchedPage {
+	page := fetchedPage{url: rawURL}
 	trimmed := strings.TrimSpace(result)
+	var m map[string]interface{}
+	if strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &m) == nil {
+		page.title, _ = m["title"].(string)
+		if u, ok := m["url"].(string); ok && u != "" {
+			page.url = u
+		}
+		for _, key := range []string{"summary", "text", "content", "body"} {
+			if v, ok := m[key].(string); ok && v != "" {
+				page.text = strings.TrimSpace(v)
+				break
+			}
+		}
+		if page.title != "" || page.text != "" {
+			return page
+		}
+	}
+
+	lines := strings.Split(trimmed, "\n")
+	if mm := fetchPreamble.FindStringSubmatch(lines[0]); mm != nil {
+		page.url = mm[1]
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "Title: ") {
+		page.title = strings.TrimSpace(strings.TrimPrefix(lines[0], "Title: "))
+		lines = lines[1:]
+	}
+	page.text = strings.TrimSpace(strings.Join(lines, "\n"))
+	return page
+}
 
-	// Size annotation.
+// buildFetchBody formats the fetched page: its title, its URL with the
+// text's size, and the text, cut to a summary unless expanded.
+func buildFetchBody(page fetchedPage, expanded bool) string {
 	var parts []string
-	if len(trimmed) > 0 {
-		kb := float64(len(trimmed)) / 1024.0
-		if kb >= 1 {
-			parts = append(parts, style.Faint.Render(fmt.Sprintf("(%.1fKB)", kb)))
-		}
+	if page.title != "" {
+		parts = append(parts, style.ToolName.Render(page.title))
+	}
+
+	source := style.Faint.Render(page.url)
+	if kb := float64(len(page.text)) / 1024.0; kb >= 1 {
+		source += style.Faint.Render(fmt.Sprintf("  (%.1fKB)", kb))
+	}
+	if page.url != "" {
+		parts = append(parts, source)
 	}
 
-	preview := truncateLines(trimmed, maxLines)
-	parts = append(parts, style.ToolOutput.Render(preview))
+	if page.text != "" {
+		text := truncateLines(page.text, maxDisplayLines(expanded, webFetchMaxLines))
+		if !expanded {
+			text = truncateString(strings.Join(strings.Fields(page.text), " "), webFetchSummary)
+		}
+		parts = append(parts, style.ToolOutput.Render(text))
+	}
 	return strings.Join(parts, "\n")
 }
 
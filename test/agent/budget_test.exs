@@ -36,6 +36,10 @@ defmodule OptimalSystemAgent.Agent.BudgetTest do
     GenServer.call(name, :get_status)
   end
 
+  defp set_limits(name, limits) do
+    GenServer.call(name, {:set_limits, limits})
+  end
+
   defp reset_daily(name) do
     GenServer.cast(name, :reset_daily)
     Process.sleep(10)
@@ -115,6 +119,78 @@ defmodule OptimalSystemAgent.Agent.BudgetTest do
     end
   end
 
+  # ---------------------------------------------------------------------------
+  # session limit
+  # ---------------------------------------------------------------------------
+
+  describe "session limit" do
+    test "returns over_limit for a session past its limit" do
+      {_pid, name} = start_budget(daily_limit: 1000.0, monthly_limit: 1000.0, session_limit: 0.01)
+
+      record_cost(name, :anthropic, "claude-sonnet-4-6", 10_000, 10_000, "session_1")
+
+      assert {:over_limit, :session} = GenServer.call(name, {:check_budget, "session_1"})
+      assert {:ok, _} = GenServer.call(name, {:check_budget, "session_2"})
+      assert {:ok, _} = check_budget(name)
+    end
+
+    test "get_status reports a session's spend against the limit" do
+      {_pid, name} = start_budget(session_limit: 1.0)
+
+      record_cost(name, :anthropic, "claude-sonnet-4-6", 10_000, 10_000, "session_1")
+
+      {:ok, status} = GenServer.call(name, {:get_status, "session_1"})
+      assert status.session_limit == 1.0
+      assert status.session_spent > 0.0
+      assert status.session_remaining < 1.0
+
+      {:ok, status} = get_status(name)
+      refute Map.has_key?(status, :session_spent)
+    end
+  end
+
+  # ---------------------------------------------------------------------------
+  # set_limits
+  # ---------------------------------------------------------------------------
+
+  describe "set_limits/1" do
+    test "changes the limits" do
+      {_pid, name} = start_budget()
+
+      assert {:ok, status} = set_limits(name, %{daily_limit: 20, session_limit: 0.5})
+      assert status.daily_limit == 20.0
+      assert status.session_limit == 0.5
+      assert status.monthly_limit == 100.0
+
+      {:ok, status} = get_status(name)
+      assert status.daily_limit == 20.0
+    end
+
+    test "a nil session_limit removes it" do
+      {_pid, name} = start_budget(session_limit: 0.01)
+
+      record_cost(name, :anthropic, "claude-sonnet-4-6", 10_000, 10_000, "session_1")
+      assert {:over_limit, :session} = GenServer.call(name, {:check_budget, "session_1"})
+
+      assert {:ok, %{session_limit: nil}} = set_limits(name, %{session_limit: nil})
+      assert {:ok, _} = GenServer.call(name, {:check_budget, "session_1"})
+    end
+
+    test "rejects an invalid limit without changing any" do
+      {_pid, name} = start_budget()
+
+      assert {:error, {:invalid_limit, :monthly_limit}} =
+               set_limits(name, %{daily_limit: 20.0, monthly_limit: -1})
+
+      assert {:error, {:invalid_limit, :daily_limit}} = set_limits(name, %{daily_limit: nil})
+      assert {:error, {:invalid_limit, :bogus}} = set_limits(name, %{bogus: 1.0})
+
+      {:ok, status} = get_status(name)
+      assert status.daily_limit == 10.0
+      assert status.monthly_limit == 100.0
+    end
+  end
+
   # ---------------------------------------------------------------------------
   # get_status
   # ---------------------------------------------------------------------------
@@ -11,6 +11,80 @@ defmodule OptimalSystemAgent.Agent.LoopTest do
     "smoke-loop-#{:erlang.unique_integer([:positive])}"
   end
 
+  # Sets an application env key for the current test only.
+  defp put_test_env(key, value) do
+    previous = Application.fetch_env(:optimal_system_agent, key)
+    Application.put_env(:optimal_system_agent, key, value)
+
+    on_exit(fn ->
+      case previous do
+        {:ok, value} -> Application.put_env(:optimal_system_agent, key, value)
+        :error -> Application.delete_env(:optimal_system_agent, key)
+      end
+    end)
+  end
+
+  # Runs a message against the fake Anthropic server in a task.
+  defp process_async(session_id, message, opts \\ []) do
+    Task.async(fn ->
+      Loop.process_message(session_id, message, [provider: :anthropic, skip_plan: true] ++ opts)
+    end)
+  end
+
+  # Stands in for the Anthropic messages API. Every request is announced to
+  # the test as `{:fake_anthropic, :stream | :sync, server}` and held until
+  # the test sends `:go` to `server`; a streamed reply sends "Hello" before
+  # the hold and " world" after it.
+  defmodule FakeAnthropic do
+    @behaviour Plug
+
+    import Plug.Conn
+
+    @impl true
+    def init(test_pid), do: test_pid
+
+    @impl true
+    def call(conn, test_pid) do
+      {:ok, body, conn} = read_body(conn)
+
+      if Jason.decode!(body)["stream"] do
+        conn = send_chunked(conn, 200)
+        conn = event(conn, text_delta("Hello"))
+        hold(test_pid, :stream)
+        conn = event(conn, text_delta(" world"))
+        event(conn, %{type: "message_stop"})
+      else
+        hold(test_pid, :sync)
+
+        conn
+        |> put_resp_content_type("application/json")
+        |> send_resp(200, Jason.encode!(%{content: [%{type: "text", text: "signal"}], usage: %{}}))
+      end
+    end
+
+    defp hold(test_pid, kind) do
+      send(test_pid, {:fake_anthropic, kind, self()})
+
+      receive do
+        :go -> :ok
+      after
+        5_000 -> :ok
+      end
+    end
+
+    defp text_delta(text) do
+      %{type: "content_block_delta", index: 0, delta: %{type: "text_delta", text: text}}
+    end
+
+    # The client may stop reading once it cancels; a failed write is fine.
+    defp event(conn, data) do
+      case chunk(conn, "data: #{Jason.encode!(data)}\n\n") do
+        {:ok, conn} -> conn
+        {:error, _} -> conn
+      end
+    end
+  end
+
   # ---------------------------------------------------------------------------
   # Module smoke tests
   # ---------------------------------------------------------------------------
@@ -88,4 +162,155 @@ defmodule OptimalSystemAgent.Agent.LoopTest do
       assert Loop.get_owner(session_id) == user_id
     end
   end
+
+  # ---------------------------------------------------------------------------
+  # process_message/3 provider/model overrides
+  # ---------------------------------------------------------------------------
+
+  describe "process_message/3 overrides" do
+    # An unregistered provider fails fast inside the loop, so no LLM is needed
+    # to see which provider/model the call was routed to.
+    @message "Explain how the retry command picks a model for this session"
+
+    test "the per-call model reaches the loop" do
+      session_id = unique_session_id()
+
+      start_supervised!(
+        {Loop, [session_id: session_id, channel: :cli]},
+        id: String.to_atom(session_id)
+      )
+
+      assert {:ok, _} =
+               Loop.process_message(session_id, @message,
+                 provider: :loop_test_provider,
+                 model: "retry-model",
+                 skip_plan: true
+               )
+
+      meta = Loop.get_metadata(session_id)
+      assert meta.provider == :loop_test_provider
+      assert meta.model == "retry-model"
+    end
+
+    test "overrides do not stick to the session" do
+      session_id = unique_session_id()
+
+      start_supervised!(
+        {Loop, [session_id: session_id, channel: :cli, provider: :loop_test_provider, model: "pinned"]},
+        id: String.to_atom(session_id)
+      )
+
+      Loop.process_message(session_id, @message, model: "retry-model", skip_plan: true)
+      assert Loop.get_metadata(session_id).model == "retry-model"
+
+      Loop.process_message(session_id, @message, skip_plan: true)
+      assert Loop.get_metadata(session_id).model == "pinned"
+    end
+  end
+
+  describe "allowed_tools" do
+    test "an empty allowlist leaves the session's tools untouched afterwards" do
+      session_id = unique_session_id()
+      tool = %{name: "loop_test_tool", description: "test", parameters: %{}}
+
+      pid =
+        start_supervised!(
+          {Loop, [session_id: session_id, channel: :cli, provider: :loop_test_provider, extra_tools: [tool]]},
+          id: String.to_atom(session_id)
+        )
+
+      Loop.process_message(session_id,
+        "Explain how the tool toggles limit what this session may call",
+        allowed_tools: [],
+        skip_plan: true
+      )
+
+      state = :sys.get_state(pid)
+      assert tool in state.tools
+      assert state.allowed_tools == nil
+    end
+  end
+
+  # ---------------------------------------------------------------------------
+  # cancel/2
+  # ---------------------------------------------------------------------------
+
+  describe "cancel/2" do
+    setup do
+      server = start_supervised!({Bandit, plug: {FakeAnthropic, self()}, ip: :loopback, port: 0, startup_log: false})
+      {:ok, {_ip, port}} = ThousandIsland.listener_info(server)
+
+      put_test_env(:anthropic_api_key, "loop-test-key")
+      put_test_env(:anthropic_url, "http://127.0.0.1:#{port}/v1")
+
+      session_id = unique_session_id()
+      start_supervised!({Loop, [session_id: session_id, channel: :cli]}, id: String.to_atom(session_id))
+
+      %{session_id: session_id}
+    end
+
+    test "returns :not_running when no request is in flight", %{session_id: session_id} do
+      assert Loop.cancel(session_id) == {:error, :not_running}
+      assert Loop.cancel("nonexistent-session-#{:erlang.unique_integer([:positive])}") == {:error, :not_running}
+    end
+
+    test "returns :not_running for a request id that is not in flight", %{session_id: session_id} do
+      task = process_async(session_id, @message, request_id: "req-loop-test")
+      assert_receive {:fake_anthropic, :stream, server}, 5_000
+
+      assert Loop.cancel(session_id, "req-other") == {:error, :not_running}
+      assert [{^session_id, "req-loop-test", false}] = :ets.lookup(:osa_loop_requests, session_id)
+
+      send(server, :go)
+      assert {:ok, response} = Task.await(task, 10_000)
+      assert response =~ "Hello world"
+    end
+
+    test "the request row is removed once the request finishes", %{session_id: session_id} do
+      task = process_async(session_id, @message)
+      assert_receive {:fake_anthropic, :stream, server}, 5_000
+      assert [{^session_id, _request_id, false}] = :ets.lookup(:osa_loop_requests, session_id)
+
+      send(server, :go)
+      assert {:ok, _} = Task.await(task, 10_000)
+
+      assert :ets.lookup(:osa_loop_requests, session_id) == []
+      assert Loop.cancel(session_id) == {:error, :not_running}
+      refute Loop.get_metadata(session_id).cancelled
+    end
+
+    test "cancelling mid-stream stops the stream", %{session_id: session_id} do
+      task = process_async(session_id, @message, request_id: "req-loop-test")
+      assert_receive {:fake_anthropic, :stream, server}, 5_000
+
+      assert Loop.cancel(session_id, "req-loop-test") == {:ok, "req-loop-test"}
+      send(server, :go)
+
+      assert Task.await(task, 10_000) == {:ok, "Request cancelled."}
+      assert Loop.get_metadata(session_id).cancelled
+      assert :ets.lookup(:osa_loop_requests, session_id) == []
+      assert Loop.cancel(session_id) == {:error, :not_running}
+    end
+
+    test "cancelling before the first iteration never calls the model", %{session_id: session_id} do
+      # An uncertain message (weight 0.3-0.6) makes the noise filter ask the
+      # default provider before the loop starts; the fake holds that call.
+      put_test_env(:noise_filter_llm_enabled, true)
+      put_test_env(:default_provider, :anthropic)
+
+      task = process_async(session_id, "Rename helper #{:erlang.unique_integer([:positive])}")
+      assert_receive {:fake_anthropic, :sync, server}, 5_000
+
+      assert {:ok, _request_id} = Loop.cancel(session_id)
+      send(server, :go)
+
+      assert Task.await(task, 10_000) == {:ok, "Request cancelled."}
+      refute_received {:fake_anthropic, :stream, _}
+
+      meta = Loop.get_metadata(session_id)
+      assert meta.cancelled
+      assert meta.iteration_count == 0
+      assert :ets.lookup(:osa_loop_requests, session_id) == []
+    end
+  end
 end
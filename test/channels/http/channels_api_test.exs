@@ -0,0 +1,151 @@
+defmodule OptimalSystemAgent.Channels.HTTP.ChannelsAPITest do
+  use ExUnit.Case, async: false
+  use Plug.Test
+
+  alias OptimalSystemAgent.Channels.HTTP.API
+
+  @opts API.init([])
+
+  # ── Helpers ──────────────────────────────────────────────────────────
+
+  setup do
+    # Keep config.json out of the real ~/.osa and the token out of the env.
+    dir = Path.join(System.tmp_dir!(), "osa_channels_api_#{System.unique_integer([:positive])}")
+    File.mkdir_p!(dir)
+
+    saved =
+      for key <- [:require_auth, :config_dir, :telegram_bot_token],
+          do: {key, Application.get_env(:optimal_system_agent, key)}
+
+    Application.put_env(:optimal_system_agent, :require_auth, false)
+    Application.put_env(:optimal_system_agent, :config_dir, dir)
+    Application.delete_env(:optimal_system_agent, :telegram_bot_token)
+
+    on_exit(fn ->
+      for {key, value} <- saved do
+        if is_nil(value),
+          do: Application.delete_env(:optimal_system_agent, key),
+          else: Application.put_env(:optimal_system_agent, key, value)
+      end
+
+      File.rm_rf!(dir)
+    end)
+
+    {:ok, dir: dir}
+  end
+
+  defp json_put(path, body) do
+    conn(:put, path, Jason.encode!(body))
+    |> put_req_header("content-type", "application/json")
+    |> API.call(@opts)
+  end
+
+  defp telegram_listing do
+    conn = conn(:get, "/channels") |> API.call(@opts)
+    assert conn.status == 200
+
+    conn.resp_body
+    |> Jason.decode!()
+    |> Map.fetch!("channels")
+    |> Enum.find(&(&1["name"] == "telegram"))
+  end
+
+  defp saved_config(dir) do
+    dir |> Path.join("config.json") |> File.read!() |> Jason.decode!()
+  end
+
+  # ── GET /channels ────────────────────────────────────────────────────
+
+  describe "GET /channels" do
+    test "lists a channel whose saved settings are unreadable as unset", %{dir: dir} do
+      File.write!(Path.join(dir, "config.json"), Jason.encode!(%{channels: %{telegram: "not an object"}}))
+
+      listing = telegram_listing()
+      assert listing["configured"] == false
+      assert listing["enabled"] == true
+      assert listing["fields"] == []
+      assert listing["connected"] == false
+    end
+
+    test "lists every channel with its settings" do
+      conn = conn(:get, "/channels") |> API.call(@opts)
+
+      assert conn.status == 200
+      body = Jason.decode!(conn.resp_body)
+      assert body["count"] == length(body["channels"])
+      assert Enum.all?(body["channels"], &Map.has_key?(&1, "fields"))
+      assert [%{"key" => "token", "set" => false}] = telegram_listing()["fields"]
+    end
+  end
+
+  # ── PUT /channels/:name ──────────────────────────────────────────────
+
+  describe "PUT /channels/:name" do
+    test "saves a field and returns it masked", %{dir: dir} do
+      conn = json_put("/channels/telegram", %{fields: %{token: "123456:ABCDEFGHx9Qk"}})
+
+      assert conn.status == 200
+      body = Jason.decode!(conn.resp_body)
+      assert body["configured"] == true
+      assert [%{"key" => "token", "set" => true, "value" => "••••x9Qk"}] = body["fields"]
+
+      assert get_in(saved_config(dir), ["channels", "telegram", "token"]) == "123456:ABCDEFGHx9Qk"
+      assert telegram_listing()["configured"] == true
+    end
+
+    test "an empty string clears a field", %{dir: dir} do
+      assert json_put("/channels/telegram", %{fields: %{token: "123456:ABCDEFGHx9Qk"}}).status == 200
+
+      conn = json_put("/channels/telegram", %{fields: %{token: ""}})
+
+      assert conn.status == 200
+      body = Jason.decode!(conn.resp_body)
+      assert body["configured"] == false
+      assert [%{"key" => "token", "set" => false}] = body["fields"]
+      assert get_in(saved_config(dir), ["channels", "telegram"]) == %{}
+    end
+
+    test "enabling a channel without credentials is a 422 and it stays unconfigured", %{dir: dir} do
+      conn = json_put("/channels/telegram", %{enabled: true})
+
+      assert conn.status == 422
+      assert Jason.decode!(conn.resp_body)["error"] == "not_configured"
+      assert get_in(saved_config(dir), ["channels", "telegram", "enabled"]) == true
+
+      listing = telegram_listing()
+      assert listing["configured"] == false
+      assert listing["enabled"] == true
+    end
+
+    test "disabling a channel saves enabled false", %{dir: dir} do
+      conn = json_put("/channels/telegram", %{enabled: false})
+
+      assert conn.status == 200
+      body = Jason.decode!(conn.resp_body)
+      assert body["enabled"] == false
+      assert body["configured"] == false
+      assert get_in(saved_config(dir), ["channels", "telegram", "enabled"]) == false
+    end
+
+    test "returns 404 for an unknown channel" do
+      conn = json_put("/channels/carrier_pigeon", %{enabled: true})
+
+      assert conn.status == 404
+      assert Jason.decode!(conn.resp_body)["error"] == "not_found"
+    end
+
+    test "returns 400 for an unknown field" do
+      conn = json_put("/channels/telegram", %{fields: %{webhook: "https://example.com"}})
+
+      assert conn.status == 400
+      assert Jason.decode!(conn.resp_body)["details"] =~ "webhook"
+    end
+
+    test "returns 400 when enabled is not a boolean" do
+      conn = json_put("/channels/telegram", %{enabled: "yes"})
+
+      assert conn.status == 400
+      assert Jason.decode!(conn.resp_body)["error"] == "invalid_request"
+    end
+  end
+end
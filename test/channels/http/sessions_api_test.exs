@@ -0,0 +1,137 @@
+defmodule OptimalSystemAgent.Channels.HTTP.SessionsAPITest do
+  use ExUnit.Case, async: false
+  use Plug.Test
+
+  alias OptimalSystemAgent.Agent.Loop
+  alias OptimalSystemAgent.Channels.HTTP.{API, Auth}
+  alias OptimalSystemAgent.SDK.Session
+
+  @opts API.init([])
+
+  # ── Helpers ──────────────────────────────────────────────────────────
+
+  setup do
+    original_auth = Application.get_env(:optimal_system_agent, :require_auth)
+    Application.put_env(:optimal_system_agent, :require_auth, false)
+
+    on_exit(fn ->
+      if original_auth,
+        do: Application.put_env(:optimal_system_agent, :require_auth, original_auth),
+        else: Application.delete_env(:optimal_system_agent, :require_auth)
+    end)
+
+    :ok
+  end
+
+  defp sessions_dir do
+    Application.get_env(:optimal_system_agent, :sessions_dir, "~/.osa/sessions") |> Path.expand()
+  end
+
+  # Writes a persisted session last active long ago, owned by owner (nil
+  # for none), and returns its id.
+  defp persisted_session(owner) do
+    session_id = "sessions-api-test-#{System.unique_integer([:positive])}"
+
+    line =
+      %{role: "user", content: "An old question", timestamp: "2020-01-01T00:00:00Z"}
+      |> then(&if(owner, do: Map.put(&1, :user_id, owner), else: &1))
+      |> Jason.encode!()
+
+    File.mkdir_p!(sessions_dir())
+    File.write!(Path.join(sessions_dir(), "#{session_id}.jsonl"), line <> "\n")
+
+    on_exit(fn ->
+      File.rm(Path.join(sessions_dir(), "#{session_id}.jsonl"))
+      File.rm(Path.join([sessions_dir(), "archive", "#{session_id}.jsonl"]))
+    end)
+
+    session_id
+  end
+
+  defp json_post(path, body, user_id \\ nil) do
+    conn = conn(:post, path, Jason.encode!(body)) |> put_req_header("content-type", "application/json")
+
+    conn =
+      if user_id,
+        do: put_req_header(conn, "authorization", "Bearer " <> Auth.generate_token(%{"user_id" => user_id})),
+        else: conn
+
+    API.call(conn, @opts)
+  end
+
+  defp cleaned_ids(conn) do
+    assert conn.status == 200
+    conn.resp_body |> Jason.decode!() |> Map.fetch!("sessions") |> Enum.map(& &1["id"]) |> Enum.sort()
+  end
+
+  # ── POST /sessions ───────────────────────────────────────────────────
+
+  describe "POST /sessions" do
+    test "returns 403 when restoring a running session of another user" do
+      session_id = "sessions-api-test-#{System.unique_integer([:positive])}"
+
+      start_supervised!(
+        {Loop, [session_id: session_id, user_id: "alice", channel: :http]},
+        id: String.to_atom(session_id)
+      )
+
+      conn = json_post("/sessions", %{session_id: session_id, messages: []}, "bob")
+      assert conn.status == 403
+      assert Jason.decode!(conn.resp_body)["error"] == "forbidden"
+      assert Loop.get_owner(session_id) == "alice"
+
+      conn = json_post("/sessions", %{session_id: session_id, messages: []}, "alice")
+      assert conn.status == 201
+      assert Jason.decode!(conn.resp_body)["status"] == "exists"
+    end
+
+    test "returns 403 when restoring a persisted session of another user" do
+      session_id = persisted_session("alice")
+      messages = [%{role: "user", content: "An old question"}]
+
+      conn = json_post("/sessions", %{session_id: session_id, messages: messages}, "bob")
+      assert conn.status == 403
+      refute Session.alive?(session_id)
+
+      conn = json_post("/sessions", %{session_id: session_id, messages: messages}, "alice")
+      on_exit(fn -> Session.close(session_id) end)
+
+      assert conn.status == 201
+      assert %{"status" => "created", "restored" => 1} = Jason.decode!(conn.resp_body)
+      assert Loop.get_owner(session_id) == "alice"
+    end
+  end
+
+  # ── POST /sessions/cleanup ───────────────────────────────────────────
+
+  describe "POST /sessions/cleanup" do
+    test "a user only matches the sessions they own" do
+      mine = persisted_session("alice")
+      theirs = persisted_session("bob")
+      unowned = persisted_session(nil)
+      ids = [mine, theirs, unowned]
+
+      body = %{older_than_days: 1, ids: ids, dry_run: true}
+      assert cleaned_ids(json_post("/sessions/cleanup", body, "alice")) == [mine]
+      assert cleaned_ids(json_post("/sessions/cleanup", body, "carol")) == []
+    end
+
+    test "deleting leaves the sessions of other users alone" do
+      mine = persisted_session("alice")
+      theirs = persisted_session("bob")
+
+      body = %{action: "delete", older_than_days: 1, ids: [mine, theirs]}
+      assert cleaned_ids(json_post("/sessions/cleanup", body, "alice")) == [mine]
+
+      refute File.exists?(Path.join(sessions_dir(), "#{mine}.jsonl"))
+      assert File.exists?(Path.join(sessions_dir(), "#{theirs}.jsonl"))
+    end
+
+    test "an anonymous dev-mode request matches every session" do
+      ids = [persisted_session("alice"), persisted_session("bob"), persisted_session(nil)]
+
+      conn = json_post("/sessions/cleanup", %{older_than_days: 1, ids: ids, dry_run: true})
+      assert cleaned_ids(conn) == Enum.sort(ids)
+    end
+  end
+end
@@ -207,6 +207,99 @@ defmodule OptimalSystemAgent.Integration.MemoryTest do
     end
   end
 
+  # ---------------------------------------------------------------------------
+  # Editing entries (update_entry / delete_entry)
+  # ---------------------------------------------------------------------------
+
+  describe "update_entry and delete_entry" do
+    # Point MEMORY.md at a scratch directory so the edits round-trip through
+    # a file these tests own.
+    setup do
+      dir = Path.join(System.tmp_dir!(), "osa-memory-test-#{System.unique_integer([:positive])}")
+      File.mkdir_p!(dir)
+      previous = Application.fetch_env(:optimal_system_agent, :bootstrap_dir)
+      Application.put_env(:optimal_system_agent, :bootstrap_dir, dir)
+
+      File.write!(Path.join(dir, "MEMORY.md"), """
+      # Memory
+
+      ## [decision] 2026-02-27T10:00:00Z
+      Use PostgreSQL for production.
+
+      ## [preference] 2026-02-27T11:00:00Z
+      User prefers concise answers.
+      """)
+
+      on_exit(fn ->
+        case previous do
+          {:ok, value} -> Application.put_env(:optimal_system_agent, :bootstrap_dir, value)
+          :error -> Application.delete_env(:optimal_system_agent, :bootstrap_dir)
+        end
+
+        File.rm_rf!(dir)
+      end)
+
+      :ok
+    end
+
+    test "an updated entry reads back with the new content and its timestamp" do
+      old = entry("decision")
+
+      assert {:ok, updated} = Memory.update_entry(old.id, %{content: "Use PostgreSQL everywhere."})
+      assert updated.id != old.id
+
+      assert [decision, preference] = entries()
+      assert decision.id == updated.id
+      assert decision.content == "Use PostgreSQL everywhere."
+      assert decision.timestamp == old.timestamp
+      assert preference.content == "User prefers concise answers."
+
+      assert Memory.update_entry(old.id, %{content: "again"}) == {:error, :not_found}
+      assert Memory.recall() =~ ~r/\A# Memory\n/
+    end
+
+    test "pinning keeps the id and content and survives a re-parse" do
+      old = entry("preference")
+
+      assert {:ok, pinned} = Memory.update_entry(old.id, %{pinned: true})
+      assert pinned.id == old.id
+      assert Memory.recall() =~ "## [preference] 2026-02-27T11:00:00Z (pinned)"
+
+      assert %{pinned: true, content: "User prefers concise answers.", timestamp: "2026-02-27T11:00:00Z"} =
+               entry("preference")
+
+      assert {:ok, _} = Memory.update_entry(old.id, %{pinned: false})
+      refute entry("preference").pinned
+      refute Memory.recall() =~ "(pinned)"
+    end
+
+    test "a deleted entry is gone and the others are kept" do
+      old = entry("decision")
+
+      assert Memory.delete_entry(old.id) == :ok
+      assert [%{category: "preference", content: "User prefers concise answers."}] = entries()
+      assert Memory.recall() =~ ~r/\A# Memory\n/
+
+      assert Memory.delete_entry(old.id) == {:error, :not_found}
+    end
+
+    test "an edit that would read back as another entry is refused" do
+      old = entry("decision")
+      before = Memory.recall()
+
+      assert Memory.update_entry(old.id, %{content: "First line\n## [bug] 2026-03-01T00:00:00Z\nSmuggled"}) ==
+               {:error, :invalid_entry}
+
+      assert Memory.update_entry(old.id, %{category: "bug] 2026-03-01T00:00:00Z"}) == {:error, :invalid_entry}
+      assert Memory.update_entry(old.id, %{category: "bug\nmore"}) == {:error, :invalid_entry}
+      assert Memory.recall() == before
+
+      # "## [" inside a line is harmless.
+      assert {:ok, _} = Memory.update_entry(old.id, %{content: "See the ## [notes] section."})
+      assert length(entries()) == 2
+    end
+  end
+
   # ---------------------------------------------------------------------------
   # Keyword extraction
   # ---------------------------------------------------------------------------
@@ -283,6 +376,11 @@ defmodule OptimalSystemAgent.Integration.MemoryTest do
   # Helpers
   # ---------------------------------------------------------------------------
 
+  # The entries of MEMORY.md as they read back from disk.
+  defp entries, do: Memory.parse_memory_entries(Memory.recall()) |> Enum.map(&elem(&1, 1))
+
+  defp entry(category), do: Enum.find(entries(), &(&1.category == category))
+
   defp cleanup_session(session_id) do
     path = Path.expand("~/.osa/sessions/#{session_id}.jsonl")
     File.rm(path)
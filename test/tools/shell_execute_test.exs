@@ -9,6 +9,22 @@ defmodule OptimalSystemAgent.Tools.Builtins.ShellExecuteTest do
 
   defp exec(command), do: ShellExecute.execute(%{"command" => command})
 
+  # Runs the command in a task, sending each on_output chunk to the test as
+  # {:output, chunk, offset}.
+  defp exec_streaming(command) do
+    test_pid = self()
+    on_output = fn chunk, offset -> send(test_pid, {:output, chunk, offset}) end
+    Task.async(fn -> ShellExecute.execute(%{"command" => command}, on_output: on_output) end)
+  end
+
+  defp received_output(acc \\ []) do
+    receive do
+      {:output, chunk, offset} -> received_output([{chunk, offset} | acc])
+    after
+      0 -> Enum.reverse(acc)
+    end
+  end
+
   # ---------------------------------------------------------------------------
   # Blocked commands
   # ---------------------------------------------------------------------------
@@ -294,6 +310,35 @@ defmodule OptimalSystemAgent.Tools.Builtins.ShellExecuteTest do
     end
   end
 
+  # ---------------------------------------------------------------------------
+  # Streaming output (on_output)
+  # ---------------------------------------------------------------------------
+
+  describe "streaming output" do
+    test "chunks arrive while the command is still running" do
+      task = exec_streaming("echo first; sleep 1; echo second")
+
+      assert_receive {:output, "first\n", 0}, 2_000
+      assert Task.yield(task, 0) == nil
+
+      assert Task.await(task, 5_000) == {:ok, "first\nsecond\n"}
+      assert [{"second\n", 6}] = received_output()
+    end
+
+    test "the chunks add up to the final result" do
+      task = exec_streaming("seq 1 10000")
+      assert {:ok, output} = Task.await(task, 10_000)
+
+      chunks = received_output()
+      assert Enum.map_join(chunks, &elem(&1, 0)) == output
+
+      # Each offset is the number of bytes sent before the chunk.
+      offsets = Enum.map(chunks, &elem(&1, 1))
+      sizes = Enum.map(chunks, &byte_size(elem(&1, 0)))
+      assert offsets == Enum.scan([0 | Enum.drop(sizes, -1)], &+/2)
+    end
+  end
+
   # ---------------------------------------------------------------------------
   # Background process stripping
   # ---------------------------------------------------------------------------